@@ -58,3 +58,20 @@ func (c *Client) DeleteDeploymentTarget(
 		nil,
 	)
 }
+
+// GetDeploymentTargetPolicy retrieves the freeze windows and protection rules of a deployment target
+func (c *Client) GetDeploymentTargetPolicy(
+	ctx context.Context,
+	projectId uint,
+	deploymentTargetIdentifier string,
+) (*types.DeploymentTargetPolicy, error) {
+	resp := &types.DeploymentTargetPolicy{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/targets/%s/policy", projectId, deploymentTargetIdentifier),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
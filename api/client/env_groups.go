@@ -54,3 +54,105 @@ func (c *Client) UpdateEnvGroup(
 		nil,
 	)
 }
+
+// ListEnvGroupVersions lists all versions of an environment group, newest first
+func (c *Client) ListEnvGroupVersions(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+) (*environment_groups.ListEnvironmentGroupVersionsResponse, error) {
+	resp := &environment_groups.ListEnvironmentGroupVersionsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/versions", projID, clusterID, envGroupName),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// DiffEnvGroupVersions gets the key-level difference between two versions of an environment group
+func (c *Client) DiffEnvGroupVersions(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	fromVersion, toVersion int,
+) (*environment_groups.DiffEnvironmentGroupVersionsResponse, error) {
+	resp := &environment_groups.DiffEnvironmentGroupVersionsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/diff", projID, clusterID, envGroupName),
+		&environment_groups.DiffEnvironmentGroupVersionsRequest{
+			FromVersion: fromVersion,
+			ToVersion:   toVersion,
+		},
+		resp,
+	)
+
+	return resp, err
+}
+
+// RollbackEnvGroupInput is the input for the RollbackEnvGroup method
+type RollbackEnvGroupInput struct {
+	ProjectID     uint
+	ClusterID     uint
+	EnvGroupName  string
+	Version       int
+	SkipRedeploys bool
+}
+
+// RollbackEnvGroup creates a new version of an environment group equal to a previous version
+func (c *Client) RollbackEnvGroup(
+	ctx context.Context,
+	inp RollbackEnvGroupInput,
+) (*environment_groups.RollbackEnvironmentGroupResponse, error) {
+	resp := &environment_groups.RollbackEnvironmentGroupResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/rollback", inp.ProjectID, inp.ClusterID, inp.EnvGroupName),
+		&environment_groups.RollbackEnvironmentGroupRequest{
+			Version:           inp.Version,
+			SkipAppAutoDeploy: inp.SkipRedeploys,
+		},
+		resp,
+	)
+
+	return resp, err
+}
+
+// ImportEnvGroupInput is the input for the ImportEnvGroup method
+type ImportEnvGroupInput struct {
+	ProjectID     uint
+	ClusterID     uint
+	EnvGroupName  string
+	Variables     map[string]string
+	Secrets       map[string]string
+	Files         []environment_groups.EnvironmentGroupFile
+	Replace       bool
+	DryRun        bool
+	SkipRedeploys bool
+}
+
+// ImportEnvGroup imports variables, secrets and files into an environment group as a single new version
+func (c *Client) ImportEnvGroup(
+	ctx context.Context,
+	inp ImportEnvGroupInput,
+) (*environment_groups.ImportEnvironmentGroupResponse, error) {
+	resp := &environment_groups.ImportEnvironmentGroupResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/import", inp.ProjectID, inp.ClusterID, inp.EnvGroupName),
+		&environment_groups.ImportEnvironmentGroupRequest{
+			Variables:         inp.Variables,
+			SecretVariables:   inp.Secrets,
+			Files:             inp.Files,
+			Replace:           inp.Replace,
+			DryRun:            inp.DryRun,
+			SkipAppAutoDeploy: inp.SkipRedeploys,
+		},
+		resp,
+	)
+
+	return resp, err
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm/drift"
 
 	v1 "k8s.io/api/batch/v1"
 )
@@ -33,6 +34,26 @@ func (c *Client) GetK8sNamespaces(
 	return resp, err
 }
 
+// ListNodeArchitectures lists the CPU architectures of the nodes in a k8s cluster
+func (c *Client) ListNodeArchitectures(
+	ctx context.Context,
+	projectID uint,
+	clusterID uint,
+) (*types.ListNodeArchitecturesResponse, error) {
+	resp := &types.ListNodeArchitecturesResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/node_architectures",
+			projectID, clusterID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
 // CreateNewK8sNamespace creates a new namespace in a k8s cluster
 func (c *Client) CreateNewK8sNamespace(
 	ctx context.Context,
@@ -188,6 +209,67 @@ func (c *Client) GetRelease(
 	return resp, err
 }
 
+// GetReleaseDrift compares the latest revision of a release with the live objects in the cluster
+func (c *Client) GetReleaseDrift(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace, name string,
+) (*drift.Report, error) {
+	resp := &drift.Report{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/drift",
+			projectID, clusterID,
+			namespace, name,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListArchivedRevisions lists the revisions of a release which were pruned from the cluster and archived
+func (c *Client) ListArchivedRevisions(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace, name string,
+) (*types.ListArchivedRevisionsResponse, error) {
+	resp := &types.ListArchivedRevisionsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/archived_revisions",
+			projectID, clusterID,
+			namespace, name,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// RestoreArchivedRevision writes an archived revision of a release back to the cluster, so that
+// the release can be rolled back to it
+func (c *Client) RestoreArchivedRevision(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace, name string,
+	req *types.RestoreArchivedRevisionRequest,
+) error {
+	return c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/archived_revisions/restore",
+			projectID, clusterID,
+			namespace, name,
+		),
+		req,
+		nil,
+	)
+}
+
 func (c *Client) GetJobs(
 	ctx context.Context,
 	projectID, clusterID uint,
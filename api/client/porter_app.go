@@ -224,6 +224,7 @@ type UpdateAppInput struct {
 	Secrets              map[string]string
 	Deletions            porter_app.Deletions
 	PatchOperations      []v2.PatchOperation
+	BreakGlassReason     string
 }
 
 // UpdateApp updates a porter app
@@ -250,6 +251,7 @@ func (c *Client) UpdateApp(
 		Secrets:              inp.Secrets,
 		Deletions:            inp.Deletions,
 		PatchOperations:      inp.PatchOperations,
+		BreakGlassReason:     inp.BreakGlassReason,
 	}
 
 	err := c.postRequest(
@@ -552,6 +554,54 @@ func (c *Client) UpdateRevisionStatus(
 	return resp, err
 }
 
+// DecideRevisionApproval approves or rejects a revision which is held for approval, by setting its status to
+// APPROVED or APPROVAL_REJECTED
+func (c *Client) DecideRevisionApproval(
+	ctx context.Context,
+	projectID uint, clusterID uint,
+	appName string, appRevisionId string,
+	status models.AppRevisionStatus,
+	reason string,
+) (*porter_app.UpdateAppRevisionStatusResponse, error) {
+	resp := &porter_app.UpdateAppRevisionStatusResponse{}
+
+	req := &porter_app.UpdateAppRevisionStatusRequest{
+		Status: status,
+		Reason: reason,
+	}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/revisions/%s",
+			projectID, clusterID, appName, appRevisionId,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListAppRevisionApprovals lists the revisions of an app which were held for approval, newest first
+func (c *Client) ListAppRevisionApprovals(
+	ctx context.Context,
+	projectID uint, clusterID uint,
+	appName string,
+) (*types.ListAppRevisionApprovalsResponse, error) {
+	resp := &types.ListAppRevisionApprovalsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/approvals",
+			projectID, clusterID, appName,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
 // GetBuildEnv returns the build environment for a given app proto
 func (c *Client) GetBuildEnv(
 	ctx context.Context,
@@ -700,10 +750,12 @@ func (c *Client) UpdateImage(
 	ctx context.Context,
 	projectID, clusterID uint,
 	appName, deploymentTargetName, tag string,
+	breakGlassReason string,
 ) (*porter_app.UpdateImageResponse, error) {
 	req := &porter_app.UpdateImageRequest{
 		Tag:                  tag,
 		DeploymentTargetName: deploymentTargetName,
+		BreakGlassReason:     breakGlassReason,
 	}
 
 	resp := &porter_app.UpdateImageResponse{}
@@ -752,11 +804,13 @@ func (c *Client) RollbackRevision(
 	projectID, clusterID uint,
 	appName string,
 	deploymentTargetName string,
+	breakGlassReason string,
 ) (*porter_app.RollbackAppRevisionResponse, error) {
 	resp := &porter_app.RollbackAppRevisionResponse{}
 
 	req := &porter_app.RollbackAppRevisionRequest{
 		DeploymentTargetName: deploymentTargetName,
+		BreakGlassReason:     breakGlassReason,
 	}
 
 	err := c.postRequest(
@@ -772,6 +826,50 @@ func (c *Client) RollbackRevision(
 	return resp, err
 }
 
+// PromoteAppInput is the input struct to PromoteApp
+type PromoteAppInput struct {
+	ProjectID            uint
+	ClusterID            uint
+	AppName              string
+	FromDeploymentTarget string
+	ToDeploymentTarget   string
+	SourceAppRevisionID  string
+	Overrides            []v2.PatchOperation
+	WithPredeploy        bool
+	DryRun               bool
+	BreakGlassReason     string
+}
+
+// PromoteApp promotes the deployed revision of an app from one deployment target to another
+func (c *Client) PromoteApp(
+	ctx context.Context,
+	inp PromoteAppInput,
+) (*porter_app.PromoteAppResponse, error) {
+	resp := &porter_app.PromoteAppResponse{}
+
+	req := &porter_app.PromoteAppRequest{
+		FromDeploymentTarget: inp.FromDeploymentTarget,
+		ToDeploymentTarget:   inp.ToDeploymentTarget,
+		SourceAppRevisionID:  inp.SourceAppRevisionID,
+		Overrides:            inp.Overrides,
+		WithPredeploy:        inp.WithPredeploy,
+		DryRun:               inp.DryRun,
+		BreakGlassReason:     inp.BreakGlassReason,
+	}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/apps/%s/promote",
+			inp.ProjectID, inp.ClusterID,
+			inp.AppName,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 // RunAppJob runs a job for an app
 func (c *Client) RunAppJob(
 	ctx context.Context,
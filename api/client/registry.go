@@ -217,6 +217,26 @@ func (c *Client) GetACRAuthorizationToken(
 	return resp, err
 }
 
+// GetRegistryAuthorizationToken gets an authorization token for a Harbor, Quay or GHCR registry
+func (c *Client) GetRegistryAuthorizationToken(
+	ctx context.Context,
+	projectID uint,
+	req *types.GetRegistryTokenRequest,
+) (*types.GetRegistryTokenResponse, error) {
+	resp := &types.GetRegistryTokenResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/registries/token",
+			projectID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 // GetDockerhubAuthorizationToken gets a Docker Hub authorization token
 func (c *Client) GetDockerhubAuthorizationToken(
 	ctx context.Context,
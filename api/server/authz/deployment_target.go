@@ -64,6 +64,7 @@ func (p *DeploymentTargetScopedMiddleware) ServeHTTP(w http.ResponseWriter, r *h
 		Namespace:    deploymentTargetDB.Selector,
 		IsPreview:    deploymentTargetDB.Preview,
 		IsDefault:    deploymentTargetDB.IsDefault,
+		IsProtected:  deploymentTargetDB.Protected,
 		CreatedAtUTC: deploymentTargetDB.CreatedAt.UTC(),
 		UpdatedAtUTC: deploymentTargetDB.UpdatedAt.UTC(),
 	}
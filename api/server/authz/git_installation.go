@@ -62,7 +62,7 @@ func (p *GitInstallationScopedMiddleware) ServeHTTP(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if err := p.doesUserHaveGitInstallationAccess(ctx, user.GithubAppIntegrationID, gitInstallationID); err != nil {
+	if err := DoesUserHaveGitInstallationAccess(ctx, p.config, user.GithubAppIntegrationID, gitInstallationID); err != nil {
 		err = telemetry.Error(ctx, span, err, "user does not have access to git installation")
 		apierrors.HandleAPIError(p.config.Logger, p.config.Alerter, w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden), true)
 		return
@@ -81,26 +81,26 @@ func NewGitInstallationContext(ctx context.Context, ga *integrations.GithubAppIn
 // by ensuring the installation id exists for one org or account they have access to
 // note that this makes a github API request, but the endpoint is fast so this doesn't add
 // much overhead
-func (p *GitInstallationScopedMiddleware) doesUserHaveGitInstallationAccess(ctx context.Context, githubIntegrationID, gitInstallationID uint) error {
+func DoesUserHaveGitInstallationAccess(ctx context.Context, conf *config.Config, githubIntegrationID, gitInstallationID uint) error {
 	ctx, span := telemetry.NewSpan(ctx, "check-user-has-git-installation-access")
 	defer span.End()
 
-	oauthInt, err := p.config.Repo.GithubAppOAuthIntegration().ReadGithubAppOauthIntegration(githubIntegrationID)
+	oauthInt, err := conf.Repo.GithubAppOAuthIntegration().ReadGithubAppOauthIntegration(githubIntegrationID)
 	if err != nil {
 		return telemetry.Error(ctx, span, err, "unable to read github app oauth integration")
 	}
 
-	if p.config.GithubAppConf == nil {
+	if conf.GithubAppConf == nil {
 		return telemetry.Error(ctx, span, nil, "config has invalid GithubAppConf")
 	}
 
 	if _, _, err = oauth.GetAccessToken(oauthInt.SharedOAuthModel,
-		&p.config.GithubAppConf.Config,
-		oauth.MakeUpdateGithubAppOauthIntegrationFunction(oauthInt, p.config.Repo)); err != nil {
+		&conf.GithubAppConf.Config,
+		oauth.MakeUpdateGithubAppOauthIntegrationFunction(oauthInt, conf.Repo)); err != nil {
 		return telemetry.Error(ctx, span, err, "unable to get access token")
 	}
 
-	client := github.NewClient(p.config.GithubConf.Client(ctx, &oauth2.Token{
+	client := github.NewClient(conf.GithubConf.Client(ctx, &oauth2.Token{
 		AccessToken:  string(oauthInt.AccessToken),
 		RefreshToken: string(oauthInt.RefreshToken),
 		TokenType:    "Bearer",
@@ -137,7 +137,7 @@ func (p *GitInstallationScopedMiddleware) doesUserHaveGitInstallationAccess(ctx
 
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "account-ids", Value: fmt.Sprintf("%v", accountIDs)})
 
-	installations, err := p.config.Repo.GithubAppInstallation().ReadGithubAppInstallationByAccountIDs(accountIDs)
+	installations, err := conf.Repo.GithubAppInstallation().ReadGithubAppInstallationByAccountIDs(accountIDs)
 	if err != nil {
 		return telemetry.Error(ctx, span, err, "unable to read github app installations")
 	}
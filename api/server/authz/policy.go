@@ -80,9 +80,22 @@ func (h *PolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// validate that the policy permits the action
-	hasAccess := policy.HasScopeAccess(policyDocs, reqScopes)
+	policyScopes, err := h.policyRequestScopes(r, reqScopes)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "insufficient permissions to perform action")
+		apierrors.HandleAPIError(
+			h.config.Logger,
+			h.config.Alerter,
+			w,
+			r,
+			apierrors.NewErrPassThroughToClient(err, http.StatusForbidden),
+			true,
+		)
 
-	if !hasAccess {
+		return
+	}
+
+	if !policy.HasScopeAccess(policyDocs, policyScopes) {
 		err := telemetry.Error(ctx, span, nil, "insufficient permissions to perform action")
 		apierrors.HandleAPIError(
 			h.config.Logger,
@@ -96,6 +109,31 @@ func (h *PolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sourceScopes, err := h.sourceRequestScopes(r, reqScopes)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "unable to read source deployment target")
+		apierrors.HandleAPIError(h.config.Logger, h.config.Alerter, w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError), true)
+		return
+	}
+
+	if sourceScopes != nil && !policy.HasScopeAccess(policyDocs, sourceScopes) {
+		err := telemetry.Error(ctx, span, nil, "insufficient permissions to read source deployment target")
+		apierrors.HandleAPIError(
+			h.config.Logger,
+			h.config.Alerter,
+			w,
+			r,
+			apierrors.NewErrPassThroughToClient(err, http.StatusForbidden),
+			true,
+		)
+
+		return
+	}
+
+	if _, ok := reqScopes[types.EnvGroupScope]; ok {
+		ctx = NewEnvGroupSecretsAccessCtx(ctx, canReadEnvGroupSecrets(policyDocs, policyScopes))
+	}
+
 	// add the set of resource ids to the request context
 	ctx = NewRequestScopeCtx(ctx, reqScopes)
 	r = r.Clone(ctx)
@@ -112,6 +150,8 @@ func getRequestActionForEndpoint(
 ) (res map[types.PermissionScope]*types.RequestAction, reqErr apierrors.RequestError) {
 	res = make(map[types.PermissionScope]*types.RequestAction)
 
+	body := newRequestBodyFields(r)
+
 	// iterate through scopes, attach policies as needed
 	for _, scope := range endpointMeta.Scopes {
 		// find the resource ID and create the resource
@@ -146,6 +186,19 @@ func getRequestActionForEndpoint(
 			resource.UInt, reqErr = requestutils.GetURLParamUint(r, types.URLParamIntegrationID)
 		case types.APIContractRevisionScope:
 			resource.Name, reqErr = requestutils.GetURLParamString(r, types.URLParamAPIContractRevisionID)
+		case types.PorterAppScope:
+			// apps are named in the url, or in the body of v2 create and update requests
+			resource.Name, _ = requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+
+			if resource.Name == "" {
+				resource.Name = body.get("name")
+			}
+		case types.EnvGroupScope:
+			resource.Name, _ = requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+
+			if resource.Name == "" {
+				resource.Name = body.get("name")
+			}
 		}
 
 		if reqErr != nil {
@@ -158,5 +211,7 @@ func getRequestActionForEndpoint(
 		}
 	}
 
+	addDerivedRequestActions(r, endpointMeta, body, res)
+
 	return res, nil
 }
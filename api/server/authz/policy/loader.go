@@ -70,11 +70,22 @@ func (b *RepoPolicyDocumentLoader) LoadPolicyDocuments(
 			return types.DeveloperPolicy, nil
 		case types.RoleViewer:
 			return types.ViewerPolicy, nil
-		default:
-			return nil, apierrors.NewErrForbidden(
-				fmt.Errorf("%s role not supported for user %d, project %d", string(role.Kind), userID, projectID),
-			)
+		case types.RoleCustom:
+			if role.PolicyUID == "" {
+				break
+			}
+
+			apiPolicy, reqErr := GetAPIPolicyFromUID(b.policyRepo, projectID, role.PolicyUID)
+			if reqErr != nil {
+				return nil, reqErr
+			}
+
+			return apiPolicy.Policy, nil
 		}
+
+		return nil, apierrors.NewErrForbidden(
+			fmt.Errorf("%s role not supported for user %d, project %d", string(role.Kind), userID, projectID),
+		)
 	}
 
 	return nil, apierrors.NewErrForbidden(
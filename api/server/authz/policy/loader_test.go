@@ -16,6 +16,7 @@ import (
 type basicLoaderTest struct {
 	description      string
 	roleKind         types.RoleKind
+	policyUID        string
 	expErr           bool
 	expErrString     string
 	expErrStatusCode int
@@ -45,6 +46,12 @@ var basicLoaderTests = []basicLoaderTest{
 		expErrStatusCode: http.StatusForbidden,
 		expErrString:     "custom role not supported for user 1, project 1",
 	},
+	{
+		description: "should load policy granted to custom role",
+		roleKind:    types.RoleCustom,
+		policyUID:   "viewer",
+		expPolicy:   types.ViewerPolicy,
+	},
 }
 
 func TestBasicPolicyDocumentLoader(t *testing.T) {
@@ -72,6 +79,7 @@ func TestBasicPolicyDocumentLoader(t *testing.T) {
 				UserID:    1,
 				ProjectID: 1,
 				Kind:      basicTest.roleKind,
+				PolicyUID: basicTest.policyUID,
 			},
 		})
 
@@ -1,6 +1,8 @@
 package policy
 
 import (
+	"path"
+
 	"github.com/karagatandev/porter/api/types"
 )
 
@@ -10,8 +12,16 @@ func HasScopeAccess(
 	policy []*types.PolicyDocument,
 	reqScopes map[types.PermissionScope]*types.RequestAction,
 ) bool {
+	readAncestorScopes := withReadAncestors(reqScopes)
+
 	// iterate through policy documents until a match is found
 	for _, policyDoc := range policy {
+		reqScopes := reqScopes
+
+		if usesCustomRoleScopes(policyDoc) {
+			reqScopes = readAncestorScopes
+		}
+
 		// check that policy document is valid for current API server
 		isValid, matchDocs := populateAndVerifyPolicyDocument(
 			policyDoc,
@@ -49,6 +59,80 @@ func HasScopeAccess(
 	return false
 }
 
+// scopeParents maps each scope to the scope containing it in types.ScopeHeirarchy
+var scopeParents = parentScopes(types.ScopeHeirarchy, "", make(map[types.PermissionScope]types.PermissionScope))
+
+func parentScopes(
+	tree types.ScopeTree,
+	parent types.PermissionScope,
+	res map[types.PermissionScope]types.PermissionScope,
+) map[types.PermissionScope]types.PermissionScope {
+	for scope, subTree := range tree {
+		if parent != "" {
+			res[scope] = parent
+		}
+
+		parentScopes(subTree, scope, res)
+	}
+
+	return res
+}
+
+// customRoleScopes are the scopes which custom roles use to grant access to parts of a cluster
+var customRoleScopes = map[types.PermissionScope]bool{
+	types.DeploymentTargetScope: true,
+	types.PorterAppScope:        true,
+	types.EnvGroupScope:         true,
+	types.EnvGroupSecretsScope:  true,
+}
+
+// usesCustomRoleScopes returns true if the policy document or any of its children governs one of
+// the customRoleScopes. Only these documents are evaluated with withReadAncestors, so that
+// policies written before custom roles existed evaluate exactly as they did before.
+func usesCustomRoleScopes(policyDoc *types.PolicyDocument) bool {
+	if policyDoc == nil {
+		return false
+	}
+
+	if customRoleScopes[policyDoc.Scope] {
+		return true
+	}
+
+	for _, child := range policyDoc.Children {
+		if usesCustomRoleScopes(child) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withReadAncestors returns a copy of reqScopes in which the scopes containing another requested
+// scope only require read access. The verb of the endpoint applies to the most specific resource it
+// acts on, so that a policy can grant writes to an app without granting writes to its project.
+func withReadAncestors(
+	reqScopes map[types.PermissionScope]*types.RequestAction,
+) map[types.PermissionScope]*types.RequestAction {
+	res := make(map[types.PermissionScope]*types.RequestAction, len(reqScopes))
+
+	for scope, action := range reqScopes {
+		res[scope] = action
+	}
+
+	for scope := range reqScopes {
+		for parent, ok := scopeParents[scope]; ok; parent, ok = scopeParents[parent] {
+			if action, requested := reqScopes[parent]; requested {
+				res[parent] = &types.RequestAction{
+					Verb:     types.APIVerbGet,
+					Resource: action.Resource,
+				}
+			}
+		}
+	}
+
+	return res
+}
+
 func isResourceAllowed(
 	matchDoc *types.PolicyDocument,
 	resource types.NameOrUInt,
@@ -56,7 +140,7 @@ func isResourceAllowed(
 	valid := false
 
 	for _, allowedResource := range matchDoc.Resources {
-		if allowedResource == resource {
+		if allowedResource == resource || isResourceNameMatch(allowedResource.Name, resource.Name) {
 			valid = true
 			break
 		}
@@ -65,6 +149,17 @@ func isResourceAllowed(
 	return valid
 }
 
+// isResourceNameMatch checks a resource name against a glob pattern from a policy document
+func isResourceNameMatch(pattern, name string) bool {
+	if pattern == "" || name == "" {
+		return false
+	}
+
+	matched, err := path.Match(pattern, name)
+
+	return err == nil && matched
+}
+
 func isVerbAllowed(
 	matchDoc *types.PolicyDocument,
 	verb types.APIVerb,
@@ -138,6 +138,25 @@ var hasScopeAccessTests = []testHasScopeAccess{
 		},
 		expRes: false,
 	},
+	{
+		description: "cluster writer cannot write a cluster and its project",
+		policy:      testPolicyClusterWriter,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+		},
+		expRes: false,
+	},
 	{
 		description: "test invalid policy document",
 		policy:      testInvalidPolicyDocument,
@@ -164,6 +183,460 @@ var hasScopeAccessTests = []testHasScopeAccess{
 		},
 		expRes: false,
 	},
+	{
+		description: "staging deployer can update an app in staging",
+		policy:      testPolicyStagingDeployer,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "staging",
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "web",
+				},
+			},
+		},
+		expRes: true,
+	},
+	{
+		description: "staging deployer cannot update an app in production",
+		policy:      testPolicyStagingDeployer,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "production",
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "web",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "staging deployer can read an app in production",
+		policy:      testPolicyStagingDeployer,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					Name: "production",
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					Name: "web",
+				},
+			},
+		},
+		expRes: true,
+	},
+	{
+		description: "staging deployer cannot write env groups",
+		policy:      testPolicyStagingDeployer,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.EnvGroupScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "shared",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "app glob pattern permits writing a matching app",
+		policy:      testPolicyAppGlob,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "api-worker",
+				},
+			},
+		},
+		expRes: true,
+	},
+	{
+		description: "app glob pattern does not permit writing other apps",
+		policy:      testPolicyAppGlob,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "web",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "env group reader can read an env group",
+		policy:      testPolicyEnvGroupReader,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ClusterScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.EnvGroupScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					Name: "shared",
+				},
+			},
+		},
+		expRes: true,
+	},
+	{
+		description: "env group reader cannot read secret values",
+		policy:      testPolicyEnvGroupReader,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ClusterScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.EnvGroupScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					Name: "shared",
+				},
+			},
+			types.EnvGroupSecretsScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					Name: "shared",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "developer can read env group secret values",
+		policy:      types.DeveloperPolicy,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ClusterScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.EnvGroupScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					Name: "shared",
+				},
+			},
+			types.EnvGroupSecretsScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					Name: "shared",
+				},
+			},
+		},
+		expRes: true,
+	},
+	{
+		description: "viewer cannot write a deployment target",
+		policy:      types.ViewerPolicy,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "staging",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "oidc token can update its app",
+		policy:      types.OIDCTrustRulePolicy(1, "staging", "web"),
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "staging",
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "web",
+				},
+			},
+		},
+		expRes: true,
+	},
+	{
+		description: "oidc token cannot update another app",
+		policy:      types.OIDCTrustRulePolicy(1, "staging", "web"),
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "staging",
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "worker",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "oidc token cannot update its app in another deployment target",
+		policy:      types.OIDCTrustRulePolicy(1, "staging", "web"),
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "production",
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "web",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "oidc token cannot update its app in another cluster",
+		policy:      types.OIDCTrustRulePolicy(1, "staging", "web"),
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 2,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "staging",
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					Name: "web",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "oidc token cannot delete its app",
+		policy:      types.OIDCTrustRulePolicy(1, "staging", "web"),
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbDelete,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbDelete,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbDelete,
+				Resource: types.NameOrUInt{
+					Name: "staging",
+				},
+			},
+			types.PorterAppScope: {
+				Verb: types.APIVerbDelete,
+				Resource: types.NameOrUInt{
+					Name: "web",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "oidc token cannot change project settings",
+		policy:      types.OIDCTrustRulePolicy(1, "staging", "web"),
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.SettingsScope: {
+				Verb: types.APIVerbUpdate,
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "oidc token cannot write to its project",
+		policy:      types.OIDCTrustRulePolicy(1, "staging", "web"),
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "oidc token cannot update its cluster",
+		policy:      types.OIDCTrustRulePolicy(1, "staging", "web"),
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "oidc token can push to a registry",
+		policy:      types.OIDCTrustRulePolicy(1, "staging", "web"),
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.RegistryScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+		},
+		expRes: true,
+	},
 }
 
 func TestHasScopeAccess(t *testing.T) {
@@ -218,6 +691,21 @@ var testPolicySpecificClusters = []*types.PolicyDocument{
 	},
 }
 
+// testPolicyClusterWriter can write clusters, but can only read its project. It does not use the
+// scopes of custom roles, so writes to a cluster still require writes to the project.
+var testPolicyClusterWriter = []*types.PolicyDocument{
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadWriteVerbGroup(),
+			},
+		},
+	},
+}
+
 var testPolicyNamespaceSpecific = []*types.PolicyDocument{
 	// This document allows a user to view the namespace "abelanger" in the cluster
 	// with id 500.
@@ -277,6 +765,103 @@ var testPolicyNamespaceSpecific = []*types.PolicyDocument{
 	},
 }
 
+// testPolicyStagingDeployer can deploy to the "staging" deployment target, but can only
+// read everything else in the project, including other deployment targets
+var testPolicyStagingDeployer = []*types.PolicyDocument{
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadVerbGroup(),
+			},
+		},
+	},
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadWriteVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadWriteVerbGroup(),
+				Children: map[types.PermissionScope]*types.PolicyDocument{
+					types.DeploymentTargetScope: {
+						Scope: types.DeploymentTargetScope,
+						Verbs: types.ReadWriteVerbGroup(),
+						Resources: []types.NameOrUInt{
+							{
+								Name: "staging",
+							},
+						},
+					},
+					types.EnvGroupScope: {
+						Scope: types.EnvGroupScope,
+						Verbs: types.ReadVerbGroup(),
+					},
+				},
+			},
+		},
+	},
+}
+
+// testPolicyAppGlob can write apps whose names start with "api-"
+var testPolicyAppGlob = []*types.PolicyDocument{
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadWriteVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadWriteVerbGroup(),
+				Children: map[types.PermissionScope]*types.PolicyDocument{
+					types.DeploymentTargetScope: {
+						Scope: types.DeploymentTargetScope,
+						Verbs: types.ReadWriteVerbGroup(),
+						Children: map[types.PermissionScope]*types.PolicyDocument{
+							types.PorterAppScope: {
+								Scope: types.PorterAppScope,
+								Verbs: types.ReadWriteVerbGroup(),
+								Resources: []types.NameOrUInt{
+									{
+										Name: "api-*",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+// testPolicyEnvGroupReader can read env groups but not their secret values
+var testPolicyEnvGroupReader = []*types.PolicyDocument{
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadVerbGroup(),
+				Children: map[types.PermissionScope]*types.PolicyDocument{
+					types.EnvGroupScope: {
+						Scope: types.EnvGroupScope,
+						Verbs: types.ReadVerbGroup(),
+						Children: map[types.PermissionScope]*types.PolicyDocument{
+							types.EnvGroupSecretsScope: {
+								Scope: types.EnvGroupSecretsScope,
+								Verbs: []types.APIVerb{},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
 // NOTE: these are invalid policy documents that don't follow the accepted heirarchy
 // for scopes. Don't use this as a model for a valid doc.
 var testInvalidPolicyDocument = []*types.PolicyDocument{
@@ -1,11 +1,13 @@
 package authz_test
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/karagatandev/porter/api/server/authz"
 	"github.com/karagatandev/porter/api/server/authz/policy"
 	"github.com/karagatandev/porter/api/server/handlers/project"
@@ -14,6 +16,7 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/types"
 	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -228,6 +231,389 @@ func TestPolicyMiddlewareFailBadParam(t *testing.T) {
 	})
 }
 
+func TestPolicyMiddlewareDeniesAppInRestrictedDeploymentTarget(t *testing.T) {
+	config, handler, next := loadHandlersWithPolicy(t, types.APIRequestMetadata{
+		Verb:   types.APIVerbUpdate,
+		Method: types.HTTPVerbPost,
+		Scopes: []types.PermissionScope{
+			types.ProjectScope,
+			types.ClusterScope,
+		},
+	}, testStagingDeployerPolicy)
+
+	user := apitest.CreateTestUser(t, config, true)
+	_, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/apps/web/rollback",
+		map[string]string{
+			"deployment_target_name": "production",
+		},
+	)
+
+	req = apitest.WithURLParams(t, req, map[string]string{
+		"project_id":      "1",
+		"cluster_id":      "1",
+		"porter_app_name": "web",
+	})
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.False(t, next.WasCalled, "next handler should not have been called")
+	apitest.AssertForbiddenError(t, rr)
+}
+
+func TestPolicyMiddlewareAllowsAppInPermittedDeploymentTarget(t *testing.T) {
+	config, handler, next := loadHandlersWithPolicy(t, types.APIRequestMetadata{
+		Verb:   types.APIVerbUpdate,
+		Method: types.HTTPVerbPost,
+		Scopes: []types.PermissionScope{
+			types.ProjectScope,
+			types.ClusterScope,
+		},
+	}, testStagingDeployerPolicy)
+
+	user := apitest.CreateTestUser(t, config, true)
+	_, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/apps/web/rollback",
+		map[string]string{
+			"deployment_target_name": "staging",
+		},
+	)
+
+	req = apitest.WithURLParams(t, req, map[string]string{
+		"project_id":      "1",
+		"cluster_id":      "1",
+		"porter_app_name": "web",
+	})
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+
+	handler.ServeHTTP(rr, req)
+
+	assertNextHandlerCalled(t, next, rr, map[types.PermissionScope]*types.RequestAction{
+		types.ProjectScope: {
+			Verb: types.APIVerbUpdate,
+			Resource: types.NameOrUInt{
+				UInt: 1,
+			},
+		},
+		types.ClusterScope: {
+			Verb: types.APIVerbUpdate,
+			Resource: types.NameOrUInt{
+				UInt: 1,
+			},
+		},
+		types.DeploymentTargetScope: {
+			Verb: types.APIVerbUpdate,
+			Resource: types.NameOrUInt{
+				Name: "staging",
+			},
+		},
+		types.PorterAppScope: {
+			Verb: types.APIVerbUpdate,
+			Resource: types.NameOrUInt{
+				Name: "web",
+			},
+		},
+	})
+}
+
+func TestPolicyMiddlewareWithholdsEnvGroupSecrets(t *testing.T) {
+	config, handler, next := loadHandlersWithPolicy(t, types.APIRequestMetadata{
+		Verb:   types.APIVerbGet,
+		Method: types.HTTPVerbGet,
+		Scopes: []types.PermissionScope{
+			types.ProjectScope,
+			types.ClusterScope,
+			types.EnvGroupScope,
+		},
+	}, testEnvGroupReaderPolicy)
+
+	user := apitest.CreateTestUser(t, config, true)
+	_, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/clusters/1/environment-groups/shared/latest", nil)
+
+	req = apitest.WithURLParams(t, req, map[string]string{
+		"project_id": "1",
+		"cluster_id": "1",
+		"name":       "shared",
+	})
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.True(t, next.WasCalled, "next handler should have been called")
+	assert.False(t, next.CanReadEnvGroupSecrets, "env group secrets should be withheld")
+}
+
+func TestPolicyMiddlewareAllowsPromotionFromReadableDeploymentTarget(t *testing.T) {
+	config, handler, next := loadHandlersWithPolicy(t, promoteAppEndpointMeta, testPromoterPolicy)
+
+	req, rr := promoteAppPolicyRequest(t, config, "staging", "production")
+
+	handler.ServeHTTP(rr, req)
+
+	assertNextHandlerCalled(t, next, rr, map[types.PermissionScope]*types.RequestAction{
+		types.ProjectScope: {
+			Verb: types.APIVerbUpdate,
+			Resource: types.NameOrUInt{
+				UInt: 1,
+			},
+		},
+		types.ClusterScope: {
+			Verb: types.APIVerbUpdate,
+			Resource: types.NameOrUInt{
+				UInt: 1,
+			},
+		},
+		types.DeploymentTargetScope: {
+			Verb: types.APIVerbUpdate,
+			Resource: types.NameOrUInt{
+				Name: "production",
+			},
+		},
+		types.PorterAppScope: {
+			Verb: types.APIVerbUpdate,
+			Resource: types.NameOrUInt{
+				Name: "web",
+			},
+		},
+	})
+}
+
+func TestPolicyMiddlewareDeniesPromotionToUnwritableDeploymentTarget(t *testing.T) {
+	config, handler, next := loadHandlersWithPolicy(t, promoteAppEndpointMeta, testPromoterPolicy)
+
+	req, rr := promoteAppPolicyRequest(t, config, "production", "staging")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.False(t, next.WasCalled, "next handler should not have been called")
+	apitest.AssertForbiddenError(t, rr)
+}
+
+func TestPolicyMiddlewareDeniesPromotionFromUnreadableDeploymentTarget(t *testing.T) {
+	config, handler, next := loadHandlersWithPolicy(t, promoteAppEndpointMeta, testPromoterPolicy)
+
+	req, rr := promoteAppPolicyRequest(t, config, "qa", "production")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.False(t, next.WasCalled, "next handler should not have been called")
+	apitest.AssertForbiddenError(t, rr)
+}
+
+var promoteAppEndpointMeta = types.APIRequestMetadata{
+	Verb:   types.APIVerbUpdate,
+	Method: types.HTTPVerbPost,
+	Scopes: []types.PermissionScope{
+		types.ProjectScope,
+		types.ClusterScope,
+	},
+}
+
+// promoteAppPolicyRequest creates a project with the "staging", "qa" and "production" deployment targets in cluster 1,
+// and returns a request to promote the app "web" between two of them
+func TestPolicyMiddlewareDeniesRevisionWithUnknownDeploymentTarget(t *testing.T) {
+	config, handler, next := loadHandlersWithPolicy(t, revisionStatusEndpointMeta, testStagingDeployerPolicy)
+
+	// the test app revision repository cannot find any revision
+	req, rr := revisionStatusPolicyRequest(t, config, uuid.New().String())
+
+	handler.ServeHTTP(rr, req)
+
+	assert.False(t, next.WasCalled, "next handler should not have been called")
+	apitest.AssertForbiddenError(t, rr)
+}
+
+func TestPolicyMiddlewareDeniesRevisionInRestrictedDeploymentTarget(t *testing.T) {
+	config, handler, next := loadHandlersWithPolicy(t, revisionStatusEndpointMeta, testStagingDeployerPolicy)
+
+	req, rr := revisionStatusPolicyRequest(t, config, "production")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.False(t, next.WasCalled, "next handler should not have been called")
+	apitest.AssertForbiddenError(t, rr)
+}
+
+func TestPolicyMiddlewareAllowsRevisionInPermittedDeploymentTarget(t *testing.T) {
+	config, handler, next := loadHandlersWithPolicy(t, revisionStatusEndpointMeta, testStagingDeployerPolicy)
+
+	req, rr := revisionStatusPolicyRequest(t, config, "staging")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.True(t, next.WasCalled, "next handler should have been called")
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "status code should be ok")
+}
+
+var revisionStatusEndpointMeta = types.APIRequestMetadata{
+	Verb:   types.APIVerbUpdate,
+	Method: types.HTTPVerbPost,
+	Scopes: []types.PermissionScope{
+		types.ProjectScope,
+		types.ClusterScope,
+	},
+}
+
+// revisionStatusPolicyRequest returns a request which updates the status of a revision of the app "web". The revision is
+// deployed to the deployment target named by target, or the revision id is target if no such deployment target exists.
+func revisionStatusPolicyRequest(t *testing.T, config *config.Config, target string) (*http.Request, *httptest.ResponseRecorder) {
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revisions := &revisionRepository{
+		AppRevisionRepository: config.Repo.AppRevision(),
+		revisions:             map[string]*models.AppRevision{},
+	}
+
+	revisionID := target
+
+	for _, name := range []string{"staging", "production"} {
+		deploymentTarget, err := config.Repo.DeploymentTarget().CreateDeploymentTarget(&models.DeploymentTarget{
+			ProjectID:    int(proj.ID),
+			ClusterID:    1,
+			VanityName:   name,
+			Selector:     name,
+			SelectorType: models.DeploymentTargetSelectorType_Namespace,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if name == target {
+			revision := &models.AppRevision{
+				ID:                 uuid.New(),
+				ProjectID:          int(proj.ID),
+				DeploymentTargetID: deploymentTarget.ID,
+			}
+
+			revisions.revisions[revision.ID.String()] = revision
+			revisionID = revision.ID.String()
+		}
+	}
+
+	config.Repo = &revisionRepositoryOverride{Repository: config.Repo, revisions: revisions}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		fmt.Sprintf("/api/projects/1/clusters/1/apps/web/revisions/%s/status", revisionID),
+		nil,
+	)
+
+	req = apitest.WithURLParams(t, req, map[string]string{
+		"project_id":      "1",
+		"cluster_id":      "1",
+		"porter_app_name": "web",
+		"app_revision_id": revisionID,
+	})
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+
+	return req, rr
+}
+
+type revisionRepositoryOverride struct {
+	repository.Repository
+
+	revisions repository.AppRevisionRepository
+}
+
+func (r *revisionRepositoryOverride) AppRevision() repository.AppRevisionRepository {
+	return r.revisions
+}
+
+type revisionRepository struct {
+	repository.AppRevisionRepository
+
+	revisions map[string]*models.AppRevision
+}
+
+func (r *revisionRepository) AppRevisionById(projectID uint, id string) (*models.AppRevision, error) {
+	revision, ok := r.revisions[id]
+	if !ok || revision.ProjectID != int(projectID) {
+		return nil, errors.New("record not found")
+	}
+
+	return revision, nil
+}
+
+func promoteAppPolicyRequest(t *testing.T, config *config.Config, from, to string) (*http.Request, *httptest.ResponseRecorder) {
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"staging", "qa", "production"} {
+		_, err := config.Repo.DeploymentTarget().CreateDeploymentTarget(&models.DeploymentTarget{
+			ProjectID:    int(proj.ID),
+			ClusterID:    1,
+			VanityName:   name,
+			Selector:     name,
+			SelectorType: models.DeploymentTargetSelectorType_Namespace,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/apps/web/promote",
+		map[string]string{
+			"from_deployment_target": from,
+			"to_deployment_target":   to,
+		},
+	)
+
+	req = apitest.WithURLParams(t, req, map[string]string{
+		"project_id":      "1",
+		"cluster_id":      "1",
+		"porter_app_name": "web",
+	})
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+
+	return req, rr
+}
+
 func loadHandlers(
 	t *testing.T,
 	endpointMeta types.APIRequestMetadata,
@@ -253,6 +639,21 @@ func loadHandlers(
 	return config, handler, next
 }
 
+func loadHandlersWithPolicy(
+	t *testing.T,
+	endpointMeta types.APIRequestMetadata,
+	policyDocs []*types.PolicyDocument,
+) (*config.Config, http.Handler, *testHandler) {
+	config := apitest.LoadConfig(t)
+
+	mwFactory := authz.NewPolicyMiddleware(config, endpointMeta, &staticDocLoader{policyDocs})
+
+	next := &testHandler{}
+	handler := mwFactory.Middleware(next)
+
+	return config, handler, next
+}
+
 type failingDocLoader struct{}
 
 func (f *failingDocLoader) LoadPolicyDocuments(opts *policy.PolicyLoaderOpts) ([]*types.PolicyDocument, apierrors.RequestError) {
@@ -265,15 +666,120 @@ func (f *viewerDocLoader) LoadPolicyDocuments(opts *policy.PolicyLoaderOpts) ([]
 	return types.ViewerPolicy, nil
 }
 
+type staticDocLoader struct {
+	policyDocs []*types.PolicyDocument
+}
+
+func (s *staticDocLoader) LoadPolicyDocuments(opts *policy.PolicyLoaderOpts) ([]*types.PolicyDocument, apierrors.RequestError) {
+	return s.policyDocs, nil
+}
+
+// testStagingDeployerPolicy can deploy to the "staging" deployment target, and read everything else
+var testStagingDeployerPolicy = []*types.PolicyDocument{
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadVerbGroup(),
+			},
+		},
+	},
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadWriteVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadWriteVerbGroup(),
+				Children: map[types.PermissionScope]*types.PolicyDocument{
+					types.DeploymentTargetScope: {
+						Scope:     types.DeploymentTargetScope,
+						Verbs:     types.ReadWriteVerbGroup(),
+						Resources: []types.NameOrUInt{{Name: "staging"}},
+					},
+				},
+			},
+		},
+	},
+}
+
+// testPromoterPolicy can deploy to the "production" deployment target, and only read the "staging" deployment target
+var testPromoterPolicy = []*types.PolicyDocument{
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadVerbGroup(),
+				Children: map[types.PermissionScope]*types.PolicyDocument{
+					types.DeploymentTargetScope: {
+						Scope:     types.DeploymentTargetScope,
+						Verbs:     types.ReadWriteVerbGroup(),
+						Resources: []types.NameOrUInt{{Name: "production"}},
+					},
+				},
+			},
+		},
+	},
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadVerbGroup(),
+				Children: map[types.PermissionScope]*types.PolicyDocument{
+					types.DeploymentTargetScope: {
+						Scope:     types.DeploymentTargetScope,
+						Verbs:     types.ReadVerbGroup(),
+						Resources: []types.NameOrUInt{{Name: "staging"}},
+					},
+				},
+			},
+		},
+	},
+}
+
+// testEnvGroupReaderPolicy can read env groups but not their secret values
+var testEnvGroupReaderPolicy = []*types.PolicyDocument{
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadVerbGroup(),
+				Children: map[types.PermissionScope]*types.PolicyDocument{
+					types.EnvGroupScope: {
+						Scope: types.EnvGroupScope,
+						Verbs: types.ReadVerbGroup(),
+						Children: map[types.PermissionScope]*types.PolicyDocument{
+							types.EnvGroupSecretsScope: {
+								Scope: types.EnvGroupSecretsScope,
+								Verbs: []types.APIVerb{},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
 type testHandler struct {
-	WasCalled bool
-	ReqScopes map[types.PermissionScope]*types.RequestAction
+	WasCalled              bool
+	ReqScopes              map[types.PermissionScope]*types.RequestAction
+	CanReadEnvGroupSecrets bool
 }
 
 func (t *testHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	t.WasCalled = true
 
 	t.ReqScopes, _ = r.Context().Value(types.RequestScopeCtxKey).(map[types.PermissionScope]*types.RequestAction)
+	t.CanReadEnvGroupSecrets = authz.CanReadEnvGroupSecrets(r.Context())
 }
 
 func assertNextHandlerCalled(
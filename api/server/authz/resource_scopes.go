@@ -0,0 +1,335 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/api/server/authz/policy"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+)
+
+// maxPolicyBodySize is the largest request body which is read to find the resources a
+// request targets
+const maxPolicyBodySize = 1 << 20
+
+// requestBodyFields lazily reads the top-level string fields of a JSON request body,
+// restoring the body so that it can be decoded again by the handler
+type requestBodyFields struct {
+	r      *http.Request
+	fields map[string]string
+	read   bool
+}
+
+func newRequestBodyFields(r *http.Request) *requestBodyFields {
+	return &requestBodyFields{r: r}
+}
+
+func (b *requestBodyFields) get(key string) string {
+	if !b.read {
+		b.read = true
+		b.fields = readJSONBodyFields(b.r)
+	}
+
+	return b.fields[key]
+}
+
+func readJSONBodyFields(r *http.Request) map[string]string {
+	res := make(map[string]string)
+
+	if r.Body == nil || r.Body == http.NoBody || r.Method == http.MethodGet {
+		return res
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "json") {
+		return res
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPolicyBodySize))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+	if err != nil {
+		return res
+	}
+
+	raw := make(map[string]json.RawMessage)
+
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return res
+	}
+
+	for key, val := range raw {
+		var str string
+
+		if err := json.Unmarshal(val, &str); err == nil {
+			res[key] = str
+		}
+	}
+
+	return res
+}
+
+// addDerivedRequestActions adds the app and deployment target that a cluster-scoped request
+// targets, even when the endpoint does not declare them, so that policies restricting apps
+// and deployment targets apply to every endpoint which acts on them
+func addDerivedRequestActions(
+	r *http.Request,
+	endpointMeta types.APIRequestMetadata,
+	body *requestBodyFields,
+	reqScopes map[types.PermissionScope]*types.RequestAction,
+) {
+	if _, ok := reqScopes[types.ClusterScope]; !ok {
+		return
+	}
+
+	if _, ok := reqScopes[types.PorterAppScope]; !ok {
+		if appName, _ := requestutils.GetURLParamString(r, types.URLParamPorterAppName); appName != "" {
+			reqScopes[types.PorterAppScope] = &types.RequestAction{
+				Verb:     endpointMeta.Verb,
+				Resource: types.NameOrUInt{Name: appName},
+			}
+		}
+	}
+
+	if _, ok := reqScopes[types.DeploymentTargetScope]; !ok {
+		identifier := r.URL.Query().Get(string(types.URLParamDeploymentTargetID))
+
+		if identifier == "" {
+			identifier = body.get("deployment_target_id")
+		}
+
+		if identifier == "" {
+			identifier = body.get("deployment_target_name")
+		}
+
+		// requests which copy an app between deployment targets, such as promotions, write to the target they copy to
+		if identifier == "" {
+			identifier = body.get("to_deployment_target")
+		}
+
+		if identifier != "" {
+			reqScopes[types.DeploymentTargetScope] = &types.RequestAction{
+				Verb:     endpointMeta.Verb,
+				Resource: types.NameOrUInt{Name: identifier},
+			}
+		}
+	}
+}
+
+// errUnknownRevisionDeploymentTarget is returned by policyRequestScopes when the deployment target of the revision
+// that a request acts on cannot be determined
+var errUnknownRevisionDeploymentTarget = errors.New("unable to determine the deployment target of the app revision")
+
+// policyRequestScopes returns a copy of reqScopes in which the deployment target is named,
+// so that policies can refer to deployment targets by name. Requests which act on an app
+// revision act on the deployment target of the revision, and it is an error if that cannot
+// be determined. Other requests which act on an app without naming a deployment target act
+// on the default deployment target of the cluster. The request scopes themselves are left as
+// they are, since the deployment target middleware looks deployment targets up by the
+// identifier in the request.
+func (h *PolicyHandler) policyRequestScopes(
+	r *http.Request,
+	reqScopes map[types.PermissionScope]*types.RequestAction,
+) (map[types.PermissionScope]*types.RequestAction, error) {
+	res := make(map[types.PermissionScope]*types.RequestAction, len(reqScopes)+1)
+
+	for scope, action := range reqScopes {
+		res[scope] = action
+	}
+
+	projID := reqScopes[types.ProjectScope].Resource.UInt
+
+	if revisionID, _ := requestutils.GetURLParamString(r, types.URLParamAppRevisionID); revisionID != "" {
+		cluster, ok := reqScopes[types.ClusterScope]
+		if !ok {
+			return res, nil
+		}
+
+		name, err := h.revisionDeploymentTargetName(projID, cluster.Resource.UInt, revisionID)
+		if err != nil {
+			return nil, err
+		}
+
+		verb := cluster.Verb
+		if app, ok := reqScopes[types.PorterAppScope]; ok {
+			verb = app.Verb
+		}
+
+		res[types.DeploymentTargetScope] = &types.RequestAction{
+			Verb:     verb,
+			Resource: types.NameOrUInt{Name: name},
+		}
+
+		return res, nil
+	}
+
+	if target, ok := reqScopes[types.DeploymentTargetScope]; ok {
+		if _, err := uuid.Parse(target.Resource.Name); err != nil {
+			// already a name
+			return res, nil
+		}
+
+		deploymentTarget, err := h.config.Repo.DeploymentTarget().DeploymentTarget(projID, target.Resource.Name)
+		if err == nil && deploymentTarget.VanityName != "" {
+			res[types.DeploymentTargetScope] = &types.RequestAction{
+				Verb:     target.Verb,
+				Resource: types.NameOrUInt{Name: deploymentTarget.VanityName},
+			}
+		}
+
+		return res, nil
+	}
+
+	cluster, hasCluster := reqScopes[types.ClusterScope]
+	app, hasApp := reqScopes[types.PorterAppScope]
+
+	if !hasCluster || !hasApp {
+		return res, nil
+	}
+
+	deploymentTargets, err := h.config.Repo.DeploymentTarget().ListForCluster(projID, cluster.Resource.UInt, false)
+	if err != nil {
+		return res, nil
+	}
+
+	for _, deploymentTarget := range deploymentTargets {
+		if deploymentTarget.IsDefault && deploymentTarget.VanityName != "" {
+			res[types.DeploymentTargetScope] = &types.RequestAction{
+				Verb:     app.Verb,
+				Resource: types.NameOrUInt{Name: deploymentTarget.VanityName},
+			}
+
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// revisionDeploymentTargetName returns the name of the deployment target of an app revision, which must be in the
+// cluster of the request
+func (h *PolicyHandler) revisionDeploymentTargetName(projID, clusterID uint, revisionID string) (string, error) {
+	revision, err := h.config.Repo.AppRevision().AppRevisionById(projID, revisionID)
+	if err != nil || revision == nil || revision.ID == uuid.Nil || revision.DeploymentTargetID == uuid.Nil {
+		return "", errUnknownRevisionDeploymentTarget
+	}
+
+	deploymentTarget, err := h.config.Repo.DeploymentTarget().DeploymentTarget(projID, revision.DeploymentTargetID.String())
+	if err != nil || deploymentTarget == nil || deploymentTarget.ID == uuid.Nil || uint(deploymentTarget.ClusterID) != clusterID {
+		return "", errUnknownRevisionDeploymentTarget
+	}
+
+	if deploymentTarget.VanityName != "" {
+		return deploymentTarget.VanityName, nil
+	}
+
+	return deploymentTarget.ID.String(), nil
+}
+
+// sourceRequestScopes returns the scopes of reading the deployment target that a request copies an app from, such as the
+// source of a promotion, so that the policy must permit reading it as well as writing to the target the request deploys to.
+// The source may be in another cluster than the one in the url, so it is authorized against its own cluster. It returns
+// nil if the request does not copy from a deployment target.
+func (h *PolicyHandler) sourceRequestScopes(
+	r *http.Request,
+	reqScopes map[types.PermissionScope]*types.RequestAction,
+) (map[types.PermissionScope]*types.RequestAction, error) {
+	if _, ok := reqScopes[types.ClusterScope]; !ok {
+		return nil, nil
+	}
+
+	identifier := readJSONBodyFields(r)["from_deployment_target"]
+	if identifier == "" {
+		return nil, nil
+	}
+
+	projID := reqScopes[types.ProjectScope].Resource.UInt
+
+	deploymentTarget, err := h.config.Repo.DeploymentTarget().DeploymentTarget(projID, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// unknown deployment targets are refused by the handler
+	if deploymentTarget.ID == uuid.Nil {
+		return nil, nil
+	}
+
+	name := deploymentTarget.VanityName
+	if name == "" {
+		name = deploymentTarget.ID.String()
+	}
+
+	res := map[types.PermissionScope]*types.RequestAction{
+		types.ProjectScope: {
+			Verb:     types.APIVerbGet,
+			Resource: types.NameOrUInt{UInt: projID},
+		},
+		types.ClusterScope: {
+			Verb:     types.APIVerbGet,
+			Resource: types.NameOrUInt{UInt: uint(deploymentTarget.ClusterID)},
+		},
+		types.DeploymentTargetScope: {
+			Verb:     types.APIVerbGet,
+			Resource: types.NameOrUInt{Name: name},
+		},
+	}
+
+	if app, ok := reqScopes[types.PorterAppScope]; ok {
+		res[types.PorterAppScope] = &types.RequestAction{
+			Verb:     types.APIVerbGet,
+			Resource: app.Resource,
+		}
+	}
+
+	return res, nil
+}
+
+type envGroupSecretsAccessKey struct{}
+
+// canReadEnvGroupSecrets checks whether the policy permits reading the secret values of the
+// env group that the request targets
+func canReadEnvGroupSecrets(
+	policyDocs []*types.PolicyDocument,
+	reqScopes map[types.PermissionScope]*types.RequestAction,
+) bool {
+	envGroup, ok := reqScopes[types.EnvGroupScope]
+	if !ok {
+		return true
+	}
+
+	secretScopes := make(map[types.PermissionScope]*types.RequestAction, len(reqScopes)+1)
+
+	for scope, action := range reqScopes {
+		secretScopes[scope] = &types.RequestAction{
+			Verb:     types.APIVerbGet,
+			Resource: action.Resource,
+		}
+	}
+
+	secretScopes[types.EnvGroupSecretsScope] = &types.RequestAction{
+		Verb:     types.APIVerbGet,
+		Resource: envGroup.Resource,
+	}
+
+	return policy.HasScopeAccess(policyDocs, secretScopes)
+}
+
+// NewEnvGroupSecretsAccessCtx records whether the secret values of an env group can be read
+func NewEnvGroupSecretsAccessCtx(ctx context.Context, canRead bool) context.Context {
+	return context.WithValue(ctx, envGroupSecretsAccessKey{}, canRead)
+}
+
+// CanReadEnvGroupSecrets returns false if the policy of the request withholds the secret
+// values of the env group that it targets
+func CanReadEnvGroupSecrets(ctx context.Context) bool {
+	canRead, ok := ctx.Value(envGroupSecretsAccessKey{}).(bool)
+
+	return !ok || canRead
+}
@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// isInstanceAdmin returns true if the user is the admin of this Porter instance, as
+// configured through ADMIN_USER_ID or ADMIN_EMAIL. If neither is set, no user is an admin.
+func isInstanceAdmin(config *config.Config, user *models.User) bool {
+	if user == nil {
+		return false
+	}
+
+	if adminUserID := config.ServerConf.AdminUserId; adminUserID != "" {
+		return adminUserID == strconv.FormatUint(uint64(user.ID), 10)
+	}
+
+	if adminEmail := config.ServerConf.AdminEmail; adminEmail != "" {
+		return adminEmail == user.Email
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/keyrotate"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// CreateKeyRotationHandler starts a rotation from OLD_ENCRYPTION_KEY to ENCRYPTION_KEY in the
+// background, or resumes it if it was interrupted
+type CreateKeyRotationHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewCreateKeyRotationHandler returns a new CreateKeyRotationHandler
+func NewCreateKeyRotationHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *CreateKeyRotationHandler {
+	return &CreateKeyRotationHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (h *CreateKeyRotationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-key-rotation")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	if !isInstanceAdmin(h.Config(), user) {
+		err := telemetry.Error(ctx, span, nil, "key rotations can only be started by the instance admin")
+		h.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	if h.Config().KeyRotator == nil {
+		err := telemetry.Error(ctx, span, nil, "OLD_ENCRYPTION_KEY must be set to start a key rotation, and envelope encryption must not be enabled")
+		h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	rotation, err := h.Config().KeyRotator.Start()
+	if err != nil {
+		if errors.Is(err, keyrotate.ErrRotationInProgress) {
+			err := telemetry.Error(ctx, span, err, "key rotation is already in progress")
+			h.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+
+		err := telemetry.Error(ctx, span, err, "error starting key rotation")
+		h.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "key-rotation-id", Value: rotation.ID})
+
+	w.WriteHeader(http.StatusAccepted)
+	h.WriteResult(w, r, rotation.ToKeyRotationType())
+}
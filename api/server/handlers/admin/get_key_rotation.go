@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetKeyRotationHandler gets the progress of a single encryption key rotation
+type GetKeyRotationHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetKeyRotationHandler returns a new GetKeyRotationHandler
+func NewGetKeyRotationHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetKeyRotationHandler {
+	return &GetKeyRotationHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (h *GetKeyRotationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-key-rotation")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	if !isInstanceAdmin(h.Config(), user) {
+		err := telemetry.Error(ctx, span, nil, "key rotations can only be viewed by the instance admin")
+		h.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	rotationID, reqErr := requestutils.GetURLParamUint(r, types.URLParamKeyRotationID)
+	if reqErr != nil {
+		h.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "key-rotation-id", Value: rotationID})
+
+	rotation, err := h.Repo().KeyRotation().ReadKeyRotation(rotationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.HandleAPIError(w, r, apierrors.NewErrNotFound(fmt.Errorf("key rotation %d not found", rotationID)))
+			return
+		}
+
+		err := telemetry.Error(ctx, span, err, "error reading key rotation")
+		h.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	h.WriteResult(w, r, rotation.ToKeyRotationType())
+}
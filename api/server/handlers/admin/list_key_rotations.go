@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ListKeyRotationsHandler lists encryption key rotations and their progress
+type ListKeyRotationsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListKeyRotationsHandler returns a new ListKeyRotationsHandler
+func NewListKeyRotationsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListKeyRotationsHandler {
+	return &ListKeyRotationsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (h *ListKeyRotationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-key-rotations")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	if !isInstanceAdmin(h.Config(), user) {
+		err := telemetry.Error(ctx, span, nil, "key rotations can only be viewed by the instance admin")
+		h.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	rotations, err := h.Repo().KeyRotation().ListKeyRotations()
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error listing key rotations")
+		h.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make([]*types.KeyRotation, 0, len(rotations))
+
+	for _, rotation := range rotations {
+		res = append(res, rotation.ToKeyRotationType())
+	}
+
+	h.WriteResult(w, r, res)
+}
@@ -0,0 +1,157 @@
+package audit_log
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+var auditLogCSVHeader = []string{
+	"id",
+	"created_at",
+	"project_id",
+	"user_id",
+	"api_token_id",
+	"verb",
+	"method",
+	"path",
+	"resources",
+	"request_summary",
+	"status_code",
+	"outcome",
+}
+
+type AuditLogExportHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewAuditLogExportHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AuditLogExportHandler {
+	return &AuditLogExportHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *AuditLogExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-export-audit-logs")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.ExportAuditLogsRequest{}
+
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.Format == "" {
+		request.Format = types.AuditLogExportFormatJSON
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "format", Value: string(request.Format)})
+
+	if request.Format != types.AuditLogExportFormatJSON && request.Format != types.AuditLogExportFormatCSV {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("unsupported export format %s", request.Format))
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	auditLogs, err := p.Repo().AuditLog().ListAllAuditLogs(ctx, proj.ID, request.AuditLogFilter)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing audit logs")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make([]*types.AuditLog, 0, len(auditLogs))
+
+	for _, auditLog := range auditLogs {
+		res = append(res, auditLog.ToAuditLogType())
+	}
+
+	filename := fmt.Sprintf("audit-logs-%d-%s.%s", proj.ID, time.Now().UTC().Format("20060102T150405Z"), request.Format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if request.Format == types.AuditLogExportFormatJSON {
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			telemetry.Error(ctx, span, err, "error writing audit log export")
+		}
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+
+	if err := writeAuditLogCSV(w, res); err != nil {
+		telemetry.Error(ctx, span, err, "error writing audit log export")
+	}
+}
+
+func writeAuditLogCSV(w http.ResponseWriter, auditLogs []*types.AuditLog) error {
+	csvWriter := csv.NewWriter(w)
+
+	if err := csvWriter.Write(auditLogCSVHeader); err != nil {
+		return err
+	}
+
+	for _, auditLog := range auditLogs {
+		var userID string
+
+		if auditLog.UserID != 0 {
+			userID = strconv.FormatUint(uint64(auditLog.UserID), 10)
+		}
+
+		err := csvWriter.Write([]string{
+			strconv.FormatUint(uint64(auditLog.ID), 10),
+			auditLog.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatUint(uint64(auditLog.ProjectID), 10),
+			userID,
+			auditLog.APITokenID,
+			string(auditLog.Verb),
+			string(auditLog.Method),
+			auditLog.Path,
+			formatAuditLogResources(auditLog.Resources),
+			auditLog.RequestSummary,
+			strconv.Itoa(auditLog.StatusCode),
+			string(auditLog.Outcome),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// formatAuditLogResources formats resources as scope=resource pairs in a stable order
+func formatAuditLogResources(resources map[types.PermissionScope]string) string {
+	pairs := make([]string, 0, len(resources))
+
+	for scope, resource := range resources {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", scope, resource))
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ";")
+}
@@ -0,0 +1,49 @@
+package audit_log
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// AuditLogSettingsGetHandler returns the audit log settings of a project
+type AuditLogSettingsGetHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewAuditLogSettingsGetHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *AuditLogSettingsGetHandler {
+	return &AuditLogSettingsGetHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (p *AuditLogSettingsGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-audit-log-settings")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	settings, err := p.Repo().AuditLog().ReadAuditLogSettings(ctx, proj.ID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "error reading audit log settings")
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		settings = &models.AuditLogSettings{ProjectID: proj.ID}
+	}
+
+	p.WriteResult(w, r, settings.ToAuditLogSettingsType(p.Config().ServerConf.AuditLogRetention))
+}
@@ -0,0 +1,68 @@
+package audit_log
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository/gorm/helpers"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+type AuditLogListHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewAuditLogListHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AuditLogListHandler {
+	return &AuditLogListHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *AuditLogListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-audit-logs")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.ListAuditLogsRequest{}
+
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	auditLogs, paginatedResult, err := p.Repo().AuditLog().ListAuditLogs(
+		ctx,
+		proj.ID,
+		request.AuditLogFilter,
+		helpers.WithPage(int(request.Page)),
+	)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing audit logs")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.ListAuditLogsResponse{
+		AuditLogs: make([]*types.AuditLog, 0, len(auditLogs)),
+		Pagination: &types.PaginationResponse{
+			NumPages:    paginatedResult.NumPages,
+			CurrentPage: paginatedResult.CurrentPage,
+			NextPage:    paginatedResult.NextPage,
+		},
+	}
+
+	for _, auditLog := range auditLogs {
+		res.AuditLogs = append(res.AuditLogs, auditLog.ToAuditLogType())
+	}
+
+	p.WriteResult(w, r, res)
+}
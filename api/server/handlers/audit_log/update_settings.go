@@ -0,0 +1,55 @@
+package audit_log
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// AuditLogSettingsUpdateHandler sets how long the audit logs of a project are kept
+type AuditLogSettingsUpdateHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewAuditLogSettingsUpdateHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AuditLogSettingsUpdateHandler {
+	return &AuditLogSettingsUpdateHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *AuditLogSettingsUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-audit-log-settings")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateAuditLogSettingsRequest{}
+
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "retention-days", Value: request.RetentionDays})
+
+	settings, err := p.Repo().AuditLog().UpdateAuditLogSettings(ctx, &models.AuditLogSettings{
+		ProjectID:     proj.ID,
+		RetentionDays: request.RetentionDays,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating audit log settings")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, settings.ToAuditLogSettingsType(p.Config().ServerConf.AuditLogRetention))
+}
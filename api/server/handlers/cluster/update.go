@@ -68,6 +68,14 @@ func (c *ClusterUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		cluster.PreviewEnvsEnabled = *request.PreviewEnvsEnabled
 	}
 
+	if request.MonitorHelmReleases != nil {
+		cluster.MonitorHelmReleases = *request.MonitorHelmReleases
+	}
+
+	if request.HelmRevisionsToKeep != nil {
+		cluster.HelmRevisionsToKeep = *request.HelmRevisionsToKeep
+	}
+
 	if request.Name != "" && cluster.Name != request.Name {
 		cluster.Name = request.Name
 	}
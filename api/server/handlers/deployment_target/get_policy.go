@@ -0,0 +1,84 @@
+package deployment_target
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetDeploymentTargetPolicyHandler is the handler for GET /api/projects/{project_id}/targets/{deployment_target_identifier}/policy
+type GetDeploymentTargetPolicyHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetDeploymentTargetPolicyHandler creates a new GetDeploymentTargetPolicyHandler
+func NewGetDeploymentTargetPolicyHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetDeploymentTargetPolicyHandler {
+	return &GetDeploymentTargetPolicyHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP returns the freeze windows and protection rules of a deployment target, and the freeze window in effect if any
+func (c *GetDeploymentTargetPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-deployment-target-policy")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	deploymentTarget, _ := ctx.Value(types.DeploymentTargetScope).(types.DeploymentTarget)
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID.String()})
+
+	policyModel, err := c.Repo().DeployPolicy().ReadDeploymentTargetPolicy(ctx, project.ID, deploymentTarget.ID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "error reading deployment target policy")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		policyModel = &models.DeploymentTargetPolicy{DeploymentTargetID: deploymentTarget.ID}
+	}
+
+	res, err := policyModel.ToDeploymentTargetPolicyType()
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error decoding deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	policy, err := policyModel.DeployPolicy()
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error decoding deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	window, until, err := policy.ActiveFreeze(time.Now())
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error evaluating freeze windows")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if window != nil {
+		res.ActiveFreeze = &types.ActiveFreeze{
+			Name:   window.Name,
+			Reason: window.Reason,
+			Until:  until,
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
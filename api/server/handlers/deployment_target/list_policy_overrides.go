@@ -0,0 +1,56 @@
+package deployment_target
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ListDeployPolicyOverridesHandler is the handler for GET /api/projects/{project_id}/targets/{deployment_target_identifier}/policy/overrides
+type ListDeployPolicyOverridesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListDeployPolicyOverridesHandler creates a new ListDeployPolicyOverridesHandler
+func NewListDeployPolicyOverridesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListDeployPolicyOverridesHandler {
+	return &ListDeployPolicyOverridesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP lists the deploys which broke the glass to override the policy of a deployment target
+func (c *ListDeployPolicyOverridesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-deploy-policy-overrides")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	deploymentTarget, _ := ctx.Value(types.DeploymentTargetScope).(types.DeploymentTarget)
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID.String()})
+
+	overrides, err := c.Repo().DeployPolicy().ListDeployPolicyOverrides(ctx, project.ID, deploymentTarget.ID)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error listing deploy policy overrides")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := &types.ListDeployPolicyOverridesResponse{
+		Overrides: make([]*types.DeployPolicyOverride, 0, len(overrides)),
+	}
+
+	for _, override := range overrides {
+		res.Overrides = append(res.Overrides, override.ToDeployPolicyOverrideType())
+	}
+
+	c.WriteResult(w, r, res)
+}
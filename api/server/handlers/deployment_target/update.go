@@ -0,0 +1,72 @@
+package deployment_target
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// UpdateDeploymentTargetHandler is the handler for PATCH /api/projects/{project_id}/targets/{deployment_target_identifier}
+type UpdateDeploymentTargetHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateDeploymentTargetHandler creates a new UpdateDeploymentTargetHandler
+func NewUpdateDeploymentTargetHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateDeploymentTargetHandler {
+	return &UpdateDeploymentTargetHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP updates the settings of a deployment target which are managed by Porter rather than the cluster control plane
+func (c *UpdateDeploymentTargetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-deployment-target")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	deploymentTarget, _ := ctx.Value(types.DeploymentTargetScope).(types.DeploymentTarget)
+
+	request := &types.UpdateDeploymentTargetRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	deploymentTargetDB, err := c.Repo().DeploymentTarget().DeploymentTarget(project.ID, deploymentTarget.ID.String())
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error reading deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if request.Protected != nil {
+		deploymentTargetDB.Protected = *request.Protected
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTargetDB.ID.String()},
+		telemetry.AttributeKV{Key: "protected", Value: deploymentTargetDB.Protected},
+	)
+
+	deploymentTargetDB, err = c.Repo().DeploymentTarget().UpdateDeploymentTarget(deploymentTargetDB)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error updating deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, &types.UpdateDeploymentTargetResponse{
+		DeploymentTarget: *deploymentTargetDB.ToDeploymentTargetType(),
+	})
+}
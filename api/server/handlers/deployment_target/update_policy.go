@@ -0,0 +1,120 @@
+package deployment_target
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/deploypolicy"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateDeploymentTargetPolicyHandler is the handler for PUT /api/projects/{project_id}/targets/{deployment_target_identifier}/policy
+type UpdateDeploymentTargetPolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateDeploymentTargetPolicyHandler creates a new UpdateDeploymentTargetPolicyHandler
+func NewUpdateDeploymentTargetPolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateDeploymentTargetPolicyHandler {
+	return &UpdateDeploymentTargetPolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP replaces the freeze windows and protection rules of a deployment target
+func (c *UpdateDeploymentTargetPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-deployment-target-policy")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	deploymentTarget, _ := ctx.Value(types.DeploymentTargetScope).(types.DeploymentTarget)
+
+	request := &types.UpdateDeploymentTargetPolicyRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: deploymentTarget.ID.String()},
+		telemetry.AttributeKV{Key: "freeze-windows", Value: len(request.FreezeWindows)},
+		telemetry.AttributeKV{Key: "approver-role", Value: request.ApproverRole},
+		telemetry.AttributeKV{Key: "allowed-branches", Value: len(request.AllowedBranches)},
+		telemetry.AttributeKV{Key: "allowed-sources", Value: len(request.AllowedSources)},
+		telemetry.AttributeKV{Key: "requires-approval", Value: request.RequiresApproval},
+		telemetry.AttributeKV{Key: "approval-timeout", Value: request.ApprovalTimeout},
+	)
+
+	policy := deploypolicy.Policy{
+		ApproverRole:     request.ApproverRole,
+		AllowedBranches:  request.AllowedBranches,
+		AllowedSources:   request.AllowedSources,
+		RequiresApproval: request.RequiresApproval,
+		ApprovalTimeout:  request.ApprovalTimeout,
+	}
+
+	for _, window := range request.FreezeWindows {
+		policy.FreezeWindows = append(policy.FreezeWindows, deploypolicy.FreezeWindow{
+			Name:     window.Name,
+			Schedule: window.Schedule,
+			Duration: window.Duration,
+			Timezone: window.Timezone,
+			Reason:   window.Reason,
+		})
+	}
+
+	if err := policy.Validate(); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	switch policy.ApproverRole {
+	case "", string(types.RoleAdmin), string(types.RoleDeveloper), string(types.RoleViewer):
+	default:
+		if _, err := c.Repo().Policy().ReadPolicy(project.ID, policy.ApproverRole); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err := telemetry.Error(ctx, span, nil, "approver role not found")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), policy.ApproverRole), http.StatusBadRequest))
+				return
+			}
+
+			err = telemetry.Error(ctx, span, err, "error reading approver role")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	policyModel, err := models.NewDeploymentTargetPolicy(project.ID, deploymentTarget.ID, policy)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error encoding deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	policyModel, err = c.Repo().DeployPolicy().UpdateDeploymentTargetPolicy(ctx, policyModel)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res, err := policyModel.ToDeploymentTargetPolicyType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -1,6 +1,7 @@
 package environment_groups
 
 import (
+	"errors"
 	"net/http"
 
 	"connectrpc.com/connect"
@@ -15,6 +16,7 @@ import (
 	"github.com/karagatandev/porter/api/types"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
 )
 
 // DeleteEnvironmentGroupHandler is the handler for the DELETE /environment-group endpoint
@@ -81,6 +83,36 @@ func (c *DeleteEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
+	case string(EnvironmentGroupType_Vault), string(EnvironmentGroupType_AWSSecretsManager):
+		agent, err := c.GetAgent(r, cluster, "")
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "unable to connect to kubernetes cluster")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		// the provider is deleted first, so that the environment group is not synced again while it is being deleted
+		provider, err := c.Repo().EnvGroupProvider().ReadEnvGroupProvider(ctx, cluster.ProjectID, cluster.ID, request.Name)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "unable to read env group provider")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		if provider != nil {
+			if err := c.Repo().EnvGroupProvider().DeleteEnvGroupProvider(ctx, provider); err != nil {
+				err := telemetry.Error(ctx, span, err, "unable to delete env group provider")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+
+		err = environment_groups.DeleteEnvironmentGroup(ctx, agent, request.Name)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "unable to delete environment group")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
 	default:
 		agent, err := c.GetAgent(r, cluster, "")
 		if err != nil {
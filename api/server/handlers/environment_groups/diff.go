@@ -0,0 +1,97 @@
+package environment_groups
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/authz"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// DiffEnvironmentGroupVersionsHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/diff endpoint
+type DiffEnvironmentGroupVersionsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewDiffEnvironmentGroupVersionsHandler handles GET requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/diff
+func NewDiffEnvironmentGroupVersionsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *DiffEnvironmentGroupVersionsHandler {
+	return &DiffEnvironmentGroupVersionsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// DiffEnvironmentGroupVersionsRequest is the request object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/diff endpoint
+type DiffEnvironmentGroupVersionsRequest struct {
+	// FromVersion is the older version to compare
+	FromVersion int `schema:"from" form:"required"`
+	// ToVersion is the newer version to compare
+	ToVersion int `schema:"to" form:"required"`
+}
+
+// DiffEnvironmentGroupVersionsResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/diff endpoint.
+// Secret variables and files only show whether they changed, never their values.
+type DiffEnvironmentGroupVersionsResponse environmentgroups.EnvironmentGroupDiff
+
+// ServeHTTP returns the key-level difference between two versions of an environment group
+func (c *DiffEnvironmentGroupVersionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-diff-env-group-versions")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &DiffEnvironmentGroupVersionsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "from-version", Value: request.FromVersion},
+		telemetry.AttributeKV{Key: "to-version", Value: request.ToVersion},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	diff, err := environmentgroups.DiffBaseEnvironmentGroupVersions(ctx, agent, envGroupName, request.FromVersion, request.ToVersion)
+	if err != nil {
+		var notFound environmentgroups.ErrEnvironmentGroupVersionNotFound
+		if errors.As(err, &notFound) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "unable to diff env group versions")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := DiffEnvironmentGroupVersionsResponse(diff)
+
+	c.WriteResult(w, r, &res)
+}
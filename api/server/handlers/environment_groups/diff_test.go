@@ -0,0 +1,58 @@
+package environment_groups_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/karagatandev/porter/api/server/handlers/environment_groups"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apitest"
+	"github.com/karagatandev/porter/api/types"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
+)
+
+func TestDiffEnvironmentGroupVersions(t *testing.T) {
+	config, agent := loadEnvGroupHistory(t)
+	req, rr := envGroupRequest(t, config, http.MethodGet, "/api/projects/1/clusters/1/environment-groups/shared/diff?from=1&to=2", "shared", nil)
+
+	handler := environment_groups.NewDiffEnvironmentGroupVersionsHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+	handler.KubernetesAgentGetter = fakeAgentGetter{agent: agent}
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseExpected(t, rr, &environment_groups.DiffEnvironmentGroupVersionsResponse{
+		Name:        "shared",
+		FromVersion: 1,
+		ToVersion:   2,
+		Variables: []environmentgroups.VariableDiff{
+			{Key: "PORT", Change: environmentgroups.VariableChange_Changed, OldValue: "80", NewValue: "8080"},
+			{Key: "REGION", Change: environmentgroups.VariableChange_Unchanged, OldValue: "us-east-1", NewValue: "us-east-1"},
+		},
+		SecretVariables: []environmentgroups.VariableDiff{
+			{Key: "DB_PASSWORD", Change: environmentgroups.VariableChange_Changed},
+		},
+		Files: []environmentgroups.VariableDiff{},
+	}, &environment_groups.DiffEnvironmentGroupVersionsResponse{})
+}
+
+func TestDiffEnvironmentGroupVersionsNotFound(t *testing.T) {
+	config, agent := loadEnvGroupHistory(t)
+	req, rr := envGroupRequest(t, config, http.MethodGet, "/api/projects/1/clusters/1/environment-groups/shared/diff?from=1&to=3", "shared", nil)
+
+	handler := environment_groups.NewDiffEnvironmentGroupVersionsHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+	handler.KubernetesAgentGetter = fakeAgentGetter{agent: agent}
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusNotFound, &types.ExternalError{
+		Error: "version 3 of environment group shared does not exist",
+	})
+}
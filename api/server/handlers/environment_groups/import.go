@@ -0,0 +1,155 @@
+package environment_groups
+
+import (
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+
+	"github.com/karagatandev/porter/api/server/authz"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ImportEnvironmentGroupHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/import endpoint
+type ImportEnvironmentGroupHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewImportEnvironmentGroupHandler handles POST requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/import
+func NewImportEnvironmentGroupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ImportEnvironmentGroupHandler {
+	return &ImportEnvironmentGroupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ImportEnvironmentGroupRequest is the request object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/import endpoint
+type ImportEnvironmentGroupRequest struct {
+	// Variables are values which are not sensitive
+	Variables map[string]string `json:"variables"`
+
+	// SecretVariables are sensitive values
+	SecretVariables map[string]string `json:"secret_variables"`
+
+	// Files is a list of files associated with the env group
+	Files []EnvironmentGroupFile `json:"files"`
+
+	// Replace removes all variables, secrets and files which are not imported, instead of merging the import into the latest version
+	Replace bool `json:"replace"`
+
+	// DryRun returns the changes the import would make without making them
+	DryRun bool `json:"dry_run"`
+
+	// SkipAppAutoDeploy is a flag to determine if linked apps should be redeployed with the new version
+	SkipAppAutoDeploy bool `json:"skip_app_auto_deploy"`
+}
+
+// ImportEnvironmentGroupResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/import endpoint
+type ImportEnvironmentGroupResponse struct {
+	Name   string `json:"name"`
+	DryRun bool   `json:"dry_run"`
+	// Version is the new version, which is 0 for dry runs
+	Version int `json:"version"`
+	// Diff is the difference between the latest version before the import and the imported version. Secret variables and files only show
+	// whether they changed.
+	Diff environmentgroups.EnvironmentGroupDiff `json:"diff"`
+}
+
+// ServeHTTP imports variables, secrets and files into an environment group as a single new version
+func (c *ImportEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-import-env-group")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &ImportEnvironmentGroupRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "replace", Value: request.Replace},
+		telemetry.AttributeKV{Key: "dry-run", Value: request.DryRun},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	files := make([]environmentgroups.EnvGroupFile, 0, len(request.Files))
+	for _, file := range request.Files {
+		files = append(files, environmentgroups.EnvGroupFile{
+			Name:     file.Name,
+			Contents: file.Contents,
+		})
+	}
+
+	output, err := environmentgroups.ImportBaseEnvironmentGroup(ctx, agent, environmentgroups.ImportBaseEnvironmentGroupInput{
+		Name:            envGroupName,
+		Variables:       request.Variables,
+		SecretVariables: request.SecretVariables,
+		Files:           files,
+		Replace:         request.Replace,
+		DryRun:          request.DryRun,
+		Author:          user.Email,
+	})
+	if err != nil {
+		if errors.Is(err, environmentgroups.ErrEnvironmentGroupNotImportable) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "unable to import env group")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if !request.DryRun && !request.SkipAppAutoDeploy {
+		_, err = c.Config().ClusterControlPlaneClient.UpdateAppsLinkedToEnvGroup(ctx, connect.NewRequest(&porterv1.UpdateAppsLinkedToEnvGroupRequest{
+			ProjectId:    int64(project.ID),
+			ClusterId:    int64(cluster.ID),
+			EnvGroupName: envGroupName,
+		}))
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error calling ccp update apps linked to env group")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	res := &ImportEnvironmentGroupResponse{
+		Name:    envGroupName,
+		DryRun:  request.DryRun,
+		Version: output.EnvironmentGroup.Version,
+		Diff:    output.Diff,
+	}
+
+	c.WriteResult(w, r, res)
+}
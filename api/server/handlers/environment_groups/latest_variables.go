@@ -1,15 +1,18 @@
 package environment_groups
 
 import (
+	"encoding/base64"
 	"net/http"
 
 	"connectrpc.com/connect"
+	"github.com/karagatandev/porter/api/server/authz"
 	"github.com/karagatandev/porter/api/server/handlers"
 	"github.com/karagatandev/porter/api/server/shared"
 	"github.com/karagatandev/porter/api/server/shared/apierrors"
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/server/shared/requestutils"
 	"github.com/karagatandev/porter/api/types"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/telemetry"
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
@@ -36,8 +39,9 @@ type LatestEnvGroupVariablesRequest struct{}
 
 // LatestEnvGroupVariablesResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/latest endpoint
 type LatestEnvGroupVariablesResponse struct {
-	Variables map[string]string `json:"variables"`
-	Secrets   map[string]string `json:"secrets"`
+	Variables map[string]string      `json:"variables"`
+	Secrets   map[string]string      `json:"secrets"`
+	Files     []EnvironmentGroupFile `json:"files,omitempty"`
 }
 
 // ServeHTTP retrieves the latest env group variables from CCP and writes them to the response
@@ -84,5 +88,34 @@ func (c *LatestEnvGroupVariablesHandler) ServeHTTP(w http.ResponseWriter, r *htt
 		Secrets:   ccpResp.Msg.EnvGroupVariables.Secret,
 	}
 
+	for _, file := range ccpResp.Msg.EnvGroupVariables.Files {
+		decoded, err := base64.StdEncoding.DecodeString(file.B64Contents)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "unable to decode base64 contents")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		res.Files = append(res.Files, EnvironmentGroupFile{
+			Name:     file.Name,
+			Contents: string(decoded),
+		})
+	}
+
+	// the policy of the request may grant access to the env group but not its secret values or file contents
+	if !authz.CanReadEnvGroupSecrets(ctx) {
+		secrets := make(map[string]string, len(res.Secrets))
+
+		for key := range res.Secrets {
+			secrets[key] = environmentgroups.EnvGroupSecretDummyValue
+		}
+
+		res.Secrets = secrets
+
+		for i := range res.Files {
+			res.Files[i].Contents = environmentgroups.EnvGroupSecretDummyValue
+		}
+	}
+
 	c.WriteResult(w, r, res)
 }
@@ -0,0 +1,251 @@
+package environment_groups
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/envgroupprovider"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// newEnvGroupProvider validates the Vault or AWS Secrets Manager configuration of an environment group and returns the provider to store.
+// All returned errors are caused by the request.
+func newEnvGroupProvider(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	envGroupName string,
+	envGroupType EnvironmentGroupType,
+	vaultEnv types.VaultEnvGroupProviderConfig,
+	awsSecretsManagerEnv types.AWSSecretsManagerEnvGroupProviderConfig,
+	keyMappings map[string]string,
+	refreshIntervalSeconds int,
+) (*models.EnvGroupProvider, error) {
+	if envGroupName == "" {
+		return nil, errors.New("env group name is required")
+	}
+
+	var conf interface{}
+
+	switch envGroupType {
+	case EnvironmentGroupType_Vault:
+		if vaultEnv.Address == "" {
+			return nil, errors.New("vault env address is required")
+		}
+		if err := envgroupprovider.ValidateVaultAddress(vaultEnv.Address); err != nil {
+			return nil, err
+		}
+		if vaultEnv.MountPath == "" {
+			return nil, errors.New("vault env mount path is required")
+		}
+		if vaultEnv.Path == "" {
+			return nil, errors.New("vault env path is required")
+		}
+		if vaultEnv.AuthMethod != types.VaultAuthMethodToken && vaultEnv.AuthMethod != types.VaultAuthMethodAppRole {
+			return nil, fmt.Errorf("vault env auth method must be one of %s or %s", types.VaultAuthMethodToken, types.VaultAuthMethodAppRole)
+		}
+		if vaultEnv.BasicIntegrationID == 0 {
+			return nil, errors.New("vault env basic integration id is required")
+		}
+		if _, err := repo.BasicIntegration().ReadBasicIntegration(cluster.ProjectID, vaultEnv.BasicIntegrationID); err != nil {
+			return nil, fmt.Errorf("vault env basic integration %d does not exist", vaultEnv.BasicIntegrationID)
+		}
+
+		conf = vaultEnv
+	case EnvironmentGroupType_AWSSecretsManager:
+		if awsSecretsManagerEnv.Region == "" {
+			return nil, errors.New("aws secrets manager env region is required")
+		}
+		if awsSecretsManagerEnv.SecretID == "" {
+			return nil, errors.New("aws secrets manager env secret id is required")
+		}
+		switch awsSecretsManagerEnv.AuthMethod {
+		case types.AWSSecretsManagerAuthMethodAccessKey:
+		case types.AWSSecretsManagerAuthMethodAssumeRole:
+			if awsSecretsManagerEnv.RoleARN == "" {
+				return nil, errors.New("aws secrets manager env role arn is required")
+			}
+		default:
+			return nil, fmt.Errorf("aws secrets manager env auth method must be one of %s or %s", types.AWSSecretsManagerAuthMethodAccessKey, types.AWSSecretsManagerAuthMethodAssumeRole)
+		}
+		if awsSecretsManagerEnv.AWSIntegrationID == 0 {
+			return nil, errors.New("aws secrets manager env aws integration id is required")
+		}
+		if _, err := repo.AWSIntegration().ReadAWSIntegration(cluster.ProjectID, awsSecretsManagerEnv.AWSIntegrationID); err != nil {
+			return nil, fmt.Errorf("aws secrets manager env aws integration %d does not exist", awsSecretsManagerEnv.AWSIntegrationID)
+		}
+
+		conf = awsSecretsManagerEnv
+	default:
+		return nil, fmt.Errorf("env group type %s is not synced by porter", envGroupType)
+	}
+
+	if err := envgroupprovider.ValidateKeyMappings(keyMappings); err != nil {
+		return nil, err
+	}
+
+	if refreshIntervalSeconds != 0 && refreshIntervalSeconds < types.MinEnvGroupProviderRefreshIntervalSeconds {
+		return nil, fmt.Errorf("refresh interval must be at least %d seconds", types.MinEnvGroupProviderRefreshIntervalSeconds)
+	}
+
+	confData, err := json.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	mappingsData, err := json.Marshal(keyMappings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EnvGroupProvider{
+		ProjectID:              cluster.ProjectID,
+		ClusterID:              cluster.ID,
+		EnvGroupName:           envGroupName,
+		Type:                   types.EnvGroupProviderType(envGroupType),
+		Config:                 confData,
+		KeyMappings:            mappingsData,
+		RefreshIntervalSeconds: refreshIntervalSeconds,
+	}, nil
+}
+
+// PreviewEnvGroupProviderHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/provider-preview endpoint
+type PreviewEnvGroupProviderHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewPreviewEnvGroupProviderHandler handles POST requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/provider-preview
+func NewPreviewEnvGroupProviderHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *PreviewEnvGroupProviderHandler {
+	return &PreviewEnvGroupProviderHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// PreviewEnvGroupProviderRequest is the request object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/provider-preview endpoint
+type PreviewEnvGroupProviderRequest struct {
+	// Name of the env group the provider would be configured for
+	Name string `json:"name"`
+
+	// Type is the provider type, either vault or aws-secrets-manager
+	Type EnvironmentGroupType `json:"type"`
+
+	// VaultEnv is the Vault secret to preview, only required for the Vault external provider type
+	VaultEnv types.VaultEnvGroupProviderConfig `json:"vault_env"`
+
+	// AWSSecretsManagerEnv is the AWS Secrets Manager secret to preview, only required for the AWS Secrets Manager external provider type
+	AWSSecretsManagerEnv types.AWSSecretsManagerEnvGroupProviderConfig `json:"aws_secrets_manager_env"`
+
+	// KeyMappings renames the keys of the secret to variable names
+	KeyMappings map[string]string `json:"key_mappings"`
+}
+
+// PreviewEnvGroupProviderResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/provider-preview endpoint
+type PreviewEnvGroupProviderResponse struct {
+	// Keys are the keys of the secret and the variables they would be synced to. Values are never returned.
+	Keys []types.EnvGroupProviderPreviewKey `json:"keys"`
+	// Error is set if the keys can't be synced as variables, for example because two keys map to the same variable
+	Error string `json:"error,omitempty"`
+}
+
+// ServeHTTP resolves the secret of a provider configuration and returns the names of its keys, without their values
+func (c *PreviewEnvGroupProviderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-preview-env-group-provider")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &PreviewEnvGroupProviderRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "environment-group-type", Value: request.Type},
+	)
+
+	provider, err := newEnvGroupProvider(c.Repo(), cluster, request.Name, request.Type, request.VaultEnv, request.AWSSecretsManagerEnv, request.KeyMappings, 0)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "invalid env group provider")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	values, err := envgroupprovider.Resolve(ctx, c.Repo(), provider)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "unable to resolve provider secret")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	res := &PreviewEnvGroupProviderResponse{
+		Keys: envgroupprovider.PreviewKeys(values, request.KeyMappings),
+	}
+
+	if _, err := envgroupprovider.MapKeys(values, request.KeyMappings); err != nil {
+		res.Error = err.Error()
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// GetEnvGroupProviderHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/provider endpoint
+type GetEnvGroupProviderHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetEnvGroupProviderHandler handles GET requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/provider
+func NewGetEnvGroupProviderHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetEnvGroupProviderHandler {
+	return &GetEnvGroupProviderHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP returns the Vault or AWS Secrets Manager provider of an environment group and the result of its last sync
+func (c *GetEnvGroupProviderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-env-group-provider")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-name", Value: envGroupName})
+
+	provider, err := c.Repo().EnvGroupProvider().ReadEnvGroupProvider(ctx, cluster.ProjectID, cluster.ID, envGroupName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, nil, "environment group is not synced from vault or aws secrets manager")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+
+		err := telemetry.Error(ctx, span, err, "unable to read env group provider")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, provider.ToEnvGroupProviderType())
+}
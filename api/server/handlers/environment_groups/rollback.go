@@ -0,0 +1,139 @@
+package environment_groups
+
+import (
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+
+	"github.com/karagatandev/porter/api/server/authz"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// RollbackEnvironmentGroupHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/rollback endpoint
+type RollbackEnvironmentGroupHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewRollbackEnvironmentGroupHandler handles POST requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/rollback
+func NewRollbackEnvironmentGroupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RollbackEnvironmentGroupHandler {
+	return &RollbackEnvironmentGroupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// RollbackEnvironmentGroupRequest is the request object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/rollback endpoint
+type RollbackEnvironmentGroupRequest struct {
+	// Version is the version to roll back to
+	Version int `json:"version" form:"required"`
+
+	// SkipAppAutoDeploy is a flag to determine if linked apps should be redeployed with the new version
+	SkipAppAutoDeploy bool `json:"skip_app_auto_deploy"`
+}
+
+// RollbackEnvironmentGroupResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/rollback endpoint
+type RollbackEnvironmentGroupResponse struct {
+	Name string `json:"name"`
+	// RolledBackTo is the version which was copied
+	RolledBackTo int `json:"rolled_back_to"`
+	// Version is the new version, which is equal to the version which was copied
+	Version EnvironmentGroupVersion `json:"version"`
+}
+
+// ServeHTTP creates a new version of an environment group equal to a previous version, and resyncs the apps linked to it
+func (c *RollbackEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-rollback-env-group")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &RollbackEnvironmentGroupRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "rollback-version", Value: request.Version},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	newVersion, err := environmentgroups.RollbackBaseEnvironmentGroup(ctx, agent, environmentgroups.RollbackBaseEnvironmentGroupInput{
+		Name:    envGroupName,
+		Version: request.Version,
+		Author:  user.Email,
+	})
+	if err != nil {
+		var notFound environmentgroups.ErrEnvironmentGroupVersionNotFound
+		if errors.As(err, &notFound) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+		if errors.Is(err, environmentgroups.ErrEnvironmentGroupNotRollbackable) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "unable to roll back env group")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "new-version", Value: newVersion.Version})
+
+	if !request.SkipAppAutoDeploy {
+		_, err = c.Config().ClusterControlPlaneClient.UpdateAppsLinkedToEnvGroup(ctx, connect.NewRequest(&porterv1.UpdateAppsLinkedToEnvGroupRequest{
+			ProjectId:    int64(project.ID),
+			ClusterId:    int64(cluster.ID),
+			EnvGroupName: envGroupName,
+		}))
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error calling ccp update apps linked to env group")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	res := &RollbackEnvironmentGroupResponse{
+		Name:         envGroupName,
+		RolledBackTo: request.Version,
+		Version: EnvironmentGroupVersion{
+			Version:         newVersion.Version,
+			Author:          newVersion.Author,
+			CreatedAtUTC:    newVersion.CreatedAtUTC,
+			Variables:       newVersion.Variables,
+			SecretVariables: newVersion.SecretVariables,
+		},
+	}
+
+	c.WriteResult(w, r, res)
+}
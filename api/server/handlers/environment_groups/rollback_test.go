@@ -0,0 +1,91 @@
+package environment_groups_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/karagatandev/porter/api/server/handlers/environment_groups"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apitest"
+	"github.com/karagatandev/porter/api/types"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
+)
+
+func TestRollbackEnvironmentGroup(t *testing.T) {
+	config, agent := loadEnvGroupHistory(t)
+	req, rr := envGroupRequest(t, config, http.MethodPost, "/api/projects/1/clusters/1/environment-groups/shared/rollback", "shared", &environment_groups.RollbackEnvironmentGroupRequest{
+		Version:           1,
+		SkipAppAutoDeploy: true,
+	})
+
+	handler := environment_groups.NewRollbackEnvironmentGroupHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+	handler.KubernetesAgentGetter = fakeAgentGetter{agent: agent}
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	res := &environment_groups.RollbackEnvironmentGroupResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(res); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.RolledBackTo != 1 || res.Version.Version != 3 {
+		t.Errorf("expected version 1 to be copied to version 3, got version %d copied to version %d", res.RolledBackTo, res.Version.Version)
+	}
+
+	if res.Version.Author != "mrp@porter.run" {
+		t.Errorf("expected the user rolling back to be the author of the new version, got %s", res.Version.Author)
+	}
+
+	if !reflect.DeepEqual(res.Version.Variables, map[string]string{"PORT": "80", "REGION": "us-east-1"}) {
+		t.Errorf("expected the variables of version 1, got %v", res.Version.Variables)
+	}
+
+	if res.Version.SecretVariables["DB_PASSWORD"] != environmentgroups.EnvGroupSecretDummyValue {
+		t.Errorf("expected the secret variables of the new version to be masked, got %s", res.Version.SecretVariables["DB_PASSWORD"])
+	}
+
+	versions, err := environmentgroups.ListBaseEnvironmentGroupVersions(context.Background(), agent, "shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(versions) != 3 || versions[0].Version != 3 {
+		t.Fatalf("expected rollback to create version 3, got %d versions", len(versions))
+	}
+
+	if !reflect.DeepEqual(versions[0].Variables, versions[2].Variables) {
+		t.Errorf("expected version 3 to equal version 1, got %v and %v", versions[0].Variables, versions[2].Variables)
+	}
+}
+
+func TestRollbackEnvironmentGroupNotFound(t *testing.T) {
+	config, agent := loadEnvGroupHistory(t)
+	req, rr := envGroupRequest(t, config, http.MethodPost, "/api/projects/1/clusters/1/environment-groups/shared/rollback", "shared", &environment_groups.RollbackEnvironmentGroupRequest{
+		Version:           5,
+		SkipAppAutoDeploy: true,
+	})
+
+	handler := environment_groups.NewRollbackEnvironmentGroupHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+	handler.KubernetesAgentGetter = fakeAgentGetter{agent: agent}
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusNotFound, &types.ExternalError{
+		Error: "version 5 of environment group shared does not exist",
+	})
+}
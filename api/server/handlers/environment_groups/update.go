@@ -2,6 +2,7 @@ package environment_groups
 
 import (
 	"encoding/base64"
+	"errors"
 	"net/http"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/apierrors"
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/envgroupprovider"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/telemetry"
 )
@@ -48,6 +51,10 @@ const (
 	EnvironmentGroupType_Datastore EnvironmentGroupType = "datastore"
 	// EnvironmentGroupType_Infisical is the infisical environment group type
 	EnvironmentGroupType_Infisical EnvironmentGroupType = "infisical"
+	// EnvironmentGroupType_Vault is the HashiCorp Vault environment group type
+	EnvironmentGroupType_Vault EnvironmentGroupType = EnvironmentGroupType(types.EnvGroupProviderTypeVault)
+	// EnvironmentGroupType_AWSSecretsManager is the AWS Secrets Manager environment group type
+	EnvironmentGroupType_AWSSecretsManager EnvironmentGroupType = EnvironmentGroupType(types.EnvGroupProviderTypeAWSSecretsManager)
 )
 
 // EnvVariableDeletions is the set of keys to delete from the environment group
@@ -96,6 +103,18 @@ type UpdateEnvironmentGroupRequest struct {
 
 	// InfisicalEnv is the Infisical environment to pull secret values from, only required for the Infisical external provider type
 	InfisicalEnv InfisicalEnv `json:"infisical_env"`
+
+	// VaultEnv is the Vault secret to sync values from, only required for the Vault external provider type
+	VaultEnv types.VaultEnvGroupProviderConfig `json:"vault_env"`
+
+	// AWSSecretsManagerEnv is the AWS Secrets Manager secret to sync values from, only required for the AWS Secrets Manager external provider type
+	AWSSecretsManagerEnv types.AWSSecretsManagerEnvGroupProviderConfig `json:"aws_secrets_manager_env"`
+
+	// KeyMappings renames the keys of a Vault or AWS Secrets Manager secret to variable names. Keys which are not mapped keep their name.
+	KeyMappings map[string]string `json:"key_mappings"`
+
+	// RefreshIntervalSeconds is how often a Vault or AWS Secrets Manager secret is synced. Defaults to an hour.
+	RefreshIntervalSeconds int `json:"refresh_interval_seconds"`
 }
 type UpdateEnvironmentGroupResponse struct {
 	// Name of the env group to create or update
@@ -165,6 +184,59 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 			return
 		}
 
+	case EnvironmentGroupType_Vault, EnvironmentGroupType_AWSSecretsManager:
+		provider, err := newEnvGroupProvider(c.Repo(), cluster, request.Name, request.Type, request.VaultEnv, request.AWSSecretsManagerEnv, request.KeyMappings, request.RefreshIntervalSeconds)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "invalid env group provider")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		agent, err := c.GetAgent(r, cluster, "")
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "unable to connect to cluster")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+			return
+		}
+
+		var author string
+		if user, ok := ctx.Value(types.UserScope).(*models.User); ok {
+			author = user.Email
+		}
+
+		// the provider is only saved once its secret has been synced, so that misconfigured providers are not refreshed
+		output, err := envgroupprovider.Sync(ctx, agent, c.Repo(), provider, author)
+		if err != nil {
+			var typeMismatch environmentgroups.ErrEnvironmentGroupTypeMismatch
+			if errors.As(err, &typeMismatch) {
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+				return
+			}
+
+			err := telemetry.Error(ctx, span, err, "unable to sync environment group from provider")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		if _, err := c.Repo().EnvGroupProvider().UpdateEnvGroupProvider(ctx, provider); err != nil {
+			err := telemetry.Error(ctx, span, err, "unable to save env group provider")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		if output.Changed && !request.SkipAppAutoDeploy {
+			_, err = c.Config().ClusterControlPlaneClient.UpdateAppsLinkedToEnvGroup(ctx, connect.NewRequest(&porterv1.UpdateAppsLinkedToEnvGroupRequest{
+				ProjectId:    int64(cluster.ProjectID),
+				ClusterId:    int64(cluster.ID),
+				EnvGroupName: request.Name,
+			}))
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "unable to update linked apps")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+
 	default:
 		var files []*porterv1.EnvGroupFile
 		for _, file := range request.Files {
@@ -198,6 +270,14 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
+
+		// the new version is created by the cluster control plane, so its author is recorded here. The update has
+		// already succeeded, so failing to record the author is not returned to the user
+		if user, ok := ctx.Value(types.UserScope).(*models.User); ok {
+			if err := c.recordVersionAuthor(r, cluster, request.Name, user.Email); err != nil {
+				_ = telemetry.Error(ctx, span, err, "unable to record environment group version author")
+			}
+		}
 	}
 
 	envGroupResponse := &UpdateEnvironmentGroupResponse{
@@ -206,3 +286,24 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 	}
 	c.WriteResult(w, r, envGroupResponse)
 }
+
+// recordVersionAuthor records the author of the latest version of an environment group, unless it has already been recorded
+func (c *UpdateEnvironmentGroupHandler) recordVersionAuthor(r *http.Request, cluster *models.Cluster, envGroupName, author string) error {
+	ctx := r.Context()
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		return err
+	}
+
+	latest, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+	if err != nil {
+		return err
+	}
+
+	if latest.Version == 0 || latest.Author != "" {
+		return nil
+	}
+
+	return environmentgroups.SetBaseEnvironmentGroupVersionAuthor(ctx, agent, envGroupName, latest.Version, author)
+}
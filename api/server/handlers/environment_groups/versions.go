@@ -0,0 +1,111 @@
+package environment_groups
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/karagatandev/porter/api/server/authz"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ListEnvironmentGroupVersionsHandler is the handler for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/versions endpoint
+type ListEnvironmentGroupVersionsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewListEnvironmentGroupVersionsHandler handles GET requests to /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/versions
+func NewListEnvironmentGroupVersionsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListEnvironmentGroupVersionsHandler {
+	return &ListEnvironmentGroupVersionsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// EnvironmentGroupVersion is a single version of an environment group. Secret values are always masked.
+type EnvironmentGroupVersion struct {
+	Version         int               `json:"version"`
+	Author          string            `json:"author,omitempty"`
+	CreatedAtUTC    time.Time         `json:"created_at"`
+	Variables       map[string]string `json:"variables,omitempty"`
+	SecretVariables map[string]string `json:"secret_variables,omitempty"`
+	Files           []string          `json:"files,omitempty"`
+}
+
+// ListEnvironmentGroupVersionsResponse is the response object for the /projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/versions endpoint
+type ListEnvironmentGroupVersionsResponse struct {
+	Name string `json:"name"`
+	// Versions are the versions of the environment group, newest first
+	Versions []EnvironmentGroupVersion `json:"versions"`
+}
+
+// ServeHTTP lists all versions of an environment group with their author and creation time
+func (c *ListEnvironmentGroupVersionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-env-group-versions")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName})
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	versions, err := environmentgroups.ListBaseEnvironmentGroupVersions(ctx, agent, envGroupName)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to list env group versions")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if len(versions) == 0 {
+		err = telemetry.Error(ctx, span, nil, "env group not found")
+		c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+		return
+	}
+
+	res := &ListEnvironmentGroupVersionsResponse{
+		Name:     envGroupName,
+		Versions: make([]EnvironmentGroupVersion, 0, len(versions)),
+	}
+
+	for _, version := range versions {
+		var files []string
+		for _, file := range version.Files {
+			files = append(files, file.Name)
+		}
+
+		res.Versions = append(res.Versions, EnvironmentGroupVersion{
+			Version:         version.Version,
+			Author:          version.Author,
+			CreatedAtUTC:    version.CreatedAtUTC,
+			Variables:       version.Variables,
+			SecretVariables: version.SecretVariables,
+			Files:           files,
+		})
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,144 @@
+package environment_groups_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/karagatandev/porter/api/server/authz"
+	"github.com/karagatandev/porter/api/server/handlers/environment_groups"
+	"github.com/karagatandev/porter/api/server/handlers/project"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apitest"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/kubernetes"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
+	"github.com/karagatandev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestListEnvironmentGroupVersions(t *testing.T) {
+	config, agent := loadEnvGroupHistory(t)
+	req, rr := envGroupRequest(t, config, http.MethodGet, "/api/projects/1/clusters/1/environment-groups/shared/versions", "shared", nil)
+
+	handler := environment_groups.NewListEnvironmentGroupVersionsHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+	handler.KubernetesAgentGetter = fakeAgentGetter{agent: agent}
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	res := &environment_groups.ListEnvironmentGroupVersionsResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(res); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Versions) != 2 || res.Versions[0].Version != 2 || res.Versions[1].Version != 1 {
+		t.Fatalf("expected versions 2 and 1, newest first, got %+v", res.Versions)
+	}
+
+	if res.Versions[0].Author != "dev@example.com" {
+		t.Errorf("expected the author of version 2 to be dev@example.com, got %s", res.Versions[0].Author)
+	}
+
+	for _, version := range res.Versions {
+		if version.SecretVariables["DB_PASSWORD"] != environmentgroups.EnvGroupSecretDummyValue {
+			t.Errorf("expected the secret variables of version %d to be masked, got %s", version.Version, version.SecretVariables["DB_PASSWORD"])
+		}
+	}
+}
+
+func TestListEnvironmentGroupVersionsNotFound(t *testing.T) {
+	config, agent := loadEnvGroupHistory(t)
+	req, rr := envGroupRequest(t, config, http.MethodGet, "/api/projects/1/clusters/1/environment-groups/missing/versions", "missing", nil)
+
+	handler := environment_groups.NewListEnvironmentGroupVersionsHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+	handler.KubernetesAgentGetter = fakeAgentGetter{agent: agent}
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rr.Code)
+	}
+}
+
+// fakeAgentGetter returns an agent for a fake cluster
+type fakeAgentGetter struct {
+	authz.KubernetesAgentGetter
+	agent *kubernetes.Agent
+}
+
+func (f fakeAgentGetter) GetAgent(r *http.Request, cluster *models.Cluster, namespace string) (*kubernetes.Agent, error) {
+	return f.agent, nil
+}
+
+// loadEnvGroupHistory returns a config and a fake cluster with two versions of the environment group "shared"
+func loadEnvGroupHistory(t *testing.T) (*config.Config, *kubernetes.Agent) {
+	t.Helper()
+
+	config := apitest.LoadConfig(t)
+	agent := kubernetes.GetAgentTesting()
+
+	versions := []environmentgroups.EnvironmentGroup{
+		{
+			Name:            "shared",
+			Variables:       map[string]string{"PORT": "80", "REGION": "us-east-1"},
+			SecretVariables: map[string]string{"DB_PASSWORD": "hunter2"},
+			Author:          "admin@example.com",
+		},
+		{
+			Name:            "shared",
+			Variables:       map[string]string{"PORT": "8080", "REGION": "us-east-1"},
+			SecretVariables: map[string]string{"DB_PASSWORD": "hunter3"},
+			Author:          "dev@example.com",
+		},
+	}
+
+	for _, version := range versions {
+		if err := environmentgroups.CreateOrUpdateBaseEnvironmentGroup(context.Background(), agent, version, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return config, agent
+}
+
+// envGroupRequest returns a request by a project admin to an endpoint of the environment group envGroupName in cluster 1
+func envGroupRequest(t *testing.T, config *config.Config, method, route, envGroupName string, requestObj interface{}) (*http.Request, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, method, route, requestObj)
+
+	req = apitest.WithURLParams(t, req, map[string]string{
+		"project_id":                       "1",
+		"cluster_id":                       "1",
+		string(types.URLParamEnvGroupName): envGroupName,
+	})
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+	req = apitest.WithCluster(t, req, &models.Cluster{Model: gorm.Model{ID: 1}, ProjectID: proj.ID})
+
+	return req, rr
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/apierrors"
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm/loader"
 	"github.com/karagatandev/porter/internal/models"
 	"gorm.io/gorm"
 )
@@ -56,11 +57,37 @@ func (p *HelmRepoCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	// registry credentials are only used to pull charts from oci:// repos
+	if request.RegistryID != 0 {
+		if !loader.IsOCIRepoURL(request.URL) {
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("a registry can only be used with oci:// repo urls"), http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		_, err := p.Repo().Registry().ReadRegistry(proj.ID, request.RegistryID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				p.HandleAPIError(w, r, apierrors.NewErrForbidden(
+					fmt.Errorf("registry with id %d not found in project %d", request.RegistryID, proj.ID),
+				))
+
+				return
+			}
+
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
 	hr := &models.HelmRepo{
 		Name:                   request.Name,
 		ProjectID:              proj.ID,
 		RepoURL:                request.URL,
 		BasicAuthIntegrationID: request.BasicIntegrationID,
+		RegistryID:             request.RegistryID,
 	}
 
 	// handle write to the database
@@ -11,6 +11,7 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/types"
 	"github.com/karagatandev/porter/internal/helm/loader"
+	porterrepo "github.com/karagatandev/porter/internal/helm/repo"
 	"github.com/karagatandev/porter/internal/models"
 )
 
@@ -47,6 +48,16 @@ func (t *ChartListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			Username: string(basic.Username),
 			Password: string(basic.Password),
 		}, helmRepo.RepoURL)
+	} else if loader.IsOCIRepoURL(helmRepo.RepoURL) {
+		var client *loader.BasicAuthClient
+
+		client, err = porterrepo.GetOCIAuthClient(r.Context(), t.Repo(), proj.ID, helmRepo.RegistryID, helmRepo.RepoURL, t.Config().DOConf, t.Config().GithubAppConf)
+		if err != nil {
+			t.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		repoIndex, err = loader.LoadRepoIndex(client, helmRepo.RepoURL)
 	} else {
 		repoIndex, err = loader.LoadRepoIndexPublic(helmRepo.RepoURL)
 	}
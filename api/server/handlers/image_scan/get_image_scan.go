@@ -0,0 +1,72 @@
+package image_scan
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetImageScanHandler returns all vulnerabilities found by the latest scan of an image
+type GetImageScanHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewGetImageScanHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GetImageScanHandler {
+	return &GetImageScanHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *GetImageScanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-image-scan")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	request := &types.GetImageScanRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	var scan *models.ImageScan
+	var err error
+
+	if request.Digest != "" {
+		scan, err = c.Repo().ImageScan().ReadImageScanByDigest(ctx, reg.ProjectID, request.Digest)
+	} else {
+		scan, err = c.Repo().ImageScan().ReadImageScanByTag(ctx, reg.ProjectID, request.Repository, request.Tag)
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(fmt.Errorf("image has not been scanned")))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading image scan")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res, err := scan.ToImageScanType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding image scan")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,51 @@
+package image_scan
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetPolicyHandler returns the image scan policy of a project
+type GetPolicyHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetPolicyHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetPolicyHandler {
+	return &GetPolicyHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-image-scan-policy")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	policy, err := c.Repo().ImageScan().ReadImageScanPolicy(ctx, proj.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// projects without a policy don't act on scan findings
+			c.WriteResult(w, r, &types.ImageScanPolicy{Action: types.ImageScanPolicyActionOff})
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading image scan policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, policy.ToImageScanPolicyType())
+}
@@ -0,0 +1,74 @@
+package image_scan
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/imagescan"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/registry"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ScanImageHandler scans an image of a registry for vulnerabilities and stores the findings
+type ScanImageHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewScanImageHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ScanImageHandler {
+	return &ScanImageHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ScanImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-scan-image")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	request := &types.ScanImageRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if registry.FindRegistryForImage([]*models.Registry{reg}, request.Repository) == nil {
+		err := telemetry.Error(ctx, span, nil, "repository is not hosted by the registry")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	_reg := registry.Registry(*reg)
+
+	scan, err := _reg.ScanImage(ctx, c.Config(), request.Repository, request.Tag, request.Digest)
+	if err != nil {
+		switch {
+		case errors.Is(err, registry.ErrImageScanningDisabled):
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusPreconditionFailed))
+		case errors.Is(err, imagescan.ErrImageNotFound):
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+		default:
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		}
+
+		return
+	}
+
+	res, err := scan.ToImageScanType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding image scan")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,58 @@
+package image_scan
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// UpdatePolicyHandler sets the image scan policy of a project
+type UpdatePolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdatePolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdatePolicyHandler {
+	return &UpdatePolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdatePolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-image-scan-policy")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateImageScanPolicyRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "action", Value: string(request.Action)},
+		telemetry.AttributeKV{Key: "severity-threshold", Value: request.SeverityThreshold},
+	)
+
+	policy, err := c.Repo().ImageScan().UpdateImageScanPolicy(ctx, &models.ImageScanPolicy{
+		ProjectID:         proj.ID,
+		Action:            string(request.Action),
+		SeverityThreshold: request.SeverityThreshold,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating image scan policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, policy.ToImageScanPolicyType())
+}
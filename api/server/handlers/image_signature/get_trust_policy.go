@@ -0,0 +1,62 @@
+package image_signature
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetTrustPolicyHandler returns the image trust policy of a project
+type GetTrustPolicyHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetTrustPolicyHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetTrustPolicyHandler {
+	return &GetTrustPolicyHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetTrustPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-image-trust-policy")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	policy, err := c.Repo().ImageSignature().ReadImageTrustPolicy(ctx, proj.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// projects without a policy don't trust any signers
+			c.WriteResult(w, r, &types.ImageTrustPolicy{
+				PublicKeys:   []types.TrustedPublicKey{},
+				Identities:   []types.TrustedIdentity{},
+				Attestations: []string{},
+			})
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading image trust policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res, err := policy.ToImageTrustPolicyType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding image trust policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,88 @@
+package image_signature
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/imagesign"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// UpdateTrustPolicyHandler sets the image trust policy of a project
+type UpdateTrustPolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateTrustPolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateTrustPolicyHandler {
+	return &UpdateTrustPolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateTrustPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-image-trust-policy")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateImageTrustPolicyRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "public-keys", Value: len(request.PublicKeys)},
+		telemetry.AttributeKV{Key: "identities", Value: len(request.Identities)},
+		telemetry.AttributeKV{Key: "attestations", Value: len(request.Attestations)},
+	)
+
+	trustPolicy := imagesign.TrustPolicy{
+		Attestations: request.Attestations,
+	}
+
+	for _, key := range request.PublicKeys {
+		trustPolicy.PublicKeys = append(trustPolicy.PublicKeys, imagesign.PublicKey{Name: key.Name, PEM: key.PEM})
+	}
+
+	for _, identity := range request.Identities {
+		trustPolicy.Identities = append(trustPolicy.Identities, imagesign.Identity{Issuer: identity.Issuer, Subject: identity.Subject})
+	}
+
+	if err := trustPolicy.Validate(); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid image trust policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	policyModel, err := models.NewImageTrustPolicy(proj.ID, trustPolicy)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error encoding image trust policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	policyModel, err = c.Repo().ImageSignature().UpdateImageTrustPolicy(ctx, policyModel)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating image trust policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res, err := policyModel.ToImageTrustPolicyType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding image trust policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
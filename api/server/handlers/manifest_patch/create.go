@@ -0,0 +1,85 @@
+package manifest_patch
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+type CreateManifestPatchHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateManifestPatchHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateManifestPatchHandler {
+	return &CreateManifestPatchHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *CreateManifestPatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-manifest-patch")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateManifestPatchRequest{}
+
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "name", Value: request.Name},
+		telemetry.AttributeKV{Key: "app-name", Value: request.AppName},
+		telemetry.AttributeKV{Key: "type", Value: string(request.Type)},
+	)
+
+	patch := NewManifestPatchFromRequest(proj.ID, request)
+	patch.CreatedByUserID = user.ID
+
+	if err := helm.ValidateManifestPatch(patch); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid manifest patch")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	patch, err := p.Repo().ManifestPatch().CreateManifestPatch(ctx, patch)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating manifest patch")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	p.WriteResult(w, r, patch.ToManifestPatchType())
+}
+
+// NewManifestPatchFromRequest returns the manifest patch of a project described by a request
+func NewManifestPatchFromRequest(projectID uint, request *types.CreateManifestPatchRequest) *models.ManifestPatch {
+	patch := &models.ManifestPatch{
+		ProjectID: projectID,
+		Name:      request.Name,
+		AppName:   request.AppName,
+		ChartName: request.ChartName,
+		ClusterID: request.ClusterID,
+		Priority:  request.Priority,
+		Type:      string(request.Type),
+		Patch:     request.Patch,
+	}
+
+	patch.SetTarget(request.Target)
+
+	return patch
+}
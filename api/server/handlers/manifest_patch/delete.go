@@ -0,0 +1,63 @@
+package manifest_patch
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+type DeleteManifestPatchHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewDeleteManifestPatchHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DeleteManifestPatchHandler {
+	return &DeleteManifestPatchHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (p *DeleteManifestPatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-manifest-patch")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	patchID, reqErr := requestutils.GetURLParamUint(r, types.URLParamManifestPatchID)
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	patch, err := p.Repo().ManifestPatch().ReadManifestPatch(ctx, proj.ID, patchID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "manifest patch not found in project")
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading manifest patch")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if _, err := p.Repo().ManifestPatch().DeleteManifestPatch(ctx, patch); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting manifest patch")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, patch.ToManifestPatchType())
+}
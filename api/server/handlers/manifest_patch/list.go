@@ -0,0 +1,50 @@
+package manifest_patch
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+type ListManifestPatchesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListManifestPatchesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListManifestPatchesHandler {
+	return &ListManifestPatchesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (p *ListManifestPatchesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-manifest-patches")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	patches, err := p.Repo().ManifestPatch().ListManifestPatchesByProjectID(ctx, proj.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing manifest patches")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.ListManifestPatchesResponse{
+		ManifestPatches: make([]*types.ManifestPatch, 0, len(patches)),
+	}
+
+	for _, patch := range patches {
+		res.ManifestPatches = append(res.ManifestPatches, patch.ToManifestPatchType())
+	}
+
+	p.WriteResult(w, r, res)
+}
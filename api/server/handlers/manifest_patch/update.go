@@ -0,0 +1,102 @@
+package manifest_patch
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+type UpdateManifestPatchHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateManifestPatchHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateManifestPatchHandler {
+	return &UpdateManifestPatchHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *UpdateManifestPatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-manifest-patch")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	patchID, reqErr := requestutils.GetURLParamUint(r, types.URLParamManifestPatchID)
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	request := &types.UpdateManifestPatchRequest{}
+
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	patch, err := p.Repo().ManifestPatch().ReadManifestPatch(ctx, proj.ID, patchID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "manifest patch not found in project")
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading manifest patch")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if request.AppName != nil {
+		patch.AppName = *request.AppName
+	}
+
+	if request.ChartName != nil {
+		patch.ChartName = *request.ChartName
+	}
+
+	if request.ClusterID != nil {
+		patch.ClusterID = *request.ClusterID
+	}
+
+	if request.Priority != nil {
+		patch.Priority = *request.Priority
+	}
+
+	if request.Target != nil {
+		patch.SetTarget(*request.Target)
+	}
+
+	if request.Patch != nil {
+		patch.Patch = *request.Patch
+	}
+
+	if err := helm.ValidateManifestPatch(patch); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid manifest patch")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	patch, err = p.Repo().ManifestPatch().UpdateManifestPatch(ctx, patch)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating manifest patch")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, patch.ToManifestPatchType())
+}
@@ -0,0 +1,121 @@
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/auth/oidc"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+type CreateTrustRuleHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateTrustRuleHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateTrustRuleHandler {
+	return &CreateTrustRuleHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *CreateTrustRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-oidc-trust-rule")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateOIDCTrustRuleRequest{}
+
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	// exchanged tokens are issued on behalf of the user who created the rule, which api
+	// tokens cannot stand in for
+	if user.ID == 0 {
+		err := telemetry.Error(ctx, span, nil, "trust rules must be created by a user")
+		p.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	if issuerURL, err := url.Parse(request.Issuer); err != nil || issuerURL.Scheme != "https" {
+		err := telemetry.Error(ctx, span, nil, "issuer must be an https url")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if time.Duration(request.TokenTTLSeconds)*time.Second > types.MaxOIDCTokenTTL {
+		err := telemetry.Error(ctx, span, nil, fmt.Sprintf("token ttl cannot exceed %s", types.MaxOIDCTokenTTL))
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "cluster-id", Value: request.ClusterID},
+		telemetry.AttributeKV{Key: "deployment-target", Value: request.DeploymentTarget},
+		telemetry.AttributeKV{Key: "app-name", Value: request.AppName},
+	)
+
+	if _, err := p.Repo().Cluster().ReadCluster(proj.ID, request.ClusterID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "cluster not found in project")
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading cluster")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	deploymentTarget, err := p.Repo().DeploymentTarget().DeploymentTarget(proj.ID, request.DeploymentTarget)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading deployment target")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// policies refer to deployment targets by name
+	if deploymentTarget.ID == uuid.Nil || uint(deploymentTarget.ClusterID) != request.ClusterID || deploymentTarget.VanityName == "" {
+		err := telemetry.Error(ctx, span, nil, "deployment target not found in cluster")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	rule, err := oidc.CreateTrustRule(ctx, p.Repo(), &models.OIDCTrustRule{
+		ProjectID:        proj.ID,
+		CreatedByUserID:  user.ID,
+		Name:             request.Name,
+		Issuer:           request.Issuer,
+		Audience:         request.Audience,
+		SubjectPattern:   request.SubjectPattern,
+		ClusterID:        request.ClusterID,
+		DeploymentTarget: deploymentTarget.VanityName,
+		AppName:          request.AppName,
+		TokenTTLSeconds:  request.TokenTTLSeconds,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating trust rule")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	p.WriteResult(w, r, rule.ToOIDCTrustRuleType())
+}
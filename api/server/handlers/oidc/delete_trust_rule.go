@@ -0,0 +1,64 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/auth/oidc"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+type DeleteTrustRuleHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewDeleteTrustRuleHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DeleteTrustRuleHandler {
+	return &DeleteTrustRuleHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (p *DeleteTrustRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-oidc-trust-rule")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	ruleID, reqErr := requestutils.GetURLParamUint(r, types.URLParamOIDCTrustRuleID)
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	rule, err := p.Repo().OIDCTrustRule().ReadOIDCTrustRule(ctx, proj.ID, ruleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "trust rule not found in project")
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading trust rule")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := oidc.DeleteTrustRule(ctx, p.Repo(), rule); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting trust rule")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, rule.ToOIDCTrustRuleType())
+}
@@ -0,0 +1,98 @@
+package oidc
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/auth/oidc"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// TokenExchangeHandler exchanges the ID tokens of CI jobs for short-lived Porter tokens,
+// according to the trust rules of a project
+type TokenExchangeHandler struct {
+	handlers.PorterHandlerReadWriter
+
+	verifier *oidc.Verifier
+}
+
+func NewTokenExchangeHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *TokenExchangeHandler {
+	return &TokenExchangeHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		verifier:                oidc.NewVerifier(nil),
+	}
+}
+
+func (p *TokenExchangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-oidc-token-exchange")
+	defer span.End()
+
+	request := &types.OIDCTokenExchangeRequest{}
+
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: request.ProjectID})
+
+	issuer, err := oidc.UnverifiedIssuer(request.IDToken)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading id token issuer")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "issuer", Value: issuer})
+
+	// only the keys of issuers which the project trusts are ever fetched
+	rules, err := p.Repo().OIDCTrustRule().ListOIDCTrustRulesByIssuer(ctx, request.ProjectID, issuer)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing trust rules")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if len(rules) == 0 {
+		err := telemetry.Error(ctx, span, nil, "issuer is not trusted by the project")
+		p.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	claims, err := p.verifier.Verify(ctx, request.IDToken, issuer)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error verifying id token")
+		p.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "subject", Value: claims.Subject})
+
+	rule, err := oidc.MatchTrustRule(rules, claims, request.AppName, request.DeploymentTarget)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "id token not permitted by trust rules")
+		p.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "trust-rule-id", Value: rule.ID})
+
+	encoded, expiry, err := oidc.IssueToken(ctx, p.Repo(), p.Config().TokenConf, rule, claims.Subject)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error issuing token")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, &types.OIDCTokenExchangeResponse{
+		Token:     encoded,
+		ExpiresAt: expiry,
+	})
+}
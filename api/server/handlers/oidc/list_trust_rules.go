@@ -0,0 +1,50 @@
+package oidc
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+type ListTrustRulesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListTrustRulesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListTrustRulesHandler {
+	return &ListTrustRulesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (p *ListTrustRulesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-oidc-trust-rules")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	rules, err := p.Repo().OIDCTrustRule().ListOIDCTrustRulesByProjectID(ctx, proj.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing trust rules")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.ListOIDCTrustRulesResponse{
+		TrustRules: make([]*types.OIDCTrustRule, 0, len(rules)),
+	}
+
+	for _, rule := range rules {
+		res.TrustRules = append(res.TrustRules, rule.ToOIDCTrustRuleType())
+	}
+
+	p.WriteResult(w, r, res)
+}
@@ -1,6 +1,7 @@
 package porter_app
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/server/shared/requestutils"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/auth/oidc"
 	"github.com/karagatandev/porter/internal/auth/token"
 	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/integrations/ci/actions"
@@ -73,8 +75,23 @@ func (c *OpenStackPRHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// app workflows exchange the job's ID token for a short-lived token scoped to the app,
+	// rather than storing a long-lived token in the repository's secrets
+	var oidcDeploymentTarget string
+	if request.DeleteWorkflowFilename == "" && request.PreviewsWorkflowFilename == "" && user.ID != 0 {
+		oidcDeploymentTarget, err = ensureGithubTrustRule(ctx, c.Config(), user, project, cluster, appName, request)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error creating github trust rule")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	useOIDC := oidcDeploymentTarget != ""
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "use-oidc", Value: useOIDC})
+
 	var secretName string
-	if request.DeleteWorkflowFilename == "" {
+	if request.DeleteWorkflowFilename == "" && !useOIDC {
 		// generate porter jwt token
 		jwt, err := token.GetTokenForAPI(user.ID, project.ID)
 		if err != nil {
@@ -133,20 +150,21 @@ func (c *OpenStackPRHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if request.OpenPr || request.DeleteWorkflowFilename != "" {
 		openPRInput := &actions.GithubPROpts{
-			PRAction:           actions.GithubPRAction_NewAppWorkflow,
-			Client:             client,
-			GitRepoOwner:       request.GithubRepoOwner,
-			GitRepoName:        request.GithubRepoName,
-			StackName:          appName,
-			ProjectID:          project.ID,
-			ClusterID:          cluster.ID,
-			ServerURL:          c.Config().ServerConf.ServerURL,
-			DefaultBranch:      request.Branch,
-			SecretName:         secretName,
-			PorterYamlPath:     request.PorterYamlPath,
-			Body:               prRequestBody,
-			PRBranch:           prBranchName,
-			DeploymentTargetId: request.DeploymentTargetId,
+			PRAction:             actions.GithubPRAction_NewAppWorkflow,
+			Client:               client,
+			GitRepoOwner:         request.GithubRepoOwner,
+			GitRepoName:          request.GithubRepoName,
+			StackName:            appName,
+			ProjectID:            project.ID,
+			ClusterID:            cluster.ID,
+			ServerURL:            c.Config().ServerConf.ServerURL,
+			DefaultBranch:        request.Branch,
+			SecretName:           secretName,
+			PorterYamlPath:       request.PorterYamlPath,
+			Body:                 prRequestBody,
+			PRBranch:             prBranchName,
+			DeploymentTargetId:   request.DeploymentTargetId,
+			OIDCDeploymentTarget: oidcDeploymentTarget,
 		}
 		if request.DeleteWorkflowFilename != "" {
 			openPRInput.PRAction = actions.GithubPRAction_DeleteAppWorkflow
@@ -234,3 +252,75 @@ func getGithubClient(config *config.Config, gitInstallationId int64) (*github.Cl
 
 	return github.NewClient(&http.Client{Transport: itr}), nil
 }
+
+// ensureGithubTrustRule makes sure that GitHub Actions runs on the pushes to the app's
+// branch can exchange their ID tokens for tokens which deploy the app. It returns the name
+// of the deployment target which the trust rule refers to, or an empty string if the app's
+// deployment target has no name that a trust rule can refer to.
+func ensureGithubTrustRule(
+	ctx context.Context,
+	config *config.Config,
+	user *models.User,
+	project *models.Project,
+	cluster *models.Cluster,
+	appName string,
+	request *types.CreateSecretAndOpenGHPRRequest,
+) (string, error) {
+	var deploymentTarget *models.DeploymentTarget
+
+	if request.DeploymentTargetId != "" {
+		target, err := config.Repo.DeploymentTarget().DeploymentTarget(project.ID, request.DeploymentTargetId)
+		if err != nil {
+			return "", fmt.Errorf("error reading deployment target: %w", err)
+		}
+
+		deploymentTarget = target
+	} else {
+		targets, err := config.Repo.DeploymentTarget().ListForCluster(project.ID, cluster.ID, false)
+		if err != nil {
+			return "", fmt.Errorf("error listing deployment targets: %w", err)
+		}
+
+		for _, target := range targets {
+			if target.IsDefault {
+				deploymentTarget = target
+				break
+			}
+		}
+	}
+
+	if deploymentTarget == nil || deploymentTarget.VanityName == "" {
+		return "", nil
+	}
+
+	subjectPattern := fmt.Sprintf("repo:%s/%s:ref:refs/heads/%s", request.GithubRepoOwner, request.GithubRepoName, request.Branch)
+
+	rules, err := config.Repo.OIDCTrustRule().ListOIDCTrustRulesByIssuer(ctx, project.ID, types.GithubActionsOIDCIssuer)
+	if err != nil {
+		return "", fmt.Errorf("error listing trust rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		if rule.SubjectPattern == subjectPattern && rule.AppName == appName && rule.DeploymentTarget == deploymentTarget.VanityName &&
+			rule.Audience == config.ServerConf.ServerURL && rule.ClusterID == cluster.ID {
+			return deploymentTarget.VanityName, nil
+		}
+	}
+
+	_, err = oidc.CreateTrustRule(ctx, config.Repo, &models.OIDCTrustRule{
+		ProjectID:        project.ID,
+		CreatedByUserID:  user.ID,
+		Name:             fmt.Sprintf("github-%s-%s", appName, deploymentTarget.VanityName),
+		Issuer:           types.GithubActionsOIDCIssuer,
+		Audience:         config.ServerConf.ServerURL,
+		SubjectPattern:   subjectPattern,
+		ClusterID:        cluster.ID,
+		DeploymentTarget: deploymentTarget.VanityName,
+		AppName:          appName,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return deploymentTarget.VanityName, nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/karagatandev/porter/internal/telemetry"
 	"github.com/pkg/errors"
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"gorm.io/gorm"
 )
 
 // GetAppRevisionHandler handles requests to the /apps/{porter_app_name}/revisions/{app_revision_id} endpoint
@@ -40,6 +41,10 @@ func NewGetAppRevisionHandler(
 // GetAppRevisionResponse represents the response from the /apps/{porter_app_name}/revisions/{app_revision_id} endpoint
 type GetAppRevisionResponse struct {
 	AppRevision porter_app.Revision `json:"app_revision"`
+	// ImageScan summarizes the vulnerabilities found in the revision's image, if it has been scanned
+	ImageScan *types.ImageScanSummary `json:"image_scan,omitempty"`
+	// ImageVerification records the signature verification of the revision's image, if it was verified
+	ImageVerification *types.ImageVerification `json:"image_verification,omitempty"`
 }
 
 // GetAppRevisionHandler returns a single app revision
@@ -129,5 +134,27 @@ func (c *GetAppRevisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		AppRevision: revisionWithEnv,
 	}
 
+	if image := ccpResp.Msg.AppRevision.GetApp().GetImage(); image != nil && image.Repository != "" {
+		scan, err := c.Repo().ImageScan().ReadImageScanByTag(ctx, project.ID, image.Repository, image.Tag)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "error reading image scan")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		if scan != nil {
+			res.ImageScan = scan.ToImageScanSummaryType()
+		}
+	}
+
+	verification, err := c.Repo().ImageSignature().ReadImageVerificationByAppRevisionID(ctx, project.ID, appRevisionID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err := telemetry.Error(ctx, span, err, "error reading image verification")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if verification != nil {
+		res.ImageVerification = verification.ToImageVerificationType()
+	}
+
 	c.WriteResult(w, r, res)
 }
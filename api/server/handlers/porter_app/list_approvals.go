@@ -0,0 +1,64 @@
+package porter_app
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ListAppRevisionApprovalsHandler handles requests to the /apps/{porter_app_name}/approvals endpoint
+type ListAppRevisionApprovalsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListAppRevisionApprovalsHandler returns a new ListAppRevisionApprovalsHandler
+func NewListAppRevisionApprovalsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListAppRevisionApprovalsHandler {
+	return &ListAppRevisionApprovalsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP lists the revisions of an app which were held for approval, newest first
+func (c *ListAppRevisionApprovalsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-app-revision-approvals")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, nil, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-name", Value: appName})
+
+	approvals, err := c.Repo().AppRevisionApproval().ListAppRevisionApprovals(ctx, project.ID, cluster.ID, appName)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error listing app revision approvals")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := &types.ListAppRevisionApprovalsResponse{
+		Approvals: make([]*types.AppRevisionApproval, 0, len(approvals)),
+	}
+
+	for _, approval := range approvals {
+		res.Approvals = append(res.Approvals, approval.ToAppRevisionApprovalType())
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,477 @@
+package porter_app
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/deployment_target"
+	"github.com/karagatandev/porter/internal/deploypolicy"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/porter_app"
+	v2 "github.com/karagatandev/porter/internal/porter_app/v2"
+	"github.com/karagatandev/porter/internal/registry"
+	"github.com/karagatandev/porter/internal/retention"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"github.com/porter-dev/api-contracts/generated/go/helpers"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+)
+
+// PromoteAppHandler promotes the deployed revision of an app from one deployment target to another
+type PromoteAppHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewPromoteAppHandler returns a new PromoteAppHandler
+func NewPromoteAppHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *PromoteAppHandler {
+	return &PromoteAppHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// PromoteAppRequest is the request object for the /apps/{porter_app_name}/promote endpoint
+type PromoteAppRequest struct {
+	// FromDeploymentTarget is the id or name of the deployment target to promote the app from
+	FromDeploymentTarget string `json:"from_deployment_target" form:"required"`
+	// ToDeploymentTarget is the id or name of the deployment target to promote the app to
+	ToDeploymentTarget string `json:"to_deployment_target" form:"required"`
+	// SourceAppRevisionID is the id of the revision expected to be promoted. If set, the promotion fails if another revision
+	// has been deployed to the source target since, so that the promoted revision is the one whose diff was reviewed.
+	SourceAppRevisionID string `json:"source_app_revision_id"`
+	// Overrides are patch operations applied to the promoted app definition, for settings which differ between targets
+	Overrides []v2.PatchOperation `json:"overrides"`
+	// WithPredeploy is a flag to indicate whether to run the predeploy job in the target
+	WithPredeploy bool `json:"with_predeploy"`
+	// DryRun returns the promotion and its diff without deploying it
+	DryRun bool `json:"dry_run"`
+	// BreakGlassReason overrides the freeze windows and protection rules of the destination target, and is recorded with the override
+	BreakGlassReason string `json:"break_glass_reason,omitempty"`
+}
+
+// PromoteAppResponse is the response object for the /apps/{porter_app_name}/promote endpoint
+type PromoteAppResponse struct {
+	// SourceAppRevisionID is the id of the promoted revision
+	SourceAppRevisionID string `json:"source_app_revision_id"`
+	// SourceRevisionNumber is the number of the promoted revision in the source target
+	SourceRevisionNumber int `json:"source_revision_number"`
+	// ImageRepository is the repository of the promoted image
+	ImageRepository string `json:"image_repository"`
+	// ImageTag is the tag of the promoted image, pinned to the digest deployed in the source target
+	ImageTag string `json:"image_tag"`
+	// Diff is the line diff between the app definition in the target and the promoted definition
+	Diff string `json:"diff"`
+	// AppRevisionID is the id of the revision created in the target, which is empty for dry runs
+	AppRevisionID string `json:"app_revision_id,omitempty"`
+	// Warnings are raised when the image could not be verified against the project's image trust policy, or the promotion
+	// overrode the policy of the destination target
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ServeHTTP copies the app definition and image digest of the latest deployed revision in the source target, applies the
+// target-specific overrides and deploys it to the destination target through the cluster control plane. The promotion is
+// recorded as an event in both targets.
+func (c *PromoteAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-promote-app")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+
+	if !project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient) {
+		err := telemetry.Error(ctx, span, nil, "project does not have validate apply v2 enabled")
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	appName, reqErr := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing porter app name")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &PromoteAppRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: appName},
+		telemetry.AttributeKV{Key: "from-deployment-target", Value: request.FromDeploymentTarget},
+		telemetry.AttributeKV{Key: "to-deployment-target", Value: request.ToDeploymentTarget},
+		telemetry.AttributeKV{Key: "dry-run", Value: request.DryRun},
+	)
+
+	source, err := c.Repo().DeploymentTarget().DeploymentTarget(project.ID, request.FromDeploymentTarget)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error reading source deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if source.ID == uuid.Nil {
+		err := telemetry.Error(ctx, span, nil, "source deployment target not found")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+
+	destination, err := c.Repo().DeploymentTarget().DeploymentTarget(project.ID, request.ToDeploymentTarget)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error reading destination deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if destination.ID == uuid.Nil {
+		err := telemetry.Error(ctx, span, nil, "destination deployment target not found")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+
+	// the request is authorized to deploy to the cluster in its url, while the source is authorized separately
+	if uint(destination.ClusterID) != cluster.ID {
+		err := telemetry.Error(ctx, span, nil, "destination deployment target is not in the cluster of the request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden))
+		return
+	}
+
+	if source.ID == destination.ID {
+		err := telemetry.Error(ctx, span, nil, "source and destination deployment targets must be different")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "source-deployment-target-id", Value: source.ID.String()},
+		telemetry.AttributeKV{Key: "destination-deployment-target-id", Value: destination.ID.String()},
+	)
+
+	sourceApp, err := c.Repo().PorterApp().ReadPorterAppByName(uint(source.ClusterID), appName)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error reading porter app in source cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if sourceApp.ID == 0 {
+		err := telemetry.Error(ctx, span, nil, "app does not exist in the source deployment target's cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+
+	sourceRevision, err := c.Repo().AppRevision().LatestAppRevision(project.ID, sourceApp.ID, source.ID, models.DeployedAppRevisionStatuses...)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error reading latest deployed revision in source deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if sourceRevision.ID == uuid.Nil {
+		err := telemetry.Error(ctx, span, nil, "app has no deployed revision in the source deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "source-app-revision-id", Value: sourceRevision.ID.String()})
+
+	if request.SourceAppRevisionID != "" && request.SourceAppRevisionID != sourceRevision.ID.String() {
+		err := telemetry.Error(ctx, span, nil, "another revision has been deployed to the source deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	sourceProto, err := appProtoFromRevision(sourceRevision)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error decoding source app revision")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if sourceProto.Image == nil || sourceProto.Image.Repository == "" {
+		err := telemetry.Error(ctx, span, nil, "source app revision has no image")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	digest, err := registry.ResolveImageDigest(ctx, c.Config(), project.ID, sourceProto.Image.Repository, sourceProto.Image.Tag)
+	if err != nil {
+		if errors.Is(err, registry.ErrImageDigestNotFound) {
+			err := telemetry.Error(ctx, span, err, "unable to resolve digest of source image")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err := telemetry.Error(ctx, span, err, "error resolving digest of source image")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	tag, _ := retention.ParseImageTag(sourceProto.Image.Tag)
+	pinnedTag := fmt.Sprintf("%s@%s", tag, digest)
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "pinned-tag", Value: pinnedTag})
+
+	var destinationProto *porterv1.PorterApp
+
+	destinationApp, err := c.Repo().PorterApp().ReadPorterAppByName(uint(destination.ClusterID), appName)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error reading porter app in destination cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if destinationApp.ID != 0 {
+		destinationRevision, err := c.Repo().AppRevision().LatestAppRevision(project.ID, destinationApp.ID, destination.ID)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error reading latest revision in destination deployment target")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		if destinationRevision.ID != uuid.Nil {
+			destinationProto, err = appProtoFromRevision(destinationRevision)
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "error decoding destination app revision")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+	}
+
+	promoted, err := porter_app.PromotedApp(ctx, porter_app.PromotedAppInput{
+		Source:      sourceProto,
+		Destination: destinationProto,
+		PinnedTag:   pinnedTag,
+		Overrides:   request.Overrides,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error applying overrides to promoted app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	diff, err := porter_app.DefinitionDiff(destinationProto, promoted)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error computing diff of promoted app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	response := &PromoteAppResponse{
+		SourceAppRevisionID:  sourceRevision.ID.String(),
+		SourceRevisionNumber: sourceRevision.RevisionNumber,
+		ImageRepository:      promoted.Image.Repository,
+		ImageTag:             promoted.Image.Tag,
+		Diff:                 diff,
+	}
+
+	if request.DryRun {
+		c.WriteResult(w, r, response)
+		return
+	}
+
+	apiToken, _ := ctx.Value("api_token").(*models.APIToken)
+
+	policyDecision, err := deployment_target.CheckDeployPolicy(ctx, c.Config(), deployment_target.CheckDeployPolicyInput{
+		ProjectID:                  project.ID,
+		DeploymentTargetIdentifier: destination.ID.String(),
+		AppName:                    appName,
+		Action:                     deploypolicy.ActionPromote,
+		User:                       user,
+		APIToken:                   apiToken,
+		BreakGlassReason:           request.BreakGlassReason,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error checking deploy policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if policyDecision.Blocked {
+		err := telemetry.Error(ctx, span, nil, "promotion refused by deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), policyDecision.Message), http.StatusForbidden))
+		return
+	}
+	if policyDecision.Message != "" {
+		response.Warnings = append(response.Warnings, policyDecision.Message)
+	}
+
+	decision, err := registry.CheckImageScanPolicy(ctx, c.Config(), project.ID, promoted.Image.Repository, promoted.Image.Tag)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error checking image scan policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if decision.Blocked {
+		err := telemetry.Error(ctx, span, nil, "image blocked by image scan policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), decision.Message), http.StatusForbidden))
+		return
+	}
+
+	signatureDecision, err := registry.VerifyImageSignature(ctx, c.Config(), registry.VerifyImageSignatureInput{
+		ProjectID:                  project.ID,
+		DeploymentTargetIdentifier: destination.ID.String(),
+		Repository:                 promoted.Image.Repository,
+		Tag:                        promoted.Image.Tag,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error verifying image signature")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if signatureDecision.Blocked {
+		err := telemetry.Error(ctx, span, nil, "image refused by image trust policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), signatureDecision.Message), http.StatusForbidden))
+		return
+	}
+	if signatureDecision.Message != "" {
+		response.Warnings = append(response.Warnings, signatureDecision.Message)
+	}
+
+	sourceType, image, err := sourceFromAppAndGitSource(ctx, promoted, GitSource{
+		GitBranch:   sourceApp.GitBranch,
+		GitRepoName: sourceApp.RepoName,
+		GitRepoID:   sourceApp.GitRepoID,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting source from promoted app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	destinationAppRecord, err := porter_app.CreateOrGetAppRecord(ctx, porter_app.CreateOrGetAppRecordInput{
+		ClusterID:           uint(destination.ClusterID),
+		ProjectID:           project.ID,
+		Name:                appName,
+		SourceType:          sourceType,
+		GitBranch:           sourceApp.GitBranch,
+		GitRepoName:         sourceApp.RepoName,
+		GitRepoID:           sourceApp.GitRepoID,
+		PorterYamlPath:      sourceApp.PorterYamlPath,
+		Image:               image,
+		PorterAppRepository: c.Repo().PorterApp(),
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error creating or getting porter app in destination cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if c.Config().ClusterControlPlaneClient == nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(errors.New("empty ClusterControlPlaneClient"), http.StatusInternalServerError))
+		return
+	}
+
+	// the promoted definition replaces the definition in the target, rather than being merged with it
+	ccpResp, err := c.Config().ClusterControlPlaneClient.UpdateApp(ctx, connect.NewRequest(&porterv1.UpdateAppRequest{
+		ProjectId: int64(project.ID),
+		ClusterId: int64(cluster.ID),
+		DeploymentTargetIdentifier: &porterv1.DeploymentTargetIdentifier{
+			Id: destination.ID.String(),
+		},
+		App:                 promoted,
+		IsPredeployEligible: request.WithPredeploy,
+		Exact:               true,
+	}))
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error calling ccp update app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if ccpResp == nil || ccpResp.Msg == nil || ccpResp.Msg.AppRevisionId == "" {
+		err := telemetry.Error(ctx, span, nil, "ccp resp app revision id is empty")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	response.AppRevisionID = ccpResp.Msg.AppRevisionId
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-revision-id", Value: response.AppRevisionID})
+
+	if signatureDecision.Verification != nil {
+		signatureDecision.Verification.AppRevisionID = response.AppRevisionID
+		if _, err := c.Repo().ImageSignature().UpdateImageVerification(ctx, signatureDecision.Verification); err != nil {
+			err := telemetry.Error(ctx, span, err, "error linking image verification to app revision")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	// the revision is created by the cluster control plane, so its app instance may not be readable yet
+	var destinationAppInstanceID uuid.UUID
+	if destinationRevision, err := c.Repo().AppRevision().AppRevisionById(project.ID, response.AppRevisionID); err == nil {
+		destinationAppInstanceID = destinationRevision.AppInstanceID
+	}
+
+	metadata := map[string]any{
+		"source_deployment_target_id":        source.ID.String(),
+		"source_deployment_target_name":      source.VanityName,
+		"destination_deployment_target_id":   destination.ID.String(),
+		"destination_deployment_target_name": destination.VanityName,
+		"source_app_revision_id":             sourceRevision.ID.String(),
+		"source_revision_number":             sourceRevision.RevisionNumber,
+		"app_revision_id":                    response.AppRevisionID,
+		"image_repository":                   response.ImageRepository,
+		"image_tag":                          response.ImageTag,
+		"promoted_by":                        user.Email,
+	}
+
+	events := []*models.PorterAppEvent{
+		promotionEvent(sourceApp.ID, source.ID, sourceRevision.AppInstanceID, metadata),
+		promotionEvent(destinationAppRecord.ID, destination.ID, destinationAppInstanceID, metadata),
+	}
+
+	if err := c.createPromotionEvents(ctx, events); err != nil {
+		err := telemetry.Error(ctx, span, err, "error recording promotion events")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	c.WriteResult(w, r, response)
+}
+
+func (c *PromoteAppHandler) createPromotionEvents(ctx context.Context, events []*models.PorterAppEvent) error {
+	for _, event := range events {
+		if err := c.Repo().PorterAppEvent().CreateEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func promotionEvent(porterAppID uint, deploymentTargetID, appInstanceID uuid.UUID, metadata map[string]any) *models.PorterAppEvent {
+	return &models.PorterAppEvent{
+		ID:                 uuid.New(),
+		Status:             string(types.PorterAppEventStatus_Success),
+		Type:               string(types.PorterAppEventType_Promotion),
+		PorterAppID:        porterAppID,
+		DeploymentTargetID: deploymentTargetID,
+		AppInstanceID:      appInstanceID,
+		Metadata:           metadata,
+	}
+}
+
+func appProtoFromRevision(revision *models.AppRevision) (*porterv1.PorterApp, error) {
+	decoded, err := base64.StdEncoding.DecodeString(revision.Base64App)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &porterv1.PorterApp{}
+	if err := helpers.UnmarshalContractObject(decoded, app); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
@@ -0,0 +1,105 @@
+package porter_app_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/api/server/handlers/porter_app"
+	"github.com/karagatandev/porter/api/server/handlers/project"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apitest"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestPromoteAppRefusesDestinationInAnotherCluster(t *testing.T) {
+	config, req, rr := promoteAppRequest(t, &porter_app.PromoteAppRequest{
+		FromDeploymentTarget: "staging",
+		ToDeploymentTarget:   "production-east",
+	})
+
+	newPromoteAppHandler(config).ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusForbidden, &types.ExternalError{
+		Error: "destination deployment target is not in the cluster of the request",
+	})
+}
+
+func TestPromoteAppRefusesSameDeploymentTarget(t *testing.T) {
+	config, req, rr := promoteAppRequest(t, &porter_app.PromoteAppRequest{
+		FromDeploymentTarget: "production",
+		ToDeploymentTarget:   "production",
+	})
+
+	newPromoteAppHandler(config).ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error: "source and destination deployment targets must be different",
+	})
+}
+
+func TestPromoteAppRefusesUnknownSource(t *testing.T) {
+	config, req, rr := promoteAppRequest(t, &porter_app.PromoteAppRequest{
+		FromDeploymentTarget: "qa",
+		ToDeploymentTarget:   "production",
+	})
+
+	newPromoteAppHandler(config).ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusNotFound, &types.ExternalError{
+		Error: "source deployment target not found",
+	})
+}
+
+func newPromoteAppHandler(config *config.Config) http.Handler {
+	return porter_app.NewPromoteAppHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+}
+
+// promoteAppRequest creates a project with a "staging" and "production" deployment target in cluster 1, and a
+// "production-east" deployment target in cluster 2, and returns a request to promote the app "web" in cluster 1
+func promoteAppRequest(t *testing.T, request *porter_app.PromoteAppRequest) (*config.Config, *http.Request, *httptest.ResponseRecorder) {
+	config := apitest.LoadConfig(t)
+
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name:            "test-project",
+		ValidateApplyV2: true,
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deploymentTargets := []*models.DeploymentTarget{
+		{ID: uuid.New(), ProjectID: int(proj.ID), ClusterID: 1, VanityName: "staging", Selector: "staging", SelectorType: models.DeploymentTargetSelectorType_Namespace},
+		{ID: uuid.New(), ProjectID: int(proj.ID), ClusterID: 1, VanityName: "production", Selector: "production", SelectorType: models.DeploymentTargetSelectorType_Namespace},
+		{ID: uuid.New(), ProjectID: int(proj.ID), ClusterID: 2, VanityName: "production-east", Selector: "production", SelectorType: models.DeploymentTargetSelectorType_Namespace},
+	}
+
+	for _, deploymentTarget := range deploymentTargets {
+		if _, err := config.Repo.DeploymentTarget().CreateDeploymentTarget(deploymentTarget); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1/clusters/1/apps/web/promote", request)
+
+	req = apitest.WithURLParams(t, req, map[string]string{
+		"project_id":      "1",
+		"cluster_id":      "1",
+		"porter_app_name": "web",
+	})
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+	req = apitest.WithCluster(t, req, &models.Cluster{Model: gorm.Model{ID: 1}, ProjectID: proj.ID})
+
+	return config, req, rr
+}
@@ -1,6 +1,7 @@
 package porter_app
 
 import (
+	"fmt"
 	"net/http"
 
 	"connectrpc.com/connect"
@@ -11,6 +12,8 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/server/shared/requestutils"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/deployment_target"
+	"github.com/karagatandev/porter/internal/deploypolicy"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/telemetry"
 	"github.com/pkg/errors"
@@ -40,11 +43,15 @@ type RollbackAppRevisionRequest struct {
 	DeploymentTargetID   string `json:"deployment_target_id"`
 	DeploymentTargetName string `json:"deployment_target_name"`
 	AppRevisionID        string `json:"app_revision_id"`
+	// BreakGlassReason overrides the freeze windows and protection rules of the deployment target, and is recorded with the override
+	BreakGlassReason string `json:"break_glass_reason,omitempty"`
 }
 
 // RollbackAppRevisionResponse is the response body for the /apps/{porter_app_name}/rollback endpoint
 type RollbackAppRevisionResponse struct {
 	TargetRevisionNumber int `json:"target_revision_number"`
+	// Warnings are raised when the rollback overrode the policy of its deployment target
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ServeHTTP handles the request and rolls back the app revision
@@ -54,6 +61,7 @@ func (c *RollbackAppRevisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 
 	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
 	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
 
 	if !project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient) {
 		err := telemetry.Error(ctx, span, nil, "project does not have validate apply v2 enabled")
@@ -113,6 +121,33 @@ func (c *RollbackAppRevisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 		telemetry.AttributeKV{Key: "deployment-target-name", Value: request.DeploymentTargetName},
 	)
 
+	deploymentTargetIdentifier := request.DeploymentTargetID
+	if deploymentTargetIdentifier == "" {
+		deploymentTargetIdentifier = deploymentTargetName
+	}
+
+	apiToken, _ := ctx.Value("api_token").(*models.APIToken)
+
+	policyDecision, err := deployment_target.CheckDeployPolicy(ctx, c.Config(), deployment_target.CheckDeployPolicyInput{
+		ProjectID:                  project.ID,
+		DeploymentTargetIdentifier: deploymentTargetIdentifier,
+		AppName:                    appName,
+		Action:                     deploypolicy.ActionRollback,
+		User:                       user,
+		APIToken:                   apiToken,
+		BreakGlassReason:           request.BreakGlassReason,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error checking deploy policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if policyDecision.Blocked {
+		err := telemetry.Error(ctx, span, nil, "rollback refused by deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), policyDecision.Message), http.StatusForbidden))
+		return
+	}
+
 	rollbackReq := connect.NewRequest(&porterv1.RollbackRevisionRequest{
 		ProjectId: int64(project.ID),
 		AppId:     int64(app.ID),
@@ -146,7 +181,13 @@ func (c *RollbackAppRevisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	c.WriteResult(w, r, &RollbackAppRevisionResponse{
+	res := &RollbackAppRevisionResponse{
 		TargetRevisionNumber: int(ccpResp.Msg.TargetRevisionNumber),
-	})
+	}
+
+	if policyDecision.Message != "" {
+		res.Warnings = append(res.Warnings, policyDecision.Message)
+	}
+
+	c.WriteResult(w, r, res)
 }
@@ -3,6 +3,7 @@ package porter_app
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"net/http"
 
 	"connectrpc.com/connect"
@@ -12,13 +13,19 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/apierrors"
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/deployment_target"
+	"github.com/karagatandev/porter/internal/deploypolicy"
+	"github.com/karagatandev/porter/internal/imagescan"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/porter_app"
 	v2 "github.com/karagatandev/porter/internal/porter_app/v2"
+	"github.com/karagatandev/porter/internal/registry"
 	"github.com/karagatandev/porter/internal/telemetry"
 	"github.com/pkg/errors"
 	"github.com/porter-dev/api-contracts/generated/go/helpers"
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
 )
 
 // UpdateAppHandler is the handler for the POST /apps/update endpoint
@@ -102,12 +109,20 @@ type UpdateAppRequest struct {
 	WithPredeploy bool `json:"with_predeploy"`
 	// Exact is a flag to indicate whether to apply the update exactly as specified in the request (default is to merge with existing app)
 	Exact bool `json:"exact"`
+	// BreakGlassReason overrides the freeze windows and protection rules of the deployment target, and is recorded with the override
+	BreakGlassReason string `json:"break_glass_reason,omitempty"`
 }
 
 // UpdateAppResponse is the response object for the POST /apps/update endpoint
 type UpdateAppResponse struct {
 	AppName       string `json:"app_name"`
 	AppRevisionId string `json:"app_revision_id"`
+	// Warnings are raised when the app's image was warned about by the project's image scan policy, could not be verified
+	// against the project's image trust policy, or the deploy overrode the policy of its deployment target
+	Warnings []string `json:"warnings,omitempty"`
+	// PendingApproval is set when the deployment target requires approval, in which case the revision is held until it
+	// is approved. Revisions which are built are held once their build succeeds.
+	PendingApproval *types.AppRevisionApproval `json:"pending_approval,omitempty"`
 }
 
 // ServeHTTP translates the request into an UpdateApp request, forwards to the cluster control plane, and returns the response
@@ -117,6 +132,7 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
 	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
 
 	request := &UpdateAppRequest{}
 	if ok := c.DecodeAndValidate(w, r, request); !ok {
@@ -254,6 +270,49 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	deploymentTargetIdentifier := deploymentTargetID
+	if deploymentTargetIdentifier == "" {
+		deploymentTargetIdentifier = deploymentTargetName
+	}
+	if deploymentTargetIdentifier == "" {
+		defaultDeploymentTarget, err := defaultDeploymentTarget(ctx, defaultDeploymentTargetInput{
+			ProjectID:                 project.ID,
+			ClusterID:                 cluster.ID,
+			ClusterControlPlaneClient: c.Config().ClusterControlPlaneClient,
+		})
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error getting default deployment target")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		deploymentTargetIdentifier = defaultDeploymentTarget.ID.String()
+	}
+
+	apiToken, _ := ctx.Value("api_token").(*models.APIToken)
+
+	// follow up applies to a revision are checked as well, since they may change the app which is deployed
+	policyDecision, err := deployment_target.CheckDeployPolicy(ctx, c.Config(), deployment_target.CheckDeployPolicyInput{
+		ProjectID:                  project.ID,
+		DeploymentTargetIdentifier: deploymentTargetIdentifier,
+		AppName:                    appProto.Name,
+		Action:                     deploypolicy.ActionApply,
+		User:                       user,
+		APIToken:                   apiToken,
+		GitBranch:                  request.GitSource.GitBranch,
+		GitRepository:              request.GitSource.GitRepoName,
+		BreakGlassReason:           request.BreakGlassReason,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error checking deploy policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if policyDecision.Blocked {
+		err := telemetry.Error(ctx, span, nil, "deploy refused by deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), policyDecision.Message), http.StatusForbidden))
+		return
+	}
+
 	// create porter app if it doesn't exist for the given name
 	_, err = porter_app.CreateOrGetAppRecord(ctx, porter_app.CreateOrGetAppRecordInput{
 		ClusterID:           cluster.ID,
@@ -291,6 +350,43 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		appProto.Image.Tag = request.ImageTagOverride
 	}
 
+	// apps which are built are checked once the build succeeds, while apps which deploy an image are checked here
+	var scanDecision imagescan.Decision
+	var signatureDecision registry.SignatureDecision
+	if appProto.Build == nil && appProto.Image != nil {
+		scanDecision, err = registry.CheckImageScanPolicy(ctx, c.Config(), project.ID, appProto.Image.Repository, appProto.Image.Tag)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error checking image scan policy")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		if scanDecision.Blocked {
+			err := telemetry.Error(ctx, span, nil, "image blocked by image scan policy")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), scanDecision.Message), http.StatusForbidden))
+			return
+		}
+
+		signatureDecision, err = registry.VerifyImageSignature(ctx, c.Config(), registry.VerifyImageSignatureInput{
+			ProjectID:                  project.ID,
+			DeploymentTargetIdentifier: deploymentTargetIdentifier,
+			Repository:                 appProto.Image.Repository,
+			Tag:                        appProto.Image.Tag,
+		})
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error verifying image signature")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		if signatureDecision.Blocked {
+			err := telemetry.Error(ctx, span, nil, "image refused by image trust policy")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), signatureDecision.Message), http.StatusForbidden))
+			return
+		}
+		if signatureDecision.Verification != nil {
+			appProto.Image.Tag = signatureDecision.Verification.PinnedTag()
+		}
+	}
+
 	updateReq := connect.NewRequest(&porterv1.UpdateAppRequest{
 		ProjectId:                  int64(project.ID),
 		ClusterId:                  int64(cluster.ID),
@@ -328,37 +424,156 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ccpResp, err := c.Config().ClusterControlPlaneClient.UpdateApp(ctx, updateReq)
+	appDigest, err := deployment_target.AppDigest(appProto)
 	if err != nil {
-		err := telemetry.Error(ctx, span, err, "error calling ccp update app")
+		err := telemetry.Error(ctx, span, err, "error computing app digest")
 		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 		return
 	}
 
-	if ccpResp == nil {
-		err := telemetry.Error(ctx, span, err, "ccp resp is nil")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-		return
+	holdForApproval := deployment_target.HoldForApprovalInput{
+		ProjectID: project.ID,
+		ClusterID: cluster.ID,
+		AppName:   appProto.Name,
+		Decision:  policyDecision,
+		AppDigest: appDigest,
+		User:      user,
+		APIToken:  apiToken,
 	}
-	if ccpResp.Msg == nil {
-		err := telemetry.Error(ctx, span, err, "ccp resp msg is nil")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-		return
+
+	var appRevisionID string
+	var pendingApproval *models.AppRevisionApproval
+
+	// a follow up apply to a revision which was already held continues it rather than holding it again, but only if it
+	// applies the same app to the same deployment target
+	if policyDecision.RequiresApproval && request.AppRevisionID != "" {
+		heldApproval, err := c.Repo().AppRevisionApproval().ReadAppRevisionApproval(ctx, project.ID, request.AppRevisionID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "error reading app revision approval")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		if heldApproval != nil {
+			if heldApproval.AppDigest != appDigest || heldApproval.DeploymentTargetID != policyDecision.DeploymentTargetID {
+				err := telemetry.Error(ctx, span, nil, "app revision was held for approval with a different app")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+				return
+			}
+
+			switch heldApproval.Status {
+			case models.AppRevisionStatus_Approved:
+				policyDecision.RequiresApproval = false
+			case models.AppRevisionStatus_AwaitingApproval:
+				if heldApproval.Deferred() {
+					c.WriteResult(w, r, &UpdateAppResponse{
+						AppName:         appProto.Name,
+						AppRevisionId:   heldApproval.AppRevisionID,
+						PendingApproval: heldApproval.ToAppRevisionApprovalType(),
+					})
+					return
+				}
+
+				policyDecision.RequiresApproval = false
+				pendingApproval = heldApproval
+			default:
+				err := telemetry.Error(ctx, span, nil, "app revision was not approved")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), heldApproval.Status), http.StatusForbidden))
+				return
+			}
+		}
 	}
 
-	if ccpResp.Msg.AppRevisionId == "" {
-		err := telemetry.Error(ctx, span, err, "ccp resp app revision id is empty")
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
-		return
+	// an apply of an image rolls out as soon as the cluster control plane creates its revision, so it is held here until it
+	// is approved. An apply which is built only rolls out once its build succeeds, so its revision is created and held then.
+	if policyDecision.RequiresApproval && (appProto.Build == nil || request.CommitSHA == "") {
+		holdForApproval.DeferredApply, err = proto.Marshal(updateReq.Msg)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error encoding deferred apply")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		pendingApproval, err = deployment_target.HoldForApproval(ctx, c.Config(), holdForApproval)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error holding apply for approval")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		appRevisionID = pendingApproval.AppRevisionID
+	} else {
+		ccpResp, err := c.Config().ClusterControlPlaneClient.UpdateApp(ctx, updateReq)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error calling ccp update app")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		if ccpResp == nil {
+			err := telemetry.Error(ctx, span, err, "ccp resp is nil")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		if ccpResp.Msg == nil {
+			err := telemetry.Error(ctx, span, err, "ccp resp msg is nil")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		if ccpResp.Msg.AppRevisionId == "" {
+			err := telemetry.Error(ctx, span, err, "ccp resp app revision id is empty")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		appRevisionID = ccpResp.Msg.AppRevisionId
+
+		if policyDecision.RequiresApproval {
+			holdForApproval.AppRevisionID = appRevisionID
+
+			pendingApproval, err = deployment_target.HoldForApproval(ctx, c.Config(), holdForApproval)
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "error holding revision for approval")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
 	}
 
-	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "resp-app-revision-id", Value: ccpResp.Msg.AppRevisionId})
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "resp-app-revision-id", Value: appRevisionID},
+		telemetry.AttributeKV{Key: "pending-approval", Value: pendingApproval != nil},
+	)
 
 	response := &UpdateAppResponse{
-		AppRevisionId: ccpResp.Msg.AppRevisionId,
+		AppRevisionId: appRevisionID,
 		AppName:       appProto.Name,
 	}
 
+	if pendingApproval != nil {
+		response.PendingApproval = pendingApproval.ToAppRevisionApprovalType()
+	}
+
+	if policyDecision.Message != "" {
+		response.Warnings = append(response.Warnings, policyDecision.Message)
+	}
+	if scanDecision.Message != "" {
+		response.Warnings = append(response.Warnings, scanDecision.Message)
+	}
+	if signatureDecision.Message != "" {
+		response.Warnings = append(response.Warnings, signatureDecision.Message)
+	}
+
+	if signatureDecision.Verification != nil {
+		signatureDecision.Verification.AppRevisionID = appRevisionID
+		if _, err := c.Repo().ImageSignature().UpdateImageVerification(ctx, signatureDecision.Verification); err != nil {
+			err := telemetry.Error(ctx, span, err, "error linking image verification to app revision")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
 	c.WriteResult(w, r, response)
 }
 
@@ -1,7 +1,10 @@
 package porter_app
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/karagatandev/porter/api/server/handlers"
@@ -10,10 +13,15 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/server/shared/requestutils"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/deployment_target"
+	"github.com/karagatandev/porter/internal/imagescan"
 	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/registry"
 	"github.com/karagatandev/porter/internal/telemetry"
 	"github.com/pkg/errors"
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
 )
 
 // UpdateAppRevisionStatusHandler handles requests to the /apps/{porter_app_name}/revisions/{app_revision_id} endpoint
@@ -34,12 +42,23 @@ func NewUpdateAppRevisionStatusHandler(
 
 // UpdateAppRevisionStatusRequest is the request object for the /apps/{porter_app_name}/revisions/{app_revision_id} endpoint
 type UpdateAppRevisionStatusRequest struct {
-	// Status is the new status to set for the app revision
+	// Status is the new status to set for the app revision. Revisions held for approval are approved or rejected by
+	// setting it to APPROVED or APPROVAL_REJECTED.
 	Status models.AppRevisionStatus `json:"status"`
+	// Reason is recorded when a revision is approved or rejected
+	Reason string `json:"reason,omitempty"`
 }
 
 // UpdateAppRevisionStatusResponse is the response object for the /apps/{porter_app_name}/revisions/{app_revision_id} endpoint
-type UpdateAppRevisionStatusResponse struct{}
+type UpdateAppRevisionStatusResponse struct {
+	// Warnings are raised by the project's image scan policy for the revision's image
+	Warnings []string `json:"warnings,omitempty"`
+	// PendingApproval is set when the build of a revision which is held for approval succeeded. The revision rolls
+	// out once it is approved.
+	PendingApproval *types.AppRevisionApproval `json:"pending_approval,omitempty"`
+	// Approval is set when the revision was approved or rejected
+	Approval *types.AppRevisionApproval `json:"approval,omitempty"`
+}
 
 // UpdateAppRevisionStatus updates the status of an app revision
 func (c *UpdateAppRevisionStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -47,6 +66,7 @@ func (c *UpdateAppRevisionStatusHandler) ServeHTTP(w http.ResponseWriter, r *htt
 	defer span.End()
 
 	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
 
 	// read the request object from the decoder
 	request := &UpdateAppRevisionStatusRequest{}
@@ -75,6 +95,35 @@ func (c *UpdateAppRevisionStatusHandler) ServeHTTP(w http.ResponseWriter, r *htt
 		return
 	}
 
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-revision-id", Value: appRevisionId},
+		telemetry.AttributeKV{Key: "status", Value: string(request.Status)},
+	)
+
+	if c.Config().ClusterControlPlaneClient == nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(errors.New("empty ClusterControlPlaneClient"), http.StatusInternalServerError))
+		return
+	}
+
+	if request.Status == models.AppRevisionStatus_Approved || request.Status == models.AppRevisionStatus_ApprovalRejected {
+		appName, _ := requestutils.GetURLParamString(r, types.URLParamPorterAppName)
+
+		res, reqErr := c.decideApproval(ctx, decideApprovalInput{
+			projectID:     project.ID,
+			clusterID:     cluster.ID,
+			appName:       appName,
+			appRevisionID: appRevisionId,
+			request:       request,
+		})
+		if reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+
+		c.WriteResult(w, r, res)
+		return
+	}
+
 	var statusProto porterv1.EnumRevisionStatus
 	switch request.Status {
 	case models.AppRevisionStatus_BuildFailed:
@@ -97,9 +146,92 @@ func (c *UpdateAppRevisionStatusHandler) ServeHTTP(w http.ResponseWriter, r *htt
 		RevisionStatus: statusProto,
 	})
 
-	if c.Config().ClusterControlPlaneClient == nil {
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(errors.New("empty ClusterControlPlaneClient"), http.StatusInternalServerError))
-		return
+	res := &UpdateAppRevisionStatusResponse{}
+
+	// a successful build deploys the revision, so the image it built is checked against the project's
+	// image scan and image trust policies first
+	if request.Status == models.AppRevisionStatus_BuildSuccessful {
+		checks, err := c.checkImage(ctx, project.ID, appRevisionId)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error checking image")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		if checks.scan.Blocked || checks.signature.Blocked {
+			updateStatusReq.Msg.RevisionStatus = porterv1.EnumRevisionStatus_ENUM_REVISION_STATUS_BUILD_FAILED
+			if _, err := c.Config().ClusterControlPlaneClient.UpdateRevisionStatus(ctx, updateStatusReq); err != nil {
+				err := telemetry.Error(ctx, span, err, "error updating revision status")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+
+			if checks.scan.Blocked {
+				err := telemetry.Error(ctx, span, nil, "image blocked by image scan policy")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), checks.scan.Message), http.StatusForbidden))
+				return
+			}
+
+			err := telemetry.Error(ctx, span, nil, "image refused by image trust policy")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), checks.signature.Message), http.StatusForbidden))
+			return
+		}
+
+		if checks.scan.Message != "" {
+			res.Warnings = append(res.Warnings, checks.scan.Message)
+		}
+		if checks.signature.Message != "" {
+			res.Warnings = append(res.Warnings, checks.signature.Message)
+		}
+
+		// the revision was created before the image was built, so the verified digest is recorded rather than pinned in the revision
+		if checks.signature.Verification != nil {
+			checks.signature.Verification.AppRevisionID = appRevisionId
+			if _, err := c.Repo().ImageSignature().UpdateImageVerification(ctx, checks.signature.Verification); err != nil {
+				err := telemetry.Error(ctx, span, err, "error linking image verification to app revision")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+
+		// revisions held for approval roll out once they are both built and approved
+		approval, err := c.Repo().AppRevisionApproval().ReadAppRevisionApproval(ctx, project.ID, appRevisionId)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "error reading app revision approval")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		if err == nil {
+			if approval.Status == models.AppRevisionStatus_AwaitingApproval && time.Now().After(approval.ExpiresAt) {
+				if err := c.expireApproval(ctx, approval); err != nil {
+					err := telemetry.Error(ctx, span, err, "error expiring app revision approval")
+					c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+					return
+				}
+			}
+
+			switch approval.Status {
+			case models.AppRevisionStatus_AwaitingApproval:
+				approval.BuildSucceeded = true
+
+				approval, err = c.Repo().AppRevisionApproval().UpdateAppRevisionApproval(ctx, approval)
+				if err != nil {
+					err := telemetry.Error(ctx, span, err, "error updating app revision approval")
+					c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+					return
+				}
+
+				res.PendingApproval = approval.ToAppRevisionApprovalType()
+
+				c.WriteResult(w, r, res)
+				return
+			case models.AppRevisionStatus_ApprovalRejected, models.AppRevisionStatus_ApprovalExpired:
+				err := telemetry.Error(ctx, span, nil, "revision was not approved")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), approval.Status), http.StatusForbidden))
+				return
+			}
+		}
 	}
 
 	_, err := c.Config().ClusterControlPlaneClient.UpdateRevisionStatus(ctx, updateStatusReq)
@@ -109,6 +241,235 @@ func (c *UpdateAppRevisionStatusHandler) ServeHTTP(w http.ResponseWriter, r *htt
 		return
 	}
 
-	res := &UpdateAppRevisionStatusResponse{}
 	c.WriteResult(w, r, res)
 }
+
+// decideApprovalInput is the input to decideApproval
+type decideApprovalInput struct {
+	projectID uint
+	// clusterID and appName are the cluster and app in the url, which the revision must belong to
+	clusterID     uint
+	appName       string
+	appRevisionID string
+	request       *UpdateAppRevisionStatusRequest
+}
+
+// decideApproval approves or rejects a revision held for approval. Approved revisions roll out if they have been built,
+// or are built, and rejected revisions are marked as failed.
+func (c *UpdateAppRevisionStatusHandler) decideApproval(
+	ctx context.Context,
+	inp decideApprovalInput,
+) (*UpdateAppRevisionStatusResponse, apierrors.RequestError) {
+	ctx, span := telemetry.NewSpan(ctx, "decide-approval")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	apiToken, _ := ctx.Value("api_token").(*models.APIToken)
+	request := inp.request
+
+	approval, err := c.Repo().AppRevisionApproval().ReadAppRevisionApproval(ctx, inp.projectID, inp.appRevisionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, nil, "revision is not held for approval")
+			return nil, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound)
+		}
+
+		err := telemetry.Error(ctx, span, err, "error reading app revision approval")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+	}
+
+	// the permissions of the request were checked against the app and cluster in the url, so a revision of
+	// another app or cluster is treated as though it does not exist
+	if approval.AppName != inp.appName || approval.ClusterID != inp.clusterID {
+		err := telemetry.Error(ctx, span, nil, "revision is not held for approval")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound)
+	}
+
+	refusal, err := deployment_target.CheckApprover(ctx, c.Config(), deployment_target.CheckApproverInput{
+		Approval: approval,
+		Status:   request.Status,
+		User:     user,
+		APIToken: apiToken,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error checking approver")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+	}
+	if refusal != "" {
+		err := telemetry.Error(ctx, span, nil, "approval refused")
+		return nil, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), refusal), http.StatusForbidden)
+	}
+
+	if approval.Status == models.AppRevisionStatus_AwaitingApproval && time.Now().After(approval.ExpiresAt) {
+		if err := c.expireApproval(ctx, approval); err != nil {
+			err := telemetry.Error(ctx, span, err, "error expiring app revision approval")
+			return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+		}
+	}
+
+	if !approval.Status.CanTransitionApproval(request.Status) {
+		err := telemetry.Error(ctx, span, nil, "revision was already decided")
+		return nil, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), approval.Status), http.StatusConflict)
+	}
+
+	switch {
+	case request.Status == models.AppRevisionStatus_Approved && approval.Deferred():
+		updateReq := &porterv1.UpdateAppRequest{}
+		if err := proto.Unmarshal(approval.DeferredApply, updateReq); err != nil {
+			err := telemetry.Error(ctx, span, err, "error decoding deferred apply")
+			return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+		}
+
+		ccpResp, err := c.Config().ClusterControlPlaneClient.UpdateApp(ctx, connect.NewRequest(updateReq))
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error calling ccp update app")
+			return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+		}
+		if ccpResp == nil || ccpResp.Msg == nil || ccpResp.Msg.AppRevisionId == "" {
+			err := telemetry.Error(ctx, span, nil, "ccp resp app revision id is empty")
+			return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+		}
+
+		approval.DeployedAppRevisionID = ccpResp.Msg.AppRevisionId
+
+		// the image was verified when the apply was held, so the verification moves to the revision which deploys it
+		verification, err := c.Repo().ImageSignature().ReadImageVerificationByAppRevisionID(ctx, inp.projectID, approval.AppRevisionID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "error reading image verification")
+			return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+		}
+		if err == nil {
+			verification.AppRevisionID = approval.DeployedAppRevisionID
+			if _, err := c.Repo().ImageSignature().UpdateImageVerification(ctx, verification); err != nil {
+				err := telemetry.Error(ctx, span, err, "error linking image verification to app revision")
+				return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+			}
+		}
+	case request.Status == models.AppRevisionStatus_Approved && approval.BuildSucceeded:
+		_, err := c.Config().ClusterControlPlaneClient.UpdateRevisionStatus(ctx, connect.NewRequest(&porterv1.UpdateRevisionStatusRequest{
+			ProjectId:      int64(inp.projectID),
+			AppRevisionId:  approval.AppRevisionID,
+			RevisionStatus: porterv1.EnumRevisionStatus_ENUM_REVISION_STATUS_BUILD_SUCCESSFUL,
+		}))
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error updating revision status")
+			return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+		}
+	case request.Status == models.AppRevisionStatus_ApprovalRejected && !approval.Deferred():
+		if err := c.failHeldRevision(ctx, approval); err != nil {
+			err := telemetry.Error(ctx, span, err, "error failing rejected revision")
+			return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+		}
+	}
+
+	now := time.Now().UTC()
+
+	approval.Status = request.Status
+	approval.Reason = request.Reason
+	approval.DecidedAt = &now
+	approval.DeferredApply = nil
+
+	if apiToken != nil {
+		approval.DecidedByAPITokenID = apiToken.UniqueID
+	} else if user != nil {
+		approval.DecidedByUserID = user.ID
+	}
+
+	approval, err = c.Repo().AppRevisionApproval().UpdateAppRevisionApproval(ctx, approval)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error updating app revision approval")
+		return nil, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError)
+	}
+
+	if err := deployment_target.NotifyApproval(ctx, c.Repo(), deployment_target.NotifyApprovalInput{
+		Approval:  approval,
+		ServerURL: c.Config().ServerConf.ServerURL,
+		CCPClient: c.Config().ClusterControlPlaneClient,
+	}); err != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "notify-error", Value: err.Error()})
+	}
+
+	return &UpdateAppRevisionStatusResponse{Approval: approval.ToAppRevisionApprovalType()}, nil
+}
+
+// expireApproval expires a revision which was not approved in time
+func (c *UpdateAppRevisionStatusHandler) expireApproval(ctx context.Context, approval *models.AppRevisionApproval) error {
+	if !approval.Deferred() {
+		if err := c.failHeldRevision(ctx, approval); err != nil {
+			return err
+		}
+	}
+
+	approval.Status = models.AppRevisionStatus_ApprovalExpired
+	approval.DeferredApply = nil
+
+	if _, err := c.Repo().AppRevisionApproval().UpdateAppRevisionApproval(ctx, approval); err != nil {
+		return fmt.Errorf("error updating app revision approval: %w", err)
+	}
+
+	_ = deployment_target.NotifyApproval(ctx, c.Repo(), deployment_target.NotifyApprovalInput{
+		Approval:  approval,
+		ServerURL: c.Config().ServerConf.ServerURL,
+		CCPClient: c.Config().ClusterControlPlaneClient,
+	})
+
+	return nil
+}
+
+// failHeldRevision marks a revision which was created by the cluster control plane, but will never be approved, as failed
+func (c *UpdateAppRevisionStatusHandler) failHeldRevision(ctx context.Context, approval *models.AppRevisionApproval) error {
+	_, err := c.Config().ClusterControlPlaneClient.UpdateRevisionStatus(ctx, connect.NewRequest(&porterv1.UpdateRevisionStatusRequest{
+		ProjectId:      int64(approval.ProjectID),
+		AppRevisionId:  approval.AppRevisionID,
+		RevisionStatus: porterv1.EnumRevisionStatus_ENUM_REVISION_STATUS_DEPLOY_FAILED,
+	}))
+	if err != nil {
+		return fmt.Errorf("error updating revision status: %w", err)
+	}
+
+	return nil
+}
+
+// imageChecks are the outcomes of checking a revision's image against the project's policies
+type imageChecks struct {
+	scan      imagescan.Decision
+	signature registry.SignatureDecision
+}
+
+func (c *UpdateAppRevisionStatusHandler) checkImage(ctx context.Context, projectID uint, appRevisionID string) (imageChecks, error) {
+	var checks imageChecks
+
+	ccpResp, err := c.Config().ClusterControlPlaneClient.GetAppRevision(ctx, connect.NewRequest(&porterv1.GetAppRevisionRequest{
+		ProjectId:     int64(projectID),
+		AppRevisionId: appRevisionID,
+	}))
+	if err != nil {
+		return checks, fmt.Errorf("error getting app revision: %w", err)
+	}
+
+	if ccpResp == nil || ccpResp.Msg == nil {
+		return checks, errors.New("get app revision response is nil")
+	}
+
+	image := ccpResp.Msg.AppRevision.GetApp().GetImage()
+	if image == nil || image.Repository == "" {
+		return checks, nil
+	}
+
+	checks.scan, err = registry.CheckImageScanPolicy(ctx, c.Config(), projectID, image.Repository, image.Tag)
+	if err != nil {
+		return checks, err
+	}
+
+	checks.signature, err = registry.VerifyImageSignature(ctx, c.Config(), registry.VerifyImageSignatureInput{
+		ProjectID:                  projectID,
+		DeploymentTargetIdentifier: ccpResp.Msg.AppRevision.DeploymentTargetId,
+		Repository:                 image.Repository,
+		Tag:                        image.Tag,
+	})
+	if err != nil {
+		return checks, err
+	}
+
+	return checks, nil
+}
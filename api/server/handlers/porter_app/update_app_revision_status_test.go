@@ -0,0 +1,130 @@
+package porter_app_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/karagatandev/porter/api/server/handlers/porter_app"
+	"github.com/karagatandev/porter/api/server/handlers/project"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apitest"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"gorm.io/gorm"
+)
+
+func TestUpdateAppRevisionStatusRefusesApprovalOfAnotherApp(t *testing.T) {
+	config, req, rr := approveAppRevisionRequest(t, &models.AppRevisionApproval{
+		ClusterID:     1,
+		AppName:       "worker",
+		AppRevisionID: "revision-1",
+		Status:        models.AppRevisionStatus_AwaitingApproval,
+	})
+
+	newUpdateAppRevisionStatusHandler(config).ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusNotFound, &types.ExternalError{
+		Error: "revision is not held for approval",
+	})
+}
+
+func TestUpdateAppRevisionStatusRefusesApprovalInAnotherCluster(t *testing.T) {
+	config, req, rr := approveAppRevisionRequest(t, &models.AppRevisionApproval{
+		ClusterID:     2,
+		AppName:       "web",
+		AppRevisionID: "revision-1",
+		Status:        models.AppRevisionStatus_AwaitingApproval,
+	})
+
+	newUpdateAppRevisionStatusHandler(config).ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusNotFound, &types.ExternalError{
+		Error: "revision is not held for approval",
+	})
+}
+
+func newUpdateAppRevisionStatusHandler(config *config.Config) http.Handler {
+	return porter_app.NewUpdateAppRevisionStatusHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	)
+}
+
+// approveAppRevisionRequest stores the approval in the project's approvals, and returns a request to approve the
+// revision "revision-1" of the app "web" in cluster 1
+func approveAppRevisionRequest(t *testing.T, approval *models.AppRevisionApproval) (*config.Config, *http.Request, *httptest.ResponseRecorder) {
+	config := apitest.LoadConfig(t)
+
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name:            "test-project",
+		ValidateApplyV2: true,
+	}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	approval.ProjectID = proj.ID
+
+	config.Repo = &approvalRepositoryOverride{
+		Repository: config.Repo,
+		approvals:  &approvalRepository{approvals: map[string]*models.AppRevisionApproval{approval.AppRevisionID: approval}},
+	}
+	// approvals are decided before the cluster control plane is called
+	config.ClusterControlPlaneClient = unusedClusterControlPlaneClient{}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/apps/web/revisions/revision-1",
+		&porter_app.UpdateAppRevisionStatusRequest{Status: models.AppRevisionStatus_Approved},
+	)
+
+	req = apitest.WithURLParams(t, req, map[string]string{
+		"project_id":      "1",
+		"cluster_id":      "1",
+		"porter_app_name": "web",
+		"app_revision_id": "revision-1",
+	})
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+	req = apitest.WithCluster(t, req, &models.Cluster{Model: gorm.Model{ID: 1}, ProjectID: proj.ID})
+
+	return config, req, rr
+}
+
+type approvalRepositoryOverride struct {
+	repository.Repository
+
+	approvals repository.AppRevisionApprovalRepository
+}
+
+func (r *approvalRepositoryOverride) AppRevisionApproval() repository.AppRevisionApprovalRepository {
+	return r.approvals
+}
+
+type approvalRepository struct {
+	repository.AppRevisionApprovalRepository
+
+	approvals map[string]*models.AppRevisionApproval
+}
+
+func (r *approvalRepository) ReadAppRevisionApproval(ctx context.Context, projectID uint, appRevisionID string) (*models.AppRevisionApproval, error) {
+	approval, ok := r.approvals[appRevisionID]
+	if !ok || approval.ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return approval, nil
+}
+
+type unusedClusterControlPlaneClient struct {
+	porterv1connect.ClusterControlPlaneServiceClient
+}
@@ -1,6 +1,7 @@
 package porter_app
 
 import (
+	"fmt"
 	"net/http"
 
 	"connectrpc.com/connect"
@@ -11,7 +12,10 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/server/shared/requestutils"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/deployment_target"
+	"github.com/karagatandev/porter/internal/deploypolicy"
 	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/registry"
 	"github.com/karagatandev/porter/internal/telemetry"
 	"github.com/pkg/errors"
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
@@ -41,6 +45,8 @@ type UpdateImageRequest struct {
 	DeploymentTargetName string `json:"deployment_target_name"`
 	Repository           string `json:"repository"`
 	Tag                  string `json:"tag"`
+	// BreakGlassReason overrides the freeze windows and protection rules of the deployment target, and is recorded with the override
+	BreakGlassReason string `json:"break_glass_reason,omitempty"`
 }
 
 // UpdateImageResponse is the response object for the /apps/{porter_app_name}/update-image endpoint
@@ -48,6 +54,9 @@ type UpdateImageResponse struct {
 	Repository string `json:"repository"`
 	Tag        string `json:"tag"`
 	RevisionID string `json:"revision_id"`
+	// Warnings are raised by the project's image scan policy for the new image, or when the update overrode the policy of
+	// its deployment target
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 func (c *UpdateImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -56,6 +65,7 @@ func (c *UpdateImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
 	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+	user, _ := ctx.Value(types.UserScope).(*models.User)
 
 	if !project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient) {
 		err := telemetry.Error(ctx, span, nil, "project does not have validate apply v2 enabled")
@@ -108,10 +118,73 @@ func (c *UpdateImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		telemetry.AttributeKV{Key: "deployment-target-name", Value: request.DeploymentTargetName},
 	)
 
+	deploymentTargetIdentifier := request.DeploymentTargetID
+	if deploymentTargetIdentifier == "" {
+		deploymentTargetIdentifier = deploymentTargetName
+	}
+
+	apiToken, _ := ctx.Value("api_token").(*models.APIToken)
+
+	// the branch and repository an image was built from aren't known, so targets which restrict them
+	// only accept image updates which break the glass
+	policyDecision, err := deployment_target.CheckDeployPolicy(ctx, c.Config(), deployment_target.CheckDeployPolicyInput{
+		ProjectID:                  project.ID,
+		DeploymentTargetIdentifier: deploymentTargetIdentifier,
+		AppName:                    appName,
+		Action:                     deploypolicy.ActionUpdateImage,
+		User:                       user,
+		APIToken:                   apiToken,
+		BreakGlassReason:           request.BreakGlassReason,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error checking deploy policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if policyDecision.Blocked {
+		err := telemetry.Error(ctx, span, nil, "image update refused by deployment target policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), policyDecision.Message), http.StatusForbidden))
+		return
+	}
+
+	decision, err := registry.CheckImageScanPolicy(ctx, c.Config(), project.ID, request.Repository, request.Tag)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error checking image scan policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if decision.Blocked {
+		err := telemetry.Error(ctx, span, nil, "image blocked by image scan policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), decision.Message), http.StatusForbidden))
+		return
+	}
+
+	signatureDecision, err := registry.VerifyImageSignature(ctx, c.Config(), registry.VerifyImageSignatureInput{
+		ProjectID:                  project.ID,
+		DeploymentTargetIdentifier: deploymentTargetIdentifier,
+		Repository:                 request.Repository,
+		Tag:                        request.Tag,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error verifying image signature")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if signatureDecision.Blocked {
+		err := telemetry.Error(ctx, span, nil, "image refused by image trust policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("%s: %s", err.Error(), signatureDecision.Message), http.StatusForbidden))
+		return
+	}
+
+	tag := request.Tag
+	if signatureDecision.Verification != nil {
+		tag = signatureDecision.Verification.PinnedTag()
+	}
+
 	updateImageReq := connect.NewRequest(&porterv1.UpdateAppImageRequest{
 		ProjectId:     int64(project.ID),
 		RepositoryUrl: request.Repository,
-		Tag:           request.Tag,
+		Tag:           tag,
 		AppName:       appName,
 		DeploymentTargetIdentifier: &porterv1.DeploymentTargetIdentifier{
 			Id:   request.DeploymentTargetID,
@@ -131,5 +204,24 @@ func (c *UpdateImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		RevisionID: ccpResp.Msg.RevisionId,
 	}
 
+	if policyDecision.Message != "" {
+		res.Warnings = append(res.Warnings, policyDecision.Message)
+	}
+	if decision.Message != "" {
+		res.Warnings = append(res.Warnings, decision.Message)
+	}
+	if signatureDecision.Message != "" {
+		res.Warnings = append(res.Warnings, signatureDecision.Message)
+	}
+
+	if signatureDecision.Verification != nil {
+		signatureDecision.Verification.AppRevisionID = ccpResp.Msg.RevisionId
+		if _, err := c.Repo().ImageSignature().UpdateImageVerification(ctx, signatureDecision.Verification); err != nil {
+			err := telemetry.Error(ctx, span, err, "error linking image verification to app revision")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
 	c.WriteResult(w, r, res)
 }
@@ -1,8 +1,10 @@
 package project
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/karagatandev/porter/api/server/authz/policy"
 	"github.com/karagatandev/porter/api/server/handlers"
 	"github.com/karagatandev/porter/api/server/shared"
 	"github.com/karagatandev/porter/api/server/shared/apierrors"
@@ -43,6 +45,25 @@ func (p *RoleUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	role.Kind = types.RoleKind(request.Kind)
+	role.PolicyUID = ""
+
+	if role.Kind == types.RoleCustom {
+		if request.PolicyUID == "" {
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("policy_uid is required for custom roles"),
+				http.StatusBadRequest,
+			))
+			return
+		}
+
+		// make sure that the policy exists in the project
+		if _, reqErr := policy.GetAPIPolicyFromUID(p.Repo().Policy(), proj.ID, request.PolicyUID); reqErr != nil {
+			p.HandleAPIError(w, r, reqErr)
+			return
+		}
+
+		role.PolicyUID = request.PolicyUID
+	}
 
 	role, err = p.Repo().Project().UpdateProjectRole(proj.ID, role)
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/karagatandev/porter/api/server/authz"
 	"github.com/karagatandev/porter/api/server/handlers"
 	"github.com/karagatandev/porter/api/server/shared"
 	"github.com/karagatandev/porter/api/server/shared/apierrors"
@@ -68,6 +69,10 @@ func (p *RegistryCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	if request.GithubAppInstallationID != 0 {
+		idCount += 1
+	}
+
 	if idCount > 1 {
 		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
 			fmt.Errorf("only one integration ID should be set"), http.StatusBadRequest,
@@ -80,6 +85,41 @@ func (p *RegistryCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	switch types.RegistryService(request.Service) {
+	case types.Harbor, types.Quay:
+		if request.BasicIntegrationID == 0 {
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("basic_integration_id must be set for %s registries", request.Service),
+				http.StatusBadRequest,
+			))
+			return
+		}
+	case types.GHCR:
+		if request.GithubAppInstallationID == 0 {
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("github_app_installation_id must be set for ghcr registries"),
+				http.StatusBadRequest,
+			))
+			return
+		}
+	default:
+		if request.GithubAppInstallationID != 0 {
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("service must be ghcr if github_app_installation_id is set"),
+				http.StatusBadRequest,
+			))
+			return
+		}
+	}
+
+	if request.Service != "" && request.URL == "" {
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("url must be set for %s registries", request.Service),
+			http.StatusBadRequest,
+		))
+		return
+	}
+
 	var err error
 
 	if request.GCPIntegrationID != 0 {
@@ -156,6 +196,32 @@ func (p *RegistryCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 			return
 		}
+	} else if request.GithubAppInstallationID != 0 {
+		_, err = p.Repo().GithubAppInstallation().ReadGithubAppInstallationByInstallationID(uint(request.GithubAppInstallationID))
+
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+					fmt.Errorf("no such GitHub App installation ID: %d", request.GithubAppInstallationID),
+					http.StatusNotFound,
+				))
+				return
+			}
+
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		// the installation grants access to the organization's packages, so the user must have access to it
+		err = authz.DoesUserHaveGitInstallationAccess(r.Context(), p.Config(), user.GithubAppIntegrationID, uint(request.GithubAppInstallationID))
+
+		if err != nil {
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("user does not have access to GitHub App installation ID: %d", request.GithubAppInstallationID),
+				http.StatusForbidden,
+			))
+			return
+		}
 	}
 
 	// create a registry model
@@ -168,6 +234,9 @@ func (p *RegistryCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		DOIntegrationID:    request.DOIntegrationID,
 		BasicIntegrationID: request.BasicIntegrationID,
 		AzureIntegrationID: request.AzureIntegrationID,
+
+		Service:                 request.Service,
+		GithubAppInstallationID: request.GithubAppInstallationID,
 	}
 
 	if regModel.URL == "" && regModel.AWSIntegrationID != 0 {
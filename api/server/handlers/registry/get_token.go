@@ -606,3 +606,100 @@ func (c *RegistryGetACRTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 
 	c.WriteResult(w, r, resp)
 }
+
+type RegistryGetTokenHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewRegistryGetTokenHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RegistryGetTokenHandler {
+	return &RegistryGetTokenHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP returns a docker auth token for the Harbor, Quay or GHCR registry hosted on the requested server
+func (c *RegistryGetTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-registry-get-token")
+	defer span.End()
+
+	proj, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.GetRegistryTokenRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	serverURL := strings.TrimSuffix(strings.TrimPrefix(request.ServerURL, "https://"), "/")
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: proj.ID},
+		telemetry.AttributeKV{Key: "server-url", Value: serverURL},
+	)
+
+	regs, err := c.Repo().Registry().ListRegistriesByProjectID(proj.ID)
+	if err != nil {
+		e := telemetry.Error(ctx, span, err, "error listing registries by project id")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(e))
+		return
+	}
+
+	var token string
+	var expiresAt time.Time
+
+	for _, reg := range regs {
+		regHost, _, _ := strings.Cut(strings.TrimPrefix(reg.URL, "https://"), "/")
+
+		if reg.Service == "" || regHost != serverURL {
+			continue
+		}
+
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "registry-id", Value: reg.ID})
+
+		switch types.RegistryService(reg.Service) {
+		case types.Harbor, types.Quay:
+			basic, err := c.Repo().BasicIntegration().ReadBasicIntegration(reg.ProjectID, reg.BasicIntegrationID)
+			if err != nil {
+				e := telemetry.Error(ctx, span, err, "error reading basic integration")
+				c.HandleAPIError(w, r, apierrors.NewErrInternal(e))
+				return
+			}
+
+			token = base64.StdEncoding.EncodeToString([]byte(string(basic.Username) + ":" + string(basic.Password)))
+
+			// robot accounts don't expire with the token, so this is an arbitrary 30-day expiry
+			expiresAt = time.Now().Add(30 * 24 * time.Hour)
+		case types.GHCR:
+			_reg := registry.Registry(*reg)
+
+			installationToken, expiry, err := _reg.RefreshGHCRToken(ctx, c.Repo(), c.Config().GithubAppConf)
+			if err != nil {
+				e := telemetry.Error(ctx, span, err, "error getting ghcr installation token")
+				c.HandleAPIError(w, r, apierrors.NewErrInternal(e))
+				return
+			}
+
+			token = base64.StdEncoding.EncodeToString([]byte("x-access-token:" + installationToken))
+			expiresAt = expiry
+		}
+
+		break
+	}
+
+	if token == "" {
+		e := telemetry.Error(ctx, span, nil, "no harbor, quay or ghcr registry found for server url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(e, http.StatusNotFound))
+		return
+	}
+
+	resp := &types.GetRegistryTokenResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}
+
+	c.WriteResult(w, r, resp)
+}
@@ -48,5 +48,29 @@ func (c *RegistryListImagesHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	digests := make([]string, 0, len(imgs))
+	for _, img := range imgs {
+		if img.Digest != "" {
+			digests = append(digests, img.Digest)
+		}
+	}
+
+	scans, err := c.Repo().ImageScan().ListImageScansByDigests(ctx, reg.ProjectID, digests)
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(fmt.Errorf("error listing image scans: %w", err)))
+		return
+	}
+
+	scansByDigest := make(map[string]*models.ImageScan, len(scans))
+	for _, scan := range scans {
+		scansByDigest[scan.Digest] = scan
+	}
+
+	for _, img := range imgs {
+		if scan, ok := scansByDigest[img.Digest]; ok {
+			img.Vulnerabilities = scan.ToImageScanSummaryType()
+		}
+	}
+
 	c.WriteResult(w, r, imgs)
 }
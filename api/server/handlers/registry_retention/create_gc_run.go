@@ -0,0 +1,103 @@
+package registry_retention
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/registry"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// CreateGCRunHandler plans a garbage collection run of a registry repository with the repository's
+// retention policy. The run is a dry run: nothing is deleted until the run is executed.
+type CreateGCRunHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateGCRunHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateGCRunHandler {
+	return &CreateGCRunHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *CreateGCRunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-registry-gc-run")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	request := &types.CreateRegistryGCRunRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "repository", Value: request.Repository})
+
+	policy, err := c.Repo().RegistryRetention().ReadRegistryRetentionPolicy(ctx, reg.ProjectID, reg.ID, request.Repository)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, fmt.Errorf("repository %s has no retention policy", request.Repository), "retention policy not found")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading retention policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	_reg := registry.Registry(*reg)
+
+	plan, err := _reg.PlanRetention(ctx, request.Repository, policy.Rules(), c.Repo(), c.Config().DOConf)
+	if err != nil {
+		if errors.Is(err, registry.ErrRetentionNotSupported) || errors.Is(err, registry.ErrLegacyAppRepository) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error planning garbage collection")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	run, err := models.NewRegistryGCRun(policy, plan)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error encoding garbage collection plan")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	run, err = c.Repo().RegistryRetention().CreateRegistryGCRun(ctx, run)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating garbage collection run")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "gc-run-id", Value: run.ID},
+		telemetry.AttributeKV{Key: "kept", Value: len(plan.Keep)},
+		telemetry.AttributeKV{Key: "deleted", Value: len(plan.Delete)},
+	)
+
+	res, err := run.ToRegistryGCRunType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding garbage collection run")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,63 @@
+package registry_retention
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeletePolicyHandler deletes the retention policy of a registry repository
+type DeletePolicyHandler struct {
+	handlers.PorterHandlerReader
+}
+
+func NewDeletePolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+) *DeletePolicyHandler {
+	return &DeletePolicyHandler{
+		PorterHandlerReader: handlers.NewDefaultPorterHandler(config, decoderValidator, nil),
+	}
+}
+
+func (c *DeletePolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-registry-retention-policy")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	request := &types.DeleteRegistryRetentionPolicyRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "repository", Value: request.Repository})
+
+	policy, err := c.Repo().RegistryRetention().ReadRegistryRetentionPolicy(ctx, reg.ProjectID, reg.ID, request.Repository)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(telemetry.Error(ctx, span, err, "retention policy not found")))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading retention policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Repo().RegistryRetention().DeleteRegistryRetentionPolicy(ctx, policy); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting retention policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
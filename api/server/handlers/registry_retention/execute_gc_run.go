@@ -0,0 +1,86 @@
+package registry_retention
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// maxPlanAge is how long a dry run can be executed after it was planned. Older plans are refused, since
+// images may have been pushed or deployed since.
+const maxPlanAge = 24 * time.Hour
+
+// ExecuteGCRunHandler queues a planned garbage collection run, to be executed by the registry garbage
+// collection worker job
+type ExecuteGCRunHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewExecuteGCRunHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ExecuteGCRunHandler {
+	return &ExecuteGCRunHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ExecuteGCRunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-execute-registry-gc-run")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	run, ok := readGCRun(c, w, r, reg)
+	if !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "gc-run-id", Value: run.ID},
+		telemetry.AttributeKV{Key: "status", Value: string(run.Status)},
+	)
+
+	if run.Status != types.RegistryGCRunStatusPlanned {
+		err := telemetry.Error(ctx, span, fmt.Errorf("garbage collection run is %s, only planned runs can be executed", run.Status), "run is not planned")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	if time.Since(run.CreatedAt) > maxPlanAge {
+		err := telemetry.Error(ctx, span, errors.New("garbage collection run was planned more than 24 hours ago, plan a new run"), "plan is stale")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	queued, err := c.Repo().RegistryRetention().UpdateRegistryGCRunStatus(ctx, run, types.RegistryGCRunStatusPlanned, types.RegistryGCRunStatusQueued)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error queueing garbage collection run")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if !queued {
+		err := telemetry.Error(ctx, span, errors.New("garbage collection run was already executed"), "run was already queued")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+		return
+	}
+
+	res, err := run.ToRegistryGCRunType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding garbage collection run")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
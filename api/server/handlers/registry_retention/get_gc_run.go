@@ -0,0 +1,79 @@
+package registry_retention
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetGCRunHandler reads a garbage collection run of a registry, including its plan and the audit of deleted images
+type GetGCRunHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetGCRunHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetGCRunHandler {
+	return &GetGCRunHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetGCRunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-registry-gc-run")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	run, ok := readGCRun(c, w, r, reg)
+	if !ok {
+		return
+	}
+
+	res, err := run.ToRegistryGCRunType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error decoding garbage collection run")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// readGCRun reads the garbage collection run in the URL, and writes an error if it can't be read
+func readGCRun(c handlers.PorterHandler, w http.ResponseWriter, r *http.Request, reg *models.Registry) (*models.RegistryGCRun, bool) {
+	ctx, span := telemetry.NewSpan(r.Context(), "read-registry-gc-run")
+	defer span.End()
+
+	runID, reqErr := requestutils.GetURLParamUint(r, types.URLParamRegistryGCRunID)
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return nil, false
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "gc-run-id", Value: runID})
+
+	run, err := c.Repo().RegistryRetention().ReadRegistryGCRun(ctx, reg.ProjectID, reg.ID, runID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.HandleAPIError(w, r, apierrors.NewErrNotFound(telemetry.Error(ctx, span, err, "garbage collection run not found")))
+			return nil, false
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading garbage collection run")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	return run, true
+}
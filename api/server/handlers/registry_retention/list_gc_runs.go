@@ -0,0 +1,62 @@
+package registry_retention
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ListGCRunsHandler lists the garbage collection runs of a registry
+type ListGCRunsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewListGCRunsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListGCRunsHandler {
+	return &ListGCRunsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListGCRunsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-registry-gc-runs")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	request := &types.ListRegistryGCRunsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	runs, err := c.Repo().RegistryRetention().ListRegistryGCRuns(ctx, reg.ProjectID, reg.ID, request.Repository)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing garbage collection runs")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListRegistryGCRunsResponse, 0, len(runs))
+
+	for _, run := range runs {
+		runType, err := run.ToRegistryGCRunType()
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error decoding garbage collection run")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		res = append(res, runType)
+	}
+
+	c.WriteResult(w, r, res)
+}
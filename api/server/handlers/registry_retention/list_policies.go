@@ -0,0 +1,49 @@
+package registry_retention
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ListPoliciesHandler lists the retention policies of a registry's repositories
+type ListPoliciesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListPoliciesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListPoliciesHandler {
+	return &ListPoliciesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListPoliciesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-registry-retention-policies")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	policies, err := c.Repo().RegistryRetention().ListRegistryRetentionPolicies(ctx, reg.ProjectID, reg.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing retention policies")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListRegistryRetentionPoliciesResponse, 0, len(policies))
+
+	for _, policy := range policies {
+		res = append(res, policy.ToRegistryRetentionPolicyType())
+	}
+
+	c.WriteResult(w, r, res)
+}
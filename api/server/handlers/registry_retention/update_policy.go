@@ -0,0 +1,81 @@
+package registry_retention
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// UpdatePolicyHandler creates or replaces the retention policy of a registry repository
+type UpdatePolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdatePolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdatePolicyHandler {
+	return &UpdatePolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdatePolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-registry-retention-policy")
+	defer span.End()
+
+	reg, _ := ctx.Value(types.RegistryScope).(*models.Registry)
+
+	request := &types.UpdateRegistryRetentionPolicyRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "repository", Value: request.Repository},
+		telemetry.AttributeKV{Key: "keep-last", Value: request.KeepLast},
+		telemetry.AttributeKV{Key: "keep-deployed-within-days", Value: request.KeepDeployedWithinDays},
+		telemetry.AttributeKV{Key: "keep-tag-patterns", Value: strings.Join(request.KeepTagPatterns, ",")},
+	)
+
+	for _, pattern := range request.KeepTagPatterns {
+		if pattern == "" || strings.Contains(pattern, ",") {
+			err := telemetry.Error(ctx, span, errors.New("tag patterns must be non-empty and cannot contain commas"), "invalid tag pattern")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	policy := &models.RegistryRetentionPolicy{
+		ProjectID:              reg.ProjectID,
+		RegistryID:             reg.ID,
+		Repository:             request.Repository,
+		KeepLast:               request.KeepLast,
+		KeepDeployedWithinDays: request.KeepDeployedWithinDays,
+		KeepTagPatterns:        strings.Join(request.KeepTagPatterns, ","),
+	}
+
+	if err := policy.Rules().Validate(); err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid retention policy")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	policy, err := c.Repo().RegistryRetention().UpdateRegistryRetentionPolicy(ctx, policy)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error updating retention policy")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, policy.ToRegistryRetentionPolicyType())
+}
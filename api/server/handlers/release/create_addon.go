@@ -14,6 +14,7 @@ import (
 	"github.com/karagatandev/porter/internal/analytics"
 	"github.com/karagatandev/porter/internal/helm"
 	"github.com/karagatandev/porter/internal/helm/loader"
+	"github.com/karagatandev/porter/internal/helm/repo"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/oauth"
 	"github.com/karagatandev/porter/internal/telemetry"
@@ -168,6 +169,13 @@ func LoadChart(ctx context.Context, config *config.Config, opts *LoadAddonChartO
 							Username: string(basic.Username),
 							Password: string(basic.Password),
 						}, hr.RepoURL, opts.TemplateName, opts.TemplateVersion)
+				} else if loader.IsOCIRepoURL(hr.RepoURL) {
+					client, err := repo.GetOCIAuthClient(ctx, config.Repo, opts.ProjectID, hr.RegistryID, hr.RepoURL, config.DOConf, config.GithubAppConf)
+					if err != nil {
+						return nil, err
+					}
+
+					return loader.LoadChart(ctx, client, hr.RepoURL, opts.TemplateName, opts.TemplateVersion)
 				} else {
 					return loader.LoadChartPublic(ctx, hr.RepoURL, opts.TemplateName, opts.TemplateVersion)
 				}
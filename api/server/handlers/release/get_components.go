@@ -40,6 +40,7 @@ func (c *GetComponentsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	parsed.GetControlRel()
 	parsed.GetLabelRel()
 	parsed.GetSpecRel()
+	parsed.GetResourceRel()
 
 	c.WriteResult(w, r, parsed)
 }
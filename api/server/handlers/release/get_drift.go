@@ -0,0 +1,72 @@
+package release
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/authz"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm/drift"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// GetDriftHandler compares the objects in a release's manifest with the live objects in
+// the cluster, reporting the fields which were changed outside of Helm
+type GetDriftHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetDriftHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetDriftHandler {
+	return &GetDriftHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetDriftHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-release-drift")
+	defer span.End()
+
+	helmRelease, _ := ctx.Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "release-name", Value: helmRelease.Name},
+		telemetry.AttributeKV{Key: "release-version", Value: helmRelease.Version},
+		telemetry.AttributeKV{Key: "namespace", Value: helmRelease.Namespace},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	getter, err := agent.NewLiveObjectGetter()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting live object getter")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	report, err := drift.DetectRelease(ctx, getter, helmRelease)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error detecting release drift")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "drifted", Value: report.Drifted})
+
+	c.WriteResult(w, r, report)
+}
@@ -0,0 +1,74 @@
+package release
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/authz"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm/grapher"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// GetHealthHandler returns the resource graph of a release, annotated with the live status
+// of each object and the health rolled up through its relationships
+type GetHealthHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetHealthHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetHealthHandler {
+	return &GetHealthHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetHealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-release-health")
+	defer span.End()
+
+	helmRelease, _ := ctx.Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "release-name", Value: helmRelease.Name},
+		telemetry.AttributeKV{Key: "namespace", Value: helmRelease.Namespace},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	yamlArr := grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest))
+	objects := grapher.ParseObjs(yamlArr, helmRelease.Namespace)
+
+	parsed := grapher.ParsedObjs{
+		Objects: objects,
+	}
+
+	parsed.GetControlRel()
+	parsed.GetLabelRel()
+	parsed.GetSpecRel()
+	parsed.GetResourceRel()
+
+	health, err := agent.GetReleaseHealth(ctx, &parsed)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting release health")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, health)
+}
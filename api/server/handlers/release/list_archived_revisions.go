@@ -0,0 +1,60 @@
+package release
+
+import (
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm/archive"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// ListArchivedRevisionsHandler lists the revisions of a release which were pruned from the
+// cluster and archived
+type ListArchivedRevisionsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListArchivedRevisionsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListArchivedRevisionsHandler {
+	return &ListArchivedRevisionsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListArchivedRevisionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-archived-revisions")
+	defer span.End()
+
+	helmRelease, _ := ctx.Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "release-name", Value: helmRelease.Name},
+		telemetry.AttributeKV{Key: "namespace", Value: helmRelease.Namespace},
+	)
+
+	if c.Config().HelmArchive == nil {
+		err := telemetry.Error(ctx, span, nil, "revision archival is not enabled on this instance")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusPreconditionFailed))
+		return
+	}
+
+	revisions, err := archive.ListRevisions(ctx, c.Config().HelmArchive, cluster.ProjectID, cluster.ID, helmRelease.Namespace, helmRelease.Name)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing archived revisions")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.ListArchivedRevisionsResponse{
+		Revisions: revisions,
+	})
+}
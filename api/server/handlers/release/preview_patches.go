@@ -0,0 +1,104 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/authz"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/handlers/manifest_patch"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// PreviewManifestPatchesHandler renders a release with the manifest patches of its project and
+// the patches in the request, without deploying it
+type PreviewManifestPatchesHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewPreviewManifestPatchesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *PreviewManifestPatchesHandler {
+	return &PreviewManifestPatchesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *PreviewManifestPatchesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-preview-manifest-patches")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+	helmRelease, _ := ctx.Value(types.ReleaseScope).(*release.Release)
+
+	request := &types.PreviewManifestPatchesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "release-name", Value: helmRelease.Name},
+		telemetry.AttributeKV{Key: "namespace", Value: helmRelease.Namespace},
+		telemetry.AttributeKV{Key: "skip-saved-patches", Value: request.SkipSavedPatches},
+	)
+
+	patches := make([]*models.ManifestPatch, 0)
+
+	if !request.SkipSavedPatches {
+		saved, err := helm.ListReleaseManifestPatches(ctx, c.Repo(), cluster.ProjectID, cluster.ID, helmRelease.Name, helmRelease.Chart.Metadata.Name)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error listing manifest patches")
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		patches = append(patches, saved...)
+	}
+
+	for i := range request.Patches {
+		patch := manifest_patch.NewManifestPatchFromRequest(cluster.ProjectID, &request.Patches[i])
+
+		if err := helm.ValidateManifestPatch(patch); err != nil {
+			err = telemetry.Error(ctx, span, err, fmt.Sprintf("invalid manifest patch %s", patch.Name))
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		patches = append(patches, patch)
+	}
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, "")
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	manifest, err := helmAgent.RenderReleaseWithPatches(ctx, &helm.UpgradeReleaseConfig{
+		Name:    helmRelease.Name,
+		Cluster: cluster,
+		Repo:    c.Repo(),
+	}, patches)
+	if err != nil {
+		// patches which don't apply to the release are the user's to fix
+		err = telemetry.Error(ctx, span, err, "error rendering release with manifest patches")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	c.WriteResult(w, r, &types.PreviewManifestPatchesResponse{
+		Manifest: manifest,
+	})
+}
@@ -0,0 +1,97 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/karagatandev/porter/api/server/authz"
+	"github.com/karagatandev/porter/api/server/handlers"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/apierrors"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm/archive"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// RestoreArchivedRevisionHandler writes an archived revision of a release back to the cluster,
+// so that the release can be rolled back to a revision which was pruned
+type RestoreArchivedRevisionHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewRestoreArchivedRevisionHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RestoreArchivedRevisionHandler {
+	return &RestoreArchivedRevisionHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *RestoreArchivedRevisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-restore-archived-revision")
+	defer span.End()
+
+	helmRelease, _ := ctx.Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.RestoreArchivedRevisionRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "release-name", Value: helmRelease.Name},
+		telemetry.AttributeKV{Key: "namespace", Value: helmRelease.Namespace},
+		telemetry.AttributeKV{Key: "revision", Value: request.Revision},
+	)
+
+	if c.Config().HelmArchive == nil {
+		err := telemetry.Error(ctx, span, nil, "revision archival is not enabled on this instance")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusPreconditionFailed))
+		return
+	}
+
+	rel, err := archive.GetRevision(ctx, c.Config().HelmArchive, cluster.ProjectID, cluster.ID, helmRelease.Namespace, helmRelease.Name, request.Revision)
+	if err != nil {
+		if errors.Is(err, archive.ErrNotFound) {
+			err = telemetry.Error(ctx, span, err, "archived revision not found")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("revision %d of release %s is not archived", request.Revision, helmRelease.Name),
+				http.StatusNotFound,
+			))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading archived revision")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, cluster, helmRelease.Namespace)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error getting helm agent")
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := helmAgent.RestoreReleaseRevision(ctx, rel); err != nil {
+		err = telemetry.Error(ctx, span, err, "error restoring revision")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	res := &types.Release{
+		Release: rel,
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,140 @@
+package router
+
+import (
+	"fmt"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/karagatandev/porter/api/server/handlers/admin"
+	"github.com/karagatandev/porter/api/server/shared"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/router"
+	"github.com/karagatandev/porter/api/types"
+)
+
+// NewAdminScopedRegisterer returns a registerer for endpoints which are only available to
+// the admin of the Porter instance
+func NewAdminScopedRegisterer(children ...*router.Registerer) *router.Registerer {
+	return &router.Registerer{
+		GetRoutes: GetAdminScopedRoutes,
+		Children:  children,
+	}
+}
+
+// GetAdminScopedRoutes returns the admin-scoped routes
+func GetAdminScopedRoutes(
+	r chi.Router,
+	config *config.Config,
+	basePath *types.Path,
+	factory shared.APIEndpointFactory,
+	children ...*router.Registerer,
+) []*router.Route {
+	routes, adminPath := getAdminRoutes(r, config, basePath, factory)
+
+	if len(children) > 0 {
+		r.Route(adminPath.RelativePath, func(r chi.Router) {
+			for _, child := range children {
+				childRoutes := child.GetRoutes(r, config, basePath, factory, child.Children...)
+
+				routes = append(routes, childRoutes...)
+			}
+		})
+	}
+
+	return routes
+}
+
+func getAdminRoutes(
+	r chi.Router,
+	config *config.Config,
+	basePath *types.Path,
+	factory shared.APIEndpointFactory,
+) ([]*router.Route, *types.Path) {
+	relPath := "/admin"
+
+	newPath := &types.Path{
+		Parent:       basePath,
+		RelativePath: relPath,
+	}
+
+	routes := make([]*router.Route, 0)
+
+	// GET /api/admin/key_rotations -> admin.NewListKeyRotationsHandler
+	listKeyRotationsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/key_rotations",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+			},
+		},
+	)
+
+	listKeyRotationsHandler := admin.NewListKeyRotationsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listKeyRotationsEndpoint,
+		Handler:  listKeyRotationsHandler,
+		Router:   r,
+	})
+
+	// POST /api/admin/key_rotations -> admin.NewCreateKeyRotationHandler
+	createKeyRotationEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/key_rotations",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+			},
+		},
+	)
+
+	createKeyRotationHandler := admin.NewCreateKeyRotationHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createKeyRotationEndpoint,
+		Handler:  createKeyRotationHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/key_rotations/{key_rotation_id} -> admin.NewGetKeyRotationHandler
+	getKeyRotationEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/key_rotations/{%s}", relPath, types.URLParamKeyRotationID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+			},
+		},
+	)
+
+	getKeyRotationHandler := admin.NewGetKeyRotationHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getKeyRotationEndpoint,
+		Handler:  getKeyRotationHandler,
+		Router:   r,
+	})
+
+	return routes, newPath
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/karagatandev/porter/api/server/handlers/gitinstallation"
 	"github.com/karagatandev/porter/api/server/handlers/healthcheck"
 	"github.com/karagatandev/porter/api/server/handlers/metadata"
+	"github.com/karagatandev/porter/api/server/handlers/oidc"
 	"github.com/karagatandev/porter/api/server/handlers/release"
 	"github.com/karagatandev/porter/api/server/handlers/user"
 	"github.com/karagatandev/porter/api/server/handlers/webhook"
@@ -269,6 +270,31 @@ func GetBaseRoutes(
 		Router:   r,
 	})
 
+	// POST /api/oidc/token -> oidc.NewTokenExchangeHandler
+	oidcTokenExchangeEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/oidc/token",
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	oidcTokenExchangeHandler := oidc.NewTokenExchangeHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: oidcTokenExchangeEndpoint,
+		Handler:  oidcTokenExchangeHandler,
+		Router:   r,
+	})
+
 	// POST /api/password/reset/initiate -> user.NewUserPasswordInitiateResetHandler
 	passwordInitiateResetEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -890,6 +890,34 @@ func getClusterRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/node_architectures -> cluster.NewListNodeArchitecturesHandler
+	listNodeArchitecturesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/node_architectures",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	listNodeArchitecturesHandler := cluster.NewListNodeArchitecturesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listNodeArchitecturesEndpoint,
+		Handler:  listNodeArchitecturesHandler,
+		Router:   r,
+	})
+
 	// GET /api/projects/{project_id}/clusters/{cluster_id}/nodes/{node_name} -> cluster.NewGetNodeHandler
 	getNodeEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -1649,6 +1677,7 @@ func getClusterRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.EnvGroupScope,
 			},
 		},
 	)
@@ -1678,6 +1707,7 @@ func getClusterRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.EnvGroupScope,
 			},
 		},
 	)
@@ -1736,6 +1766,7 @@ func getClusterRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.EnvGroupScope,
 			},
 		},
 	)
@@ -1752,6 +1783,185 @@ func getClusterRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/versions -> environment_groups.NewListEnvironmentGroupVersionsHandler
+	listEnvironmentGroupVersionsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/environment-groups/{%s}/versions", relPath, types.URLParamEnvGroupName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+				types.EnvGroupScope,
+			},
+		},
+	)
+
+	listEnvironmentGroupVersionsHandler := environment_groups.NewListEnvironmentGroupVersionsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listEnvironmentGroupVersionsEndpoint,
+		Handler:  listEnvironmentGroupVersionsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/diff -> environment_groups.NewDiffEnvironmentGroupVersionsHandler
+	diffEnvironmentGroupVersionsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/environment-groups/{%s}/diff", relPath, types.URLParamEnvGroupName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+				types.EnvGroupScope,
+			},
+		},
+	)
+
+	diffEnvironmentGroupVersionsHandler := environment_groups.NewDiffEnvironmentGroupVersionsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: diffEnvironmentGroupVersionsEndpoint,
+		Handler:  diffEnvironmentGroupVersionsHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/rollback -> environment_groups.NewRollbackEnvironmentGroupHandler
+	rollbackEnvironmentGroupEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/environment-groups/{%s}/rollback", relPath, types.URLParamEnvGroupName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+				types.EnvGroupScope,
+			},
+		},
+	)
+
+	rollbackEnvironmentGroupHandler := environment_groups.NewRollbackEnvironmentGroupHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: rollbackEnvironmentGroupEndpoint,
+		Handler:  rollbackEnvironmentGroupHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/import -> environment_groups.NewImportEnvironmentGroupHandler
+	importEnvironmentGroupEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/environment-groups/{%s}/import", relPath, types.URLParamEnvGroupName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+				types.EnvGroupScope,
+			},
+		},
+	)
+
+	importEnvironmentGroupHandler := environment_groups.NewImportEnvironmentGroupHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: importEnvironmentGroupEndpoint,
+		Handler:  importEnvironmentGroupHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/environment-groups/{env_group_name}/provider -> environment_groups.NewGetEnvGroupProviderHandler
+	getEnvGroupProviderEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/environment-groups/{%s}/provider", relPath, types.URLParamEnvGroupName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+				types.EnvGroupScope,
+			},
+		},
+	)
+
+	getEnvGroupProviderHandler := environment_groups.NewGetEnvGroupProviderHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getEnvGroupProviderEndpoint,
+		Handler:  getEnvGroupProviderHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/environment-groups/provider-preview -> environment_groups.NewPreviewEnvGroupProviderHandler
+	previewEnvGroupProviderEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/environment-groups/provider-preview",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+				types.EnvGroupScope,
+			},
+		},
+	)
+
+	previewEnvGroupProviderHandler := environment_groups.NewPreviewEnvGroupProviderHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: previewEnvGroupProviderEndpoint,
+		Handler:  previewEnvGroupProviderHandler,
+		Router:   r,
+	})
+
 	// GET /api/projects/{project_id}/clusters/{cluster_id}/environment-groups/update-linked-apps
 	updateLinkedAppsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -1765,6 +1975,7 @@ func getClusterRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.EnvGroupScope,
 			},
 		},
 	)
@@ -89,6 +89,122 @@ func getDeploymentTargetRoutes(
 		Router:   r,
 	})
 
+	// PATCH /api/projects/{project_id}/targets/{deployment_target_identifier} -> deployment_target.UpdateDeploymentTargetHandler
+	updateDeploymentTargetEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPatch,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath,
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+				types.DeploymentTargetScope,
+			},
+		},
+	)
+
+	updateDeploymentTargetHandler := deployment_target.NewUpdateDeploymentTargetHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateDeploymentTargetEndpoint,
+		Handler:  updateDeploymentTargetHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/targets/{deployment_target_identifier}/policy -> deployment_target.GetDeploymentTargetPolicyHandler
+	getDeploymentTargetPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/policy", relPath),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.DeploymentTargetScope,
+			},
+		},
+	)
+
+	getDeploymentTargetPolicyHandler := deployment_target.NewGetDeploymentTargetPolicyHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getDeploymentTargetPolicyEndpoint,
+		Handler:  getDeploymentTargetPolicyHandler,
+		Router:   r,
+	})
+
+	// PUT /api/projects/{project_id}/targets/{deployment_target_identifier}/policy -> deployment_target.UpdateDeploymentTargetPolicyHandler
+	updateDeploymentTargetPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPut,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/policy", relPath),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+				types.DeploymentTargetScope,
+			},
+		},
+	)
+
+	updateDeploymentTargetPolicyHandler := deployment_target.NewUpdateDeploymentTargetPolicyHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateDeploymentTargetPolicyEndpoint,
+		Handler:  updateDeploymentTargetPolicyHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/targets/{deployment_target_identifier}/policy/overrides -> deployment_target.ListDeployPolicyOverridesHandler
+	listDeployPolicyOverridesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/policy/overrides", relPath),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.DeploymentTargetScope,
+			},
+		},
+	)
+
+	listDeployPolicyOverridesHandler := deployment_target.NewListDeployPolicyOverridesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listDeployPolicyOverridesEndpoint,
+		Handler:  listDeployPolicyOverridesHandler,
+		Router:   r,
+	})
+
 	// DELETE /api/projects/{project_id}/targets/{deployment_target_identifier} -> deployment_target.DeleteDeploymentTargetHandler
 	deleteDeploymentTargetEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+const (
+	// maxAuditedBodySize is the largest request body which is summarized in an audit log
+	maxAuditedBodySize = 1 << 20
+
+	// maxAuditSummaryLength is the length that request summaries are truncated to
+	maxAuditSummaryLength = 4096
+
+	redactedValue = "[REDACTED]"
+)
+
+// sensitiveKeyFragments are substrings of JSON keys whose values are redacted from
+// audit log request summaries. Environment variables are redacted wholesale since
+// their names say nothing about whether their values are secret.
+var sensitiveKeyFragments = []string{
+	"password",
+	"secret",
+	"token",
+	"key",
+	"credential",
+	"cert",
+	"private",
+	"auth",
+	"kubeconfig",
+	"dockerconfig",
+	"variables",
+	"env",
+}
+
+// encodedDocumentKeyFragments are substrings of JSON keys whose values are whole documents,
+// such as helm values, porter.yaml files and base64-encoded app protos. These embed
+// environment variables and other secrets, so they are redacted like sensitive keys.
+var encodedDocumentKeyFragments = []string{
+	"values",
+	"yaml",
+	"proto",
+	"b64",
+	"base64",
+	"manifest",
+	"overrides",
+	"patch",
+}
+
+type auditCtxKey struct{}
+
+// auditRecord holds the context of a request after it has passed through the scope
+// middleware, so that the outer audit middleware can read the resources it targeted
+type auditRecord struct {
+	scopedCtx context.Context
+}
+
+// AuditLogMiddleware records an audit log for every request to a project-scoped
+// endpoint. Middleware must wrap the authorization middleware so that denied requests
+// are recorded, and CaptureScopes must be attached after all scope middleware.
+type AuditLogMiddleware struct {
+	config       *config.Config
+	endpointMeta types.APIRequestMetadata
+}
+
+func NewAuditLogMiddleware(config *config.Config, endpointMeta types.APIRequestMetadata) *AuditLogMiddleware {
+	return &AuditLogMiddleware{config, endpointMeta}
+}
+
+// Middleware records the actor, resources, redacted request body and outcome of the request
+func (mw *AuditLogMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		summary := summarizeRequestBody(r)
+
+		record := &auditRecord{}
+		rw := newRequestLoggerResponseWriter(w)
+
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), auditCtxKey{}, record)))
+
+		mw.writeAuditLog(r, record, summary, rw.statusCode)
+	})
+}
+
+// CaptureScopes stores the context populated by the scope middleware for Middleware to read
+func (mw *AuditLogMiddleware) CaptureScopes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if record, ok := r.Context().Value(auditCtxKey{}).(*auditRecord); ok {
+			record.scopedCtx = r.Context()
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (mw *AuditLogMiddleware) writeAuditLog(r *http.Request, record *auditRecord, summary string, statusCode int) {
+	ctx, span := telemetry.NewSpan(r.Context(), "middleware-audit-log")
+	defer span.End()
+
+	// the request was denied before reaching the scope middleware if the scoped context
+	// was not captured, so fall back to the project in the url
+	scopedCtx := record.scopedCtx
+	if scopedCtx == nil {
+		scopedCtx = ctx
+	}
+
+	auditLog := &models.AuditLog{
+		Verb:           mw.endpointMeta.Verb,
+		Method:         mw.endpointMeta.Method,
+		Path:           r.URL.Path,
+		RequestSummary: summary,
+		StatusCode:     statusCode,
+		Outcome:        auditOutcome(statusCode),
+	}
+
+	if proj, ok := scopedCtx.Value(types.ProjectScope).(*models.Project); ok {
+		auditLog.ProjectID = proj.ID
+	} else if projID, reqErr := requestutils.GetURLParamUint(r, types.URLParamProjectID); reqErr == nil {
+		auditLog.ProjectID = projID
+	}
+
+	if apiToken, ok := scopedCtx.Value("api_token").(*models.APIToken); ok {
+		auditLog.APITokenID = apiToken.UniqueID
+	} else if user, ok := scopedCtx.Value(types.UserScope).(*models.User); ok {
+		auditLog.UserID = user.ID
+	}
+
+	resources := make(map[types.PermissionScope]string)
+
+	if reqScopes, ok := scopedCtx.Value(types.RequestScopeCtxKey).(map[types.PermissionScope]*types.RequestAction); ok {
+		for scope, action := range reqScopes {
+			if scope == types.UserScope {
+				continue
+			}
+
+			if action.Resource.Name != "" {
+				resources[scope] = action.Resource.Name
+			} else if action.Resource.UInt != 0 {
+				resources[scope] = fmt.Sprintf("%d", action.Resource.UInt)
+			}
+		}
+	}
+
+	resourceBytes, err := json.Marshal(resources)
+	if err == nil {
+		auditLog.Resources = resourceBytes
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: auditLog.ProjectID},
+		telemetry.AttributeKV{Key: "status-code", Value: statusCode},
+	)
+
+	// the request has already been served, so failing to write the audit log is only reported
+	if _, err := mw.config.Repo.AuditLog().CreateAuditLog(context.WithoutCancel(ctx), auditLog); err != nil {
+		telemetry.Error(ctx, span, err, "error creating audit log")
+	}
+}
+
+func auditOutcome(statusCode int) types.AuditLogOutcome {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return types.AuditLogOutcomeDenied
+	case statusCode >= http.StatusBadRequest:
+		return types.AuditLogOutcomeFailure
+	default:
+		return types.AuditLogOutcomeSuccess
+	}
+}
+
+// summarizeRequestBody reads the request body and returns it with secret values
+// redacted. The body is restored so that it can be read again by the handler.
+func summarizeRequestBody(r *http.Request) string {
+	if r.Body == nil || r.Body == http.NoBody {
+		return ""
+	}
+
+	if r.ContentLength > maxAuditedBodySize {
+		return fmt.Sprintf("[body of %d bytes omitted]", r.ContentLength)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAuditedBodySize+1))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+	if err != nil || len(body) == 0 {
+		return ""
+	}
+
+	if len(body) > maxAuditedBodySize {
+		return "[body omitted]"
+	}
+
+	var parsed interface{}
+
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		// bodies which are not json cannot be redacted, so they are never stored
+		return fmt.Sprintf("[non-json body of %d bytes omitted]", len(body))
+	}
+
+	redacted, err := json.Marshal(redactSensitiveValues(parsed))
+	if err != nil {
+		return ""
+	}
+
+	if len(redacted) > maxAuditSummaryLength {
+		return string(redacted[:maxAuditSummaryLength]) + "...[truncated]"
+	}
+
+	return string(redacted)
+}
+
+func redactSensitiveValues(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactSensitiveValues(child)
+			}
+		}
+
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactSensitiveValues(child)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+
+	for _, fragment := range sensitiveKeyFragments {
+		if strings.Contains(lower, fragment) {
+			return true
+		}
+	}
+
+	for _, fragment := range encodedDocumentKeyFragments {
+		if strings.Contains(lower, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,333 @@
+package middleware
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/karagatandev/porter/api/server/shared/apitest"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+)
+
+func TestRedactSensitiveValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "non-sensitive values are kept",
+			body:     `{"name":"web","replicas":2,"enabled":true}`,
+			expected: `{"enabled":true,"name":"web","replicas":2}`,
+		},
+		{
+			name:     "credentials are redacted",
+			body:     `{"username":"admin","password":"hunter2","client_secret":"abc","api_token":"tok"}`,
+			expected: `{"api_token":"[REDACTED]","client_secret":"[REDACTED]","password":"[REDACTED]","username":"admin"}`,
+		},
+		{
+			name:     "keys are matched case-insensitively",
+			body:     `{"AWSAccessKeyID":"AKIA","PrivateKey":"-----BEGIN"}`,
+			expected: `{"AWSAccessKeyID":"[REDACTED]","PrivateKey":"[REDACTED]"}`,
+		},
+		{
+			name:     "environment variables are redacted wholesale",
+			body:     `{"name":"prod","variables":{"PORT":"80"},"secret_variables":{"DB_URL":"postgres://"},"env":[{"name":"A"}]}`,
+			expected: `{"env":"[REDACTED]","name":"prod","secret_variables":"[REDACTED]","variables":"[REDACTED]"}`,
+		},
+		{
+			name:     "nested objects and arrays are redacted",
+			body:     `{"registries":[{"url":"ghcr.io","auth":{"user":"a"}}],"cluster":{"name":"c","kubeconfig":"apiVersion: v1"}}`,
+			expected: `{"cluster":{"kubeconfig":"[REDACTED]","name":"c"},"registries":[{"auth":"[REDACTED]","url":"ghcr.io"}]}`,
+		},
+		{
+			name:     "encoded documents are redacted",
+			body:     `{"values":"env:\n  DB_PASSWORD: hunter2","b64_app_proto":"ZW52","base64_addons":["ZW52"],"porter_yaml":"env: {}"}`,
+			expected: `{"b64_app_proto":"[REDACTED]","base64_addons":"[REDACTED]","porter_yaml":"[REDACTED]","values":"[REDACTED]"}`,
+		},
+		{
+			name:     "scalars are kept",
+			body:     `"password"`,
+			expected: `"password"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed interface{}
+
+			if err := json.Unmarshal([]byte(tt.body), &parsed); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			redacted, err := json.Marshal(redactSensitiveValues(parsed))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(redacted) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, redacted)
+			}
+		})
+	}
+}
+
+func TestSummarizeRequestBody(t *testing.T) {
+	longBody := fmt.Sprintf(`{"description":"%s"}`, strings.Repeat("a", maxAuditSummaryLength))
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name: "empty body",
+		},
+		{
+			name:     "json body is redacted",
+			body:     `{"name":"web","token":"abc"}`,
+			expected: `{"name":"web","token":"[REDACTED]"}`,
+		},
+		{
+			name:     "non-json body is omitted",
+			body:     "password=hunter2",
+			expected: "[non-json body of 16 bytes omitted]",
+		},
+		{
+			name:     "long summaries are truncated",
+			body:     longBody,
+			expected: longBody[:maxAuditSummaryLength] + "...[truncated]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/projects/1/apps", strings.NewReader(tt.body))
+
+			summary := summarizeRequestBody(req)
+			if summary != tt.expected {
+				t.Errorf("expected summary %q, got %q", tt.expected, summary)
+			}
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(body) != tt.body {
+				t.Errorf("expected the body to be readable by the handler, got %q", body)
+			}
+		})
+	}
+}
+
+func TestAuditLogMiddleware(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+
+	proj, err := config.Repo.Project().CreateProject(&models.Project{Name: "test-project"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mw := NewAuditLogMiddleware(config, types.APIRequestMetadata{
+		Verb:   types.APIVerbUpdate,
+		Method: types.HTTPVerbPost,
+	})
+
+	// scopes stands in for the authn, authz and scope middleware
+	scopes := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = apitest.WithAuthenticatedUser(t, r, user)
+			r = apitest.WithProject(t, r, proj)
+			r = apitest.WithRequestScopes(t, r, map[types.PermissionScope]*types.RequestAction{
+				types.UserScope:    {Verb: types.APIVerbUpdate, Resource: types.NameOrUInt{UInt: user.ID}},
+				types.ProjectScope: {Verb: types.APIVerbUpdate, Resource: types.NameOrUInt{UInt: proj.ID}},
+				types.ClusterScope: {Verb: types.APIVerbUpdate, Resource: types.NameOrUInt{UInt: 2}},
+				types.ReleaseScope: {Verb: types.APIVerbUpdate, Resource: types.NameOrUInt{Name: "web"}},
+			})
+
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	var handlerBody string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		handlerBody = string(body)
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	body := `{"image":"nginx","variables":{"DB_PASSWORD":"hunter2"}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/projects/1/clusters/2/releases/web", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	mw.Middleware(scopes(mw.CaptureScopes(handler))).ServeHTTP(rr, req)
+
+	if handlerBody != body {
+		t.Errorf("expected the handler to read the original body, got %s", handlerBody)
+	}
+
+	auditLogs, err := config.Repo.AuditLog().ListAllAuditLogs(req.Context(), proj.ID, types.AuditLogFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(auditLogs) != 1 {
+		t.Fatalf("expected 1 audit log, got %d", len(auditLogs))
+	}
+
+	got := auditLogs[0].ToAuditLogType()
+
+	expected := &types.AuditLog{
+		ID:         got.ID,
+		CreatedAt:  got.CreatedAt,
+		ProjectID:  proj.ID,
+		UserID:     user.ID,
+		Verb:       types.APIVerbUpdate,
+		Method:     types.HTTPVerbPost,
+		Path:       "/api/projects/1/clusters/2/releases/web",
+		StatusCode: http.StatusAccepted,
+		Outcome:    types.AuditLogOutcomeSuccess,
+		Resources: map[types.PermissionScope]string{
+			types.ProjectScope: fmt.Sprintf("%d", proj.ID),
+			types.ClusterScope: "2",
+			types.ReleaseScope: "web",
+		},
+		RequestSummary: `{"image":"nginx","variables":"[REDACTED]"}`,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected audit log %+v, got %+v", expected, got)
+	}
+}
+
+func TestAuditLogMiddlewareRedactsEnvValues(t *testing.T) {
+	config := apitest.LoadConfig(t)
+
+	proj, err := config.Repo.Project().CreateProject(&models.Project{Name: "test-project"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mw := NewAuditLogMiddleware(config, types.APIRequestMetadata{
+		Verb:   types.APIVerbUpdate,
+		Method: types.HTTPVerbPost,
+	})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	secret := "hunter2"
+	encodedProto := base64.StdEncoding.EncodeToString([]byte(`{"env":{"DB_PASSWORD":"` + secret + `"}}`))
+
+	bodies := []string{
+		// helm upgrade
+		fmt.Sprintf(`{"values":"env:\n  normal:\n    DB_PASSWORD: %s\n","chart_version":"1.0.0"}`, secret),
+		// app update
+		fmt.Sprintf(`{"b64_app_proto":"%s","deployment_target_id":"staging"}`, encodedProto),
+	}
+
+	for _, body := range bodies {
+		req := httptest.NewRequest(http.MethodPost, "/api/projects/1/clusters/2/apps/update", strings.NewReader(body))
+		req = apitest.WithURLParams(t, req, map[string]string{
+			string(types.URLParamProjectID): fmt.Sprintf("%d", proj.ID),
+		})
+
+		mw.Middleware(handler).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	auditLogs, err := config.Repo.AuditLog().ListAllAuditLogs(context.Background(), proj.ID, types.AuditLogFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(auditLogs) != len(bodies) {
+		t.Fatalf("expected %d audit logs, got %d", len(bodies), len(auditLogs))
+	}
+
+	for _, auditLog := range auditLogs {
+		if strings.Contains(auditLog.RequestSummary, secret) || strings.Contains(auditLog.RequestSummary, encodedProto) {
+			t.Errorf("expected env values to be redacted, got %s", auditLog.RequestSummary)
+		}
+	}
+}
+
+func TestAuditLogMiddlewareDenied(t *testing.T) {
+	config := apitest.LoadConfig(t)
+
+	mw := NewAuditLogMiddleware(config, types.APIRequestMetadata{
+		Verb:   types.APIVerbDelete,
+		Method: types.HTTPVerbDelete,
+	})
+
+	// the authz middleware rejects the request before the scopes are captured
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/projects/3/clusters/2", nil)
+	req = apitest.WithURLParams(t, req, map[string]string{
+		string(types.URLParamProjectID): "3",
+	})
+	rr := httptest.NewRecorder()
+
+	mw.Middleware(handler).ServeHTTP(rr, req)
+
+	auditLogs, err := config.Repo.AuditLog().ListAllAuditLogs(req.Context(), 3, types.AuditLogFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(auditLogs) != 1 {
+		t.Fatalf("expected 1 audit log, got %d", len(auditLogs))
+	}
+
+	got := auditLogs[0]
+
+	if got.UserID != 0 || got.APITokenID != "" {
+		t.Errorf("expected no actor for a request denied before authentication, got user %d and token %s", got.UserID, got.APITokenID)
+	}
+
+	if got.StatusCode != http.StatusForbidden || got.Outcome != types.AuditLogOutcomeDenied {
+		t.Errorf("expected a denied outcome with status 403, got %s with status %d", got.Outcome, got.StatusCode)
+	}
+
+	if got.RequestSummary != "" {
+		t.Errorf("expected an empty request summary, got %s", got.RequestSummary)
+	}
+}
+
+func TestAuditOutcome(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		expected   types.AuditLogOutcome
+	}{
+		{statusCode: http.StatusOK, expected: types.AuditLogOutcomeSuccess},
+		{statusCode: http.StatusCreated, expected: types.AuditLogOutcomeSuccess},
+		{statusCode: http.StatusUnauthorized, expected: types.AuditLogOutcomeDenied},
+		{statusCode: http.StatusForbidden, expected: types.AuditLogOutcomeDenied},
+		{statusCode: http.StatusBadRequest, expected: types.AuditLogOutcomeFailure},
+		{statusCode: http.StatusInternalServerError, expected: types.AuditLogOutcomeFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			if outcome := auditOutcome(tt.statusCode); outcome != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, outcome)
+			}
+		})
+	}
+}
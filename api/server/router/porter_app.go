@@ -702,6 +702,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.PorterAppScope,
 			},
 		},
 	)
@@ -776,6 +777,35 @@ func getPorterAppRoutes(
 		Router:   r,
 	})
 
+	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/promote -> porter_app.NewPromoteAppHandler
+	promoteAppEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/{%s}/promote", relPathV2, types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	promoteAppHandler := porter_app.NewPromoteAppHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: promoteAppEndpoint,
+		Handler:  promoteAppHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/update-image -> porter_app.NewUpdateImageHandler
 	updatePorterAppImageEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -934,6 +964,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.PorterAppScope,
 			},
 		},
 	)
@@ -1416,6 +1447,34 @@ func getPorterAppRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/approvals -> porter_app.NewListAppRevisionApprovalsHandler
+	listAppRevisionApprovalsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/apps/{%s}/approvals", types.URLParamPorterAppName),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.ClusterScope,
+			},
+		},
+	)
+
+	listAppRevisionApprovalsHandler := porter_app.NewListAppRevisionApprovalsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listAppRevisionApprovalsEndpoint,
+		Handler:  listAppRevisionApprovalsHandler,
+		Router:   r,
+	})
+
 	// GET /api/projects/{project_id}/clusters/{cluster_id}/apps/{porter_app_name}/revisions/{app_revision_id}/build-env -> porter_app.NewGetBuildEnvHandler
 	getBuildEnvEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -12,12 +12,17 @@ import (
 	"github.com/go-chi/chi/v5"
 	apiContract "github.com/karagatandev/porter/api/server/handlers/api_contract"
 	"github.com/karagatandev/porter/api/server/handlers/api_token"
+	"github.com/karagatandev/porter/api/server/handlers/audit_log"
 	"github.com/karagatandev/porter/api/server/handlers/billing"
 	"github.com/karagatandev/porter/api/server/handlers/cluster"
 	"github.com/karagatandev/porter/api/server/handlers/datastore"
 	"github.com/karagatandev/porter/api/server/handlers/gitinstallation"
 	"github.com/karagatandev/porter/api/server/handlers/helmrepo"
+	"github.com/karagatandev/porter/api/server/handlers/image_scan"
+	"github.com/karagatandev/porter/api/server/handlers/image_signature"
 	"github.com/karagatandev/porter/api/server/handlers/infra"
+	"github.com/karagatandev/porter/api/server/handlers/manifest_patch"
+	"github.com/karagatandev/porter/api/server/handlers/oidc"
 	"github.com/karagatandev/porter/api/server/handlers/policy"
 	"github.com/karagatandev/porter/api/server/handlers/project"
 	"github.com/karagatandev/porter/api/server/handlers/registry"
@@ -1147,6 +1152,34 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	//  GET /api/projects/{project_id}/registries/token -> registry.NewRegistryGetTokenHandler
+	getRegistryTokenEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/registries/token",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getRegistryTokenHandler := registry.NewRegistryGetTokenHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getRegistryTokenEndpoint,
+		Handler:  getRegistryTokenHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects/{project_id}/infras -> infra.NewInfraCreateHandler
 	createInfraEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -2049,5 +2082,431 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	//  GET /api/projects/{project_id}/audit_logs -> audit_log.NewAuditLogListHandler
+	listAuditLogsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/audit_logs",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	listAuditLogsHandler := audit_log.NewAuditLogListHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listAuditLogsEndpoint,
+		Handler:  listAuditLogsHandler,
+		Router:   r,
+	})
+
+	//  GET /api/projects/{project_id}/audit_logs/export -> audit_log.NewAuditLogExportHandler
+	exportAuditLogsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/audit_logs/export",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	exportAuditLogsHandler := audit_log.NewAuditLogExportHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: exportAuditLogsEndpoint,
+		Handler:  exportAuditLogsHandler,
+		Router:   r,
+	})
+
+	//  GET /api/projects/{project_id}/audit_logs/settings -> audit_log.NewAuditLogSettingsGetHandler
+	getAuditLogSettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/audit_logs/settings",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	getAuditLogSettingsHandler := audit_log.NewAuditLogSettingsGetHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getAuditLogSettingsEndpoint,
+		Handler:  getAuditLogSettingsHandler,
+		Router:   r,
+	})
+
+	//  POST /api/projects/{project_id}/audit_logs/settings -> audit_log.NewAuditLogSettingsUpdateHandler
+	updateAuditLogSettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/audit_logs/settings",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateAuditLogSettingsHandler := audit_log.NewAuditLogSettingsUpdateHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateAuditLogSettingsEndpoint,
+		Handler:  updateAuditLogSettingsHandler,
+		Router:   r,
+	})
+
+	//  GET /api/projects/{project_id}/oidc/trust_rules -> oidc.NewListTrustRulesHandler
+	listTrustRulesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/oidc/trust_rules",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	listTrustRulesHandler := oidc.NewListTrustRulesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listTrustRulesEndpoint,
+		Handler:  listTrustRulesHandler,
+		Router:   r,
+	})
+
+	//  POST /api/projects/{project_id}/oidc/trust_rules -> oidc.NewCreateTrustRuleHandler
+	createTrustRuleEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/oidc/trust_rules",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	createTrustRuleHandler := oidc.NewCreateTrustRuleHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createTrustRuleEndpoint,
+		Handler:  createTrustRuleHandler,
+		Router:   r,
+	})
+
+	//  DELETE /api/projects/{project_id}/oidc/trust_rules/{oidc_trust_rule_id} -> oidc.NewDeleteTrustRuleHandler
+	deleteTrustRuleEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/oidc/trust_rules/{%s}", relPath, types.URLParamOIDCTrustRuleID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	deleteTrustRuleHandler := oidc.NewDeleteTrustRuleHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteTrustRuleEndpoint,
+		Handler:  deleteTrustRuleHandler,
+		Router:   r,
+	})
+
+	//  GET /api/projects/{project_id}/image_scan_policy -> image_scan.NewGetPolicyHandler
+	getImageScanPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/image_scan_policy",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getImageScanPolicyHandler := image_scan.NewGetPolicyHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getImageScanPolicyEndpoint,
+		Handler:  getImageScanPolicyHandler,
+		Router:   r,
+	})
+
+	//  PUT /api/projects/{project_id}/image_scan_policy -> image_scan.NewUpdatePolicyHandler
+	updateImageScanPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPut,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/image_scan_policy",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateImageScanPolicyHandler := image_scan.NewUpdatePolicyHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateImageScanPolicyEndpoint,
+		Handler:  updateImageScanPolicyHandler,
+		Router:   r,
+	})
+
+	//  GET /api/projects/{project_id}/image_trust_policy -> image_signature.NewGetTrustPolicyHandler
+	getImageTrustPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/image_trust_policy",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getImageTrustPolicyHandler := image_signature.NewGetTrustPolicyHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getImageTrustPolicyEndpoint,
+		Handler:  getImageTrustPolicyHandler,
+		Router:   r,
+	})
+
+	//  PUT /api/projects/{project_id}/image_trust_policy -> image_signature.NewUpdateTrustPolicyHandler
+	updateImageTrustPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPut,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/image_trust_policy",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateImageTrustPolicyHandler := image_signature.NewUpdateTrustPolicyHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateImageTrustPolicyEndpoint,
+		Handler:  updateImageTrustPolicyHandler,
+		Router:   r,
+	})
+
+	//  GET /api/projects/{project_id}/manifest_patches -> manifest_patch.NewListManifestPatchesHandler
+	listManifestPatchesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/manifest_patches",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	listManifestPatchesHandler := manifest_patch.NewListManifestPatchesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listManifestPatchesEndpoint,
+		Handler:  listManifestPatchesHandler,
+		Router:   r,
+	})
+
+	//  POST /api/projects/{project_id}/manifest_patches -> manifest_patch.NewCreateManifestPatchHandler
+	createManifestPatchEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/manifest_patches",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	createManifestPatchHandler := manifest_patch.NewCreateManifestPatchHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createManifestPatchEndpoint,
+		Handler:  createManifestPatchHandler,
+		Router:   r,
+	})
+
+	//  PATCH /api/projects/{project_id}/manifest_patches/{manifest_patch_id} -> manifest_patch.NewUpdateManifestPatchHandler
+	updateManifestPatchEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPatch,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/manifest_patches/{%s}", relPath, types.URLParamManifestPatchID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateManifestPatchHandler := manifest_patch.NewUpdateManifestPatchHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateManifestPatchEndpoint,
+		Handler:  updateManifestPatchHandler,
+		Router:   r,
+	})
+
+	//  DELETE /api/projects/{project_id}/manifest_patches/{manifest_patch_id} -> manifest_patch.NewDeleteManifestPatchHandler
+	deleteManifestPatchEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/manifest_patches/{%s}", relPath, types.URLParamManifestPatchID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	deleteManifestPatchHandler := manifest_patch.NewDeleteManifestPatchHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteManifestPatchEndpoint,
+		Handler:  deleteManifestPatchHandler,
+		Router:   r,
+	})
+
 	return routes, newPath
 }
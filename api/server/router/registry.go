@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/karagatandev/porter/api/server/handlers/image_scan"
 	"github.com/karagatandev/porter/api/server/handlers/registry"
+	"github.com/karagatandev/porter/api/server/handlers/registry_retention"
 	"github.com/karagatandev/porter/api/server/shared"
 	"github.com/karagatandev/porter/api/server/shared/config"
 	"github.com/karagatandev/porter/api/server/shared/router"
@@ -229,5 +231,262 @@ func getRegistryRoutes(
 		Router:   r,
 	})
 
+	// POST /api/projects/{project_id}/registries/{registry_id}/scans -> image_scan.NewScanImageHandler
+	scanImageEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/scans",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+		},
+	)
+
+	scanImageHandler := image_scan.NewScanImageHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: scanImageEndpoint,
+		Handler:  scanImageHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/registries/{registry_id}/scans -> image_scan.NewGetImageScanHandler
+	getImageScanEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/scans",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+		},
+	)
+
+	getImageScanHandler := image_scan.NewGetImageScanHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getImageScanEndpoint,
+		Handler:  getImageScanHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/registries/{registry_id}/retention_policies -> registry_retention.NewListPoliciesHandler
+	listRetentionPoliciesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/retention_policies",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+		},
+	)
+
+	listRetentionPoliciesHandler := registry_retention.NewListPoliciesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listRetentionPoliciesEndpoint,
+		Handler:  listRetentionPoliciesHandler,
+		Router:   r,
+	})
+
+	// PUT /api/projects/{project_id}/registries/{registry_id}/retention_policies -> registry_retention.NewUpdatePolicyHandler
+	updateRetentionPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPut,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/retention_policies",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+		},
+	)
+
+	updateRetentionPolicyHandler := registry_retention.NewUpdatePolicyHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateRetentionPolicyEndpoint,
+		Handler:  updateRetentionPolicyHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/registries/{registry_id}/retention_policies -> registry_retention.NewDeletePolicyHandler
+	deleteRetentionPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/retention_policies",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+		},
+	)
+
+	deleteRetentionPolicyHandler := registry_retention.NewDeletePolicyHandler(
+		config,
+		factory.GetDecoderValidator(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteRetentionPolicyEndpoint,
+		Handler:  deleteRetentionPolicyHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/registries/{registry_id}/gc_runs -> registry_retention.NewCreateGCRunHandler
+	createGCRunEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/gc_runs",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+		},
+	)
+
+	createGCRunHandler := registry_retention.NewCreateGCRunHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createGCRunEndpoint,
+		Handler:  createGCRunHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/registries/{registry_id}/gc_runs -> registry_retention.NewListGCRunsHandler
+	listGCRunsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/gc_runs",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+		},
+	)
+
+	listGCRunsHandler := registry_retention.NewListGCRunsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listGCRunsEndpoint,
+		Handler:  listGCRunsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/registries/{registry_id}/gc_runs/{gc_run_id} -> registry_retention.NewGetGCRunHandler
+	getGCRunEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/gc_runs/{%s}", relPath, types.URLParamRegistryGCRunID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+		},
+	)
+
+	getGCRunHandler := registry_retention.NewGetGCRunHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getGCRunEndpoint,
+		Handler:  getGCRunHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/registries/{registry_id}/gc_runs/{gc_run_id}/execute -> registry_retention.NewExecuteGCRunHandler
+	executeGCRunEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/gc_runs/{%s}/execute", relPath, types.URLParamRegistryGCRunID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.RegistryScope,
+			},
+		},
+	)
+
+	executeGCRunHandler := registry_retention.NewExecuteGCRunHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: executeGCRunEndpoint,
+		Handler:  executeGCRunHandler,
+		Router:   r,
+	})
+
 	return routes, newPath
 }
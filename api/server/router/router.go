@@ -62,8 +62,9 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 		notificationRegisterer,
 	)
 	statusRegisterer := NewStatusScopedRegisterer()
+	adminRegisterer := NewAdminScopedRegisterer()
 
-	userRegisterer := NewUserScopedRegisterer(projRegisterer, statusRegisterer)
+	userRegisterer := NewUserScopedRegisterer(projRegisterer, statusRegisterer, adminRegisterer)
 	panicMW := middleware.NewPanicMiddleware(config)
 
 	if config.ServerConf.PprofEnabled {
@@ -265,6 +266,8 @@ func registerRoutes(config *config.Config, routes []*router.Route) {
 	for _, route := range routes {
 		atomicGroup := route.Router.Group(nil)
 
+		var auditMw *middleware.AuditLogMiddleware
+
 		for _, scope := range route.Endpoint.Metadata.Scopes {
 			switch scope {
 			case types.UserScope:
@@ -275,6 +278,12 @@ func registerRoutes(config *config.Config, routes []*router.Route) {
 					atomicGroup.Use(authNFactory.NewAuthenticated)
 				}
 			case types.ProjectScope:
+				// record every write to a project, including requests denied by the policy middleware
+				if isMutatingVerb(route.Endpoint.Metadata.Verb) {
+					auditMw = middleware.NewAuditLogMiddleware(config, *route.Endpoint.Metadata)
+					atomicGroup.Use(auditMw.Middleware)
+				}
+
 				policyFactory := authz.NewPolicyMiddleware(config, *route.Endpoint.Metadata, policyDocLoader)
 
 				atomicGroup.Use(policyFactory.Middleware)
@@ -310,6 +319,10 @@ func registerRoutes(config *config.Config, routes []*router.Route) {
 			}
 		}
 
+		if auditMw != nil {
+			atomicGroup.Use(auditMw.CaptureScopes)
+		}
+
 		if !route.Endpoint.Metadata.Quiet {
 			atomicGroup.Use(loggerMw.Middleware)
 		}
@@ -332,3 +345,7 @@ func registerRoutes(config *config.Config, routes []*router.Route) {
 		)
 	}
 }
+
+func isMutatingVerb(verb types.APIVerb) bool {
+	return verb == types.APIVerbCreate || verb == types.APIVerbUpdate || verb == types.APIVerbDelete
+}
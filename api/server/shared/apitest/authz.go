@@ -18,6 +18,15 @@ func WithProject(t *testing.T, req *http.Request, proj *models.Project) *http.Re
 	return req
 }
 
+// WithCluster adds the cluster to the context of the request, as the cluster middleware does
+func WithCluster(t *testing.T, req *http.Request, cluster *models.Cluster) *http.Request {
+	ctx := req.Context()
+	ctx = context.WithValue(ctx, types.ClusterScope, cluster)
+	req = req.WithContext(ctx)
+
+	return req
+}
+
 func WithRequestScopes(t *testing.T, req *http.Request, reqScopes map[types.PermissionScope]*types.RequestAction) *http.Request {
 	ctx := req.Context()
 	ctx = authz.NewRequestScopeCtx(ctx, reqScopes)
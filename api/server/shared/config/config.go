@@ -10,9 +10,14 @@ import (
 	"github.com/karagatandev/porter/internal/analytics"
 	"github.com/karagatandev/porter/internal/auth/token"
 	"github.com/karagatandev/porter/internal/billing"
+	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/features"
+	"github.com/karagatandev/porter/internal/helm/archive"
 	"github.com/karagatandev/porter/internal/helm/urlcache"
+	"github.com/karagatandev/porter/internal/imagescan"
+	"github.com/karagatandev/porter/internal/imagesign"
 	"github.com/karagatandev/porter/internal/integrations/dns"
+	"github.com/karagatandev/porter/internal/keyrotate"
 	"github.com/karagatandev/porter/internal/nats"
 	"github.com/karagatandev/porter/internal/notifier"
 	"github.com/karagatandev/porter/internal/oauth"
@@ -91,6 +96,16 @@ type Config struct {
 	// URLCache contains a cache of chart names to chart repos
 	URLCache *urlcache.ChartURLCache
 
+	// HelmArchive is the store pruned Helm release revisions are archived to. It is nil if
+	// archival is disabled.
+	HelmArchive archive.Store
+
+	// ImageScanner scans images for vulnerabilities. It is nil if image scanning is disabled.
+	ImageScanner imagescan.Scanner
+
+	// ImageVerifier verifies image signatures. It is nil if signature verification is disabled.
+	ImageVerifier imagesign.Verifier
+
 	// ProvisionerClient is an authenticated client for the provisioner service
 	ProvisionerClient *client.Client
 
@@ -116,6 +131,15 @@ type Config struct {
 	// is used
 	CredentialBackend credentials.CredentialStorage
 
+	// Keyring holds the envelope encryption configured by ENCRYPTION_KMS_PROVIDER, and the
+	// previous key set by OLD_ENCRYPTION_KEY while a key rotation is in progress
+	Keyring *encryption.Keyring
+
+	// KeyRotator re-encrypts values from OLD_ENCRYPTION_KEY to ENCRYPTION_KEY in the background
+	// when started by the instance admin. It is nil if OLD_ENCRYPTION_KEY is not set, or if
+	// envelope encryption is enabled, since envelope values are rotated when they are written.
+	KeyRotator *keyrotate.Rotator
+
 	// NATS contains the required config for connecting to a NATS cluster for streaming
 	NATS nats.NATS
 
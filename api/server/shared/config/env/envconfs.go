@@ -16,6 +16,10 @@ type ServerConf struct {
 
 	UsageTrackingEnabled bool `env:"USAGE_TRACKING_ENABLED,default=false"`
 
+	// AuditLogRetention is how long project audit logs are kept before they are deleted, unless
+	// the project sets its own retention. A retention of 0 keeps audit logs forever.
+	AuditLogRetention time.Duration `env:"AUDIT_LOG_RETENTION,default=2160h"`
+
 	Port                 int           `env:"SERVER_PORT,default=8080"`
 	StaticFilePath       string        `env:"STATIC_FILE_PATH,default=/porter/static"`
 	CookieName           string        `env:"COOKIE_NAME,default=porter"`
@@ -32,6 +36,35 @@ type ServerConf struct {
 	DefaultApplicationHelmRepoURL string `env:"HELM_APP_REPO_URL,default=https://charts.getporter.dev"`
 	DefaultAddonHelmRepoURL       string `env:"HELM_ADD_ON_REPO_URL,default=https://chart-addons.getporter.dev"`
 
+	// HelmRepoIndexTTL is how long a fetched Helm repo index is used before it is revalidated
+	HelmRepoIndexTTL time.Duration `env:"HELM_REPO_INDEX_TTL,default=5m"`
+
+	// HelmRepoCacheRefreshInterval is how often the charts of the default Helm repos are reloaded
+	HelmRepoCacheRefreshInterval time.Duration `env:"HELM_REPO_CACHE_REFRESH_INTERVAL,default=10m"`
+
+	// HelmRepoCacheDir is where the indexes of public Helm repos are kept, so that they can be
+	// used while a repo is unreachable. Defaults to a directory in the system's temp directory.
+	HelmRepoCacheDir string `env:"HELM_REPO_CACHE_DIR"`
+
+	// HelmArchive configures where Helm release revisions pruned from clusters are archived,
+	// so that they can be restored
+	HelmArchive HelmArchiveConf
+
+	// MetricsPort is the port Prometheus metrics are served on. Metrics are not served if it is 0.
+	MetricsPort int `env:"METRICS_PORT,default=0"`
+
+	// ImageScanner is the scanner used to find vulnerabilities in images, either "trivy" or "local".
+	// The local scanner only reports findings registered with it and is meant for tests. Images
+	// are not scanned if it is empty.
+	ImageScanner string `env:"IMAGE_SCANNER"`
+	TrivyPath    string `env:"TRIVY_PATH,default=trivy"`
+
+	// ImageVerifier is the verifier used to check image signatures, either "cosign" or "local".
+	// The local verifier only trusts signatures registered with it and is meant for tests. Image
+	// signatures are not verified if it is empty.
+	ImageVerifier string `env:"IMAGE_VERIFIER"`
+	CosignPath    string `env:"COSIGN_PATH,default=cosign"`
+
 	BasicLoginEnabled bool `env:"BASIC_LOGIN_ENABLED,default=true"`
 
 	GithubClientID     string `env:"GITHUB_CLIENT_ID"`
@@ -186,6 +219,23 @@ type ServerConf struct {
 type DBConf struct {
 	// EncryptionKey is the key to use for sensitive values that are encrypted at rest
 	EncryptionKey string `env:"ENCRYPTION_KEY,default=__random_strong_encryption_key__"`
+	// OldEncryptionKey is the key that values are being rotated away from. While it is set,
+	// values which are still encrypted with it can be read, and the instance admin can start
+	// a key rotation from it to EncryptionKey
+	OldEncryptionKey string `env:"OLD_ENCRYPTION_KEY"`
+
+	// EncryptionKMSProvider enables envelope encryption, where each record is encrypted with
+	// its own data key wrapped by a key from this key management service. One of
+	// "local", "aws", "gcp" or "vault"; if empty, EncryptionKey is used directly
+	EncryptionKMSProvider string `env:"ENCRYPTION_KMS_PROVIDER"`
+	// EncryptionKMSKeyID identifies the key-encryption key: a key ARN or alias for AWS,
+	// a crypto key resource name for GCP, or a transit key name for Vault
+	EncryptionKMSKeyID string `env:"ENCRYPTION_KMS_KEY_ID"`
+	// EncryptionKMSAWSRegion is the region of the AWS KMS key
+	EncryptionKMSAWSRegion string `env:"ENCRYPTION_KMS_AWS_REGION,default=us-east-1"`
+	// EncryptionKMSVaultTransitMount is the mount path of the Vault Transit engine. The Vault
+	// server URL and token are read from VAULT_SERVER_URL and VAULT_API_KEY
+	EncryptionKMSVaultTransitMount string `env:"ENCRYPTION_KMS_VAULT_TRANSIT_MOUNT,default=transit"`
 
 	Host     string `env:"DB_HOST,default=postgres"`
 	Port     int    `env:"DB_PORT,default=5432"`
@@ -215,3 +265,27 @@ type RedisConf struct {
 	Password string `env:"REDIS_PASS"`
 	DB       int    `env:"REDIS_DB,default=0"`
 }
+
+// HelmArchiveConf configures where Helm release revisions pruned from clusters are archived
+type HelmArchiveConf struct {
+	// Backend is one of "s3", "gcs" or "local". Revisions are not archived if it is empty.
+	Backend string `env:"HELM_ARCHIVE_BACKEND"`
+
+	// Bucket is the S3 or GCS bucket revisions are archived to
+	Bucket string `env:"HELM_ARCHIVE_BUCKET"`
+
+	AWSRegion          string `env:"HELM_ARCHIVE_AWS_REGION,default=us-east-1"`
+	AWSAccessKeyID     string `env:"HELM_ARCHIVE_AWS_ACCESS_KEY_ID"`
+	AWSSecretAccessKey string `env:"HELM_ARCHIVE_AWS_SECRET_ACCESS_KEY"`
+
+	// GCPCredentials is a service account key for the GCS bucket. Application default
+	// credentials are used if it is empty.
+	GCPCredentials string `env:"HELM_ARCHIVE_GCP_CREDENTIALS"`
+
+	// Dir is the directory revisions are archived to with the local backend
+	Dir string `env:"HELM_ARCHIVE_DIR,default=/porter/helm-archive"`
+
+	// EncryptionKey encrypts archived revisions, which contain the values of releases. It is
+	// required when Backend is set.
+	EncryptionKey string `env:"HELM_ARCHIVE_ENCRYPTION_KEY"`
+}
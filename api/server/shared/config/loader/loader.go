@@ -24,10 +24,15 @@ import (
 	"github.com/karagatandev/porter/internal/auth/token"
 	"github.com/karagatandev/porter/internal/billing"
 	"github.com/karagatandev/porter/internal/features"
+	"github.com/karagatandev/porter/internal/helm/archive"
+	helmloader "github.com/karagatandev/porter/internal/helm/loader"
 	"github.com/karagatandev/porter/internal/helm/urlcache"
+	"github.com/karagatandev/porter/internal/imagescan"
+	"github.com/karagatandev/porter/internal/imagesign"
 	"github.com/karagatandev/porter/internal/integrations/cloudflare"
 	"github.com/karagatandev/porter/internal/integrations/dns"
 	"github.com/karagatandev/porter/internal/integrations/powerdns"
+	"github.com/karagatandev/porter/internal/keyrotate"
 	"github.com/karagatandev/porter/internal/notifier"
 	"github.com/karagatandev/porter/internal/notifier/sendgrid"
 	"github.com/karagatandev/porter/internal/oauth"
@@ -107,10 +112,21 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 		key[i] = b
 	}
 
+	res.Logger.Info().Msg("Creating encryption keyring")
+	res.Keyring, err = adapter.NewKeyring(envConf.DBConf, &key)
+	if err != nil {
+		return nil, fmt.Errorf("could not create encryption keyring: %w", err)
+	}
+	res.Logger.Info().Msg("Created encryption keyring")
+
 	res.Logger.Info().Msg("Creating new gorm repository")
-	res.Repo = gorm.NewRepository(InstanceDB, &key, instanceCredentialBackend)
+	res.Repo = gorm.NewRepositoryWithKeyring(InstanceDB, &key, instanceCredentialBackend, res.Keyring)
 	res.Logger.Info().Msg("Created new gorm repository")
 
+	if res.Keyring.PreviousKey != nil && res.Keyring.Envelope == nil {
+		res.KeyRotator = keyrotate.NewRotator(InstanceDB, res.Keyring.PreviousKey, &key, res.Logger)
+	}
+
 	res.Logger.Info().Msg("Creating new session store")
 	// create the session store
 	res.Store, err = sessionstore.NewStore(
@@ -310,6 +326,38 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 	}
 
 	res.WhitelistedUsers = wlUsers
+	repoCacheDir := sc.HelmRepoCacheDir
+	if repoCacheDir == "" {
+		repoCacheDir = filepath.Join(os.TempDir(), "porter-helm-repo-index")
+	}
+
+	helmloader.SetRepoIndexCache(helmloader.NewRepoIndexCache(sc.HelmRepoIndexTTL, repoCacheDir))
+
+	res.HelmArchive, err = archive.NewStore(context.Background(), &sc.HelmArchive)
+	if err != nil {
+		return nil, fmt.Errorf("error creating helm archive store: %w", err)
+	}
+
+	switch sc.ImageScanner {
+	case "":
+	case "trivy":
+		res.ImageScanner = imagescan.NewTrivyScanner(sc.TrivyPath)
+	case "local":
+		res.ImageScanner = imagescan.NewLocalScanner()
+	default:
+		return nil, fmt.Errorf("unknown image scanner %q: must be one of trivy, local", sc.ImageScanner)
+	}
+
+	switch sc.ImageVerifier {
+	case "":
+	case "cosign":
+		res.ImageVerifier = imagesign.NewCosignVerifier(sc.CosignPath)
+	case "local":
+		res.ImageVerifier = imagesign.NewLocalVerifier()
+	default:
+		return nil, fmt.Errorf("unknown image verifier %q: must be one of cosign, local", sc.ImageVerifier)
+	}
+
 	res.Logger.Info().Msg("Creating URL Cache")
 	res.URLCache = urlcache.Init(sc.DefaultApplicationHelmRepoURL, sc.DefaultAddonHelmRepoURL)
 	res.Logger.Info().Msg("Created URL Cache")
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AppRevisionApproval is a revision of an app which is held until it is approved for its deployment target
+type AppRevisionApproval struct {
+	ID                 uint      `json:"id"`
+	CreatedAt          time.Time `json:"created_at"`
+	DeploymentTargetID uuid.UUID `json:"deployment_target_id"`
+	AppName            string    `json:"app_name"`
+	// AppRevisionID is the id of the revision awaiting approval
+	AppRevisionID string `json:"app_revision_id"`
+	// DeployedAppRevisionID is the id of the revision created once an apply of an image, which is not built, is approved
+	DeployedAppRevisionID string `json:"deployed_app_revision_id,omitempty"`
+	// Status is one of AWAITING_APPROVAL, APPROVED, APPROVAL_REJECTED or APPROVAL_EXPIRED
+	Status string `json:"status"`
+	// AwaitingBuild is true if the revision's image is still to be built, in which case it rolls out once it is
+	// both built and approved
+	AwaitingBuild bool      `json:"awaiting_build"`
+	ExpiresAt     time.Time `json:"expires_at"`
+
+	// RequestedByUserID is set when the apply was made by a user, and RequestedByAPITokenID when it was made
+	// with a project API token
+	RequestedByUserID     uint   `json:"requested_by_user_id,omitempty"`
+	RequestedByAPITokenID string `json:"requested_by_api_token_id,omitempty"`
+
+	DecidedByUserID     uint       `json:"decided_by_user_id,omitempty"`
+	DecidedByAPITokenID string     `json:"decided_by_api_token_id,omitempty"`
+	DecidedAt           *time.Time `json:"decided_at,omitempty"`
+	// Reason is given by whoever approved or rejected the revision
+	Reason string `json:"reason,omitempty"`
+}
+
+// ListAppRevisionApprovalsResponse lists the approvals of an app's revisions, newest first
+type ListAppRevisionApprovalsResponse struct {
+	Approvals []*AppRevisionApproval `json:"approvals"`
+}
@@ -0,0 +1,93 @@
+package types
+
+import "time"
+
+// AuditLogOutcome is the result of an audited request
+type AuditLogOutcome string
+
+const (
+	// AuditLogOutcomeSuccess means the request completed with a non-error status code
+	AuditLogOutcomeSuccess AuditLogOutcome = "success"
+	// AuditLogOutcomeDenied means the request was rejected by authentication or authorization
+	AuditLogOutcomeDenied AuditLogOutcome = "denied"
+	// AuditLogOutcomeFailure means the request was authorized but returned an error
+	AuditLogOutcomeFailure AuditLogOutcome = "failure"
+)
+
+// AuditLogExportFormat is the format that audit logs are exported in
+type AuditLogExportFormat string
+
+const (
+	AuditLogExportFormatJSON AuditLogExportFormat = "json"
+	AuditLogExportFormatCSV  AuditLogExportFormat = "csv"
+)
+
+// AuditLog is a record of a single mutating API request
+type AuditLog struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ProjectID uint      `json:"project_id"`
+
+	// UserID is set when the request was made by a user
+	UserID uint `json:"user_id,omitempty"`
+	// APITokenID is set when the request was made with a project API token
+	APITokenID string `json:"api_token_id,omitempty"`
+
+	Verb   APIVerb  `json:"verb"`
+	Method HTTPVerb `json:"method"`
+	Path   string   `json:"path"`
+
+	// Resources maps each permission scope of the request to the resource it targeted
+	Resources map[PermissionScope]string `json:"resources"`
+
+	// RequestSummary is the request body with secret values redacted
+	RequestSummary string `json:"request_summary,omitempty"`
+
+	StatusCode int             `json:"status_code"`
+	Outcome    AuditLogOutcome `json:"outcome"`
+}
+
+// AuditLogFilter filters the audit logs of a project. It can be parsed from a url using gorilla/schema.
+type AuditLogFilter struct {
+	// Since and Until are RFC 3339 timestamps bounding when the requests were made
+	Since *time.Time `schema:"since"`
+	Until *time.Time `schema:"until"`
+
+	UserID     *uint   `schema:"user_id"`
+	APITokenID *string `schema:"api_token_id"`
+}
+
+// ListAuditLogsRequest is a page of audit logs matching a filter
+type ListAuditLogsRequest struct {
+	PaginationRequest
+	AuditLogFilter
+}
+
+// ListAuditLogsResponse is a page of audit logs
+type ListAuditLogsResponse struct {
+	AuditLogs  []*AuditLog         `json:"audit_logs" form:"required"`
+	Pagination *PaginationResponse `json:"pagination"`
+}
+
+// ExportAuditLogsRequest is every audit log matching a filter, in the given format. The
+// format defaults to json.
+type ExportAuditLogsRequest struct {
+	AuditLogFilter
+
+	Format AuditLogExportFormat `schema:"format"`
+}
+
+// AuditLogSettings are the audit log settings of a project
+type AuditLogSettings struct {
+	// RetentionDays is how many days the audit logs of the project are kept. It is 0 if the project
+	// keeps audit logs for the server's default retention.
+	RetentionDays uint `json:"retention_days"`
+	// DefaultRetentionDays is the server's default retention. A retention of 0 keeps audit logs forever.
+	DefaultRetentionDays uint `json:"default_retention_days"`
+}
+
+// UpdateAuditLogSettingsRequest sets how many days the audit logs of a project are kept. A retention
+// of 0 keeps audit logs for the server's default retention.
+type UpdateAuditLogSettingsRequest struct {
+	RetentionDays uint `json:"retention_days" form:"max=3650"`
+}
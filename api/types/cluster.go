@@ -51,6 +51,12 @@ type Cluster struct {
 	// This was likely the credential that was used to create the cluster.
 	// For AWS EKS clusters, this will be an ARN for the final target role in the assume role chain.
 	CloudProviderCredentialIdentifier string `json:"cloud_provider_credential_identifier"`
+
+	// Whether old revisions of Helm releases in the cluster are archived and deleted
+	MonitorHelmReleases bool `json:"monitor_helm_releases"`
+
+	// The number of revisions of each Helm release kept in the cluster, 0 for the default
+	HelmRevisionsToKeep uint `json:"helm_revisions_to_keep"`
 }
 
 type ClusterCandidate struct {
@@ -296,6 +302,10 @@ type UpdateClusterRequest struct {
 	AgentIntegrationEnabled *bool `json:"agent_integration_enabled"`
 
 	PreviewEnvsEnabled *bool `json:"preview_envs_enabled"`
+
+	MonitorHelmReleases *bool `json:"monitor_helm_releases"`
+
+	HelmRevisionsToKeep *uint `json:"helm_revisions_to_keep"`
 }
 
 type RenameClusterRequest struct {
@@ -307,3 +317,9 @@ type ListClusterResponse []*Cluster
 type CreateClusterCandidateResponse []*ClusterCandidate
 
 type ListClusterCandidateResponse []*ClusterCandidate
+
+// ListNodeArchitecturesResponse is the list of distinct CPU architectures of a cluster's nodes
+type ListNodeArchitecturesResponse struct {
+	// example: ["amd64", "arm64"]
+	Architectures []string `json:"architectures"`
+}
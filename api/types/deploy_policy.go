@@ -0,0 +1,83 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FreezeWindow is a recurring period during which deploys to a deployment target are refused
+type FreezeWindow struct {
+	Name string `json:"name" form:"required"`
+	// Schedule is a five field cron expression for when the window starts, e.g. "0 15 * * FRI"
+	Schedule string `json:"schedule" form:"required"`
+	// Duration is how long the window lasts once it starts, e.g. "9h"
+	Duration string `json:"duration" form:"required"`
+	// Timezone is the IANA time zone the schedule is evaluated in, and defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ActiveFreeze is a freeze window which is currently in effect
+type ActiveFreeze struct {
+	Name   string    `json:"name"`
+	Reason string    `json:"reason,omitempty"`
+	Until  time.Time `json:"until"`
+}
+
+// DeploymentTargetPolicy is the set of rules which deploys to a deployment target must satisfy
+type DeploymentTargetPolicy struct {
+	DeploymentTargetID uuid.UUID      `json:"deployment_target_id"`
+	FreezeWindows      []FreezeWindow `json:"freeze_windows"`
+	// ApproverRole is the policy uid which users or API tokens must be granted to deploy to the target
+	ApproverRole string `json:"approver_role,omitempty"`
+	// AllowedBranches are glob patterns for the git branches deploys may be built from
+	AllowedBranches []string `json:"allowed_branches"`
+	// AllowedSources are glob patterns for the git repositories, in the form owner/name, deploys may be built from
+	AllowedSources []string `json:"allowed_sources"`
+	// RequiresApproval holds applies to the target until they are approved by a user with the approver role, or an admin
+	RequiresApproval bool `json:"requires_approval"`
+	// ApprovalTimeout is how long a revision awaits approval before it expires, e.g. "4h"
+	ApprovalTimeout string `json:"approval_timeout,omitempty"`
+
+	// ActiveFreeze is set if a freeze window is in effect
+	ActiveFreeze *ActiveFreeze `json:"active_freeze,omitempty"`
+}
+
+// UpdateDeploymentTargetPolicyRequest replaces the policy of a deployment target
+type UpdateDeploymentTargetPolicyRequest struct {
+	FreezeWindows    []FreezeWindow `json:"freeze_windows"`
+	ApproverRole     string         `json:"approver_role"`
+	AllowedBranches  []string       `json:"allowed_branches"`
+	AllowedSources   []string       `json:"allowed_sources"`
+	RequiresApproval bool           `json:"requires_approval"`
+	ApprovalTimeout  string         `json:"approval_timeout"`
+}
+
+// DeployPolicyViolation is a rule of a deployment target's policy which a deploy did not satisfy
+type DeployPolicyViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// DeployPolicyOverride records a deploy which broke the glass to go out despite violating the policy of its deployment target
+type DeployPolicyOverride struct {
+	ID                 uint      `json:"id"`
+	CreatedAt          time.Time `json:"created_at"`
+	DeploymentTargetID uuid.UUID `json:"deployment_target_id"`
+	AppName            string    `json:"app_name"`
+	// Action is the kind of deploy: apply, update-image, rollback or promote
+	Action string `json:"action"`
+
+	// UserID is set when the deploy was made by a user, and APITokenID when it was made with a project API token
+	UserID     uint   `json:"user_id,omitempty"`
+	APITokenID string `json:"api_token_id,omitempty"`
+
+	Reason     string                  `json:"reason"`
+	Violations []DeployPolicyViolation `json:"violations"`
+}
+
+// ListDeployPolicyOverridesResponse lists the policy overrides of a deployment target, newest first
+type ListDeployPolicyOverridesResponse struct {
+	Overrides []*DeployPolicyOverride `json:"overrides"`
+}
@@ -16,6 +16,7 @@ type DeploymentTarget struct {
 	Namespace    string    `json:"namespace"`
 	IsPreview    bool      `json:"is_preview"`
 	IsDefault    bool      `json:"is_default"`
+	IsProtected  bool      `json:"is_protected"`
 	CreatedAtUTC time.Time `json:"created_at"`
 	UpdatedAtUTC time.Time `json:"updated_at"`
 }
@@ -50,6 +51,17 @@ type CreateDeploymentTargetResponse struct {
 	DeploymentTargetID string `json:"deployment_target_id"`
 }
 
+// UpdateDeploymentTargetRequest is the request object for the /targets/{deployment_target_identifier} PATCH endpoint
+type UpdateDeploymentTargetRequest struct {
+	// Protected requires deploys to the target to meet the project's image trust policy
+	Protected *bool `json:"protected,omitempty"`
+}
+
+// UpdateDeploymentTargetResponse is the response object for the /targets/{deployment_target_identifier} PATCH endpoint
+type UpdateDeploymentTargetResponse struct {
+	DeploymentTarget DeploymentTarget `json:"deployment_target"`
+}
+
 // ListDeploymentTargetsRequest is the request object for the /deployment-targets GET endpoint
 type ListDeploymentTargetsRequest struct {
 	Preview bool `json:"preview"`
@@ -0,0 +1,92 @@
+package types
+
+import "time"
+
+// EnvGroupProviderType is an external secrets manager whose secrets Porter syncs into an environment group
+type EnvGroupProviderType string
+
+const (
+	// EnvGroupProviderTypeVault syncs the keys of a HashiCorp Vault KV v2 secret
+	EnvGroupProviderTypeVault EnvGroupProviderType = "vault"
+	// EnvGroupProviderTypeAWSSecretsManager syncs the keys of an AWS Secrets Manager secret
+	EnvGroupProviderTypeAWSSecretsManager EnvGroupProviderType = "aws-secrets-manager"
+)
+
+// VaultAuthMethod is how Porter authenticates with Vault
+type VaultAuthMethod string
+
+const (
+	// VaultAuthMethodToken authenticates with the token stored as the password of a basic integration
+	VaultAuthMethodToken VaultAuthMethod = "token"
+	// VaultAuthMethodAppRole logs in with the role ID and secret ID stored as the username and password of a basic integration
+	VaultAuthMethodAppRole VaultAuthMethod = "approle"
+)
+
+// AWSSecretsManagerAuthMethod is how Porter authenticates with AWS Secrets Manager
+type AWSSecretsManagerAuthMethod string
+
+const (
+	// AWSSecretsManagerAuthMethodAccessKey uses the credentials of an AWS integration
+	AWSSecretsManagerAuthMethodAccessKey AWSSecretsManagerAuthMethod = "access_key"
+	// AWSSecretsManagerAuthMethodAssumeRole assumes a role with the credentials of an AWS integration
+	AWSSecretsManagerAuthMethodAssumeRole AWSSecretsManagerAuthMethod = "assume_role"
+)
+
+const (
+	// DefaultEnvGroupProviderRefreshIntervalSeconds is how often an environment group is synced if no refresh interval is set
+	DefaultEnvGroupProviderRefreshIntervalSeconds = 3600
+	// MinEnvGroupProviderRefreshIntervalSeconds is the shortest refresh interval, so that providers are not rate limited
+	MinEnvGroupProviderRefreshIntervalSeconds = 60
+)
+
+// VaultEnvGroupProviderConfig is a secret in a Vault KV v2 secrets engine
+type VaultEnvGroupProviderConfig struct {
+	// Address is the https URL of the Vault server, which must be reachable at a public address
+	// example: https://vault.example.com:8200
+	Address string `json:"address"`
+	// Namespace is the Vault Enterprise namespace of the secret, if any
+	Namespace string `json:"namespace,omitempty"`
+	// MountPath is the path the KV v2 secrets engine is mounted at
+	// example: secret
+	MountPath string `json:"mount_path"`
+	// Path is the path of the secret in the secrets engine
+	// example: apps/web/production
+	Path       string          `json:"path"`
+	AuthMethod VaultAuthMethod `json:"auth_method"`
+	// BasicIntegrationID is the basic integration holding the credentials of the auth method
+	BasicIntegrationID uint `json:"basic_integration_id"`
+}
+
+// AWSSecretsManagerEnvGroupProviderConfig is a secret in AWS Secrets Manager. Secrets which hold a JSON object
+// are synced as one key per field, and other secrets are synced as a single key named after the secret.
+type AWSSecretsManagerEnvGroupProviderConfig struct {
+	// example: us-east-1
+	Region string `json:"region"`
+	// SecretID is the name or ARN of the secret
+	// example: prod/web
+	SecretID   string                      `json:"secret_id"`
+	AuthMethod AWSSecretsManagerAuthMethod `json:"auth_method"`
+	// AWSIntegrationID is the AWS integration whose credentials are used
+	AWSIntegrationID uint `json:"aws_integration_id"`
+	// RoleARN is the role assumed by the assume_role auth method
+	RoleARN string `json:"role_arn,omitempty"`
+}
+
+// EnvGroupProvider is the external secrets manager an environment group is synced from
+type EnvGroupProvider struct {
+	EnvGroupName      string                                   `json:"env_group_name"`
+	Type              EnvGroupProviderType                     `json:"type"`
+	Vault             *VaultEnvGroupProviderConfig             `json:"vault,omitempty"`
+	AWSSecretsManager *AWSSecretsManagerEnvGroupProviderConfig `json:"aws_secrets_manager,omitempty"`
+	// KeyMappings renames the keys of the secret to variable names. Keys which are not mapped keep their name.
+	KeyMappings            map[string]string `json:"key_mappings,omitempty"`
+	RefreshIntervalSeconds int               `json:"refresh_interval_seconds"`
+	LastSyncedAt           *time.Time        `json:"last_synced_at,omitempty"`
+	LastSyncError          string            `json:"last_sync_error,omitempty"`
+}
+
+// EnvGroupProviderPreviewKey is a key of an external secret and the variable it is synced to
+type EnvGroupProviderPreviewKey struct {
+	ProviderKey string `json:"provider_key"`
+	Variable    string `json:"variable"`
+}
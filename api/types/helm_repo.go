@@ -10,6 +10,9 @@ type HelmRepo struct {
 	Name string `json:"name"`
 
 	RepoURL string `json:"repo_name"`
+
+	// RegistryID is the registry whose credentials are used for oci:// repos
+	RegistryID uint `json:"registry_id,omitempty"`
 }
 
 type GetHelmRepoResponse HelmRepo
@@ -18,4 +21,7 @@ type CreateUpdateHelmRepoRequest struct {
 	URL                string `json:"url" form:"required"`
 	Name               string `json:"name" form:"required"`
 	BasicIntegrationID uint   `json:"basic_integration_id"`
+
+	// RegistryID is the registry whose credentials are used to pull charts from an oci:// URL
+	RegistryID uint `json:"registry_id"`
 }
@@ -0,0 +1,76 @@
+package types
+
+import "time"
+
+// ImageScanPolicyAction is what a project's image scan policy does when a deployed image
+// has vulnerabilities at or above the policy's severity threshold
+type ImageScanPolicyAction string
+
+const (
+	ImageScanPolicyActionOff   ImageScanPolicyAction = "off"
+	ImageScanPolicyActionWarn  ImageScanPolicyAction = "warn"
+	ImageScanPolicyActionBlock ImageScanPolicyAction = "block"
+)
+
+// ImageVulnerability is a vulnerability found in a package installed in an image
+type ImageVulnerability struct {
+	// example: CVE-2023-4863
+	ID               string `json:"id"`
+	Package          string `json:"package"`
+	InstalledVersion string `json:"installed_version"`
+	FixedVersion     string `json:"fixed_version,omitempty"`
+	// enum: unknown,low,medium,high,critical
+	Severity string `json:"severity"`
+	Title    string `json:"title,omitempty"`
+}
+
+// ImageScanSummary counts the vulnerabilities of each severity found by the latest scan of an image
+type ImageScanSummary struct {
+	Repository string    `json:"repository"`
+	Tag        string    `json:"tag,omitempty"`
+	Digest     string    `json:"digest"`
+	Scanner    string    `json:"scanner"`
+	ScannedAt  time.Time `json:"scanned_at"`
+
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Unknown  int `json:"unknown"`
+}
+
+// ImageScan is the result of scanning an image for vulnerabilities
+type ImageScan struct {
+	ImageScanSummary
+
+	Vulnerabilities []ImageVulnerability `json:"vulnerabilities"`
+}
+
+// ScanImageRequest scans an image of a registry by digest or, if the digest isn't known, by tag
+type ScanImageRequest struct {
+	// example: 123456789012.dkr.ecr.us-east-2.amazonaws.com/web
+	Repository string `json:"repository" form:"required"`
+	Tag        string `json:"tag" form:"required_without=Digest"`
+	Digest     string `json:"digest"`
+}
+
+// GetImageScanRequest reads the latest scan of an image by digest or, if the digest isn't known, by tag
+type GetImageScanRequest struct {
+	Repository string `schema:"repository" form:"required_without=Digest"`
+	Tag        string `schema:"tag" form:"required_without=Digest"`
+	Digest     string `schema:"digest"`
+}
+
+// ImageScanPolicy decides whether images may be deployed in a project based on the vulnerabilities
+// found in them
+type ImageScanPolicy struct {
+	Action ImageScanPolicyAction `json:"action"`
+	// SeverityThreshold is the lowest severity of the vulnerabilities which the policy acts on
+	// enum: low,medium,high,critical
+	SeverityThreshold string `json:"severity_threshold"`
+}
+
+type UpdateImageScanPolicyRequest struct {
+	Action            ImageScanPolicyAction `json:"action" form:"required,oneof=off warn block"`
+	SeverityThreshold string                `json:"severity_threshold" form:"required,oneof=low medium high critical"`
+}
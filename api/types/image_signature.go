@@ -0,0 +1,47 @@
+package types
+
+import "time"
+
+// TrustedPublicKey is a cosign public key which a project trusts to sign images
+type TrustedPublicKey struct {
+	Name string `json:"name" form:"required"`
+	// PEM is the PEM encoded public key
+	PEM string `json:"pem" form:"required"`
+}
+
+// TrustedIdentity is a keyless signing identity which a project trusts to sign images
+type TrustedIdentity struct {
+	// example: https://token.actions.githubusercontent.com
+	Issuer string `json:"issuer" form:"required"`
+	// Subject is a regular expression which must match the whole subject of the signing certificate
+	// example: ^https://github\.com/acme/.*@refs/heads/main$
+	Subject string `json:"subject" form:"required"`
+}
+
+// ImageTrustPolicy lists the signers whose image signatures a project trusts. Deploys to protected
+// deployment targets are refused unless the image has a valid signature from one of the signers.
+type ImageTrustPolicy struct {
+	PublicKeys []TrustedPublicKey `json:"public_keys"`
+	Identities []TrustedIdentity  `json:"identities"`
+	// Attestations are the predicate types which a trusted signer must have attested to
+	// example: ["slsaprovenance"]
+	Attestations []string `json:"attestations"`
+}
+
+type UpdateImageTrustPolicyRequest struct {
+	PublicKeys   []TrustedPublicKey `json:"public_keys" form:"dive"`
+	Identities   []TrustedIdentity  `json:"identities" form:"dive"`
+	Attestations []string           `json:"attestations"`
+}
+
+// ImageVerification records the signature verification of the image deployed by an app revision
+type ImageVerification struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	// Digest is the digest of the verified image, which the revision is pinned to
+	Digest string `json:"digest"`
+	// Signer is the name of the public key or the subject of the identity which signed the image
+	Signer       string    `json:"signer"`
+	Attestations []string  `json:"attestations"`
+	VerifiedAt   time.Time `json:"verified_at"`
+}
@@ -0,0 +1,42 @@
+package types
+
+import "time"
+
+const URLParamKeyRotationID URLParam = "key_rotation_id"
+
+// KeyRotationStatus is the status of an encryption key rotation
+type KeyRotationStatus string
+
+const (
+	// KeyRotationStatusRunning means the rotation is in progress, or was interrupted and can be resumed
+	KeyRotationStatusRunning KeyRotationStatus = "running"
+	// KeyRotationStatusCompleted means every table was rotated, although some records may have failed
+	KeyRotationStatusCompleted KeyRotationStatus = "completed"
+	// KeyRotationStatusFailed means the rotation stopped on an error which was not specific to a record
+	KeyRotationStatusFailed KeyRotationStatus = "failed"
+)
+
+// KeyRotation is the progress of an encryption key rotation
+type KeyRotation struct {
+	ID          uint              `json:"id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+	Status      KeyRotationStatus `json:"status"`
+	Error       string            `json:"error,omitempty"`
+
+	// Tables contains the progress of each encrypted table
+	Tables []*KeyRotationTable `json:"tables"`
+}
+
+// KeyRotationTable is the progress of a key rotation through a single encrypted table
+type KeyRotationTable struct {
+	Table     string `json:"table"`
+	Total     int64  `json:"total"`
+	Rotated   int64  `json:"rotated"`
+	Skipped   int64  `json:"skipped"`
+	Failed    int64  `json:"failed"`
+	LastID    uint   `json:"last_id"`
+	LastError string `json:"last_error,omitempty"`
+	Completed bool   `json:"completed"`
+}
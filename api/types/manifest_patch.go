@@ -0,0 +1,94 @@
+package types
+
+import "time"
+
+const URLParamManifestPatchID URLParam = "manifest_patch_id"
+
+// ManifestPatchType is the kind of kustomize patch a ManifestPatch applies
+type ManifestPatchType string
+
+const (
+	// ManifestPatchTypeStrategicMerge patches are partial Kubernetes objects which are merged
+	// into the targeted objects, e.g. to add a sidecar container
+	ManifestPatchTypeStrategicMerge ManifestPatchType = "strategic_merge"
+
+	// ManifestPatchTypeJSON6902 patches are lists of RFC 6902 JSON patch operations
+	ManifestPatchTypeJSON6902 ManifestPatchType = "json6902"
+)
+
+// ManifestPatchTarget selects the objects which a patch applies to. Empty fields match
+// every object, and Name and Namespace are regular expressions.
+type ManifestPatchTarget struct {
+	Group              string `json:"group,omitempty"`
+	Version            string `json:"version,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+	Name               string `json:"name,omitempty"`
+	Namespace          string `json:"namespace,omitempty"`
+	LabelSelector      string `json:"label_selector,omitempty"`
+	AnnotationSelector string `json:"annotation_selector,omitempty"`
+}
+
+// ManifestPatch is a kustomize patch which is applied to the rendered manifests of the
+// releases of a project, after Porter's own post-renderers. Patches are applied in order of
+// their priority, then of their creation.
+//
+// A patch which selects neither an app nor a chart applies only to the releases of Porter's
+// application charts (web, worker and job), so that add-ons and the charts Porter installs in
+// its clusters are only patched when they are selected explicitly.
+type ManifestPatch struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ProjectID uint      `json:"project_id"`
+	Name      string    `json:"name"`
+
+	// AppName limits the patch to the releases with this name
+	AppName string `json:"app_name,omitempty"`
+	// ChartName limits the patch to the releases of this chart
+	ChartName string `json:"chart_name,omitempty"`
+	// ClusterID limits the patch to the releases in this cluster. The patch applies in every
+	// cluster of the project when it is 0.
+	ClusterID uint `json:"cluster_id,omitempty"`
+	Priority  int  `json:"priority"`
+
+	Type   ManifestPatchType   `json:"type"`
+	Target ManifestPatchTarget `json:"target"`
+	Patch  string              `json:"patch"`
+}
+
+type CreateManifestPatchRequest struct {
+	Name      string              `json:"name" form:"required"`
+	AppName   string              `json:"app_name"`
+	ChartName string              `json:"chart_name"`
+	ClusterID uint                `json:"cluster_id"`
+	Priority  int                 `json:"priority"`
+	Type      ManifestPatchType   `json:"type" form:"required,oneof=strategic_merge json6902"`
+	Target    ManifestPatchTarget `json:"target"`
+	Patch     string              `json:"patch" form:"required"`
+}
+
+type UpdateManifestPatchRequest struct {
+	AppName   *string              `json:"app_name"`
+	ChartName *string              `json:"chart_name"`
+	ClusterID *uint                `json:"cluster_id"`
+	Priority  *int                 `json:"priority"`
+	Target    *ManifestPatchTarget `json:"target"`
+	Patch     *string              `json:"patch"`
+}
+
+type ListManifestPatchesResponse struct {
+	ManifestPatches []*ManifestPatch `json:"manifest_patches"`
+}
+
+// PreviewManifestPatchesRequest renders a release with its current chart and values
+// without deploying it. The saved patches of the project are applied, followed by Patches,
+// which allows previewing patches before saving them.
+type PreviewManifestPatchesRequest struct {
+	Patches []CreateManifestPatchRequest `json:"patches"`
+
+	// SkipSavedPatches renders the release without the saved patches of the project
+	SkipSavedPatches bool `json:"skip_saved_patches"`
+}
+
+type PreviewManifestPatchesResponse struct {
+	Manifest string `json:"manifest"`
+}
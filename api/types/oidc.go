@@ -0,0 +1,134 @@
+package types
+
+import "time"
+
+const (
+	// GithubActionsOIDCIssuer is the issuer of the ID tokens minted for GitHub Actions jobs
+	GithubActionsOIDCIssuer = "https://token.actions.githubusercontent.com"
+
+	// GitlabOIDCIssuer is the issuer of the ID tokens minted for GitLab.com CI jobs
+	GitlabOIDCIssuer = "https://gitlab.com"
+
+	// DefaultOIDCTokenTTL is the lifetime of exchanged tokens when a trust rule does not set one
+	DefaultOIDCTokenTTL = 15 * time.Minute
+
+	// MaxOIDCTokenTTL is the longest lifetime that a trust rule may grant exchanged tokens
+	MaxOIDCTokenTTL = time.Hour
+)
+
+const URLParamOIDCTrustRuleID URLParam = "oidc_trust_rule_id"
+
+// OIDCTrustRule permits CI jobs whose ID tokens match its issuer, audience and subject to
+// exchange them for short-lived Porter tokens which can only deploy a single app to a
+// single deployment target
+type OIDCTrustRule struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ProjectID uint      `json:"project_id"`
+	Name      string    `json:"name"`
+
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+
+	// SubjectPattern is matched against the subject claim of the ID token, where "*"
+	// matches any sequence of characters, e.g. "repo:porter-dev/porter:ref:refs/heads/*"
+	SubjectPattern string `json:"subject_pattern"`
+
+	ClusterID        uint   `json:"cluster_id"`
+	DeploymentTarget string `json:"deployment_target"`
+	AppName          string `json:"app_name"`
+
+	PolicyUID       string `json:"policy_uid"`
+	TokenTTLSeconds uint   `json:"token_ttl_seconds"`
+}
+
+type CreateOIDCTrustRuleRequest struct {
+	Name           string `json:"name" form:"required"`
+	Issuer         string `json:"issuer" form:"required,url"`
+	Audience       string `json:"audience" form:"required"`
+	SubjectPattern string `json:"subject_pattern" form:"required"`
+
+	ClusterID        uint   `json:"cluster_id" form:"required"`
+	DeploymentTarget string `json:"deployment_target" form:"required"`
+	AppName          string `json:"app_name" form:"required"`
+
+	// TokenTTLSeconds defaults to DefaultOIDCTokenTTL and may not exceed MaxOIDCTokenTTL
+	TokenTTLSeconds uint `json:"token_ttl_seconds"`
+}
+
+type ListOIDCTrustRulesResponse struct {
+	TrustRules []*OIDCTrustRule `json:"trust_rules"`
+}
+
+// OIDCTokenExchangeRequest exchanges a CI provider's ID token for a Porter token. When
+// several trust rules of the project match the ID token, the app and deployment target
+// select between them.
+type OIDCTokenExchangeRequest struct {
+	ProjectID        uint   `json:"project_id" form:"required"`
+	IDToken          string `json:"id_token" form:"required"`
+	AppName          string `json:"app_name"`
+	DeploymentTarget string `json:"deployment_target"`
+}
+
+type OIDCTokenExchangeResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OIDCTrustRulePolicy is the policy of tokens exchanged through a trust rule. It permits
+// reading the project and cluster, pushing images, and deploying the app to the deployment
+// target, but not writing to the project or cluster themselves, deleting anything, reading
+// env group secrets or touching other clusters.
+func OIDCTrustRulePolicy(clusterID uint, deploymentTarget, appName string) []*PolicyDocument {
+	deployVerbs := []APIVerb{APIVerbGet, APIVerbList, APIVerbCreate, APIVerbUpdate}
+
+	return []*PolicyDocument{
+		{
+			Scope: ProjectScope,
+			Verbs: ReadVerbGroup(),
+			Children: map[PermissionScope]*PolicyDocument{
+				ClusterScope: {
+					Scope:     ClusterScope,
+					Resources: []NameOrUInt{{UInt: clusterID}},
+					Verbs:     ReadVerbGroup(),
+					Children: map[PermissionScope]*PolicyDocument{
+						NamespaceScope: {
+							Scope: NamespaceScope,
+							Verbs: ReadVerbGroup(),
+						},
+						PreviewEnvironmentScope: {
+							Scope: PreviewEnvironmentScope,
+							Verbs: ReadVerbGroup(),
+						},
+						DeploymentTargetScope: {
+							Scope:     DeploymentTargetScope,
+							Resources: []NameOrUInt{{Name: deploymentTarget}},
+							Verbs:     deployVerbs,
+							Children: map[PermissionScope]*PolicyDocument{
+								PorterAppScope: {
+									Scope:     PorterAppScope,
+									Resources: []NameOrUInt{{Name: appName}},
+									Verbs:     deployVerbs,
+								},
+							},
+						},
+						EnvGroupScope: {
+							Scope: EnvGroupScope,
+							Verbs: ReadVerbGroup(),
+							Children: map[PermissionScope]*PolicyDocument{
+								EnvGroupSecretsScope: {
+									Scope: EnvGroupSecretsScope,
+									Verbs: []APIVerb{},
+								},
+							},
+						},
+					},
+				},
+				RegistryScope: {
+					Scope: RegistryScope,
+					Verbs: []APIVerb{APIVerbGet, APIVerbList, APIVerbCreate},
+				},
+			},
+		},
+	}
+}
@@ -22,6 +22,13 @@ const (
 	GitlabIntegrationScope   PermissionScope = "gitlab_integration"
 	PreviewEnvironmentScope  PermissionScope = "preview_environment"
 	APIContractRevisionScope PermissionScope = "contract_revision"
+	PorterAppScope           PermissionScope = "porter_app"
+	EnvGroupScope            PermissionScope = "env_group"
+
+	// EnvGroupSecretsScope governs reading the secret values of an env group. It is never
+	// declared by an endpoint: handlers which return secret values check it separately, so
+	// that a policy can grant access to an env group while withholding its secrets.
+	EnvGroupSecretsScope PermissionScope = "env_group_secrets"
 )
 
 type NameOrUInt struct {
@@ -29,6 +36,9 @@ type NameOrUInt struct {
 	UInt uint   `json:"uint"`
 }
 
+// PolicyDocument grants verbs on the resources of a scope, and on its children. Resource
+// names may be glob patterns as understood by path.Match, so a document can grant access
+// to every app named "api-*" or every deployment target named "staging*".
 type PolicyDocument struct {
 	Scope     PermissionScope                     `json:"scope"`
 	Resources []NameOrUInt                        `json:"resources"`
@@ -51,6 +61,12 @@ var ScopeHeirarchy = ScopeTree{
 				ReleaseScope: {},
 			},
 			PreviewEnvironmentScope: {},
+			DeploymentTargetScope: {
+				PorterAppScope: {},
+			},
+			EnvGroupScope: {
+				EnvGroupSecretsScope: {},
+			},
 		},
 		RegistryScope:        {},
 		HelmRepoScope:        {},
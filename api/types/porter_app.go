@@ -120,6 +120,8 @@ const (
 	PorterAppEventType_AppEvent PorterAppEventType = "APP_EVENT"
 	// PorterAppEventType_Notification represents a translation of the porter agent app event into the new notification format, which details everything that occurs while the app is running
 	PorterAppEventType_Notification PorterAppEventType = "NOTIFICATION"
+	// PorterAppEventType_Promotion represents a revision being promoted from one deployment target to another. It is recorded in both targets
+	PorterAppEventType_Promotion PorterAppEventType = "PROMOTION"
 )
 
 // PorterAppEventStatus is an alias for a string that represents a Porter Stack Event Status
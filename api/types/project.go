@@ -147,6 +147,9 @@ type ListCollaboratorsResponse []*Collaborator
 type UpdateRoleRequest struct {
 	UserID uint   `json:"user_id,required"`
 	Kind   string `json:"kind,required"`
+
+	// PolicyUID is the uid of the project policy to grant, and is required for custom roles
+	PolicyUID string `json:"policy_uid"`
 }
 
 // UpdateRoleResponse is a struct that contains the response from a `POST projects/{project_id}/roles` request
@@ -26,7 +26,7 @@ type Registry struct {
 	URL string `json:"url"`
 
 	// The integration service for this registry
-	// enum: gcr,gar,ecr,acr,docr,dockerhub
+	// enum: gcr,gar,ecr,acr,docr,dockerhub,harbor,quay,ghcr
 	// example: ecr
 	Service string `json:"service"`
 
@@ -60,6 +60,11 @@ type Registry struct {
 	// minimum: 1
 	// example: 0
 	BasicIntegrationID uint `json:"basic_integration_id,omitempty"`
+
+	// The GitHub App installation used to access a GHCR registry
+	// minimum: 1
+	// example: 0
+	GithubAppInstallationID int64 `json:"github_app_installation_id,omitempty"`
 }
 
 // Repository is a collection of images
@@ -90,6 +95,9 @@ type Image struct {
 
 	// When the image was pushed
 	PushedAt *time.Time `json:"pushed_at"`
+
+	// The vulnerabilities found by the latest scan of the image, if it was scanned
+	Vulnerabilities *ImageScanSummary `json:"vulnerabilities,omitempty"`
 }
 
 // Type of registry service
@@ -102,6 +110,9 @@ const (
 	ACR       RegistryService = "acr"
 	DOCR      RegistryService = "docr"
 	DockerHub RegistryService = "dockerhub"
+	Harbor    RegistryService = "harbor"
+	Quay      RegistryService = "quay"
+	GHCR      RegistryService = "ghcr"
 )
 
 // swagger:model ListRegistriesResponse
@@ -150,6 +161,18 @@ type CreateRegistryRequest struct {
 
 	// ACR name (**Azure only**)
 	ACRName string `json:"acr_name"`
+
+	// The registry service, for services which are accessed with a basic integration or a GitHub App
+	// installation rather than a cloud provider integration. Harbor and Quay registries are accessed
+	// with a robot account stored in a basic integration.
+	// enum: harbor,quay,ghcr
+	// example: harbor
+	Service string `json:"service" form:"omitempty,oneof=harbor quay ghcr"`
+
+	// The GitHub App installation used to access the packages of a GitHub organization (**GHCR only**)
+	// minimum: 1
+	// example: 0
+	GithubAppInstallationID int64 `json:"github_app_installation_id"`
 }
 
 // swagger:model
@@ -193,6 +216,12 @@ type GetRegistryECRTokenRequest struct {
 	AccountID string `schema:"account_id"`
 }
 
+// GetRegistryTokenRequest gets the credentials of a Harbor, Quay, GHCR or private registry by its host
+type GetRegistryTokenRequest struct {
+	// example: harbor.example.com
+	ServerURL string `schema:"server_url" form:"required"`
+}
+
 type GetRegistryDOCRTokenRequest struct {
 	ServerURL string `schema:"server_url"`
 }
@@ -0,0 +1,115 @@
+package types
+
+import "time"
+
+// RegistryRetentionPolicy decides which images in a registry repository are kept when the repository is
+// garbage collected. An image is kept if any of the rules keeps it, and images deployed by the latest
+// revision of an app are always kept.
+type RegistryRetentionPolicy struct {
+	ID         uint   `json:"id"`
+	RegistryID uint   `json:"registry_id"`
+	Repository string `json:"repository"`
+
+	// KeepLast keeps the most recently pushed images
+	KeepLast int `json:"keep_last"`
+	// KeepDeployedWithinDays keeps images deployed by an app revision created within the number of days
+	KeepDeployedWithinDays int `json:"keep_deployed_within_days"`
+	// KeepTagPatterns keeps images with a tag matching one of the glob patterns
+	// example: ["v*", "release-*"]
+	KeepTagPatterns []string `json:"keep_tag_patterns"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UpdateRegistryRetentionPolicyRequest creates or replaces the retention policy of a repository
+type UpdateRegistryRetentionPolicyRequest struct {
+	// example: web
+	Repository             string   `json:"repository" form:"required"`
+	KeepLast               int      `json:"keep_last" form:"min=0"`
+	KeepDeployedWithinDays int      `json:"keep_deployed_within_days" form:"min=0"`
+	KeepTagPatterns        []string `json:"keep_tag_patterns"`
+}
+
+// DeleteRegistryRetentionPolicyRequest deletes the retention policy of a repository
+type DeleteRegistryRetentionPolicyRequest struct {
+	Repository string `schema:"repository" form:"required"`
+}
+
+// ListRegistryRetentionPoliciesResponse lists the retention policies of a registry's repositories
+type ListRegistryRetentionPoliciesResponse []*RegistryRetentionPolicy
+
+// RegistryGCRunStatus is the status of a garbage collection run
+type RegistryGCRunStatus string
+
+const (
+	// RegistryGCRunStatusPlanned is the status of a dry run, which can be executed once reviewed
+	RegistryGCRunStatusPlanned RegistryGCRunStatus = "planned"
+	// RegistryGCRunStatusQueued is the status of a run waiting for the registry garbage collection job
+	RegistryGCRunStatusQueued    RegistryGCRunStatus = "queued"
+	RegistryGCRunStatusRunning   RegistryGCRunStatus = "running"
+	RegistryGCRunStatusCompleted RegistryGCRunStatus = "completed"
+	RegistryGCRunStatusFailed    RegistryGCRunStatus = "failed"
+)
+
+// RetainedImage is an image which a garbage collection run keeps, and the reason why
+type RetainedImage struct {
+	Tag      string     `json:"tag"`
+	Digest   string     `json:"digest"`
+	PushedAt *time.Time `json:"pushed_at,omitempty"`
+	// example: deployed
+	Reason string `json:"reason"`
+}
+
+// RegistryManifest is an image manifest which a garbage collection run deletes, along with all of its tags
+type RegistryManifest struct {
+	Digest   string     `json:"digest"`
+	Tags     []string   `json:"tags"`
+	PushedAt *time.Time `json:"pushed_at,omitempty"`
+}
+
+// RegistryGCDeletion records the deletion of a manifest by a garbage collection run
+type RegistryGCDeletion struct {
+	Digest string   `json:"digest"`
+	Tags   []string `json:"tags"`
+	// DeletedAt is unset if the manifest could not be deleted
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Error is why the manifest could not be deleted
+	Error string `json:"error,omitempty"`
+}
+
+// RegistryGCRun is a garbage collection run of a repository. Runs are created as dry runs which plan the
+// images to delete, and delete the images once executed.
+type RegistryGCRun struct {
+	ID         uint                `json:"id"`
+	RegistryID uint                `json:"registry_id"`
+	Repository string              `json:"repository"`
+	Status     RegistryGCRunStatus `json:"status"`
+
+	// Policy is the retention policy the run was planned with
+	Policy RegistryRetentionPolicy `json:"policy"`
+
+	Keep   []RetainedImage    `json:"keep"`
+	Delete []RegistryManifest `json:"delete"`
+
+	// Deleted is the audit of the manifests deleted by the run
+	Deleted []RegistryGCDeletion `json:"deleted"`
+	Error   string               `json:"error,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// CreateRegistryGCRunRequest plans a garbage collection run of a repository with its retention policy
+type CreateRegistryGCRunRequest struct {
+	// example: web
+	Repository string `json:"repository" form:"required"`
+}
+
+// ListRegistryGCRunsRequest lists the garbage collection runs of a registry, optionally of one repository
+type ListRegistryGCRunsRequest struct {
+	Repository string `schema:"repository"`
+}
+
+// ListRegistryGCRunsResponse lists garbage collection runs, most recent first
+type ListRegistryGCRunsResponse []*RegistryGCRun
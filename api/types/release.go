@@ -107,6 +107,18 @@ type RollbackReleaseRequest struct {
 	Revision int `json:"revision" form:"required"`
 }
 
+// ListArchivedRevisionsResponse is the revision numbers of a release which were pruned from the
+// cluster and archived, from the newest
+type ListArchivedRevisionsResponse struct {
+	Revisions []int `json:"revisions"`
+}
+
+// RestoreArchivedRevisionRequest restores an archived revision of a release into the cluster,
+// so that the release can be rolled back to it
+type RestoreArchivedRevisionRequest struct {
+	Revision int `json:"revision" form:"required"`
+}
+
 // swagger:model UpdateReleaseRequest
 type V1UpgradeReleaseRequest struct {
 	// The Helm values to upgrade the release with
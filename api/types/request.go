@@ -66,6 +66,7 @@ const (
 	URLParamDeploymentTargetIdentifier URLParam = "deployment_target_identifier"
 	URLParamWebhookID                  URLParam = "webhook_id"
 	URLParamJobRunName                 URLParam = "job_run_name"
+	URLParamRegistryGCRunID            URLParam = "gc_run_id"
 )
 
 type Path struct {
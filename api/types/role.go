@@ -13,4 +13,7 @@ type Role struct {
 	Kind      RoleKind `json:"kind"`
 	UserID    uint     `json:"user_id"`
 	ProjectID uint     `json:"project_id"`
+
+	// PolicyUID is the uid of the project policy that a custom role is granted
+	PolicyUID string `json:"policy_uid,omitempty"`
 }
@@ -42,6 +42,7 @@ func RegisterCommands() (*cobra.Command, error) {
 	rootCmd.AddCommand(registerCommand_Open(cliConf))
 	rootCmd.AddCommand(registerCommand_Project(cliConf))
 	rootCmd.AddCommand(registerCommand_Registry(cliConf))
+	rootCmd.AddCommand(registerCommand_Revision(cliConf))
 	rootCmd.AddCommand(registerCommand_Run(cliConf))
 	rootCmd.AddCommand(registerCommand_Server(cliConf))
 	rootCmd.AddCommand(registerCommand_Stack(cliConf))
@@ -49,6 +50,7 @@ func RegisterCommands() (*cobra.Command, error) {
 	rootCmd.AddCommand(registerCommand_Update(cliConf))
 	rootCmd.AddCommand(registerCommand_Version(cliConf))
 	rootCmd.AddCommand(registerCommand_Env(cliConf))
+	rootCmd.AddCommand(registerCommand_EnvGroup(cliConf))
 	rootCmd.AddCommand(registerCommand_Datastore(cliConf))
 	return rootCmd, nil
 }
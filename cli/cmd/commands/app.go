@@ -34,16 +34,26 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/yaml"
 )
 
 var (
+	appApprovalReason    string
+	appApprovalRevision  string
+	appBreakGlassReason  string
 	appDeployMethod      string
+	appFailOnDrift       bool
 	appContainerName     string
 	appCpuMilli          int
 	appExistingPod       bool
 	appInteractive       bool
 	appMemoryMi          int
 	appNamespace         string
+	appPromoteFrom       string
+	appPromoteTo         string
+	appPromoteOverrides  string
+	appPromotePredeploy  bool
+	appPromoteYes        bool
 	appTag               string
 	appVerbose           bool
 	appWait              bool
@@ -135,11 +145,18 @@ To use buildpacks with the "--method pack" flag, you can specify the builder and
 buildpacks using the --builder and --attach-buildpacks flags:
 
 	%s
+
+To cache layers between builds, build with a remote BuildKit daemon or build for multiple platforms,
+set the cache, buildkit and platforms settings in the build block of a porter.yaml and pass it with
+the --file flag:
+
+	%s
 `,
 			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app build\":"),
 			color.New(color.FgGreen, color.Bold).Sprintf("porter app build example --build-context ./app"),
 			color.New(color.FgGreen, color.Bold).Sprintf("porter app build example-app --method docker --dockerfile ./prod.Dockerfile"),
 			color.New(color.FgGreen, color.Bold).Sprintf("porter app build example-app --method pack --builder heroku/buildpacks:20 --attach-buildpacks heroku/nodejs"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app build example-app -f porter.yaml"),
 		),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return checkLoginAndRunWithConfig(cmd, cliConf, args, appBuild)
@@ -156,6 +173,7 @@ buildpacks using the --builder and --attach-buildpacks flags:
 		false,
 		"do not pull the previous image before building",
 	)
+	appBuildCommand.PersistentFlags().StringVarP(&porterYAML, "file", "f", "", "path to a porter.yaml with the cache, buildkit and platforms settings for the build")
 	appCmd.AddCommand(appBuildCommand)
 
 	appPushCommand := &cobra.Command{
@@ -271,6 +289,7 @@ in that it only updates the app, but does not attempt to build a new image.`,
 		"",
 		"the specified tag to use, default is \"latest\"",
 	)
+	appUpdateTagCmd.Flags().StringVar(&appBreakGlassReason, "break-glass-reason", "", "override the freeze windows and protection rules of the deployment target, recording this reason")
 	appCmd.AddCommand(appUpdateTagCmd)
 
 	// appRollback represents the "porter app rollback" subcommand
@@ -282,8 +301,85 @@ in that it only updates the app, but does not attempt to build a new image.`,
 			return checkLoginAndRunWithConfig(cmd, cliConf, args, appRollback)
 		},
 	}
+	appRollbackCmd.Flags().StringVar(&appBreakGlassReason, "break-glass-reason", "", "override the freeze windows and protection rules of the deployment target, recording this reason")
 	appCmd.AddCommand(appRollbackCmd)
 
+	// appPromoteCmd represents the "porter app promote" subcommand
+	appPromoteCmd := &cobra.Command{
+		Use:   "promote [application]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Promotes an application from one deployment target to another.",
+		Long: fmt.Sprintf(`
+  %s
+
+Deploys the latest deployed revision of an application in one deployment target to another. The
+exact image digest and app definition of the revision are copied, while the target keeps its own
+environment groups. The diff against the app in the target is shown before asking for confirmation.
+
+  %s
+
+Settings which differ between targets can be overridden with a file of JSON patch operations on the
+app definition, in JSON or YAML:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app promote\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app promote example-app --from staging --to production"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app promote example-app --from staging --to production --overrides production.yaml"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appPromote)
+		},
+	}
+	appPromoteCmd.Flags().StringVar(&appPromoteFrom, "from", "", "the name of the deployment target to promote the app from")
+	appPromoteCmd.Flags().StringVar(&appPromoteTo, "to", "", "the name of the deployment target to promote the app to")
+	appPromoteCmd.Flags().StringVar(&appPromoteOverrides, "overrides", "", "path to a file of patch operations to apply to the promoted app definition")
+	appPromoteCmd.Flags().BoolVar(&appPromotePredeploy, "predeploy", false, "run the predeploy job in the target before deploying the application")
+	appPromoteCmd.Flags().BoolVarP(&appPromoteYes, "yes", "y", false, "promote without asking for confirmation")
+	appPromoteCmd.Flags().StringVar(&appBreakGlassReason, "break-glass-reason", "", "override the freeze windows and protection rules of the destination target, recording this reason")
+	_ = appPromoteCmd.MarkFlagRequired("from")
+	_ = appPromoteCmd.MarkFlagRequired("to")
+	appCmd.AddCommand(appPromoteCmd)
+
+	// appApproveCmd represents the "porter app approve" subcommand
+	appApproveCmd := &cobra.Command{
+		Use:   "approve [application]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Approves a revision of an application which is held for approval, so that it deploys.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appApprove)
+		},
+	}
+	appApproveCmd.Flags().StringVar(&appApprovalRevision, "revision", "", "the id of the revision to approve")
+	appApproveCmd.Flags().StringVar(&appApprovalReason, "reason", "", "a reason to record with the approval")
+	_ = appApproveCmd.MarkFlagRequired("revision")
+	appCmd.AddCommand(appApproveCmd)
+
+	// appRejectCmd represents the "porter app reject" subcommand
+	appRejectCmd := &cobra.Command{
+		Use:   "reject [application]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Rejects a revision of an application which is held for approval, so that it never deploys.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appReject)
+		},
+	}
+	appRejectCmd.Flags().StringVar(&appApprovalRevision, "revision", "", "the id of the revision to reject")
+	appRejectCmd.Flags().StringVar(&appApprovalReason, "reason", "", "a reason to record with the rejection")
+	_ = appRejectCmd.MarkFlagRequired("revision")
+	appCmd.AddCommand(appRejectCmd)
+
+	// appApprovalsCmd represents the "porter app approvals" subcommand
+	appApprovalsCmd := &cobra.Command{
+		Use:   "approvals [application]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Lists the revisions of an application which were held for approval.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appApprovals)
+		},
+	}
+	appCmd.AddCommand(appApprovalsCmd)
+
 	// appManifestsCmd represents the "porter app manifest" subcommand
 	appManifestsCmd := &cobra.Command{
 		Use:   "manifests [application]",
@@ -295,6 +391,40 @@ in that it only updates the app, but does not attempt to build a new image.`,
 	}
 	appCmd.AddCommand(appManifestsCmd)
 
+	// appDriftCmd represents the "porter app drift" subcommand
+	appDriftCmd := &cobra.Command{
+		Use:   "drift [application]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Reports changes made to an application's Kubernetes objects outside of Porter.",
+		Long: fmt.Sprintf(`
+  %s
+
+Compares the objects in the latest release of an application with the live objects in the
+cluster, and prints the fields which were changed, e.g. with kubectl edit. Fields defaulted
+by Kubernetes and object status are not compared.
+
+  %s
+
+Use the --fail-on-drift flag to exit with a non-zero code when drift is found:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app drift\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app drift example-app"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app drift example-app --fail-on-drift"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appDrift)
+		},
+	}
+	appDriftCmd.Flags().BoolVar(
+		&appFailOnDrift,
+		"fail-on-drift",
+		false,
+		"exit with a non-zero code if the application has drifted",
+	)
+	appCmd.AddCommand(appDriftCmd)
+
 	// appLogsCmd represents the "porter app logs" subcommand
 	appLogsCmd := &cobra.Command{
 		Use:   "logs [application]",
@@ -460,6 +590,7 @@ func appBuild(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client
 		ImageTag:             tag,
 		PatchOperations:      patchOperations,
 		PullImageBeforeBuild: pullBeforeBuild,
+		PorterYamlPath:       porterYAML,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to build app: %w", err)
@@ -560,6 +691,90 @@ func appManifests(ctx context.Context, _ *types.GetAuthenticatedUserResponse, cl
 	return nil
 }
 
+func appDrift(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	project, err := client.GetProject(ctx, cliConfig.Project)
+	if err != nil {
+		return fmt.Errorf("could not retrieve project from Porter API. Please contact support@porter.run")
+	}
+
+	namespace := fmt.Sprintf("porter-stack-%s", appName)
+
+	if project.ValidateApplyV2 {
+		namespace, err = appDeploymentTargetNamespace(ctx, client, cliConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	report, err := client.GetReleaseDrift(ctx, cliConfig.Project, cliConfig.Cluster, namespace, appName)
+	if err != nil {
+		return fmt.Errorf("failed to detect drift: %w", err)
+	}
+
+	if !report.Drifted && len(report.Resources) == 0 {
+		_, _ = color.New(color.FgGreen).Printf("No drift found in the %d objects of %s (revision %d)\n", report.Checked, appName, report.Revision)
+		return nil
+	}
+
+	for _, res := range report.Resources {
+		_, _ = color.New(color.Bold).Printf("%s %s/%s\n", res.Kind, res.Namespace, res.Name)
+
+		switch {
+		case res.Error != "":
+			_, _ = color.New(color.FgYellow).Printf("  could not be compared: %s\n", res.Error)
+		case res.Missing:
+			_, _ = color.New(color.FgRed).Println("  deleted from the cluster")
+		}
+
+		for _, field := range res.Fields {
+			switch {
+			case field.Desired == nil:
+				_, _ = color.New(color.FgRed).Printf("  + %s: %v\n", field.Path, field.Live)
+			case field.Live == nil:
+				_, _ = color.New(color.FgRed).Printf("  - %s: %v\n", field.Path, field.Desired)
+			default:
+				_, _ = color.New(color.FgRed).Printf("  ~ %s: %v -> %v\n", field.Path, field.Desired, field.Live)
+			}
+		}
+	}
+
+	if report.Drifted && appFailOnDrift {
+		return fmt.Errorf("%s has drifted from revision %d", appName, report.Revision)
+	}
+
+	return nil
+}
+
+// appDeploymentTargetNamespace returns the namespace of the deployment target set with the
+// --target flag, or of the cluster's default deployment target
+func appDeploymentTargetNamespace(ctx context.Context, client api.Client, cliConfig config.CLIConfig) (string, error) {
+	targets, err := client.ListDeploymentTargets(ctx, cliConfig.Project, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to list deployment targets: %w", err)
+	}
+
+	for _, target := range targets.DeploymentTargets {
+		if target.ClusterID != cliConfig.Cluster {
+			continue
+		}
+
+		if (deploymentTargetName == "" && target.IsDefault) || (deploymentTargetName != "" && target.Name == deploymentTargetName) {
+			return target.Namespace, nil
+		}
+	}
+
+	if deploymentTargetName != "" {
+		return "", fmt.Errorf("deployment target %s not found in cluster %d", deploymentTargetName, cliConfig.Cluster)
+	}
+
+	return "", fmt.Errorf("no default deployment target found in cluster %d", cliConfig.Cluster)
+}
+
 func appRollback(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
 	project, err := client.GetProject(ctx, cliConfig.Project)
 	if err != nil {
@@ -576,12 +791,105 @@ func appRollback(ctx context.Context, _ *types.GetAuthenticatedUserResponse, cli
 	}
 
 	err = v2.Rollback(ctx, v2.RollbackInput{
+		CLIConfig:        cliConfig,
+		Client:           client,
+		AppName:          appName,
+		BreakGlassReason: appBreakGlassReason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rollback app: %w", err)
+	}
+
+	return nil
+}
+
+func appPromote(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	project, err := client.GetProject(ctx, cliConfig.Project)
+	if err != nil {
+		return fmt.Errorf("could not retrieve project from Porter API. Please contact support@porter.run")
+	}
+
+	if !project.ValidateApplyV2 {
+		return fmt.Errorf("promote command is not enabled for this project")
+	}
+
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	var overrides []appV2.PatchOperation
+	if appPromoteOverrides != "" {
+		data, err := os.ReadFile(appPromoteOverrides) // nolint:gosec
+		if err != nil {
+			return fmt.Errorf("error reading overrides file: %w", err)
+		}
+
+		if err := yaml.Unmarshal(data, &overrides); err != nil {
+			return fmt.Errorf("error parsing overrides file: %w", err)
+		}
+	}
+
+	err = v2.Promote(ctx, v2.PromoteInput{
+		CLIConfig:            cliConfig,
+		Client:               client,
+		AppName:              appName,
+		FromDeploymentTarget: appPromoteFrom,
+		ToDeploymentTarget:   appPromoteTo,
+		Overrides:            overrides,
+		WithPredeploy:        appPromotePredeploy,
+		SkipConfirmation:     appPromoteYes,
+		BreakGlassReason:     appBreakGlassReason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to promote app: %w", err)
+	}
+
+	return nil
+}
+
+func appApprove(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	return decideApproval(ctx, client, cliConfig, args, false)
+}
+
+func appReject(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	return decideApproval(ctx, client, cliConfig, args, true)
+}
+
+func decideApproval(ctx context.Context, client api.Client, cliConfig config.CLIConfig, args []string, reject bool) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	err := v2.DecideApproval(ctx, v2.DecideApprovalInput{
+		CLIConfig:     cliConfig,
+		Client:        client,
+		AppName:       appName,
+		AppRevisionID: appApprovalRevision,
+		Reject:        reject,
+		Reason:        appApprovalReason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to decide approval: %w", err)
+	}
+
+	return nil
+}
+
+func appApprovals(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	err := v2.ListApprovals(ctx, v2.ListApprovalsInput{
 		CLIConfig: cliConfig,
 		Client:    client,
 		AppName:   appName,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to rollback app: %w", err)
+		return fmt.Errorf("failed to list approvals: %w", err)
 	}
 
 	return nil
@@ -1609,6 +1917,7 @@ func appUpdateTag(ctx context.Context, user *types.GetAuthenticatedUserResponse,
 			Tag:                         appTag,
 			Client:                      client,
 			WaitForSuccessfulDeployment: appWait,
+			BreakGlassReason:            appBreakGlassReason,
 		})
 		if err != nil {
 			return fmt.Errorf("error updating tag: %w", err)
@@ -48,6 +48,8 @@ var (
 	pullImageBeforeBuild bool
 	predeploy            bool
 	exact                bool
+	// breakGlassReason overrides the freeze windows and protection rules of the deployment target
+	breakGlassReason string
 )
 
 func registerCommand_Apply(cliConf config.CLIConfig) *cobra.Command {
@@ -115,6 +117,7 @@ applying a configuration:
 	applyCmd.PersistentFlags().BoolVarP(&previewApply, "preview", "p", false, "apply as preview environment based on current git branch")
 	applyCmd.PersistentFlags().BoolVar(&pullImageBeforeBuild, "pull-before-build", false, "attempt to pull image from registry before building")
 	applyCmd.PersistentFlags().BoolVar(&predeploy, "predeploy", false, "run predeploy job before deploying the application")
+	applyCmd.PersistentFlags().StringVar(&breakGlassReason, "break-glass-reason", "", "override the freeze windows and protection rules of the deployment target, recording this reason")
 	applyCmd.PersistentFlags().BoolVar(&exact, "exact", false, "apply the exact configuration as specified in the porter.yaml file (default is to merge with existing configuration)")
 	applyCmd.PersistentFlags().BoolVarP(
 		&appWait,
@@ -209,6 +212,7 @@ func apply(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client ap
 			Exact:                       exact,
 			PatchOperations:             patchOperations,
 			SkipBuild:                   noBuild,
+			BreakGlassReason:            breakGlassReason,
 		}
 		err = v2.Apply(ctx, inp)
 		if err != nil {
@@ -56,6 +56,39 @@ func registerCommand_Connect(cliConf config.CLIConfig) *cobra.Command {
 		},
 	}
 
+	connectHarborCmd := &cobra.Command{
+		Use:   "harbor",
+		Short: "Adds a Harbor project to a project",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, runConnectHarbor)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	connectQuayCmd := &cobra.Command{
+		Use:   "quay",
+		Short: "Adds a Quay organization to a project",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, runConnectQuay)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	connectGHCRCmd := &cobra.Command{
+		Use:   "ghcr",
+		Short: "Adds a GitHub Container Registry organization to a project",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, runConnectGHCR)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
 	connectRegistryCmd := &cobra.Command{
 		Use:   "registry",
 		Short: "Adds a custom image registry to a project",
@@ -131,6 +164,9 @@ func registerCommand_Connect(cliConf config.CLIConfig) *cobra.Command {
 	connectCmd.AddCommand(connectECRCmd)
 	connectCmd.AddCommand(connectRegistryCmd)
 	connectCmd.AddCommand(connectDockerhubCmd)
+	connectCmd.AddCommand(connectHarborCmd)
+	connectCmd.AddCommand(connectQuayCmd)
+	connectCmd.AddCommand(connectGHCRCmd)
 	connectCmd.AddCommand(connectGCRCmd)
 	connectCmd.AddCommand(connectGARCmd)
 	connectCmd.AddCommand(connectDOCRCmd)
@@ -225,6 +261,45 @@ func runConnectDockerhub(ctx context.Context, _ *types.GetAuthenticatedUserRespo
 	return cliConf.SetRegistry(regID)
 }
 
+func runConnectHarbor(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	regID, err := connect.Harbor(
+		ctx,
+		client,
+		cliConf.Project,
+	)
+	if err != nil {
+		return err
+	}
+
+	return cliConf.SetRegistry(regID)
+}
+
+func runConnectQuay(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	regID, err := connect.Quay(
+		ctx,
+		client,
+		cliConf.Project,
+	)
+	if err != nil {
+		return err
+	}
+
+	return cliConf.SetRegistry(regID)
+}
+
+func runConnectGHCR(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
+	regID, err := connect.GHCR(
+		ctx,
+		client,
+		cliConf.Project,
+	)
+	if err != nil {
+		return err
+	}
+
+	return cliConf.SetRegistry(regID)
+}
+
 func runConnectRegistry(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, _ []string) error {
 	regID, err := connect.Registry(
 		ctx,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -13,6 +14,7 @@ import (
 	"github.com/karagatandev/porter/api/server/handlers/porter_app"
 	"github.com/karagatandev/porter/api/types"
 	"github.com/karagatandev/porter/cli/cmd/config"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
 	"github.com/spf13/cobra"
 )
 
@@ -105,9 +107,54 @@ When updating an environment group, all apps linked to the environment group wil
 	unsetCommand.Flags().StringSliceP("secrets", "s", nil, "secrets to unset")
 	unsetCommand.Flags().Bool("skip-redeploys", false, "skip re-deploying apps linked to the environment group")
 
+	historyCommand := &cobra.Command{
+		Use:   "history",
+		Short: "List the versions of an environment group",
+		Long: `List the versions of an environment group, newest first, with the user who created each version.
+
+Only environment groups are versioned, so the --group flag is required.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupHistory)
+		},
+	}
+
+	diffCommand := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the changes between two versions of an environment group",
+		Long: `Show the changes between two versions of an environment group.
+
+Variables are shown with their old and new values. Secrets and files are only shown as added, removed, changed or unchanged.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupDiff)
+		},
+	}
+	diffCommand.Flags().Int("from", 0, "the older version to compare")
+	diffCommand.Flags().Int("to", 0, "the newer version to compare (defaults to the latest version)")
+	_ = diffCommand.MarkFlagRequired("from")
+
+	rollbackCommand := &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll back an environment group to a previous version",
+		Long: `Roll back an environment group to a previous version.
+
+A new version equal to the previous version is created, and all apps linked to the environment group will be re-deployed, unless the --skip-redeploys flag is used.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envGroupRollback)
+		},
+	}
+	rollbackCommand.Flags().Int("version", 0, "the version to roll back to")
+	rollbackCommand.Flags().Bool("skip-redeploys", false, "skip re-deploying apps linked to the environment group")
+	_ = rollbackCommand.MarkFlagRequired("version")
+
 	envCmd.AddCommand(pullCommand)
 	envCmd.AddCommand(setCommand)
 	envCmd.AddCommand(unsetCommand)
+	envCmd.AddCommand(historyCommand)
+	envCmd.AddCommand(diffCommand)
+	envCmd.AddCommand(rollbackCommand)
 
 	return envCmd
 }
@@ -323,6 +370,154 @@ func unsetEnv(ctx context.Context, user *types.GetAuthenticatedUserResponse, cli
 	return nil
 }
 
+func envGroupHistory(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	if envGroupName == "" {
+		return fmt.Errorf("must specify an environment group with --group")
+	}
+
+	resp, err := client.ListEnvGroupVersions(ctx, cliConf.Project, cliConf.Cluster, envGroupName)
+	if err != nil {
+		return fmt.Errorf("could not list env group versions: %w", err)
+	}
+	if resp == nil {
+		return fmt.Errorf("could not list env group versions: response was nil")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 3, 8, 1, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "VERSION", "CREATED AT", "AUTHOR", "KEYS") // nolint:errcheck,gosec
+
+	for _, version := range resp.Versions {
+		author := version.Author
+		if author == "" {
+			author = "-"
+		}
+
+		keys := len(version.Variables) + len(version.SecretVariables) + len(version.Files)
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\n", version.Version, version.CreatedAtUTC.Format(time.RFC3339), author, keys) // nolint:errcheck,gosec
+	}
+
+	return w.Flush()
+}
+
+func envGroupDiff(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	if envGroupName == "" {
+		return fmt.Errorf("must specify an environment group with --group")
+	}
+
+	fromVersion, err := cmd.Flags().GetInt("from")
+	if err != nil {
+		return fmt.Errorf("could not get from: %w", err)
+	}
+
+	toVersion, err := cmd.Flags().GetInt("to")
+	if err != nil {
+		return fmt.Errorf("could not get to: %w", err)
+	}
+
+	if toVersion == 0 {
+		versions, err := client.ListEnvGroupVersions(ctx, cliConf.Project, cliConf.Cluster, envGroupName)
+		if err != nil {
+			return fmt.Errorf("could not list env group versions: %w", err)
+		}
+		if versions == nil || len(versions.Versions) == 0 {
+			return fmt.Errorf("environment group %s has no versions", envGroupName)
+		}
+
+		toVersion = versions.Versions[0].Version
+	}
+
+	diff, err := client.DiffEnvGroupVersions(ctx, cliConf.Project, cliConf.Cluster, envGroupName, fromVersion, toVersion)
+	if err != nil {
+		return fmt.Errorf("could not diff env group versions: %w", err)
+	}
+	if diff == nil {
+		return fmt.Errorf("could not diff env group versions: response was nil")
+	}
+
+	fmt.Printf("Changes to environment group %s from version %d to version %d:\n", envGroupName, diff.FromVersion, diff.ToVersion)
+
+	printEnvGroupDiff("Variables", diff.Variables, false)
+	printEnvGroupDiff("Secrets", diff.SecretVariables, true)
+	printEnvGroupDiff("Files", diff.Files, true)
+
+	return nil
+}
+
+// printEnvGroupDiff prints the keys of a diff which were added, removed or changed
+func printEnvGroupDiff(title string, diffs []environmentgroups.VariableDiff, masked bool) {
+	var changed []environmentgroups.VariableDiff
+	for _, diff := range diffs {
+		if diff.Change != environmentgroups.VariableChange_Unchanged {
+			changed = append(changed, diff)
+		}
+	}
+
+	if len(changed) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s:\n", title)
+
+	for _, diff := range changed {
+		switch {
+		case diff.Change == environmentgroups.VariableChange_Added && !masked:
+			color.New(color.FgGreen).Printf("+ %s=%s\n", diff.Key, diff.NewValue) // nolint:errcheck,gosec
+		case diff.Change == environmentgroups.VariableChange_Added:
+			color.New(color.FgGreen).Printf("+ %s\n", diff.Key) // nolint:errcheck,gosec
+		case diff.Change == environmentgroups.VariableChange_Removed && !masked:
+			color.New(color.FgRed).Printf("- %s=%s\n", diff.Key, diff.OldValue) // nolint:errcheck,gosec
+		case diff.Change == environmentgroups.VariableChange_Removed:
+			color.New(color.FgRed).Printf("- %s\n", diff.Key) // nolint:errcheck,gosec
+		case !masked:
+			color.New(color.FgYellow).Printf("~ %s: %s -> %s\n", diff.Key, diff.OldValue, diff.NewValue) // nolint:errcheck,gosec
+		default:
+			color.New(color.FgYellow).Printf("~ %s (changed)\n", diff.Key) // nolint:errcheck,gosec
+		}
+	}
+}
+
+func envGroupRollback(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	if envGroupName == "" {
+		return fmt.Errorf("must specify an environment group with --group")
+	}
+
+	version, err := cmd.Flags().GetInt("version")
+	if err != nil {
+		return fmt.Errorf("could not get version: %w", err)
+	}
+
+	skipRedeploys, err := cmd.Flags().GetBool("skip-redeploys")
+	if err != nil {
+		return fmt.Errorf("could not get skip-redeploys: %w", err)
+	}
+
+	s := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+	s.Color("cyan") // nolint:errcheck,gosec
+	s.Suffix = fmt.Sprintf(" Rolling back environment group %s to version %d...", envGroupName, version)
+
+	s.Start()
+	resp, err := client.RollbackEnvGroup(ctx, api.RollbackEnvGroupInput{
+		ProjectID:     cliConf.Project,
+		ClusterID:     cliConf.Cluster,
+		EnvGroupName:  envGroupName,
+		Version:       version,
+		SkipRedeploys: skipRedeploys,
+	})
+	s.Stop()
+	if err != nil {
+		return fmt.Errorf("could not roll back env group: %w", err)
+	}
+	if resp == nil {
+		return fmt.Errorf("could not roll back env group: response was nil")
+	}
+
+	color.New(color.FgGreen).Printf("Rolled back environment group %s to version %d as version %d\n", envGroupName, resp.RolledBackTo, resp.Version.Version) // nolint:errcheck,gosec
+
+	return nil
+}
+
 func writeEnvFile(envFilePath string, envVars envVariables) error {
 	// open existing file or create new file: https://pkg.go.dev/os#example-OpenFile-Append
 	envFile, err := os.OpenFile(envFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) // nolint:gosec
@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	api "github.com/karagatandev/porter/api/client"
+	"github.com/karagatandev/porter/api/server/handlers/environment_groups"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/cli/cmd/config"
+	"github.com/karagatandev/porter/cli/cmd/envgroup"
+	environmentgroups "github.com/karagatandev/porter/internal/kubernetes/environment_groups"
+	"github.com/spf13/cobra"
+)
+
+func registerCommand_EnvGroup(cliConf config.CLIConfig) *cobra.Command {
+	envGroupCmd := &cobra.Command{
+		Use:     "env-group",
+		Aliases: []string{"envgroup"},
+		Short:   "Import and export environment groups",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	exportCommand := &cobra.Command{
+		Use:   "export [NAME]",
+		Short: "Export the variables, secrets and files of an environment group",
+		Long: `Export the variables, secrets and files of an environment group to a .env, JSON or YAML file.
+
+The format is taken from the extension of the file, and can be overridden with --format. If no file is specified, the environment group is written to stdout.
+In .env files, secrets are marked with a "# porter:secret" comment on the line before them, and files are written as base64-encoded values marked with a "# porter:file" comment.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, exportEnvGroup)
+		},
+	}
+	exportCommand.Flags().StringP("file", "f", "", "file to write the environment group to")
+	exportCommand.Flags().String("format", "", "the format to write: dotenv, json or yaml (defaults to the extension of the file, or dotenv)")
+
+	importCommand := &cobra.Command{
+		Use:   "import [NAME]",
+		Short: "Import variables, secrets and files into an environment group",
+		Long: `Import variables, secrets and files from a .env, JSON or YAML file into an environment group.
+
+All changes are published as a single new version of the environment group. The import is merged into the latest version, unless --replace is used,
+in which case all variables, secrets and files which are not in the file are removed. Variables can be imported as secrets with --secret.
+Use --dry-run to show the changes the import would make without making them.
+All apps linked to the environment group will be re-deployed, unless the --skip-redeploys flag is used.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, importEnvGroup)
+		},
+	}
+	importCommand.Flags().StringP("file", "f", "", "file to import the environment group from")
+	importCommand.Flags().String("format", "", "the format to read: dotenv, json or yaml (defaults to the extension of the file)")
+	importCommand.Flags().StringSlice("secret", nil, "keys to import as secrets")
+	importCommand.Flags().Bool("replace", false, "remove all variables, secrets and files which are not in the file")
+	importCommand.Flags().Bool("dry-run", false, "show the changes the import would make without making them")
+	importCommand.Flags().Bool("skip-redeploys", false, "skip re-deploying apps linked to the environment group")
+	_ = importCommand.MarkFlagRequired("file")
+
+	envGroupCmd.AddCommand(exportCommand)
+	envGroupCmd.AddCommand(importCommand)
+
+	return envGroupCmd
+}
+
+func exportEnvGroup(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	filePath, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return fmt.Errorf("could not get file: %w", err)
+	}
+
+	formatName, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return fmt.Errorf("could not get format: %w", err)
+	}
+
+	format, err := envgroup.ParseFormat(formatName, filePath)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.GetLatestEnvGroupVariables(ctx, cliConf.Project, cliConf.Cluster, name)
+	if err != nil {
+		return fmt.Errorf("could not get env group variables: %w", err)
+	}
+	if resp == nil {
+		return fmt.Errorf("could not get env group variables: response was nil")
+	}
+
+	eg := envgroup.EnvGroup{
+		Variables: resp.Variables,
+		Secrets:   resp.Secrets,
+	}
+	for _, file := range resp.Files {
+		eg.Files = append(eg.Files, envgroup.EnvGroupFile{Name: file.Name, Contents: file.Contents})
+	}
+
+	data, err := envgroup.Marshal(eg, format)
+	if err != nil {
+		return fmt.Errorf("could not write env group: %w", err)
+	}
+
+	for _, value := range resp.Secrets {
+		if value == environmentgroups.EnvGroupSecretDummyValue {
+			color.New(color.FgYellow).Fprintln(os.Stderr, "You do not have access to the secret values of this environment group, so they were exported as masked values. Importing masked values leaves the existing secrets unchanged.") // nolint:errcheck,gosec
+			break
+		}
+	}
+
+	if filePath == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(filePath, data, 0o600); err != nil {
+		return fmt.Errorf("could not write to %s: %w", filePath, err)
+	}
+
+	color.New(color.FgGreen).Printf("Exported environment group %s to %s\n", name, filePath) // nolint:errcheck,gosec
+
+	return nil
+}
+
+func importEnvGroup(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	filePath, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return fmt.Errorf("could not get file: %w", err)
+	}
+
+	formatName, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return fmt.Errorf("could not get format: %w", err)
+	}
+
+	secrets, err := cmd.Flags().GetStringSlice("secret")
+	if err != nil {
+		return fmt.Errorf("could not get secret: %w", err)
+	}
+
+	replace, err := cmd.Flags().GetBool("replace")
+	if err != nil {
+		return fmt.Errorf("could not get replace: %w", err)
+	}
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return fmt.Errorf("could not get dry-run: %w", err)
+	}
+
+	skipRedeploys, err := cmd.Flags().GetBool("skip-redeploys")
+	if err != nil {
+		return fmt.Errorf("could not get skip-redeploys: %w", err)
+	}
+
+	format, err := envgroup.ParseFormat(formatName, filePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filePath) // nolint:gosec
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", filePath, err)
+	}
+
+	eg, err := envgroup.Unmarshal(data, format)
+	if err != nil {
+		return fmt.Errorf("could not parse %s: %w", filePath, err)
+	}
+
+	if err := eg.MarkSecrets(secrets); err != nil {
+		return err
+	}
+
+	files := make([]environment_groups.EnvironmentGroupFile, 0, len(eg.Files))
+	for _, file := range eg.Files {
+		files = append(files, environment_groups.EnvironmentGroupFile{Name: file.Name, Contents: file.Contents})
+	}
+
+	s := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+	s.Color("cyan") // nolint:errcheck,gosec
+	s.Suffix = fmt.Sprintf(" Importing environment group %s...", name)
+
+	s.Start()
+	resp, err := client.ImportEnvGroup(ctx, api.ImportEnvGroupInput{
+		ProjectID:     cliConf.Project,
+		ClusterID:     cliConf.Cluster,
+		EnvGroupName:  name,
+		Variables:     eg.Variables,
+		Secrets:       eg.Secrets,
+		Files:         files,
+		Replace:       replace,
+		DryRun:        dryRun,
+		SkipRedeploys: skipRedeploys,
+	})
+	s.Stop()
+	if err != nil {
+		return fmt.Errorf("could not import env group: %w", err)
+	}
+	if resp == nil {
+		return fmt.Errorf("could not import env group: response was nil")
+	}
+
+	if dryRun {
+		fmt.Printf("Changes the import would make to environment group %s:\n", name)
+	} else {
+		fmt.Printf("Changes made to environment group %s:\n", name)
+	}
+
+	printEnvGroupDiff("Variables", resp.Diff.Variables, false)
+	printEnvGroupDiff("Secrets", resp.Diff.SecretVariables, true)
+	printEnvGroupDiff("Files", resp.Diff.Files, true)
+
+	if !dryRun {
+		color.New(color.FgGreen).Printf("\nImported environment group %s as version %d\n", name, resp.Version) // nolint:errcheck,gosec
+	}
+
+	return nil
+}
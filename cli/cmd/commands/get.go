@@ -11,6 +11,7 @@ import (
 
 	api "github.com/karagatandev/porter/api/client"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm/grapher"
 	"github.com/spf13/cobra"
 	"github.com/stefanmcshane/helm/pkg/time"
 	"gopkg.in/yaml.v2"
@@ -44,6 +45,23 @@ func registerCommand_Get(cliConf config.CLIConfig) *cobra.Command {
 		},
 	}
 
+	// getGraphCmd represents the "porter get graph" command
+	getGraphCmd := &cobra.Command{
+		Use:   "graph [release]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Prints the graph of the Kubernetes objects in a release and their relationships.",
+		Long: `Prints the graph of the Kubernetes objects in a release and their relationships, as JSON
+or in the Graphviz DOT language. For example, to render the graph of a release as an image:
+
+  porter get graph web --output dot | dot -Tsvg > web.svg`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, getGraph)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
 	getCmd.PersistentFlags().StringVar(
 		&namespace,
 		"namespace",
@@ -55,10 +73,11 @@ func registerCommand_Get(cliConf config.CLIConfig) *cobra.Command {
 		&output,
 		"output",
 		"",
-		"the output format to use (\"yaml\" or \"json\")",
+		"the output format to use (\"yaml\" or \"json\"; \"json\" or \"dot\" for graph)",
 	)
 
 	getCmd.AddCommand(getValuesCmd)
+	getCmd.AddCommand(getGraphCmd)
 
 	return getCmd
 }
@@ -152,3 +171,38 @@ func getValues(ctx context.Context, _ *types.GetAuthenticatedUserResponse, clien
 
 	return nil
 }
+
+func getGraph(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	rel, err := client.GetRelease(ctx, cliConf.Project, cliConf.Cluster, namespace, args[0])
+	if err != nil {
+		return err
+	}
+
+	yamlArr := grapher.ImportMultiDocYAML([]byte(rel.Manifest))
+	parsed := grapher.ParsedObjs{
+		Objects: grapher.ParseObjs(yamlArr, rel.Namespace),
+	}
+
+	parsed.GetControlRel()
+	parsed.GetLabelRel()
+	parsed.GetSpecRel()
+	parsed.GetResourceRel()
+
+	graph := parsed.Graph()
+
+	switch output {
+	case "dot":
+		fmt.Print(graph.DOT())
+	case "", "json":
+		bytes, err := graph.JSON()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(bytes))
+	default:
+		return fmt.Errorf("unsupported output format %q for graph, must be \"json\" or \"dot\"", output)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	api "github.com/karagatandev/porter/api/client"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/cli/cmd/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreRevision int
+	restoreRollback bool
+)
+
+func registerCommand_Revision(cliConf config.CLIConfig) *cobra.Command {
+	revisionCmd := &cobra.Command{
+		Use:   "revision",
+		Short: "Manages the archived revisions of a release.",
+	}
+
+	// revisionListCmd represents the "porter revision list" command
+	revisionListCmd := &cobra.Command{
+		Use:   "list [release]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Lists the revisions of a release which were pruned from the cluster and archived.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, listArchivedRevisions)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	// revisionRestoreCmd represents the "porter revision restore" command
+	revisionRestoreCmd := &cobra.Command{
+		Use:   "restore [release]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Restores an archived revision of a release into the cluster.",
+		Long: fmt.Sprintf(`
+%s
+
+Restores a revision of a release which was pruned from the cluster and archived. The revision is
+added to the release's history without being deployed, so that the release can be rolled back to it.
+
+Example commands:
+
+  %s
+
+To roll the release back to the revision once it is restored, use the --rollback flag:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter revision restore\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter revision restore web --revision 12"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter revision restore web --revision 12 --rollback"),
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, restoreArchivedRevision)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	revisionRestoreCmd.Flags().IntVar(
+		&restoreRevision,
+		"revision",
+		0,
+		"the number of the revision to restore",
+	)
+
+	revisionRestoreCmd.Flags().BoolVar(
+		&restoreRollback,
+		"rollback",
+		false,
+		"roll the release back to the revision once it is restored",
+	)
+
+	revisionRestoreCmd.MarkFlagRequired("revision")
+
+	revisionCmd.PersistentFlags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"the namespace of the release",
+	)
+
+	revisionCmd.AddCommand(revisionListCmd)
+	revisionCmd.AddCommand(revisionRestoreCmd)
+
+	return revisionCmd
+}
+
+func listArchivedRevisions(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	resp, err := client.ListArchivedRevisions(ctx, cliConf.Project, cliConf.Cluster, namespace, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to list archived revisions: %w", err)
+	}
+
+	if len(resp.Revisions) == 0 {
+		fmt.Printf("No revisions of %s are archived\n", args[0])
+		return nil
+	}
+
+	for _, revision := range resp.Revisions {
+		fmt.Println(revision)
+	}
+
+	return nil
+}
+
+func restoreArchivedRevision(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, _ config.FeatureFlags, _ *cobra.Command, args []string) error {
+	err := client.RestoreArchivedRevision(ctx, cliConf.Project, cliConf.Cluster, namespace, args[0], &types.RestoreArchivedRevisionRequest{
+		Revision: restoreRevision,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore revision %d: %w", restoreRevision, err)
+	}
+
+	_, _ = color.New(color.FgGreen).Printf("Restored revision %d of %s\n", restoreRevision, args[0])
+
+	if !restoreRollback {
+		return nil
+	}
+
+	err = client.RollbackRelease(ctx, cliConf.Project, cliConf.Cluster, namespace, args[0], &types.RollbackReleaseRequest{
+		Revision: restoreRevision,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to roll back to revision %d: %w", restoreRevision, err)
+	}
+
+	_, _ = color.New(color.FgGreen).Printf("Rolled %s back to revision %d\n", args[0], restoreRevision)
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/google/uuid"
@@ -81,6 +82,26 @@ If the --preview flag is set, only deployment targets for preview environments w
 	deleteTargetCmd.MarkFlagRequired("name") // nolint:errcheck,gosec
 	targetCmd.AddCommand(deleteTargetCmd)
 
+	policyTargetCmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Shows the freeze windows and protection rules of a deployment target",
+		Long: `Shows the freeze windows and protection rules of a deployment target, and the freeze window in effect if any.
+
+Deploys which violate the policy are refused unless they are made with --break-glass-reason, in which case the
+override is recorded along with the reason.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, targetPolicy)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	policyTargetCmd.Flags().StringVar(&targetName, "name", "", "Name of deployment target")
+	policyTargetCmd.MarkFlagRequired("name") // nolint:errcheck,gosec
+	targetCmd.AddCommand(policyTargetCmd)
+
 	return targetCmd
 }
 
@@ -199,6 +220,67 @@ func deleteTarget(ctx context.Context, _ *types.GetAuthenticatedUserResponse, cl
 	return nil
 }
 
+func targetPolicy(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return fmt.Errorf("error finding name flag: %w", err)
+	}
+
+	policy, err := client.GetDeploymentTargetPolicy(ctx, cliConf.Project, name)
+	if err != nil {
+		return fmt.Errorf("error getting target policy: %w", err)
+	}
+
+	if policy.ActiveFreeze != nil {
+		color.New(color.FgRed).Printf("Deploys to %s are frozen by %s until %s\n", name, policy.ActiveFreeze.Name, policy.ActiveFreeze.Until.Local().Format(time.RFC1123)) // nolint:errcheck,gosec
+		if policy.ActiveFreeze.Reason != "" {
+			color.New(color.FgRed).Printf("Reason: %s\n", policy.ActiveFreeze.Reason) // nolint:errcheck,gosec
+		}
+		fmt.Println()
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "FREEZE-WINDOW", "SCHEDULE", "DURATION", "TIMEZONE", "REASON")
+	for _, window := range policy.FreezeWindows {
+		timezone := window.Timezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", window.Name, window.Schedule, window.Duration, timezone, window.Reason)
+	}
+
+	_ = w.Flush()
+
+	fmt.Println()
+	fmt.Printf("Approver role:    %s\n", valueOrNone(policy.ApproverRole))
+	fmt.Printf("Allowed branches: %s\n", valueOrNone(strings.Join(policy.AllowedBranches, ", ")))
+	fmt.Printf("Allowed sources:  %s\n", valueOrNone(strings.Join(policy.AllowedSources, ", ")))
+
+	if policy.RequiresApproval {
+		timeout := policy.ApprovalTimeout
+		if timeout == "" {
+			timeout = "24h"
+		}
+
+		fmt.Printf("Approval:         required, expires after %s\n", timeout)
+	} else {
+		fmt.Printf("Approval:         not required\n")
+	}
+
+	return nil
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+
+	return value
+}
+
 func confirmAction(prompt string) (bool, error) {
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Printf("%s [Y/n]: ", prompt)
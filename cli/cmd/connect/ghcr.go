@@ -0,0 +1,79 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/karagatandev/porter/api/types"
+
+	"github.com/fatih/color"
+	api "github.com/karagatandev/porter/api/client"
+	"github.com/karagatandev/porter/cli/cmd/utils"
+)
+
+// GHCR connects the container packages of a GitHub organization to a Porter project, using an
+// installation of the Porter GitHub App on the organization
+func GHCR(
+	ctx context.Context,
+	client api.Client,
+	projectID uint,
+) (uint, error) {
+	// if project ID is 0, ask the user to set the project ID or create a project
+	if projectID == 0 {
+		return 0, fmt.Errorf("no project set, please run porter project set [id]")
+	}
+
+	installationIDs, err := client.ListGitInstallationIDs(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+
+	if installationIDs == nil || len(*installationIDs) == 0 {
+		return 0, fmt.Errorf("no GitHub App installations found, please install the Porter GitHub App on your organization from the dashboard")
+	}
+
+	org, err := utils.PromptPlaintext("GitHub organization: ")
+	if err != nil {
+		return 0, err
+	}
+
+	if org == "" || strings.Contains(org, "/") {
+		return 0, fmt.Errorf("invalid GitHub organization: %s", org)
+	}
+
+	options := make([]string, 0, len(*installationIDs))
+
+	for _, id := range *installationIDs {
+		options = append(options, strconv.FormatInt(id, 10))
+	}
+
+	selected, err := utils.PromptSelect(fmt.Sprintf("Select the GitHub App installation on %s", org), options)
+	if err != nil {
+		return 0, err
+	}
+
+	installationID, err := strconv.ParseInt(selected, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	reg, err := client.CreateRegistry(
+		ctx,
+		projectID,
+		&types.CreateRegistryRequest{
+			URL:                     fmt.Sprintf("ghcr.io/%s", org),
+			Name:                    org,
+			GithubAppInstallationID: installationID,
+			Service:                 string(types.GHCR),
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	color.New(color.FgGreen).Printf("created GHCR registry with id %d and name %s\n", reg.ID, reg.Name)
+
+	return reg.ID, nil
+}
@@ -0,0 +1,88 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/karagatandev/porter/api/types"
+
+	"github.com/fatih/color"
+	api "github.com/karagatandev/porter/api/client"
+	"github.com/karagatandev/porter/cli/cmd/utils"
+)
+
+// Harbor connects a Harbor project to a Porter project, using a robot account of the project
+func Harbor(
+	ctx context.Context,
+	client api.Client,
+	projectID uint,
+) (uint, error) {
+	// if project ID is 0, ask the user to set the project ID or create a project
+	if projectID == 0 {
+		return 0, fmt.Errorf("no project set, please run porter project set [id]")
+	}
+
+	host, err := utils.PromptPlaintext("Provide the host of your Harbor instance. For example, harbor.example.com.\nHost: ")
+	if err != nil {
+		return 0, err
+	}
+
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "https://"), "/")
+
+	if host == "" {
+		return 0, fmt.Errorf("invalid Harbor host")
+	}
+
+	project, err := utils.PromptPlaintext("Harbor project: ")
+	if err != nil {
+		return 0, err
+	}
+
+	if project == "" || strings.Contains(project, "/") {
+		return 0, fmt.Errorf("invalid Harbor project: %s", project)
+	}
+
+	username, err := utils.PromptPlaintext("Provide the name of a robot account with push and pull access to the project. For example, robot$porter.\nRobot account: ")
+	if err != nil {
+		return 0, err
+	}
+
+	password, err := utils.PromptPassword("Robot account secret: ")
+	if err != nil {
+		return 0, err
+	}
+
+	// create the basic auth integration
+	integration, err := client.CreateBasicAuthIntegration(
+		ctx,
+		projectID,
+		&types.CreateBasicRequest{
+			Username: username,
+			Password: password,
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	color.New(color.FgGreen).Printf("created basic auth integration with id %d\n", integration.ID)
+
+	reg, err := client.CreateRegistry(
+		ctx,
+		projectID,
+		&types.CreateRegistryRequest{
+			URL:                fmt.Sprintf("%s/%s", host, project),
+			Name:               project,
+			BasicIntegrationID: integration.ID,
+			Service:            string(types.Harbor),
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	color.New(color.FgGreen).Printf("created Harbor registry with id %d and name %s\n", reg.ID, reg.Name)
+
+	return reg.ID, nil
+}
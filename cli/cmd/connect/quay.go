@@ -0,0 +1,88 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/karagatandev/porter/api/types"
+
+	"github.com/fatih/color"
+	api "github.com/karagatandev/porter/api/client"
+	"github.com/karagatandev/porter/cli/cmd/utils"
+)
+
+// Quay connects a Quay organization to a Porter project, using a robot account of the organization
+func Quay(
+	ctx context.Context,
+	client api.Client,
+	projectID uint,
+) (uint, error) {
+	// if project ID is 0, ask the user to set the project ID or create a project
+	if projectID == 0 {
+		return 0, fmt.Errorf("no project set, please run porter project set [id]")
+	}
+
+	host, err := utils.PromptPlaintext("Provide the host of your Quay instance, or leave blank to use quay.io.\nHost: ")
+	if err != nil {
+		return 0, err
+	}
+
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "https://"), "/")
+
+	if host == "" {
+		host = "quay.io"
+	}
+
+	org, err := utils.PromptPlaintext("Quay organization: ")
+	if err != nil {
+		return 0, err
+	}
+
+	if org == "" || strings.Contains(org, "/") {
+		return 0, fmt.Errorf("invalid Quay organization: %s", org)
+	}
+
+	username, err := utils.PromptPlaintext(fmt.Sprintf("Provide the name of a robot account with write access to the organization's repositories. For example, %s+porter.\nRobot account: ", org))
+	if err != nil {
+		return 0, err
+	}
+
+	password, err := utils.PromptPassword("Robot account token: ")
+	if err != nil {
+		return 0, err
+	}
+
+	// create the basic auth integration
+	integration, err := client.CreateBasicAuthIntegration(
+		ctx,
+		projectID,
+		&types.CreateBasicRequest{
+			Username: username,
+			Password: password,
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	color.New(color.FgGreen).Printf("created basic auth integration with id %d\n", integration.ID)
+
+	reg, err := client.CreateRegistry(
+		ctx,
+		projectID,
+		&types.CreateRegistryRequest{
+			URL:                fmt.Sprintf("%s/%s", host, org),
+			Name:               org,
+			BasicIntegrationID: integration.ID,
+			Service:            string(types.Quay),
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	color.New(color.FgGreen).Printf("created Quay registry with id %d and name %s\n", reg.ID, reg.Name)
+
+	return reg.ID, nil
+}
@@ -84,8 +84,15 @@ func (b *BuildAgent) BuildPack(ctx context.Context, dockerAgent *docker.Agent, d
 		UseCache:     b.UseCache,
 	}
 
+	if b.UseCache {
+		opts.Cache = &docker.CacheOpts{
+			Type: docker.CacheTypeRegistry,
+			Tag:  "pack-cache",
+		}
+	}
+
 	// call builder
-	return packAgent.Build(ctx, opts, buildConfig, fmt.Sprintf("%s:%s", b.ImageRepo, "pack-cache"))
+	return packAgent.Build(ctx, opts, buildConfig)
 }
 
 // ResolveDockerPaths returns a path to the dockerfile that is either relative or absolute, and a path
@@ -61,6 +61,9 @@ func (a *AuthGetter) GetCredentials(ctx context.Context, serverURL string) (user
 		return a.GetDockerHubCredentials(ctx, serverURL, a.ProjectID)
 	} else if strings.Contains(serverURL, "azurecr.io") {
 		return a.GetACRCredentials(ctx, serverURL, a.ProjectID)
+	} else if !strings.Contains(serverURL, ".dkr.ecr.") {
+		// ghcr.io, quay.io and self-hosted Harbor registries
+		return a.GetRegistryCredentials(ctx, serverURL, a.ProjectID)
 	}
 
 	return a.GetECRCredentials(ctx, serverURL, a.ProjectID)
@@ -280,6 +283,34 @@ func (a *AuthGetter) GetACRCredentials(ctx context.Context, serverURL string, pr
 	return decodeDockerToken(token)
 }
 
+// GetRegistryCredentials returns Harbor, Quay or GHCR credentials
+func (a *AuthGetter) GetRegistryCredentials(ctx context.Context, serverURL string, projID uint) (user string, secret string, err error) {
+	cachedEntry := a.Cache.Get(serverURL)
+	var token string
+
+	if cachedEntry != nil && cachedEntry.IsValid(time.Now()) {
+		token = cachedEntry.AuthorizationToken
+	} else {
+		req := &types.GetRegistryTokenRequest{ServerURL: serverURL}
+		tokenResp, err := a.Client.GetRegistryAuthorizationToken(ctx, projID, req)
+		if err != nil {
+			return "", "", err
+		}
+
+		token = tokenResp.Token
+
+		// set the token in cache
+		a.Cache.Set(serverURL, &AuthEntry{
+			AuthorizationToken: token,
+			RequestedAt:        time.Now(),
+			ExpiresAt:          tokenResp.ExpiresAt,
+			ProxyEndpoint:      serverURL,
+		})
+	}
+
+	return decodeDockerToken(token)
+}
+
 func decodeDockerToken(token string) (string, string, error) {
 	decodedToken, err := base64.StdEncoding.DecodeString(token)
 	if err != nil {
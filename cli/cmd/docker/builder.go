@@ -34,6 +34,16 @@ type BuildOpts struct {
 	IsDockerfileInCtx bool
 	UseCache          bool
 
+	// Cache configures the layer cache of the build. If nil, the cache is imported from the current image.
+	Cache *CacheOpts
+	// BuildKit configures a remote BuildKit daemon to build with instead of the local Docker daemon
+	BuildKit *BuildKitOpts
+	// Platforms are the platforms to build the image for, such as linux/arm64. Defaults to linux/amd64.
+	Platforms []string
+	// Push pushes the image from BuildKit instead of loading it into the local Docker daemon. Images built
+	// for multiple platforms can only be pushed, since the Docker daemon cannot load a manifest list.
+	Push bool
+
 	Env map[string]string
 
 	LogFile *os.File
@@ -50,7 +60,8 @@ func (a *Agent) BuildLocal(ctx context.Context, opts *BuildOpts) (err error) {
 			log.Printf("unable to pull image. Continuing with build: %s", err.Error())
 		}
 	}
-	if os.Getenv("DOCKER_BUILDKIT") == "1" {
+	// exporting the cache, building with a remote daemon and building for other platforms all require buildx
+	if os.Getenv("DOCKER_BUILDKIT") == "1" || opts.Cache != nil || opts.BuildKit != nil || len(opts.Platforms) > 0 || opts.Push {
 		return buildLocalWithBuildkit(ctx, *opts)
 	}
 
@@ -117,7 +128,7 @@ func (a *Agent) BuildLocal(ctx context.Context, opts *BuildOpts) (err error) {
 			fmt.Sprintf("%s:%s", opts.ImageRepo, opts.CurrentTag),
 		},
 		Remove:   true,
-		Platform: "linux/amd64",
+		Platform: defaultPlatform,
 	})
 	if err != nil {
 		return fmt.Errorf("error building image: %w", err)
@@ -212,13 +223,11 @@ func buildLocalWithBuildkit(ctx context.Context, opts BuildOpts) error {
 		extraDockerArgs = parsedFields
 	}
 
-	commandArgs := []string{
-		"buildx",
-		"build",
-		"-f", dockerfileName,
-		"--tag", fmt.Sprintf("%s:%s", opts.ImageRepo, opts.Tag),
-		"--cache-from", fmt.Sprintf("type=registry,ref=%s:%s", opts.ImageRepo, opts.CurrentTag),
+	commandArgs, err := buildxArgs(ctx, opts, dockerfileName)
+	if err != nil {
+		return err
 	}
+
 	for key, val := range opts.Env {
 		if key == "PORTER_BUILDKIT_ARGS" {
 			continue
@@ -226,8 +235,8 @@ func buildLocalWithBuildkit(ctx context.Context, opts BuildOpts) error {
 		commandArgs = append(commandArgs, "--build-arg", fmt.Sprintf("%s=%s", key, val))
 	}
 
-	if !sliceContainsString(extraDockerArgs, "--platform") {
-		commandArgs = append(commandArgs, "--platform", "linux/amd64")
+	if len(opts.Platforms) == 0 && !sliceContainsString(extraDockerArgs, "--platform") {
+		commandArgs = append(commandArgs, "--platform", defaultPlatform)
 	}
 
 	commandArgs = append(commandArgs, extraDockerArgs...)
@@ -279,6 +288,48 @@ func buildLocalWithBuildkit(ctx context.Context, opts BuildOpts) error {
 	return nil
 }
 
+// buildxArgs returns the arguments to "docker buildx build" which select the builder and configure the cache
+func buildxArgs(ctx context.Context, opts BuildOpts, dockerfileName string) ([]string, error) {
+	builder, err := ensureBuilder(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	commandArgs := []string{
+		"buildx",
+		"build",
+		"-f", dockerfileName,
+		"--tag", fmt.Sprintf("%s:%s", opts.ImageRepo, opts.Tag),
+	}
+
+	if len(opts.Platforms) > 0 {
+		commandArgs = append(commandArgs, "--platform", strings.Join(opts.Platforms, ","))
+	}
+
+	if builder != "" {
+		commandArgs = append(commandArgs, "--builder", builder)
+	}
+
+	switch {
+	case opts.Push:
+		commandArgs = append(commandArgs, "--push")
+	case builder != "" && !opts.isMultiPlatform():
+		// images built outside of the local Docker daemon must be loaded into it to be pushed
+		commandArgs = append(commandArgs, "--load")
+	}
+
+	if opts.Cache == nil {
+		return append(commandArgs, "--cache-from", fmt.Sprintf("type=registry,ref=%s:%s", opts.ImageRepo, opts.CurrentTag)), nil
+	}
+
+	cacheArgs, err := opts.Cache.buildxArgs(opts.ImageRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(commandArgs, cacheArgs...), nil
+}
+
 func injectDockerfileIntoBuildContext(buildContext string, dockerfilePath string) (string, error) {
 	randomName := ".dockerfile." + stringid.GenerateRandomID()[:20]
 	data := map[string]func() ([]byte, error){
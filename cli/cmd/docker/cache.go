@@ -0,0 +1,189 @@
+package docker
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CacheType is the backend which a buildkit build imports its layer cache from and exports it to
+type CacheType string
+
+const (
+	// CacheTypeRegistry stores the cache as an image in the app's image repository
+	CacheTypeRegistry CacheType = "registry"
+	// CacheTypeLocal stores the cache in a directory, for CI runners which persist a cache directory between builds
+	CacheTypeLocal CacheType = "local"
+	// CacheTypeGHA stores the cache in the GitHub Actions cache service
+	CacheTypeGHA CacheType = "gha"
+)
+
+const (
+	defaultCacheTag  = "cache"
+	defaultCacheMode = "max"
+
+	// localBuilderName is the name of the buildx builder used to export caches and build for multiple platforms
+	// when building with the local Docker daemon
+	localBuilderName = "porter-builder"
+)
+
+// CacheOpts configures the layer cache of a buildkit build
+type CacheOpts struct {
+	Type CacheType
+	// Mode is "min" to only cache the layers of the final image or "max" to cache all intermediate layers.
+	// Defaults to "max".
+	Mode string
+	// Tag is the tag holding the registry cache in the image repository. Defaults to "cache".
+	Tag string
+	// Dir is the directory holding the local cache
+	Dir string
+	// Scope separates the GitHub Actions caches of different images. Defaults to the image repository.
+	Scope string
+}
+
+// BuildKitOpts configures a remote BuildKit daemon to build with
+type BuildKitOpts struct {
+	// Host is the address of the daemon, either tcp://host:port or kube-pod://pod-name?namespace=ns
+	Host string
+	// CACert, Cert and Key are paths to the TLS files used to connect to a tcp:// daemon
+	CACert string
+	Cert   string
+	Key    string
+}
+
+// CacheImage returns the image holding the registry cache for the given image repository
+func (c *CacheOpts) CacheImage(imageRepo string) string {
+	tag := c.Tag
+	if tag == "" {
+		tag = defaultCacheTag
+	}
+
+	return fmt.Sprintf("%s:%s", imageRepo, tag)
+}
+
+// buildxArgs returns the --cache-from and --cache-to arguments for the cache
+func (c *CacheOpts) buildxArgs(imageRepo string) ([]string, error) {
+	mode := c.Mode
+	if mode == "" {
+		mode = defaultCacheMode
+	}
+
+	if mode != "min" && mode != "max" {
+		return nil, fmt.Errorf("invalid cache mode %q: must be one of min, max", c.Mode)
+	}
+
+	var from, to string
+
+	switch c.Type {
+	case CacheTypeRegistry:
+		ref := c.CacheImage(imageRepo)
+
+		from = fmt.Sprintf("type=registry,ref=%s", ref)
+		to = fmt.Sprintf("type=registry,ref=%s,mode=%s", ref, mode)
+	case CacheTypeLocal:
+		if c.Dir == "" {
+			return nil, errors.New("a directory must be set for the local cache")
+		}
+
+		from = fmt.Sprintf("type=local,src=%s", c.Dir)
+		to = fmt.Sprintf("type=local,dest=%s,mode=%s", c.Dir, mode)
+	case CacheTypeGHA:
+		// buildx reads the cache service credentials from the environment, which are only exposed to
+		// the build step by actions such as crazy-max/ghaction-github-runtime
+		if os.Getenv("ACTIONS_RUNTIME_TOKEN") == "" || os.Getenv("ACTIONS_CACHE_URL") == "" {
+			return nil, errors.New("the gha cache can only be used in GitHub Actions with ACTIONS_RUNTIME_TOKEN and ACTIONS_CACHE_URL set")
+		}
+
+		scope := c.Scope
+		if scope == "" {
+			scope = imageRepo
+		}
+
+		from = fmt.Sprintf("type=gha,scope=%s", scope)
+		to = fmt.Sprintf("type=gha,scope=%s,mode=%s", scope, mode)
+	default:
+		return nil, fmt.Errorf("invalid cache type %q: must be one of %s, %s, %s", c.Type, CacheTypeRegistry, CacheTypeLocal, CacheTypeGHA)
+	}
+
+	return []string{"--cache-from", from, "--cache-to", to}, nil
+}
+
+// name returns a stable name for the buildx builder connected to the daemon
+func (b *BuildKitOpts) name() string {
+	return fmt.Sprintf("porter-%x", sha256.Sum256([]byte(b.Host)))[:19]
+}
+
+// createArgs returns the arguments to create a buildx builder connected to the daemon
+func (b *BuildKitOpts) createArgs() ([]string, error) {
+	u, err := url.Parse(b.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid buildkit host %q: %w", b.Host, err)
+	}
+
+	if u.Scheme != "tcp" && u.Scheme != "kube-pod" {
+		return nil, fmt.Errorf("invalid buildkit host %q: must start with tcp:// or kube-pod://", b.Host)
+	}
+
+	args := []string{"buildx", "create", "--name", b.name(), "--driver", "remote"}
+
+	var driverOpts []string
+
+	for _, opt := range []struct{ key, path string }{
+		{"cacert", b.CACert},
+		{"cert", b.Cert},
+		{"key", b.Key},
+	} {
+		if opt.path != "" {
+			driverOpts = append(driverOpts, fmt.Sprintf("%s=%s", opt.key, opt.path))
+		}
+	}
+
+	if len(driverOpts) > 0 {
+		args = append(args, "--driver-opt", strings.Join(driverOpts, ","))
+	}
+
+	return append(args, b.Host), nil
+}
+
+// ensureBuilder returns the name of the buildx builder to run the build with, creating it if it doesn't exist.
+// The default builder of the local Docker daemon is used, by returning an empty name, unless the build targets
+// a remote daemon, exports its cache or builds for multiple platforms, which the default docker driver does not support.
+func ensureBuilder(ctx context.Context, opts BuildOpts) (string, error) {
+	var name string
+	var createArgs []string
+
+	switch {
+	case opts.BuildKit != nil:
+		args, err := opts.BuildKit.createArgs()
+		if err != nil {
+			return "", err
+		}
+
+		name, createArgs = opts.BuildKit.name(), args
+	case opts.Cache != nil || opts.isMultiPlatform():
+		name = localBuilderName
+		createArgs = []string{"buildx", "create", "--name", name, "--driver", "docker-container"}
+	default:
+		return "", nil
+	}
+
+	// #nosec G204 - the builder name is derived from the build options
+	if err := exec.CommandContext(ctx, "docker", "buildx", "inspect", name).Run(); err == nil {
+		return name, nil
+	}
+
+	fmt.Println("Creating buildx builder:", name)
+
+	// #nosec G204 - the builder is created from the build options
+	out, err := exec.CommandContext(ctx, "docker", createArgs...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("unable to create buildx builder %s: %s: %w", name, strings.TrimSpace(string(out)), err)
+	}
+
+	return name, nil
+}
@@ -0,0 +1,93 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCacheBuildxArgs(t *testing.T) {
+	t.Setenv("ACTIONS_RUNTIME_TOKEN", "token")
+	t.Setenv("ACTIONS_CACHE_URL", "https://cache.example.com/")
+
+	tests := []struct {
+		name     string
+		cache    CacheOpts
+		expected []string
+	}{
+		{
+			name:  "registry cache defaults to a dedicated tag in max mode",
+			cache: CacheOpts{Type: CacheTypeRegistry},
+			expected: []string{
+				"--cache-from", "type=registry,ref=registry.example.com/app:cache",
+				"--cache-to", "type=registry,ref=registry.example.com/app:cache,mode=max",
+			},
+		},
+		{
+			name:  "local cache",
+			cache: CacheOpts{Type: CacheTypeLocal, Dir: "/tmp/cache", Mode: "min"},
+			expected: []string{
+				"--cache-from", "type=local,src=/tmp/cache",
+				"--cache-to", "type=local,dest=/tmp/cache,mode=min",
+			},
+		},
+		{
+			name:  "gha cache",
+			cache: CacheOpts{Type: CacheTypeGHA, Scope: "web"},
+			expected: []string{
+				"--cache-from", "type=gha,scope=web",
+				"--cache-to", "type=gha,scope=web,mode=max",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := tt.cache.buildxArgs("registry.example.com/app")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(args, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, args)
+			}
+		})
+	}
+
+	for _, cache := range []CacheOpts{
+		{Type: "s3"},
+		{Type: CacheTypeLocal},
+		{Type: CacheTypeRegistry, Mode: "all"},
+	} {
+		if _, err := cache.buildxArgs("registry.example.com/app"); err == nil {
+			t.Errorf("expected an error for cache %+v", cache)
+		}
+	}
+}
+
+func TestBuildKitCreateArgs(t *testing.T) {
+	buildKit := BuildKitOpts{
+		Host:   "tcp://buildkitd:1234",
+		CACert: "ca.pem",
+		Cert:   "cert.pem",
+		Key:    "key.pem",
+	}
+
+	args, err := buildKit.createArgs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"buildx", "create", "--name", buildKit.name(), "--driver", "remote",
+		"--driver-opt", "cacert=ca.pem,cert=cert.pem,key=key.pem",
+		"tcp://buildkitd:1234",
+	}
+
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %v, got %v", expected, args)
+	}
+
+	if _, err := (&BuildKitOpts{Host: "buildkitd:1234"}).createArgs(); err == nil {
+		t.Errorf("expected an error for a host without a scheme")
+	}
+}
@@ -0,0 +1,104 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// defaultPlatform is the platform images are built for when no platforms are set
+const defaultPlatform = "linux/amd64"
+
+// ImagePlatforms returns the platforms, such as linux/arm64, which an image in a registry has a manifest for
+func (a *Agent) ImagePlatforms(ctx context.Context, image string) ([]string, error) {
+	encodedRegistryAuth, err := a.getEncodedRegistryAuth(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	inspect, err := a.DistributionInspect(ctx, image, encodedRegistryAuth)
+	if err != nil {
+		return nil, a.handleDockerClientErr(err, "Could not inspect image "+image)
+	}
+
+	platforms := make([]string, 0, len(inspect.Platforms))
+
+	for _, platform := range inspect.Platforms {
+		p := fmt.Sprintf("%s/%s", platform.OS, platform.Architecture)
+
+		if platform.Variant != "" {
+			p += "/" + platform.Variant
+		}
+
+		platforms = append(platforms, p)
+	}
+
+	return platforms, nil
+}
+
+// knownArchitectures are the node architectures, as reported by the kubernetes.io/arch label, which images can be built for
+var knownArchitectures = map[string]bool{
+	"386":      true,
+	"amd64":    true,
+	"arm":      true,
+	"arm64":    true,
+	"ppc64le":  true,
+	"riscv64":  true,
+	"s390x":    true,
+	"mips64le": true,
+}
+
+// IsKnownArchitecture returns true if images can be built for the node architecture arch
+func IsKnownArchitecture(arch string) bool {
+	return knownArchitectures[arch]
+}
+
+// MissingArchitectures returns the architectures, such as arm64, which none of the platforms are built for
+func MissingArchitectures(platforms []string, archs []string) []string {
+	var missing []string
+
+	for _, arch := range archs {
+		found := false
+
+		for _, platform := range platforms {
+			if parts := strings.Split(platform, "/"); len(parts) >= 2 && parts[1] == arch {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, arch)
+		}
+	}
+
+	return missing
+}
+
+// MissingPlatforms returns the platforms, such as linux/arm64, which an image built for imagePlatforms has no manifest for.
+// A platform without a variant matches a manifest of any variant, so linux/arm64 is satisfied by linux/arm64/v8
+func MissingPlatforms(imagePlatforms []string, platforms []string) []string {
+	var missing []string
+
+	for _, platform := range platforms {
+		found := false
+
+		for _, imagePlatform := range imagePlatforms {
+			if imagePlatform == platform || strings.HasPrefix(imagePlatform, platform+"/") {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, platform)
+		}
+	}
+
+	return missing
+}
+
+// isMultiPlatform returns true if the build produces a manifest list, which cannot be loaded into the local Docker daemon
+func (o BuildOpts) isMultiPlatform() bool {
+	return len(o.Platforms) > 1
+}
@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingArchitectures(t *testing.T) {
+	tests := []struct {
+		name      string
+		platforms []string
+		archs     []string
+		expected  []string
+	}{
+		{
+			name:      "manifest list covers all nodes",
+			platforms: []string{"linux/amd64", "linux/arm64/v8"},
+			archs:     []string{"amd64", "arm64"},
+		},
+		{
+			name:      "single platform image on a mixed cluster",
+			platforms: []string{"linux/amd64"},
+			archs:     []string{"amd64", "arm64"},
+			expected:  []string{"arm64"},
+		},
+		{
+			name:      "no node architectures reported",
+			platforms: []string{"linux/arm64"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing := MissingArchitectures(tt.platforms, tt.archs)
+
+			if !reflect.DeepEqual(missing, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, missing)
+			}
+		})
+	}
+}
+
+func TestMissingPlatforms(t *testing.T) {
+	tests := []struct {
+		name           string
+		imagePlatforms []string
+		platforms      []string
+		expected       []string
+	}{
+		{
+			name:           "manifest list covers all platforms",
+			imagePlatforms: []string{"linux/amd64", "linux/arm64/v8"},
+			platforms:      []string{"linux/amd64", "linux/arm64"},
+		},
+		{
+			name:           "manifest list is missing a platform",
+			imagePlatforms: []string{"linux/amd64"},
+			platforms:      []string{"linux/amd64", "linux/arm64"},
+			expected:       []string{"linux/arm64"},
+		},
+		{
+			name:           "variant does not match another variant",
+			imagePlatforms: []string{"linux/arm/v6"},
+			platforms:      []string{"linux/arm/v7"},
+			expected:       []string{"linux/arm/v7"},
+		},
+		{
+			name:           "no platforms set",
+			imagePlatforms: []string{"linux/amd64"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing := MissingPlatforms(tt.imagePlatforms, tt.platforms)
+
+			if !reflect.DeepEqual(missing, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, missing)
+			}
+		})
+	}
+}
+
+func TestIsKnownArchitecture(t *testing.T) {
+	tests := []struct {
+		arch     string
+		expected bool
+	}{
+		{arch: "amd64", expected: true},
+		{arch: "arm64", expected: true},
+		{arch: ""},
+		{arch: "unknown"},
+		{arch: "linux/arm64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arch, func(t *testing.T) {
+			if known := IsKnownArchitecture(tt.arch); known != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, known)
+			}
+		})
+	}
+}
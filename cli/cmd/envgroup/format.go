@@ -0,0 +1,253 @@
+package envgroup
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Format is a file format environment groups can be exported to and imported from
+type Format string
+
+const (
+	// FormatDotenv is the .env format. Secrets and files are marked with a comment on the line before them
+	FormatDotenv Format = "dotenv"
+	// FormatJSON is the JSON format
+	FormatJSON Format = "json"
+	// FormatYAML is the YAML format
+	FormatYAML Format = "yaml"
+)
+
+const (
+	// dotenvSecretMarker marks the next line of a .env file as a secret
+	dotenvSecretMarker = "# porter:secret"
+	// dotenvFileMarker marks the next line of a .env file as a file, whose value is the base64-encoded file contents
+	dotenvFileMarker = "# porter:file"
+)
+
+// EnvGroupFile is a file belonging to an environment group
+type EnvGroupFile struct {
+	Name     string `json:"name"`
+	Contents string `json:"contents"`
+}
+
+// EnvGroup holds the contents of an environment group in a form which can be written to and read from a file
+type EnvGroup struct {
+	Variables map[string]string `json:"variables,omitempty"`
+	Secrets   map[string]string `json:"secrets,omitempty"`
+	Files     []EnvGroupFile    `json:"files,omitempty"`
+}
+
+// ParseFormat returns the format with the given name, or the format matching the extension of path if name is empty
+func ParseFormat(name, path string) (Format, error) {
+	if name == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			return FormatJSON, nil
+		case ".yaml", ".yml":
+			return FormatYAML, nil
+		default:
+			return FormatDotenv, nil
+		}
+	}
+
+	switch Format(strings.ToLower(name)) {
+	case FormatDotenv, "env", ".env":
+		return FormatDotenv, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	}
+
+	return "", fmt.Errorf("unsupported format %s: must be one of dotenv, json or yaml", name)
+}
+
+// Marshal writes an environment group in the given format
+func Marshal(eg EnvGroup, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(eg, "", "  ")
+	case FormatYAML:
+		return yaml.Marshal(eg)
+	case FormatDotenv:
+		return marshalDotenv(eg), nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %s", format)
+}
+
+// Unmarshal reads an environment group in the given format
+func Unmarshal(data []byte, format Format) (EnvGroup, error) {
+	var eg EnvGroup
+
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &eg); err != nil {
+			return eg, fmt.Errorf("invalid JSON: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &eg); err != nil {
+			return eg, fmt.Errorf("invalid YAML: %w", err)
+		}
+	case FormatDotenv:
+		var err error
+		if eg, err = unmarshalDotenv(data); err != nil {
+			return eg, err
+		}
+	default:
+		return eg, fmt.Errorf("unsupported format %s", format)
+	}
+
+	for k := range eg.Variables {
+		if _, ok := eg.Secrets[k]; ok {
+			return eg, fmt.Errorf("key %s is both a variable and a secret", k)
+		}
+	}
+
+	return eg, nil
+}
+
+// MarkSecrets moves variables to the secrets of the environment group. Keys which are already secrets are ignored.
+func (eg *EnvGroup) MarkSecrets(keys []string) error {
+	for _, key := range keys {
+		if _, ok := eg.Secrets[key]; ok {
+			continue
+		}
+
+		value, ok := eg.Variables[key]
+		if !ok {
+			return fmt.Errorf("secret %s is not set", key)
+		}
+
+		if eg.Secrets == nil {
+			eg.Secrets = make(map[string]string)
+		}
+
+		eg.Secrets[key] = value
+		delete(eg.Variables, key)
+	}
+
+	return nil
+}
+
+func marshalDotenv(eg EnvGroup) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("# Generated by Porter CLI\n")
+
+	for _, k := range sortedKeys(eg.Variables) {
+		fmt.Fprintf(&buf, "%s=%s\n", k, quoteDotenvValue(eg.Variables[k]))
+	}
+
+	for _, k := range sortedKeys(eg.Secrets) {
+		fmt.Fprintf(&buf, "%s\n%s=%s\n", dotenvSecretMarker, k, quoteDotenvValue(eg.Secrets[k]))
+	}
+
+	for _, file := range eg.Files {
+		fmt.Fprintf(&buf, "%s\n%s=%s\n", dotenvFileMarker, file.Name, base64.StdEncoding.EncodeToString([]byte(file.Contents)))
+	}
+
+	return buf.Bytes()
+}
+
+// quoteDotenvValue quotes values which can't be written as-is on a single line
+func quoteDotenvValue(value string) string {
+	if value == "" || strings.ContainsAny(value, "\n\r\t\"'#\\ ") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+func unmarshalDotenv(data []byte) (EnvGroup, error) {
+	eg := EnvGroup{
+		Variables: make(map[string]string),
+		Secrets:   make(map[string]string),
+	}
+
+	marker := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == dotenvSecretMarker || line == dotenvFileMarker {
+			marker = line
+			continue
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return eg, fmt.Errorf("line %d: expected KEY=value", lineNumber)
+		}
+
+		value, err := unquoteDotenvValue(strings.TrimSpace(value))
+		if err != nil {
+			return eg, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		switch marker {
+		case dotenvSecretMarker:
+			eg.Secrets[key] = value
+		case dotenvFileMarker:
+			contents, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return eg, fmt.Errorf("line %d: file %s is not base64-encoded: %w", lineNumber, key, err)
+			}
+			eg.Files = append(eg.Files, EnvGroupFile{Name: key, Contents: string(contents)})
+		default:
+			eg.Variables[key] = value
+		}
+
+		marker = ""
+	}
+
+	if err := scanner.Err(); err != nil {
+		return eg, err
+	}
+
+	return eg, nil
+}
+
+func unquoteDotenvValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		return unquoted, nil
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		return value[1 : len(value)-1], nil
+	}
+
+	return value, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,110 @@
+package envgroup
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	eg := EnvGroup{
+		Variables: map[string]string{
+			"PORT":     "8080",
+			"GREETING": "hello world",
+			"EMPTY":    "",
+		},
+		Secrets: map[string]string{
+			"DATABASE_URL": "postgres://user:p#ss@db:5432/app",
+		},
+		Files: []EnvGroupFile{
+			{Name: "config.json", Contents: "{\n  \"debug\": true\n}\n"},
+		},
+	}
+
+	for _, format := range []Format{FormatDotenv, FormatJSON, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := Marshal(eg, format)
+			if err != nil {
+				t.Fatalf("unexpected error marshaling: %v", err)
+			}
+
+			got, err := Unmarshal(data, format)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshaling: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, eg) {
+				t.Errorf("expected %+v, got %+v", eg, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalDotenv(t *testing.T) {
+	data := []byte(`# comment
+export PORT=8080
+NAME='single quoted'
+# porter:secret
+TOKEN="abc\ndef"
+`)
+
+	got, err := Unmarshal(data, FormatDotenv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := EnvGroup{
+		Variables: map[string]string{"PORT": "8080", "NAME": "single quoted"},
+		Secrets:   map[string]string{"TOKEN": "abc\ndef"},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	if _, err := Unmarshal([]byte("NOT_A_PAIR\n"), FormatDotenv); err == nil {
+		t.Errorf("expected an error for a line without a value")
+	}
+}
+
+func TestMarkSecrets(t *testing.T) {
+	eg := EnvGroup{Variables: map[string]string{"PORT": "8080", "TOKEN": "abc"}}
+
+	if err := eg.MarkSecrets([]string{"TOKEN"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := eg.Variables["TOKEN"]; ok || eg.Secrets["TOKEN"] != "abc" {
+		t.Errorf("expected TOKEN to be a secret, got %+v", eg)
+	}
+
+	if err := eg.MarkSecrets([]string{"MISSING"}); err == nil {
+		t.Errorf("expected an error for a key which is not set")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected Format
+	}{
+		{path: "prod.env", expected: FormatDotenv},
+		{path: "prod.json", expected: FormatJSON},
+		{path: "prod.yml", expected: FormatYAML},
+		{name: "yaml", path: "prod.env", expected: FormatYAML},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.name, tt.path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.expected {
+			t.Errorf("%s %s: expected %s, got %s", tt.name, tt.path, tt.expected, got)
+		}
+	}
+
+	if _, err := ParseFormat("toml", ""); err == nil {
+		t.Errorf("expected an error for an unsupported format")
+	}
+}
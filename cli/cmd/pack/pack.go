@@ -35,8 +35,16 @@ func init() {
 // Agent is a buildpack agent
 type Agent struct{}
 
-// Build manages buildpack builds
-func (a *Agent) Build(ctx context.Context, opts *docker.BuildOpts, buildConfig *types.BuildConfig, cacheImage string) error {
+// Build manages buildpack builds. Pack can only cache to a registry, so a registry cache is used when
+// opts.UseCache is set without a cache configuration.
+func (a *Agent) Build(ctx context.Context, opts *docker.BuildOpts, buildConfig *types.BuildConfig) error {
+	// pack builds for the architecture of the Docker daemon, so it cannot cross-build or produce a manifest list
+	for _, platform := range opts.Platforms {
+		if platform != "linux/amd64" {
+			return fmt.Errorf("platform %s is not supported by pack builds: use the docker build method to build for other platforms", platform)
+		}
+	}
+
 	absPath, err := filepath.Abs(opts.BuildContext)
 	if err != nil {
 		return err
@@ -61,8 +69,17 @@ func (a *Agent) Build(ctx context.Context, opts *docker.BuildOpts, buildConfig *
 		GroupID:         0,
 	}
 
-	if opts.UseCache {
-		buildOpts.CacheImage = cacheImage
+	cache := opts.Cache
+	if cache == nil && opts.UseCache {
+		cache = &docker.CacheOpts{Type: docker.CacheTypeRegistry}
+	}
+
+	if cache != nil {
+		if cache.Type != docker.CacheTypeRegistry {
+			return fmt.Errorf("cache type %q is not supported by pack builds: only %q is supported", cache.Type, docker.CacheTypeRegistry)
+		}
+
+		buildOpts.CacheImage = cache.CacheImage(opts.ImageRepo)
 		buildOpts.Publish = true
 	}
 
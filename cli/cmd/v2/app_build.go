@@ -39,6 +39,8 @@ type AppBuildInput struct {
 	PatchOperations []v2.PatchOperation
 	// PullImageBeforeBuild is a flag indicating whether to pull the previous image before building
 	PullImageBeforeBuild bool
+	// PorterYamlPath is the path to a porter.yaml file with the cache and BuildKit settings for the build, if provided
+	PorterYamlPath string
 }
 
 // AppBuild builds an app using a combination of the provided flag values and build settings from the latest app revision
@@ -109,6 +111,17 @@ func AppBuild(ctx context.Context, inp AppBuildInput) error {
 		return fmt.Errorf("error creating build input from build settings: %w", err)
 	}
 
+	if inp.PorterYamlPath != "" {
+		localSettings, err := localBuildSettingsFromPorterYAML(inp.PorterYamlPath)
+		if err != nil {
+			return fmt.Errorf("error reading build settings from porter yaml: %w", err)
+		}
+
+		buildInput.Cache = localSettings.Cache
+		buildInput.BuildKit = localSettings.BuildKit
+		buildInput.Platforms = localSettings.Platforms
+	}
+
 	// skip push when only a build is requested
 	buildInput.SkipPush = true
 
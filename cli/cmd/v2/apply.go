@@ -24,6 +24,7 @@ import (
 
 	api "github.com/karagatandev/porter/api/client"
 	"github.com/karagatandev/porter/cli/cmd/config"
+	"github.com/karagatandev/porter/cli/cmd/docker"
 )
 
 // ApplyInput is the input for the Apply function
@@ -52,6 +53,8 @@ type ApplyInput struct {
 	PatchOperations []v2.PatchOperation
 	// SkipBuild is true when Apply should skip the build step
 	SkipBuild bool
+	// BreakGlassReason overrides the freeze windows and protection rules of the deployment target
+	BreakGlassReason string
 }
 
 // Apply implements the functionality of the `porter apply` command for validate apply v2 projects
@@ -146,6 +149,7 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 		WithPredeploy:      inp.WithPredeploy,
 		Exact:              inp.Exact,
 		PatchOperations:    inp.PatchOperations,
+		BreakGlassReason:   inp.BreakGlassReason,
 	}
 
 	updateResp, err := client.UpdateApp(ctx, updateInput)
@@ -157,8 +161,16 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 		return errors.New("app revision id is empty")
 	}
 
+	printWarnings(updateResp.Warnings)
+
 	appName := updateResp.AppName
 
+	// applies of an image are held before a revision is created, while applies which are built are held once built
+	if updateResp.PendingApproval != nil && !updateResp.PendingApproval.AwaitingBuild {
+		printPendingApproval(appName, updateResp.PendingApproval)
+		return nil
+	}
+
 	buildSettings, err := client.GetBuildFromRevision(ctx, api.GetBuildFromRevisionInput{
 		ProjectID:     cliConf.Project,
 		ClusterID:     cliConf.Cluster,
@@ -237,6 +249,18 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 			return buildError
 		}
 
+		if porterYamlExists {
+			localSettings, err := localBuildSettingsFromPorterYAML(inp.PorterYamlPath)
+			if err != nil {
+				buildError = fmt.Errorf("error reading build settings from porter yaml: %w", err)
+				return buildError
+			}
+
+			buildInput.Cache = localSettings.Cache
+			buildInput.BuildKit = localSettings.BuildKit
+			buildInput.Platforms = localSettings.Platforms
+		}
+
 		buildOutput := build(ctx, client, buildInput)
 		if buildOutput.Error != nil {
 			buildError = fmt.Errorf("error building app: %w", buildOutput.Error)
@@ -244,18 +268,31 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 			return buildError
 		}
 
-		_, err = client.UpdateRevisionStatus(ctx, cliConf.Project, cliConf.Cluster, appName, updateResp.AppRevisionId, models.AppRevisionStatus_BuildSuccessful)
+		err = checkImageArchitectures(ctx, client, cliConf.Project, cliConf.Cluster, buildOutput.Image, buildInput.Platforms)
+		if err != nil {
+			buildError = err
+			return buildError
+		}
+
+		statusResp, err := client.UpdateRevisionStatus(ctx, cliConf.Project, cliConf.Cluster, appName, updateResp.AppRevisionId, models.AppRevisionStatus_BuildSuccessful)
 		if err != nil {
 			buildError = fmt.Errorf("error updating revision status post build: %w", err)
 			return buildError
 		}
 
+		printWarnings(statusResp.Warnings)
+
 		color.New(color.FgGreen).Printf("Successfully built image (tag: %s)\n", commitSHA) // nolint:errcheck,gosec
 
 		buildMetadata := make(map[string]interface{})
 		buildMetadata["end_time"] = time.Now().UTC()
 		_ = updateExistingEvent(ctx, client, appName, cliConf.Project, cliConf.Cluster, deploymentTargetID, types.PorterAppEventType_Build, eventID, types.PorterAppEventStatus_Success, buildMetadata)
 		buildFinished = true
+
+		if statusResp.PendingApproval != nil {
+			printPendingApproval(appName, statusResp.PendingApproval)
+			return nil
+		}
 	}
 
 	color.New(color.FgGreen).Printf("Deploying new revision %s for app %s...\n", updateResp.AppRevisionId, appName) // nolint:errcheck,gosec
@@ -539,3 +576,61 @@ func correctBuildContext(buildContext string) string {
 	}
 	return buildContext
 }
+
+// checkImageArchitectures checks the platforms of a pushed image before it is deployed. The deploy fails if the image has no
+// manifest for one of the platforms, such as linux/arm64, set in build.platforms. A warning is printed if the image is not built
+// for an architecture among the cluster's nodes, since pods scheduled on those nodes fail to start, but the deploy does not fail,
+// as the app may not be scheduled on them
+func checkImageArchitectures(ctx context.Context, client api.Client, projectID, clusterID uint, image string, platforms []string) error {
+	if image == "" {
+		return nil
+	}
+
+	dockerAgent, err := docker.NewAgentWithAuthGetter(ctx, client, projectID)
+	if err != nil {
+		return fmt.Errorf("error getting docker agent: %w", err)
+	}
+
+	imagePlatforms, err := dockerAgent.ImagePlatforms(ctx, image)
+	if err != nil {
+		if len(platforms) > 0 {
+			return fmt.Errorf("unable to verify that image %s is built for %s: %w", image, strings.Join(platforms, ", "), err)
+		}
+
+		color.New(color.FgYellow).Printf("Skipping image architecture check: unable to inspect image %s: %s\n", image, err.Error()) // nolint:errcheck,gosec
+		return nil
+	}
+
+	if missing := docker.MissingPlatforms(imagePlatforms, platforms); len(missing) > 0 {
+		return fmt.Errorf("image %s is not built for %s, which is set in build.platforms", image, strings.Join(missing, ", "))
+	}
+
+	resp, err := client.ListNodeArchitectures(ctx, projectID, clusterID)
+	if err != nil {
+		color.New(color.FgYellow).Printf("Skipping image architecture check: unable to list the architectures of the cluster's nodes: %s\n", err.Error()) // nolint:errcheck,gosec
+		return nil
+	}
+
+	var archs []string
+	for _, arch := range resp.Architectures {
+		if !docker.IsKnownArchitecture(arch) {
+			color.New(color.FgYellow).Printf("Skipping image architecture check for the cluster's %s nodes: unknown architecture\n", arch) // nolint:errcheck,gosec
+			continue
+		}
+
+		archs = append(archs, arch)
+	}
+
+	if missing := docker.MissingArchitectures(imagePlatforms, archs); len(missing) > 0 {
+		color.New(color.FgYellow).Printf("Warning: image %s is not built for the %s architecture of some of the cluster's nodes, so pods scheduled on them will fail to start: add the missing platforms to build.platforms in porter.yaml\n", image, strings.Join(missing, ", ")) // nolint:errcheck,gosec
+	}
+
+	return nil
+}
+
+// printWarnings prints the warnings returned by the server for a deploy, such as images which could not be verified
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		color.New(color.FgYellow).Printf("Warning: %s\n", warning) // nolint:errcheck,gosec
+	}
+}
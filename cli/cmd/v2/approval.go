@@ -0,0 +1,102 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/karagatandev/porter/api/client"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/cli/cmd/config"
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// DecideApprovalInput is the input for the DecideApproval function
+type DecideApprovalInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// AppName is the name of the app
+	AppName string
+	// AppRevisionID is the id of the revision held for approval
+	AppRevisionID string
+	// Reject rejects the revision instead of approving it
+	Reject bool
+	// Reason is recorded with the decision
+	Reason string
+}
+
+// DecideApproval approves or rejects a revision which is held for approval
+func DecideApproval(ctx context.Context, inp DecideApprovalInput) error {
+	status := models.AppRevisionStatus_Approved
+	if inp.Reject {
+		status = models.AppRevisionStatus_ApprovalRejected
+	}
+
+	resp, err := inp.Client.DecideRevisionApproval(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.AppName, inp.AppRevisionID, status, inp.Reason)
+	if err != nil {
+		return fmt.Errorf("error deciding revision approval: %w", err)
+	}
+
+	if resp.Approval == nil {
+		return fmt.Errorf("approval of revision %s is missing from response", inp.AppRevisionID)
+	}
+
+	if inp.Reject {
+		color.New(color.FgGreen).Printf("Rejected revision %s of %s\n", inp.AppRevisionID, inp.AppName) // nolint:errcheck,gosec
+		return nil
+	}
+
+	switch {
+	case resp.Approval.DeployedAppRevisionID != "":
+		color.New(color.FgGreen).Printf("Approved revision %s of %s, deploying it as revision %s\n", inp.AppRevisionID, inp.AppName, resp.Approval.DeployedAppRevisionID) // nolint:errcheck,gosec
+	case resp.Approval.AwaitingBuild:
+		color.New(color.FgGreen).Printf("Approved revision %s of %s, which will deploy once it is built\n", inp.AppRevisionID, inp.AppName) // nolint:errcheck,gosec
+	default:
+		color.New(color.FgGreen).Printf("Approved revision %s of %s, deploying it now\n", inp.AppRevisionID, inp.AppName) // nolint:errcheck,gosec
+	}
+
+	return nil
+}
+
+// ListApprovalsInput is the input for the ListApprovals function
+type ListApprovalsInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// AppName is the name of the app
+	AppName string
+}
+
+// ListApprovals prints the revisions of an app which were held for approval, newest first
+func ListApprovals(ctx context.Context, inp ListApprovalsInput) error {
+	resp, err := inp.Client.ListAppRevisionApprovals(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.AppName)
+	if err != nil {
+		return fmt.Errorf("error listing revision approvals: %w", err)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "REVISION", "STATUS", "REQUESTED", "EXPIRES", "REASON") // nolint:errcheck,gosec
+	for _, approval := range resp.Approvals {
+		expires := ""
+		if models.AppRevisionStatus(approval.Status) == models.AppRevisionStatus_AwaitingApproval {
+			expires = approval.ExpiresAt.Local().Format(time.RFC1123)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", approval.AppRevisionID, approval.Status, approval.CreatedAt.Local().Format(time.RFC1123), expires, approval.Reason) // nolint:errcheck,gosec
+	}
+
+	return w.Flush()
+}
+
+func printPendingApproval(appName string, approval *types.AppRevisionApproval) {
+	color.New(color.FgYellow).Printf("Revision %s of %s is awaiting approval, and will deploy once it is approved before %s\n", approval.AppRevisionID, appName, approval.ExpiresAt.Local().Format(time.RFC1123)) // nolint:errcheck,gosec
+	color.New(color.FgYellow).Printf("It can be approved with: porter app approve %s --revision %s\n", appName, approval.AppRevisionID)                                                                           // nolint:errcheck,gosec
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/karagatandev/porter/cli/cmd/pack"
 
 	"github.com/karagatandev/porter/cli/cmd/docker"
+	v2 "github.com/karagatandev/porter/internal/porter_app/v2"
+	"gopkg.in/yaml.v2"
 
 	api "github.com/karagatandev/porter/api/client"
 )
@@ -44,11 +46,18 @@ type buildInput struct {
 	Env map[string]string
 	// SkipPush is used to skip pushing the image to the registry
 	SkipPush bool
+	// Cache configures the layer cache of the build, and BuildKit a remote BuildKit daemon to build with
+	Cache    *docker.CacheOpts
+	BuildKit *docker.BuildKitOpts
+	// Platforms are the platforms to build the image for, such as linux/arm64
+	Platforms []string
 }
 
 type buildOutput struct {
 	Error error
 	Logs  string
+	// Image is the image which was pushed to the registry, if the build pushed one
+	Image string
 }
 
 // build will create an image repository if it does not exist, and then build and push the image
@@ -98,6 +107,8 @@ func build(ctx context.Context, client api.Client, inp buildInput) buildOutput {
 	// temp file gets cleaned up when os exits (i.e. when the GHA completes), so no need to remove it manually
 	logFile, _ := os.CreateTemp("", buildLogFilename)
 
+	var pushedByBuildKit bool
+
 	switch inp.BuildMethod {
 	case buildMethodDocker:
 		basePath, err := filepath.Abs(".")
@@ -126,8 +137,15 @@ func build(ctx context.Context, client api.Client, inp buildInput) buildOutput {
 			Env:               inp.Env,
 			LogFile:           logFile,
 			UseCache:          inp.PullImageBeforeBuild,
+			Cache:             inp.Cache,
+			BuildKit:          inp.BuildKit,
+			Platforms:         inp.Platforms,
+			// a manifest list for multiple platforms can't be loaded into the local Docker daemon, so it is pushed by BuildKit
+			Push: len(inp.Platforms) > 1 && !inp.SkipPush,
 		}
 
+		pushedByBuildKit = opts.Push
+
 		err = dockerAgent.BuildLocal(
 			ctx,
 			opts,
@@ -155,6 +173,13 @@ func build(ctx context.Context, client api.Client, inp buildInput) buildOutput {
 			BuildContext: inp.BuildContext,
 			Env:          inp.Env,
 			LogFile:      logFile,
+			Cache:        inp.Cache,
+			Platforms:    inp.Platforms,
+		}
+
+		if inp.BuildKit != nil {
+			output.Error = errors.New("buildkit settings are only supported with the docker build method")
+			return output
 		}
 
 		buildConfig := &types.BuildConfig{
@@ -169,7 +194,7 @@ func build(ctx context.Context, client api.Client, inp buildInput) buildOutput {
 			opts.Env["ALLOW_EOL_SHIMMED_BUILDER"] = "1"
 		}
 
-		err := packAgent.Build(ctx, opts, buildConfig, "")
+		err := packAgent.Build(ctx, opts, buildConfig)
 		if err != nil {
 			output.Error = fmt.Errorf("error building image with pack: %w", err)
 			logString := "Error reading contents of build log file"
@@ -189,7 +214,7 @@ func build(ctx context.Context, client api.Client, inp buildInput) buildOutput {
 		return output
 	}
 
-	if !inp.SkipPush {
+	if !inp.SkipPush && !pushedByBuildKit {
 		err = dockerAgent.PushImage(ctx, fmt.Sprintf("%s:%s", repositoryURL, tag))
 		if err != nil {
 			output.Error = fmt.Errorf("error pushing image: %w", err)
@@ -197,6 +222,10 @@ func build(ctx context.Context, client api.Client, inp buildInput) buildOutput {
 		}
 	}
 
+	if !inp.SkipPush {
+		output.Image = fmt.Sprintf("%s:%s", repositoryURL, tag)
+	}
+
 	return output
 }
 
@@ -324,3 +353,56 @@ func resolveDockerPaths(basePath string, buildContextPath string, dockerfilePath
 
 	return absoluteBuildContextPath, outputDockerfilePath, isDockerfileRelative, nil
 }
+
+// localBuildSettings are the build settings which only apply to builds run by the CLI, so they are read from
+// the local porter.yaml rather than stored with the app
+type localBuildSettings struct {
+	Cache     *docker.CacheOpts
+	BuildKit  *docker.BuildKitOpts
+	Platforms []string
+}
+
+// localBuildSettingsFromPorterYAML reads the cache, BuildKit and platform settings from the build block of a local porter.yaml
+func localBuildSettingsFromPorterYAML(porterYamlPath string) (localBuildSettings, error) {
+	var settings localBuildSettings
+
+	porterYaml, err := os.ReadFile(filepath.Clean(porterYamlPath))
+	if err != nil {
+		return settings, fmt.Errorf("could not read porter yaml file: %w", err)
+	}
+
+	var app struct {
+		Build *v2.Build `yaml:"build"`
+	}
+
+	if err := yaml.Unmarshal(porterYaml, &app); err != nil {
+		return settings, fmt.Errorf("error unmarshaling porter yaml: %w", err)
+	}
+
+	if app.Build == nil {
+		return settings, nil
+	}
+
+	if app.Build.Cache != nil {
+		settings.Cache = &docker.CacheOpts{
+			Type:  docker.CacheType(app.Build.Cache.Type),
+			Mode:  app.Build.Cache.Mode,
+			Tag:   app.Build.Cache.Tag,
+			Dir:   app.Build.Cache.Dir,
+			Scope: app.Build.Cache.Scope,
+		}
+	}
+
+	if app.Build.BuildKit != nil {
+		settings.BuildKit = &docker.BuildKitOpts{
+			Host:   app.Build.BuildKit.Host,
+			CACert: app.Build.BuildKit.CACert,
+			Cert:   app.Build.BuildKit.Cert,
+			Key:    app.Build.BuildKit.Key,
+		}
+	}
+
+	settings.Platforms = app.Build.Platforms
+
+	return settings, nil
+}
@@ -0,0 +1,109 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	api "github.com/karagatandev/porter/api/client"
+	"github.com/karagatandev/porter/cli/cmd/config"
+	"github.com/karagatandev/porter/cli/cmd/utils"
+	v2 "github.com/karagatandev/porter/internal/porter_app/v2"
+)
+
+// PromoteInput is the input for the Promote function
+type PromoteInput struct {
+	// CLIConfig is the CLI configuration
+	CLIConfig config.CLIConfig
+	// Client is the Porter API client
+	Client api.Client
+	// AppName is the name of the app to promote
+	AppName string
+	// FromDeploymentTarget is the name of the deployment target to promote the app from
+	FromDeploymentTarget string
+	// ToDeploymentTarget is the name of the deployment target to promote the app to
+	ToDeploymentTarget string
+	// Overrides are patch operations applied to the promoted app definition
+	Overrides []v2.PatchOperation
+	// WithPredeploy is true when the predeploy job should run in the target
+	WithPredeploy bool
+	// SkipConfirmation promotes the app without prompting for confirmation after showing the diff
+	SkipConfirmation bool
+	// BreakGlassReason overrides the freeze windows and protection rules of the destination target
+	BreakGlassReason string
+}
+
+// Promote shows the diff of promoting the deployed revision of an app from one deployment target to another, then
+// deploys the exact image and app definition to the target once confirmed
+func Promote(ctx context.Context, inp PromoteInput) error {
+	promoteInput := api.PromoteAppInput{
+		ProjectID:            inp.CLIConfig.Project,
+		ClusterID:            inp.CLIConfig.Cluster,
+		AppName:              inp.AppName,
+		FromDeploymentTarget: inp.FromDeploymentTarget,
+		ToDeploymentTarget:   inp.ToDeploymentTarget,
+		Overrides:            inp.Overrides,
+		WithPredeploy:        inp.WithPredeploy,
+		DryRun:               true,
+	}
+
+	plan, err := inp.Client.PromoteApp(ctx, promoteInput)
+	if err != nil {
+		return fmt.Errorf("error planning promotion: %w", err)
+	}
+
+	color.New(color.FgGreen).Printf("Promoting revision %d of %s from %s to %s\n", plan.SourceRevisionNumber, inp.AppName, inp.FromDeploymentTarget, inp.ToDeploymentTarget) // nolint:errcheck,gosec
+
+	color.New(color.FgGreen).Printf("Image: %s:%s\n\n", plan.ImageRepository, plan.ImageTag) // nolint:errcheck,gosec
+
+	if plan.Diff == "" {
+		color.New(color.FgGreen).Printf("%s in %s already matches the promoted revision\n", inp.AppName, inp.ToDeploymentTarget) // nolint:errcheck,gosec
+		return nil
+	}
+
+	printPromotionDiff(plan.Diff)
+
+	if !inp.SkipConfirmation {
+		proceed, err := utils.PromptConfirm(fmt.Sprintf("Promote %s to %s?", inp.AppName, inp.ToDeploymentTarget), false)
+		if err != nil {
+			return fmt.Errorf("error confirming promotion: %w", err)
+		}
+		if !proceed {
+			return errors.New("promotion cancelled")
+		}
+	}
+
+	// the promotion fails if another revision was deployed to the source after the diff was shown
+	promoteInput.SourceAppRevisionID = plan.SourceAppRevisionID
+	promoteInput.DryRun = false
+	promoteInput.BreakGlassReason = inp.BreakGlassReason
+
+	resp, err := inp.Client.PromoteApp(ctx, promoteInput)
+	if err != nil {
+		return fmt.Errorf("error promoting app: %w", err)
+	}
+
+	for _, warning := range resp.Warnings {
+		color.New(color.FgYellow).Printf("Warning: %s\n", warning) // nolint:errcheck,gosec
+	}
+
+	color.New(color.FgGreen).Printf("Successfully promoted %s to %s, created revision %s\n", inp.AppName, inp.ToDeploymentTarget, resp.AppRevisionID) // nolint:errcheck,gosec
+	return nil
+}
+
+func printPromotionDiff(diff string) {
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			color.New(color.FgGreen).Print(line) // nolint:errcheck,gosec
+		case strings.HasPrefix(line, "-"):
+			color.New(color.FgRed).Print(line) // nolint:errcheck,gosec
+		default:
+			fmt.Print(line)
+		}
+	}
+
+	fmt.Println()
+}
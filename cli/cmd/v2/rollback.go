@@ -19,17 +19,21 @@ type RollbackInput struct {
 	AppName string
 	// DeploymentTargetName is the name of the deployment target to rollback
 	DeploymentTargetName string
+	// BreakGlassReason overrides the freeze windows and protection rules of the deployment target
+	BreakGlassReason string
 }
 
 // Rollback deploys the previous successful revision of an app
 func Rollback(ctx context.Context, inp RollbackInput) error {
 	color.New(color.FgGreen).Printf("Rolling back to last deployed revision ...\n") // nolint:errcheck,gosec
 
-	rollbackResp, err := inp.Client.RollbackRevision(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.AppName, inp.DeploymentTargetName)
+	rollbackResp, err := inp.Client.RollbackRevision(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.AppName, inp.DeploymentTargetName, inp.BreakGlassReason)
 	if err != nil {
 		return fmt.Errorf("error calling rollback revision endpoint: %w", err)
 	}
 
+	printWarnings(rollbackResp.Warnings)
+
 	color.New(color.FgGreen).Printf("Successfully rolled back to revision %d\n", rollbackResp.TargetRevisionNumber) // nolint:errcheck,gosec
 	return nil
 }
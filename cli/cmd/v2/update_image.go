@@ -18,6 +18,7 @@ type UpdateImageInput struct {
 	Tag                         string
 	Client                      api.Client
 	WaitForSuccessfulDeployment bool
+	BreakGlassReason            string
 }
 
 // UpdateImage updates the image of an application
@@ -27,7 +28,7 @@ func UpdateImage(ctx context.Context, input UpdateImageInput) error {
 		tag = "latest"
 	}
 
-	resp, err := input.Client.UpdateImage(ctx, input.ProjectID, input.ClusterID, input.AppName, input.DeploymentTargetName, tag)
+	resp, err := input.Client.UpdateImage(ctx, input.ProjectID, input.ClusterID, input.AppName, input.DeploymentTargetName, tag, input.BreakGlassReason)
 	if err != nil {
 		return fmt.Errorf("unable to update image: %w", err)
 	}
@@ -36,6 +37,8 @@ func UpdateImage(ctx context.Context, input UpdateImageInput) error {
 
 	_, _ = color.New(triggeredBackgroundColor).Printf("Updated application %s to use tag \"%s\"\n", input.AppName, tag)
 
+	printWarnings(resp.Warnings)
+
 	if input.WaitForSuccessfulDeployment {
 		return waitForAppRevisionStatus(ctx, waitForAppRevisionStatusInput{
 			ProjectID:  input.ProjectID,
@@ -12,14 +12,17 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/karagatandev/porter/api/server"
 	"github.com/karagatandev/porter/api/server/router"
 
 	"github.com/karagatandev/porter/api/authmanagement"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/karagatandev/porter/internal/auth/oidc"
 	"github.com/karagatandev/porter/internal/telemetry"
 
 	"github.com/karagatandev/porter/api/server/shared/config"
@@ -102,6 +105,33 @@ func main() {
 		})
 	}
 
+	if !authServiceFlag {
+		g.Go(func() error {
+			pruneAuditLogs(ctx, config)
+			return nil
+		})
+	}
+
+	if !authServiceFlag {
+		g.Go(func() error {
+			pruneExpiredOIDCTokens(ctx, config)
+			return nil
+		})
+	}
+
+	if !authServiceFlag && config.ServerConf.HelmRepoCacheRefreshInterval > 0 {
+		g.Go(func() error {
+			config.URLCache.Run(ctx, config.ServerConf.HelmRepoCacheRefreshInterval)
+			return nil
+		})
+	}
+
+	if config.ServerConf.MetricsPort > 0 {
+		g.Go(func() error {
+			return serveMetrics(ctx, config)
+		})
+	}
+
 	termFunc := func() error {
 		termChan := make(chan os.Signal, 1)
 		signal.Notify(termChan, syscall.SIGINT, syscall.SIGTERM)
@@ -124,6 +154,83 @@ func main() {
 	}
 }
 
+// auditLogPruneInterval is how often audit logs older than the retention period are deleted
+const auditLogPruneInterval = time.Hour
+
+// pruneAuditLogs deletes audit logs older than the retention of their project until ctx is cancelled. Projects
+// which do not set a retention use AUDIT_LOG_RETENTION.
+func pruneAuditLogs(ctx context.Context, conf *config.Config) {
+	ticker := time.NewTicker(auditLogPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := conf.Repo.AuditLog().DeleteExpiredAuditLogs(ctx, conf.ServerConf.AuditLogRetention)
+		if err != nil {
+			conf.Logger.Error().Err(err).Msg("Error pruning audit logs")
+		} else if deleted > 0 {
+			conf.Logger.Info().Msgf("Pruned %d audit logs", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serveMetrics serves the Prometheus metrics on the configured metrics port until ctx is cancelled
+func serveMetrics(ctx context.Context, conf *config.Config) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", conf.ServerConf.MetricsPort),
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	conf.Logger.Info().Msgf("Starting metrics server on port %d", conf.ServerConf.MetricsPort)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("metrics server failed: %s", err.Error())
+	}
+
+	return nil
+}
+
+// oidcTokenPruneInterval is how often the expired tokens exchanged for CI ID tokens are deleted
+const oidcTokenPruneInterval = 15 * time.Minute
+
+// pruneExpiredOIDCTokens deletes the expired tokens exchanged for CI ID tokens until ctx is cancelled,
+// since every exchange stores a new token
+func pruneExpiredOIDCTokens(ctx context.Context, conf *config.Config) {
+	ticker := time.NewTicker(oidcTokenPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := oidc.DeleteExpiredTokens(ctx, conf.Repo)
+		if err != nil {
+			conf.Logger.Error().Err(err).Msg("Error pruning expired OIDC tokens")
+		} else if deleted > 0 {
+			conf.Logger.Info().Msgf("Pruned %d expired OIDC tokens", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 const (
 	defaultProjectName = "default"
 	defaultClusterName = "cluster-1"
@@ -1,17 +1,18 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 
 	"github.com/karagatandev/porter/api/server/shared/config/envloader"
-	"github.com/karagatandev/porter/cmd/migrate/keyrotate"
 	"github.com/karagatandev/porter/cmd/migrate/populate_source_config_display_name"
 	"github.com/karagatandev/porter/cmd/migrate/startup_migrations"
 
 	adapter "github.com/karagatandev/porter/internal/adapter"
 	"github.com/karagatandev/porter/internal/features"
+	"github.com/karagatandev/porter/internal/keyrotate"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/repository/gorm"
 	lr "github.com/karagatandev/porter/pkg/logger"
@@ -139,7 +140,7 @@ func main() {
 		copy(oldKey[:], []byte(oldKeyStr))
 		copy(newKey[:], []byte(newKeyStr))
 
-		err := keyrotate.Rotate(db, &oldKey, &newKey)
+		err := keyrotate.NewRotator(db, &oldKey, &newKey, logger).Run(context.Background())
 		if err != nil {
 			logger.Fatal().Err(err).Msg("key rotation failed")
 		}
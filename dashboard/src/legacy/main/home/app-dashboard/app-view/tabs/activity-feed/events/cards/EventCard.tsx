@@ -38,6 +38,7 @@ const EventCard: React.FC<Props> = ({
       match(event)
         .with({ type: "APP_EVENT" }, () => "")
         .with({ type: "NOTIFICATION" }, () => "")
+        .with({ type: "PROMOTION" }, () => "")
         .with({ type: "BUILD" }, (event) =>
           event.metadata.commit_sha
             ? `https://www.github.com/${porterApp.repo_name}/commit/${event.metadata.commit_sha}`
@@ -74,6 +75,7 @@ const EventCard: React.FC<Props> = ({
       match(event)
         .with({ type: "APP_EVENT" }, () => "")
         .with({ type: "NOTIFICATION" }, () => "")
+        .with({ type: "PROMOTION" }, () => "")
         .with({ type: "BUILD" }, (event) =>
           event.metadata.commit_sha ? event.metadata.commit_sha.slice(0, 7) : ""
         )
@@ -140,6 +142,7 @@ const EventCard: React.FC<Props> = ({
       />
     ))
     .with({ type: "AUTO_ROLLBACK" }, () => null)
+    .with({ type: "PROMOTION" }, () => null) // promotions are shown as the deploy events they trigger
     .exhaustive();
 };
 
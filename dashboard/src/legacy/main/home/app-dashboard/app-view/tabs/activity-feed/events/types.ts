@@ -142,6 +142,18 @@ export const porterAppNotificationEventMetadataValidator = z.discriminatedUnion(
 export type PorterAppNotification = z.infer<
   typeof porterAppNotificationEventMetadataValidator
 >;
+const porterAppPromotionEventMetadataValidator = z.object({
+  source_deployment_target_id: z.string(),
+  source_deployment_target_name: z.string(),
+  destination_deployment_target_id: z.string(),
+  destination_deployment_target_name: z.string(),
+  source_app_revision_id: z.string(),
+  source_revision_number: z.number(),
+  app_revision_id: z.string(),
+  image_repository: z.string(),
+  image_tag: z.string(),
+  promoted_by: z.string().optional().default(""),
+});
 export const porterAppEventValidator = z.discriminatedUnion("type", [
   z.object({
     id: z.string(),
@@ -210,6 +222,16 @@ export const porterAppEventValidator = z.discriminatedUnion("type", [
     porter_app_id: z.number(),
     metadata: porterAppDeployEventMetadataValidator,
   }),
+  z.object({
+    id: z.string(),
+    created_at: z.string(),
+    updated_at: z.string(),
+    status: z.string().optional().default(""),
+    type: z.literal("PROMOTION"),
+    type_external_source: z.string().optional().default(""),
+    porter_app_id: z.number(),
+    metadata: porterAppPromotionEventMetadataValidator,
+  }),
 ]);
 
 export const getPorterAppEventsValidator = z
@@ -231,3 +253,6 @@ export type PorterAppNotificationEvent = PorterAppEvent & {
 export type PorterAppRollbackEvent = PorterAppEvent & {
   type: "AUTO_ROLLBACK";
 };
+export type PorterAppPromotionEvent = PorterAppEvent & {
+  type: "PROMOTION";
+};
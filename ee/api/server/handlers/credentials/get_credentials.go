@@ -79,7 +79,7 @@ func (c *CredentialsGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		}
 
 		// use this vault client for the repo
-		repo = gorm.NewRepository(c.Config().DB, &key, vaultClient)
+		repo = gorm.NewRepositoryWithKeyring(c.Config().DB, &key, vaultClient, c.Config().Keyring)
 	}
 
 	if ceToken.DOCredentialID != 0 {
@@ -0,0 +1,44 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/karagatandev/porter/api/server/shared/config/env"
+	"github.com/karagatandev/porter/internal/encryption"
+)
+
+// NewKeyring returns the keys configured alongside the static ENCRYPTION_KEY: the
+// envelope encryption configured by ENCRYPTION_KMS_PROVIDER, and the key set by
+// OLD_ENCRYPTION_KEY while a key rotation is in progress
+func NewKeyring(conf *env.DBConf, key *[32]byte) (*encryption.Keyring, error) {
+	keyring := &encryption.Keyring{}
+
+	if conf.OldEncryptionKey != "" {
+		previousKey := [32]byte{}
+
+		copy(previousKey[:], []byte(conf.OldEncryptionKey))
+
+		keyring.PreviousKey = &previousKey
+	}
+
+	if conf.EncryptionKMSProvider == "" {
+		return keyring, nil
+	}
+
+	kms, err := encryption.NewKeyManager(context.Background(), encryption.KeyManagerOpts{
+		Provider:          encryption.KeyManagerProvider(conf.EncryptionKMSProvider),
+		KeyID:             conf.EncryptionKMSKeyID,
+		AWSRegion:         conf.EncryptionKMSAWSRegion,
+		VaultServerURL:    conf.VaultServerURL,
+		VaultToken:        conf.VaultAPIKey,
+		VaultTransitMount: conf.EncryptionKMSVaultTransitMount,
+		LocalKey:          key,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keyring.Envelope = encryption.NewEnvelope(kms, key)
+
+	return keyring, nil
+}
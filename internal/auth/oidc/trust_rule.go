@@ -0,0 +1,170 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/auth/token"
+	"github.com/karagatandev/porter/internal/encryption"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CreateTrustRule stores a trust rule along with the policy of the tokens exchanged
+// through it, which grants deploying the rule's app to the rule's deployment target
+func CreateTrustRule(ctx context.Context, repo repository.Repository, rule *models.OIDCTrustRule) (*models.OIDCTrustRule, error) {
+	uid, err := encryption.GenerateRandomBytes(16)
+	if err != nil {
+		return nil, err
+	}
+
+	policyBytes, err := json.Marshal(types.OIDCTrustRulePolicy(rule.ClusterID, rule.DeploymentTarget, rule.AppName))
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := repo.Policy().CreatePolicy(&models.Policy{
+		ProjectID:       rule.ProjectID,
+		UniqueID:        uid,
+		CreatedByUserID: rule.CreatedByUserID,
+		Name:            fmt.Sprintf("oidc-%s", rule.Name),
+		PolicyBytes:     policyBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating trust rule policy: %w", err)
+	}
+
+	rule.PolicyUID = policy.UniqueID
+
+	rule, err = repo.OIDCTrustRule().CreateOIDCTrustRule(ctx, rule)
+	if err != nil {
+		return nil, fmt.Errorf("error creating trust rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteTrustRule deletes a trust rule along with its policy, which invalidates every
+// token that was exchanged through it
+func DeleteTrustRule(ctx context.Context, repo repository.Repository, rule *models.OIDCTrustRule) error {
+	if _, err := repo.OIDCTrustRule().DeleteOIDCTrustRule(ctx, rule); err != nil {
+		return fmt.Errorf("error deleting trust rule: %w", err)
+	}
+
+	policy, err := repo.Policy().ReadPolicy(rule.ProjectID, rule.PolicyUID)
+	if err != nil {
+		// the policy may have been deleted on its own
+		return nil
+	}
+
+	if _, err := repo.Policy().DeletePolicy(policy); err != nil {
+		return fmt.Errorf("error deleting trust rule policy: %w", err)
+	}
+
+	return nil
+}
+
+// MatchTrustRule returns the trust rule which permits exchanging an ID token with the
+// verified claims. When appName or deploymentTarget are set, only rules for them match.
+func MatchTrustRule(rules []*models.OIDCTrustRule, claims *Claims, appName, deploymentTarget string) (*models.OIDCTrustRule, error) {
+	var match *models.OIDCTrustRule
+
+	for _, rule := range rules {
+		if rule.Issuer != claims.Issuer || !claims.HasAudience(rule.Audience) || !MatchSubject(rule.SubjectPattern, claims.Subject) {
+			continue
+		}
+
+		if (appName != "" && rule.AppName != appName) || (deploymentTarget != "" && rule.DeploymentTarget != deploymentTarget) {
+			continue
+		}
+
+		if match != nil {
+			return nil, fmt.Errorf("id token matches several trust rules, specify the app and deployment target")
+		}
+
+		match = rule
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("id token does not match any trust rule")
+	}
+
+	return match, nil
+}
+
+// IssueToken issues a short-lived API token with the policy of a trust rule, returning
+// the encoded token and its expiry. Both the stored token and the encoded token expire,
+// and expired tokens are deleted by DeleteExpiredTokens.
+func IssueToken(
+	ctx context.Context,
+	repo repository.Repository,
+	tokenConf *token.TokenGeneratorConf,
+	rule *models.OIDCTrustRule,
+	subject string,
+) (string, time.Time, error) {
+	uid, err := encryption.GenerateRandomBytes(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	secretKey, err := encryption.GenerateRandomBytes(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	// hash the secret key for storage in the db
+	hashedToken, err := bcrypt.GenerateFromPassword([]byte(secretKey), 8)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiry := time.Now().Add(rule.TokenTTL())
+
+	name := fmt.Sprintf("oidc-%s-%s", rule.Name, subject)
+	if len(name) > 255 {
+		name = name[:255]
+	}
+
+	apiToken, err := repo.APIToken().CreateAPIToken(&models.APIToken{
+		UniqueID:        uid,
+		ProjectID:       rule.ProjectID,
+		CreatedByUserID: rule.CreatedByUserID,
+		Expiry:          &expiry,
+		PolicyUID:       rule.PolicyUID,
+		PolicyName:      fmt.Sprintf("oidc-%s", rule.Name),
+		Name:            name,
+		SecretKey:       hashedToken,
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error creating api token: %w", err)
+	}
+
+	jwt, err := token.GetStoredTokenForAPI(rule.CreatedByUserID, rule.ProjectID, apiToken.UniqueID, secretKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	jwt.Expiry = &expiry
+
+	encoded, err := jwt.EncodeToken(tokenConf)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return encoded, expiry, nil
+}
+
+// DeleteExpiredTokens deletes the API tokens exchanged through trust rules which have
+// expired, returning the number of tokens deleted
+func DeleteExpiredTokens(ctx context.Context, repo repository.Repository) (int64, error) {
+	deleted, err := repo.OIDCTrustRule().DeleteExpiredOIDCTokens(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("error deleting expired tokens: %w", err)
+	}
+
+	return deleted, nil
+}
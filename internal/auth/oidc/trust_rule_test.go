@@ -0,0 +1,100 @@
+package oidc_test
+
+import (
+	"testing"
+
+	"github.com/karagatandev/porter/internal/auth/oidc"
+	"github.com/karagatandev/porter/internal/models"
+)
+
+func TestMatchTrustRule(t *testing.T) {
+	rules := []*models.OIDCTrustRule{
+		{
+			Name:             "web-staging",
+			Issuer:           "https://token.actions.githubusercontent.com",
+			Audience:         "https://dashboard.porter.run",
+			SubjectPattern:   "repo:porter-dev/porter:ref:refs/heads/main",
+			DeploymentTarget: "staging",
+			AppName:          "web",
+		},
+		{
+			Name:             "web-production",
+			Issuer:           "https://token.actions.githubusercontent.com",
+			Audience:         "https://dashboard.porter.run",
+			SubjectPattern:   "repo:porter-dev/porter:ref:refs/heads/main",
+			DeploymentTarget: "production",
+			AppName:          "web",
+		},
+		{
+			Name:             "worker",
+			Issuer:           "https://token.actions.githubusercontent.com",
+			Audience:         "https://dashboard.porter.run",
+			SubjectPattern:   "repo:porter-dev/worker:*",
+			DeploymentTarget: "staging",
+			AppName:          "worker",
+		},
+	}
+
+	claims := func(subject, audience string) *oidc.Claims {
+		return &oidc.Claims{
+			Issuer:   "https://token.actions.githubusercontent.com",
+			Subject:  subject,
+			Audience: []string{audience},
+		}
+	}
+
+	tests := []struct {
+		description      string
+		claims           *oidc.Claims
+		appName          string
+		deploymentTarget string
+		expRule          string
+	}{
+		{
+			description: "subject pattern matches",
+			claims:      claims("repo:porter-dev/worker:ref:refs/heads/feat", "https://dashboard.porter.run"),
+			expRule:     "worker",
+		},
+		{
+			description:      "deployment target selects between rules",
+			claims:           claims("repo:porter-dev/porter:ref:refs/heads/main", "https://dashboard.porter.run"),
+			appName:          "web",
+			deploymentTarget: "production",
+			expRule:          "web-production",
+		},
+		{
+			description: "ambiguous rules do not match",
+			claims:      claims("repo:porter-dev/porter:ref:refs/heads/main", "https://dashboard.porter.run"),
+			appName:     "web",
+		},
+		{
+			description: "other branches do not match",
+			claims:      claims("repo:porter-dev/porter:ref:refs/heads/dev", "https://dashboard.porter.run"),
+		},
+		{
+			description: "other audiences do not match",
+			claims:      claims("repo:porter-dev/worker:ref:refs/heads/main", "sts.amazonaws.com"),
+		},
+	}
+
+	for _, test := range tests {
+		rule, err := oidc.MatchTrustRule(rules, test.claims, test.appName, test.deploymentTarget)
+
+		if test.expRule == "" {
+			if err == nil {
+				t.Errorf("%s: expected no rule to match, got %s", test.description, rule.Name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.description, err)
+			continue
+		}
+
+		if rule.Name != test.expRule {
+			t.Errorf("%s: expected rule %s, got %s", test.description, test.expRule, rule.Name)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/karagatandev/porter/internal/publicnet"
+)
+
+const (
+	// keySetTTL is how long the JSON web keys of an issuer are cached for
+	keySetTTL = time.Hour
+
+	// minKeySetRefreshInterval limits how often the keys of an issuer are refetched when an
+	// ID token is signed with a key which is not in the cached key set
+	minKeySetRefreshInterval = time.Minute
+
+	maxDiscoveryResponseSize = 1 << 20
+)
+
+// Claims are the claims of a verified ID token
+type Claims struct {
+	Issuer   string
+	Subject  string
+	Audience []string
+}
+
+// HasAudience checks that the ID token was minted for the audience
+func (c *Claims) HasAudience(audience string) bool {
+	for _, aud := range c.Audience {
+		if aud == audience {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Verifier verifies ID tokens against the JSON web keys published by their issuers
+type Verifier struct {
+	client *http.Client
+
+	mu      sync.Mutex
+	keySets map[string]*keySet
+}
+
+type keySet struct {
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewVerifier returns a Verifier which fetches JSON web keys with the client. Issuers are named
+// by the tokens being verified, so the default client refuses to connect to internal addresses.
+func NewVerifier(client *http.Client) *Verifier {
+	if client == nil {
+		client = publicnet.NewHTTPClient(10 * time.Second)
+	}
+
+	return &Verifier{
+		client:  client,
+		keySets: make(map[string]*keySet),
+	}
+}
+
+// UnverifiedIssuer returns the issuer claimed by an ID token without verifying the token,
+// so that the trust rules for the issuer can be found before its keys are fetched
+func UnverifiedIssuer(rawToken string) (string, error) {
+	claims := jwt.MapClaims{}
+
+	if _, _, err := new(jwt.Parser).ParseUnverified(rawToken, claims); err != nil {
+		return "", fmt.Errorf("could not parse id token: %w", err)
+	}
+
+	issuer, _ := claims["iss"].(string)
+	if issuer == "" {
+		return "", fmt.Errorf("id token has no issuer")
+	}
+
+	return issuer, nil
+}
+
+// Verify checks that an ID token was signed by issuer and has not expired. The issuer's
+// keys are discovered through its OpenID configuration.
+func (v *Verifier) Verify(ctx context.Context, rawToken, issuer string) (*Claims, error) {
+	token, err := jwt.Parse(rawToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		return v.getKey(ctx, issuer, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not verify id token: %w", err)
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("id token is not valid")
+	}
+
+	// expiry is checked when the token is parsed, but is optional in jwt-go
+	if _, ok := mapClaims["exp"]; !ok {
+		return nil, fmt.Errorf("id token has no expiry")
+	}
+
+	claims := &Claims{}
+	claims.Issuer, _ = mapClaims["iss"].(string)
+	claims.Subject, _ = mapClaims["sub"].(string)
+
+	if claims.Issuer != issuer {
+		return nil, fmt.Errorf("id token was issued by %s, not %s", claims.Issuer, issuer)
+	}
+
+	switch aud := mapClaims["aud"].(type) {
+	case string:
+		claims.Audience = []string{aud}
+	case []interface{}:
+		for _, a := range aud {
+			if str, ok := a.(string); ok {
+				claims.Audience = append(claims.Audience, str)
+			}
+		}
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) getKey(ctx context.Context, issuer, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	set, ok := v.keySets[issuer]
+
+	if ok && time.Since(set.fetchedAt) < keySetTTL {
+		if key := set.find(kid); key != nil {
+			return key, nil
+		}
+
+		// issuers rotate their keys, so refetch them if the token uses an unknown key
+		if time.Since(set.fetchedAt) < minKeySetRefreshInterval {
+			return nil, fmt.Errorf("no key with id %q for issuer %s", kid, issuer)
+		}
+	}
+
+	keys, err := v.fetchKeys(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	set = &keySet{keys: keys, fetchedAt: time.Now()}
+	v.keySets[issuer] = set
+
+	if key := set.find(kid); key != nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("no key with id %q for issuer %s", kid, issuer)
+}
+
+func (s *keySet) find(kid string) *rsa.PublicKey {
+	if kid != "" {
+		return s.keys[kid]
+	}
+
+	// tokens without a key id can only be verified if the issuer has a single key
+	if len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key
+		}
+	}
+
+	return nil
+}
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (v *Verifier) fetchKeys(ctx context.Context, issuer string) (map[string]*rsa.PublicKey, error) {
+	discovery := &discoveryDocument{}
+
+	if err := v.getJSON(ctx, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", discovery); err != nil {
+		return nil, fmt.Errorf("could not discover openid configuration of %s: %w", issuer, err)
+	}
+
+	if discovery.Issuer != issuer {
+		return nil, fmt.Errorf("openid configuration of %s is for issuer %s", issuer, discovery.Issuer)
+	}
+
+	if discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("openid configuration of %s has no jwks_uri", issuer)
+	}
+
+	jwks := &jsonWebKeySet{}
+
+	if err := v.getJSON(ctx, discovery.JWKSURI, jwks); err != nil {
+		return nil, fmt.Errorf("could not fetch keys of %s: %w", issuer, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		key, err := jwk.rsaPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q of %s: %w", jwk.Kid, issuer, err)
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (v *Verifier) getJSON(ctx context.Context, url string, dst interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, maxDiscoveryResponseSize)).Decode(dst)
+}
+
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 || exponent.Int64() < 3 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+// MatchSubject matches the subject of an ID token against a trust rule pattern, in which
+// "*" matches any sequence of characters, including ":" and "/"
+func MatchSubject(pattern, subject string) bool {
+	if pattern == "" || subject == "" {
+		return false
+	}
+
+	parts := strings.Split(pattern, "*")
+
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	re, err := regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(subject)
+}
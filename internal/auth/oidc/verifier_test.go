@@ -0,0 +1,221 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/karagatandev/porter/internal/auth/oidc"
+)
+
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	issuer := &testIssuer{key: key, kid: "test-key"}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/keys",
+		})
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": issuer.kid,
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *testIssuer) sign(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	issuer := newTestIssuer(t)
+	verifier := oidc.NewVerifier(issuer.server.Client())
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": issuer.server.URL,
+			"sub": "repo:porter-dev/porter:ref:refs/heads/main",
+			"aud": "https://dashboard.porter.run",
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		}
+	}
+
+	tests := []struct {
+		description string
+		token       func() string
+		issuer      string
+		wantErr     bool
+	}{
+		{
+			description: "valid token",
+			token:       func() string { return issuer.sign(t, issuer.key, validClaims()) },
+			issuer:      issuer.server.URL,
+		},
+		{
+			description: "expired token",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return issuer.sign(t, issuer.key, claims)
+			},
+			issuer:  issuer.server.URL,
+			wantErr: true,
+		},
+		{
+			description: "token without expiry",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return issuer.sign(t, issuer.key, claims)
+			},
+			issuer:  issuer.server.URL,
+			wantErr: true,
+		},
+		{
+			description: "token signed by another key",
+			token:       func() string { return issuer.sign(t, otherKey, validClaims()) },
+			issuer:      issuer.server.URL,
+			wantErr:     true,
+		},
+		{
+			description: "token claiming another issuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://token.actions.githubusercontent.com"
+				return issuer.sign(t, issuer.key, claims)
+			},
+			issuer:  issuer.server.URL,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		claims, err := verifier.Verify(context.Background(), test.token(), test.issuer)
+
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", test.description)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.description, err)
+			continue
+		}
+
+		if claims.Subject != "repo:porter-dev/porter:ref:refs/heads/main" {
+			t.Errorf("%s: unexpected subject %s", test.description, claims.Subject)
+		}
+
+		if !claims.HasAudience("https://dashboard.porter.run") {
+			t.Errorf("%s: expected audience to be set", test.description)
+		}
+	}
+}
+
+func TestVerifyRefusesInternalIssuers(t *testing.T) {
+	issuer := newTestIssuer(t)
+
+	// the issuer listens on a loopback address, which the default client must not connect to
+	verifier := oidc.NewVerifier(nil)
+
+	token := issuer.sign(t, issuer.key, jwt.MapClaims{
+		"iss": issuer.server.URL,
+		"sub": "repo:porter-dev/porter:ref:refs/heads/main",
+		"aud": "https://dashboard.porter.run",
+		"exp": time.Now().Add(5 * time.Minute).Unix(),
+	})
+
+	if _, err := verifier.Verify(context.Background(), token, issuer.server.URL); err == nil {
+		t.Errorf("expected fetching the keys of a loopback issuer to fail")
+	}
+}
+
+func TestUnverifiedIssuer(t *testing.T) {
+	issuer := newTestIssuer(t)
+
+	got, err := oidc.UnverifiedIssuer(issuer.sign(t, issuer.key, jwt.MapClaims{"iss": issuer.server.URL}))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if got != issuer.server.URL {
+		t.Errorf("expected issuer %s, got %s", issuer.server.URL, got)
+	}
+
+	if _, err := oidc.UnverifiedIssuer("not-a-token"); err == nil {
+		t.Errorf("expected error for malformed token")
+	}
+}
+
+func TestMatchSubject(t *testing.T) {
+	tests := []struct {
+		pattern, subject string
+		want             bool
+	}{
+		{"repo:porter-dev/porter:ref:refs/heads/main", "repo:porter-dev/porter:ref:refs/heads/main", true},
+		{"repo:porter-dev/porter:*", "repo:porter-dev/porter:ref:refs/heads/main", true},
+		{"repo:porter-dev/porter:ref:refs/heads/*", "repo:porter-dev/porter:ref:refs/heads/feat/x", true},
+		{"repo:porter-dev/porter:ref:refs/heads/main", "repo:porter-dev/porter:ref:refs/heads/main2", false},
+		{"repo:porter-dev/*:ref:refs/heads/main", "repo:evil/porter:ref:refs/heads/other", false},
+		{"repo:porter-dev/porter.*", "repo:porter-dev/porterXcli", false},
+		{"", "repo:porter-dev/porter", false},
+	}
+
+	for _, test := range tests {
+		if got := oidc.MatchSubject(test.pattern, test.subject); got != test.want {
+			t.Errorf("MatchSubject(%q, %q) = %v, want %v", test.pattern, test.subject, got, test.want)
+		}
+	}
+}
@@ -29,6 +29,10 @@ type Token struct {
 	// Additional fields that may or may not be set
 	TokenID string `json:"token_id"`
 	Secret  string `json:"secret"`
+
+	// Expiry is set for tokens which are rejected after a point in time, such as the
+	// short-lived tokens exchanged for CI ID tokens
+	Expiry *time.Time `json:"exp,omitempty"`
 }
 
 func GetTokenForUser(userID uint) (*Token, error) {
@@ -81,7 +85,7 @@ func GetStoredTokenForAPI(userID, projID uint, tokenID, secret string) (*Token,
 }
 
 func (t *Token) EncodeToken(conf *TokenGeneratorConf) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	claims := jwt.MapClaims{
 		"sub_kind":   t.SubKind,
 		"sub":        t.Sub,
 		"iby":        t.IBy,
@@ -89,7 +93,14 @@ func (t *Token) EncodeToken(conf *TokenGeneratorConf) (string, error) {
 		"project_id": t.ProjectID,
 		"token_id":   t.TokenID,
 		"secret":     t.Secret,
-	})
+	}
+
+	// the exp claim is checked by jwt.Parse, so expired tokens cannot be decoded
+	if t.Expiry != nil {
+		claims["exp"] = t.Expiry.Unix()
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// Sign and get the complete encoded token as a string using the secret
 	return token.SignedString([]byte(conf.TokenSecret))
@@ -152,6 +163,15 @@ func GetTokenFromEncoded(tokenString string, conf *TokenGeneratorConf) (*Token,
 			}
 		}
 
+		if expInter, ok := claims["exp"]; ok {
+			exp, ok := expInter.(float64)
+
+			if ok {
+				expiry := time.Unix(int64(exp), 0)
+				res.Expiry = &expiry
+			}
+		}
+
 		cancelTokens := func(userId string, lastIssueTime time.Time, res *Token) error {
 			timeAsUTC := lastIssueTime.UTC()
 			if res.Sub == userId && res.IAt.UTC().Before(timeAsUTC) {
@@ -46,3 +46,43 @@ func TestGetAndEncodeTokenForUser(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestEncodeTokenWithExpiry(t *testing.T) {
+	conf := &token.TokenGeneratorConf{
+		TokenSecret: "fakesecret",
+	}
+
+	tok, err := token.GetStoredTokenForAPI(1, 1, "token-id", "secret")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	expiry := time.Now().Add(15 * time.Minute)
+	tok.Expiry = &expiry
+
+	tokString, err := tok.EncodeToken(conf)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	gotToken, err := token.GetTokenFromEncoded(tokString, conf)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if gotToken.Expiry == nil || gotToken.Expiry.Unix() != expiry.Unix() {
+		t.Errorf("expected expiry %d, got %v", expiry.Unix(), gotToken.Expiry)
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	tok.Expiry = &expired
+
+	tokString, err = tok.EncodeToken(conf)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if _, err := token.GetTokenFromEncoded(tokString, conf); err == nil {
+		t.Errorf("expected an expired token to be rejected")
+	}
+}
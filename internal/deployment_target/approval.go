@@ -0,0 +1,298 @@
+package deployment_target
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/internal/deploypolicy"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/notifier"
+	"github.com/karagatandev/porter/internal/notifier/slack"
+	"github.com/karagatandev/porter/internal/repository"
+	"github.com/karagatandev/porter/internal/telemetry"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// HoldForApprovalInput is the input to HoldForApproval
+type HoldForApprovalInput struct {
+	ProjectID uint
+	ClusterID uint
+	AppName   string
+
+	// Decision is the outcome of checking the apply against the policy of its deployment target, which required approval
+	Decision DeployPolicyDecision
+
+	// AppRevisionID is the revision created by the cluster control plane for an apply which is built, and which waits
+	// for its build. Applies of an image are not sent to the cluster control plane until they are approved, so they
+	// set DeferredApply to the encoded update instead.
+	AppRevisionID string
+	DeferredApply []byte
+	// AppDigest is the digest of the app being applied, as returned by AppDigest
+	AppDigest string
+
+	// User is the user applying, and APIToken is set instead when the apply is made with a project API token
+	User     *models.User
+	APIToken *models.APIToken
+}
+
+// HoldForApproval holds a revision until it is approved for its deployment target, and asks the approvers of the
+// project to approve it
+func HoldForApproval(ctx context.Context, conf *config.Config, inp HoldForApprovalInput) (*models.AppRevisionApproval, error) {
+	ctx, span := telemetry.NewSpan(ctx, "hold-for-approval")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: inp.ProjectID},
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: inp.Decision.DeploymentTargetID.String()},
+		telemetry.AttributeKV{Key: "app-name", Value: inp.AppName},
+		telemetry.AttributeKV{Key: "app-revision-id", Value: inp.AppRevisionID},
+		telemetry.AttributeKV{Key: "deferred", Value: len(inp.DeferredApply) > 0},
+	)
+
+	if !inp.Decision.RequiresApproval {
+		return nil, telemetry.Error(ctx, span, nil, "apply does not require approval")
+	}
+
+	approval := &models.AppRevisionApproval{
+		ProjectID:          inp.ProjectID,
+		ClusterID:          inp.ClusterID,
+		DeploymentTargetID: inp.Decision.DeploymentTargetID,
+		AppName:            inp.AppName,
+		AppRevisionID:      inp.AppRevisionID,
+		AppDigest:          inp.AppDigest,
+		Status:             models.AppRevisionStatus_AwaitingApproval,
+		ExpiresAt:          inp.Decision.ApprovalExpiresAt,
+		BuildRequired:      len(inp.DeferredApply) == 0,
+		DeferredApply:      inp.DeferredApply,
+	}
+
+	if approval.AppRevisionID == "" {
+		approval.AppRevisionID = uuid.New().String()
+	}
+
+	if inp.APIToken != nil {
+		approval.RequestedByAPITokenID = inp.APIToken.UniqueID
+	} else if inp.User != nil {
+		approval.RequestedByUserID = inp.User.ID
+	}
+
+	approval, err := conf.Repo.AppRevisionApproval().CreateAppRevisionApproval(ctx, approval)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating app revision approval")
+	}
+
+	// the revision is held whether or not the approvers could be reached, since it can be listed and approved regardless
+	if err := NotifyApproval(ctx, conf.Repo, NotifyApprovalInput{
+		Approval:  approval,
+		Requested: true,
+		ServerURL: conf.ServerConf.ServerURL,
+		CCPClient: conf.ClusterControlPlaneClient,
+	}); err != nil {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "notify-error", Value: err.Error()})
+	}
+
+	return approval, nil
+}
+
+// AppDigest returns the sha256 digest of an app, which identifies the app a revision was held with
+func AppDigest(app *porterv1.PorterApp) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(app)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// CheckApproverInput is the input to CheckApprover
+type CheckApproverInput struct {
+	Approval *models.AppRevisionApproval
+	// Status is the status the approval is moved to
+	Status models.AppRevisionStatus
+
+	// User is the user deciding the approval, and APIToken is set instead when it is decided with a project API token
+	User     *models.User
+	APIToken *models.APIToken
+}
+
+// CheckApprover returns why the user or API token may not approve or reject a revision, or an empty string if they may.
+// Revisions may be decided by holders of the approver role of their deployment target, or by admins, but they may not
+// be approved by whoever applied them.
+func CheckApprover(ctx context.Context, conf *config.Config, inp CheckApproverInput) (string, error) {
+	ctx, span := telemetry.NewSpan(ctx, "check-approver")
+	defer span.End()
+
+	approval := inp.Approval
+
+	var policy deploypolicy.Policy
+
+	policyModel, err := conf.Repo.DeployPolicy().ReadDeploymentTargetPolicy(ctx, approval.ProjectID, approval.DeploymentTargetID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", telemetry.Error(ctx, span, err, "error reading deployment target policy")
+	}
+	if err == nil {
+		policy, err = policyModel.DeployPolicy()
+		if err != nil {
+			return "", telemetry.Error(ctx, span, err, "error decoding deployment target policy")
+		}
+	}
+
+	roles, err := deployerRoles(conf, approval.ProjectID, inp.User, inp.APIToken)
+	if err != nil {
+		return "", telemetry.Error(ctx, span, err, "error reading roles of approver")
+	}
+
+	if !policy.CanApprove(roles) {
+		if policy.ApproverRole != "" {
+			return fmt.Sprintf("revisions can only be approved or rejected by admins or users with the %s role", policy.ApproverRole), nil
+		}
+		return "revisions can only be approved or rejected by admins", nil
+	}
+
+	if inp.Status != models.AppRevisionStatus_Approved {
+		return "", nil
+	}
+
+	var requester bool
+	if inp.APIToken != nil {
+		requester = approval.RequestedByAPITokenID != "" && approval.RequestedByAPITokenID == inp.APIToken.UniqueID
+	} else if inp.User != nil {
+		requester = approval.RequestedByUserID != 0 && approval.RequestedByUserID == inp.User.ID
+	}
+
+	if requester {
+		return "revisions cannot be approved by whoever applied them", nil
+	}
+
+	return "", nil
+}
+
+// NotifyApprovalInput is the input to NotifyApproval
+type NotifyApprovalInput struct {
+	Approval *models.AppRevisionApproval
+	// Requested asks approvers to decide the revision, rather than announcing that it was decided
+	Requested bool
+	ServerURL string
+
+	// CCPClient reads the notification configs of the project's Slack integrations
+	CCPClient porterv1connect.ClusterControlPlaneServiceClient
+}
+
+// NotifyApproval posts a revision held for approval, or its decision, through the notification config of its project.
+// Each Slack integration of the project is notified unless its notification config disables deploy notifications,
+// and the mentions of its notification config are mentioned.
+func NotifyApproval(ctx context.Context, repo repository.Repository, inp NotifyApprovalInput) error {
+	approval := inp.Approval
+
+	slackInts, err := repo.SlackIntegration().ListSlackIntegrationsByProjectID(approval.ProjectID)
+	if err != nil {
+		return fmt.Errorf("error listing slack integrations: %w", err)
+	}
+
+	if len(slackInts) == 0 {
+		return nil
+	}
+
+	opts := &notifier.ApprovalNotifyOpts{
+		Approval:             approval.ToAppRevisionApprovalType(),
+		DeploymentTargetName: approval.DeploymentTargetID.String(),
+		URL:                  fmt.Sprintf("%s/apps/%s?project_id=%d&cluster_id=%d", inp.ServerURL, approval.AppName, approval.ProjectID, approval.ClusterID),
+	}
+
+	deploymentTarget, err := repo.DeploymentTarget().DeploymentTarget(approval.ProjectID, approval.DeploymentTargetID.String())
+	if err == nil && deploymentTarget.VanityName != "" {
+		opts.DeploymentTargetName = deploymentTarget.VanityName
+	}
+
+	if policyModel, err := repo.DeployPolicy().ReadDeploymentTargetPolicy(ctx, approval.ProjectID, approval.DeploymentTargetID); err == nil {
+		if policy, err := policyModel.DeployPolicy(); err == nil {
+			opts.ApproverRole = policy.ApproverRole
+		}
+	}
+
+	var errs []error
+
+	for _, slackInt := range slackInts {
+		notifConf, err := slackNotificationConfig(ctx, inp.CCPClient, approval.ProjectID, slackInt.NotificationConfigID)
+		if err != nil {
+			// the approvers are still notified if the notification config cannot be read
+			errs = append(errs, err)
+		}
+
+		if !deployNotificationsEnabled(notifConf) {
+			continue
+		}
+
+		var mentions []string
+		if notifConf != nil && notifConf.SlackConfig != nil {
+			mentions = notifConf.SlackConfig.Mentions
+		}
+
+		var approvalNotifier notifier.ApprovalNotifier = slack.NewApprovalNotifier(mentions, slackInt)
+
+		if inp.Requested {
+			err = approvalNotifier.NotifyApprovalRequested(opts)
+		} else {
+			err = approvalNotifier.NotifyApprovalDecided(opts)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// slackNotificationConfig reads the notification config of a Slack integration, or returns nil if it has none
+func slackNotificationConfig(
+	ctx context.Context,
+	ccpClient porterv1connect.ClusterControlPlaneServiceClient,
+	projectID uint,
+	notificationConfigID uint,
+) (*porterv1.NotificationConfig, error) {
+	if notificationConfigID == 0 || ccpClient == nil {
+		return nil, nil
+	}
+
+	resp, err := ccpClient.NotificationConfig(ctx, connect.NewRequest(&porterv1.NotificationConfigRequest{
+		ProjectId:            int64(projectID),
+		NotificationConfigId: int64(notificationConfigID),
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("error reading notification config %d: %w", notificationConfigID, err)
+	}
+
+	if resp == nil || resp.Msg == nil {
+		return nil, fmt.Errorf("notification config %d is nil", notificationConfigID)
+	}
+
+	return resp.Msg.Config, nil
+}
+
+// deployNotificationsEnabled returns false if a notification config disables deploy notifications. Like other
+// notifications, approvals are sent for event types which the config does not mention.
+func deployNotificationsEnabled(notifConf *porterv1.NotificationConfig) bool {
+	if notifConf == nil {
+		return true
+	}
+
+	for _, t := range notifConf.EnabledTypes {
+		if t.Type == porterv1.EnumNotificationEventType_ENUM_NOTIFICATION_EVENT_TYPE_DEPLOY {
+			return t.Enabled
+		}
+	}
+
+	return true
+}
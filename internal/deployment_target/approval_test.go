@@ -0,0 +1,56 @@
+package deployment_target
+
+import (
+	"testing"
+
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+)
+
+func TestDeployNotificationsEnabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		notifConf *porterv1.NotificationConfig
+		expected  bool
+	}{
+		{
+			name:     "no notification config",
+			expected: true,
+		},
+		{
+			name: "deploy notifications enabled",
+			notifConf: &porterv1.NotificationConfig{
+				EnabledTypes: []*porterv1.NotificationTypeEnabled{
+					{Type: porterv1.EnumNotificationEventType_ENUM_NOTIFICATION_EVENT_TYPE_DEPLOY, Enabled: true},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "deploy notifications disabled",
+			notifConf: &porterv1.NotificationConfig{
+				EnabledTypes: []*porterv1.NotificationTypeEnabled{
+					{Type: porterv1.EnumNotificationEventType_ENUM_NOTIFICATION_EVENT_TYPE_BUILD, Enabled: true},
+					{Type: porterv1.EnumNotificationEventType_ENUM_NOTIFICATION_EVENT_TYPE_DEPLOY, Enabled: false},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "deploy notifications not mentioned",
+			notifConf: &porterv1.NotificationConfig{
+				EnabledTypes: []*porterv1.NotificationTypeEnabled{
+					{Type: porterv1.EnumNotificationEventType_ENUM_NOTIFICATION_EVENT_TYPE_ALERT, Enabled: false},
+				},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if enabled := deployNotificationsEnabled(tt.notifConf); enabled != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, enabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package deployment_target
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/deploypolicy"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// CheckDeployPolicyInput is the input to CheckDeployPolicy
+type CheckDeployPolicyInput struct {
+	ProjectID uint
+	// DeploymentTargetIdentifier is the id or name of the deployment target being deployed to
+	DeploymentTargetIdentifier string
+	AppName                    string
+	Action                     deploypolicy.Action
+
+	// User is the user deploying, and APIToken is set instead when the deploy is made with a project API token
+	User     *models.User
+	APIToken *models.APIToken
+
+	GitBranch     string
+	GitRepository string
+
+	// BreakGlassReason overrides the policy when it is set. The override is recorded along with the reason. Applies which
+	// require approval are still held unless the deployer may approve them.
+	BreakGlassReason string
+}
+
+// DeployPolicyDecision is the outcome of checking a deploy against the policy of its deployment target
+type DeployPolicyDecision struct {
+	// Blocked is true if the deploy may not go out
+	Blocked bool
+	// Message lists the violated rules, which were either enforced or overridden
+	Message string
+	// Override records the deploy if it broke the glass to override the policy
+	Override *models.DeployPolicyOverride
+
+	// RequiresApproval is true if the apply satisfies the policy but must be held until it is approved, which
+	// HoldForApproval does
+	RequiresApproval     bool
+	DeploymentTargetID   uuid.UUID
+	DeploymentTargetName string
+	ApprovalExpiresAt    time.Time
+}
+
+// CheckDeployPolicy checks a deploy against the freeze windows and protection rules of its deployment target.
+// Deploys which violate the policy are blocked unless they give a reason for breaking the glass, in which case
+// the override is recorded. Applies to a policy that requires approval are held for approval rather than blocked,
+// unless an admin or holder of the approver role breaks the glass. Deploys to targets without a policy are always allowed.
+func CheckDeployPolicy(ctx context.Context, conf *config.Config, inp CheckDeployPolicyInput) (DeployPolicyDecision, error) {
+	ctx, span := telemetry.NewSpan(ctx, "check-deploy-policy")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: inp.ProjectID},
+		telemetry.AttributeKV{Key: "deployment-target-identifier", Value: inp.DeploymentTargetIdentifier},
+		telemetry.AttributeKV{Key: "app-name", Value: inp.AppName},
+		telemetry.AttributeKV{Key: "action", Value: string(inp.Action)},
+		telemetry.AttributeKV{Key: "break-glass", Value: inp.BreakGlassReason != ""},
+	)
+
+	var decision DeployPolicyDecision
+
+	if inp.DeploymentTargetIdentifier == "" {
+		return decision, nil
+	}
+
+	deploymentTarget, err := conf.Repo.DeploymentTarget().DeploymentTarget(inp.ProjectID, inp.DeploymentTargetIdentifier)
+	if err != nil {
+		return decision, telemetry.Error(ctx, span, err, "error reading deployment target")
+	}
+	if deploymentTarget.ID == uuid.Nil {
+		return decision, nil
+	}
+
+	policyModel, err := conf.Repo.DeployPolicy().ReadDeploymentTargetPolicy(ctx, inp.ProjectID, deploymentTarget.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return decision, nil
+		}
+		return decision, telemetry.Error(ctx, span, err, "error reading deployment target policy")
+	}
+
+	policy, err := policyModel.DeployPolicy()
+	if err != nil {
+		return decision, telemetry.Error(ctx, span, err, "error decoding deployment target policy")
+	}
+
+	if policy.Empty() {
+		return decision, nil
+	}
+
+	var roles []string
+	if policy.ApproverRole != "" || policy.RequiresApproval {
+		roles, err = deployerRoles(conf, inp.ProjectID, inp.User, inp.APIToken)
+		if err != nil {
+			return decision, telemetry.Error(ctx, span, err, "error reading roles of deployer")
+		}
+	}
+
+	violations, err := policy.Evaluate(deploypolicy.Deploy{
+		Action:        inp.Action,
+		Time:          time.Now(),
+		Roles:         roles,
+		GitBranch:     inp.GitBranch,
+		GitRepository: inp.GitRepository,
+	})
+	if err != nil {
+		return decision, telemetry.Error(ctx, span, err, "error evaluating deployment target policy")
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "violations", Value: len(violations)})
+
+	// applies are held for approval rather than refused. Only those who may approve the apply themselves can break
+	// the glass to send it out unapproved; anyone else breaking the glass only overrides the other rules.
+	var held bool
+	if inp.Action == deploypolicy.ActionApply && (inp.BreakGlassReason == "" || !policy.CanApprove(roles)) {
+		unapproved := violations[:0]
+
+		for _, violation := range violations {
+			if violation.Rule == deploypolicy.RuleApproval {
+				held = true
+				continue
+			}
+			unapproved = append(unapproved, violation)
+		}
+
+		violations = unapproved
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "held-for-approval", Value: held})
+
+	if len(violations) != 0 && inp.BreakGlassReason == "" {
+		messages := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			messages = append(messages, violation.Message)
+		}
+
+		decision.Blocked = true
+		decision.Message = fmt.Sprintf("%s. Give a break-glass reason to override the policy of %s", strings.Join(messages, "; "), deploymentTarget.VanityName)
+
+		return decision, nil
+	}
+
+	if held {
+		expiry, err := policy.ApprovalExpiry()
+		if err != nil {
+			return decision, telemetry.Error(ctx, span, err, "error reading approval timeout")
+		}
+
+		decision.RequiresApproval = true
+		decision.DeploymentTargetID = deploymentTarget.ID
+		decision.DeploymentTargetName = deploymentTarget.VanityName
+		decision.ApprovalExpiresAt = time.Now().UTC().Add(expiry)
+	}
+
+	if len(violations) == 0 {
+		return decision, nil
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.Message)
+	}
+
+	violationBytes, err := json.Marshal(violations)
+	if err != nil {
+		return decision, telemetry.Error(ctx, span, err, "error encoding policy violations")
+	}
+
+	override := &models.DeployPolicyOverride{
+		ProjectID:          inp.ProjectID,
+		DeploymentTargetID: deploymentTarget.ID,
+		AppName:            inp.AppName,
+		Action:             inp.Action,
+		Reason:             inp.BreakGlassReason,
+		Violations:         violationBytes,
+	}
+
+	if inp.APIToken != nil {
+		override.APITokenID = inp.APIToken.UniqueID
+	} else if inp.User != nil {
+		override.UserID = inp.User.ID
+	}
+
+	decision.Override, err = conf.Repo.DeployPolicy().CreateDeployPolicyOverride(ctx, override)
+	if err != nil {
+		return decision, telemetry.Error(ctx, span, err, "error recording deploy policy override")
+	}
+
+	decision.Message = fmt.Sprintf("deploy overrode the policy of %s: %s", deploymentTarget.VanityName, strings.Join(messages, "; "))
+
+	return decision, nil
+}
+
+// deployerRoles returns the policy uids granted to the user or API token making a deploy
+func deployerRoles(conf *config.Config, projectID uint, user *models.User, apiToken *models.APIToken) ([]string, error) {
+	if apiToken != nil {
+		return []string{apiToken.PolicyUID}, nil
+	}
+
+	if user == nil || user.ID == 0 {
+		return nil, nil
+	}
+
+	role, err := conf.Repo.Project().ReadProjectRole(projectID, user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if role.Kind == types.RoleCustom {
+		return []string{role.PolicyUID}, nil
+	}
+
+	return []string{string(role.Kind)}, nil
+}
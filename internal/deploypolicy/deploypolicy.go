@@ -0,0 +1,324 @@
+// Package deploypolicy evaluates deploys to a deployment target against the target's policy: the
+// windows during which the target is frozen, the role required to deploy to it, the branches and
+// repositories that deploys may be built from, and whether deploys must be approved.
+package deploypolicy
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// MaxFreezeDuration is the longest a single freeze window may last. Longer freezes are expressed
+// with schedules that start more often.
+const MaxFreezeDuration = 7 * 24 * time.Hour
+
+const (
+	// DefaultApprovalTimeout is how long a revision awaits approval when the policy does not set a timeout
+	DefaultApprovalTimeout = 24 * time.Hour
+	// MaxApprovalTimeout is the longest a revision may await approval before it expires
+	MaxApprovalTimeout = 7 * 24 * time.Hour
+)
+
+// FreezeWindow is a recurring period during which deploys to a target are refused
+type FreezeWindow struct {
+	// Name identifies the window in error messages
+	Name string `json:"name"`
+	// Schedule is a cron expression for when the window starts, e.g. "0 15 * * FRI"
+	Schedule string `json:"schedule"`
+	// Duration is how long the window lasts once it starts, e.g. "9h", in the format of time.ParseDuration
+	Duration string `json:"duration"`
+	// Timezone is the IANA time zone the schedule is evaluated in, and defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+	// Reason is shown to whoever attempts to deploy during the window
+	Reason string `json:"reason,omitempty"`
+}
+
+// Policy is the set of rules deploys to a deployment target must satisfy
+type Policy struct {
+	FreezeWindows []FreezeWindow `json:"freeze_windows,omitempty"`
+	// ApproverRole is the uid of the policy a user or API token must be granted to deploy to the target:
+	// admin, developer, viewer or the uid of a custom role's policy. Admins always satisfy it.
+	ApproverRole string `json:"approver_role,omitempty"`
+	// AllowedBranches are path.Match patterns for the git branches deploys may be built from, e.g. release/*
+	AllowedBranches []string `json:"allowed_branches,omitempty"`
+	// AllowedSources are path.Match patterns for the git repositories deploys may be built from, in the
+	// form owner/name, e.g. acme/*
+	AllowedSources []string `json:"allowed_sources,omitempty"`
+	// RequiresApproval holds applies to the target until a user with the approver role, or an admin if no
+	// approver role is set, approves them
+	RequiresApproval bool `json:"requires_approval,omitempty"`
+	// ApprovalTimeout is how long a revision awaits approval before it expires, e.g. "4h", and defaults to
+	// DefaultApprovalTimeout
+	ApprovalTimeout string `json:"approval_timeout,omitempty"`
+}
+
+// Empty returns true if the policy places no restrictions on deploys
+func (p Policy) Empty() bool {
+	return len(p.FreezeWindows) == 0 && p.ApproverRole == "" && len(p.AllowedBranches) == 0 && len(p.AllowedSources) == 0 && !p.RequiresApproval
+}
+
+// Validate checks that the schedules, durations, time zones and patterns in the policy are usable
+func (p Policy) Validate() error {
+	for _, window := range p.FreezeWindows {
+		if window.Name == "" {
+			return errors.New("freeze windows must have a name")
+		}
+
+		if _, _, _, err := window.parse(); err != nil {
+			return fmt.Errorf("invalid freeze window %s: %w", window.Name, err)
+		}
+	}
+
+	for _, pattern := range p.AllowedBranches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+	}
+
+	for _, pattern := range p.AllowedSources {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid source pattern %q: %w", pattern, err)
+		}
+	}
+
+	if _, err := p.ApprovalExpiry(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ApprovalExpiry returns how long a revision awaits approval before it expires
+func (p Policy) ApprovalExpiry() (time.Duration, error) {
+	if p.ApprovalTimeout == "" {
+		return DefaultApprovalTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(p.ApprovalTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid approval timeout: %w", err)
+	}
+
+	if timeout < time.Minute || timeout > MaxApprovalTimeout {
+		return 0, fmt.Errorf("approval timeout must be between 1m and %s", MaxApprovalTimeout)
+	}
+
+	return timeout, nil
+}
+
+// CanApprove returns true if a user or API token granted the roles may approve or reject revisions held for
+// approval. Admins may always approve, and holders of the approver role may approve if one is set.
+func (p Policy) CanApprove(roles []string) bool {
+	if p.ApproverRole == "" {
+		return hasRole(roles, "admin")
+	}
+
+	return hasRole(roles, p.ApproverRole)
+}
+
+// ActiveFreeze returns the freeze window in effect at t and the time it ends, or nil if the target is not frozen.
+// If several windows are in effect, the one which ends last is returned.
+func (p Policy) ActiveFreeze(t time.Time) (*FreezeWindow, time.Time, error) {
+	var active *FreezeWindow
+	var until time.Time
+
+	for i := range p.FreezeWindows {
+		end, ok, err := p.FreezeWindows[i].activeUntil(t)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("invalid freeze window %s: %w", p.FreezeWindows[i].Name, err)
+		}
+
+		if ok && end.After(until) {
+			active = &p.FreezeWindows[i]
+			until = end
+		}
+	}
+
+	return active, until, nil
+}
+
+func (w FreezeWindow) parse() (*Schedule, time.Duration, *time.Location, error) {
+	schedule, err := ParseSchedule(w.Schedule)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid duration: %w", err)
+	}
+
+	if duration < time.Minute || duration > MaxFreezeDuration {
+		return nil, 0, nil, fmt.Errorf("duration must be between 1m and %s", MaxFreezeDuration)
+	}
+
+	loc := time.UTC
+	if w.Timezone != "" {
+		loc, err = time.LoadLocation(w.Timezone)
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+
+	return schedule, duration, loc, nil
+}
+
+// activeUntil returns the end of the most recent start of the window, if the window started less than its
+// duration before t
+func (w FreezeWindow) activeUntil(t time.Time) (time.Time, bool, error) {
+	schedule, duration, loc, err := w.parse()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	// walking back a minute at a time is cheap since windows last at most a week, and stepping in absolute
+	// time rather than wall clock time keeps daylight saving transitions from skipping or repeating starts
+	for start := t.Truncate(time.Minute); t.Sub(start) < duration; start = start.Add(-time.Minute) {
+		if schedule.Matches(start.In(loc)) {
+			return start.Add(duration), true, nil
+		}
+	}
+
+	return time.Time{}, false, nil
+}
+
+// Action is the kind of deploy being evaluated
+type Action string
+
+const (
+	// ActionApply deploys a new app definition or build
+	ActionApply Action = "apply"
+	// ActionUpdateImage deploys a new image tag with the current app definition
+	ActionUpdateImage Action = "update-image"
+	// ActionRollback redeploys a revision which was previously deployed to the target
+	ActionRollback Action = "rollback"
+	// ActionPromote deploys a revision which is running in another target
+	ActionPromote Action = "promote"
+)
+
+// Deploy describes a deploy to evaluate against a policy
+type Deploy struct {
+	Action Action
+	Time   time.Time
+	// Roles are the policy uids granted to whoever is deploying
+	Roles []string
+	// GitBranch and GitRepository describe where the deployed code was built from, if known
+	GitBranch     string
+	GitRepository string
+}
+
+// Rule names a rule of a policy
+type Rule string
+
+const (
+	RuleFreezeWindow    Rule = "freeze_window"
+	RuleApproverRole    Rule = "approver_role"
+	RuleAllowedBranches Rule = "allowed_branches"
+	RuleAllowedSources  Rule = "allowed_sources"
+	RuleApproval        Rule = "approval"
+)
+
+// Violation is a rule of the policy which a deploy does not satisfy
+type Violation struct {
+	Rule    Rule   `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Evaluate returns the rules of the policy which the deploy violates. Rollbacks and promotions redeploy
+// images which already ran in a target, so the branches and repositories they were built from are not
+// checked. Rollbacks restore a revision which was already approved, so they never require approval, while
+// applies which require it may be held until they are approved rather than refused.
+func (p Policy) Evaluate(d Deploy) ([]Violation, error) {
+	var violations []Violation
+
+	window, until, err := p.ActiveFreeze(d.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	if window != nil {
+		msg := fmt.Sprintf("deploys are frozen by %s until %s", window.Name, until.UTC().Format(time.RFC3339))
+		if window.Reason != "" {
+			msg = fmt.Sprintf("%s: %s", msg, window.Reason)
+		}
+
+		violations = append(violations, Violation{Rule: RuleFreezeWindow, Message: msg})
+	}
+
+	if p.ApproverRole != "" && !hasRole(d.Roles, p.ApproverRole) {
+		violations = append(violations, Violation{
+			Rule:    RuleApproverRole,
+			Message: fmt.Sprintf("deploys require the %s role", p.ApproverRole),
+		})
+	}
+
+	if p.RequiresApproval && d.Action != ActionRollback {
+		msg := "deploys require approval"
+		if d.Action != ActionApply {
+			msg = fmt.Sprintf("%s, which only applies can request", msg)
+		}
+
+		violations = append(violations, Violation{Rule: RuleApproval, Message: msg})
+	}
+
+	if d.Action == ActionRollback || d.Action == ActionPromote {
+		return violations, nil
+	}
+
+	if len(p.AllowedBranches) > 0 && !matchesAny(p.AllowedBranches, d.GitBranch, false) {
+		msg := fmt.Sprintf("deploys must be built from one of the branches %s", strings.Join(p.AllowedBranches, ", "))
+		if d.GitBranch != "" {
+			msg = fmt.Sprintf("%s, not %s", msg, d.GitBranch)
+		}
+
+		violations = append(violations, Violation{Rule: RuleAllowedBranches, Message: msg})
+	}
+
+	if len(p.AllowedSources) > 0 && !matchesAny(p.AllowedSources, d.GitRepository, true) {
+		msg := fmt.Sprintf("deploys must be built from one of the repositories %s", strings.Join(p.AllowedSources, ", "))
+		if d.GitRepository != "" {
+			msg = fmt.Sprintf("%s, not %s", msg, d.GitRepository)
+		}
+
+		violations = append(violations, Violation{Rule: RuleAllowedSources, Message: msg})
+	}
+
+	return violations, nil
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role || r == "admin" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesAny returns false for an empty value, since a deploy of unknown origin can't satisfy a pattern.
+// Repository names are compared case-insensitively, as git hosts treat them.
+func matchesAny(patterns []string, value string, foldCase bool) bool {
+	if value == "" {
+		return false
+	}
+
+	if foldCase {
+		value = strings.ToLower(value)
+	}
+
+	for _, pattern := range patterns {
+		if foldCase {
+			pattern = strings.ToLower(pattern)
+		}
+
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+
+	return false
+}
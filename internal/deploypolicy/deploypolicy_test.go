@@ -0,0 +1,253 @@
+package deploypolicy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		time    time.Time
+		matches bool
+		err     bool
+	}{
+		{spec: "0 15 * * FRI", time: time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC), matches: true},
+		{spec: "0 15 * * FRI", time: time.Date(2026, 10, 16, 15, 1, 0, 0, time.UTC), matches: false},
+		{spec: "0 15 * * 5", time: time.Date(2026, 10, 15, 15, 0, 0, 0, time.UTC), matches: false},
+		{spec: "*/15 9-17 * * 1-5", time: time.Date(2026, 10, 19, 9, 45, 0, 0, time.UTC), matches: true},
+		{spec: "*/15 9-17 * * 1-5", time: time.Date(2026, 10, 19, 9, 40, 0, 0, time.UTC), matches: false},
+		{spec: "0 0 * * 7", time: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), matches: true},
+		{spec: "0 0 24 dec *", time: time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC), matches: true},
+		// restricting both day fields matches either of them
+		{spec: "0 0 1 * MON", time: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), matches: true},
+		{spec: "0 0 1 * MON", time: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), matches: true},
+		{spec: "0 0 1 * MON", time: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), matches: false},
+		{spec: "5/20 * * * *", time: time.Date(2026, 10, 19, 0, 45, 0, 0, time.UTC), matches: true},
+		{spec: "0 15 * *", err: true},
+		{spec: "60 * * * *", err: true},
+		{spec: "0 17-9 * * *", err: true},
+		{spec: "0 * * * funday", err: true},
+		{spec: "*/0 * * * *", err: true},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.spec, err)
+			continue
+		}
+
+		if got := schedule.Matches(tt.time); got != tt.matches {
+			t.Errorf("%s at %s: expected match to be %t, got %t", tt.spec, tt.time, tt.matches, got)
+		}
+	}
+}
+
+func TestActiveFreeze(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database is unavailable: %s", err)
+	}
+
+	policy := Policy{FreezeWindows: []FreezeWindow{
+		{Name: "friday-afternoon", Schedule: "0 15 * * FRI", Duration: "9h", Timezone: "America/New_York"},
+		{Name: "nightly", Schedule: "0 23 * * *", Duration: "2h"},
+	}}
+
+	tests := []struct {
+		name   string
+		time   time.Time
+		window string
+		until  time.Time
+	}{
+		{
+			name:   "friday after 3pm in new york",
+			time:   time.Date(2026, 10, 16, 16, 30, 0, 0, newYork),
+			window: "friday-afternoon",
+			until:  time.Date(2026, 10, 17, 0, 0, 0, 0, newYork),
+		},
+		{
+			name: "friday before 3pm in new york",
+			time: time.Date(2026, 10, 16, 14, 59, 0, 0, newYork),
+		},
+		{
+			name: "3pm friday utc is before 3pm in new york",
+			time: time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC),
+		},
+		{
+			name:   "nightly window continues past midnight",
+			time:   time.Date(2026, 10, 20, 0, 30, 0, 0, time.UTC),
+			window: "nightly",
+			until:  time.Date(2026, 10, 20, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "window ends exactly at its duration",
+			time: time.Date(2026, 10, 20, 1, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		window, until, err := policy.ActiveFreeze(tt.time)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+
+		if tt.window == "" {
+			if window != nil {
+				t.Errorf("%s: expected no active window, got %s", tt.name, window.Name)
+			}
+			continue
+		}
+
+		if window == nil || window.Name != tt.window {
+			t.Errorf("%s: expected window %s to be active, got %v", tt.name, tt.window, window)
+			continue
+		}
+
+		if !until.Equal(tt.until) {
+			t.Errorf("%s: expected window to end at %s, got %s", tt.name, tt.until, until)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	policy := Policy{
+		FreezeWindows:   []FreezeWindow{{Name: "monday-noon", Schedule: "0 12 * * MON", Duration: "1h", Reason: "weekly sync"}},
+		ApproverRole:    "release-managers",
+		AllowedBranches: []string{"main", "release/*"},
+		AllowedSources:  []string{"acme/*"},
+	}
+
+	tests := []struct {
+		name   string
+		deploy Deploy
+		rules  []Rule
+	}{
+		{
+			name:   "allowed deploy outside of the freeze",
+			deploy: Deploy{Action: ActionApply, Time: now.Add(2 * time.Hour), Roles: []string{"release-managers"}, GitBranch: "release/v2", GitRepository: "Acme/API"},
+		},
+		{
+			name:   "admins satisfy the approver role",
+			deploy: Deploy{Action: ActionApply, Time: now.Add(2 * time.Hour), Roles: []string{"admin"}, GitBranch: "main", GitRepository: "acme/api"},
+		},
+		{
+			name:   "every rule violated",
+			deploy: Deploy{Action: ActionApply, Time: now, Roles: []string{"developer"}, GitBranch: "feature/x", GitRepository: "other/api"},
+			rules:  []Rule{RuleFreezeWindow, RuleApproverRole, RuleAllowedBranches, RuleAllowedSources},
+		},
+		{
+			name:   "deploys of unknown origin violate source rules",
+			deploy: Deploy{Action: ActionUpdateImage, Time: now.Add(2 * time.Hour), Roles: []string{"release-managers"}},
+			rules:  []Rule{RuleAllowedBranches, RuleAllowedSources},
+		},
+		{
+			name:   "rollbacks skip source rules",
+			deploy: Deploy{Action: ActionRollback, Time: now, Roles: []string{"release-managers"}},
+			rules:  []Rule{RuleFreezeWindow},
+		},
+	}
+
+	for _, tt := range tests {
+		violations, err := policy.Evaluate(tt.deploy)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+
+		if len(violations) != len(tt.rules) {
+			t.Errorf("%s: expected %d violations, got %v", tt.name, len(tt.rules), violations)
+			continue
+		}
+
+		for i, rule := range tt.rules {
+			if violations[i].Rule != rule {
+				t.Errorf("%s: expected violation %d to be %s, got %s", tt.name, i, rule, violations[i].Rule)
+			}
+		}
+	}
+}
+
+func TestEvaluateApproval(t *testing.T) {
+	policy := Policy{RequiresApproval: true}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		action   Action
+		approval bool
+	}{
+		{action: ActionApply, approval: true},
+		{action: ActionUpdateImage, approval: true},
+		{action: ActionPromote, approval: true},
+		{action: ActionRollback, approval: false},
+	}
+
+	for _, tt := range tests {
+		violations, err := policy.Evaluate(Deploy{Action: tt.action, Time: now})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.action, err)
+		}
+
+		got := len(violations) == 1 && violations[0].Rule == RuleApproval
+		if got != tt.approval || (!tt.approval && len(violations) != 0) {
+			t.Errorf("%s: expected approval to be required to be %t, got %v", tt.action, tt.approval, violations)
+		}
+	}
+}
+
+func TestCanApprove(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		roles  []string
+		ok     bool
+	}{
+		{name: "admins approve without an approver role", policy: Policy{RequiresApproval: true}, roles: []string{"admin"}, ok: true},
+		{name: "developers may not approve without an approver role", policy: Policy{RequiresApproval: true}, roles: []string{"developer"}, ok: false},
+		{name: "approver role approves", policy: Policy{RequiresApproval: true, ApproverRole: "release-managers"}, roles: []string{"release-managers"}, ok: true},
+		{name: "admins satisfy the approver role", policy: Policy{RequiresApproval: true, ApproverRole: "release-managers"}, roles: []string{"admin"}, ok: true},
+		{name: "no roles", policy: Policy{RequiresApproval: true}, ok: false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.CanApprove(tt.roles); got != tt.ok {
+			t.Errorf("%s: expected %t, got %t", tt.name, tt.ok, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		err    bool
+	}{
+		{name: "empty policy", policy: Policy{}},
+		{name: "valid window", policy: Policy{FreezeWindows: []FreezeWindow{{Name: "w", Schedule: "0 15 * * FRI", Duration: "9h", Timezone: "UTC"}}}},
+		{name: "unnamed window", policy: Policy{FreezeWindows: []FreezeWindow{{Schedule: "0 15 * * FRI", Duration: "9h"}}}, err: true},
+		{name: "window too long", policy: Policy{FreezeWindows: []FreezeWindow{{Name: "w", Schedule: "0 15 * * FRI", Duration: "200h"}}}, err: true},
+		{name: "unknown timezone", policy: Policy{FreezeWindows: []FreezeWindow{{Name: "w", Schedule: "0 15 * * FRI", Duration: "1h", Timezone: "Mars/Olympus"}}}, err: true},
+		{name: "bad branch pattern", policy: Policy{AllowedBranches: []string{"release/["}}, err: true},
+		{name: "approval timeout", policy: Policy{RequiresApproval: true, ApprovalTimeout: "4h"}},
+		{name: "approval timeout too long", policy: Policy{RequiresApproval: true, ApprovalTimeout: "200h"}, err: true},
+		{name: "invalid approval timeout", policy: Policy{RequiresApproval: true, ApprovalTimeout: "soon"}, err: true},
+	}
+
+	for _, tt := range tests {
+		err := tt.policy.Validate()
+		if tt.err && err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+		if !tt.err && err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// envelopeMagic prefixes every envelope ciphertext. Ciphertexts without it were
+// written by Encrypt with the static ENCRYPTION_KEY.
+var envelopeMagic = []byte("PENV")
+
+const (
+	envelopeFormatVersion byte = 1
+
+	// keyVersionCacheTTL is how long the current key version reported by the
+	// key manager is cached before it is looked up again
+	keyVersionCacheTTL = time.Minute
+)
+
+// ErrMalformedEnvelope is returned when a ciphertext carries the envelope magic
+// bytes but its header cannot be parsed
+var ErrMalformedEnvelope = errors.New("malformed envelope ciphertext")
+
+// Envelope encrypts data with a random per-record data key, which is itself
+// wrapped by a key-encryption key from a KeyManager. Ciphertexts take the form
+//
+//	magic|format|len(keyVersion)|keyVersion|len(wrappedKey)|wrappedKey|nonce|ciphertext|tag
+//
+// where the header is authenticated as additional data. Because every ciphertext
+// records the key version that wrapped its data key, records can be rotated
+// lazily: see NeedsRotation and DecryptAndRotate.
+type Envelope struct {
+	kms KeyManager
+
+	// legacyKey decrypts ciphertexts that predate envelope encryption
+	legacyKey *[32]byte
+
+	mu               sync.Mutex
+	currentVersion   string
+	versionFetchedAt time.Time
+}
+
+// NewEnvelope returns an Envelope which wraps data keys with kms. If legacyKey is
+// non-nil, ciphertexts written by Encrypt are decrypted with it.
+func NewEnvelope(kms KeyManager, legacyKey *[32]byte) *Envelope {
+	return &Envelope{
+		kms:       kms,
+		legacyKey: legacyKey,
+	}
+}
+
+// IsEnvelope returns true if the ciphertext was written by Envelope.Encrypt
+func IsEnvelope(ciphertext []byte) bool {
+	return bytes.HasPrefix(ciphertext, envelopeMagic)
+}
+
+// EnvelopeKeyVersion returns the key-encryption key version recorded in an
+// envelope ciphertext
+func EnvelopeKeyVersion(ciphertext []byte) (string, error) {
+	header, err := parseEnvelopeHeader(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	return header.keyVersion, nil
+}
+
+// Encrypt generates a new data key, encrypts plaintext with it using 256-bit
+// AES-GCM and wraps the data key with the current key-encryption key
+func (e *Envelope) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	dataKey := NewEncryptionKey()
+
+	wrapped, keyVersion, err := e.kms.WrapKey(ctx, dataKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := encodeEnvelopeHeader(keyVersion, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+gcm.Overhead())
+	res = append(res, header...)
+	res = append(res, nonce...)
+
+	return gcm.Seal(res, nonce, plaintext, header), nil
+}
+
+// Decrypt decrypts an envelope ciphertext, or a legacy ciphertext if the Envelope
+// was created with a legacy key
+func (e *Envelope) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if !IsEnvelope(ciphertext) {
+		if e.legacyKey == nil {
+			return nil, errors.New("ciphertext is not an envelope and no legacy key is configured")
+		}
+
+		return Decrypt(ciphertext, e.legacyKey)
+	}
+
+	header, err := parseEnvelopeHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKeyBytes, err := e.kms.UnwrapKey(ctx, header.wrappedKey, header.keyVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dataKeyBytes) != 32 {
+		return nil, errors.New("unwrapped data key has invalid length")
+	}
+
+	dataKey := [32]byte{}
+	copy(dataKey[:], dataKeyBytes)
+
+	gcm, err := newGCM(&dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	body := ciphertext[header.length:]
+
+	if len(body) < gcm.NonceSize() {
+		return nil, ErrMalformedEnvelope
+	}
+
+	return gcm.Open(nil,
+		body[:gcm.NonceSize()],
+		body[gcm.NonceSize():],
+		ciphertext[:header.length],
+	)
+}
+
+// NeedsRotation returns true if the ciphertext is a legacy ciphertext or its data
+// key was wrapped with a key version other than the current one
+func (e *Envelope) NeedsRotation(ctx context.Context, ciphertext []byte) (bool, error) {
+	if !IsEnvelope(ciphertext) {
+		return true, nil
+	}
+
+	keyVersion, err := EnvelopeKeyVersion(ciphertext)
+	if err != nil {
+		return false, err
+	}
+
+	currentVersion, err := e.getCurrentKeyVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return keyVersion != currentVersion, nil
+}
+
+// DecryptAndRotate decrypts the ciphertext and, if it needs rotation, re-encrypts
+// the plaintext under the current key version. The rotated ciphertext is nil if no
+// rotation was necessary; callers should persist it when it is non-nil.
+func (e *Envelope) DecryptAndRotate(ctx context.Context, ciphertext []byte) (plaintext []byte, rotated []byte, err error) {
+	plaintext, err = e.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	needsRotation, err := e.NeedsRotation(ctx, ciphertext)
+	if err != nil || !needsRotation {
+		// failing to determine the current version should not fail the read
+		return plaintext, nil, nil
+	}
+
+	rotated, err = e.Encrypt(ctx, plaintext)
+	if err != nil {
+		return plaintext, nil, nil
+	}
+
+	return plaintext, rotated, nil
+}
+
+func (e *Envelope) getCurrentKeyVersion(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.currentVersion != "" && time.Since(e.versionFetchedAt) < keyVersionCacheTTL {
+		return e.currentVersion, nil
+	}
+
+	version, err := e.kms.CurrentKeyVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	e.currentVersion = version
+	e.versionFetchedAt = time.Now()
+
+	return version, nil
+}
+
+type envelopeHeader struct {
+	keyVersion string
+	wrappedKey []byte
+
+	// length is the length of the encoded header in bytes
+	length int
+}
+
+func encodeEnvelopeHeader(keyVersion string, wrappedKey []byte) ([]byte, error) {
+	if len(keyVersion) > 0xffff {
+		return nil, errors.New("key version is too long")
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	buf.Write(envelopeMagic)
+	buf.WriteByte(envelopeFormatVersion)
+
+	lenBytes := make([]byte, 4)
+
+	binary.BigEndian.PutUint16(lenBytes, uint16(len(keyVersion)))
+	buf.Write(lenBytes[:2])
+	buf.WriteString(keyVersion)
+
+	binary.BigEndian.PutUint32(lenBytes, uint32(len(wrappedKey)))
+	buf.Write(lenBytes)
+	buf.Write(wrappedKey)
+
+	return buf.Bytes(), nil
+}
+
+func parseEnvelopeHeader(ciphertext []byte) (*envelopeHeader, error) {
+	if !IsEnvelope(ciphertext) {
+		return nil, ErrMalformedEnvelope
+	}
+
+	pos := len(envelopeMagic)
+
+	if len(ciphertext) < pos+3 || ciphertext[pos] != envelopeFormatVersion {
+		return nil, ErrMalformedEnvelope
+	}
+
+	pos++
+
+	keyVersionLen := int(binary.BigEndian.Uint16(ciphertext[pos:]))
+	pos += 2
+
+	if len(ciphertext) < pos+keyVersionLen+4 {
+		return nil, ErrMalformedEnvelope
+	}
+
+	keyVersion := string(ciphertext[pos : pos+keyVersionLen])
+	pos += keyVersionLen
+
+	wrappedKeyLen := int(binary.BigEndian.Uint32(ciphertext[pos:]))
+	pos += 4
+
+	if wrappedKeyLen < 0 || len(ciphertext) < pos+wrappedKeyLen {
+		return nil, ErrMalformedEnvelope
+	}
+
+	wrappedKey := ciphertext[pos : pos+wrappedKeyLen]
+	pos += wrappedKeyLen
+
+	return &envelopeHeader{
+		keyVersion: keyVersion,
+		wrappedKey: wrappedKey,
+		length:     pos,
+	}, nil
+}
+
+func newGCM(key *[32]byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,150 @@
+package encryption_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/karagatandev/porter/internal/encryption"
+)
+
+func TestEnvelopeEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	kms := encryption.NewLocalKeyManager("v1", encryption.NewEncryptionKey())
+	envelope := encryption.NewEnvelope(kms, nil)
+
+	plaintext := []byte("my-secret-token")
+
+	ciphertext, err := envelope.Encrypt(ctx, plaintext)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !encryption.IsEnvelope(ciphertext) {
+		t.Fatalf("expected ciphertext to be an envelope")
+	}
+
+	keyVersion, err := encryption.EnvelopeKeyVersion(ciphertext)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if keyVersion != "v1" {
+		t.Errorf("incorrect key version: expected %s, got %s\n", "v1", keyVersion)
+	}
+
+	decrypted, err := envelope.Decrypt(ctx, ciphertext)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Errorf("incorrect plaintext: expected %s, got %s\n", plaintext, decrypted)
+	}
+}
+
+func TestEnvelopeDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	kms := encryption.NewLocalKeyManager("v1", encryption.NewEncryptionKey())
+	envelope := encryption.NewEnvelope(kms, nil)
+
+	ciphertext, err := envelope.Encrypt(ctx, []byte("my-secret-token"))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	ciphertext[len(ciphertext)-1] ^= 0xff
+
+	if _, err := envelope.Decrypt(ctx, ciphertext); err == nil {
+		t.Errorf("expected tampered ciphertext to fail decryption")
+	}
+
+	if _, err := envelope.Decrypt(ctx, []byte("PENV\x01")); err == nil {
+		t.Errorf("expected truncated envelope to fail decryption")
+	}
+}
+
+func TestEnvelopeLegacyCiphertext(t *testing.T) {
+	ctx := context.Background()
+	legacyKey := encryption.NewEncryptionKey()
+	kms := encryption.NewLocalKeyManager("v1", encryption.NewEncryptionKey())
+	envelope := encryption.NewEnvelope(kms, legacyKey)
+
+	plaintext := []byte("my-secret-token")
+
+	legacyCiphertext, err := encryption.Encrypt(plaintext, legacyKey)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	decrypted, rotated, err := envelope.DecryptAndRotate(ctx, legacyCiphertext)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Errorf("incorrect plaintext: expected %s, got %s\n", plaintext, decrypted)
+	}
+
+	if rotated == nil || !encryption.IsEnvelope(rotated) {
+		t.Fatalf("expected legacy ciphertext to be rotated into an envelope")
+	}
+
+	decrypted, err = encryption.NewEnvelope(kms, nil).Decrypt(ctx, rotated)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Errorf("incorrect rotated plaintext: expected %s, got %s\n", plaintext, decrypted)
+	}
+}
+
+func TestEnvelopeLazyRotation(t *testing.T) {
+	ctx := context.Background()
+	kms := encryption.NewLocalKeyManager("v1", encryption.NewEncryptionKey())
+
+	ciphertext, err := encryption.NewEnvelope(kms, nil).Encrypt(ctx, []byte("my-secret-token"))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := kms.AddKey("v2", encryption.NewEncryptionKey()); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// use a fresh envelope so the cached key version is not reused
+	envelope := encryption.NewEnvelope(kms, nil)
+
+	needsRotation, err := envelope.NeedsRotation(ctx, ciphertext)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !needsRotation {
+		t.Fatalf("expected ciphertext wrapped with v1 to need rotation")
+	}
+
+	_, rotated, err := envelope.DecryptAndRotate(ctx, ciphertext)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	keyVersion, err := encryption.EnvelopeKeyVersion(rotated)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if keyVersion != "v2" {
+		t.Errorf("incorrect rotated key version: expected %s, got %s\n", "v2", keyVersion)
+	}
+
+	_, rotated, err = envelope.DecryptAndRotate(ctx, rotated)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if rotated != nil {
+		t.Errorf("expected ciphertext wrapped with the current key to not be rotated")
+	}
+}
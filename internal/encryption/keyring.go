@@ -0,0 +1,13 @@
+package encryption
+
+// Keyring holds the keys, besides the static ENCRYPTION_KEY, that are used to
+// encrypt and decrypt values at rest
+type Keyring struct {
+	// Envelope encrypts values with per-record data keys, if envelope encryption
+	// is enabled
+	Envelope *Envelope
+
+	// PreviousKey decrypts values which a key rotation has not yet re-encrypted with
+	// the current static key
+	PreviousKey *[32]byte
+}
@@ -0,0 +1,84 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// KeyManager wraps and unwraps per-record data keys with a key-encryption key
+// that is held by a key management service. Implementations must be safe for
+// concurrent use.
+type KeyManager interface {
+	// WrapKey encrypts a data key with the current key-encryption key, and returns
+	// the wrapped key along with the version of the key-encryption key that was used
+	WrapKey(ctx context.Context, dataKey []byte) (wrapped []byte, keyVersion string, err error)
+
+	// UnwrapKey decrypts a data key that was wrapped with the given key version
+	UnwrapKey(ctx context.Context, wrapped []byte, keyVersion string) ([]byte, error)
+
+	// CurrentKeyVersion returns the version of the key-encryption key that WrapKey
+	// currently uses. Ciphertexts carrying any other version are due for rotation.
+	CurrentKeyVersion(ctx context.Context) (string, error)
+}
+
+// KeyManagerProvider is the name of a supported key management service
+type KeyManagerProvider string
+
+const (
+	// KeyManagerProvider_Local wraps data keys with static keys held in memory
+	KeyManagerProvider_Local KeyManagerProvider = "local"
+
+	// KeyManagerProvider_AWS wraps data keys with an AWS KMS key
+	KeyManagerProvider_AWS KeyManagerProvider = "aws"
+
+	// KeyManagerProvider_GCP wraps data keys with a GCP Cloud KMS crypto key
+	KeyManagerProvider_GCP KeyManagerProvider = "gcp"
+
+	// KeyManagerProvider_Vault wraps data keys with a Vault Transit key
+	KeyManagerProvider_Vault KeyManagerProvider = "vault"
+)
+
+// KeyManagerOpts are the options for creating a KeyManager through NewKeyManager
+type KeyManagerOpts struct {
+	Provider KeyManagerProvider
+
+	// KeyID identifies the key-encryption key. This is the key ARN or alias for AWS,
+	// the full crypto key resource name for GCP, and the transit key name for Vault.
+	KeyID string
+
+	// AWSRegion is the region of the AWS KMS key
+	AWSRegion string
+
+	// VaultServerURL, VaultToken and VaultTransitMount configure the Vault Transit engine
+	VaultServerURL    string
+	VaultToken        string
+	VaultTransitMount string
+
+	// LocalKey is the key-encryption key used by the local key manager
+	LocalKey *[32]byte
+}
+
+// ErrUnknownKeyVersion is returned when a data key was wrapped with a key version
+// that the key manager does not know about
+var ErrUnknownKeyVersion = errors.New("unknown key-encryption key version")
+
+// NewKeyManager returns the KeyManager for the configured provider
+func NewKeyManager(ctx context.Context, opts KeyManagerOpts) (KeyManager, error) {
+	switch opts.Provider {
+	case KeyManagerProvider_Local:
+		if opts.LocalKey == nil {
+			return nil, errors.New("local key manager requires a key")
+		}
+
+		return NewLocalKeyManager("local-1", opts.LocalKey), nil
+	case KeyManagerProvider_AWS:
+		return NewAWSKeyManager(opts.KeyID, opts.AWSRegion)
+	case KeyManagerProvider_GCP:
+		return NewGCPKeyManager(ctx, opts.KeyID)
+	case KeyManagerProvider_Vault:
+		return NewVaultTransitKeyManager(opts.VaultServerURL, opts.VaultToken, opts.VaultTransitMount, opts.KeyID)
+	default:
+		return nil, fmt.Errorf("unsupported key manager provider %q", opts.Provider)
+	}
+}
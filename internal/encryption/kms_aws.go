@@ -0,0 +1,80 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// AWSKeyManager is a KeyManager backed by an AWS KMS symmetric key. The key
+// version is the ARN of the KMS key, so pointing KeyID at a new key causes
+// existing ciphertexts to be re-wrapped.
+type AWSKeyManager struct {
+	client *kms.KMS
+	keyID  string
+}
+
+// NewAWSKeyManager returns an AWSKeyManager for the given key ARN or alias. Credentials
+// are resolved through the default AWS credential chain.
+func NewAWSKeyManager(keyID, region string) (*AWSKeyManager, error) {
+	if keyID == "" {
+		return nil, errors.New("aws key manager requires a key id")
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AWSKeyManager{
+		client: kms.New(sess),
+		keyID:  keyID,
+	}, nil
+}
+
+// WrapKey encrypts the data key with the configured KMS key
+func (a *AWSKeyManager) WrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	out, err := a.client.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(a.keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return out.CiphertextBlob, aws.StringValue(out.KeyId), nil
+}
+
+// UnwrapKey decrypts the data key with the KMS key identified by keyVersion
+func (a *AWSKeyManager) UnwrapKey(ctx context.Context, wrapped []byte, keyVersion string) ([]byte, error) {
+	out, err := a.client.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(keyVersion),
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Plaintext, nil
+}
+
+// CurrentKeyVersion returns the ARN of the configured KMS key
+func (a *AWSKeyManager) CurrentKeyVersion(ctx context.Context) (string, error) {
+	out, err := a.client.DescribeKeyWithContext(ctx, &kms.DescribeKeyInput{
+		KeyId: aws.String(a.keyID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if out.KeyMetadata == nil {
+		return "", errors.New("kms key metadata is empty")
+	}
+
+	return aws.StringValue(out.KeyMetadata.Arn), nil
+}
@@ -0,0 +1,152 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"golang.org/x/oauth2/google"
+)
+
+const gcpKMSEndpoint = "https://cloudkms.googleapis.com/v1/"
+
+// GCPKeyManager is a KeyManager backed by a GCP Cloud KMS symmetric crypto key. The
+// key version is the resource name of the crypto key version that wrapped the data
+// key, so rotating the primary version in Cloud KMS causes existing ciphertexts to
+// be re-wrapped.
+type GCPKeyManager struct {
+	httpClient *http.Client
+	keyName    string
+}
+
+// NewGCPKeyManager returns a GCPKeyManager for the crypto key with the given resource
+// name (projects/*/locations/*/keyRings/*/cryptoKeys/*). Credentials are resolved
+// through Application Default Credentials.
+func NewGCPKeyManager(ctx context.Context, keyName string) (*GCPKeyManager, error) {
+	if keyName == "" {
+		return nil, errors.New("gcp key manager requires a crypto key name")
+	}
+
+	httpClient, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloudkms")
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCPKeyManager{
+		httpClient: httpClient,
+		keyName:    keyName,
+	}, nil
+}
+
+type gcpEncryptRequest struct {
+	Plaintext string `json:"plaintext"`
+}
+
+type gcpEncryptResponse struct {
+	Name       string `json:"name"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+type gcpDecryptRequest struct {
+	Ciphertext string `json:"ciphertext"`
+}
+
+type gcpDecryptResponse struct {
+	Plaintext string `json:"plaintext"`
+}
+
+type gcpCryptoKey struct {
+	Primary struct {
+		Name string `json:"name"`
+	} `json:"primary"`
+}
+
+// WrapKey encrypts the data key with the primary version of the crypto key
+func (g *GCPKeyManager) WrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	res := &gcpEncryptResponse{}
+
+	err := g.doRequest(ctx, http.MethodPost, g.keyName+":encrypt", &gcpEncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}, res)
+	if err != nil {
+		return nil, "", err
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(res.Ciphertext)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return wrapped, res.Name, nil
+}
+
+// UnwrapKey decrypts the data key. Cloud KMS identifies the key version from the
+// ciphertext itself, so keyVersion is only used for error reporting.
+func (g *GCPKeyManager) UnwrapKey(ctx context.Context, wrapped []byte, keyVersion string) ([]byte, error) {
+	res := &gcpDecryptResponse{}
+
+	err := g.doRequest(ctx, http.MethodPost, g.keyName+":decrypt", &gcpDecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrapped),
+	}, res)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data key with %s: %w", keyVersion, err)
+	}
+
+	return base64.StdEncoding.DecodeString(res.Plaintext)
+}
+
+// CurrentKeyVersion returns the resource name of the primary crypto key version
+func (g *GCPKeyManager) CurrentKeyVersion(ctx context.Context) (string, error) {
+	res := &gcpCryptoKey{}
+
+	err := g.doRequest(ctx, http.MethodGet, g.keyName, nil, res)
+	if err != nil {
+		return "", err
+	}
+
+	if res.Primary.Name == "" {
+		return "", errors.New("crypto key has no primary version")
+	}
+
+	return res.Primary.Name, nil
+}
+
+func (g *GCPKeyManager) doRequest(ctx context.Context, method, path string, data interface{}, dst interface{}) error {
+	var body io.Reader
+
+	if data != nil {
+		strData, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(strData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, gcpKMSEndpoint+path, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	res, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		resBytes, _ := io.ReadAll(res.Body)
+
+		return fmt.Errorf("cloud kms request failed with status code %d: %s", res.StatusCode, string(resBytes))
+	}
+
+	return json.NewDecoder(res.Body).Decode(dst)
+}
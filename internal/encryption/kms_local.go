@@ -0,0 +1,79 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// LocalKeyManager is a KeyManager which wraps data keys with static keys held
+// in memory. It is meant for tests and for single-node installations which do
+// not have access to an external key management service.
+type LocalKeyManager struct {
+	mu             sync.RWMutex
+	keys           map[string]*[32]byte
+	currentVersion string
+}
+
+// NewLocalKeyManager returns a LocalKeyManager which wraps data keys with the
+// given key, identified by version
+func NewLocalKeyManager(version string, key *[32]byte) *LocalKeyManager {
+	return &LocalKeyManager{
+		keys: map[string]*[32]byte{
+			version: key,
+		},
+		currentVersion: version,
+	}
+}
+
+// AddKey registers a new key-encryption key under the given version and makes it the
+// current key. Previously registered keys remain available for unwrapping.
+func (l *LocalKeyManager) AddKey(version string, key *[32]byte) error {
+	if version == "" {
+		return errors.New("key version cannot be empty")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.keys[version] = key
+	l.currentVersion = version
+
+	return nil
+}
+
+// WrapKey encrypts the data key with the current key
+func (l *LocalKeyManager) WrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	l.mu.RLock()
+	version := l.currentVersion
+	key := l.keys[version]
+	l.mu.RUnlock()
+
+	wrapped, err := Encrypt(dataKey, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return wrapped, version, nil
+}
+
+// UnwrapKey decrypts the data key with the key registered under keyVersion
+func (l *LocalKeyManager) UnwrapKey(ctx context.Context, wrapped []byte, keyVersion string) ([]byte, error) {
+	l.mu.RLock()
+	key, ok := l.keys[keyVersion]
+	l.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownKeyVersion
+	}
+
+	return Decrypt(wrapped, key)
+}
+
+// CurrentKeyVersion returns the version of the key used by WrapKey
+func (l *LocalKeyManager) CurrentKeyVersion(ctx context.Context) (string, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.currentVersion, nil
+}
@@ -0,0 +1,171 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// VaultTransitKeyManager is a KeyManager backed by a key in Vault's Transit secrets
+// engine. The key version is the "vN" segment of the Transit ciphertext, so rotating
+// the key in Vault causes existing ciphertexts to be re-wrapped.
+type VaultTransitKeyManager struct {
+	serverURL  string
+	token      string
+	mount      string
+	keyName    string
+	httpClient *http.Client
+}
+
+// NewVaultTransitKeyManager returns a VaultTransitKeyManager for the transit key keyName
+// on the Transit engine mounted at mount
+func NewVaultTransitKeyManager(serverURL, token, mount, keyName string) (*VaultTransitKeyManager, error) {
+	if serverURL == "" || token == "" || keyName == "" {
+		return nil, errors.New("vault transit key manager requires a server url, token and key name")
+	}
+
+	if mount == "" {
+		mount = "transit"
+	}
+
+	return &VaultTransitKeyManager{
+		serverURL: serverURL,
+		token:     token,
+		mount:     mount,
+		keyName:   keyName,
+		httpClient: &http.Client{
+			Timeout: time.Minute,
+		},
+	}, nil
+}
+
+type vaultTransitEncryptRequest struct {
+	Plaintext string `json:"plaintext"`
+}
+
+type vaultTransitEncryptResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+	} `json:"data"`
+}
+
+type vaultTransitDecryptRequest struct {
+	Ciphertext string `json:"ciphertext"`
+}
+
+type vaultTransitDecryptResponse struct {
+	Data struct {
+		Plaintext string `json:"plaintext"`
+	} `json:"data"`
+}
+
+type vaultTransitKeyResponse struct {
+	Data struct {
+		LatestVersion int `json:"latest_version"`
+	} `json:"data"`
+}
+
+// WrapKey encrypts the data key with the latest version of the transit key
+func (v *VaultTransitKeyManager) WrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	res := &vaultTransitEncryptResponse{}
+
+	err := v.doRequest(ctx, http.MethodPost, path.Join("/v1", v.mount, "encrypt", v.keyName), &vaultTransitEncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}, res)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// transit ciphertexts take the form vault:v<version>:<base64 ciphertext>
+	parts := strings.SplitN(res.Data.Ciphertext, ":", 3)
+
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, "", errors.New("unexpected vault transit ciphertext format")
+	}
+
+	return []byte(res.Data.Ciphertext), parts[1], nil
+}
+
+// UnwrapKey decrypts the data key. Vault reads the key version from the transit
+// ciphertext itself.
+func (v *VaultTransitKeyManager) UnwrapKey(ctx context.Context, wrapped []byte, keyVersion string) ([]byte, error) {
+	if !strings.HasPrefix(string(wrapped), "vault:"+keyVersion+":") {
+		return nil, ErrUnknownKeyVersion
+	}
+
+	res := &vaultTransitDecryptResponse{}
+
+	err := v.doRequest(ctx, http.MethodPost, path.Join("/v1", v.mount, "decrypt", v.keyName), &vaultTransitDecryptRequest{
+		Ciphertext: string(wrapped),
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(res.Data.Plaintext)
+}
+
+// CurrentKeyVersion returns the latest version of the transit key
+func (v *VaultTransitKeyManager) CurrentKeyVersion(ctx context.Context) (string, error) {
+	res := &vaultTransitKeyResponse{}
+
+	err := v.doRequest(ctx, http.MethodGet, path.Join("/v1", v.mount, "keys", v.keyName), nil, res)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("v%d", res.Data.LatestVersion), nil
+}
+
+func (v *VaultTransitKeyManager) doRequest(ctx context.Context, method, reqPath string, data interface{}, dst interface{}) error {
+	reqURL, err := url.Parse(v.serverURL)
+	if err != nil {
+		return err
+	}
+
+	reqURL.Path = reqPath
+
+	var body io.Reader
+
+	if data != nil {
+		strData, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(strData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Accept", "application/json; charset=utf-8")
+	req.Header.Set("X-Vault-Token", v.token)
+
+	res, err := v.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		resBytes, _ := io.ReadAll(res.Body)
+
+		return fmt.Errorf("vault transit request failed with status code %d: %s", res.StatusCode, string(resBytes))
+	}
+
+	return json.NewDecoder(res.Body).Decode(dst)
+}
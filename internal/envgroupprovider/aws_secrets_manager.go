@@ -0,0 +1,67 @@
+package envgroupprovider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+func resolveAWSSecretsManager(ctx context.Context, repo repository.Repository, projectID uint, conf *types.AWSSecretsManagerEnvGroupProviderConfig) (map[string]string, error) {
+	awsInt, err := repo.AWSIntegration().ReadAWSIntegration(projectID, conf.AWSIntegrationID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading aws integration: %w", err)
+	}
+
+	sess, err := awsInt.GetSession()
+	if err != nil {
+		return nil, fmt.Errorf("error creating aws session: %w", err)
+	}
+
+	awsConf := aws.NewConfig().WithRegion(conf.Region)
+
+	switch conf.AuthMethod {
+	case types.AWSSecretsManagerAuthMethodAccessKey:
+	case types.AWSSecretsManagerAuthMethodAssumeRole:
+		awsConf = awsConf.WithCredentials(stscreds.NewCredentials(sess, conf.RoleARN))
+	default:
+		return nil, fmt.Errorf("unsupported aws secrets manager auth method %s", conf.AuthMethod)
+	}
+
+	out, err := secretsmanager.New(sess, awsConf).GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(conf.SecretID),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+			return nil, fmt.Errorf("secret %s was not found in %s", conf.SecretID, conf.Region)
+		}
+
+		return nil, fmt.Errorf("error getting secret %s: %w", conf.SecretID, err)
+	}
+
+	return secretValues(aws.StringValue(out.Name), out.SecretString, out.SecretBinary)
+}
+
+// secretValues returns the fields of a secret which holds a JSON object, or a single key named after the
+// secret otherwise
+func secretValues(name string, secretString *string, secretBinary []byte) (map[string]string, error) {
+	value := string(secretBinary)
+	if secretString != nil {
+		value = *secretString
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(value), &fields); err == nil {
+		return flatten(fields)
+	}
+
+	return map[string]string{lastPathSegment(name): value}, nil
+}
@@ -0,0 +1,141 @@
+// Package envgroupprovider resolves the secrets of external secrets managers which environment groups are
+// synced from. Secrets are resolved into flat maps of keys to values, which are renamed with the provider's
+// key mappings before they are published as the secret variables of an environment group.
+package envgroupprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// variableNameRegex matches the keys a kubernetes secret can hold
+var variableNameRegex = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// Resolve returns the values of a provider's secret, keyed by the provider's key names
+func Resolve(ctx context.Context, repo repository.Repository, provider *models.EnvGroupProvider) (map[string]string, error) {
+	switch provider.Type {
+	case types.EnvGroupProviderTypeVault:
+		conf, err := provider.VaultConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding vault config: %w", err)
+		}
+
+		return resolveVault(ctx, repo, provider.ProjectID, conf)
+	case types.EnvGroupProviderTypeAWSSecretsManager:
+		conf, err := provider.AWSSecretsManagerConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding aws secrets manager config: %w", err)
+		}
+
+		return resolveAWSSecretsManager(ctx, repo, provider.ProjectID, conf)
+	}
+
+	return nil, fmt.Errorf("unsupported env group provider type %s", provider.Type)
+}
+
+// MapKeys renames the keys of a resolved secret with the provider's key mappings. Keys which are not mapped
+// keep their name. An error is returned if a variable name is invalid or if two keys map to the same variable.
+func MapKeys(values map[string]string, mappings map[string]string) (map[string]string, error) {
+	res := make(map[string]string, len(values))
+	sources := make(map[string]string, len(values))
+
+	for _, key := range sortedKeys(values) {
+		variable := key
+		if mapped, ok := mappings[key]; ok {
+			variable = mapped
+		}
+
+		if !variableNameRegex.MatchString(variable) {
+			return nil, fmt.Errorf("key %s can't be used as a variable name: map it to a name made of letters, digits, '-', '_' or '.'", variable)
+		}
+
+		if source, ok := sources[variable]; ok {
+			return nil, fmt.Errorf("keys %s and %s both map to variable %s", source, key, variable)
+		}
+
+		sources[variable] = key
+		res[variable] = values[key]
+	}
+
+	return res, nil
+}
+
+// PreviewKeys returns the keys of a resolved secret and the variables they are synced to, without their values
+func PreviewKeys(values map[string]string, mappings map[string]string) []types.EnvGroupProviderPreviewKey {
+	keys := make([]types.EnvGroupProviderPreviewKey, 0, len(values))
+
+	for _, key := range sortedKeys(values) {
+		variable := key
+		if mapped, ok := mappings[key]; ok {
+			variable = mapped
+		}
+
+		keys = append(keys, types.EnvGroupProviderPreviewKey{
+			ProviderKey: key,
+			Variable:    variable,
+		})
+	}
+
+	return keys
+}
+
+// ValidateKeyMappings checks that all mapped variable names are valid
+func ValidateKeyMappings(mappings map[string]string) error {
+	for key, variable := range mappings {
+		if key == "" {
+			return fmt.Errorf("key mappings can't map an empty key")
+		}
+
+		if !variableNameRegex.MatchString(variable) {
+			return fmt.Errorf("key %s is mapped to invalid variable name %q: variable names must be made of letters, digits, '-', '_' or '.'", key, variable)
+		}
+	}
+
+	return nil
+}
+
+// flatten converts the fields of a secret to strings. Fields which are not strings are JSON encoded.
+func flatten(fields map[string]interface{}) (map[string]string, error) {
+	res := make(map[string]string, len(fields))
+
+	for key, value := range fields {
+		switch v := value.(type) {
+		case string:
+			res[key] = v
+		case nil:
+			res[key] = ""
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("error encoding field %s: %w", key, err)
+			}
+			res[key] = string(encoded)
+		}
+	}
+
+	return res, nil
+}
+
+// lastPathSegment returns the part of a secret name after its last slash
+func lastPathSegment(name string) string {
+	name = strings.TrimSuffix(name, "/")
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,183 @@
+package envgroupprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/karagatandev/porter/api/types"
+)
+
+func TestMapKeys(t *testing.T) {
+	values := map[string]string{
+		"db-password": "hunter2",
+		"API_KEY":     "abc",
+	}
+
+	got, err := MapKeys(values, map[string]string{"db-password": "DATABASE_PASSWORD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"DATABASE_PASSWORD": "hunter2",
+		"API_KEY":           "abc",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := MapKeys(values, map[string]string{"db-password": "API_KEY"}); err == nil {
+		t.Errorf("expected an error when two keys map to the same variable")
+	}
+
+	if _, err := MapKeys(map[string]string{"not valid": "x"}, nil); err == nil {
+		t.Errorf("expected an error for an invalid variable name")
+	}
+}
+
+func TestPreviewKeys(t *testing.T) {
+	got := PreviewKeys(map[string]string{"b": "secret", "a": "secret"}, map[string]string{"b": "B"})
+
+	expected := []types.EnvGroupProviderPreviewKey{
+		{ProviderKey: "a", Variable: "a"},
+		{ProviderKey: "b", Variable: "B"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestSecretValues(t *testing.T) {
+	secret := `{"USER": "admin", "PORT": 5432, "TLS": null}`
+
+	got, err := secretValues("prod/db", &secret, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{"USER": "admin", "PORT": "5432", "TLS": ""}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	got, err = secretValues("prod/api-token", nil, []byte("plain"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected = map[string]string{"api-token": "plain"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestVaultClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"auth": map[string]string{"client_token": "token"},
+			})
+		case "/v1/secret/data/apps/web":
+			if r.Header.Get("X-Vault-Token") != "token" || r.Header.Get("X-Vault-Namespace") != "team" {
+				w.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"data": map[string]interface{}{"PASSWORD": "hunter2"},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &vaultClient{address: server.URL, namespace: "team", httpClient: server.Client()}
+
+	if err := client.loginAppRole(context.Background(), "role", "secret"); err != nil {
+		t.Fatalf("unexpected error logging in: %v", err)
+	}
+
+	got, err := client.readKVv2(context.Background(), "/secret/", "apps/web")
+	if err != nil {
+		t.Fatalf("unexpected error reading secret: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, map[string]string{"PASSWORD": "hunter2"}) {
+		t.Errorf("unexpected values %v", got)
+	}
+
+	if _, err := client.readKVv2(context.Background(), "secret", "missing"); err == nil {
+		t.Errorf("expected an error for a missing secret")
+	}
+
+	client.token = "wrong"
+	if _, err := client.readKVv2(context.Background(), "secret", "apps/web"); err == nil {
+		t.Errorf("expected an error for a forbidden secret")
+	}
+}
+
+func TestValidateVaultAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		valid   bool
+	}{
+		{address: "https://vault.example.com", valid: true},
+		{address: "https://vault.example.com:8200/", valid: true},
+		{address: "https://8.8.8.8:8200", valid: true},
+		{address: "http://vault.example.com"},
+		{address: "vault.example.com"},
+		{address: "https://"},
+		{address: "https://localhost:8200"},
+		{address: "https://vault.localhost"},
+		{address: "https://127.0.0.1:8200"},
+		{address: "https://[::1]:8200"},
+		{address: "https://169.254.169.254"},
+		{address: "https://[fe80::1]"},
+		{address: "https://10.0.0.5:8200"},
+		{address: "https://172.16.0.1"},
+		{address: "https://192.168.1.10"},
+		{address: "https://[fd00::1]"},
+		{address: "https://100.100.100.200"},
+		{address: "https://0.0.0.0"},
+		{address: "https://[::ffff:127.0.0.1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := ValidateVaultAddress(tt.address)
+
+			if tt.valid && err != nil {
+				t.Errorf("expected %s to be valid, got %v", tt.address, err)
+			}
+
+			if !tt.valid && err == nil {
+				t.Errorf("expected %s to be invalid", tt.address)
+			}
+		})
+	}
+}
+
+func TestVaultClientRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]string{"client_token": "token"},
+		})
+	}))
+	defer server.Close()
+
+	// the server listens on a loopback address, which a hostname could resolve to after validation
+	client := &vaultClient{address: server.URL, httpClient: vaultHTTPClient}
+
+	if err := client.loginAppRole(context.Background(), "role", "secret"); err == nil {
+		t.Errorf("expected connecting to a loopback address to fail")
+	}
+}
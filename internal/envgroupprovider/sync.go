@@ -0,0 +1,67 @@
+package envgroupprovider
+
+import (
+	"context"
+	"time"
+
+	"github.com/karagatandev/porter/internal/kubernetes"
+	"github.com/karagatandev/porter/internal/kubernetes/environment_groups"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// Sync resolves a provider's secret and publishes it as a new version of the provider's environment group, if it changed.
+// The time and error of the sync are recorded on the provider, which the caller is responsible for saving.
+func Sync(ctx context.Context, agent *kubernetes.Agent, repo repository.Repository, provider *models.EnvGroupProvider, author string) (environment_groups.SyncExternalBaseEnvironmentGroupOutput, error) {
+	ctx, span := telemetry.NewSpan(ctx, "sync-env-group-provider")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: provider.EnvGroupName},
+		telemetry.AttributeKV{Key: "provider-type", Value: string(provider.Type)},
+	)
+
+	output, err := sync(ctx, agent, repo, provider, author)
+
+	now := time.Now().UTC()
+	provider.LastSyncedAt = &now
+	provider.LastSyncError = ""
+
+	if err != nil {
+		provider.LastSyncError = err.Error()
+		return output, telemetry.Error(ctx, span, err, "unable to sync env group provider")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "changed", Value: output.Changed},
+		telemetry.AttributeKV{Key: "version", Value: output.Version},
+	)
+
+	return output, nil
+}
+
+func sync(ctx context.Context, agent *kubernetes.Agent, repo repository.Repository, provider *models.EnvGroupProvider, author string) (environment_groups.SyncExternalBaseEnvironmentGroupOutput, error) {
+	var output environment_groups.SyncExternalBaseEnvironmentGroupOutput
+
+	values, err := Resolve(ctx, repo, provider)
+	if err != nil {
+		return output, err
+	}
+
+	mappings, err := provider.Mappings()
+	if err != nil {
+		return output, err
+	}
+
+	variables, err := MapKeys(values, mappings)
+	if err != nil {
+		return output, err
+	}
+
+	return environment_groups.SyncExternalBaseEnvironmentGroup(ctx, agent, environment_groups.SyncExternalBaseEnvironmentGroupInput{
+		Name:            provider.EnvGroupName,
+		Type:            string(provider.Type),
+		SecretVariables: variables,
+		Author:          author,
+	})
+}
@@ -0,0 +1,178 @@
+package envgroupprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/publicnet"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// vaultHTTPClient is the client used to call Vault servers. It refuses to connect to
+// internal addresses, which an address resolving to one at connection time would otherwise reach.
+var vaultHTTPClient = publicnet.NewHTTPClient(15 * time.Second)
+
+// ValidateVaultAddress checks that the address of a Vault server is an absolute https URL
+// whose host is not a loopback, private or link-local address
+func ValidateVaultAddress(address string) error {
+	u, err := url.Parse(address)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("invalid vault address %s: expected to be in the form https://HOST[:PORT]", address)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("invalid vault address %s: the host must be a public address", address)
+	}
+
+	if ip := net.ParseIP(host); ip != nil && publicnet.IsInternalIP(ip) {
+		return fmt.Errorf("invalid vault address %s: the host must be a public address", address)
+	}
+
+	return nil
+}
+
+type vaultClient struct {
+	address   string
+	namespace string
+	token     string
+
+	httpClient *http.Client
+}
+
+type vaultErrorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+type vaultAppRoleLoginRequest struct {
+	RoleID   string `json:"role_id"`
+	SecretID string `json:"secret_id"`
+}
+
+type vaultAppRoleLoginResponse struct {
+	Auth struct {
+		ClientToken string `json:"client_token"`
+	} `json:"auth"`
+}
+
+type vaultKVv2ReadResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+func resolveVault(ctx context.Context, repo repository.Repository, projectID uint, conf *types.VaultEnvGroupProviderConfig) (map[string]string, error) {
+	if err := ValidateVaultAddress(conf.Address); err != nil {
+		return nil, err
+	}
+
+	basic, err := repo.BasicIntegration().ReadBasicIntegration(projectID, conf.BasicIntegrationID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading vault credentials: %w", err)
+	}
+
+	client := &vaultClient{
+		address:    strings.TrimSuffix(conf.Address, "/"),
+		namespace:  conf.Namespace,
+		httpClient: vaultHTTPClient,
+	}
+
+	switch conf.AuthMethod {
+	case types.VaultAuthMethodToken:
+		client.token = string(basic.Password)
+	case types.VaultAuthMethodAppRole:
+		if err := client.loginAppRole(ctx, string(basic.Username), string(basic.Password)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported vault auth method %s", conf.AuthMethod)
+	}
+
+	return client.readKVv2(ctx, conf.MountPath, conf.Path)
+}
+
+func (c *vaultClient) loginAppRole(ctx context.Context, roleID, secretID string) error {
+	res := &vaultAppRoleLoginResponse{}
+
+	if err := c.do(ctx, http.MethodPost, "auth/approle/login", &vaultAppRoleLoginRequest{RoleID: roleID, SecretID: secretID}, res); err != nil {
+		return fmt.Errorf("error logging in to vault with approle: %w", err)
+	}
+
+	if res.Auth.ClientToken == "" {
+		return fmt.Errorf("error logging in to vault with approle: no token was returned")
+	}
+
+	c.token = res.Auth.ClientToken
+
+	return nil
+}
+
+func (c *vaultClient) readKVv2(ctx context.Context, mountPath, secretPath string) (map[string]string, error) {
+	res := &vaultKVv2ReadResponse{}
+
+	path := fmt.Sprintf("%s/data/%s", strings.Trim(mountPath, "/"), strings.Trim(secretPath, "/"))
+
+	if err := c.do(ctx, http.MethodGet, path, nil, res); err != nil {
+		return nil, fmt.Errorf("error reading vault secret %s: %w", path, err)
+	}
+
+	return flatten(res.Data.Data)
+}
+
+func (c *vaultClient) do(ctx context.Context, method, path string, body interface{}, res interface{}) error {
+	var reqBody io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", c.address, path), reqBody)
+	if err != nil {
+		return err
+	}
+
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	if resp.StatusCode >= 300 {
+		errResp := &vaultErrorResponse{}
+		_ = json.NewDecoder(resp.Body).Decode(errResp)
+
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("not found")
+		}
+
+		if len(errResp.Errors) > 0 {
+			return fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.Join(errResp.Errors, ", "))
+		}
+
+		return fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(res)
+}
@@ -261,6 +261,45 @@ func (a *Agent) DeleteReleaseRevision(
 	return err
 }
 
+// RestoreReleaseRevision writes an archived revision of a release back to the cluster as a
+// superseded revision, so that the release can be rolled back to it. The objects of the
+// revision are not deployed.
+func (a *Agent) RestoreReleaseRevision(
+	ctx context.Context,
+	rel *release.Release,
+) error {
+	ctx, span := telemetry.NewSpan(ctx, "helm-restore-release-revision")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "name", Value: rel.Name},
+		telemetry.AttributeKV{Key: "version", Value: rel.Version},
+	)
+
+	if rel.Namespace != a.namespace {
+		return telemetry.Error(ctx, span, nil, fmt.Sprintf("revision belongs to namespace %s, not %s", rel.Namespace, a.namespace))
+	}
+
+	if _, err := a.ActionConfig.Releases.Get(rel.Name, rel.Version); err == nil {
+		return telemetry.Error(ctx, span, nil, fmt.Sprintf("revision %d of release %s already exists", rel.Version, rel.Name))
+	} else if !errors.Is(err, driver.ErrReleaseNotFound) {
+		return telemetry.Error(ctx, span, err, "error checking for existing revision")
+	}
+
+	if rel.Info == nil {
+		rel.Info = &release.Info{}
+	}
+
+	rel.Info.Status = release.StatusSuperseded
+	rel.Info.Description = "Restored from archive"
+
+	if err := a.ActionConfig.Releases.Create(rel); err != nil {
+		return telemetry.Error(ctx, span, err, "error restoring revision")
+	}
+
+	return nil
+}
+
 // GetReleaseHistory returns a list of charts for a specific release
 func (a *Agent) GetReleaseHistory(
 	ctx context.Context,
@@ -357,6 +396,11 @@ func (a *Agent) UpgradeReleaseByValues(
 	cmd := action.NewUpgrade(a.ActionConfig)
 	cmd.Namespace = rel.Namespace
 
+	patches, err := ListReleaseManifestPatches(ctx, conf.Repo, conf.Cluster.ProjectID, conf.Cluster.ID, conf.Name, ch.Metadata.Name)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing manifest patches")
+	}
+
 	cmd.PostRenderer, err = NewPorterPostrenderer(
 		conf.Cluster,
 		conf.Repo,
@@ -365,6 +409,7 @@ func (a *Agent) UpgradeReleaseByValues(
 		conf.Registries,
 		doAuth,
 		disablePullSecretsInjection,
+		patches,
 	)
 	if err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error getting porter postrenderer")
@@ -473,6 +518,12 @@ func (a *Agent) UpgradeReleaseByValues(
 
 				versionMapper := &DeprecatedAPIVersionMapper{}
 
+				// objects which were removed from the chart are migrated as well, when the
+				// cluster's APIs can be discovered
+				if served, err := a.K8sAgent.GetServedAPIs(); err == nil {
+					versionMapper.Served = served
+				}
+
 				updatedManifestBuffer, err := versionMapper.Run(oldManifestBuffer, newManifestBuffer)
 				if err != nil {
 					return nil, telemetry.Error(ctx, span, err, "error running version mapper")
@@ -502,6 +553,68 @@ func (a *Agent) UpgradeReleaseByValues(
 	return res, nil
 }
 
+// RenderReleaseWithPatches renders the latest revision of a release with its chart and values,
+// applying the given manifest patches after Porter's own post-renderers, and returns the
+// rendered manifest. Nothing is deployed, and image pull secrets are not injected since
+// they would be created in the cluster.
+func (a *Agent) RenderReleaseWithPatches(
+	ctx context.Context,
+	conf *UpgradeReleaseConfig,
+	patches []*models.ManifestPatch,
+) (string, error) {
+	ctx, span := telemetry.NewSpan(ctx, "helm-render-release-with-patches")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: conf.Cluster.ProjectID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: conf.Cluster.ID},
+		telemetry.AttributeKV{Key: "name", Value: conf.Name},
+		telemetry.AttributeKV{Key: "patches", Value: len(patches)},
+	)
+
+	rel, err := a.GetRelease(ctx, conf.Name, 0, false)
+	if err != nil {
+		return "", telemetry.Error(ctx, span, err, "could not get release to be rendered")
+	}
+
+	ch := rel.Chart
+
+	if conf.Chart != nil {
+		ch = conf.Chart
+	}
+
+	values := rel.Config
+
+	if conf.Values != nil {
+		values = conf.Values
+	}
+
+	cmd := action.NewUpgrade(a.ActionConfig)
+	cmd.Namespace = rel.Namespace
+	cmd.DryRun = true
+
+	cmd.PostRenderer, err = NewPorterPostrenderer(
+		conf.Cluster,
+		conf.Repo,
+		a.K8sAgent,
+		rel.Namespace,
+		nil,
+		nil,
+		true,
+		patches,
+	)
+	if err != nil {
+		return "", telemetry.Error(ctx, span, err, "error getting porter postrenderer")
+	}
+
+	res, err := cmd.RunWithContext(ctx, conf.Name, ch, values)
+	if err != nil {
+		return "", telemetry.Error(ctx, span, err, "error rendering release")
+	}
+
+	return res.Manifest, nil
+}
+
 // InstallChartConfig is the config required to install a chart
 type InstallChartConfig struct {
 	Chart      *chart.Chart
@@ -578,7 +691,10 @@ func (a *Agent) InstallChart(
 		return nil, telemetry.Error(ctx, span, err, "error checking if installable")
 	}
 
-	var err error
+	patches, err := ListReleaseManifestPatches(ctx, conf.Repo, conf.Cluster.ProjectID, conf.Cluster.ID, conf.Name, conf.Chart.Metadata.Name)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing manifest patches")
+	}
 
 	cmd.PostRenderer, err = NewPorterPostrenderer(
 		conf.Cluster,
@@ -588,6 +704,7 @@ func (a *Agent) InstallChart(
 		conf.Registries,
 		doAuth,
 		disablePullSecretsInjection,
+		patches,
 	)
 	if err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error getting post renderer")
@@ -643,7 +760,10 @@ func (a *Agent) UpgradeInstallChart(
 		return nil, telemetry.Error(ctx, span, err, "error checking if installable")
 	}
 
-	var err error
+	patches, err := ListReleaseManifestPatches(ctx, conf.Repo, conf.Cluster.ProjectID, conf.Cluster.ID, conf.Name, conf.Chart.Metadata.Name)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing manifest patches")
+	}
 
 	cmd.PostRenderer, err = NewPorterPostrenderer(
 		conf.Cluster,
@@ -653,6 +773,7 @@ func (a *Agent) UpgradeInstallChart(
 		conf.Registries,
 		doAuth,
 		disablePullSecretsInjection,
+		patches,
 	)
 	if err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error getting post renderer")
@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/karagatandev/porter/internal/encryption"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// ErrNotFound is returned by a store when no object exists for a key
+var ErrNotFound = errors.New("archived object not found")
+
+// Store is a backend that pruned Helm release revisions are archived to. Keys are slash
+// separated paths.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// List returns the keys which start with prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// encryptedStore encrypts the objects of a store at rest
+type encryptedStore struct {
+	Store
+
+	key *[32]byte
+}
+
+// NewEncryptedStore wraps a store so that objects are encrypted before they are written
+// and decrypted when they are read
+func NewEncryptedStore(store Store, key *[32]byte) Store {
+	return &encryptedStore{store, key}
+}
+
+func (s *encryptedStore) Put(ctx context.Context, key string, data []byte) error {
+	encrypted, err := encryption.Encrypt(data, s.key)
+	if err != nil {
+		return err
+	}
+
+	return s.Store.Put(ctx, key, encrypted)
+}
+
+func (s *encryptedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.Store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption.Decrypt(data, s.key)
+}
+
+// releasePrefix is the prefix of the keys of the archived revisions of a release. Revisions
+// are stored at <project_id>/<cluster_id>/<namespace>/<release_name>/<revision>.
+func releasePrefix(projectID, clusterID uint, namespace, name string) string {
+	return fmt.Sprintf("%d/%d/%s/%s/", projectID, clusterID, namespace, name)
+}
+
+// RevisionKey returns the key a revision of a release is archived at
+func RevisionKey(projectID, clusterID uint, namespace, name string, version int) string {
+	return releasePrefix(projectID, clusterID, namespace, name) + strconv.Itoa(version)
+}
+
+// ArchiveRevision writes a revision of a release to the store
+func ArchiveRevision(ctx context.Context, store Store, projectID, clusterID uint, rel *release.Release) error {
+	data, err := json.Marshal(rel)
+	if err != nil {
+		return fmt.Errorf("error marshalling revision %d of release %s: %w", rel.Version, rel.Name, err)
+	}
+
+	return store.Put(ctx, RevisionKey(projectID, clusterID, rel.Namespace, rel.Name, rel.Version), data)
+}
+
+// GetRevision reads an archived revision of a release from the store
+func GetRevision(ctx context.Context, store Store, projectID, clusterID uint, namespace, name string, version int) (*release.Release, error) {
+	data, err := store.Get(ctx, RevisionKey(projectID, clusterID, namespace, name, version))
+	if err != nil {
+		return nil, err
+	}
+
+	rel := &release.Release{}
+
+	if err := json.Unmarshal(data, rel); err != nil {
+		return nil, fmt.Errorf("error unmarshalling revision %d of release %s: %w", version, name, err)
+	}
+
+	if rel.Name != name || rel.Namespace != namespace || rel.Version != version {
+		return nil, fmt.Errorf("archived object at %s is not revision %d of release %s",
+			RevisionKey(projectID, clusterID, namespace, name, version), version, name)
+	}
+
+	return rel, nil
+}
+
+// ListRevisions returns the archived revision numbers of a release, from the newest
+func ListRevisions(ctx context.Context, store Store, projectID, clusterID uint, namespace, name string) ([]int, error) {
+	prefix := releasePrefix(projectID, clusterID, namespace, name)
+
+	keys, err := store.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]int, 0, len(keys))
+
+	for _, key := range keys {
+		version, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+		if err != nil {
+			// skip objects which aren't revisions
+			continue
+		}
+
+		res = append(res, version)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(res)))
+
+	return res, nil
+}
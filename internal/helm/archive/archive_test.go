@@ -0,0 +1,109 @@
+package archive_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/karagatandev/porter/api/server/shared/config/env"
+	"github.com/karagatandev/porter/internal/helm/archive"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+func newRevision(name string, version int) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: "default",
+		Version:   version,
+		Info:      &release.Info{Status: release.StatusSuperseded},
+		Config:    map[string]interface{}{"replicas": float64(version)},
+	}
+}
+
+func TestArchiveAndRestoreRevisions(t *testing.T) {
+	ctx := context.Background()
+
+	var key [32]byte
+	copy(key[:], "__random_strong_encryption_key__")
+
+	local := archive.NewLocalStore(t.TempDir())
+	store := archive.NewEncryptedStore(local, &key)
+
+	// "web-worker" shares a prefix with "web" and must not be listed with its revisions
+	for _, rev := range []*release.Release{newRevision("web", 1), newRevision("web", 3), newRevision("web", 2), newRevision("web-worker", 1)} {
+		if err := archive.ArchiveRevision(ctx, store, 1, 2, rev); err != nil {
+			t.Fatalf("unexpected error archiving revision: %v", err)
+		}
+	}
+
+	revisions, err := archive.ListRevisions(ctx, store, 1, 2, "default", "web")
+	if err != nil {
+		t.Fatalf("unexpected error listing revisions: %v", err)
+	}
+
+	if !reflect.DeepEqual(revisions, []int{3, 2, 1}) {
+		t.Errorf("expected revisions [3 2 1], got %v", revisions)
+	}
+
+	rel, err := archive.GetRevision(ctx, store, 1, 2, "default", "web", 2)
+	if err != nil {
+		t.Fatalf("unexpected error getting revision: %v", err)
+	}
+
+	if rel.Version != 2 || rel.Config["replicas"] != float64(2) {
+		t.Errorf("unexpected revision: %+v", rel)
+	}
+
+	// revisions are encrypted at rest
+	data, err := local.Get(ctx, archive.RevisionKey(1, 2, "default", "web", 2))
+	if err != nil {
+		t.Fatalf("unexpected error reading raw revision: %v", err)
+	}
+
+	if bytes.Contains(data, []byte("replicas")) {
+		t.Errorf("expected the archived revision to be encrypted")
+	}
+
+	if _, err := archive.GetRevision(ctx, store, 1, 2, "default", "web", 4); !errors.Is(err, archive.ErrNotFound) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	if revisions, err := archive.ListRevisions(ctx, store, 1, 3, "default", "web"); err != nil || len(revisions) != 0 {
+		t.Errorf("expected no revisions for another cluster, got %v, %v", revisions, err)
+	}
+}
+
+func TestNewStoreRequiresEncryptionKey(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := archive.NewStore(ctx, &env.HelmArchiveConf{Backend: "local", Dir: t.TempDir()}); err == nil {
+		t.Fatal("expected an error creating a store without an encryption key")
+	}
+
+	store, err := archive.NewStore(ctx, &env.HelmArchiveConf{})
+	if err != nil || store != nil {
+		t.Fatalf("expected no store when archival is disabled, got %v, %v", store, err)
+	}
+
+	dir := t.TempDir()
+
+	store, err = archive.NewStore(ctx, &env.HelmArchiveConf{Backend: "local", Dir: dir, EncryptionKey: "__random_strong_encryption_key__"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := archive.ArchiveRevision(ctx, store, 1, 2, newRevision("web", 1)); err != nil {
+		t.Fatalf("unexpected error archiving revision: %v", err)
+	}
+
+	// the revision must not be readable without the key
+	if _, err := archive.GetRevision(ctx, archive.NewLocalStore(dir), 1, 2, "default", "web", 1); err == nil {
+		t.Error("expected the archived revision to be encrypted")
+	}
+
+	if _, err := archive.GetRevision(ctx, store, 1, 2, "default", "web", 1); err != nil {
+		t.Errorf("unexpected error restoring revision: %v", err)
+	}
+}
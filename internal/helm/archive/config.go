@@ -0,0 +1,61 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/karagatandev/porter/api/server/shared/config/env"
+)
+
+// NewStore returns the store configured by conf, or nil if archival is disabled. Archived revisions
+// contain the values of releases, so they are always encrypted.
+func NewStore(ctx context.Context, conf *env.HelmArchiveConf) (Store, error) {
+	if (conf.Backend == "s3" || conf.Backend == "gcs") && conf.Bucket == "" {
+		return nil, fmt.Errorf("a bucket is required for the %s helm archive backend", conf.Backend)
+	}
+
+	if conf.Backend != "" && conf.EncryptionKey == "" {
+		return nil, fmt.Errorf("an encryption key is required for the %s helm archive backend", conf.Backend)
+	}
+
+	var store Store
+
+	switch conf.Backend {
+	case "":
+		return nil, nil
+	case "s3":
+		s3Store, err := NewS3Store(&S3Options{
+			AWSRegion:          conf.AWSRegion,
+			AWSAccessKeyID:     conf.AWSAccessKeyID,
+			AWSSecretAccessKey: conf.AWSSecretAccessKey,
+			BucketName:         conf.Bucket,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		store = s3Store
+	case "gcs":
+		gcsStore, err := NewGCSStore(ctx, &GCSOptions{
+			CredentialsJSON: []byte(conf.GCPCredentials),
+			BucketName:      conf.Bucket,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		store = gcsStore
+	case "local":
+		store = NewLocalStore(conf.Dir)
+	default:
+		return nil, fmt.Errorf("unknown helm archive backend %q, must be one of \"s3\", \"gcs\" or \"local\"", conf.Backend)
+	}
+
+	var key [32]byte
+
+	for i, b := range []byte(conf.EncryptionKey) {
+		key[i] = b
+	}
+
+	return NewEncryptedStore(store, &key), nil
+}
@@ -0,0 +1,84 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// GCSStore archives objects in a Google Cloud Storage bucket
+type GCSStore struct {
+	svc    *storage.Service
+	bucket string
+}
+
+type GCSOptions struct {
+	// CredentialsJSON is a service account key. Application default credentials are used
+	// if it is empty.
+	CredentialsJSON []byte
+	BucketName      string
+}
+
+func NewGCSStore(ctx context.Context, opts *GCSOptions) (*GCSStore, error) {
+	clientOpts := []option.ClientOption{option.WithScopes(storage.DevstorageReadWriteScope)}
+
+	if len(opts.CredentialsJSON) > 0 {
+		clientOpts = append(clientOpts, option.WithCredentialsJSON(opts.CredentialsJSON))
+	}
+
+	svc, err := storage.NewService(ctx, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCSStore{
+		svc:    svc,
+		bucket: opts.BucketName,
+	}, nil
+}
+
+func (s *GCSStore) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.svc.Objects.Insert(s.bucket, &storage.Object{Name: key}).
+		Media(bytes.NewReader(data)).
+		Context(ctx).
+		Do()
+
+	return err
+}
+
+func (s *GCSStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.svc.Objects.Get(s.bucket, key).Context(ctx).Download()
+	if err != nil {
+		var apiErr *googleapi.Error
+
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+func (s *GCSStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var res []string
+
+	err := s.svc.Objects.List(s.bucket).Prefix(prefix).Pages(ctx, func(objects *storage.Objects) error {
+		for _, obj := range objects.Items {
+			res = append(res, obj.Name)
+		}
+
+		return nil
+	})
+
+	return res, err
+}
@@ -0,0 +1,96 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3Store archives objects in an S3 bucket
+type S3Store struct {
+	client *s3.S3
+	bucket string
+}
+
+type S3Options struct {
+	AWSRegion          string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	BucketName         string
+}
+
+func NewS3Store(opts *S3Options) (*S3Store, error) {
+	awsConf := &aws.Config{
+		Region: &opts.AWSRegion,
+	}
+
+	// fall back to the default credential chain, such as an instance role, when no keys are set
+	if opts.AWSAccessKeyID != "" {
+		awsConf.Credentials = credentials.NewStaticCredentials(opts.AWSAccessKeyID, opts.AWSSecretAccessKey, "")
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            *awsConf,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create AWS session: %w", err)
+	}
+
+	return &S3Store{
+		client: s3.New(sess),
+		bucket: opts.BucketName,
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Body:   aws.ReadSeekCloser(bytes.NewReader(data)),
+		Bucket: &s.bucket,
+		Key:    aws.String(key),
+	})
+
+	return err
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	defer output.Body.Close()
+
+	return io.ReadAll(output.Body)
+}
+
+func (s *S3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	var res []string
+
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: &s.bucket,
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			res = append(res, aws.StringValue(obj.Key))
+		}
+
+		return true
+	})
+
+	return res, err
+}
@@ -0,0 +1,268 @@
+package deprecation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Version is a minor Kubernetes version
+type Version struct {
+	Major int
+	Minor int
+}
+
+// ParseVersion parses versions as reported by the discovery API, such as 1 and 27+ for
+// the major and minor versions of a GKE cluster
+func ParseVersion(major, minor string) (Version, error) {
+	majorVal, err := strconv.Atoi(strings.TrimSuffix(major, "+"))
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid major version %s: %w", major, err)
+	}
+
+	minorVal, err := strconv.Atoi(strings.TrimSuffix(minor, "+"))
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid minor version %s: %w", minor, err)
+	}
+
+	return Version{majorVal, minorVal}, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// AtLeast returns true if v is the same as or later than other
+func (v Version) AtLeast(other Version) bool {
+	return v.Major > other.Major || (v.Major == other.Major && v.Minor >= other.Minor)
+}
+
+// ServedAPIs are the API versions and kinds served by a cluster, as reported by discovery
+type ServedAPIs struct {
+	Version Version
+
+	kinds map[string]map[string]bool
+}
+
+// NewServedAPIs returns the APIs of a cluster from a map of API versions to the kinds they serve
+func NewServedAPIs(version Version, kinds map[string][]string) *ServedAPIs {
+	res := &ServedAPIs{
+		Version: version,
+		kinds:   make(map[string]map[string]bool),
+	}
+
+	for apiVersion, apiKinds := range kinds {
+		res.kinds[apiVersion] = make(map[string]bool)
+
+		for _, kind := range apiKinds {
+			res.kinds[apiVersion][kind] = true
+		}
+	}
+
+	return res
+}
+
+// Serves returns true if the cluster serves a kind at an API version
+func (s *ServedAPIs) Serves(apiVersion, kind string) bool {
+	return s.kinds[apiVersion][kind]
+}
+
+// Issue is an object of a manifest which uses an API version removed from the cluster
+type Issue struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+
+	API *DeprecatedAPI
+
+	// Replacement is the API version which the object must be migrated to, or was rewritten
+	// to when Rewritten is true. It is empty when the cluster serves no replacement.
+	Replacement string
+	Rewritten   bool
+}
+
+func (i Issue) String() string {
+	obj := fmt.Sprintf("%s %s", i.Kind, i.Name)
+
+	if i.Namespace != "" {
+		obj = fmt.Sprintf("%s %s/%s", i.Kind, i.Namespace, i.Name)
+	}
+
+	switch {
+	case i.Rewritten:
+		return fmt.Sprintf("%s was migrated from %s to %s", obj, i.APIVersion, i.Replacement)
+	case len(i.API.Replacements) == 0:
+		return fmt.Sprintf("%s uses %s, which was removed in Kubernetes %s: %s", obj, i.APIVersion, i.API.RemovedIn, i.API.Note)
+	case i.Replacement == "":
+		return fmt.Sprintf("%s uses %s, which was removed in Kubernetes %s, and the cluster serves none of its replacements (%s)",
+			obj, i.APIVersion, i.API.RemovedIn, strings.Join(i.API.Replacements, ", "))
+	}
+
+	msg := fmt.Sprintf("%s uses %s, which was removed in Kubernetes %s. Update the chart to use %s",
+		obj, i.APIVersion, i.API.RemovedIn, i.Replacement)
+
+	if i.API.Note != "" {
+		msg += ": " + i.API.Note
+	}
+
+	return msg
+}
+
+// RemovedAPIsError is returned when a manifest contains objects whose API versions are not
+// served by the cluster and cannot be migrated automatically
+type RemovedAPIsError struct {
+	Version Version
+	Issues  []Issue
+}
+
+func (e *RemovedAPIsError) Error() string {
+	msgs := make([]string, 0, len(e.Issues))
+
+	for _, issue := range e.Issues {
+		msgs = append(msgs, issue.String())
+	}
+
+	return fmt.Sprintf("the manifest uses APIs which are not served by Kubernetes %s: %s", e.Version, strings.Join(msgs, "; "))
+}
+
+var apiVersionLine = regexp.MustCompile(`(?m)^apiVersion:[ \t]*["']?([^"'\s]+)["']?[ \t]*$`)
+
+// docSeparator splits a manifest into its YAML documents
+var docSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+type objectIdentity struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+}
+
+// Check finds the objects of a manifest whose API versions are in the table and are not served
+// by the cluster. Objects which are rewritable are migrated to the first replacement served by
+// the cluster, and the rewritten manifest is returned along with every issue found. When
+// objects cannot be rewritten, a *RemovedAPIsError listing them is returned.
+//
+// Objects whose API versions are not in the table, such as custom resources installed by the
+// same chart, are not checked.
+func (t Table) Check(manifest string, served *ServedAPIs) (string, []Issue, error) {
+	docs := splitDocs(manifest)
+	issues := make([]Issue, 0)
+	failed := make([]Issue, 0)
+
+	for i, doc := range docs {
+		obj := &objectIdentity{}
+
+		// documents which aren't objects are left for Helm to report
+		if err := yaml.Unmarshal([]byte(doc), obj); err != nil || obj.APIVersion == "" || obj.Kind == "" {
+			continue
+		}
+
+		if served.Serves(obj.APIVersion, obj.Kind) {
+			continue
+		}
+
+		api, ok := t.Lookup(obj.APIVersion, obj.Kind)
+
+		if !ok {
+			continue
+		}
+
+		issue := Issue{
+			APIVersion: obj.APIVersion,
+			Kind:       obj.Kind,
+			Name:       obj.Metadata.Name,
+			Namespace:  obj.Metadata.Namespace,
+			API:        api,
+		}
+
+		for _, replacement := range api.Replacements {
+			if served.Serves(replacement, obj.Kind) {
+				issue.Replacement = replacement
+				break
+			}
+		}
+
+		if api.Rewritable && issue.Replacement != "" {
+			docs[i] = rewriteAPIVersion(doc, issue.Replacement)
+			issue.Rewritten = true
+		} else {
+			failed = append(failed, issue)
+		}
+
+		issues = append(issues, issue)
+	}
+
+	if len(failed) > 0 {
+		return manifest, issues, &RemovedAPIsError{
+			Version: served.Version,
+			Issues:  failed,
+		}
+	}
+
+	return strings.Join(docs, ""), issues, nil
+}
+
+// Rewrite migrates every object of a manifest whose API version is in the table and is not
+// served by the cluster to the first replacement served by the cluster, whether or not the
+// object is rewritable. This is only meant for the manifests of deployed revisions, which
+// Helm compares with the new manifest but does not apply, so that releases whose previous
+// revisions use removed APIs can still be upgraded.
+func (t Table) Rewrite(manifest string, served *ServedAPIs) string {
+	docs := splitDocs(manifest)
+
+	for i, doc := range docs {
+		obj := &objectIdentity{}
+
+		if err := yaml.Unmarshal([]byte(doc), obj); err != nil || served.Serves(obj.APIVersion, obj.Kind) {
+			continue
+		}
+
+		api, ok := t.Lookup(obj.APIVersion, obj.Kind)
+
+		if !ok {
+			continue
+		}
+
+		for _, replacement := range api.Replacements {
+			if served.Serves(replacement, obj.Kind) {
+				docs[i] = rewriteAPIVersion(doc, replacement)
+				break
+			}
+		}
+	}
+
+	return strings.Join(docs, "")
+}
+
+// splitDocs splits a manifest into its documents, keeping the separators so that joining
+// the documents returns the original manifest
+func splitDocs(manifest string) []string {
+	locs := docSeparator.FindAllStringIndex(manifest, -1)
+	docs := make([]string, 0, len(locs)+1)
+	start := 0
+
+	for _, loc := range locs {
+		docs = append(docs, manifest[start:loc[0]])
+		start = loc[0]
+	}
+
+	return append(docs, manifest[start:])
+}
+
+// rewriteAPIVersion replaces the top-level apiVersion of a document, leaving the rest of the
+// document, including its comments, untouched
+func rewriteAPIVersion(doc, apiVersion string) string {
+	loc := apiVersionLine.FindStringIndex(doc)
+
+	if loc == nil {
+		return doc
+	}
+
+	return doc[:loc[0]] + "apiVersion: " + apiVersion + doc[loc[1]:]
+}
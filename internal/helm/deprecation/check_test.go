@@ -0,0 +1,201 @@
+package deprecation_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/karagatandev/porter/internal/helm/deprecation"
+)
+
+const manifest = `---
+# Source: web/templates/cronjob.yaml
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  schedule: "0 * * * *"
+---
+# Source: web/templates/ingress.yaml
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+  namespace: default
+spec:
+  backend:
+    serviceName: web
+    servicePort: 80
+---
+# Source: web/templates/pdb.yaml
+apiVersion: "policy/v1beta1"
+kind: PodDisruptionBudget
+metadata:
+  name: web
+---
+# Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+# Source: web/templates/widget.yaml
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: web
+`
+
+func servedAPIs(minor int) *deprecation.ServedAPIs {
+	return deprecation.NewServedAPIs(deprecation.Version{Major: 1, Minor: minor}, map[string][]string{
+		"v1":                   {"Service", "ConfigMap"},
+		"batch/v1":             {"Job", "CronJob"},
+		"networking.k8s.io/v1": {"Ingress", "IngressClass", "NetworkPolicy"},
+		"policy/v1":            {"PodDisruptionBudget"},
+	})
+}
+
+func TestParseVersion(t *testing.T) {
+	version, err := deprecation.ParseVersion("1", "27+")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if version != (deprecation.Version{Major: 1, Minor: 27}) {
+		t.Errorf("expected version 1.27, got %s", version)
+	}
+
+	if !version.AtLeast(deprecation.Version{Major: 1, Minor: 25}) || version.AtLeast(deprecation.Version{Major: 1, Minor: 28}) {
+		t.Errorf("unexpected version comparison for %s", version)
+	}
+
+	if _, err := deprecation.ParseVersion("1", ""); err == nil {
+		t.Errorf("expected an error for an empty minor version")
+	}
+}
+
+func TestCheckReportsObjectsWhichCannotBeRewritten(t *testing.T) {
+	_, issues, err := deprecation.DefaultTable.Check(manifest, servedAPIs(25))
+
+	removedErr := &deprecation.RemovedAPIsError{}
+
+	if !errors.As(err, &removedErr) {
+		t.Fatalf("expected a removed APIs error, got %v", err)
+	}
+
+	if len(removedErr.Issues) != 1 || removedErr.Issues[0].Kind != "Ingress" || removedErr.Issues[0].Replacement != "networking.k8s.io/v1" {
+		t.Fatalf("expected the ingress to be reported, got %+v", removedErr.Issues)
+	}
+
+	if !strings.Contains(err.Error(), "Ingress default/web uses networking.k8s.io/v1beta1, which was removed in Kubernetes 1.22") {
+		t.Errorf("unexpected error message: %s", err.Error())
+	}
+
+	if len(issues) != 3 {
+		t.Errorf("expected 3 issues, got %d", len(issues))
+	}
+}
+
+func TestCheckRewritesObjects(t *testing.T) {
+	// the ingress is served by the cluster, so only the cronjob and pdb are migrated
+	served := deprecation.NewServedAPIs(deprecation.Version{Major: 1, Minor: 21}, map[string][]string{
+		"v1":                        {"Service"},
+		"batch/v1":                  {"CronJob"},
+		"networking.k8s.io/v1beta1": {"Ingress"},
+		"policy/v1":                 {"PodDisruptionBudget"},
+	})
+
+	res, issues, err := deprecation.DefaultTable.Check(manifest, served)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+
+	for _, issue := range issues {
+		if !issue.Rewritten {
+			t.Errorf("expected %s %s to be rewritten", issue.Kind, issue.Name)
+		}
+	}
+
+	expected := strings.Replace(manifest, "apiVersion: batch/v1beta1\n", "apiVersion: batch/v1\n", 1)
+	expected = strings.Replace(expected, "apiVersion: \"policy/v1beta1\"\n", "apiVersion: policy/v1\n", 1)
+
+	if res != expected {
+		t.Errorf("unexpected manifest:\n%s", res)
+	}
+}
+
+func TestCheckWithoutReplacement(t *testing.T) {
+	psp := "apiVersion: policy/v1beta1\nkind: PodSecurityPolicy\nmetadata:\n  name: restricted\n"
+
+	_, _, err := deprecation.DefaultTable.Check(psp, servedAPIs(25))
+	if err == nil || !strings.Contains(err.Error(), "PodSecurityPolicy has no replacement") {
+		t.Errorf("expected the pod security policy to be reported, got %v", err)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	// objects are migrated even when their schema changed
+	res := deprecation.DefaultTable.Rewrite(manifest, servedAPIs(25))
+
+	for _, apiVersion := range []string{"apiVersion: batch/v1\n", "apiVersion: networking.k8s.io/v1\n", "apiVersion: policy/v1\n", "apiVersion: example.com/v1\n"} {
+		if !strings.Contains(res, apiVersion) {
+			t.Errorf("expected %q in the rewritten manifest:\n%s", apiVersion, res)
+		}
+	}
+
+	if !strings.Contains(res, "# Source: web/templates/ingress.yaml\n") {
+		t.Errorf("expected comments to be kept:\n%s", res)
+	}
+}
+
+func TestTableExtend(t *testing.T) {
+	table := deprecation.DefaultTable.Extend(
+		deprecation.DeprecatedAPI{
+			APIVersion:   "example.com/v1",
+			Kind:         "Widget",
+			RemovedIn:    deprecation.Version{Major: 1, Minor: 30},
+			Replacements: []string{"example.com/v2"},
+			Rewritable:   true,
+		},
+		deprecation.DeprecatedAPI{
+			APIVersion:   "batch/v1beta1",
+			Kind:         "CronJob",
+			RemovedIn:    deprecation.Version{Major: 1, Minor: 25},
+			Replacements: []string{"batch/v1"},
+			Note:         "check the schedule",
+		},
+	)
+
+	if len(table) != len(deprecation.DefaultTable)+1 {
+		t.Errorf("expected one entry to be added, got %d entries", len(table))
+	}
+
+	if api, ok := table.Lookup("batch/v1beta1", "CronJob"); !ok || api.Rewritable {
+		t.Errorf("expected the cronjob entry to be replaced, got %+v", api)
+	}
+
+	if api, _ := deprecation.DefaultTable.Lookup("batch/v1beta1", "CronJob"); !api.Rewritable {
+		t.Errorf("expected the default table to be unchanged")
+	}
+}
+
+func TestDefaultTable(t *testing.T) {
+	for _, api := range deprecation.DefaultTable {
+		if !api.RemovedIn.AtLeast(deprecation.Version{Major: 1, Minor: 16}) || api.RemovedIn.AtLeast(deprecation.Version{Major: 1, Minor: 33}) {
+			t.Errorf("%s %s: removal version %s out of range", api.APIVersion, api.Kind, api.RemovedIn)
+		}
+
+		if !api.RemovedIn.AtLeast(api.DeprecatedIn) {
+			t.Errorf("%s %s: removed before being deprecated", api.APIVersion, api.Kind)
+		}
+
+		if !api.Rewritable && api.Note == "" {
+			t.Errorf("%s %s: entries which are not rewritable must describe the migration", api.APIVersion, api.Kind)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package deprecation
+
+// DeprecatedAPI is an API version of a kind which was removed from Kubernetes
+type DeprecatedAPI struct {
+	APIVersion string
+	Kind       string
+
+	// DeprecatedIn and RemovedIn are the minor Kubernetes versions, such as 1.22, in which the
+	// API version was deprecated and stopped being served
+	DeprecatedIn Version
+	RemovedIn    Version
+
+	// Replacements are the API versions which replace this one, in order of preference. The
+	// first one served by a cluster is used. Kinds which were removed without a replacement,
+	// such as PodSecurityPolicy, have none.
+	Replacements []string
+
+	// Rewritable is true when the kind's schema did not change in the replacements, so that
+	// objects can be migrated by only changing their apiVersion
+	Rewritable bool
+
+	// Note describes the changes required to migrate objects which are not rewritable
+	Note string
+}
+
+// Table is a list of removed API versions, which is looked up by apiVersion and kind
+type Table []DeprecatedAPI
+
+// Lookup returns the entry of the table for an API version of a kind
+func (t Table) Lookup(apiVersion, kind string) (*DeprecatedAPI, bool) {
+	for i := range t {
+		if t[i].APIVersion == apiVersion && t[i].Kind == kind {
+			return &t[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Extend returns a table with the entries of t followed by the given entries. Entries for an
+// API version and kind which is already in t replace the existing entry.
+func (t Table) Extend(entries ...DeprecatedAPI) Table {
+	res := make(Table, len(t), len(t)+len(entries))
+	copy(res, t)
+
+	for _, entry := range entries {
+		replaced := false
+
+		for i := range res {
+			if res[i].APIVersion == entry.APIVersion && res[i].Kind == entry.Kind {
+				res[i] = entry
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			res = append(res, entry)
+		}
+	}
+
+	return res
+}
+
+// removed returns the entries of the table for a list of kinds served by an API version
+func removed(apiVersion string, kinds []string, deprecatedIn, removedIn Version, replacements []string, rewritable bool, note string) []DeprecatedAPI {
+	res := make([]DeprecatedAPI, 0, len(kinds))
+
+	for _, kind := range kinds {
+		res = append(res, DeprecatedAPI{
+			APIVersion:   apiVersion,
+			Kind:         kind,
+			DeprecatedIn: deprecatedIn,
+			RemovedIn:    removedIn,
+			Replacements: replacements,
+			Rewritable:   rewritable,
+			Note:         note,
+		})
+	}
+
+	return res
+}
+
+// DefaultTable lists the API versions removed between Kubernetes 1.16 and 1.32, from the
+// Kubernetes deprecated API migration guide
+var DefaultTable = Table{}.Extend(concat(
+	// 1.16
+	removed("extensions/v1beta1", []string{"Deployment", "DaemonSet", "ReplicaSet"}, Version{1, 8}, Version{1, 16},
+		[]string{"apps/v1"}, false, "spec.selector is required and immutable, and spec.rollbackTo was removed"),
+	removed("apps/v1beta1", []string{"Deployment", "StatefulSet"}, Version{1, 9}, Version{1, 16},
+		[]string{"apps/v1"}, false, "spec.selector is required and immutable, and spec.rollbackTo was removed"),
+	removed("apps/v1beta2", []string{"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet"}, Version{1, 9}, Version{1, 16},
+		[]string{"apps/v1"}, true, ""),
+	removed("extensions/v1beta1", []string{"NetworkPolicy"}, Version{1, 9}, Version{1, 16},
+		[]string{"networking.k8s.io/v1"}, true, ""),
+	removed("extensions/v1beta1", []string{"PodSecurityPolicy"}, Version{1, 10}, Version{1, 16},
+		[]string{"policy/v1beta1"}, true, ""),
+
+	// 1.22
+	removed("extensions/v1beta1", []string{"Ingress"}, Version{1, 14}, Version{1, 22},
+		[]string{"networking.k8s.io/v1"}, false,
+		"spec.backend was renamed to spec.defaultBackend, backend.serviceName and backend.servicePort were replaced by backend.service.name and backend.service.port, and pathType is required"),
+	removed("networking.k8s.io/v1beta1", []string{"Ingress"}, Version{1, 19}, Version{1, 22},
+		[]string{"networking.k8s.io/v1"}, false,
+		"spec.backend was renamed to spec.defaultBackend, backend.serviceName and backend.servicePort were replaced by backend.service.name and backend.service.port, and pathType is required"),
+	removed("networking.k8s.io/v1beta1", []string{"IngressClass"}, Version{1, 19}, Version{1, 22},
+		[]string{"networking.k8s.io/v1"}, true, ""),
+	removed("admissionregistration.k8s.io/v1beta1", []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, Version{1, 16}, Version{1, 22},
+		[]string{"admissionregistration.k8s.io/v1"}, false,
+		"webhooks[*].admissionReviewVersions and webhooks[*].sideEffects are required, and the defaults of failurePolicy, matchPolicy and timeoutSeconds changed"),
+	removed("apiextensions.k8s.io/v1beta1", []string{"CustomResourceDefinition"}, Version{1, 16}, Version{1, 22},
+		[]string{"apiextensions.k8s.io/v1"}, false,
+		"spec.versions[*].schema.openAPIV3Schema is required and must be structural, and spec.version, spec.validation, spec.subresources and spec.additionalPrinterColumns moved under spec.versions[*]"),
+	removed("apiregistration.k8s.io/v1beta1", []string{"APIService"}, Version{1, 10}, Version{1, 22},
+		[]string{"apiregistration.k8s.io/v1"}, true, ""),
+	removed("authentication.k8s.io/v1beta1", []string{"TokenReview"}, Version{1, 6}, Version{1, 22},
+		[]string{"authentication.k8s.io/v1"}, true, ""),
+	removed("authorization.k8s.io/v1beta1", []string{"SubjectAccessReview", "LocalSubjectAccessReview", "SelfSubjectAccessReview", "SelfSubjectRulesReview"}, Version{1, 6}, Version{1, 22},
+		[]string{"authorization.k8s.io/v1"}, true, ""),
+	removed("certificates.k8s.io/v1beta1", []string{"CertificateSigningRequest"}, Version{1, 19}, Version{1, 22},
+		[]string{"certificates.k8s.io/v1"}, false, "spec.signerName is required"),
+	removed("coordination.k8s.io/v1beta1", []string{"Lease"}, Version{1, 14}, Version{1, 22},
+		[]string{"coordination.k8s.io/v1"}, true, ""),
+	removed("rbac.authorization.k8s.io/v1beta1", []string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"}, Version{1, 17}, Version{1, 22},
+		[]string{"rbac.authorization.k8s.io/v1"}, true, ""),
+	removed("scheduling.k8s.io/v1beta1", []string{"PriorityClass"}, Version{1, 14}, Version{1, 22},
+		[]string{"scheduling.k8s.io/v1"}, true, ""),
+	removed("storage.k8s.io/v1beta1", []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, Version{1, 19}, Version{1, 22},
+		[]string{"storage.k8s.io/v1"}, true, ""),
+
+	// 1.25
+	removed("batch/v1beta1", []string{"CronJob"}, Version{1, 21}, Version{1, 25},
+		[]string{"batch/v1"}, true, ""),
+	removed("discovery.k8s.io/v1beta1", []string{"EndpointSlice"}, Version{1, 21}, Version{1, 25},
+		[]string{"discovery.k8s.io/v1"}, false, "endpoints[*].topology was replaced by endpoints[*].nodeName and endpoints[*].zone"),
+	removed("events.k8s.io/v1beta1", []string{"Event"}, Version{1, 22}, Version{1, 25},
+		[]string{"events.k8s.io/v1"}, false, "type is limited to Normal and Warning, and deprecatedCount, deprecatedFirstTimestamp and deprecatedLastTimestamp were renamed"),
+	removed("autoscaling/v2beta1", []string{"HorizontalPodAutoscaler"}, Version{1, 22}, Version{1, 25},
+		[]string{"autoscaling/v2"}, false,
+		"metrics[*].resource.targetAverageUtilization and targetAverageValue were replaced by metrics[*].resource.target, and similarly for the other metric types"),
+	removed("policy/v1beta1", []string{"PodDisruptionBudget"}, Version{1, 21}, Version{1, 25},
+		[]string{"policy/v1"}, true, ""),
+	removed("policy/v1beta1", []string{"PodSecurityPolicy"}, Version{1, 21}, Version{1, 25},
+		nil, false, "PodSecurityPolicy has no replacement, use Pod Security Admission or a policy engine instead"),
+	removed("node.k8s.io/v1beta1", []string{"RuntimeClass"}, Version{1, 20}, Version{1, 25},
+		[]string{"node.k8s.io/v1"}, true, ""),
+
+	// 1.26
+	removed("flowcontrol.apiserver.k8s.io/v1beta1", []string{"FlowSchema", "PriorityLevelConfiguration"}, Version{1, 23}, Version{1, 26},
+		[]string{"flowcontrol.apiserver.k8s.io/v1", "flowcontrol.apiserver.k8s.io/v1beta3", "flowcontrol.apiserver.k8s.io/v1beta2"}, true, ""),
+	removed("autoscaling/v2beta2", []string{"HorizontalPodAutoscaler"}, Version{1, 23}, Version{1, 26},
+		[]string{"autoscaling/v2"}, true, ""),
+
+	// 1.27
+	removed("storage.k8s.io/v1beta1", []string{"CSIStorageCapacity"}, Version{1, 24}, Version{1, 27},
+		[]string{"storage.k8s.io/v1"}, true, ""),
+
+	// 1.29
+	removed("flowcontrol.apiserver.k8s.io/v1beta2", []string{"FlowSchema", "PriorityLevelConfiguration"}, Version{1, 26}, Version{1, 29},
+		[]string{"flowcontrol.apiserver.k8s.io/v1", "flowcontrol.apiserver.k8s.io/v1beta3"}, true, ""),
+
+	// 1.32
+	removed("flowcontrol.apiserver.k8s.io/v1beta3", []string{"FlowSchema", "PriorityLevelConfiguration"}, Version{1, 29}, Version{1, 32},
+		[]string{"flowcontrol.apiserver.k8s.io/v1"}, true, ""),
+)...)
+
+func concat(lists ...[]DeprecatedAPI) []DeprecatedAPI {
+	res := make([]DeprecatedAPI, 0)
+
+	for _, list := range lists {
+		res = append(res, list...)
+	}
+
+	return res
+}
@@ -0,0 +1,143 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/karagatandev/porter/internal/helm/grapher"
+	"github.com/stefanmcshane/helm/pkg/release"
+)
+
+// LiveObjectGetter fetches objects from the cluster. It returns nil without an error when
+// the object does not exist.
+type LiveObjectGetter interface {
+	GetLiveObject(ctx context.Context, apiVersion, kind, namespace, name string) (map[string]interface{}, error)
+}
+
+// ResourceDrift is the drift of a single object of a release. Missing is set when the object
+// was deleted from the cluster, and Error when its live state could not be fetched.
+type ResourceDrift struct {
+	APIVersion string       `json:"api_version"`
+	Kind       string       `json:"kind"`
+	Name       string       `json:"name"`
+	Namespace  string       `json:"namespace"`
+	Missing    bool         `json:"missing,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Fields     []FieldDrift `json:"fields,omitempty"`
+}
+
+// Report is the drift of a release's objects from its rendered manifest. Resources only
+// contains the objects which drifted or could not be fetched.
+type Report struct {
+	Release   string          `json:"release"`
+	Namespace string          `json:"namespace"`
+	Revision  int             `json:"revision"`
+	Checked   int             `json:"checked"`
+	Drifted   bool            `json:"drifted"`
+	Resources []ResourceDrift `json:"resources"`
+}
+
+// DetectRelease compares every object in the manifest of a Helm release with its live state
+func DetectRelease(ctx context.Context, getter LiveObjectGetter, rel *release.Release) (*Report, error) {
+	report, err := Detect(ctx, getter, rel.Manifest, rel.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Release = rel.Name
+	report.Revision = rel.Version
+
+	return report, nil
+}
+
+// Detect compares every object in a rendered manifest with its live state. Objects without
+// a namespace are looked up in namespace.
+func Detect(ctx context.Context, getter LiveObjectGetter, manifest, namespace string) (*Report, error) {
+	report := &Report{
+		Namespace: namespace,
+		Resources: []ResourceDrift{},
+	}
+
+	objs := grapher.ImportMultiDocYAML([]byte(manifest))
+	scaled := autoscaledObjects(objs)
+
+	for _, obj := range objs {
+		apiVersion, _ := obj["apiVersion"].(string)
+		kind, _ := obj["kind"].(string)
+
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		ns, _ := metadata["namespace"].(string)
+
+		// skip empty documents and block comments
+		if kind == "" || name == "" {
+			continue
+		}
+
+		if ns == "" {
+			ns = namespace
+		}
+
+		report.Checked++
+
+		res := ResourceDrift{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Name:       name,
+			Namespace:  ns,
+		}
+
+		live, err := getter.GetLiveObject(ctx, apiVersion, kind, ns, name)
+
+		switch {
+		case err != nil:
+			res.Error = fmt.Sprintf("error getting live object: %s", err.Error())
+		case live == nil:
+			res.Missing = true
+		default:
+			ignorePaths := []string{}
+
+			if scaled[kind+"/"+name] {
+				ignorePaths = append(ignorePaths, "spec.replicas")
+			}
+
+			res.Fields = Diff(obj, live, ignorePaths...)
+
+			if len(res.Fields) == 0 {
+				continue
+			}
+		}
+
+		if res.Missing || len(res.Fields) > 0 {
+			report.Drifted = true
+		}
+
+		report.Resources = append(report.Resources, res)
+	}
+
+	return report, nil
+}
+
+// autoscaledObjects returns the objects whose replicas are managed by a
+// HorizontalPodAutoscaler in the manifest, keyed by kind and name
+func autoscaledObjects(objs []map[string]interface{}) map[string]bool {
+	scaled := make(map[string]bool)
+
+	for _, obj := range objs {
+		if obj["kind"] != "HorizontalPodAutoscaler" {
+			continue
+		}
+
+		spec, _ := obj["spec"].(map[string]interface{})
+		ref, _ := spec["scaleTargetRef"].(map[string]interface{})
+
+		kind, _ := ref["kind"].(string)
+		name, _ := ref["name"].(string)
+
+		if kind != "" && name != "" {
+			scaled[kind+"/"+name] = true
+		}
+	}
+
+	return scaled
+}
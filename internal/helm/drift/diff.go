@@ -0,0 +1,409 @@
+package drift
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// redacted replaces the values of Secrets in reports
+const redacted = "<redacted>"
+
+// FieldDrift is a field whose live value differs from the value in the rendered manifest.
+// Desired is nil when a list item was added to the live object, and Live is nil when the
+// field was removed from it.
+type FieldDrift struct {
+	Path    string      `json:"path"`
+	Desired interface{} `json:"desired"`
+	Live    interface{} `json:"live"`
+}
+
+// ignoredMetadataFields are set by the API server, so are never compared
+var ignoredMetadataFields = map[string]bool{
+	"uid":                        true,
+	"resourceVersion":            true,
+	"generation":                 true,
+	"creationTimestamp":          true,
+	"deletionTimestamp":          true,
+	"deletionGracePeriodSeconds": true,
+	"managedFields":              true,
+	"selfLink":                   true,
+	"ownerReferences":            true,
+	"finalizers":                 true,
+}
+
+// listKeys are the fields which identify the items of a list of objects, in order of
+// preference. Items of lists without one of these keys are compared by index.
+var listKeys = []string{"name", "containerPort", "mountPath", "ip"}
+
+// quantitySegments are the path segments under which values are resource quantities, which
+// the API server canonicalizes, e.g. "1024Mi" to "1Gi"
+var quantitySegments = map[string]bool{
+	"resources": true,
+	"capacity":  true,
+	"hard":      true,
+}
+
+// Diff compares an object from a rendered manifest with the live object. Only the fields set
+// in the manifest are compared, so fields defaulted by the API server and status are not
+// reported, except that items added to lists and keys added to the data of ConfigMaps and
+// Secrets are. ignorePaths are paths which are not compared, e.g. "spec.replicas" for
+// objects scaled by an autoscaler.
+func Diff(desired, live map[string]interface{}, ignorePaths ...string) []FieldDrift {
+	d := &differ{
+		kind:    fmt.Sprint(desired["kind"]),
+		ignored: make(map[string]bool),
+		drifts:  []FieldDrift{},
+	}
+
+	for _, path := range ignorePaths {
+		d.ignored[path] = true
+	}
+
+	desired = normalizeDesired(d.kind, desired)
+
+	for key, value := range desired {
+		switch key {
+		case "status", "apiVersion", "kind":
+			continue
+		case "metadata":
+			d.diffMetadata(value, live[key])
+		case "data", "binaryData", "stringData":
+			if d.kind == "ConfigMap" || d.kind == "Secret" {
+				d.diffData(key, value, live[key])
+				continue
+			}
+
+			d.diff(key, []string{key}, value, live[key])
+		default:
+			d.diff(key, []string{key}, value, live[key])
+		}
+	}
+
+	sort.Slice(d.drifts, func(i, j int) bool {
+		return d.drifts[i].Path < d.drifts[j].Path
+	})
+
+	return d.drifts
+}
+
+type differ struct {
+	kind    string
+	ignored map[string]bool
+	drifts  []FieldDrift
+}
+
+func (d *differ) report(path string, desired, live interface{}) {
+	if d.kind == "Secret" && (strings.HasPrefix(path, "data") || strings.HasPrefix(path, "stringData")) {
+		if desired != nil {
+			desired = redacted
+		}
+
+		if live != nil {
+			live = redacted
+		}
+	}
+
+	d.drifts = append(d.drifts, FieldDrift{
+		Path:    path,
+		Desired: desired,
+		Live:    live,
+	})
+}
+
+func (d *differ) diffMetadata(desired, live interface{}) {
+	desiredMeta, ok := desired.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	liveMeta, _ := live.(map[string]interface{})
+
+	for key, value := range desiredMeta {
+		if ignoredMetadataFields[key] || key == "namespace" {
+			continue
+		}
+
+		d.diff(joinPath("metadata", key), []string{"metadata", key}, value, liveMeta[key])
+	}
+}
+
+// diffData compares the data of ConfigMaps and Secrets, in which added keys are drift
+func (d *differ) diffData(key string, desired, live interface{}) {
+	desiredData, _ := desired.(map[string]interface{})
+	liveData, _ := live.(map[string]interface{})
+
+	for k, value := range desiredData {
+		d.diff(joinPath(key, k), []string{key, k}, value, liveData[k])
+	}
+
+	for k, value := range liveData {
+		if _, ok := desiredData[k]; !ok {
+			d.report(joinPath(key, k), nil, value)
+		}
+	}
+}
+
+func (d *differ) diff(path string, segments []string, desired, live interface{}) {
+	if d.ignored[path] {
+		return
+	}
+
+	if live == nil {
+		// fields set to their zero value are dropped when the object is stored
+		if !isZero(desired) {
+			d.report(path, desired, nil)
+		}
+
+		return
+	}
+
+	switch desiredVal := desired.(type) {
+	case map[string]interface{}:
+		liveVal, ok := live.(map[string]interface{})
+		if !ok {
+			d.report(path, desired, live)
+			return
+		}
+
+		for key, value := range desiredVal {
+			d.diff(joinPath(path, key), append(segments[:len(segments):len(segments)], key), value, liveVal[key])
+		}
+	case []interface{}:
+		liveVal, ok := live.([]interface{})
+		if !ok {
+			d.report(path, desired, live)
+			return
+		}
+
+		d.diffList(path, segments, desiredVal, liveVal)
+	default:
+		if !scalarEqual(desired, live, inQuantity(segments)) {
+			d.report(path, desired, live)
+		}
+	}
+}
+
+func (d *differ) diffList(path string, segments []string, desired, live []interface{}) {
+	if key := listKey(desired); key != "" {
+		liveItems := make(map[string]interface{})
+
+		for _, item := range live {
+			if m, ok := item.(map[string]interface{}); ok {
+				liveItems[fmt.Sprint(m[key])] = item
+			}
+		}
+
+		seen := make(map[string]bool)
+
+		for _, item := range desired {
+			id := fmt.Sprint(item.(map[string]interface{})[key])
+			seen[id] = true
+
+			itemPath := fmt.Sprintf("%s[%s=%s]", path, key, id)
+
+			if liveItem, ok := liveItems[id]; ok {
+				d.diff(itemPath, segments, item, liveItem)
+			} else {
+				d.report(itemPath, item, nil)
+			}
+		}
+
+		for id, item := range liveItems {
+			if !seen[id] {
+				d.report(fmt.Sprintf("%s[%s=%s]", path, key, id), nil, item)
+			}
+		}
+
+		return
+	}
+
+	if len(desired) > 0 {
+		if _, ok := desired[0].(map[string]interface{}); ok && len(desired) == len(live) {
+			for i := range desired {
+				d.diff(fmt.Sprintf("%s[%d]", path, i), segments, desired[i], live[i])
+			}
+
+			return
+		}
+	}
+
+	if len(desired) != len(live) {
+		d.report(path, desired, live)
+		return
+	}
+
+	for i := range desired {
+		d.diff(fmt.Sprintf("%s[%d]", path, i), segments, desired[i], live[i])
+	}
+}
+
+// listKey returns the field that identifies every item of a list of objects, if any
+func listKey(items []interface{}) string {
+	if len(items) == 0 {
+		return ""
+	}
+
+	for _, key := range listKeys {
+		ids := make(map[string]bool)
+		ok := true
+
+		for _, item := range items {
+			m, isMap := item.(map[string]interface{})
+			if !isMap || m[key] == nil || ids[fmt.Sprint(m[key])] {
+				ok = false
+				break
+			}
+
+			ids[fmt.Sprint(m[key])] = true
+		}
+
+		if ok {
+			return key
+		}
+	}
+
+	return ""
+}
+
+// normalizeDesired converts a manifest to the form in which the API server stores it. The
+// stringData of Secrets is merged into their data, base64 encoded.
+func normalizeDesired(kind string, desired map[string]interface{}) map[string]interface{} {
+	stringData, ok := desired["stringData"].(map[string]interface{})
+	if kind != "Secret" || !ok {
+		return desired
+	}
+
+	normalized := make(map[string]interface{}, len(desired))
+
+	for k, v := range desired {
+		if k != "stringData" {
+			normalized[k] = v
+		}
+	}
+
+	data := make(map[string]interface{})
+
+	if existing, ok := desired["data"].(map[string]interface{}); ok {
+		for k, v := range existing {
+			data[k] = v
+		}
+	}
+
+	for k, v := range stringData {
+		data[k] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(v)))
+	}
+
+	normalized["data"] = data
+
+	return normalized
+}
+
+func inQuantity(segments []string) bool {
+	for _, segment := range segments {
+		if quantitySegments[segment] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scalarEqual compares values decoded from YAML and JSON, which differ in their numeric types
+func scalarEqual(desired, live interface{}, quantity bool) bool {
+	if reflect.DeepEqual(desired, live) {
+		return true
+	}
+
+	desiredNum, desiredIsNum := toFloat(desired)
+	liveNum, liveIsNum := toFloat(live)
+
+	if desiredIsNum && liveIsNum {
+		return desiredNum == liveNum
+	}
+
+	if quantity {
+		desiredQty, err := resource.ParseQuantity(fmt.Sprint(desired))
+		if err != nil {
+			return false
+		}
+
+		liveQty, err := resource.ParseQuantity(fmt.Sprint(live))
+		if err != nil {
+			return false
+		}
+
+		return desiredQty.Cmp(liveQty) == 0
+	}
+
+	// int-or-string fields, e.g. a target port of "8080"
+	_, desiredIsString := desired.(string)
+	_, liveIsString := live.(string)
+
+	if desiredIsString != liveIsString && (desiredIsNum || liveIsNum) {
+		return fmt.Sprint(desired) == fmt.Sprint(live)
+	}
+
+	return false
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+func isZero(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+
+	switch v := val.(type) {
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	}
+
+	if num, ok := toFloat(val); ok {
+		return num == 0
+	}
+
+	return false
+}
+
+// joinPath appends a key to a path, quoting keys such as annotation names
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,220 @@
+package drift_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/karagatandev/porter/internal/helm/drift"
+	"github.com/karagatandev/porter/internal/helm/grapher"
+)
+
+const deploymentManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  annotations:
+    porter.run/app: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.25
+          env:
+            - name: PORT
+              value: "8080"
+          ports:
+            - containerPort: 8080
+          resources:
+            requests:
+              memory: 1024Mi
+              cpu: 0.5
+`
+
+// liveDeployment is deploymentManifest as stored by the API server: defaulted, with a
+// status and canonicalized quantities
+const liveDeployment = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {
+    "name": "web",
+    "namespace": "default",
+    "uid": "0b0e3c8e",
+    "resourceVersion": "1234",
+    "annotations": {
+      "porter.run/app": "web",
+      "deployment.kubernetes.io/revision": "3"
+    }
+  },
+  "spec": {
+    "replicas": 2,
+    "progressDeadlineSeconds": 600,
+    "template": {
+      "spec": {
+        "containers": [
+          {
+            "name": "web",
+            "image": "nginx:1.25",
+            "imagePullPolicy": "IfNotPresent",
+            "env": [{"name": "PORT", "value": "8080"}],
+            "ports": [{"containerPort": 8080, "protocol": "TCP"}],
+            "resources": {"requests": {"memory": "1Gi", "cpu": "500m"}}
+          }
+        ]
+      }
+    }
+  },
+  "status": {"replicas": 2}
+}`
+
+func decodeLive(t *testing.T, data string) map[string]interface{} {
+	live := map[string]interface{}{}
+
+	if err := json.Unmarshal([]byte(data), &live); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	return live
+}
+
+func decodeDesired(manifest string) map[string]interface{} {
+	return grapher.ImportMultiDocYAML([]byte(manifest))[0]
+}
+
+func TestDiffIgnoresDefaultsAndStatus(t *testing.T) {
+	fields := drift.Diff(decodeDesired(deploymentManifest), decodeLive(t, liveDeployment))
+
+	if len(fields) != 0 {
+		t.Errorf("Expected no drift, got %v", fields)
+	}
+}
+
+func TestDiffReportsEditedFields(t *testing.T) {
+	live := decodeLive(t, liveDeployment)
+
+	spec := live["spec"].(map[string]interface{})
+	spec["replicas"] = float64(5)
+
+	container := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})
+	container["image"] = "nginx:latest"
+	container["env"] = append(container["env"].([]interface{}), map[string]interface{}{"name": "DEBUG", "value": "1"})
+
+	fields := drift.Diff(decodeDesired(deploymentManifest), live)
+
+	expected := map[string]bool{
+		"spec.replicas": true,
+		"spec.template.spec.containers[name=web].image":           true,
+		"spec.template.spec.containers[name=web].env[name=DEBUG]": true,
+	}
+
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %d drifted fields, got %v", len(expected), fields)
+	}
+
+	for _, field := range fields {
+		if !expected[field.Path] {
+			t.Errorf("Unexpected drifted field %s", field.Path)
+		}
+	}
+
+	// replicas of autoscaled objects are expected to change
+	fields = drift.Diff(decodeDesired(deploymentManifest), live, "spec.replicas")
+
+	for _, field := range fields {
+		if field.Path == "spec.replicas" {
+			t.Errorf("Expected spec.replicas to be ignored")
+		}
+	}
+}
+
+func TestDiffRedactsSecrets(t *testing.T) {
+	desired := decodeDesired(`apiVersion: v1
+kind: Secret
+metadata:
+  name: web
+stringData:
+  password: hunter2
+`)
+
+	live := decodeLive(t, `{
+  "apiVersion": "v1",
+  "kind": "Secret",
+  "metadata": {"name": "web"},
+  "data": {"password": "Y2hhbmdlZA==", "token": "YWRkZWQ="}
+}`)
+
+	fields := drift.Diff(desired, live)
+
+	if len(fields) != 2 {
+		t.Fatalf("Expected 2 drifted fields, got %v", fields)
+	}
+
+	for _, field := range fields {
+		if field.Live != "<redacted>" {
+			t.Errorf("Expected the live value of %s to be redacted, got %v", field.Path, field.Live)
+		}
+	}
+
+	if fields[0].Path != "data.password" || fields[1].Path != "data.token" || fields[1].Desired != nil {
+		t.Errorf("Unexpected drifted fields %v", fields)
+	}
+}
+
+type fakeGetter map[string]map[string]interface{}
+
+func (f fakeGetter) GetLiveObject(ctx context.Context, apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	return f[kind+"/"+namespace+"/"+name], nil
+}
+
+func TestDetect(t *testing.T) {
+	manifest := deploymentManifest + `---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+data:
+  LOG_LEVEL: info
+`
+
+	live := decodeLive(t, liveDeployment)
+	live["spec"].(map[string]interface{})["replicas"] = float64(7)
+
+	getter := fakeGetter{
+		"Deployment/default/web": live,
+		"HorizontalPodAutoscaler/default/web": {
+			"metadata": map[string]interface{}{"name": "web"},
+			"spec": map[string]interface{}{
+				"scaleTargetRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"},
+			},
+		},
+	}
+
+	report, err := drift.Detect(context.Background(), getter, manifest, "default")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if report.Checked != 3 {
+		t.Errorf("Expected 3 checked objects, got %d", report.Checked)
+	}
+
+	if !report.Drifted || len(report.Resources) != 1 {
+		t.Fatalf("Expected only the config map to have drifted, got %+v", report.Resources)
+	}
+
+	if res := report.Resources[0]; res.Kind != "ConfigMap" || !res.Missing {
+		t.Errorf("Expected the config map to be missing, got %+v", res)
+	}
+}
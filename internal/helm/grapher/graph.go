@@ -0,0 +1,129 @@
+package grapher
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GraphNode is an object of a release in an exported graph.
+type GraphNode struct {
+	ID        int    `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// GraphEdge is a relationship between two objects in an exported graph. Type is "control",
+// "label" or "spec" for the untyped relationships, and the ResourceRelType otherwise.
+type GraphEdge struct {
+	Source int    `json:"source"`
+	Target int    `json:"target"`
+	Type   string `json:"type"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Graph is the flattened form of ParsedObjs, in which every relationship appears once.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// Graph flattens the objects and their relationships. Relationships are stored on both of
+// the objects they connect, so they are deduplicated.
+func (parsed *ParsedObjs) Graph() *Graph {
+	graph := &Graph{
+		Nodes: []GraphNode{},
+		Edges: []GraphEdge{},
+	}
+
+	seen := make(map[GraphEdge]bool)
+
+	addEdge := func(rel Relation, relType, detail string) {
+		edge := GraphEdge{
+			Source: rel.Source,
+			Target: rel.Target,
+			Type:   relType,
+			Detail: detail,
+		}
+
+		if !seen[edge] {
+			seen[edge] = true
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	for _, o := range parsed.Objects {
+		graph.Nodes = append(graph.Nodes, GraphNode{
+			ID:        o.ID,
+			Kind:      o.Kind,
+			Name:      o.Name,
+			Namespace: o.Namespace,
+		})
+
+		for _, rel := range o.Relations.ControlRels {
+			addEdge(rel.Relation, "control", "")
+		}
+
+		for _, rel := range o.Relations.LabelRels {
+			addEdge(rel.Relation, "label", "")
+		}
+
+		for _, rel := range o.Relations.SpecRels {
+			addEdge(rel.Relation, "spec", "")
+		}
+
+		for _, rel := range o.Relations.ResourceRels {
+			addEdge(rel.Relation, string(rel.Type), rel.Detail)
+		}
+	}
+
+	sort.SliceStable(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Source != graph.Edges[j].Source {
+			return graph.Edges[i].Source < graph.Edges[j].Source
+		}
+
+		return graph.Edges[i].Target < graph.Edges[j].Target
+	})
+
+	return graph
+}
+
+// JSON encodes the graph as indented JSON.
+func (g *Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// DOT encodes the graph in the Graphviz DOT language, e.g. to be rendered with `dot -Tsvg`.
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph release {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	for _, node := range g.Nodes {
+		label := fmt.Sprintf("%s\n%s", node.Kind, node.Name)
+		fmt.Fprintf(&sb, "  %d [label=%s];\n", node.ID, dotQuote(label))
+	}
+
+	for _, edge := range g.Edges {
+		label := edge.Type
+		if edge.Detail != "" {
+			label = fmt.Sprintf("%s (%s)", edge.Type, edge.Detail)
+		}
+
+		fmt.Fprintf(&sb, "  %d -> %d [label=%s];\n", edge.Source, edge.Target, dotQuote(label))
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// dotQuote quotes a string as a DOT identifier, keeping newlines as line breaks.
+func dotQuote(str string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(str) + `"`
+}
@@ -0,0 +1,64 @@
+package grapher_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/karagatandev/porter/internal/helm/grapher"
+)
+
+func TestGraph(t *testing.T) {
+	parsed := parseTestFile(t, "./test_yaml/resources.yaml")
+	graph := parsed.Graph()
+
+	if len(graph.Nodes) != len(parsed.Objects) {
+		t.Errorf("Expected %d nodes. Got %d", len(parsed.Objects), len(graph.Nodes))
+	}
+
+	seen := map[grapher.GraphEdge]bool{}
+
+	for _, edge := range graph.Edges {
+		if seen[edge] {
+			t.Errorf("Duplicate edge %v", edge)
+		}
+
+		seen[edge] = true
+	}
+
+	if !seen[grapher.GraphEdge{Source: 0, Target: 1, Type: "ingress_backend", Detail: "80"}] {
+		t.Errorf("Expected an ingress backend edge from the ingress to the service")
+	}
+
+	if !seen[grapher.GraphEdge{Source: 2, Target: 13, Type: "control"}] {
+		t.Errorf("Expected a control edge from the deployment to its pod")
+	}
+
+	encoded, err := graph.JSON()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	decoded := &grapher.Graph{}
+
+	if err := json.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(decoded.Edges) != len(graph.Edges) {
+		t.Errorf("Expected %d edges after decoding. Got %d", len(graph.Edges), len(decoded.Edges))
+	}
+
+	dot := graph.DOT()
+
+	for _, expected := range []string{
+		"digraph release {",
+		`0 [label="Ingress\nweb"];`,
+		`0 -> 1 [label="ingress_backend (80)"];`,
+		`1 -> 13 [label="service_endpoint (80->http)"];`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("Expected DOT output to contain %s, got:\n%s", expected, dot)
+		}
+	}
+}
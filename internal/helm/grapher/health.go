@@ -0,0 +1,178 @@
+package grapher
+
+import (
+	"sort"
+	"time"
+)
+
+// HealthStatus is the health of an object in the cluster.
+type HealthStatus string
+
+const (
+	// HealthHealthy means that the object is in its desired state.
+	HealthHealthy HealthStatus = "healthy"
+
+	// HealthProgressing means that the object is converging to its desired state, e.g. during a rollout.
+	HealthProgressing HealthStatus = "progressing"
+
+	// HealthDegraded means that the object is missing or failing.
+	HealthDegraded HealthStatus = "degraded"
+
+	// HealthUnknown means that the health of the object cannot be determined, e.g. for a ConfigMap.
+	HealthUnknown HealthStatus = "unknown"
+)
+
+// healthRank orders statuses by severity. Unknown statuses do not roll up.
+var healthRank = map[HealthStatus]int{
+	HealthUnknown:     0,
+	HealthHealthy:     1,
+	HealthProgressing: 2,
+	HealthDegraded:    3,
+}
+
+// rollUpEdgeTypes are the edges whose source depends on its target, so that the health of
+// the target rolls up into the source. Edges from configuration, policies and RBAC objects
+// to the objects using them are not followed.
+var rollUpEdgeTypes = map[string]bool{
+	"control":                          true,
+	"label":                            true,
+	"spec":                             true,
+	string(ResourceRelIngressBackend):  true,
+	string(ResourceRelServiceEndpoint): true,
+	string(ResourceRelScaleTarget):     true,
+	string(ResourceRelVolumeBinding):   true,
+}
+
+// LiveEvent is a Kubernetes event involving an object.
+type LiveEvent struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// RolloutStatus is the progress of a rollout of a controller's pods.
+type RolloutStatus struct {
+	Desired   int32 `json:"desired"`
+	Updated   int32 `json:"updated"`
+	Ready     int32 `json:"ready"`
+	Available int32 `json:"available"`
+}
+
+// PodStatus is the live status of a pod.
+type PodStatus struct {
+	Name                  string `json:"name"`
+	Phase                 string `json:"phase"`
+	Restarts              int32  `json:"restarts"`
+	LastTerminationReason string `json:"last_termination_reason,omitempty"`
+}
+
+// LiveStatus is the state of an object in the cluster. Only the fields that apply to the
+// kind of the object are set.
+type LiveStatus struct {
+	Health  HealthStatus `json:"health"`
+	Message string       `json:"message,omitempty"`
+
+	Rollout   *RolloutStatus `json:"rollout,omitempty"`
+	Pod       *PodStatus     `json:"pod,omitempty"`
+	Endpoints *int           `json:"endpoints,omitempty"`
+	Addresses []string       `json:"addresses,omitempty"`
+	Binding   string         `json:"binding,omitempty"`
+
+	Events []LiveEvent `json:"events,omitempty"`
+}
+
+// HealthNode is an object of a release annotated with its live status. Health is the rolled
+// up health of the object and everything it depends on, and DegradedBy lists the objects
+// that made it worse than the object's own status.
+type HealthNode struct {
+	GraphNode
+	Live       *LiveStatus  `json:"live"`
+	Health     HealthStatus `json:"health"`
+	DegradedBy []int        `json:"degraded_by,omitempty"`
+}
+
+// HealthGraph is a Graph whose nodes are annotated with their live status.
+type HealthGraph struct {
+	Nodes []HealthNode `json:"nodes"`
+	Edges []GraphEdge  `json:"edges"`
+}
+
+// WithHealth annotates the nodes of the graph with their live status, keyed by object ID, and
+// rolls health up through the edges so that a failing object marks the objects depending on
+// it as failing too. Nodes without a live status are unknown.
+func (g *Graph) WithHealth(live map[int]*LiveStatus) *HealthGraph {
+	hg := &HealthGraph{
+		Nodes: make([]HealthNode, 0, len(g.Nodes)),
+		Edges: g.Edges,
+	}
+
+	dependencies := make(map[int][]int)
+
+	for _, edge := range g.Edges {
+		if rollUpEdgeTypes[edge.Type] && edge.Source != edge.Target {
+			dependencies[edge.Source] = append(dependencies[edge.Source], edge.Target)
+		}
+	}
+
+	own := make(map[int]HealthStatus)
+
+	for _, node := range g.Nodes {
+		status, ok := live[node.ID]
+		if !ok || status == nil {
+			status = &LiveStatus{Health: HealthUnknown}
+		}
+
+		own[node.ID] = status.Health
+
+		hg.Nodes = append(hg.Nodes, HealthNode{
+			GraphNode: node,
+			Live:      status,
+			Health:    status.Health,
+		})
+	}
+
+	for i, node := range hg.Nodes {
+		causes := []int{}
+
+		// walk every object the node transitively depends on, guarding against cycles
+		// between objects which select each other
+		visited := map[int]bool{node.ID: true}
+		queue := append([]int{}, dependencies[node.ID]...)
+
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+
+			if visited[id] {
+				continue
+			}
+
+			visited[id] = true
+			queue = append(queue, dependencies[id]...)
+
+			health, ok := own[id]
+			if !ok || health == HealthUnknown || health == HealthHealthy {
+				continue
+			}
+
+			if healthRank[health] > healthRank[hg.Nodes[i].Health] {
+				hg.Nodes[i].Health = health
+			}
+
+			causes = append(causes, id)
+		}
+
+		// only keep the causes which account for the rolled up health
+		for _, id := range causes {
+			if own[id] == hg.Nodes[i].Health && healthRank[own[id]] > healthRank[node.Health] {
+				hg.Nodes[i].DegradedBy = append(hg.Nodes[i].DegradedBy, id)
+			}
+		}
+
+		sort.Ints(hg.Nodes[i].DegradedBy)
+	}
+
+	return hg
+}
@@ -0,0 +1,105 @@
+package grapher_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/karagatandev/porter/internal/helm/grapher"
+)
+
+// ids of the objects in test_yaml/resources.yaml
+const (
+	healthIngress       = 0
+	healthService       = 1
+	healthDeployment    = 2
+	healthAutoscaler    = 3
+	healthConfigMap     = 4
+	healthClaim         = 6
+	healthNetworkPolicy = 12
+	healthPod           = 13
+)
+
+func allHealthy(parsed grapher.ParsedObjs) map[int]*grapher.LiveStatus {
+	live := make(map[int]*grapher.LiveStatus)
+
+	for _, o := range parsed.Objects {
+		live[o.ID] = &grapher.LiveStatus{Health: grapher.HealthHealthy}
+	}
+
+	return live
+}
+
+func TestWithHealthDegradedLeaf(t *testing.T) {
+	parsed := parseTestFile(t, "./test_yaml/resources.yaml")
+
+	live := allHealthy(parsed)
+	live[healthPod] = &grapher.LiveStatus{Health: grapher.HealthDegraded, Message: "container web: CrashLoopBackOff"}
+
+	graph := parsed.Graph().WithHealth(live)
+
+	expected := map[int]grapher.HealthStatus{
+		healthIngress:       grapher.HealthDegraded,
+		healthService:       grapher.HealthDegraded,
+		healthDeployment:    grapher.HealthDegraded,
+		healthAutoscaler:    grapher.HealthDegraded,
+		healthPod:           grapher.HealthDegraded,
+		healthConfigMap:     grapher.HealthHealthy,
+		healthClaim:         grapher.HealthHealthy,
+		healthNetworkPolicy: grapher.HealthHealthy,
+	}
+
+	for _, node := range graph.Nodes {
+		want, ok := expected[node.ID]
+		if !ok {
+			continue
+		}
+
+		if node.Health != want {
+			t.Errorf("%s %s: expected health %s, got %s", node.Kind, node.Name, want, node.Health)
+		}
+	}
+
+	deployment := graph.Nodes[healthDeployment]
+
+	if deployment.Live.Health != grapher.HealthHealthy {
+		t.Errorf("Expected the deployment's own status to be kept, got %s", deployment.Live.Health)
+	}
+
+	if !reflect.DeepEqual(deployment.DegradedBy, []int{healthPod}) {
+		t.Errorf("Expected the deployment to be degraded by its pod, got %v", deployment.DegradedBy)
+	}
+
+	if len(graph.Nodes[healthPod].DegradedBy) != 0 {
+		t.Errorf("Expected the pod to be degraded by itself only, got %v", graph.Nodes[healthPod].DegradedBy)
+	}
+}
+
+func TestWithHealthProgressingDependency(t *testing.T) {
+	parsed := parseTestFile(t, "./test_yaml/resources.yaml")
+
+	live := allHealthy(parsed)
+	live[healthClaim] = &grapher.LiveStatus{Health: grapher.HealthProgressing, Binding: "Pending"}
+	delete(live, healthConfigMap)
+
+	graph := parsed.Graph().WithHealth(live)
+
+	if graph.Nodes[healthPod].Health != grapher.HealthProgressing {
+		t.Errorf("Expected the pod mounting the pending claim to be progressing, got %s", graph.Nodes[healthPod].Health)
+	}
+
+	if !reflect.DeepEqual(graph.Nodes[healthIngress].DegradedBy, []int{healthClaim}) {
+		t.Errorf("Expected the ingress to be progressing because of the claim, got %v", graph.Nodes[healthIngress].DegradedBy)
+	}
+
+	if graph.Nodes[healthConfigMap].Health != grapher.HealthUnknown {
+		t.Errorf("Expected the config map without a live status to be unknown, got %s", graph.Nodes[healthConfigMap].Health)
+	}
+
+	// a degraded object outranks a progressing dependency
+	live[healthPod] = &grapher.LiveStatus{Health: grapher.HealthDegraded}
+	graph = parsed.Graph().WithHealth(live)
+
+	if !reflect.DeepEqual(graph.Nodes[healthDeployment].DegradedBy, []int{healthPod}) {
+		t.Errorf("Expected the deployment to be degraded by its pod only, got %v", graph.Nodes[healthDeployment].DegradedBy)
+	}
+}
@@ -42,9 +42,10 @@ func ParseObjs(objs []map[string]interface{}, releaseNamespace string) []Object
 			Namespace: namespace.(string),
 			RawYAML:   obj,
 			Relations: Relations{
-				ControlRels: []ControlRel{},
-				LabelRels:   []LabelRel{},
-				SpecRels:    []SpecRel{},
+				ControlRels:  []ControlRel{},
+				LabelRels:    []LabelRel{},
+				SpecRels:     []SpecRel{},
+				ResourceRels: []ResourceRel{},
 			},
 		}
 		objArr = append(objArr, parsedObj)
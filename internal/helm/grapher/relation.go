@@ -35,11 +35,12 @@ type ParsedObjs struct {
 	PodSelectors []string
 }
 
-// Relations is embedded into the Object struct and contains arrays of the three types of relationships.
+// Relations is embedded into the Object struct and contains arrays of each type of relationship.
 type Relations struct {
-	ControlRels []ControlRel
-	LabelRels   []LabelRel
-	SpecRels    []SpecRel
+	ControlRels  []ControlRel
+	LabelRels    []LabelRel
+	SpecRels     []SpecRel
+	ResourceRels []ResourceRel
 }
 
 // MatchLabel is used to match Equality label selector.
@@ -0,0 +1,521 @@
+package grapher
+
+import (
+	"fmt"
+)
+
+// ResourceRelType is the kind of relationship that a ResourceRel describes.
+type ResourceRelType string
+
+const (
+	// ResourceRelIngressBackend connects an Ingress to the Services that it routes to.
+	ResourceRelIngressBackend ResourceRelType = "ingress_backend"
+
+	// ResourceRelServiceEndpoint connects a Service to the Pods that serve its ports.
+	ResourceRelServiceEndpoint ResourceRelType = "service_endpoint"
+
+	// ResourceRelScaleTarget connects a HorizontalPodAutoscaler to the controller it scales.
+	ResourceRelScaleTarget ResourceRelType = "scale_target"
+
+	// ResourceRelVolumeBinding connects a PersistentVolumeClaim to the PersistentVolume bound to it.
+	ResourceRelVolumeBinding ResourceRelType = "volume_binding"
+
+	// ResourceRelStorageClass connects a PersistentVolumeClaim or PersistentVolume to its StorageClass.
+	ResourceRelStorageClass ResourceRelType = "storage_class"
+
+	// ResourceRelRoleSubject connects a ServiceAccount to the RoleBindings and ClusterRoleBindings naming it.
+	ResourceRelRoleSubject ResourceRelType = "role_subject"
+
+	// ResourceRelRoleRef connects a RoleBinding or ClusterRoleBinding to the Role or ClusterRole it grants.
+	ResourceRelRoleRef ResourceRelType = "role_ref"
+
+	// ResourceRelConfigConsumer connects a ConfigMap or Secret to the Pods that consume it.
+	ResourceRelConfigConsumer ResourceRelType = "config_consumer"
+
+	// ResourceRelNetworkPolicy connects a NetworkPolicy to the Pods that it selects.
+	ResourceRelNetworkPolicy ResourceRelType = "network_policy"
+)
+
+// ResourceRel is a typed relationship between two objects. Detail describes the edge further:
+// the port for ingress backends and service endpoints, and how a ConfigMap or Secret is
+// consumed ("envFrom", "env", "volume" or "imagePullSecrets") for config consumers.
+type ResourceRel struct {
+	Relation
+	Type   ResourceRelType
+	Detail string
+}
+
+// GetResourceRel generates typed relationships between Ingresses, Services, Pods, autoscalers,
+// storage, RBAC objects, configuration and network policies. It must be called after
+// GetControlRel so that the Pods of controllers can be connected.
+func (parsed *ParsedObjs) GetResourceRel() {
+	for _, o := range parsed.Objects {
+		switch o.Kind {
+		case "Ingress":
+			parsed.getIngressBackendRels(o)
+		case "Service":
+			parsed.getServiceEndpointRels(o)
+		case "HorizontalPodAutoscaler":
+			kind := getString(o.RawYAML, "spec", "scaleTargetRef", "kind")
+			name := getString(o.RawYAML, "spec", "scaleTargetRef", "name")
+
+			for _, target := range parsed.findObjects(kind, name, o.Namespace) {
+				parsed.addResourceRel(o.ID, target.ID, ResourceRelScaleTarget, "")
+			}
+		case "PersistentVolumeClaim":
+			volumeName := getString(o.RawYAML, "spec", "volumeName")
+
+			for _, target := range parsed.findObjects("PersistentVolume", volumeName, "") {
+				parsed.addResourceRel(o.ID, target.ID, ResourceRelVolumeBinding, "")
+			}
+
+			parsed.getStorageClassRels(o)
+		case "PersistentVolume":
+			// volumes may be pre-bound to a claim rather than the other way around
+			claimName := getString(o.RawYAML, "spec", "claimRef", "name")
+			claimNamespace := getString(o.RawYAML, "spec", "claimRef", "namespace")
+
+			for _, claim := range parsed.findObjects("PersistentVolumeClaim", claimName, claimNamespace) {
+				if getString(claim.RawYAML, "spec", "volumeName") != o.Name {
+					parsed.addResourceRel(claim.ID, o.ID, ResourceRelVolumeBinding, "")
+				}
+			}
+
+			parsed.getStorageClassRels(o)
+		case "RoleBinding", "ClusterRoleBinding":
+			parsed.getRoleBindingRels(o)
+		case "ConfigMap", "Secret":
+			parsed.getConfigConsumerRels(o)
+		case "NetworkPolicy":
+			parsed.getNetworkPolicyRels(o)
+		}
+	}
+}
+
+func (parsed *ParsedObjs) getIngressBackendRels(o Object) {
+	backends := []map[string]interface{}{}
+
+	if backend := getMap(o.RawYAML, "spec", "defaultBackend"); backend != nil {
+		backends = append(backends, backend)
+	}
+
+	if backend := getMap(o.RawYAML, "spec", "backend"); backend != nil {
+		backends = append(backends, backend)
+	}
+
+	for _, rule := range getSlice(o.RawYAML, "spec", "rules") {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, path := range getSlice(ruleMap, "http", "paths") {
+			if backend := getMap(asMap(path), "backend"); backend != nil {
+				backends = append(backends, backend)
+			}
+		}
+	}
+
+	for _, backend := range backends {
+		// networking.k8s.io/v1 nests the service, while older versions name it directly
+		name := getString(backend, "service", "name")
+		port := getString(backend, "service", "port", "number")
+
+		if port == "" {
+			port = getString(backend, "service", "port", "name")
+		}
+
+		if name == "" {
+			name = getString(backend, "serviceName")
+			port = getString(backend, "servicePort")
+		}
+
+		for _, target := range parsed.findObjects("Service", name, o.Namespace) {
+			parsed.addResourceRel(o.ID, target.ID, ResourceRelIngressBackend, port)
+		}
+	}
+}
+
+func (parsed *ParsedObjs) getServiceEndpointRels(o Object) {
+	selector := getMap(o.RawYAML, "spec", "selector")
+
+	// services without selectors are backed by manually managed endpoints
+	if len(selector) == 0 {
+		return
+	}
+
+	matchLabels := toMatchLabels(selector)
+
+	for _, pod := range parsed.Objects {
+		if pod.Kind != "Pod" || pod.Namespace != o.Namespace || !matchesSelector(pod, matchLabels, nil) {
+			continue
+		}
+
+		for _, port := range getSlice(o.RawYAML, "spec", "ports") {
+			portMap := asMap(port)
+			servicePort := getString(portMap, "port")
+			targetPort := getString(portMap, "targetPort")
+
+			if targetPort == "" {
+				targetPort = servicePort
+			}
+
+			if podServesPort(pod, targetPort) {
+				parsed.addResourceRel(o.ID, pod.ID, ResourceRelServiceEndpoint, fmt.Sprintf("%s->%s", servicePort, targetPort))
+			}
+		}
+	}
+}
+
+func (parsed *ParsedObjs) getStorageClassRels(o Object) {
+	storageClass := getString(o.RawYAML, "spec", "storageClassName")
+
+	for _, target := range parsed.findObjects("StorageClass", storageClass, "") {
+		parsed.addResourceRel(o.ID, target.ID, ResourceRelStorageClass, "")
+	}
+}
+
+func (parsed *ParsedObjs) getRoleBindingRels(o Object) {
+	for _, subject := range getSlice(o.RawYAML, "subjects") {
+		subjectMap := asMap(subject)
+
+		if getString(subjectMap, "kind") != "ServiceAccount" {
+			continue
+		}
+
+		namespace := getString(subjectMap, "namespace")
+		if namespace == "" {
+			namespace = o.Namespace
+		}
+
+		for _, sa := range parsed.findObjects("ServiceAccount", getString(subjectMap, "name"), namespace) {
+			parsed.addResourceRel(sa.ID, o.ID, ResourceRelRoleSubject, "")
+		}
+	}
+
+	kind := getString(o.RawYAML, "roleRef", "kind")
+
+	for _, role := range parsed.findObjects(kind, getString(o.RawYAML, "roleRef", "name"), o.Namespace) {
+		parsed.addResourceRel(o.ID, role.ID, ResourceRelRoleRef, "")
+	}
+}
+
+func (parsed *ParsedObjs) getConfigConsumerRels(o Object) {
+	for _, pod := range parsed.Objects {
+		if pod.Kind != "Pod" || pod.Namespace != o.Namespace {
+			continue
+		}
+
+		for _, via := range podConfigReferences(pod, o.Kind, o.Name) {
+			parsed.addResourceRel(o.ID, pod.ID, ResourceRelConfigConsumer, via)
+		}
+	}
+}
+
+func (parsed *ParsedObjs) getNetworkPolicyRels(o Object) {
+	podSelector := getMap(o.RawYAML, "spec", "podSelector")
+	matchLabels, matchExpressions := parseLabelSelector(podSelector)
+
+	for _, pod := range parsed.Objects {
+		if pod.Kind != "Pod" || pod.Namespace != o.Namespace {
+			continue
+		}
+
+		// an empty pod selector selects every pod in the namespace
+		if matchesSelector(pod, matchLabels, matchExpressions) {
+			parsed.addResourceRel(o.ID, pod.ID, ResourceRelNetworkPolicy, "")
+		}
+	}
+}
+
+// addResourceRel adds a relationship to both the source and the target, ignoring duplicates.
+func (parsed *ParsedObjs) addResourceRel(source, target int, relType ResourceRelType, detail string) {
+	rel := ResourceRel{
+		Relation: Relation{
+			Source: source,
+			Target: target,
+		},
+		Type:   relType,
+		Detail: detail,
+	}
+
+	for i, o := range parsed.Objects {
+		if o.ID != source && o.ID != target {
+			continue
+		}
+
+		if !containsResourceRel(o.Relations.ResourceRels, rel) {
+			parsed.Objects[i].Relations.ResourceRels = append(parsed.Objects[i].Relations.ResourceRels, rel)
+		}
+	}
+}
+
+func containsResourceRel(rels []ResourceRel, rel ResourceRel) bool {
+	for _, r := range rels {
+		if r == rel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clusterScopedKinds are kinds whose objects are not namespaced
+var clusterScopedKinds = map[string]bool{
+	"PersistentVolume":   true,
+	"StorageClass":       true,
+	"ClusterRole":        true,
+	"ClusterRoleBinding": true,
+	"Namespace":          true,
+}
+
+// findObjects returns the objects of a kind with a name. Namespaced objects must be in the
+// namespace as well.
+func (parsed *ParsedObjs) findObjects(kind, name, namespace string) []Object {
+	res := []Object{}
+
+	if kind == "" || name == "" {
+		return res
+	}
+
+	for _, o := range parsed.Objects {
+		if o.Kind != kind || o.Name != name {
+			continue
+		}
+
+		if !clusterScopedKinds[kind] && namespace != "" && o.Namespace != namespace {
+			continue
+		}
+
+		res = append(res, o)
+	}
+
+	return res
+}
+
+// podServesPort checks if a Pod serves a Service's target port. Named target ports must be
+// declared by a container, while numbered target ports are served even when undeclared.
+func podServesPort(pod Object, targetPort string) bool {
+	if targetPort == "" {
+		return false
+	}
+
+	declared := false
+
+	for _, container := range getSlice(pod.RawYAML, "spec", "containers") {
+		for _, port := range getSlice(asMap(container), "ports") {
+			portMap := asMap(port)
+			declared = true
+
+			if getString(portMap, "name") == targetPort || getString(portMap, "containerPort") == targetPort {
+				return true
+			}
+		}
+	}
+
+	return !declared && isNumeric(targetPort)
+}
+
+// podConfigReferences returns how a Pod consumes a ConfigMap or Secret, if at all.
+func podConfigReferences(pod Object, kind, name string) []string {
+	res := []string{}
+
+	refKey, keyRefKey, volumeKey, volumeNameKey := "configMapRef", "configMapKeyRef", "configMap", "name"
+	if kind == "Secret" {
+		refKey, keyRefKey, volumeKey, volumeNameKey = "secretRef", "secretKeyRef", "secret", "secretName"
+	}
+
+	addVia := func(via string) {
+		for _, r := range res {
+			if r == via {
+				return
+			}
+		}
+
+		res = append(res, via)
+	}
+
+	containers := []interface{}{}
+	containers = append(containers, getSlice(pod.RawYAML, "spec", "containers")...)
+	containers = append(containers, getSlice(pod.RawYAML, "spec", "initContainers")...)
+
+	for _, container := range containers {
+		containerMap := asMap(container)
+
+		for _, envFrom := range getSlice(containerMap, "envFrom") {
+			if getString(asMap(envFrom), refKey, "name") == name {
+				addVia("envFrom")
+			}
+		}
+
+		for _, env := range getSlice(containerMap, "env") {
+			if getString(asMap(env), "valueFrom", keyRefKey, "name") == name {
+				addVia("env")
+			}
+		}
+	}
+
+	for _, volume := range getSlice(pod.RawYAML, "spec", "volumes") {
+		volumeMap := asMap(volume)
+
+		if getString(volumeMap, volumeKey, volumeNameKey) == name {
+			addVia("volume")
+		}
+
+		for _, source := range getSlice(volumeMap, "projected", "sources") {
+			if getString(asMap(source), volumeKey, "name") == name {
+				addVia("volume")
+			}
+		}
+	}
+
+	if kind == "Secret" {
+		for _, secret := range getSlice(pod.RawYAML, "spec", "imagePullSecrets") {
+			if getString(asMap(secret), "name") == name {
+				addVia("imagePullSecrets")
+			}
+		}
+	}
+
+	return res
+}
+
+// parseLabelSelector reads a metav1.LabelSelector.
+func parseLabelSelector(selector map[string]interface{}) ([]MatchLabel, []MatchExpression) {
+	matchLabels := toMatchLabels(getMap(selector, "matchLabels"))
+	matchExpressions := []MatchExpression{}
+
+	for _, expr := range getSlice(selector, "matchExpressions") {
+		exprMap := asMap(expr)
+		values := []string{}
+
+		for _, val := range getSlice(exprMap, "values") {
+			values = append(values, fmt.Sprint(val))
+		}
+
+		matchExpressions = append(matchExpressions, MatchExpression{
+			key:      getString(exprMap, "key"),
+			operator: getString(exprMap, "operator"),
+			values:   values,
+		})
+	}
+
+	return matchLabels, matchExpressions
+}
+
+// toMatchLabels is like addMatchLabels, but tolerates label values which are not strings.
+func toMatchLabels(ml map[string]interface{}) []MatchLabel {
+	matchLabels := []MatchLabel{}
+
+	for k, v := range ml {
+		matchLabels = append(matchLabels, MatchLabel{
+			key:   k,
+			value: fmt.Sprint(v),
+		})
+	}
+
+	return matchLabels
+}
+
+// matchesSelector checks the labels of an object against equality and set-based selectors.
+func matchesSelector(o Object, ml []MatchLabel, me []MatchExpression) bool {
+	labels := getMap(o.RawYAML, "metadata", "labels")
+
+	for _, l := range ml {
+		if fmt.Sprint(labels[l.key]) != l.value {
+			return false
+		}
+	}
+
+	for _, e := range me {
+		val, exists := labels[e.key]
+
+		switch e.operator {
+		case "In":
+			if !exists || !containsString(e.values, fmt.Sprint(val)) {
+				return false
+			}
+		case "NotIn":
+			if exists && containsString(e.values, fmt.Sprint(val)) {
+				return false
+			}
+		case "Exists":
+			if !exists {
+				return false
+			}
+		case "DoesNotExist":
+			if exists {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsString(arr []string, str string) bool {
+	for _, s := range arr {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isNumeric(str string) bool {
+	for _, c := range str {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return str != ""
+}
+
+// getMap, getSlice and getString are like getField, but return zero values rather than
+// panicking when a field is missing or has an unexpected type.
+func getMap(yaml map[string]interface{}, keys ...string) map[string]interface{} {
+	m, _ := lookupField(yaml, keys...).(map[string]interface{})
+	return m
+}
+
+func getSlice(yaml map[string]interface{}, keys ...string) []interface{} {
+	s, _ := lookupField(yaml, keys...).([]interface{})
+	return s
+}
+
+func getString(yaml map[string]interface{}, keys ...string) string {
+	switch val := lookupField(yaml, keys...).(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case map[string]interface{}, []interface{}:
+		return ""
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func lookupField(yaml map[string]interface{}, keys ...string) interface{} {
+	var curr interface{} = yaml
+
+	for _, key := range keys {
+		m, ok := curr.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		curr = m[key]
+	}
+
+	return curr
+}
+
+func asMap(val interface{}) map[string]interface{} {
+	m, _ := val.(map[string]interface{})
+	return m
+}
@@ -0,0 +1,75 @@
+package grapher_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/karagatandev/porter/internal/helm/grapher"
+)
+
+func parseTestFile(t *testing.T, filePath string) grapher.ParsedObjs {
+	file, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Error reading file %s", filePath)
+	}
+
+	yamlArr := grapher.ImportMultiDocYAML(file)
+	objects := grapher.ParseObjs(yamlArr, "default")
+	parsed := grapher.ParsedObjs{
+		Objects: objects,
+	}
+
+	parsed.GetControlRel()
+	parsed.GetLabelRel()
+	parsed.GetSpecRel()
+	parsed.GetResourceRel()
+
+	return parsed
+}
+
+func TestResourceRels(t *testing.T) {
+	parsed := parseTestFile(t, "./test_yaml/resources.yaml")
+
+	// the generated pod of the web deployment is added after the objects in the yaml
+	const pod = 13
+
+	expected := []grapher.ResourceRel{
+		{Relation: grapher.Relation{Source: 0, Target: 1}, Type: grapher.ResourceRelIngressBackend, Detail: "80"},
+		{Relation: grapher.Relation{Source: 1, Target: pod}, Type: grapher.ResourceRelServiceEndpoint, Detail: "80->http"},
+		{Relation: grapher.Relation{Source: 3, Target: 2}, Type: grapher.ResourceRelScaleTarget},
+		{Relation: grapher.Relation{Source: 4, Target: pod}, Type: grapher.ResourceRelConfigConsumer, Detail: "envFrom"},
+		{Relation: grapher.Relation{Source: 4, Target: pod}, Type: grapher.ResourceRelConfigConsumer, Detail: "volume"},
+		{Relation: grapher.Relation{Source: 5, Target: pod}, Type: grapher.ResourceRelConfigConsumer, Detail: "env"},
+		{Relation: grapher.Relation{Source: 6, Target: 7}, Type: grapher.ResourceRelVolumeBinding},
+		{Relation: grapher.Relation{Source: 6, Target: 8}, Type: grapher.ResourceRelStorageClass},
+		{Relation: grapher.Relation{Source: 7, Target: 8}, Type: grapher.ResourceRelStorageClass},
+		{Relation: grapher.Relation{Source: 9, Target: 10}, Type: grapher.ResourceRelRoleSubject},
+		{Relation: grapher.Relation{Source: 10, Target: 11}, Type: grapher.ResourceRelRoleRef},
+		{Relation: grapher.Relation{Source: 12, Target: pod}, Type: grapher.ResourceRelNetworkPolicy},
+	}
+
+	got := map[grapher.ResourceRel]int{}
+
+	for _, o := range parsed.Objects {
+		for _, rel := range o.Relations.ResourceRels {
+			if rel.Source != o.ID && rel.Target != o.ID {
+				t.Errorf("%s %s holds relation %v which does not involve it", o.Kind, o.Name, rel)
+			}
+
+			got[rel]++
+		}
+	}
+
+	for _, rel := range expected {
+		// each relation is stored on both of the objects it connects
+		if got[rel] != 2 {
+			t.Errorf("Expected relation %v to be stored twice, got %d", rel, got[rel])
+		}
+
+		delete(got, rel)
+	}
+
+	for rel := range got {
+		t.Errorf("Unexpected relation %v", rel)
+	}
+}
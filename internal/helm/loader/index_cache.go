@@ -0,0 +1,269 @@
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/helm/pkg/repo"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// RepoIndexCacheRequests counts the lookups of repo index files by their result: "hit" when
+	// the cached index was fresh, "revalidated" when the repo reported it unchanged, "miss" when
+	// it was fetched and "stale" when the repo was unreachable and a previous copy was used
+	RepoIndexCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "porter",
+		Subsystem: "helm_repo_index_cache",
+		Name:      "requests_total",
+		Help:      "Lookups of Helm repo index files by result.",
+	}, []string{"result"})
+
+	// RepoIndexFetchFailures counts the failed fetches of repo index files by repo URL
+	RepoIndexFetchFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "porter",
+		Subsystem: "helm_repo_index_cache",
+		Name:      "fetch_failures_total",
+		Help:      "Failed fetches of Helm repo index files by repo.",
+	}, []string{"repo_url"})
+)
+
+func init() {
+	prometheus.MustRegister(RepoIndexCacheRequests, RepoIndexFetchFailures)
+}
+
+// DefaultRepoIndexTTL is how long a fetched index file is used before it is revalidated
+const DefaultRepoIndexTTL = 5 * time.Minute
+
+type repoIndexEntry struct {
+	index        *repo.IndexFile
+	etag         string
+	lastModified string
+	fetchedAt    time.Time
+}
+
+// RepoIndexCache caches the index files of Helm repos. Index files older than the TTL are
+// revalidated with the ETag and Last-Modified headers returned by the repo. When a repo is
+// unreachable, the last index fetched is used, and for public repos the index is also kept
+// on disk in Dir so that it survives restarts.
+type RepoIndexCache struct {
+	TTL time.Duration
+	Dir string
+
+	client *http.Client
+
+	mu      sync.Mutex
+	entries map[string]*repoIndexEntry
+}
+
+// NewRepoIndexCache returns a cache whose on-disk copies are kept in dir. Index files are not
+// kept on disk if dir is empty.
+func NewRepoIndexCache(ttl time.Duration, dir string) *RepoIndexCache {
+	return &RepoIndexCache{
+		TTL:     ttl,
+		Dir:     dir,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		entries: make(map[string]*repoIndexEntry),
+	}
+}
+
+var defaultRepoIndexCache = NewRepoIndexCache(DefaultRepoIndexTTL, filepath.Join(os.TempDir(), "porter-helm-repo-index"))
+
+// SetRepoIndexCache replaces the cache used by LoadRepoIndex
+func SetRepoIndexCache(cache *RepoIndexCache) {
+	defaultRepoIndexCache = cache
+}
+
+// Get returns the index file of a repo, fetching it if the cached copy is older than the TTL
+func (c *RepoIndexCache) Get(client *BasicAuthClient, repoURL string) (*repo.IndexFile, error) {
+	trimmedRepoURL := strings.TrimSuffix(strings.TrimSpace(repoURL), "/")
+	key := c.key(client, trimmedRepoURL)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && time.Since(entry.fetchedAt) < c.TTL {
+		RepoIndexCacheRequests.WithLabelValues("hit").Inc()
+		return copyRepoIndex(entry.index), nil
+	}
+
+	fetched, notModified, err := c.fetch(client, trimmedRepoURL, entry)
+	if err != nil {
+		RepoIndexFetchFailures.WithLabelValues(trimmedRepoURL).Inc()
+
+		if ok {
+			RepoIndexCacheRequests.WithLabelValues("stale").Inc()
+			return copyRepoIndex(entry.index), nil
+		}
+
+		if index, diskErr := c.readDisk(client, key); diskErr == nil {
+			RepoIndexCacheRequests.WithLabelValues("stale").Inc()
+			return index, nil
+		}
+
+		return nil, err
+	}
+
+	if notModified {
+		RepoIndexCacheRequests.WithLabelValues("revalidated").Inc()
+
+		fetched = &repoIndexEntry{
+			index:        entry.index,
+			etag:         entry.etag,
+			lastModified: entry.lastModified,
+			fetchedAt:    time.Now(),
+		}
+	} else {
+		RepoIndexCacheRequests.WithLabelValues("miss").Inc()
+	}
+
+	c.mu.Lock()
+	c.entries[key] = fetched
+	c.mu.Unlock()
+
+	return copyRepoIndex(fetched.index), nil
+}
+
+// fetch downloads the index file of a repo. If the repo reports that the cached entry is
+// unchanged, notModified is true and the returned entry is nil.
+func (c *RepoIndexCache) fetch(client *BasicAuthClient, repoURL string, cached *repoIndexEntry) (*repoIndexEntry, bool, error) {
+	req, err := http.NewRequest("GET", repoURL+"/index.yaml", nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if client.Username != "" {
+		req.SetBasicAuth(client.Username, client.Password)
+	}
+
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return nil, true, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("error fetching index of %s: unexpected status %s", repoURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	index, err := parseRepoIndex(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.writeDisk(client, c.key(client, repoURL), data)
+
+	return &repoIndexEntry{
+		index:        index,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		fetchedAt:    time.Now(),
+	}, false, nil
+}
+
+// key identifies the index of a repo for a set of credentials, so that the index of a
+// private repo is never returned to a client without its credentials
+func (c *RepoIndexCache) key(client *BasicAuthClient, repoURL string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{repoURL, client.Username, client.Password}, "\x00")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// writeDisk keeps the index of a public repo on disk. Failures are ignored since the disk
+// copy is only a fallback.
+func (c *RepoIndexCache) writeDisk(client *BasicAuthClient, key string, data []byte) {
+	if c.Dir == "" || client.Username != "" {
+		return
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return
+	}
+
+	// write to a temporary file first so that concurrent readers never see a partial index
+	tmpFile, err := os.CreateTemp(c.Dir, key+"-*.tmp")
+	if err != nil {
+		return
+	}
+
+	_, err = tmpFile.Write(data)
+
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), filepath.Join(c.Dir, key+".yaml"))
+	}
+
+	if err != nil {
+		os.Remove(tmpFile.Name())
+	}
+}
+
+func (c *RepoIndexCache) readDisk(client *BasicAuthClient, key string) (*repo.IndexFile, error) {
+	if c.Dir == "" || client.Username != "" {
+		return nil, fmt.Errorf("index is not kept on disk")
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.Dir, key+".yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRepoIndex(data)
+}
+
+func parseRepoIndex(data []byte) (*repo.IndexFile, error) {
+	index := &repo.IndexFile{}
+
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, err
+	}
+
+	index.SortEntries()
+
+	return index, nil
+}
+
+// copyRepoIndex copies the entries of a cached index, since callers sort them in place
+func copyRepoIndex(index *repo.IndexFile) *repo.IndexFile {
+	res := *index
+	res.Entries = make(map[string]repo.ChartVersions, len(index.Entries))
+
+	for name, versions := range index.Entries {
+		res.Entries[name] = append(repo.ChartVersions{}, versions...)
+	}
+
+	return &res
+}
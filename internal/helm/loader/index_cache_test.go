@@ -0,0 +1,95 @@
+package loader_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/karagatandev/porter/internal/helm/loader"
+)
+
+const index = `apiVersion: v1
+entries:
+  web:
+  - name: web
+    version: 0.2.0
+  - name: web
+    version: 0.1.0
+`
+
+func TestRepoIndexCacheRevalidates(t *testing.T) {
+	var fetches, notModified int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(index))
+	}))
+	defer server.Close()
+
+	cache := loader.NewRepoIndexCache(time.Hour, "")
+
+	for i := 0; i < 2; i++ {
+		res, err := cache.Get(&loader.BasicAuthClient{}, server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(res.Entries["web"]) != 2 {
+			t.Fatalf("expected 2 versions of web, got %d", len(res.Entries["web"]))
+		}
+	}
+
+	if fetches != 1 {
+		t.Errorf("expected a fresh index to be served from the cache, got %d fetches", fetches)
+	}
+
+	cache.TTL = 0
+
+	if _, err := cache.Get(&loader.BasicAuthClient{}, server.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if notModified != 1 {
+		t.Errorf("expected an expired index to be revalidated")
+	}
+}
+
+func TestRepoIndexCacheFallsBackToDisk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(index))
+	}))
+
+	dir := t.TempDir()
+
+	if _, err := loader.NewRepoIndexCache(0, dir).Get(&loader.BasicAuthClient{}, server.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server.Close()
+
+	// a new cache has no index in memory, so the one written to disk is used
+	res, err := loader.NewRepoIndexCache(0, dir).Get(&loader.BasicAuthClient{}, server.URL)
+	if err != nil {
+		t.Fatalf("expected the index on disk to be used, got %v", err)
+	}
+
+	if len(res.Entries["web"]) != 2 {
+		t.Errorf("expected 2 versions of web, got %d", len(res.Entries["web"]))
+	}
+
+	// the index of a private repo is never kept on disk
+	_, err = loader.NewRepoIndexCache(0, dir).Get(&loader.BasicAuthClient{Username: "user", Password: "pass"}, server.URL)
+	if err == nil {
+		t.Errorf("expected an error for an unreachable private repo")
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/karagatandev/porter/internal/telemetry"
 
 	"k8s.io/helm/pkg/repo"
-	"sigs.k8s.io/yaml"
 
 	"github.com/karagatandev/porter/api/types"
 	"github.com/stefanmcshane/helm/pkg/chart"
@@ -80,45 +79,20 @@ func FindPorterChartInIndexList(index *repo.IndexFile, name string) *types.Porte
 type BasicAuthClient struct {
 	Username string
 	Password string
+
+	// DockerConfigJSON holds the credentials of OCI registries as the contents of a docker
+	// config file. It takes precedence over the username and password for oci:// repos.
+	DockerConfigJSON []byte
 }
 
-// LoadRepoIndex uses an http request to get the index file and loads it
+// LoadRepoIndex gets the index file of a repo from the repo index cache, fetching it if it is
+// stale. For OCI repos, the index is built from the tags of the chart the repo URL refers to.
 func LoadRepoIndex(client *BasicAuthClient, repoURL string) (*repo.IndexFile, error) {
-	trimmedRepoURL := strings.TrimSuffix(strings.TrimSpace(repoURL), "/")
-	indexURL := trimmedRepoURL + "/index.yaml"
-
-	req, err := http.NewRequest("GET", indexURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if client.Username != "" {
-		req.SetBasicAuth(client.Username, client.Password)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	if IsOCIRepoURL(repoURL) {
+		return LoadOCIRepoIndex(context.Background(), client, repoURL)
 	}
 
-	// index not found in the cache, parse it
-	index := &repo.IndexFile{}
-	err = yaml.Unmarshal(data, index)
-
-	if err != nil {
-		return index, err
-	}
-
-	index.SortEntries()
-
-	return index, nil
+	return defaultRepoIndexCache.Get(client, repoURL)
 }
 
 // LoadRepoIndexPublic loads an index file from a remote public Helm repo
@@ -137,6 +111,10 @@ func LoadChart(ctx context.Context, client *BasicAuthClient, repoURL, chartName,
 		telemetry.AttributeKV{Key: "chart-version", Value: chartVersion},
 	)
 
+	if IsOCIRepoURL(repoURL) {
+		return LoadOCIChart(ctx, client, repoURL, chartName, chartVersion)
+	}
+
 	repoIndex, err := LoadRepoIndex(client, repoURL)
 	if err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error loading repo index")
@@ -0,0 +1,218 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/karagatandev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/chart"
+	chartloader "github.com/stefanmcshane/helm/pkg/chart/loader"
+	"github.com/stefanmcshane/helm/pkg/registry"
+	hapichart "k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/repo"
+)
+
+// OCIScheme is the scheme of the URLs of charts stored as OCI artifacts
+const OCIScheme = "oci://"
+
+// IsOCIRepoURL returns true if the repo URL refers to an OCI registry rather than to a
+// classic Helm repo serving an index.yaml
+func IsOCIRepoURL(repoURL string) bool {
+	return strings.HasPrefix(strings.TrimSpace(repoURL), OCIScheme)
+}
+
+// OCIChartRef returns the reference of a chart in an OCI registry, without its scheme or tag.
+// OCI registries can't be browsed like an index.yaml, so a repo URL either refers to the
+// namespace the charts are pushed to, such as oci://registry.example.com/charts, or to the
+// repository of a single chart, such as oci://registry.example.com/charts/web.
+func OCIChartRef(repoURL, chartName string) string {
+	ref := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(repoURL), OCIScheme), "/")
+
+	if chartName == "" || path.Base(ref) == chartName {
+		return ref
+	}
+
+	return ref + "/" + chartName
+}
+
+// dockerConfigJSON returns the credentials of the client as the contents of a docker config
+// file, which is how Helm's registry client reads credentials
+func (c *BasicAuthClient) dockerConfigJSON(host string) ([]byte, error) {
+	if len(c.DockerConfigJSON) > 0 {
+		return c.DockerConfigJSON, nil
+	}
+
+	auths := map[string]interface{}{}
+
+	if c.Username != "" {
+		auths[host] = map[string]string{
+			"auth": base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password)),
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"auths": auths,
+	})
+}
+
+// newOCIRegistryClient returns a Helm registry client authenticated with the client's
+// credentials. The returned function removes the credentials file and must be called once
+// the registry client is no longer used.
+func newOCIRegistryClient(client *BasicAuthClient, ref string) (*registry.Client, func(), error) {
+	host := strings.SplitN(ref, "/", 2)[0]
+
+	data, err := client.dockerConfigJSON(host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credsFile, err := os.CreateTemp("", "porter-oci-*.json")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanup := func() {
+		os.Remove(credsFile.Name())
+	}
+
+	_, err = credsFile.Write(data)
+
+	if closeErr := credsFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	regClient, err := registry.NewClient(registry.ClientOptCredentialsFile(credsFile.Name()))
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	return regClient, cleanup, nil
+}
+
+// ListOCIChartVersions lists the semver tags of a chart in an OCI registry, from the latest
+func ListOCIChartVersions(client *BasicAuthClient, repoURL, chartName string) ([]string, error) {
+	ref := OCIChartRef(repoURL, chartName)
+
+	regClient, cleanup, err := newOCIRegistryClient(client, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cleanup()
+
+	return regClient.Tags(ref)
+}
+
+// LoadOCIRepoIndex builds an index file for charts in an OCI registry by listing their tags,
+// so that OCI repos can be browsed like classic Helm repos. When no chart names are given,
+// the repo URL must refer to the repository of a single chart. The description and icon of
+// each chart are read from its latest version.
+func LoadOCIRepoIndex(ctx context.Context, client *BasicAuthClient, repoURL string, chartNames ...string) (*repo.IndexFile, error) {
+	if len(chartNames) == 0 {
+		chartNames = []string{path.Base(OCIChartRef(repoURL, ""))}
+	}
+
+	index := repo.NewIndexFile()
+
+	for _, chartName := range chartNames {
+		ref := OCIChartRef(repoURL, chartName)
+
+		versions, err := ListOCIChartVersions(client, repoURL, chartName)
+		if err != nil {
+			return nil, fmt.Errorf("error listing tags of %s: %w", ref, err)
+		}
+
+		if len(versions) == 0 {
+			continue
+		}
+
+		latest := &hapichart.Metadata{
+			Name:    chartName,
+			Version: versions[0],
+		}
+
+		if ch, err := LoadOCIChart(ctx, client, repoURL, chartName, versions[0]); err == nil && ch.Metadata != nil {
+			latest.Description = ch.Metadata.Description
+			latest.Icon = ch.Metadata.Icon
+			latest.Keywords = ch.Metadata.Keywords
+		}
+
+		for i, version := range versions {
+			md := &hapichart.Metadata{
+				Name:    chartName,
+				Version: version,
+			}
+
+			if i == 0 {
+				md = latest
+			}
+
+			index.Entries[chartName] = append(index.Entries[chartName], &repo.ChartVersion{
+				Metadata: md,
+				URLs:     []string{OCIScheme + ref + ":" + version},
+			})
+		}
+	}
+
+	index.SortEntries()
+
+	return index, nil
+}
+
+// LoadOCIChart pulls a chart from an OCI registry. If chartVersion is an empty string, the
+// latest semver tag is pulled.
+func LoadOCIChart(ctx context.Context, client *BasicAuthClient, repoURL, chartName, chartVersion string) (*chart.Chart, error) {
+	ctx, span := telemetry.NewSpan(ctx, "load-oci-chart")
+	defer span.End()
+
+	ref := OCIChartRef(repoURL, chartName)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "chart-ref", Value: ref},
+		telemetry.AttributeKV{Key: "chart-version", Value: chartVersion},
+	)
+
+	regClient, cleanup, err := newOCIRegistryClient(client, ref)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating registry client")
+	}
+
+	defer cleanup()
+
+	if chartVersion == "" {
+		versions, err := regClient.Tags(ref)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error listing chart tags")
+		}
+
+		if len(versions) == 0 {
+			return nil, telemetry.Error(ctx, span, nil, fmt.Sprintf("%s has no semver tags", ref))
+		}
+
+		chartVersion = versions[0]
+	}
+
+	// tags can't contain a plus sign, so Helm pushes versions with build metadata with an
+	// underscore instead
+	res, err := regClient.Pull(
+		fmt.Sprintf("%s:%s", ref, strings.ReplaceAll(chartVersion, "+", "_")),
+		registry.PullOptWithChart(true),
+	)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error pulling chart")
+	}
+
+	return chartloader.LoadArchive(bytes.NewReader(res.Chart.Data))
+}
@@ -0,0 +1,30 @@
+package loader_test
+
+import (
+	"testing"
+
+	"github.com/karagatandev/porter/internal/helm/loader"
+)
+
+func TestOCIChartRef(t *testing.T) {
+	tests := []struct {
+		repoURL   string
+		chartName string
+		expected  string
+	}{
+		{"oci://123456789.dkr.ecr.us-east-1.amazonaws.com/charts", "web", "123456789.dkr.ecr.us-east-1.amazonaws.com/charts/web"},
+		{"oci://us-docker.pkg.dev/project/charts/", "web", "us-docker.pkg.dev/project/charts/web"},
+		{"oci://us-docker.pkg.dev/project/charts/web", "web", "us-docker.pkg.dev/project/charts/web"},
+		{"oci://us-docker.pkg.dev/project/charts/web", "", "us-docker.pkg.dev/project/charts/web"},
+	}
+
+	for _, tt := range tests {
+		if ref := loader.OCIChartRef(tt.repoURL, tt.chartName); ref != tt.expected {
+			t.Errorf("OCIChartRef(%s, %s): expected %s, got %s", tt.repoURL, tt.chartName, tt.expected, ref)
+		}
+	}
+
+	if loader.IsOCIRepoURL("https://charts.getporter.dev") || !loader.IsOCIRepoURL(" oci://ghcr.io/porter/charts") {
+		t.Errorf("unexpected result of IsOCIRepoURL")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/karagatandev/porter/internal/helm/deprecation"
 	"github.com/karagatandev/porter/internal/kubernetes"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/repository"
@@ -20,9 +21,13 @@ import (
 	"github.com/docker/distribution/reference"
 )
 
+// PorterPostrenderer runs Porter's own post-renderers, followed by the manifest patches
+// defined by the users of the project
 type PorterPostrenderer struct {
+	DeprecatedAPIPostrenderer       *DeprecatedAPIPostrenderer
 	DockerSecretsPostRenderer       *DockerSecretsPostRenderer
 	EnvironmentVariablePostrenderer *EnvironmentVariablePostrenderer
+	ManifestPatchPostrenderer       *ManifestPatchPostrenderer
 }
 
 func NewPorterPostrenderer(
@@ -33,6 +38,7 @@ func NewPorterPostrenderer(
 	regs []*models.Registry,
 	doAuth *oauth2.Config,
 	disablePullSecretsInjection bool,
+	patches []*models.ManifestPatch,
 ) (postrender.PostRenderer, error) {
 	var dockerSecretsPostrenderer *DockerSecretsPostRenderer
 	var err error
@@ -50,15 +56,35 @@ func NewPorterPostrenderer(
 		return nil, err
 	}
 
+	var deprecatedAPIPostrenderer *DeprecatedAPIPostrenderer
+
+	// the manifests are only checked when the cluster's APIs can be discovered, leaving
+	// removed APIs for Helm to report otherwise
+	if agent != nil {
+		if served, err := agent.GetServedAPIs(); err == nil {
+			deprecatedAPIPostrenderer = NewDeprecatedAPIPostrenderer(served)
+		}
+	}
+
 	return &PorterPostrenderer{
+		DeprecatedAPIPostrenderer:       deprecatedAPIPostrenderer,
 		DockerSecretsPostRenderer:       dockerSecretsPostrenderer,
 		EnvironmentVariablePostrenderer: envVarPostrenderer,
+		ManifestPatchPostrenderer:       NewManifestPatchPostrenderer(patches),
 	}, nil
 }
 
 func (p *PorterPostrenderer) Run(
 	renderedManifests *bytes.Buffer,
 ) (modifiedManifests *bytes.Buffer, err error) {
+	if p.DeprecatedAPIPostrenderer != nil {
+		renderedManifests, err = p.DeprecatedAPIPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if p.DockerSecretsPostRenderer != nil {
 		renderedManifests, err = p.DockerSecretsPostRenderer.Run(renderedManifests)
 
@@ -68,6 +94,13 @@ func (p *PorterPostrenderer) Run(
 	}
 
 	renderedManifests, err = p.EnvironmentVariablePostrenderer.Run(renderedManifests)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.ManifestPatchPostrenderer != nil {
+		renderedManifests, err = p.ManifestPatchPostrenderer.Run(renderedManifests)
+	}
 
 	return renderedManifests, err
 }
@@ -827,7 +860,12 @@ func getRegNameFromImageRef(image string) (string, error) {
 	return regName, nil
 }
 
-type DeprecatedAPIVersionMapper struct{}
+// DeprecatedAPIVersionMapper updates the API versions of the objects of a deployed revision
+// to the ones used by the new manifest. When Served is set, the objects which are not in the
+// new manifest and use API versions removed from the cluster are migrated as well.
+type DeprecatedAPIVersionMapper struct {
+	Served *deprecation.ServedAPIs
+}
 
 type APIVersionKind struct {
 	oldAPIVersion, newAPIVersion, oldKind, newKind string
@@ -921,6 +959,10 @@ func (d *DeprecatedAPIVersionMapper) Run(
 		}
 	}
 
+	if d.Served != nil {
+		return bytes.NewBufferString(deprecation.DefaultTable.Rewrite(modifiedManifests.String(), d.Served)), nil
+	}
+
 	return modifiedManifests, nil
 }
 
@@ -0,0 +1,34 @@
+package helm
+
+import (
+	"bytes"
+
+	"github.com/karagatandev/porter/internal/helm/deprecation"
+)
+
+// DeprecatedAPIPostrenderer is a Helm post-renderer that checks the rendered manifests against
+// the APIs served by the cluster. Objects using removed API versions are migrated to their
+// replacement when only their apiVersion changed, and otherwise fail the install or upgrade
+// with an error describing the changes required.
+type DeprecatedAPIPostrenderer struct {
+	Served *deprecation.ServedAPIs
+	Table  deprecation.Table
+}
+
+func NewDeprecatedAPIPostrenderer(served *deprecation.ServedAPIs) *DeprecatedAPIPostrenderer {
+	return &DeprecatedAPIPostrenderer{
+		Served: served,
+		Table:  deprecation.DefaultTable,
+	}
+}
+
+func (d *DeprecatedAPIPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	manifest, _, err := d.Table.Check(renderedManifests.String(), d.Served)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBufferString(manifest), nil
+}
@@ -0,0 +1,244 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gopkg.in/yaml.v2"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// kustomizeRoot is the directory of the in-memory filesystem the manifests are patched in
+const kustomizeRoot = "/release"
+
+// ManifestPatchPostrenderer is a Helm post-renderer that applies user-defined kustomize
+// patches to the rendered manifests, in order. Strategic merge patches are merged into the
+// objects selected by their target, or into the object they name when they have no target.
+// JSON 6902 patches are applied to the objects selected by their target.
+type ManifestPatchPostrenderer struct {
+	Patches []*models.ManifestPatch
+}
+
+func NewManifestPatchPostrenderer(patches []*models.ManifestPatch) *ManifestPatchPostrenderer {
+	return &ManifestPatchPostrenderer{
+		Patches: patches,
+	}
+}
+
+func (m *ManifestPatchPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	if len(m.Patches) == 0 || len(bytes.TrimSpace(renderedManifests.Bytes())) == 0 {
+		return renderedManifests, nil
+	}
+
+	kustomization := map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  []string{"resources.yaml"},
+	}
+
+	patches := make([]map[string]interface{}, 0, len(m.Patches))
+
+	for _, patch := range m.Patches {
+		kPatch, err := getKustomizePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest patch %s: %w", patch.Name, err)
+		}
+
+		patches = append(patches, kPatch)
+	}
+
+	kustomization["patches"] = patches
+
+	kustomizationBytes, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := filesys.MakeFsInMemory()
+
+	if err := fs.WriteFile(kustomizeRoot+"/resources.yaml", renderedManifests.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if err := fs.WriteFile(kustomizeRoot+"/kustomization.yaml", kustomizationBytes); err != nil {
+		return nil, err
+	}
+
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, kustomizeRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error applying manifest patches: %w", err)
+	}
+
+	patched, err := resMap.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(patched), nil
+}
+
+// ValidateManifestPatch checks that a manifest patch can be passed to kustomize. Whether the
+// patch applies cleanly can only be checked by rendering a release with it.
+func ValidateManifestPatch(patch *models.ManifestPatch) error {
+	_, err := getKustomizePatch(patch)
+	return err
+}
+
+// getKustomizePatch returns the entry of the kustomization's patches for a manifest patch
+func getKustomizePatch(patch *models.ManifestPatch) (map[string]interface{}, error) {
+	target := patch.Target()
+	hasTarget := target != types.ManifestPatchTarget{}
+	patchBody := patch.Patch
+
+	switch types.ManifestPatchType(patch.Type) {
+	case types.ManifestPatchTypeStrategicMerge:
+		if hasTarget {
+			var err error
+
+			patchBody, err = addPlaceholderIdentity(patchBody, target)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case types.ManifestPatchTypeJSON6902:
+		if !hasTarget {
+			return nil, fmt.Errorf("json6902 patches must have a target")
+		}
+
+		ops := []interface{}{}
+
+		if err := yaml.Unmarshal([]byte(patchBody), &ops); err != nil {
+			return nil, fmt.Errorf("json6902 patches must be a list of operations: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported patch type %s", patch.Type)
+	}
+
+	kPatch := map[string]interface{}{
+		"patch": patchBody,
+	}
+
+	if hasTarget {
+		selector := map[string]string{}
+
+		for key, val := range map[string]string{
+			"group":              target.Group,
+			"version":            target.Version,
+			"kind":               target.Kind,
+			"name":               target.Name,
+			"namespace":          target.Namespace,
+			"labelSelector":      target.LabelSelector,
+			"annotationSelector": target.AnnotationSelector,
+		} {
+			if val != "" {
+				selector[key] = val
+			}
+		}
+
+		kPatch["target"] = selector
+	}
+
+	return kPatch, nil
+}
+
+// addPlaceholderIdentity fills in the kind and name of a strategic merge patch with a target.
+// Kustomize requires both to parse the patch, but ignores them when the patch has a target, so
+// that users only have to write the fields they change.
+func addPlaceholderIdentity(patchBody string, target types.ManifestPatchTarget) (string, error) {
+	obj := make(resource)
+
+	if err := yaml.Unmarshal([]byte(patchBody), &obj); err != nil {
+		return "", fmt.Errorf("strategic merge patches must be a Kubernetes object: %w", err)
+	}
+
+	if _, ok := obj["kind"]; !ok {
+		kind := target.Kind
+
+		if kind == "" {
+			kind = "Placeholder"
+		}
+
+		obj["kind"] = kind
+	}
+
+	metadata, ok := obj["metadata"].(map[interface{}]interface{})
+
+	if !ok {
+		metadata = make(map[interface{}]interface{})
+		obj["metadata"] = metadata
+	}
+
+	if _, ok := metadata["name"]; !ok {
+		metadata["name"] = "placeholder"
+	}
+
+	res, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+// applicationCharts are the charts of Porter applications, which patches that select neither an
+// app nor a chart apply to
+var applicationCharts = map[string]bool{
+	"web":    true,
+	"worker": true,
+	"job":    true,
+}
+
+// ListReleaseManifestPatches returns the patches of a project which apply to the release with
+// the given name and chart in a cluster, in the order they are applied
+func ListReleaseManifestPatches(
+	ctx context.Context,
+	repo repository.Repository,
+	projectID, clusterID uint,
+	releaseName, chartName string,
+) ([]*models.ManifestPatch, error) {
+	if repo == nil || repo.ManifestPatch() == nil {
+		return nil, nil
+	}
+
+	patches, err := repo.ManifestPatch().ListManifestPatchesByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*models.ManifestPatch, 0)
+
+	for _, patch := range patches {
+		if manifestPatchApplies(patch, clusterID, releaseName, chartName) {
+			res = append(res, patch)
+		}
+	}
+
+	return res, nil
+}
+
+func manifestPatchApplies(patch *models.ManifestPatch, clusterID uint, releaseName, chartName string) bool {
+	if patch.ClusterID != 0 && patch.ClusterID != clusterID {
+		return false
+	}
+
+	if patch.AppName != "" && patch.AppName != releaseName {
+		return false
+	}
+
+	if patch.ChartName != "" && patch.ChartName != chartName {
+		return false
+	}
+
+	if patch.AppName == "" && patch.ChartName == "" {
+		return applicationCharts[chartName]
+	}
+
+	return true
+}
@@ -0,0 +1,282 @@
+package helm_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/helm"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository/test"
+	"gopkg.in/yaml.v2"
+)
+
+const patchedManifests = `---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+---
+# Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`
+
+func newManifestPatch(name string, patchType types.ManifestPatchType, target types.ManifestPatchTarget, patch string) *models.ManifestPatch {
+	res := &models.ManifestPatch{
+		Name:  name,
+		Type:  string(patchType),
+		Patch: patch,
+	}
+
+	res.SetTarget(target)
+
+	return res
+}
+
+func decodeObjects(t *testing.T, manifests string) map[string]map[interface{}]interface{} {
+	t.Helper()
+
+	res := make(map[string]map[interface{}]interface{})
+
+	for _, doc := range strings.Split(manifests, "---") {
+		obj := make(map[interface{}]interface{})
+
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			t.Fatalf("error decoding manifests: %v", err)
+		}
+
+		if len(obj) == 0 {
+			continue
+		}
+
+		res[obj["kind"].(string)] = obj
+	}
+
+	return res
+}
+
+func TestManifestPatchPostrendererAppliesPatchesInOrder(t *testing.T) {
+	renderer := helm.NewManifestPatchPostrenderer([]*models.ManifestPatch{
+		newManifestPatch("sidecar", types.ManifestPatchTypeStrategicMerge, types.ManifestPatchTarget{Kind: "Deployment"}, `
+spec:
+  template:
+    spec:
+      containers:
+      - name: proxy
+        image: envoy
+      - name: web
+        securityContext:
+          runAsNonRoot: true
+`),
+		newManifestPatch("team-label", types.ManifestPatchTypeJSON6902, types.ManifestPatchTarget{LabelSelector: "app=web"}, `
+- op: add
+  path: /metadata/labels/team
+  value: payments
+`),
+		// replaces the label added by the previous patch, since patches run in order
+		newManifestPatch("team-label-override", types.ManifestPatchTypeJSON6902, types.ManifestPatchTarget{Kind: "Deployment", Name: "web"}, `
+- op: replace
+  path: /metadata/labels/team
+  value: billing
+`),
+		newManifestPatch("service-annotation", types.ManifestPatchTypeStrategicMerge, types.ManifestPatchTarget{}, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  annotations:
+    porter.run/patched: "true"
+`),
+	})
+
+	out, err := renderer.Run(bytes.NewBufferString(patchedManifests))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	objs := decodeObjects(t, out.String())
+
+	deployment, ok := objs["Deployment"]
+	if !ok {
+		t.Fatalf("expected the deployment in the patched manifests, got:\n%s", out.String())
+	}
+
+	labels := deployment["metadata"].(map[interface{}]interface{})["labels"].(map[interface{}]interface{})
+
+	if labels["team"] != "billing" {
+		t.Errorf("expected team label billing, got %v", labels["team"])
+	}
+
+	containers := deployment["spec"].(map[interface{}]interface{})["template"].(map[interface{}]interface{})["spec"].(map[interface{}]interface{})["containers"].([]interface{})
+
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(containers))
+	}
+
+	for _, container := range containers {
+		container := container.(map[interface{}]interface{})
+
+		switch container["name"] {
+		case "web":
+			if container["image"] != "nginx" {
+				t.Errorf("expected the web container to keep its image, got %v", container["image"])
+			}
+
+			if _, ok := container["securityContext"]; !ok {
+				t.Errorf("expected the web container to have a security context")
+			}
+		case "proxy":
+		default:
+			t.Errorf("unexpected container %v", container["name"])
+		}
+	}
+
+	service := objs["Service"]
+	annotations := service["metadata"].(map[interface{}]interface{})["annotations"].(map[interface{}]interface{})
+
+	if annotations["porter.run/patched"] != "true" {
+		t.Errorf("expected the service to be annotated, got %v", annotations)
+	}
+}
+
+func TestManifestPatchPostrendererWithoutPatches(t *testing.T) {
+	renderer := helm.NewManifestPatchPostrenderer(nil)
+
+	out, err := renderer.Run(bytes.NewBufferString(patchedManifests))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.String() != patchedManifests {
+		t.Errorf("expected the manifests to be unchanged, got:\n%s", out.String())
+	}
+}
+
+func TestValidateManifestPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   *models.ManifestPatch
+		wantErr bool
+	}{
+		{
+			name:  "strategic merge patch with target",
+			patch: newManifestPatch("a", types.ManifestPatchTypeStrategicMerge, types.ManifestPatchTarget{Kind: "Deployment"}, "spec:\n  replicas: 2\n"),
+		},
+		{
+			name:    "json6902 patch without target",
+			patch:   newManifestPatch("b", types.ManifestPatchTypeJSON6902, types.ManifestPatchTarget{}, "- op: remove\n  path: /spec/replicas\n"),
+			wantErr: true,
+		},
+		{
+			name:    "json6902 patch which is not a list",
+			patch:   newManifestPatch("c", types.ManifestPatchTypeJSON6902, types.ManifestPatchTarget{Kind: "Deployment"}, "op: remove\n"),
+			wantErr: true,
+		},
+		{
+			name:    "unknown patch type",
+			patch:   newManifestPatch("d", "merge", types.ManifestPatchTarget{Kind: "Deployment"}, "spec: {}\n"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := helm.ValidateManifestPatch(tt.patch)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestListReleaseManifestPatches(t *testing.T) {
+	repo := test.NewRepository(true)
+
+	patches := []*models.ManifestPatch{
+		{ProjectID: 1, Name: "apps", Priority: 1},
+		{ProjectID: 1, Name: "web-app", AppName: "web-app"},
+		{ProjectID: 1, Name: "ingress-nginx", ChartName: "ingress-nginx"},
+		{ProjectID: 1, Name: "cluster-2", ClusterID: 2},
+		{ProjectID: 1, Name: "web-app-in-cluster-1", AppName: "web-app", ClusterID: 1},
+		{ProjectID: 2, Name: "other-project"},
+	}
+
+	for _, patch := range patches {
+		if _, err := repo.ManifestPatch().CreateManifestPatch(context.Background(), patch); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		clusterID   uint
+		releaseName string
+		chartName   string
+		want        []string
+	}{
+		{
+			name:        "app release",
+			clusterID:   1,
+			releaseName: "web-app",
+			chartName:   "web",
+			want:        []string{"web-app", "web-app-in-cluster-1", "apps"},
+		},
+		{
+			name:        "app release in another cluster",
+			clusterID:   2,
+			releaseName: "web-app",
+			chartName:   "web",
+			want:        []string{"web-app", "cluster-2", "apps"},
+		},
+		{
+			name:        "selected chart",
+			clusterID:   1,
+			releaseName: "nginx",
+			chartName:   "ingress-nginx",
+			want:        []string{"ingress-nginx"},
+		},
+		{
+			name:        "system chart",
+			clusterID:   1,
+			releaseName: "porter-agent",
+			chartName:   "porter-agent",
+			want:        []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := helm.ListReleaseManifestPatches(context.Background(), repo, 1, tt.clusterID, tt.releaseName, tt.chartName)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			names := make([]string, 0, len(res))
+			for _, patch := range res {
+				names = append(names, patch.Name)
+			}
+
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected patches %v, got %v", tt.want, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/karagatandev/porter/internal/helm/loader"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/oauth"
+	"github.com/karagatandev/porter/internal/registry"
+	"github.com/karagatandev/porter/internal/repository"
+	"golang.org/x/oauth2"
+)
+
+// GetOCIAuthClient returns a client authenticated with the credentials of a registry of the
+// project, for pulling charts from an OCI repo. If registryID is 0, the registry with the same
+// host as the repo URL is used, and the client is anonymous when there is no such registry.
+// The installation token of a GHCR registry is refreshed first if githubApp is set; otherwise the
+// cached token is used, and the client is anonymous once it has expired.
+func GetOCIAuthClient(
+	ctx context.Context,
+	repo repository.Repository,
+	projectID, registryID uint,
+	repoURL string,
+	doAuth *oauth2.Config, // only required if using DOCR
+	githubApp *oauth.GithubAppConf, // only required to refresh the token of a GHCR registry
+) (*loader.BasicAuthClient, error) {
+	var reg *models.Registry
+
+	if registryID != 0 {
+		var err error
+
+		reg, err = repo.Registry().ReadRegistry(projectID, registryID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		regs, err := repo.Registry().ListRegistriesByProjectID(projectID)
+		if err != nil {
+			return nil, err
+		}
+
+		host := ociHost(repoURL)
+
+		for _, r := range regs {
+			if ociHost(r.URL) == host {
+				reg = r
+				break
+			}
+		}
+	}
+
+	if reg == nil {
+		return &loader.BasicAuthClient{}, nil
+	}
+
+	_reg := registry.Registry(*reg)
+
+	if _reg.GithubAppInstallationID != 0 && githubApp != nil {
+		if _, _, err := _reg.RefreshGHCRToken(ctx, repo, githubApp); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := _reg.GetDockerConfigJSON(repo, doAuth)
+	if err != nil {
+		if errors.Is(err, registry.ErrGHCRTokenExpired) {
+			return &loader.BasicAuthClient{}, nil
+		}
+
+		return nil, err
+	}
+
+	return &loader.BasicAuthClient{
+		DockerConfigJSON: data,
+	}, nil
+}
+
+// ociHost returns the host of a registry or OCI repo URL, which may have no scheme
+func ociHost(regURL string) string {
+	regURL = strings.TrimPrefix(strings.TrimSpace(regURL), loader.OCIScheme)
+
+	if !strings.Contains(regURL, "://") {
+		regURL = "https://" + regURL
+	}
+
+	parsed, err := url.Parse(regURL)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Host
+}
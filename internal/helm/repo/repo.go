@@ -21,6 +21,10 @@ func (hr *HelmRepo) ListCharts(repo repository.Repository) (types.ListTemplatesR
 		return hr.listChartsBasic(repo)
 	}
 
+	if loader.IsOCIRepoURL(hr.RepoURL) {
+		return hr.listChartsOCI(repo)
+	}
+
 	return nil, fmt.Errorf("error listing charts")
 }
 
@@ -33,6 +37,10 @@ func (hr *HelmRepo) GetChart(
 		return hr.getChartBasic(repo, chartName, chartVersion)
 	}
 
+	if loader.IsOCIRepoURL(hr.RepoURL) {
+		return hr.getChartOCI(repo, chartName, chartVersion)
+	}
+
 	return nil, fmt.Errorf("error listing charts")
 }
 
@@ -82,6 +90,34 @@ func (hr *HelmRepo) getChartBasic(
 	return loader.LoadChart(context.Background(), client, hr.RepoURL, chartName, chartVersion)
 }
 
+func (hr *HelmRepo) listChartsOCI(
+	repo repository.Repository,
+) (types.ListTemplatesResponse, error) {
+	client, err := GetOCIAuthClient(context.Background(), repo, hr.ProjectID, hr.RegistryID, hr.RepoURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	repoIndex, err := loader.LoadRepoIndex(client, hr.RepoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.RepoIndexToPorterChartList(repoIndex, hr.RepoURL), nil
+}
+
+func (hr *HelmRepo) getChartOCI(
+	repo repository.Repository,
+	chartName, chartVersion string,
+) (*chart.Chart, error) {
+	client, err := GetOCIAuthClient(context.Background(), repo, hr.ProjectID, hr.RegistryID, hr.RepoURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.LoadOCIChart(context.Background(), client, hr.RepoURL, chartName, chartVersion)
+}
+
 func ValidateRepoURL(
 	defaultAddonRepoURL, defaultAppRepoURL string,
 	hrs []*models.HelmRepo,
@@ -1,19 +1,57 @@
 package urlcache
 
-import "github.com/karagatandev/porter/internal/helm/loader"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/karagatandev/porter/internal/helm/loader"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// Lookups counts the chart lookups by their result, "hit" or "miss"
+	Lookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "porter",
+		Subsystem: "chart_url_cache",
+		Name:      "lookups_total",
+		Help:      "Lookups of chart repo URLs by result.",
+	}, []string{"result"})
+
+	// RefreshFailures counts the repos which could not be loaded when refreshing the cache
+	RefreshFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "porter",
+		Subsystem: "chart_url_cache",
+		Name:      "refresh_failures_total",
+		Help:      "Failed refreshes of chart repos by repo.",
+	}, []string{"repo_url"})
+)
+
+func init() {
+	prometheus.MustRegister(Lookups, RefreshFailures)
+}
 
 // ChartLookupURLs contains an in-memory store of Porter chart names matched with
 // a repo URL, so that finding a chart does not involve multiple lookups to our
 // chart repo's index.yaml file
 type ChartURLCache struct {
+	mu    sync.RWMutex
 	cache map[string]string
-	urls  []string
+
+	// repoCharts are the charts of each repo as of its last successful load, so that the
+	// charts of a repo which is temporarily unreachable are kept
+	repoCharts map[string][]string
+
+	urls []string
 }
 
 func Init(urls ...string) *ChartURLCache {
 	res := &ChartURLCache{
-		cache: make(map[string]string),
-		urls:  urls,
+		cache:      make(map[string]string),
+		repoCharts: make(map[string][]string),
+		urls:       urls,
 	}
 
 	res.Update()
@@ -21,25 +59,77 @@ func Init(urls ...string) *ChartURLCache {
 	return res
 }
 
-func (c *ChartURLCache) Update() {
-	newCharts := make(map[string]string)
+// Update reloads the charts of every repo. Repos which can't be loaded keep the charts from
+// their last successful load, and the errors loading them are returned.
+func (c *ChartURLCache) Update() error {
+	var errs []error
+
+	loaded := make(map[string][]string)
 
 	for _, chartRepo := range c.urls {
 		indexFile, err := loader.LoadRepoIndexPublic(chartRepo)
 		if err != nil {
+			RefreshFailures.WithLabelValues(chartRepo).Inc()
+			errs = append(errs, fmt.Errorf("error loading chart repo %s: %w", chartRepo, err))
+
 			continue
 		}
 
+		charts := make([]string, 0, len(indexFile.Entries))
+
 		for chartName := range indexFile.Entries {
+			charts = append(charts, chartName)
+		}
+
+		loaded[chartRepo] = charts
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for chartRepo, charts := range loaded {
+		c.repoCharts[chartRepo] = charts
+	}
+
+	newCharts := make(map[string]string)
+
+	// later repos take precedence when several repos have a chart with the same name
+	for _, chartRepo := range c.urls {
+		for _, chartName := range c.repoCharts[chartRepo] {
 			newCharts[chartName] = chartRepo
 		}
 	}
 
 	c.cache = newCharts
+
+	return errors.Join(errs...)
 }
 
 func (c *ChartURLCache) GetURL(chartName string) (string, bool) {
+	c.mu.RLock()
 	res, ok := c.cache[chartName]
+	c.mu.RUnlock()
+
+	if ok {
+		Lookups.WithLabelValues("hit").Inc()
+	} else {
+		Lookups.WithLabelValues("miss").Inc()
+	}
 
 	return res, ok
 }
+
+// Run updates the cache every interval until ctx is cancelled
+func (c *ChartURLCache) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Update()
+		}
+	}
+}
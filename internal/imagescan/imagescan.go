@@ -0,0 +1,127 @@
+// Package imagescan scans container images for known vulnerabilities and decides whether
+// images may be deployed under a project's scan policy.
+package imagescan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Severity is the severity of a vulnerability, as assigned by the scanner's vulnerability database
+type Severity string
+
+const (
+	SeverityUnknown  Severity = "unknown"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Severities lists the severities from the least to the most severe
+var Severities = []Severity{SeverityUnknown, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// ErrImageNotFound is returned by scanners when the image does not exist
+var ErrImageNotFound = errors.New("image not found")
+
+// ParseSeverity parses a severity case-insensitively, returning SeverityUnknown for
+// severities which aren't recognized, such as "negligible"
+func ParseSeverity(s string) Severity {
+	sev := Severity(strings.ToLower(s))
+
+	if sev.rank() < 0 {
+		return SeverityUnknown
+	}
+
+	return sev
+}
+
+func (s Severity) rank() int {
+	for i, sev := range Severities {
+		if sev == s {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// AtLeast returns true if s is as severe as or more severe than other
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() >= other.rank()
+}
+
+// Finding is a vulnerability found in a package installed in an image
+type Finding struct {
+	// ID is the identifier of the vulnerability, such as CVE-2023-4863
+	ID               string   `json:"id"`
+	Package          string   `json:"package"`
+	InstalledVersion string   `json:"installed_version"`
+	FixedVersion     string   `json:"fixed_version,omitempty"`
+	Severity         Severity `json:"severity"`
+	Title            string   `json:"title,omitempty"`
+}
+
+// Report is the result of scanning an image
+type Report struct {
+	// Digest is the digest of the image manifest which was scanned
+	Digest    string
+	Scanner   string
+	ScannedAt time.Time
+	Findings  []Finding
+}
+
+// Counts returns the number of findings of each severity
+func (r *Report) Counts() map[Severity]int {
+	counts := make(map[Severity]int, len(Severities))
+
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+	}
+
+	return counts
+}
+
+// FindingsAtLeast returns the findings which are at least as severe as the threshold
+func (r *Report) FindingsAtLeast(threshold Severity) []Finding {
+	var res []Finding
+
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(threshold) {
+			res = append(res, f)
+		}
+	}
+
+	return res
+}
+
+// ImageRef identifies the image to scan
+type ImageRef struct {
+	// Repository is the image repository, such as 123456789012.dkr.ecr.us-east-2.amazonaws.com/web
+	Repository string
+	Tag        string
+	// Digest is used instead of the tag if it is set
+	Digest string
+
+	// DockerConfigJSON is a docker config file with credentials for the image's registry
+	DockerConfigJSON []byte
+}
+
+// String returns the reference to the image by digest if it is known, and by tag otherwise
+func (i ImageRef) String() string {
+	if i.Digest != "" {
+		return fmt.Sprintf("%s@%s", i.Repository, i.Digest)
+	}
+
+	return fmt.Sprintf("%s:%s", i.Repository, i.Tag)
+}
+
+// Scanner scans images for known vulnerabilities
+type Scanner interface {
+	// Name identifies the scanner in reports
+	Name() string
+	Scan(ctx context.Context, image ImageRef) (*Report, error)
+}
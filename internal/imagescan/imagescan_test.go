@@ -0,0 +1,125 @@
+package imagescan
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	report := &Report{
+		Digest: "sha256:abc",
+		Findings: []Finding{
+			{ID: "CVE-2023-0001", Severity: SeverityLow},
+			{ID: "CVE-2023-0002", Severity: SeverityHigh},
+			{ID: "CVE-2023-0003", Severity: SeverityCritical},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		policy      Policy
+		report      *Report
+		blocked     bool
+		message     string
+		emptyReason bool
+	}{
+		{
+			name:    "block at high",
+			policy:  Policy{Action: ActionBlock, Threshold: SeverityHigh},
+			report:  report,
+			blocked: true,
+			message: "2 vulnerabilities of severity high or higher: CVE-2023-0002, CVE-2023-0003",
+		},
+		{
+			name:    "warn at critical",
+			policy:  Policy{Action: ActionWarn, Threshold: SeverityCritical},
+			report:  report,
+			message: "1 vulnerabilities of severity critical or higher: CVE-2023-0003",
+		},
+		{
+			name:        "below threshold",
+			policy:      Policy{Action: ActionBlock, Threshold: SeverityCritical},
+			report:      &Report{Findings: []Finding{{ID: "CVE-2023-0001", Severity: SeverityMedium}}},
+			emptyReason: true,
+		},
+		{
+			name:        "policy off",
+			policy:      Policy{Action: ActionOff, Threshold: SeverityLow},
+			report:      report,
+			emptyReason: true,
+		},
+		{
+			name:    "unscanned images are warned about but not blocked",
+			policy:  Policy{Action: ActionBlock, Threshold: SeverityLow},
+			message: "not been scanned",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := tt.policy.Evaluate(tt.report)
+
+			if decision.Blocked != tt.blocked {
+				t.Errorf("expected blocked to be %t, got %t", tt.blocked, decision.Blocked)
+			}
+
+			if tt.emptyReason && decision.Message != "" {
+				t.Errorf("expected no message, got %q", decision.Message)
+			}
+
+			if !strings.Contains(decision.Message, tt.message) {
+				t.Errorf("expected message to contain %q, got %q", tt.message, decision.Message)
+			}
+		})
+	}
+}
+
+func TestParseTrivyReport(t *testing.T) {
+	data := []byte(`{
+		"ArtifactName": "registry.example.com/web:v1",
+		"Metadata": {"RepoDigests": ["registry.example.com/web@sha256:abc"]},
+		"Results": [
+			{"Target": "debian", "Vulnerabilities": [
+				{"VulnerabilityID": "CVE-2023-4863", "PkgName": "libwebp7", "InstalledVersion": "1.2.4", "FixedVersion": "1.2.4-0.2", "Severity": "HIGH"},
+				{"VulnerabilityID": "CVE-2011-3374", "PkgName": "apt", "InstalledVersion": "2.6.1", "Severity": "NEGLIGIBLE"}
+			]},
+			{"Target": "app/package-lock.json"}
+		]
+	}`)
+
+	report, err := parseTrivyReport(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Digest != "sha256:abc" {
+		t.Errorf("expected digest sha256:abc, got %s", report.Digest)
+	}
+
+	counts := report.Counts()
+
+	if len(report.Findings) != 2 || counts[SeverityHigh] != 1 || counts[SeverityUnknown] != 1 {
+		t.Errorf("unexpected findings: %+v", report.Findings)
+	}
+}
+
+func TestLocalScanner(t *testing.T) {
+	scanner := NewLocalScanner()
+	scanner.AddImage("registry.example.com/web", "v1", "sha256:abc", Finding{ID: "CVE-2023-4863", Severity: SeverityHigh})
+
+	report, err := scanner.Scan(context.Background(), ImageRef{Repository: "registry.example.com/web", Tag: "v1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Digest != "sha256:abc" || len(report.Findings) != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	_, err = scanner.Scan(context.Background(), ImageRef{Repository: "registry.example.com/web", Tag: "v2"})
+	if !errors.Is(err, ErrImageNotFound) {
+		t.Errorf("expected an image not found error, got %v", err)
+	}
+}
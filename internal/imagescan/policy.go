@@ -0,0 +1,73 @@
+package imagescan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Action is what a policy does when an image exceeds its severity threshold
+type Action string
+
+const (
+	// ActionOff disables the policy
+	ActionOff Action = "off"
+	// ActionWarn allows the deploy, but warns about the vulnerabilities found
+	ActionWarn Action = "warn"
+	// ActionBlock rejects the deploy
+	ActionBlock Action = "block"
+)
+
+// maxListedFindings is the number of vulnerabilities named in a decision's message
+const maxListedFindings = 5
+
+// Policy decides whether images may be deployed based on the vulnerabilities found in them
+type Policy struct {
+	Action Action
+	// Threshold is the lowest severity of the vulnerabilities which the policy acts on
+	Threshold Severity
+}
+
+// Decision is the result of evaluating a policy against an image's scan report
+type Decision struct {
+	// Blocked is true if the image may not be deployed
+	Blocked bool
+	// Message explains why the image was blocked or warned about. It is empty if the image passed the policy.
+	Message string
+}
+
+// Evaluate decides whether the image with the given scan report may be deployed. A nil report
+// means that the image has not been scanned, which is warned about but never blocked, since
+// the scanner may be unavailable.
+func (p Policy) Evaluate(report *Report) Decision {
+	if p.Action == "" || p.Action == ActionOff {
+		return Decision{}
+	}
+
+	if report == nil {
+		return Decision{Message: "image has not been scanned for vulnerabilities"}
+	}
+
+	findings := report.FindingsAtLeast(p.Threshold)
+	if len(findings) == 0 {
+		return Decision{}
+	}
+
+	ids := make([]string, 0, maxListedFindings)
+
+	for i, f := range findings {
+		if i == maxListedFindings {
+			ids = append(ids, fmt.Sprintf("and %d more", len(findings)-maxListedFindings))
+			break
+		}
+
+		ids = append(ids, f.ID)
+	}
+
+	return Decision{
+		Blocked: p.Action == ActionBlock,
+		Message: fmt.Sprintf(
+			"image %s has %d vulnerabilities of severity %s or higher: %s",
+			report.Digest, len(findings), p.Threshold, strings.Join(ids, ", "),
+		),
+	}
+}
@@ -0,0 +1,138 @@
+package imagescan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TrivyScanner scans images with the trivy CLI. An SBOM attached to the image in its registry is
+// used if there is one, and the image layers are scanned otherwise.
+type TrivyScanner struct {
+	// Path is the path to the trivy binary
+	Path string
+}
+
+func NewTrivyScanner(path string) *TrivyScanner {
+	if path == "" {
+		path = "trivy"
+	}
+
+	return &TrivyScanner{path}
+}
+
+func (s *TrivyScanner) Name() string {
+	return "trivy"
+}
+
+func (s *TrivyScanner) Scan(ctx context.Context, image ImageRef) (*Report, error) {
+	// trivy reads registry credentials from the docker config in $DOCKER_CONFIG
+	dockerConfigDir, err := os.MkdirTemp("", "porter-trivy-")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dockerConfigDir)
+
+	if len(image.DockerConfigJSON) > 0 {
+		err = os.WriteFile(filepath.Join(dockerConfigDir, "config.json"), image.DockerConfigJSON, 0o600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	// #nosec G204 - the image reference is passed as a single argument
+	cmd := exec.CommandContext(
+		ctx, s.Path, "image",
+		"--quiet",
+		"--format", "json",
+		"--scanners", "vuln",
+		"--sbom-sources", "oci",
+		image.String(),
+	)
+	cmd.Env = append(os.Environ(), "DOCKER_CONFIG="+dockerConfigDir)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+
+		if strings.Contains(msg, "MANIFEST_UNKNOWN") || strings.Contains(msg, "NAME_UNKNOWN") {
+			return nil, fmt.Errorf("%w: %s", ErrImageNotFound, image)
+		}
+
+		return nil, fmt.Errorf("error scanning image %s: %s: %w", image, msg, err)
+	}
+
+	report, err := parseTrivyReport(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	report.Scanner = s.Name()
+
+	if image.Digest != "" {
+		report.Digest = image.Digest
+	}
+
+	return report, nil
+}
+
+type trivyReport struct {
+	Metadata struct {
+		RepoDigests []string `json:"RepoDigests"`
+	} `json:"Metadata"`
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+			Title            string `json:"Title"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// parseTrivyReport converts the JSON output of "trivy image" to a report
+func parseTrivyReport(data []byte) (*Report, error) {
+	var tr trivyReport
+
+	if err := json.Unmarshal(data, &tr); err != nil {
+		return nil, fmt.Errorf("error parsing trivy report: %w", err)
+	}
+
+	report := &Report{
+		ScannedAt: time.Now().UTC(),
+	}
+
+	// repo digests have the form repository@sha256:...
+	if len(tr.Metadata.RepoDigests) > 0 {
+		if _, digest, ok := strings.Cut(tr.Metadata.RepoDigests[0], "@"); ok {
+			report.Digest = digest
+		}
+	}
+
+	for _, result := range tr.Results {
+		for _, v := range result.Vulnerabilities {
+			report.Findings = append(report.Findings, Finding{
+				ID:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         ParseSeverity(v.Severity),
+				Title:            v.Title,
+			})
+		}
+	}
+
+	return report, nil
+}
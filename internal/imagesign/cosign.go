@@ -0,0 +1,178 @@
+package imagesign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CosignVerifier verifies images with the cosign CLI. Signatures are checked against each trusted
+// public key and keyless identity in turn, and attestations are checked against the first signer
+// whose signature is valid.
+type CosignVerifier struct {
+	// Path is the path to the cosign binary
+	Path string
+}
+
+func NewCosignVerifier(path string) *CosignVerifier {
+	if path == "" {
+		path = "cosign"
+	}
+
+	return &CosignVerifier{path}
+}
+
+func (v *CosignVerifier) Name() string {
+	return "cosign"
+}
+
+// cosignSigner is a trusted signer, with the cosign flags which verify against it
+type cosignSigner struct {
+	name string
+	args []string
+}
+
+func (v *CosignVerifier) Verify(ctx context.Context, image ImageRef, policy TrustPolicy) (*Verification, error) {
+	if policy.Empty() {
+		return nil, fmt.Errorf("%w: no signers are trusted", ErrNoValidSignature)
+	}
+
+	// cosign reads registry credentials from the docker config in $DOCKER_CONFIG
+	dir, err := os.MkdirTemp("", "porter-cosign-")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	if len(image.DockerConfigJSON) > 0 {
+		err = os.WriteFile(filepath.Join(dir, "config.json"), image.DockerConfigJSON, 0o600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signers, err := cosignSigners(dir, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []string
+
+	for _, signer := range signers {
+		out, err := v.run(ctx, dir, append([]string{"verify", "--output", "json"}, append(signer.args, image.String())...)...)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", signer.name, err))
+			continue
+		}
+
+		digest, err := parseCosignVerifyOutput(out)
+		if err != nil {
+			return nil, err
+		}
+
+		verification := &Verification{
+			Digest:     digest,
+			Signer:     signer.name,
+			VerifiedAt: time.Now().UTC(),
+		}
+
+		// attestations are verified against the digest which was signed, in case the tag has moved since
+		ref := ImageRef{Repository: image.Repository, Digest: digest}
+
+		for _, predicateType := range policy.Attestations {
+			args := append([]string{"verify-attestation", "--type", predicateType}, append(signer.args, ref.String())...)
+
+			if _, err := v.run(ctx, dir, args...); err != nil {
+				return nil, fmt.Errorf("%w: %s attestation by %s: %s", ErrMissingAttestation, predicateType, signer.name, err)
+			}
+
+			verification.Attestations = append(verification.Attestations, predicateType)
+		}
+
+		return verification, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoValidSignature, strings.Join(errs, "; "))
+}
+
+func (v *CosignVerifier) run(ctx context.Context, dockerConfigDir string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	// #nosec G204 - the arguments are passed to cosign individually
+	cmd := exec.CommandContext(ctx, v.Path, args...)
+	cmd.Env = append(os.Environ(), "DOCKER_CONFIG="+dockerConfigDir)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s", msg)
+		}
+
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// cosignSigners writes the trusted public keys to dir and returns the flags to verify against each signer
+func cosignSigners(dir string, policy TrustPolicy) ([]cosignSigner, error) {
+	var signers []cosignSigner
+
+	for i, key := range policy.PublicKeys {
+		path := filepath.Join(dir, fmt.Sprintf("key-%d.pub", i))
+
+		if err := os.WriteFile(path, []byte(key.PEM), 0o600); err != nil {
+			return nil, err
+		}
+
+		signers = append(signers, cosignSigner{
+			name: key.Name,
+			args: []string{"--key", path},
+		})
+	}
+
+	for _, identity := range policy.Identities {
+		signers = append(signers, cosignSigner{
+			name: identity.Subject,
+			args: []string{
+				"--certificate-identity-regexp", identity.SubjectPattern(),
+				"--certificate-oidc-issuer", identity.Issuer,
+			},
+		})
+	}
+
+	return signers, nil
+}
+
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// parseCosignVerifyOutput returns the digest covered by the signatures printed by "cosign verify --output json"
+func parseCosignVerifyOutput(data []byte) (string, error) {
+	var payloads []cosignPayload
+
+	if err := json.Unmarshal(bytes.TrimSpace(data), &payloads); err != nil {
+		return "", fmt.Errorf("error parsing cosign output: %w", err)
+	}
+
+	for _, p := range payloads {
+		if digest := p.Critical.Image.DockerManifestDigest; digest != "" {
+			return digest, nil
+		}
+	}
+
+	return "", fmt.Errorf("cosign output does not include the signed digest")
+}
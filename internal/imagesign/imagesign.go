@@ -0,0 +1,131 @@
+// Package imagesign verifies the signatures and attestations of container images against the
+// signers a project trusts, so images of unknown provenance can be kept out of deploys.
+package imagesign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoValidSignature is returned by verifiers when an image has no signature made by a trusted signer
+	ErrNoValidSignature = errors.New("image has no valid signature from a trusted signer")
+	// ErrMissingAttestation is returned by verifiers when a trusted signer has not attested to an image
+	// with every required predicate type
+	ErrMissingAttestation = errors.New("image is missing a required attestation")
+)
+
+// PublicKey is a cosign public key which images may be signed with
+type PublicKey struct {
+	// Name identifies the key in verification results
+	Name string `json:"name"`
+	// PEM is the PEM encoded public key
+	PEM string `json:"pem"`
+}
+
+// Identity is a keyless signing identity, as recorded in a Fulcio certificate
+type Identity struct {
+	// Issuer is the OIDC issuer of the identity, e.g. https://token.actions.githubusercontent.com
+	Issuer string `json:"issuer"`
+	// Subject is a regular expression which must match the whole of the identity's subject, e.g. a workflow ref
+	Subject string `json:"subject"`
+}
+
+// SubjectPattern returns the subject of the identity anchored to match the whole subject, so that a
+// subject such as https://github.com/acme/web does not also trust https://github.com/acme/web-fork
+func (i Identity) SubjectPattern() string {
+	return "^(?:" + i.Subject + ")$"
+}
+
+// TrustPolicy lists the signers whose signatures are trusted, and the attestations which images must have
+type TrustPolicy struct {
+	PublicKeys []PublicKey `json:"public_keys"`
+	Identities []Identity  `json:"identities"`
+	// Attestations are the predicate types which a trusted signer must have attested to, e.g. slsaprovenance
+	Attestations []string `json:"attestations"`
+}
+
+// Empty returns true if the policy trusts no signers
+func (p TrustPolicy) Empty() bool {
+	return len(p.PublicKeys) == 0 && len(p.Identities) == 0
+}
+
+// Validate checks that the keys and identities in the policy are usable
+func (p TrustPolicy) Validate() error {
+	for _, key := range p.PublicKeys {
+		if key.Name == "" {
+			return errors.New("public keys must have a name")
+		}
+
+		if !strings.Contains(key.PEM, "-----BEGIN PUBLIC KEY-----") {
+			return fmt.Errorf("public key %s is not a PEM encoded public key", key.Name)
+		}
+	}
+
+	for _, identity := range p.Identities {
+		if identity.Issuer == "" || identity.Subject == "" {
+			return errors.New("keyless identities must have an issuer and a subject")
+		}
+
+		if _, err := regexp.Compile(identity.Subject); err != nil {
+			return fmt.Errorf("invalid subject for identity with issuer %s: %w", identity.Issuer, err)
+		}
+	}
+
+	return nil
+}
+
+// ImageRef refers to the image to verify
+type ImageRef struct {
+	Repository string
+	Tag        string
+	// Digest is verified instead of the tag if it is set
+	Digest string
+	// DockerConfigJSON holds the credentials for the image's registry, in the format of ~/.docker/config.json
+	DockerConfigJSON []byte
+}
+
+// String returns the image reference in the form repository@digest or repository:tag
+func (i ImageRef) String() string {
+	if i.Digest != "" {
+		return fmt.Sprintf("%s@%s", i.Repository, i.Digest)
+	}
+
+	return fmt.Sprintf("%s:%s", i.Repository, i.Tag)
+}
+
+// Verification is the result of successfully verifying an image
+type Verification struct {
+	// Digest is the digest of the image which the signature covers
+	Digest string
+	// Signer is the name of the public key or the subject of the identity which signed the image
+	Signer string
+	// Attestations are the predicate types which were verified
+	Attestations []string
+	VerifiedAt   time.Time
+}
+
+// PinTag returns the tag with the verified digest appended, in the form tag@sha256:..., so that
+// deploys of the tag pull the verified image even if the tag is moved
+func (v *Verification) PinTag(tag string) string {
+	if v.Digest == "" {
+		return tag
+	}
+
+	tag, _, _ = strings.Cut(tag, "@")
+
+	return fmt.Sprintf("%s@%s", tag, v.Digest)
+}
+
+// Verifier verifies image signatures against a trust policy
+type Verifier interface {
+	// Name identifies the verifier
+	Name() string
+	// Verify returns an error wrapping ErrNoValidSignature or ErrMissingAttestation if the
+	// image does not satisfy the policy
+	Verify(ctx context.Context, image ImageRef, policy TrustPolicy) (*Verification, error)
+}
@@ -0,0 +1,137 @@
+package imagesign
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLocalVerifier(t *testing.T) {
+	verifier := NewLocalVerifier()
+	verifier.AddSignature("registry.example.com/web", "v1", "sha256:abc", "release-key", "slsaprovenance")
+	verifier.AddSignature("registry.example.com/web", "v2", "sha256:def", "https://github.com/acme/web/.github/workflows/release.yml@refs/heads/main")
+
+	keyPolicy := TrustPolicy{PublicKeys: []PublicKey{{Name: "release-key"}}}
+	identityPolicy := TrustPolicy{Identities: []Identity{{
+		Issuer:  "https://token.actions.githubusercontent.com",
+		Subject: `^https://github\.com/acme/.*@refs/heads/main$`,
+	}}}
+
+	tests := []struct {
+		name   string
+		image  ImageRef
+		policy TrustPolicy
+		err    error
+		digest string
+	}{
+		{
+			name:   "signed with a trusted key",
+			image:  ImageRef{Repository: "registry.example.com/web", Tag: "v1"},
+			policy: keyPolicy,
+			digest: "sha256:abc",
+		},
+		{
+			name:   "signed by a trusted identity",
+			image:  ImageRef{Repository: "registry.example.com/web", Tag: "v2"},
+			policy: identityPolicy,
+			digest: "sha256:def",
+		},
+		{
+			name:   "signed by an untrusted signer",
+			image:  ImageRef{Repository: "registry.example.com/web", Tag: "v2"},
+			policy: keyPolicy,
+			err:    ErrNoValidSignature,
+		},
+		{
+			name:   "unsigned",
+			image:  ImageRef{Repository: "registry.example.com/web", Tag: "v3"},
+			policy: keyPolicy,
+			err:    ErrNoValidSignature,
+		},
+		{
+			name:   "missing attestation",
+			image:  ImageRef{Repository: "registry.example.com/web", Tag: "v2"},
+			policy: TrustPolicy{Identities: identityPolicy.Identities, Attestations: []string{"slsaprovenance"}},
+			err:    ErrMissingAttestation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verification, err := verifier.Verify(context.Background(), tt.image, tt.policy)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if verification.Digest != tt.digest {
+				t.Errorf("expected digest %s, got %s", tt.digest, verification.Digest)
+			}
+		})
+	}
+}
+
+func TestParseCosignVerifyOutput(t *testing.T) {
+	data := []byte(`[{"critical":{"identity":{"docker-reference":"registry.example.com/web"},"image":{"docker-manifest-digest":"sha256:abc"},"type":"cosign container image signature"},"optional":null}]`)
+
+	digest, err := parseCosignVerifyOutput(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if digest != "sha256:abc" {
+		t.Errorf("expected digest sha256:abc, got %s", digest)
+	}
+}
+
+func TestPinTag(t *testing.T) {
+	v := &Verification{Digest: "sha256:abc"}
+
+	if got := v.PinTag("v1"); got != "v1@sha256:abc" {
+		t.Errorf("expected v1@sha256:abc, got %s", got)
+	}
+
+	if got := v.PinTag("v1@sha256:old"); got != "v1@sha256:abc" {
+		t.Errorf("expected the previous digest to be replaced, got %s", got)
+	}
+}
+
+func TestTrustPolicyValidate(t *testing.T) {
+	if err := (TrustPolicy{Identities: []Identity{{Issuer: "https://accounts.google.com", Subject: "("}}}).Validate(); err == nil {
+		t.Error("expected an invalid subject to be rejected")
+	}
+
+	if err := (TrustPolicy{PublicKeys: []PublicKey{{Name: "release-key", PEM: "not a key"}}}).Validate(); err == nil {
+		t.Error("expected an invalid public key to be rejected")
+	}
+}
+
+func TestIdentitySubjectMatchesWholeSubject(t *testing.T) {
+	verifier := NewLocalVerifier()
+	verifier.AddSignature("registry.example.com/web", "v1", "sha256:abc", "https://github.com/acme/web-fork/.github/workflows/release.yml@refs/heads/main")
+
+	policy := TrustPolicy{Identities: []Identity{{
+		Issuer:  "https://token.actions.githubusercontent.com",
+		Subject: "https://github.com/acme/web/",
+	}}}
+
+	if _, err := verifier.Verify(context.Background(), ImageRef{Repository: "registry.example.com/web", Tag: "v1"}, policy); !errors.Is(err, ErrNoValidSignature) {
+		t.Errorf("expected a subject which only matches part of the signer to be rejected, got %v", err)
+	}
+
+	signers, err := cosignSigners(t.TempDir(), policy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(signers) != 1 || signers[0].args[1] != "^(?:https://github.com/acme/web/)$" {
+		t.Errorf("expected cosign to be passed an anchored subject, got %v", signers)
+	}
+}
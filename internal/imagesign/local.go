@@ -0,0 +1,97 @@
+package imagesign
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// LocalVerifier stands in for a real verifier in tests and local development. Images are signed
+// by registering a signature with AddSignature. A signature is trusted if its signer is the name
+// of a trusted public key or matches the subject of a trusted identity.
+type LocalVerifier struct {
+	mu sync.Mutex
+
+	// digests maps repository:tag to the digest of the image
+	digests    map[string]string
+	signatures map[string][]localSignature
+}
+
+type localSignature struct {
+	signer       string
+	attestations []string
+}
+
+func NewLocalVerifier() *LocalVerifier {
+	return &LocalVerifier{
+		digests:    make(map[string]string),
+		signatures: make(map[string][]localSignature),
+	}
+}
+
+// AddSignature registers a signature of an image by signer, along with the predicate types the signer attested to
+func (v *LocalVerifier) AddSignature(repository, tag, digest, signer string, attestations ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.digests[fmt.Sprintf("%s:%s", repository, tag)] = digest
+	v.signatures[digest] = append(v.signatures[digest], localSignature{signer, attestations})
+}
+
+func (v *LocalVerifier) Name() string {
+	return "local"
+}
+
+func (v *LocalVerifier) Verify(ctx context.Context, image ImageRef, policy TrustPolicy) (*Verification, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	digest := image.Digest
+	if digest == "" {
+		digest = v.digests[fmt.Sprintf("%s:%s", image.Repository, image.Tag)]
+	}
+
+	for _, sig := range v.signatures[digest] {
+		if !trusts(policy, sig.signer) {
+			continue
+		}
+
+		attested := make(map[string]bool, len(sig.attestations))
+		for _, a := range sig.attestations {
+			attested[a] = true
+		}
+
+		for _, predicateType := range policy.Attestations {
+			if !attested[predicateType] {
+				return nil, fmt.Errorf("%w: %s attestation by %s", ErrMissingAttestation, predicateType, sig.signer)
+			}
+		}
+
+		return &Verification{
+			Digest:       digest,
+			Signer:       sig.signer,
+			Attestations: append([]string(nil), policy.Attestations...),
+			VerifiedAt:   time.Now().UTC(),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoValidSignature, image)
+}
+
+func trusts(policy TrustPolicy, signer string) bool {
+	for _, key := range policy.PublicKeys {
+		if key.Name == signer {
+			return true
+		}
+	}
+
+	for _, identity := range policy.Identities {
+		if ok, _ := regexp.MatchString(identity.SubjectPattern(), signer); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -238,6 +238,7 @@ type GithubActionYAMLOnPullRequestTypes struct {
 
 type GithubActionYAMLJob struct {
 	RunsOn      string                 `yaml:"runs-on,omitempty"`
+	Permissions map[string]string      `yaml:"permissions,omitempty"`
 	Steps       []GithubActionYAMLStep `yaml:"steps,omitempty"`
 	Concurrency map[string]string      `yaml:"concurrency,omitempty"`
 }
@@ -36,6 +36,11 @@ type GithubPROpts struct {
 	WorkflowFileName          string
 	PRBranch                  string
 	DeploymentTargetId        string
+
+	// OIDCDeploymentTarget makes the app workflow exchange the job's ID token for a Porter
+	// token which deploys to the named deployment target, instead of reading a long-lived
+	// token from the SecretName secret
+	OIDCDeploymentTarget string
 }
 
 type GetStackApplyActionYAMLOpts struct {
@@ -47,6 +52,7 @@ type GetStackApplyActionYAMLOpts struct {
 	PorterYamlPath       string
 	Preview              bool
 	DeploymentTargetId   string
+	OIDCDeploymentTarget string
 }
 
 func OpenGithubPR(opts *GithubPROpts) (*github.PullRequest, error) {
@@ -106,15 +112,16 @@ func commitChange(prBranchName string, opts GithubPROpts) error {
 	switch opts.PRAction {
 	case GithubPRAction_NewAppWorkflow:
 		applyWorkflowYAML, err := getStackApplyActionYAML(&GetStackApplyActionYAMLOpts{
-			ServerURL:          opts.ServerURL,
-			ClusterID:          opts.ClusterID,
-			ProjectID:          opts.ProjectID,
-			StackName:          opts.StackName,
-			DefaultBranch:      opts.DefaultBranch,
-			SecretName:         opts.SecretName,
-			PorterYamlPath:     opts.PorterYamlPath,
-			DeploymentTargetId: opts.DeploymentTargetId,
-			Preview:            false,
+			ServerURL:            opts.ServerURL,
+			ClusterID:            opts.ClusterID,
+			ProjectID:            opts.ProjectID,
+			StackName:            opts.StackName,
+			DefaultBranch:        opts.DefaultBranch,
+			SecretName:           opts.SecretName,
+			PorterYamlPath:       opts.PorterYamlPath,
+			DeploymentTargetId:   opts.DeploymentTargetId,
+			Preview:              false,
+			OIDCDeploymentTarget: opts.OIDCDeploymentTarget,
 		})
 		if err != nil {
 			return err
@@ -181,9 +188,24 @@ func getStackApplyActionYAML(opts *GetStackApplyActionYAMLOpts) ([]byte, error)
 		getCheckoutCodeStep(),
 		getSetTagStep(),
 		getSetupPorterStep(),
+	}
+
+	porterToken := fmt.Sprintf("${{ secrets.%s }}", opts.SecretName)
+	var permissions map[string]string
+
+	if opts.OIDCDeploymentTarget != "" {
+		gaSteps = append(gaSteps, getExchangeOIDCTokenStep(opts.ServerURL, opts.ProjectID, opts.StackName, opts.OIDCDeploymentTarget))
+		porterToken = getExchangedOIDCTokenRef()
+		permissions = map[string]string{
+			"id-token": "write",
+			"contents": "read",
+		}
+	}
+
+	gaSteps = append(gaSteps,
 		getDeployStackStep(
 			opts.ServerURL,
-			opts.SecretName,
+			porterToken,
 			opts.StackName,
 			"v0.1.0",
 			opts.PorterYamlPath,
@@ -192,7 +214,7 @@ func getStackApplyActionYAML(opts *GetStackApplyActionYAMLOpts) ([]byte, error)
 			opts.DeploymentTargetId,
 			opts.Preview,
 		),
-	}
+	)
 
 	if opts.Preview {
 		actionYaml := GithubActionYAML{
@@ -234,8 +256,9 @@ func getStackApplyActionYAML(opts *GetStackApplyActionYAMLOpts) ([]byte, error)
 		Name: fmt.Sprintf("Deploy to %s", opts.StackName),
 		Jobs: map[string]GithubActionYAMLJob{
 			"porter-deploy": {
-				RunsOn: "ubuntu-latest",
-				Steps:  gaSteps,
+				RunsOn:      "ubuntu-latest",
+				Permissions: permissions,
+				Steps:       gaSteps,
 			},
 		},
 	}
@@ -9,8 +9,20 @@ const (
 	updateAppActionName     = "porter-dev/porter-update-action"
 	createPreviewActionName = "porter-dev/porter-preview-action"
 	cliActionName           = "porter-dev/porter-cli-action"
+
+	// exchangeOIDCTokenStepID is the id of the step which exchanges the job's ID token for a
+	// Porter token
+	exchangeOIDCTokenStepID = "porter-token"
 )
 
+// exchangeOIDCTokenScript requests an ID token for the job with the Porter server as its
+// audience, and exchanges it for a short-lived Porter token
+const exchangeOIDCTokenScript = `id_token=$(curl -sSf -H "Authorization: bearer $ACTIONS_ID_TOKEN_REQUEST_TOKEN" "$ACTIONS_ID_TOKEN_REQUEST_URL&audience=$PORTER_HOST" | jq -r .value)
+request=$(jq -n --arg id_token "$id_token" --arg app_name "$PORTER_APP_NAME" --arg deployment_target "$PORTER_DEPLOYMENT_TARGET" --argjson project_id "$PORTER_PROJECT" '{project_id: $project_id, id_token: $id_token, app_name: $app_name, deployment_target: $deployment_target}')
+token=$(curl -sSf -X POST -H "Content-Type: application/json" -d "$request" "$PORTER_HOST/api/oidc/token" | jq -r .token)
+echo "::add-mask::$token"
+echo "token=$token" >> $GITHUB_OUTPUT`
+
 func getCheckoutCodeStep() GithubActionYAMLStep {
 	return GithubActionYAMLStep{
 		Name: "Checkout code",
@@ -33,6 +45,29 @@ func getSetupPorterStep() GithubActionYAMLStep {
 	}
 }
 
+// getExchangeOIDCTokenStep exchanges the job's ID token for a Porter token which can
+// deploy the app to the deployment target, according to the trust rules of the project.
+// The job needs the id-token: write permission.
+func getExchangeOIDCTokenStep(serverURL string, projectID uint, appName, deploymentTarget string) GithubActionYAMLStep {
+	return GithubActionYAMLStep{
+		Name: "Get Porter token",
+		ID:   exchangeOIDCTokenStepID,
+		Run:  exchangeOIDCTokenScript,
+		Env: map[string]string{
+			"PORTER_HOST":              serverURL,
+			"PORTER_PROJECT":           fmt.Sprintf("%d", projectID),
+			"PORTER_APP_NAME":          appName,
+			"PORTER_DEPLOYMENT_TARGET": deploymentTarget,
+		},
+		Timeout: 5,
+	}
+}
+
+// getExchangedOIDCTokenRef refers to the token output by the OIDC token exchange step
+func getExchangedOIDCTokenRef() string {
+	return fmt.Sprintf("${{ steps.%s.outputs.token }}", exchangeOIDCTokenStepID)
+}
+
 func getUpdateAppStep(serverURL, porterTokenSecretName string, projectID uint, clusterID uint, appName string, appNamespace, actionVersion string) GithubActionYAMLStep {
 	return GithubActionYAMLStep{
 		Name: "Update Porter App",
@@ -79,7 +114,7 @@ func getCreatePreviewEnvStep(
 }
 
 func getDeployStackStep(
-	serverURL, porterTokenSecretName, stackName, actionVersion, porterYamlPath string,
+	serverURL, porterToken, stackName, actionVersion, porterYamlPath string,
 	projectID, clusterID uint,
 	deploymentTargetId string,
 	preview bool,
@@ -102,7 +137,7 @@ func getDeployStackStep(
 		"PORTER_CLUSTER":    fmt.Sprintf("%d", clusterID),
 		"PORTER_HOST":       serverURL,
 		"PORTER_PROJECT":    fmt.Sprintf("%d", projectID),
-		"PORTER_TOKEN":      fmt.Sprintf("${{ secrets.%s }}", porterTokenSecretName),
+		"PORTER_TOKEN":      porterToken,
 		"PORTER_TAG":        "${{ steps.vars.outputs.sha_short }}",
 		"PORTER_STACK_NAME": stackName,
 		"PORTER_PR_NUMBER":  "${{ github.event.number }}",
@@ -68,6 +68,7 @@ func setupTestEnv(tester *tester, t *testing.T) {
 		&models.ClusterCandidate{},
 		&models.ClusterResolver{},
 		&models.Infra{},
+		&models.Operation{},
 		&models.GitActionConfig{},
 		&models.Onboarding{},
 		&ints.KubeIntegration{},
@@ -76,9 +77,17 @@ func setupTestEnv(tester *tester, t *testing.T) {
 		&ints.OAuthIntegration{},
 		&ints.GCPIntegration{},
 		&ints.AWSIntegration{},
+		&ints.AzureIntegration{},
+		&ints.GitlabIntegration{},
+		&ints.SlackIntegration{},
+		&ints.UpstashIntegration{},
+		&ints.NeonIntegration{},
 		&ints.ClusterTokenCache{},
 		&ints.RegTokenCache{},
 		&ints.HelmRepoTokenCache{},
+		&models.KeyRotation{},
+		&models.KeyRotationCheckpoint{},
+		&models.AppRevisionApproval{},
 	)
 
 	if err != nil {
@@ -0,0 +1,356 @@
+package keyrotate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	gorm "github.com/karagatandev/porter/internal/repository/gorm"
+	lr "github.com/karagatandev/porter/pkg/logger"
+
+	_gorm "gorm.io/gorm"
+)
+
+// process 100 records at a time
+const stepSize = 100
+
+// rotationHeartbeatTimeout is how long a running rotation can go without writing a checkpoint
+// before it is considered interrupted, and can be resumed by Start
+const rotationHeartbeatTimeout = 5 * time.Minute
+
+// errRecordsFailed is returned by rotateTable when some records of the table could not be rotated
+var errRecordsFailed = errors.New("records failed to rotate")
+
+// ErrRotationInProgress is returned by Start when the rotation is already running, either in
+// this process or on another server
+var ErrRotationInProgress = errors.New("key rotation is already in progress")
+
+// Rotate re-encrypts every encrypted column from oldKey to newKey. See Rotator.Run.
+func Rotate(db *_gorm.DB, oldKey, newKey *[32]byte) error {
+	return NewRotator(db, oldKey, newKey, lr.NewConsole(false)).Run(context.Background())
+}
+
+// Rotator re-encrypts every encrypted table from an old key to a new key. Progress is
+// checkpointed per table after every batch, so a rotation that is interrupted or fails
+// can be resumed by running it again with the same keys. Records which cannot be
+// rotated are counted as failures and left untouched instead of aborting the rotation,
+// and their tables are left incomplete so that the next run retries them.
+type Rotator struct {
+	db     *_gorm.DB
+	repo   repository.KeyRotationRepository
+	oldKey *[32]byte
+	newKey *[32]byte
+	logger *lr.Logger
+	tables []*encryptedTable
+
+	mu      sync.Mutex
+	running bool
+}
+
+// NewRotator returns a Rotator for all encrypted tables
+func NewRotator(db *_gorm.DB, oldKey, newKey *[32]byte, logger *lr.Logger) *Rotator {
+	return &Rotator{
+		db:     db.Unscoped(),
+		repo:   gorm.NewKeyRotationRepository(db),
+		oldKey: oldKey,
+		newKey: newKey,
+		logger: logger,
+		tables: encryptedTables(),
+	}
+}
+
+// Start runs the rotation in the background and returns its record, which can be used to
+// monitor its progress. A rotation which was interrupted, or which failed, is resumed from its
+// checkpoints. A completed rotation is returned without being run again.
+func (r *Rotator) Start() (*models.KeyRotation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return nil, ErrRotationInProgress
+	}
+
+	rotation, err := r.repo.ReadKeyRotationByFingerprint(keyFingerprint(r.oldKey, r.newKey))
+
+	switch {
+	case err == nil:
+		if rotation.Status == types.KeyRotationStatusCompleted {
+			return rotation, nil
+		}
+
+		if rotation.Status == types.KeyRotationStatusRunning && time.Since(lastProgress(rotation)) < rotationHeartbeatTimeout {
+			return nil, ErrRotationInProgress
+		}
+	case errors.Is(err, _gorm.ErrRecordNotFound):
+		rotation, err = r.getOrCreateRotation()
+		if err != nil {
+			return nil, fmt.Errorf("error creating key rotation: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("error reading key rotation: %w", err)
+	}
+
+	r.running = true
+
+	go func() {
+		defer func() {
+			r.mu.Lock()
+			r.running = false
+			r.mu.Unlock()
+		}()
+
+		if err := r.Run(context.Background()); err != nil {
+			r.logger.Error().Err(err).Msgf("key rotation %d did not complete", rotation.ID)
+		}
+	}()
+
+	return rotation, nil
+}
+
+// lastProgress returns the last time a rotation or any of its checkpoints was written
+func lastProgress(rotation *models.KeyRotation) time.Time {
+	last := rotation.UpdatedAt
+
+	for _, checkpoint := range rotation.Checkpoints {
+		if checkpoint.UpdatedAt.After(last) {
+			last = checkpoint.UpdatedAt
+		}
+	}
+
+	return last
+}
+
+// Run rotates every encrypted table which has not been completed by a previous run with
+// the same keys. Rerunning a completed rotation is a no-op.
+func (r *Rotator) Run(ctx context.Context) error {
+	rotation, err := r.getOrCreateRotation()
+	if err != nil {
+		return fmt.Errorf("error reading key rotation: %w", err)
+	}
+
+	if rotation.Status == types.KeyRotationStatusCompleted {
+		r.logger.Info().Msgf("key rotation %d was already completed, skipping", rotation.ID)
+		return nil
+	}
+
+	rotation.Status = types.KeyRotationStatusRunning
+	rotation.Error = ""
+
+	if _, err := r.repo.UpdateKeyRotation(rotation); err != nil {
+		return fmt.Errorf("error updating key rotation: %w", err)
+	}
+
+	checkpoints := make(map[string]*models.KeyRotationCheckpoint)
+
+	for i := range rotation.Checkpoints {
+		checkpoints[rotation.Checkpoints[i].EncryptedTable] = &rotation.Checkpoints[i]
+	}
+
+	var failedTables []string
+
+	for _, table := range r.tables {
+		checkpoint, ok := checkpoints[table.name]
+
+		if !ok {
+			checkpoint = &models.KeyRotationCheckpoint{
+				KeyRotationID:  rotation.ID,
+				EncryptedTable: table.name,
+			}
+		}
+
+		if checkpoint.Completed {
+			continue
+		}
+
+		err := r.rotateTable(ctx, table, checkpoint)
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			// leave the rotation as running so that it is resumed on the next run
+			return err
+		}
+
+		if errors.Is(err, errRecordsFailed) {
+			// the table is left incomplete so that the next run retries it, but the other tables are still rotated
+			failedTables = append(failedTables, err.Error())
+			continue
+		}
+
+		if err != nil {
+			rotation.Status = types.KeyRotationStatusFailed
+			rotation.Error = fmt.Sprintf("error rotating %s: %s", table.name, err.Error())
+
+			if _, updateErr := r.repo.UpdateKeyRotation(rotation); updateErr != nil {
+				r.logger.Error().Err(updateErr).Msg("error marking key rotation as failed")
+			}
+
+			return fmt.Errorf("error rotating %s: %w", table.name, err)
+		}
+	}
+
+	if len(failedTables) > 0 {
+		rotation.Status = types.KeyRotationStatusFailed
+		rotation.Error = strings.Join(failedTables, "; ")
+
+		if _, err := r.repo.UpdateKeyRotation(rotation); err != nil {
+			return fmt.Errorf("error marking key rotation as failed: %w", err)
+		}
+
+		return fmt.Errorf("key rotation %d is incomplete: %s", rotation.ID, rotation.Error)
+	}
+
+	now := time.Now()
+
+	rotation.Status = types.KeyRotationStatusCompleted
+	rotation.CompletedAt = &now
+
+	if _, err := r.repo.UpdateKeyRotation(rotation); err != nil {
+		return fmt.Errorf("error marking key rotation as completed: %w", err)
+	}
+
+	r.logger.Info().Msgf("key rotation %d completed", rotation.ID)
+
+	return nil
+}
+
+func (r *Rotator) getOrCreateRotation() (*models.KeyRotation, error) {
+	fingerprint := keyFingerprint(r.oldKey, r.newKey)
+
+	rotation, err := r.repo.ReadKeyRotationByFingerprint(fingerprint)
+
+	if err == nil {
+		return rotation, nil
+	}
+
+	if !errors.Is(err, _gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return r.repo.CreateKeyRotation(&models.KeyRotation{
+		KeyFingerprint: fingerprint,
+		Status:         types.KeyRotationStatusRunning,
+	})
+}
+
+// rotateTable rotates the records of a table after the last checkpointed record. If any records fail, the table is
+// left incomplete and its checkpoint is reset, so that the next run walks the table again from its first record.
+// Records which were rotated by this pass are skipped by the next one.
+func (r *Rotator) rotateTable(ctx context.Context, table *encryptedTable, checkpoint *models.KeyRotationCheckpoint) error {
+	if err := r.db.Model(table.model).Count(&checkpoint.Total).Error; err != nil {
+		return err
+	}
+
+	if checkpoint.LastID == 0 {
+		checkpoint.Rotated = 0
+		checkpoint.Skipped = 0
+		checkpoint.Failed = 0
+		checkpoint.LastError = ""
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var ids []uint
+
+		if err := r.db.Model(table.model).Where("id > ?", checkpoint.LastID).Order("id asc").Limit(stepSize).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			break
+		}
+
+		for _, id := range ids {
+			skipped, err := r.rotateRecord(table, id)
+
+			switch {
+			case err != nil:
+				checkpoint.Failed++
+				checkpoint.LastError = fmt.Sprintf("record %d: %s", id, err.Error())
+
+				r.logger.Error().Err(err).Msgf("error rotating %s record %d", table.name, id)
+			case skipped:
+				checkpoint.Skipped++
+			default:
+				checkpoint.Rotated++
+			}
+
+			checkpoint.LastID = id
+		}
+
+		if _, err := r.repo.UpdateKeyRotationCheckpoint(checkpoint); err != nil {
+			return err
+		}
+	}
+
+	if checkpoint.Failed > 0 {
+		failed := checkpoint.Failed
+		checkpoint.LastID = 0
+
+		if _, err := r.repo.UpdateKeyRotationCheckpoint(checkpoint); err != nil {
+			return err
+		}
+
+		r.logger.Error().Msgf("%d records of %s failed to rotate, and will be retried on the next run", failed, table.name)
+
+		return fmt.Errorf("%w: %d records of %s", errRecordsFailed, failed, table.name)
+	}
+
+	checkpoint.Completed = true
+
+	if _, err := r.repo.UpdateKeyRotationCheckpoint(checkpoint); err != nil {
+		return err
+	}
+
+	r.logger.Info().Msgf(
+		"rotated %s: %d rotated, %d already rotated, %d failed",
+		table.name, checkpoint.Rotated, checkpoint.Skipped, checkpoint.Failed,
+	)
+
+	return nil
+}
+
+// rotateRecord re-encrypts a single record with the new key and verifies that the
+// written record decrypts. It returns true if the record was already encrypted with
+// the new key, which happens when a previous run stopped partway through a batch.
+func (r *Rotator) rotateRecord(table *encryptedTable, id uint) (bool, error) {
+	record, err := table.load(r.db, id, r.oldKey)
+	if err != nil {
+		if _, newErr := table.load(r.db, id, r.newKey); newErr == nil {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("could not decrypt with the old or new key: %w", err)
+	}
+
+	err = r.db.Transaction(func(tx *_gorm.DB) error {
+		return table.store(tx, record, r.newKey)
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not write re-encrypted record: %w", err)
+	}
+
+	if _, err := table.load(r.db, id, r.newKey); err != nil {
+		return false, fmt.Errorf("could not verify re-encrypted record: %w", err)
+	}
+
+	return false, nil
+}
+
+func keyFingerprint(oldKey, newKey *[32]byte) string {
+	h := sha256.New()
+
+	h.Write(oldKey[:])
+	h.Write(newKey[:])
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -1,12 +1,16 @@
 package keyrotate_test
 
 import (
+	"errors"
 	"testing"
+	"time"
 
-	"github.com/karagatandev/porter/cmd/migrate/keyrotate"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/keyrotate"
 	"github.com/karagatandev/porter/internal/models"
 	ints "github.com/karagatandev/porter/internal/models/integrations"
 	gorm "github.com/karagatandev/porter/internal/repository/gorm"
+	lr "github.com/karagatandev/porter/pkg/logger"
 )
 
 func TestClusterModelRotation(t *testing.T) {
@@ -586,3 +590,313 @@ func TestAWSIntegrationModelRotation(t *testing.T) {
 		}
 	}
 }
+
+func TestRotationResumesPartialRun(t *testing.T) {
+	var newKey [32]byte
+
+	for i, b := range []byte("__r3n3o3_s3r3n3_3n3r3p3i3n_k3y__") {
+		newKey[i] = b
+	}
+
+	tester := &tester{
+		dbFileName: "./porter_resume_rotate.db",
+	}
+
+	setupTestEnv(tester, t)
+
+	for i := 0; i < 10; i++ {
+		initCluster(tester, t)
+	}
+
+	defer cleanup(tester, t)
+
+	// simulate a run which stopped partway through by rotating the first clusters by hand
+	newRepo := gorm.NewClusterRepository(tester.DB, &newKey).(*gorm.ClusterRepository)
+
+	for _, c := range tester.initClusters[:4] {
+		cluster := &models.Cluster{}
+
+		if err := tester.DB.Where("id = ?", c.ID).First(cluster).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		cluster.CertificateAuthorityData = []byte("-----BEGIN")
+
+		if err := newRepo.EncryptClusterData(cluster, &newKey); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		if err := tester.DB.Save(cluster).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	err := keyrotate.Rotate(tester.DB, tester.Key, &newKey)
+	if err != nil {
+		t.Fatalf("error rotating: %v\n", err)
+	}
+
+	rotations, err := gorm.NewKeyRotationRepository(tester.DB).ListKeyRotations()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(rotations) != 1 || rotations[0].Status != types.KeyRotationStatusCompleted {
+		t.Fatalf("expected a single completed key rotation\n")
+	}
+
+	for _, checkpoint := range rotations[0].Checkpoints {
+		if checkpoint.EncryptedTable != "clusters" {
+			continue
+		}
+
+		if checkpoint.Rotated != 6 || checkpoint.Skipped != 4 || checkpoint.Failed != 0 {
+			t.Errorf("incorrect cluster checkpoint: rotated %d, skipped %d, failed %d\n", checkpoint.Rotated, checkpoint.Skipped, checkpoint.Failed)
+		}
+	}
+
+	// running the rotation again should be a no-op
+	err = keyrotate.Rotate(tester.DB, tester.Key, &newKey)
+	if err != nil {
+		t.Fatalf("error rerunning rotation: %v\n", err)
+	}
+
+	for _, c := range tester.initClusters {
+		cluster, err := newRepo.ReadCluster(c.ProjectID, c.ID)
+		if err != nil {
+			t.Fatalf("error reading cluster: %v\n", err)
+		}
+
+		if string(cluster.CertificateAuthorityData) != "-----BEGIN" {
+			t.Errorf("%s\n", string(cluster.CertificateAuthorityData))
+		}
+	}
+}
+
+func TestRotationRetriesFailedTables(t *testing.T) {
+	var newKey, otherKey [32]byte
+
+	for i, b := range []byte("__r3n3o3_s3r3n3_3n3r3p3i3n_k3y__") {
+		newKey[i] = b
+	}
+
+	for i, b := range []byte("__o3h3r3_s3r3n3_3n3r3p3i3n_k3y__") {
+		otherKey[i] = b
+	}
+
+	tester := &tester{
+		dbFileName: "./porter_retry_rotate.db",
+	}
+
+	setupTestEnv(tester, t)
+
+	for i := 0; i < 10; i++ {
+		initCluster(tester, t)
+	}
+
+	defer cleanup(tester, t)
+
+	// encrypt one cluster with a key which is neither the old nor the new key, so that it can't be rotated
+	oldRepo := gorm.NewClusterRepository(tester.DB, tester.Key).(*gorm.ClusterRepository)
+	broken := tester.initClusters[3]
+
+	setClusterKey := func(key *[32]byte) {
+		cluster := &models.Cluster{}
+
+		if err := tester.DB.Where("id = ?", broken.ID).First(cluster).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		cluster.CertificateAuthorityData = []byte("-----BEGIN")
+
+		if err := oldRepo.EncryptClusterData(cluster, key); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		if err := tester.DB.Save(cluster).Error; err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	setClusterKey(&otherKey)
+
+	if err := keyrotate.Rotate(tester.DB, tester.Key, &newKey); err == nil {
+		t.Fatalf("expected an error rotating a cluster encrypted with another key\n")
+	}
+
+	clusterCheckpoint := func() models.KeyRotationCheckpoint {
+		rotations, err := gorm.NewKeyRotationRepository(tester.DB).ListKeyRotations()
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		if len(rotations) != 1 {
+			t.Fatalf("expected a single key rotation, got %d\n", len(rotations))
+		}
+
+		for _, checkpoint := range rotations[0].Checkpoints {
+			if checkpoint.EncryptedTable == "clusters" {
+				return checkpoint
+			}
+		}
+
+		t.Fatalf("no checkpoint for the clusters table\n")
+		return models.KeyRotationCheckpoint{}
+	}
+
+	rotations, err := gorm.NewKeyRotationRepository(tester.DB).ListKeyRotations()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if rotations[0].Status != types.KeyRotationStatusFailed {
+		t.Errorf("expected the key rotation to have failed, got %s\n", rotations[0].Status)
+	}
+
+	if checkpoint := clusterCheckpoint(); checkpoint.Completed || checkpoint.Rotated != 9 || checkpoint.Failed != 1 {
+		t.Errorf("incorrect cluster checkpoint: completed %t, rotated %d, failed %d\n", checkpoint.Completed, checkpoint.Rotated, checkpoint.Failed)
+	}
+
+	// once the cluster can be decrypted with the old key again, rerunning the rotation retries it
+	setClusterKey(tester.Key)
+
+	if err := keyrotate.Rotate(tester.DB, tester.Key, &newKey); err != nil {
+		t.Fatalf("error rerunning rotation: %v\n", err)
+	}
+
+	if checkpoint := clusterCheckpoint(); !checkpoint.Completed || checkpoint.Rotated != 1 || checkpoint.Skipped != 9 || checkpoint.Failed != 0 {
+		t.Errorf("incorrect cluster checkpoint: completed %t, rotated %d, skipped %d, failed %d\n", checkpoint.Completed, checkpoint.Rotated, checkpoint.Skipped, checkpoint.Failed)
+	}
+
+	newRepo := gorm.NewClusterRepository(tester.DB, &newKey).(*gorm.ClusterRepository)
+
+	for _, c := range tester.initClusters {
+		cluster, err := newRepo.ReadCluster(c.ProjectID, c.ID)
+		if err != nil {
+			t.Fatalf("error reading cluster: %v\n", err)
+		}
+
+		if string(cluster.CertificateAuthorityData) != "-----BEGIN" {
+			t.Errorf("%s\n", string(cluster.CertificateAuthorityData))
+		}
+	}
+}
+
+func TestRotatorStart(t *testing.T) {
+	var newKey [32]byte
+
+	for i, b := range []byte("__r3n3o3_s3r3n3_3n3r3p3i3n_k3y__") {
+		newKey[i] = b
+	}
+
+	tester := &tester{
+		dbFileName: "./porter_start_rotate.db",
+	}
+
+	setupTestEnv(tester, t)
+
+	for i := 0; i < 10; i++ {
+		initCluster(tester, t)
+	}
+
+	defer cleanup(tester, t)
+
+	rotator := keyrotate.NewRotator(tester.DB, tester.Key, &newKey, lr.NewConsole(false))
+
+	rotation, err := rotator.Start()
+	if err != nil {
+		t.Fatalf("error starting rotation: %v\n", err)
+	}
+
+	rotation = waitForRotation(tester, t, rotation.ID)
+
+	if rotation.Status != types.KeyRotationStatusCompleted {
+		t.Fatalf("expected the key rotation to complete, got %s: %s\n", rotation.Status, rotation.Error)
+	}
+
+	newRepo := gorm.NewClusterRepository(tester.DB, &newKey).(*gorm.ClusterRepository)
+
+	for _, c := range tester.initClusters {
+		cluster, err := newRepo.ReadCluster(c.ProjectID, c.ID)
+		if err != nil {
+			t.Fatalf("error reading cluster: %v\n", err)
+		}
+
+		if string(cluster.CertificateAuthorityData) != "-----BEGIN" {
+			t.Errorf("%s\n", string(cluster.CertificateAuthorityData))
+		}
+	}
+
+	// starting a completed rotation returns it without running it again
+	completed, err := rotator.Start()
+	if err != nil {
+		t.Fatalf("error restarting completed rotation: %v\n", err)
+	}
+
+	if completed.ID != rotation.ID || completed.Status != types.KeyRotationStatusCompleted {
+		t.Errorf("expected completed rotation %d, got rotation %d with status %s\n", rotation.ID, completed.ID, completed.Status)
+	}
+}
+
+func TestRotatorStartInProgress(t *testing.T) {
+	var newKey [32]byte
+
+	for i, b := range []byte("__r3n3o3_s3r3n3_3n3r3p3i3n_k3y__") {
+		newKey[i] = b
+	}
+
+	tester := &tester{
+		dbFileName: "./porter_start_in_progress_rotate.db",
+	}
+
+	setupTestEnv(tester, t)
+	initCluster(tester, t)
+	defer cleanup(tester, t)
+
+	rotator := keyrotate.NewRotator(tester.DB, tester.Key, &newKey, lr.NewConsole(false))
+
+	rotation, err := rotator.Start()
+	if err != nil {
+		t.Fatalf("error starting rotation: %v\n", err)
+	}
+
+	rotation = waitForRotation(tester, t, rotation.ID)
+	rotation.Status = types.KeyRotationStatusRunning
+
+	rotationRepo := gorm.NewKeyRotationRepository(tester.DB)
+
+	if _, err := rotationRepo.UpdateKeyRotation(rotation); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// the rotation now looks like it was recently checkpointed by another server
+	other := keyrotate.NewRotator(tester.DB, tester.Key, &newKey, lr.NewConsole(false))
+
+	if _, err := other.Start(); !errors.Is(err, keyrotate.ErrRotationInProgress) {
+		t.Errorf("expected ErrRotationInProgress, got %v\n", err)
+	}
+}
+
+// waitForRotation waits for a rotation started in the background to stop running
+func waitForRotation(tester *tester, t *testing.T, id uint) *models.KeyRotation {
+	t.Helper()
+
+	deadline := time.Now().Add(30 * time.Second)
+
+	for {
+		rotation, err := gorm.NewKeyRotationRepository(tester.DB).ReadKeyRotation(id)
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		if rotation.Status != types.KeyRotationStatusRunning {
+			return rotation
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the key rotation to stop running\n")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,453 @@
+package keyrotate
+
+import (
+	"github.com/karagatandev/porter/internal/models"
+	ints "github.com/karagatandev/porter/internal/models/integrations"
+	gorm "github.com/karagatandev/porter/internal/repository/gorm"
+
+	_gorm "gorm.io/gorm"
+)
+
+// encryptedTable is a table with columns that are encrypted at rest
+type encryptedTable struct {
+	name  string
+	model interface{}
+
+	// load reads the record with the given id and decrypts it with key
+	load func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error)
+
+	// store encrypts a record returned by load with key and writes it back
+	store func(db *_gorm.DB, record interface{}, key *[32]byte) error
+}
+
+// encryptedTables returns every table with encrypted columns. New models with
+// encrypted columns must be added here so that they are covered by key rotation.
+func encryptedTables() []*encryptedTable {
+	return []*encryptedTable{
+		{
+			name:  "clusters",
+			model: &models.Cluster{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				cluster := &models.Cluster{}
+
+				if err := db.Where("id = ?", id).First(cluster).Error; err != nil {
+					return nil, err
+				}
+
+				// the token cache is not a gorm association, so it is attached manually. It
+				// is not fatal if it does not exist.
+				if cluster.TokenCacheID != 0 {
+					db.Where("id = ?", cluster.TokenCacheID).First(&cluster.TokenCache)
+				}
+
+				return cluster, gorm.NewClusterRepository(db, key).(*gorm.ClusterRepository).DecryptClusterData(cluster, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				cluster := record.(*models.Cluster)
+
+				if err := gorm.NewClusterRepository(db, key).(*gorm.ClusterRepository).EncryptClusterData(cluster, key); err != nil {
+					return err
+				}
+
+				if err := db.Save(cluster).Error; err != nil {
+					return err
+				}
+
+				if cluster.TokenCache.ID != 0 {
+					return db.Save(&cluster.TokenCache).Error
+				}
+
+				return nil
+			},
+		},
+		{
+			name:  "registries",
+			model: &models.Registry{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				reg := &models.Registry{}
+
+				if err := db.Preload("TokenCache").Where("id = ?", id).First(reg).Error; err != nil {
+					return nil, err
+				}
+
+				return reg, gorm.NewRegistryRepository(db, key).(*gorm.RegistryRepository).DecryptRegistryData(reg, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				reg := record.(*models.Registry)
+
+				if err := gorm.NewRegistryRepository(db, key).(*gorm.RegistryRepository).EncryptRegistryData(reg, key); err != nil {
+					return err
+				}
+
+				if err := db.Omit("TokenCache").Save(reg).Error; err != nil {
+					return err
+				}
+
+				if reg.TokenCache.ID != 0 {
+					return db.Save(&reg.TokenCache).Error
+				}
+
+				return nil
+			},
+		},
+		{
+			name:  "helm_repos",
+			model: &models.HelmRepo{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				hr := &models.HelmRepo{}
+
+				if err := db.Preload("TokenCache").Where("id = ?", id).First(hr).Error; err != nil {
+					return nil, err
+				}
+
+				return hr, gorm.NewHelmRepoRepository(db, key).(*gorm.HelmRepoRepository).DecryptHelmRepoData(hr, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				hr := record.(*models.HelmRepo)
+
+				if err := gorm.NewHelmRepoRepository(db, key).(*gorm.HelmRepoRepository).EncryptHelmRepoData(hr, key); err != nil {
+					return err
+				}
+
+				if err := db.Omit("TokenCache").Save(hr).Error; err != nil {
+					return err
+				}
+
+				if hr.TokenCache.ID != 0 {
+					return db.Save(&hr.TokenCache).Error
+				}
+
+				return nil
+			},
+		},
+		{
+			name:  "cluster_candidates",
+			model: &models.ClusterCandidate{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &models.ClusterCandidate{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewClusterRepository(db, key).(*gorm.ClusterRepository).DecryptClusterCandidateData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*models.ClusterCandidate)
+
+				if err := gorm.NewClusterRepository(db, key).(*gorm.ClusterRepository).EncryptClusterCandidateData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "infras",
+			model: &models.Infra{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &models.Infra{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewInfraRepository(db, key).(*gorm.InfraRepository).DecryptInfraData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*models.Infra)
+
+				if err := gorm.NewInfraRepository(db, key).(*gorm.InfraRepository).EncryptInfraData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "operations",
+			model: &models.Operation{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &models.Operation{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewInfraRepository(db, key).(*gorm.InfraRepository).DecryptOperationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*models.Operation)
+
+				if err := gorm.NewInfraRepository(db, key).(*gorm.InfraRepository).EncryptOperationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "kube_integrations",
+			model: &ints.KubeIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &ints.KubeIntegration{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewKubeIntegrationRepository(db, key).(*gorm.KubeIntegrationRepository).DecryptKubeIntegrationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*ints.KubeIntegration)
+
+				if err := gorm.NewKubeIntegrationRepository(db, key).(*gorm.KubeIntegrationRepository).EncryptKubeIntegrationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "basic_integrations",
+			model: &ints.BasicIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &ints.BasicIntegration{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewBasicIntegrationRepository(db, key).(*gorm.BasicIntegrationRepository).DecryptBasicIntegrationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*ints.BasicIntegration)
+
+				if err := gorm.NewBasicIntegrationRepository(db, key).(*gorm.BasicIntegrationRepository).EncryptBasicIntegrationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "oidc_integrations",
+			model: &ints.OIDCIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &ints.OIDCIntegration{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewOIDCIntegrationRepository(db, key).(*gorm.OIDCIntegrationRepository).DecryptOIDCIntegrationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*ints.OIDCIntegration)
+
+				if err := gorm.NewOIDCIntegrationRepository(db, key).(*gorm.OIDCIntegrationRepository).EncryptOIDCIntegrationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "o_auth_integrations",
+			model: &ints.OAuthIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &ints.OAuthIntegration{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewOAuthIntegrationRepository(db, key, nil).(*gorm.OAuthIntegrationRepository).DecryptOAuthIntegrationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*ints.OAuthIntegration)
+
+				if err := gorm.NewOAuthIntegrationRepository(db, key, nil).(*gorm.OAuthIntegrationRepository).EncryptOAuthIntegrationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "gcp_integrations",
+			model: &ints.GCPIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &ints.GCPIntegration{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewGCPIntegrationRepository(db, key, nil).(*gorm.GCPIntegrationRepository).DecryptGCPIntegrationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*ints.GCPIntegration)
+
+				if err := gorm.NewGCPIntegrationRepository(db, key, nil).(*gorm.GCPIntegrationRepository).EncryptGCPIntegrationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "aws_integrations",
+			model: &ints.AWSIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &ints.AWSIntegration{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewAWSIntegrationRepository(db, key, nil).(*gorm.AWSIntegrationRepository).DecryptAWSIntegrationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*ints.AWSIntegration)
+
+				if err := gorm.NewAWSIntegrationRepository(db, key, nil).(*gorm.AWSIntegrationRepository).EncryptAWSIntegrationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "azure_integrations",
+			model: &ints.AzureIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &ints.AzureIntegration{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewAzureIntegrationRepository(db, key, nil).(*gorm.AzureIntegrationRepository).DecryptAzureIntegrationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*ints.AzureIntegration)
+
+				if err := gorm.NewAzureIntegrationRepository(db, key, nil).(*gorm.AzureIntegrationRepository).EncryptAzureIntegrationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "gitlab_integrations",
+			model: &ints.GitlabIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &ints.GitlabIntegration{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewGitlabIntegrationRepository(db, key, nil).(*gorm.GitlabIntegrationRepository).DecryptGitlabIntegrationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*ints.GitlabIntegration)
+
+				if err := gorm.NewGitlabIntegrationRepository(db, key, nil).(*gorm.GitlabIntegrationRepository).EncryptGitlabIntegrationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+		{
+			name:  "slack_integrations",
+			model: &ints.SlackIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &ints.SlackIntegration{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewSlackIntegrationRepository(db, key).(*gorm.SlackIntegrationRepository).DecryptSlackIntegrationData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*ints.SlackIntegration)
+
+				if err := gorm.NewSlackIntegrationRepository(db, key).(*gorm.SlackIntegrationRepository).EncryptSlackIntegrationData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+
+		{
+			name:  "upstash_integrations",
+			model: &ints.UpstashIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				upstashInt := ints.UpstashIntegration{}
+
+				if err := db.Where("id = ?", id).First(&upstashInt).Error; err != nil {
+					return nil, err
+				}
+
+				return gorm.NewUpstashIntegrationRepository(db, key).(*gorm.UpstashIntegrationRepository).DecryptUpstashIntegration(upstashInt, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				encrypted, err := gorm.NewUpstashIntegrationRepository(db, key).(*gorm.UpstashIntegrationRepository).EncryptUpstashIntegration(record.(ints.UpstashIntegration), key)
+				if err != nil {
+					return err
+				}
+
+				return db.Save(&encrypted).Error
+			},
+		},
+		{
+			name:  "neon_integrations",
+			model: &ints.NeonIntegration{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				neonInt := ints.NeonIntegration{}
+
+				if err := db.Where("id = ?", id).First(&neonInt).Error; err != nil {
+					return nil, err
+				}
+
+				return gorm.NewNeonIntegrationRepository(db, key).(*gorm.NeonIntegrationRepository).DecryptNeonIntegration(neonInt, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				encrypted, err := gorm.NewNeonIntegrationRepository(db, key).(*gorm.NeonIntegrationRepository).EncryptNeonIntegration(record.(ints.NeonIntegration), key)
+				if err != nil {
+					return err
+				}
+
+				return db.Save(&encrypted).Error
+			},
+		},
+		{
+			name:  "app_revision_approvals",
+			model: &models.AppRevisionApproval{},
+			load: func(db *_gorm.DB, id uint, key *[32]byte) (interface{}, error) {
+				record := &models.AppRevisionApproval{}
+
+				if err := db.Where("id = ?", id).First(record).Error; err != nil {
+					return nil, err
+				}
+
+				return record, gorm.NewAppRevisionApprovalRepository(db, key).(*gorm.AppRevisionApprovalRepository).DecryptAppRevisionApprovalData(record, key)
+			},
+			store: func(db *_gorm.DB, record interface{}, key *[32]byte) error {
+				r := record.(*models.AppRevisionApproval)
+
+				if err := gorm.NewAppRevisionApprovalRepository(db, key).(*gorm.AppRevisionApprovalRepository).EncryptAppRevisionApprovalData(r, key); err != nil {
+					return err
+				}
+
+				return db.Save(r).Error
+			},
+		},
+	}
+}
@@ -1967,6 +1967,12 @@ func (a *Agent) CreateImagePullSecrets(
 
 		data, err := _reg.GetDockerConfigJSON(repo, doAuth)
 		if err != nil {
+			// the installation token of a GHCR registry is refreshed whenever the registry is used with the
+			// GitHub App's credentials, so the registry is skipped rather than failing the other registries
+			if goerrors.Is(err, registry.ErrGHCRTokenExpired) {
+				continue
+			}
+
 			return nil, err
 		}
 
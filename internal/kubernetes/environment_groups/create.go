@@ -64,6 +64,7 @@ func CreateOrUpdateBaseEnvironmentGroup(ctx context.Context, a *kubernetes.Agent
 		SecretVariables: environmentGroup.SecretVariables,
 		Version:         latestEnvironmentGroup.Version + 1,
 		CreatedAtUTC:    environmentGroup.CreatedAtUTC,
+		Author:          environmentGroup.Author,
 	}
 
 	err = createVersionedEnvironmentGroupInNamespace(ctx, a, newEnvironmentGroup, Namespace_EnvironmentGroups, additionalLabels)
@@ -138,6 +139,9 @@ func createVersionedEnvironmentGroupInNamespace(ctx context.Context, a *kubernet
 	for k, v := range additionalLabels {
 		configMap.Labels[k] = v
 	}
+	if environmentGroup.Author != "" {
+		configMap.Annotations = map[string]string{AnnotationKey_EnvironmentGroupAuthor: environmentGroup.Author}
+	}
 
 	err := createConfigMapWithVersion(ctx, a, configMap, environmentGroup.Version)
 	if err != nil {
@@ -164,6 +168,9 @@ func createVersionedEnvironmentGroupInNamespace(ctx context.Context, a *kubernet
 	for k, v := range additionalLabels {
 		secret.Labels[k] = v
 	}
+	if environmentGroup.Author != "" {
+		secret.Annotations = map[string]string{AnnotationKey_EnvironmentGroupAuthor: environmentGroup.Author}
+	}
 
 	err = createSecretWithVersion(ctx, a, secret, environmentGroup.Version)
 	if err != nil {
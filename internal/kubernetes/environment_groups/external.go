@@ -0,0 +1,106 @@
+package environment_groups
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/karagatandev/porter/internal/kubernetes"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ErrEnvironmentGroupTypeMismatch is returned when syncing an environment group from a provider, but an environment group of another type already exists with the same name
+type ErrEnvironmentGroupTypeMismatch struct {
+	Name         string
+	ExistingType string
+	Type         string
+}
+
+func (e ErrEnvironmentGroupTypeMismatch) Error() string {
+	existingType := e.ExistingType
+	if existingType == "" {
+		existingType = "porter"
+	}
+
+	return fmt.Sprintf("environment group %s already exists with type %s, not %s", e.Name, existingType, e.Type)
+}
+
+// SyncExternalBaseEnvironmentGroupInput contains all information required to publish the values resolved from an external provider as an environment group
+type SyncExternalBaseEnvironmentGroupInput struct {
+	// Name is the environment group name. The environment group is created if it does not exist
+	Name string
+	// Type is the type of the provider, which is set as the environment group type
+	Type string
+	// SecretVariables are the values resolved from the provider. All provider values are secret.
+	SecretVariables map[string]string
+	// Author is the email of the user who configured the provider, if the sync was started by a user
+	Author string
+}
+
+// SyncExternalBaseEnvironmentGroupOutput is the result of syncing an environment group from an external provider
+type SyncExternalBaseEnvironmentGroupOutput struct {
+	// Changed is true if the resolved values differ from the latest version, in which case a new version was published
+	Changed bool
+	// Version is the latest version of the environment group after the sync
+	Version int
+}
+
+// SyncExternalBaseEnvironmentGroup publishes the values resolved from an external provider as a new version of an environment group, then syncs it
+// to the namespaces of all linked applications. No version is published if the values are equal to the latest version.
+func SyncExternalBaseEnvironmentGroup(ctx context.Context, a *kubernetes.Agent, inp SyncExternalBaseEnvironmentGroupInput) (SyncExternalBaseEnvironmentGroupOutput, error) {
+	ctx, span := telemetry.NewSpan(ctx, "sync-external-base-env-group")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: inp.Name},
+		telemetry.AttributeKV{Key: "environment-group-type", Value: inp.Type},
+	)
+
+	var output SyncExternalBaseEnvironmentGroupOutput
+
+	if inp.Name == "" {
+		return output, telemetry.Error(ctx, span, nil, "environment group name cannot be empty")
+	}
+
+	latest, err := latestBaseEnvironmentGroup(ctx, a, inp.Name)
+	if err != nil {
+		return output, telemetry.Error(ctx, span, err, "unable to get latest base environment group")
+	}
+
+	if latest.Version != 0 && latest.Type != inp.Type {
+		return output, telemetry.Error(ctx, span, ErrEnvironmentGroupTypeMismatch{Name: inp.Name, ExistingType: latest.Type, Type: inp.Type}, "environment group type does not match provider type")
+	}
+
+	output.Version = latest.Version
+
+	if latest.Version != 0 && len(latest.Variables) == 0 && len(latest.Files) == 0 && equalValues(latest.SecretVariables, inp.SecretVariables) {
+		return output, nil
+	}
+
+	newVersion, err := publishBaseEnvironmentGroupVersion(ctx, a, EnvironmentGroup{
+		Name:            inp.Name,
+		SecretVariables: inp.SecretVariables,
+		Author:          inp.Author,
+	}, map[string]string{LabelKey_EnvironmentGroupType: inp.Type})
+	if err != nil {
+		return output, telemetry.Error(ctx, span, err, "unable to publish synced version")
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "new-version", Value: newVersion.Version})
+
+	output.Changed = true
+	output.Version = newVersion.Version
+
+	return output, nil
+}
+
+func equalValues(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if other, ok := b[k]; !ok || other != v {
+			return false
+		}
+	}
+
+	return true
+}
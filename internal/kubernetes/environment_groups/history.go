@@ -0,0 +1,302 @@
+package environment_groups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/karagatandev/porter/internal/kubernetes"
+	"github.com/karagatandev/porter/internal/telemetry"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListBaseEnvironmentGroupVersions returns all versions of an environment group stored in the porter-env-group namespace, newest first.
+// Secret values are replaced with a dummy value.
+func ListBaseEnvironmentGroupVersions(ctx context.Context, a *kubernetes.Agent, environmentGroupName string) ([]EnvironmentGroup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "list-base-env-group-versions")
+	defer span.End()
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName})
+
+	if environmentGroupName == "" {
+		return nil, telemetry.Error(ctx, span, nil, "environment group name cannot be empty")
+	}
+
+	versions, err := ListEnvironmentGroups(ctx, a, WithEnvironmentGroupName(environmentGroupName), WithNamespace(Namespace_EnvironmentGroups))
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "unable to list base environment groups")
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "versions", Value: len(versions)})
+
+	return versions, nil
+}
+
+// SetBaseEnvironmentGroupVersionAuthor records the email of the user who created a version of an environment group.
+// This is used for versions which are created outside of this package, such as by the cluster control plane.
+func SetBaseEnvironmentGroupVersionAuthor(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, version int, author string) error {
+	ctx, span := telemetry.NewSpan(ctx, "set-base-env-group-version-author")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName},
+		telemetry.AttributeKV{Key: "environment-group-version", Value: version},
+	)
+
+	if author == "" {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s.%d", environmentGroupName, version)
+
+	configMap, err := a.Clientset.CoreV1().ConfigMaps(Namespace_EnvironmentGroups).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to get environment group configmap")
+	}
+
+	setAuthorAnnotation(&configMap.ObjectMeta, author)
+
+	_, err = a.Clientset.CoreV1().ConfigMaps(Namespace_EnvironmentGroups).Update(ctx, configMap, metav1.UpdateOptions{})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to update environment group configmap")
+	}
+
+	secret, err := a.Clientset.CoreV1().Secrets(Namespace_EnvironmentGroups).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return nil
+		}
+		return telemetry.Error(ctx, span, err, "unable to get environment group secret")
+	}
+
+	setAuthorAnnotation(&secret.ObjectMeta, author)
+
+	_, err = a.Clientset.CoreV1().Secrets(Namespace_EnvironmentGroups).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to update environment group secret")
+	}
+
+	return nil
+}
+
+func setAuthorAnnotation(meta *metav1.ObjectMeta, author string) {
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	meta.Annotations[AnnotationKey_EnvironmentGroupAuthor] = author
+}
+
+// VariableChange describes how a key changed between two versions of an environment group
+type VariableChange string
+
+const (
+	// VariableChange_Added is a key which only exists in the newer version
+	VariableChange_Added VariableChange = "added"
+	// VariableChange_Removed is a key which only exists in the older version
+	VariableChange_Removed VariableChange = "removed"
+	// VariableChange_Changed is a key whose value differs between the versions
+	VariableChange_Changed VariableChange = "changed"
+	// VariableChange_Unchanged is a key whose value is the same in both versions
+	VariableChange_Unchanged VariableChange = "unchanged"
+)
+
+// VariableDiff is the difference of a single key between two versions of an environment group.
+// Values are never set for secret variables and files, so that only whether they changed is exposed.
+type VariableDiff struct {
+	Key      string         `json:"key"`
+	Change   VariableChange `json:"change"`
+	OldValue string         `json:"old_value,omitempty"`
+	NewValue string         `json:"new_value,omitempty"`
+}
+
+// EnvironmentGroupDiff is the key-level difference between two versions of an environment group
+type EnvironmentGroupDiff struct {
+	Name            string         `json:"name"`
+	FromVersion     int            `json:"from_version"`
+	ToVersion       int            `json:"to_version"`
+	Variables       []VariableDiff `json:"variables"`
+	SecretVariables []VariableDiff `json:"secret_variables"`
+	Files           []VariableDiff `json:"files"`
+}
+
+// ErrEnvironmentGroupVersionNotFound is returned when a requested version of an environment group does not exist
+type ErrEnvironmentGroupVersionNotFound struct {
+	Name    string
+	Version int
+}
+
+func (e ErrEnvironmentGroupVersionNotFound) Error() string {
+	return fmt.Sprintf("version %d of environment group %s does not exist", e.Version, e.Name)
+}
+
+// ErrEnvironmentGroupNotRollbackable is returned when rolling back an environment group which is synced from an external provider
+var ErrEnvironmentGroupNotRollbackable = errors.New("only porter environment groups can be rolled back")
+
+// baseEnvironmentGroupVersion returns a version of an environment group stored in the porter-env-group namespace, including its secret values
+func baseEnvironmentGroupVersion(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, version int) (EnvironmentGroup, error) {
+	var eg EnvironmentGroup
+
+	if version == 0 {
+		return eg, ErrEnvironmentGroupVersionNotFound{Name: environmentGroupName, Version: version}
+	}
+
+	environmentGroups, err := listEnvironmentGroups(ctx, a,
+		WithEnvironmentGroupName(environmentGroupName),
+		WithEnvironmentGroupVersion(version),
+		WithNamespace(Namespace_EnvironmentGroups),
+	)
+	if err != nil {
+		return eg, err
+	}
+
+	if len(environmentGroups) == 0 {
+		return eg, ErrEnvironmentGroupVersionNotFound{Name: environmentGroupName, Version: version}
+	}
+
+	return environmentGroups[0], nil
+}
+
+// DiffBaseEnvironmentGroupVersions returns the key-level difference between two versions of an environment group.
+// Secret variables and files are compared by value, but only whether they changed is returned.
+func DiffBaseEnvironmentGroupVersions(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, fromVersion, toVersion int) (EnvironmentGroupDiff, error) {
+	ctx, span := telemetry.NewSpan(ctx, "diff-base-env-group-versions")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName},
+		telemetry.AttributeKV{Key: "from-version", Value: fromVersion},
+		telemetry.AttributeKV{Key: "to-version", Value: toVersion},
+	)
+
+	diff := EnvironmentGroupDiff{
+		Name:        environmentGroupName,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+	}
+
+	from, err := baseEnvironmentGroupVersion(ctx, a, environmentGroupName, fromVersion)
+	if err != nil {
+		return diff, telemetry.Error(ctx, span, err, "unable to get from version")
+	}
+
+	to, err := baseEnvironmentGroupVersion(ctx, a, environmentGroupName, toVersion)
+	if err != nil {
+		return diff, telemetry.Error(ctx, span, err, "unable to get to version")
+	}
+
+	diff.Variables = diffVariables(from.Variables, to.Variables, false)
+	diff.SecretVariables = diffVariables(from.SecretVariables, to.SecretVariables, true)
+	diff.Files = diffVariables(filesByName(from.Files), filesByName(to.Files), true)
+
+	return diff, nil
+}
+
+func filesByName(files []EnvGroupFile) map[string]string {
+	res := make(map[string]string, len(files))
+	for _, file := range files {
+		res[file.Name] = file.Contents
+	}
+	return res
+}
+
+// diffVariables compares the keys of two versions, sorted by key. If maskValues is set, values are not included in the result.
+func diffVariables(from, to map[string]string, maskValues bool) []VariableDiff {
+	keys := make(map[string]bool)
+	for k := range from {
+		keys[k] = true
+	}
+	for k := range to {
+		keys[k] = true
+	}
+
+	diffs := make([]VariableDiff, 0, len(keys))
+	for k := range keys {
+		oldValue, inFrom := from[k]
+		newValue, inTo := to[k]
+
+		diff := VariableDiff{Key: k}
+
+		switch {
+		case !inFrom:
+			diff.Change = VariableChange_Added
+		case !inTo:
+			diff.Change = VariableChange_Removed
+		case oldValue != newValue:
+			diff.Change = VariableChange_Changed
+		default:
+			diff.Change = VariableChange_Unchanged
+		}
+
+		if !maskValues {
+			diff.OldValue = oldValue
+			diff.NewValue = newValue
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+
+	return diffs
+}
+
+// RollbackBaseEnvironmentGroupInput contains all information required to roll back an environment group to a previous version
+type RollbackBaseEnvironmentGroupInput struct {
+	// Name is the environment group name
+	Name string
+	// Version is the version to roll back to
+	Version int
+	// Author is the email of the user rolling back the environment group
+	Author string
+}
+
+// RollbackBaseEnvironmentGroup creates a new version of an environment group which is equal to a previous version, then syncs the new version to the
+// namespaces of all linked applications. The new version is returned with its secret values replaced with a dummy value.
+// Environment groups which are synced from an external provider can't be rolled back, since their values are owned by the provider.
+func RollbackBaseEnvironmentGroup(ctx context.Context, a *kubernetes.Agent, inp RollbackBaseEnvironmentGroupInput) (EnvironmentGroup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "rollback-base-env-group")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: inp.Name},
+		telemetry.AttributeKV{Key: "rollback-version", Value: inp.Version},
+	)
+
+	var eg EnvironmentGroup
+
+	target, err := baseEnvironmentGroupVersion(ctx, a, inp.Name, inp.Version)
+	if err != nil {
+		return eg, telemetry.Error(ctx, span, err, "unable to get version to roll back to")
+	}
+
+	if target.Type != "" && target.Type != "porter" {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-type", Value: target.Type})
+		return eg, telemetry.Error(ctx, span, ErrEnvironmentGroupNotRollbackable, "environment group is synced from an external provider")
+	}
+
+	additionalLabels := map[string]string{}
+	if target.Type != "" {
+		additionalLabels[LabelKey_EnvironmentGroupType] = target.Type
+	}
+	if target.DefaultAppEnvironment {
+		additionalLabels[LabelKey_DefaultAppEnvironment] = "true"
+	}
+
+	latest, err := publishBaseEnvironmentGroupVersion(ctx, a, EnvironmentGroup{
+		Name:            target.Name,
+		Variables:       target.Variables,
+		SecretVariables: target.SecretVariables,
+		Files:           target.Files,
+		Author:          inp.Author,
+	}, additionalLabels)
+	if err != nil {
+		return eg, telemetry.Error(ctx, span, err, "unable to publish rollback version")
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "new-version", Value: latest.Version})
+
+	return latest, nil
+}
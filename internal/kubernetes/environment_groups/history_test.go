@@ -0,0 +1,225 @@
+package environment_groups
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/karagatandev/porter/internal/kubernetes"
+)
+
+func TestDiffVariables(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       map[string]string
+		to         map[string]string
+		maskValues bool
+		expected   []VariableDiff
+	}{
+		{
+			name:     "no variables",
+			expected: []VariableDiff{},
+		},
+		{
+			name: "added, removed, changed and unchanged keys sorted by key",
+			from: map[string]string{"PORT": "80", "LOG_LEVEL": "info", "REGION": "us-east-1"},
+			to:   map[string]string{"PORT": "8080", "LOG_LEVEL": "info", "WORKERS": "4"},
+			expected: []VariableDiff{
+				{Key: "LOG_LEVEL", Change: VariableChange_Unchanged, OldValue: "info", NewValue: "info"},
+				{Key: "PORT", Change: VariableChange_Changed, OldValue: "80", NewValue: "8080"},
+				{Key: "REGION", Change: VariableChange_Removed, OldValue: "us-east-1"},
+				{Key: "WORKERS", Change: VariableChange_Added, NewValue: "4"},
+			},
+		},
+		{
+			name:       "masked values are compared but not returned",
+			from:       map[string]string{"DB_PASSWORD": "hunter2", "API_KEY": "abc", "OLD_TOKEN": "xyz"},
+			to:         map[string]string{"DB_PASSWORD": "hunter3", "API_KEY": "abc", "NEW_TOKEN": "xyz"},
+			maskValues: true,
+			expected: []VariableDiff{
+				{Key: "API_KEY", Change: VariableChange_Unchanged},
+				{Key: "DB_PASSWORD", Change: VariableChange_Changed},
+				{Key: "NEW_TOKEN", Change: VariableChange_Added},
+				{Key: "OLD_TOKEN", Change: VariableChange_Removed},
+			},
+		},
+		{
+			name: "empty values are distinguished from missing keys",
+			from: map[string]string{"FEATURE_FLAG": ""},
+			to:   map[string]string{"FEATURE_FLAG": "", "DEBUG": ""},
+			expected: []VariableDiff{
+				{Key: "DEBUG", Change: VariableChange_Added},
+				{Key: "FEATURE_FLAG", Change: VariableChange_Unchanged},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs := diffVariables(tt.from, tt.to, tt.maskValues)
+
+			if !reflect.DeepEqual(diffs, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, diffs)
+			}
+		})
+	}
+}
+
+func TestDiffBaseEnvironmentGroupVersions(t *testing.T) {
+	ctx := context.Background()
+	agent := kubernetes.GetAgentTesting()
+
+	createEnvGroupVersion(t, agent, EnvironmentGroup{
+		Name:            "shared",
+		Variables:       map[string]string{"PORT": "80", "REGION": "us-east-1"},
+		SecretVariables: map[string]string{"DB_PASSWORD": "hunter2"},
+	})
+	createEnvGroupVersion(t, agent, EnvironmentGroup{
+		Name:            "shared",
+		Variables:       map[string]string{"PORT": "8080", "REGION": "us-east-1"},
+		SecretVariables: map[string]string{"DB_PASSWORD": "hunter3", "API_KEY": "abc"},
+	})
+
+	diff, err := DiffBaseEnvironmentGroupVersions(ctx, agent, "shared", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := EnvironmentGroupDiff{
+		Name:        "shared",
+		FromVersion: 1,
+		ToVersion:   2,
+		Variables: []VariableDiff{
+			{Key: "PORT", Change: VariableChange_Changed, OldValue: "80", NewValue: "8080"},
+			{Key: "REGION", Change: VariableChange_Unchanged, OldValue: "us-east-1", NewValue: "us-east-1"},
+		},
+		SecretVariables: []VariableDiff{
+			{Key: "API_KEY", Change: VariableChange_Added},
+			{Key: "DB_PASSWORD", Change: VariableChange_Changed},
+		},
+		Files: []VariableDiff{},
+	}
+
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("expected %+v, got %+v", expected, diff)
+	}
+
+	_, err = DiffBaseEnvironmentGroupVersions(ctx, agent, "shared", 1, 3)
+
+	var notFound ErrEnvironmentGroupVersionNotFound
+	if !errors.As(err, &notFound) || notFound.Version != 3 {
+		t.Errorf("expected version 3 not to be found, got %v", err)
+	}
+}
+
+func TestRollbackBaseEnvironmentGroup(t *testing.T) {
+	ctx := context.Background()
+	agent := kubernetes.GetAgentTesting()
+
+	createEnvGroupVersion(t, agent, EnvironmentGroup{
+		Name:            "shared",
+		Variables:       map[string]string{"PORT": "80"},
+		SecretVariables: map[string]string{"DB_PASSWORD": "hunter2"},
+	})
+	createEnvGroupVersion(t, agent, EnvironmentGroup{
+		Name:            "shared",
+		Variables:       map[string]string{"PORT": "8080", "WORKERS": "4"},
+		SecretVariables: map[string]string{"DB_PASSWORD": "hunter3"},
+	})
+
+	rolledBack, err := RollbackBaseEnvironmentGroup(ctx, agent, RollbackBaseEnvironmentGroupInput{
+		Name:    "shared",
+		Version: 1,
+		Author:  "admin@example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rolledBack.Version != 3 {
+		t.Errorf("expected rollback to create version 3, got %d", rolledBack.Version)
+	}
+
+	if rolledBack.SecretVariables["DB_PASSWORD"] != EnvGroupSecretDummyValue {
+		t.Errorf("expected secret values of the new version to be masked, got %s", rolledBack.SecretVariables["DB_PASSWORD"])
+	}
+
+	latest, err := latestBaseEnvironmentGroup(ctx, agent, "shared")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if latest.Version != 3 {
+		t.Fatalf("expected version 3 to be the latest version, got %d", latest.Version)
+	}
+
+	if !reflect.DeepEqual(latest.Variables, map[string]string{"PORT": "80"}) {
+		t.Errorf("expected the variables of version 1, got %v", latest.Variables)
+	}
+
+	if !reflect.DeepEqual(latest.SecretVariables, map[string]string{"DB_PASSWORD": "hunter2"}) {
+		t.Errorf("expected the secret variables of version 1, got %v", latest.SecretVariables)
+	}
+
+	if latest.Author != "admin@example.com" {
+		t.Errorf("expected the rollback author to be recorded, got %s", latest.Author)
+	}
+
+	versions, err := ListBaseEnvironmentGroupVersions(ctx, agent, "shared")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(versions) != 3 {
+		t.Errorf("expected earlier versions to be kept, got %d versions", len(versions))
+	}
+}
+
+func TestRollbackBaseEnvironmentGroupErrors(t *testing.T) {
+	ctx := context.Background()
+	agent := kubernetes.GetAgentTesting()
+
+	createEnvGroupVersion(t, agent, EnvironmentGroup{
+		Name:      "shared",
+		Variables: map[string]string{"PORT": "80"},
+	})
+
+	_, err := RollbackBaseEnvironmentGroup(ctx, agent, RollbackBaseEnvironmentGroupInput{Name: "shared", Version: 2})
+
+	var notFound ErrEnvironmentGroupVersionNotFound
+	if !errors.As(err, &notFound) {
+		t.Errorf("expected version 2 not to be found, got %v", err)
+	}
+
+	err = CreateOrUpdateBaseEnvironmentGroup(ctx, agent, EnvironmentGroup{
+		Name:      "vault",
+		Variables: map[string]string{"PORT": "80"},
+	}, map[string]string{LabelKey_EnvironmentGroupType: "vault"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = RollbackBaseEnvironmentGroup(ctx, agent, RollbackBaseEnvironmentGroupInput{Name: "vault", Version: 1})
+	if !errors.Is(err, ErrEnvironmentGroupNotRollbackable) {
+		t.Errorf("expected ErrEnvironmentGroupNotRollbackable, got %v", err)
+	}
+
+	versions, err := ListBaseEnvironmentGroupVersions(ctx, agent, "vault")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(versions) != 1 {
+		t.Errorf("expected no version to be created, got %d versions", len(versions))
+	}
+}
+
+// createEnvGroupVersion creates the next version of a porter environment group
+func createEnvGroupVersion(t *testing.T, agent *kubernetes.Agent, environmentGroup EnvironmentGroup) {
+	t.Helper()
+
+	if err := CreateOrUpdateBaseEnvironmentGroup(context.Background(), agent, environmentGroup, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package environment_groups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/karagatandev/porter/internal/kubernetes"
+	"github.com/karagatandev/porter/internal/telemetry"
+	v1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrEnvironmentGroupNotImportable is returned when importing into an environment group which is synced from an external provider
+var ErrEnvironmentGroupNotImportable = errors.New("only porter environment groups can be imported into")
+
+// ImportBaseEnvironmentGroupInput contains all information required to import variables, secrets and files into an environment group
+type ImportBaseEnvironmentGroupInput struct {
+	// Name is the environment group name. The environment group is created if it does not exist
+	Name string
+	// Variables are the imported non-secret values
+	Variables map[string]string
+	// SecretVariables are the imported secret values. Values equal to EnvGroupSecretDummyValue keep the existing value
+	SecretVariables map[string]string
+	// Files are the imported files
+	Files []EnvGroupFile
+	// Replace removes all keys and files of the latest version which are not imported. Otherwise, the imported keys are merged into the latest version
+	Replace bool
+	// DryRun returns the difference the import would make without creating a new version
+	DryRun bool
+	// Author is the email of the user importing into the environment group
+	Author string
+}
+
+// ImportBaseEnvironmentGroupOutput is the result of an import
+type ImportBaseEnvironmentGroupOutput struct {
+	// Diff is the difference between the latest version before the import and the imported version
+	Diff EnvironmentGroupDiff
+	// EnvironmentGroup is the new version, with its secret values replaced with a dummy value. It is empty for dry runs.
+	EnvironmentGroup EnvironmentGroup
+}
+
+// ImportBaseEnvironmentGroup creates a new version of an environment group from imported values in a single step, then syncs it to the namespaces
+// of all linked applications. If any step fails, the new version is removed so that the latest version is unchanged.
+func ImportBaseEnvironmentGroup(ctx context.Context, a *kubernetes.Agent, inp ImportBaseEnvironmentGroupInput) (ImportBaseEnvironmentGroupOutput, error) {
+	ctx, span := telemetry.NewSpan(ctx, "import-base-env-group")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: inp.Name},
+		telemetry.AttributeKV{Key: "replace", Value: inp.Replace},
+		telemetry.AttributeKV{Key: "dry-run", Value: inp.DryRun},
+	)
+
+	var output ImportBaseEnvironmentGroupOutput
+
+	if inp.Name == "" {
+		return output, telemetry.Error(ctx, span, nil, "environment group name cannot be empty")
+	}
+
+	latest, err := latestBaseEnvironmentGroup(ctx, a, inp.Name)
+	if err != nil {
+		return output, telemetry.Error(ctx, span, err, "unable to get latest base environment group")
+	}
+
+	if latest.Type != "" && latest.Type != "porter" {
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-type", Value: latest.Type})
+		return output, telemetry.Error(ctx, span, ErrEnvironmentGroupNotImportable, "environment group is synced from an external provider")
+	}
+
+	imported := mergeImport(latest, inp)
+
+	output.Diff = EnvironmentGroupDiff{
+		Name:            inp.Name,
+		FromVersion:     latest.Version,
+		ToVersion:       latest.Version + 1,
+		Variables:       diffVariables(latest.Variables, imported.Variables, false),
+		SecretVariables: diffVariables(latest.SecretVariables, imported.SecretVariables, true),
+		Files:           diffVariables(filesByName(latest.Files), filesByName(imported.Files), true),
+	}
+
+	if inp.DryRun {
+		return output, nil
+	}
+
+	additionalLabels := map[string]string{}
+	if latest.Type != "" {
+		additionalLabels[LabelKey_EnvironmentGroupType] = latest.Type
+	}
+	if latest.DefaultAppEnvironment {
+		additionalLabels[LabelKey_DefaultAppEnvironment] = "true"
+	}
+
+	newVersion, err := publishBaseEnvironmentGroupVersion(ctx, a, imported, additionalLabels)
+	if err != nil {
+		return output, telemetry.Error(ctx, span, err, "unable to publish imported version")
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "new-version", Value: newVersion.Version})
+
+	output.Diff.ToVersion = newVersion.Version
+	output.EnvironmentGroup = newVersion
+
+	return output, nil
+}
+
+// mergeImport returns the environment group resulting from importing values into the latest version
+func mergeImport(latest EnvironmentGroup, inp ImportBaseEnvironmentGroupInput) EnvironmentGroup {
+	res := EnvironmentGroup{
+		Name:            inp.Name,
+		Variables:       make(map[string]string),
+		SecretVariables: make(map[string]string),
+		Author:          inp.Author,
+	}
+
+	files := make(map[string]string)
+
+	if !inp.Replace {
+		for k, v := range latest.Variables {
+			res.Variables[k] = v
+		}
+		for k, v := range latest.SecretVariables {
+			res.SecretVariables[k] = v
+		}
+		for _, file := range latest.Files {
+			files[file.Name] = file.Contents
+		}
+	}
+
+	// a key can only be a variable or a secret, so importing a key removes it from the other set
+	for k, v := range inp.Variables {
+		res.Variables[k] = v
+		delete(res.SecretVariables, k)
+	}
+	for k, v := range inp.SecretVariables {
+		if existing, ok := latest.SecretVariables[k]; ok && v == EnvGroupSecretDummyValue {
+			v = existing
+		}
+		res.SecretVariables[k] = v
+		delete(res.Variables, k)
+	}
+	for _, file := range inp.Files {
+		files[file.Name] = file.Contents
+	}
+
+	for name, contents := range files {
+		res.Files = append(res.Files, EnvGroupFile{Name: name, Contents: contents})
+	}
+	sort.Slice(res.Files, func(i, j int) bool {
+		return res.Files[i].Name < res.Files[j].Name
+	})
+
+	return res
+}
+
+// publishBaseEnvironmentGroupVersion creates a new version of an environment group including its files, and syncs it to the namespaces of all
+// linked applications. If any step fails, the new version is deleted from the base namespace and all namespaces it was synced to, so that
+// a version is either fully published or not at all. The new version is returned with its secret values replaced with a dummy value.
+func publishBaseEnvironmentGroupVersion(ctx context.Context, a *kubernetes.Agent, environmentGroup EnvironmentGroup, additionalLabels map[string]string) (EnvironmentGroup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "publish-base-env-group-version")
+	defer span.End()
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroup.Name})
+
+	var eg EnvironmentGroup
+
+	err := CreateOrUpdateBaseEnvironmentGroup(ctx, a, environmentGroup, additionalLabels)
+	if err != nil {
+		return eg, telemetry.Error(ctx, span, err, "unable to create new version")
+	}
+
+	latest, err := latestBaseEnvironmentGroup(ctx, a, environmentGroup.Name)
+	if err != nil {
+		return eg, telemetry.Error(ctx, span, err, "unable to get new version")
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "new-version", Value: latest.Version})
+
+	synced := []string{Namespace_EnvironmentGroups}
+
+	// undo removes the new version wherever it was created. Errors are recorded but not returned, so that the original error is returned
+	undo := func() {
+		for _, namespace := range synced {
+			if err := deleteEnvironmentGroupVersion(ctx, a, namespace, latest.Name, latest.Version); err != nil {
+				_ = telemetry.Error(ctx, span, err, "unable to remove new version after failure")
+			}
+		}
+	}
+
+	if len(environmentGroup.Files) > 0 {
+		err = createFileSecret(ctx, a, latest.Name, latest.Version, environmentGroup.Files, additionalLabels)
+		if err != nil {
+			undo()
+			return eg, telemetry.Error(ctx, span, err, "unable to create new version files")
+		}
+	}
+
+	linkedApps, err := LinkedApplications(ctx, a, environmentGroup.Name, false)
+	if err != nil {
+		undo()
+		return eg, telemetry.Error(ctx, span, err, "unable to list linked applications")
+	}
+
+	for _, app := range linkedApps {
+		if contains(synced, app.Namespace) {
+			continue
+		}
+
+		_, err := SyncLatestVersionToNamespace(ctx, a, SyncLatestVersionToNamespaceInput{
+			BaseEnvironmentGroupName: environmentGroup.Name,
+			TargetNamespace:          app.Namespace,
+		}, nil)
+		synced = append(synced, app.Namespace)
+		if err != nil {
+			undo()
+			return eg, telemetry.Error(ctx, span, err, "unable to sync new version to linked application namespace")
+		}
+	}
+
+	for k := range latest.SecretVariables {
+		latest.SecretVariables[k] = EnvGroupSecretDummyValue
+	}
+	latest.Files = nil
+
+	return latest, nil
+}
+
+func contains(namespaces []string, namespace string) bool {
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteEnvironmentGroupVersion deletes a single version of an environment group from a namespace
+func deleteEnvironmentGroupVersion(ctx context.Context, a *kubernetes.Agent, namespace, environmentGroupName string, version int) error {
+	name := fmt.Sprintf("%s.%d", environmentGroupName, version)
+
+	err := a.Clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+
+	err = a.Clientset.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+
+	err = a.Clientset.CoreV1().Secrets(namespace).Delete(ctx, envGroupFileSecretName(environmentGroupName, strconv.Itoa(version)), metav1.DeleteOptions{})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// createFileSecret creates the secret holding the files of an environment group version in the porter-env-group namespace
+func createFileSecret(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, version int, files []EnvGroupFile, additionalLabels map[string]string) error {
+	secretData := make(map[string][]byte)
+	for _, file := range files {
+		secretData[file.Name] = []byte(file.Contents)
+	}
+
+	secret := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      envGroupFileSecretName(environmentGroupName, strconv.Itoa(version)),
+			Namespace: Namespace_EnvironmentGroups,
+			Labels: map[string]string{
+				LabelKey_EnvironmentGroupName:    environmentGroupName,
+				LabelKey_EnvironmentGroupVersion: strconv.Itoa(version),
+				LabelKey_PorterManaged:           "true",
+				LabelKey_FileSecret:              "true",
+			},
+		},
+		Data: secretData,
+	}
+	for k, v := range additionalLabels {
+		secret.Labels[k] = v
+	}
+
+	return createSecretWithVersion(ctx, a, secret, version)
+}
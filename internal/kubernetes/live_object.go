@@ -0,0 +1,71 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// LiveObjectGetter fetches objects of any kind from the cluster, resolving their resources
+// through discovery
+type LiveObjectGetter struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+// NewLiveObjectGetter returns a LiveObjectGetter using the agent's credentials
+func (a *Agent) NewLiveObjectGetter() (*LiveObjectGetter, error) {
+	restConf, err := a.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := dynamic.NewForConfig(restConf)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper, err := a.RESTClientGetter.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LiveObjectGetter{
+		client: client,
+		mapper: mapper,
+	}, nil
+}
+
+// GetLiveObject returns the object with the given kind and name, or nil if it does not exist.
+// The namespace is ignored for cluster-scoped kinds.
+func (g *LiveObjectGetter) GetLiveObject(ctx context.Context, apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api version %s: %w", apiVersion, err)
+	}
+
+	mapping, err := g.mapper.RESTMapping(gv.WithKind(kind).GroupKind(), gv.Version)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s is not served by the cluster: %w", apiVersion, kind, err)
+	}
+
+	var resource dynamic.ResourceInterface = g.client.Resource(mapping.Resource)
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resource = g.client.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil && errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return obj.Object, nil
+}
@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	v1 "k8s.io/api/core/v1"
@@ -141,3 +142,33 @@ func ListNodesByLabels(clientset kubernetes.Interface, labelSelector string) ([]
 
 	return nodes.Items, nil
 }
+
+// ListArchitectures returns the sorted, distinct CPU architectures of the nodes in the cluster, such as amd64 and arm64
+func ListArchitectures(ctx context.Context, clientset kubernetes.Interface) ([]string, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	archSet := make(map[string]bool)
+
+	for _, node := range nodes.Items {
+		arch := node.Labels[v1.LabelArchStable]
+		if arch == "" {
+			arch = node.Status.NodeInfo.Architecture
+		}
+
+		if arch != "" {
+			archSet[arch] = true
+		}
+	}
+
+	archs := make([]string, 0, len(archSet))
+	for arch := range archSet {
+		archs = append(archs, arch)
+	}
+
+	sort.Strings(archs)
+
+	return archs, nil
+}
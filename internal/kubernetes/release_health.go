@@ -0,0 +1,438 @@
+package kubernetes
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sort"
+
+	"github.com/karagatandev/porter/internal/helm/grapher"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// failingWaitingReasons are the reasons of waiting containers which will not recover on their own
+var failingWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// GetReleaseHealth fetches the live status of the objects of a parsed release and returns its
+// graph annotated with them. The pods generated from controller templates are matched to the
+// pods selected by the live controller, in order of their names.
+func (a *Agent) GetReleaseHealth(ctx context.Context, parsed *grapher.ParsedObjs) (*grapher.HealthGraph, error) {
+	live := make(map[int]*grapher.LiveStatus)
+
+	// the live pods of each controller, keyed by the ID of the controller
+	controllerPods := make(map[int][]v1.Pod)
+
+	for _, obj := range parsed.Objects {
+		if isTemplatePod(obj) {
+			continue
+		}
+
+		status, selector, err := a.getLiveStatus(ctx, obj)
+		if err != nil {
+			return nil, fmt.Errorf("error getting status of %s %s/%s: %w", obj.Kind, obj.Namespace, obj.Name, err)
+		}
+
+		if selector != nil {
+			pods, err := a.listSelectedPods(ctx, obj.Namespace, selector)
+			if err != nil {
+				return nil, fmt.Errorf("error listing pods of %s %s/%s: %w", obj.Kind, obj.Namespace, obj.Name, err)
+			}
+
+			controllerPods[obj.ID] = pods
+		}
+
+		if status.Events, err = a.getLiveEvents(obj.Name, obj.Namespace); err != nil {
+			return nil, err
+		}
+
+		live[obj.ID] = status
+	}
+
+	for _, obj := range parsed.Objects {
+		if !isTemplatePod(obj) {
+			continue
+		}
+
+		parentID := obj.Relations.ControlRels[0].Source
+
+		// find which of the controller's template pods this is
+		index := 0
+
+		for _, o := range parsed.Objects {
+			if o.ID != parentID {
+				continue
+			}
+
+			for i, rel := range o.Relations.ControlRels {
+				if rel.Target == obj.ID {
+					index = i
+				}
+			}
+		}
+
+		pods := controllerPods[parentID]
+
+		if index >= len(pods) {
+			live[obj.ID] = &grapher.LiveStatus{
+				Health:  grapher.HealthProgressing,
+				Message: "pod has not been created",
+			}
+
+			continue
+		}
+
+		status := getPodStatus(&pods[index])
+
+		events, err := a.getLiveEvents(pods[index].Name, pods[index].Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		status.Events = events
+		live[obj.ID] = status
+	}
+
+	return parsed.Graph().WithHealth(live), nil
+}
+
+// isTemplatePod checks if an object is a pod generated from the template of a controller,
+// rather than a pod in the release's manifest
+func isTemplatePod(obj grapher.Object) bool {
+	return obj.Kind == "Pod" && len(obj.Relations.ControlRels) > 0 && obj.Relations.ControlRels[0].Target == obj.ID
+}
+
+// getLiveStatus returns the live status of an object, along with the selector of its pods
+// if it is a controller
+func (a *Agent) getLiveStatus(ctx context.Context, obj grapher.Object) (*grapher.LiveStatus, *metav1.LabelSelector, error) {
+	var (
+		status   *grapher.LiveStatus
+		selector *metav1.LabelSelector
+		err      error
+	)
+
+	switch obj.Kind {
+	case "Deployment":
+		var depl *appsv1.Deployment
+
+		if depl, err = a.GetDeployment(obj); err == nil {
+			status, selector = getDeploymentStatus(depl), depl.Spec.Selector
+		}
+	case "StatefulSet":
+		var ss *appsv1.StatefulSet
+
+		if ss, err = a.GetStatefulSet(obj); err == nil {
+			status, selector = getRolloutStatus(ss.Generation, ss.Status.ObservedGeneration, ss.Spec.Replicas, ss.Status.UpdatedReplicas, ss.Status.ReadyReplicas, ss.Status.AvailableReplicas), ss.Spec.Selector
+		}
+	case "ReplicaSet":
+		var rs *appsv1.ReplicaSet
+
+		if rs, err = a.GetReplicaSet(obj); err == nil {
+			status, selector = getRolloutStatus(rs.Generation, rs.Status.ObservedGeneration, rs.Spec.Replicas, rs.Status.FullyLabeledReplicas, rs.Status.ReadyReplicas, rs.Status.AvailableReplicas), rs.Spec.Selector
+		}
+	case "DaemonSet":
+		var ds *appsv1.DaemonSet
+
+		if ds, err = a.GetDaemonSet(obj); err == nil {
+			desired := ds.Status.DesiredNumberScheduled
+			status, selector = getRolloutStatus(ds.Generation, ds.Status.ObservedGeneration, &desired, ds.Status.UpdatedNumberScheduled, ds.Status.NumberReady, ds.Status.NumberAvailable), ds.Spec.Selector
+		}
+	case "Job":
+		var job *batchv1.Job
+
+		if job, err = a.GetJob(obj); err == nil {
+			status, selector = getJobStatus(job), job.Spec.Selector
+		}
+	case "Pod":
+		var pod *v1.Pod
+
+		if pod, err = a.GetPodByName(obj.Name, obj.Namespace); err == nil {
+			status = getPodStatus(pod)
+		}
+	case "Service":
+		status, err = a.getServiceStatus(ctx, obj)
+	case "Ingress":
+		status, err = a.getIngressStatus(obj)
+	case "PersistentVolumeClaim":
+		var pvc *v1.PersistentVolumeClaim
+
+		pvc, err = a.Clientset.CoreV1().PersistentVolumeClaims(obj.Namespace).Get(ctx, obj.Name, metav1.GetOptions{})
+		if err == nil {
+			status = getPVCStatus(pvc)
+		}
+	default:
+		status = &grapher.LiveStatus{Health: grapher.HealthUnknown}
+	}
+
+	if goerrors.Is(err, IsNotFoundError) || errors.IsNotFound(err) {
+		return &grapher.LiveStatus{Health: grapher.HealthDegraded, Message: "not found"}, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	return status, selector, nil
+}
+
+func (a *Agent) listSelectedPods(ctx context.Context, namespace string, selector *metav1.LabelSelector) ([]v1.Pod, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := a.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	return pods.Items, nil
+}
+
+func (a *Agent) getLiveEvents(name, namespace string) ([]grapher.LiveEvent, error) {
+	eventList, err := a.ListEvents(name, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error listing events of %s/%s: %w", namespace, name, err)
+	}
+
+	events := make([]grapher.LiveEvent, 0, len(eventList.Items))
+
+	for _, event := range eventList.Items {
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+
+		events = append(events, grapher.LiveEvent{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  event.Message,
+			Count:    event.Count,
+			LastSeen: lastSeen,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastSeen.After(events[j].LastSeen)
+	})
+
+	return events, nil
+}
+
+func getDeploymentStatus(depl *appsv1.Deployment) *grapher.LiveStatus {
+	for _, cond := range depl.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			status := getRolloutStatus(depl.Generation, depl.Status.ObservedGeneration, depl.Spec.Replicas, depl.Status.UpdatedReplicas, depl.Status.ReadyReplicas, depl.Status.AvailableReplicas)
+			status.Health = grapher.HealthDegraded
+			status.Message = cond.Message
+
+			return status
+		}
+	}
+
+	return getRolloutStatus(depl.Generation, depl.Status.ObservedGeneration, depl.Spec.Replicas, depl.Status.UpdatedReplicas, depl.Status.ReadyReplicas, depl.Status.AvailableReplicas)
+}
+
+// getRolloutStatus returns the status of a controller's rollout, which is progressing until
+// every desired replica is updated and available
+func getRolloutStatus(generation, observedGeneration int64, replicas *int32, updated, ready, available int32) *grapher.LiveStatus {
+	// replicas defaults to 1 when unset
+	desired := int32(1)
+	if replicas != nil {
+		desired = *replicas
+	}
+
+	status := &grapher.LiveStatus{
+		Health: grapher.HealthHealthy,
+		Rollout: &grapher.RolloutStatus{
+			Desired:   desired,
+			Updated:   updated,
+			Ready:     ready,
+			Available: available,
+		},
+	}
+
+	switch {
+	case observedGeneration < generation:
+		status.Health = grapher.HealthProgressing
+		status.Message = "waiting for the rollout to be observed"
+	case updated < desired:
+		status.Health = grapher.HealthProgressing
+		status.Message = fmt.Sprintf("%d of %d replicas updated", updated, desired)
+	case available < desired:
+		status.Health = grapher.HealthProgressing
+		status.Message = fmt.Sprintf("%d of %d replicas available", available, desired)
+	}
+
+	return status
+}
+
+func getJobStatus(job *batchv1.Job) *grapher.LiveStatus {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != v1.ConditionTrue {
+			continue
+		}
+
+		switch cond.Type {
+		case batchv1.JobFailed:
+			return &grapher.LiveStatus{Health: grapher.HealthDegraded, Message: cond.Message}
+		case batchv1.JobComplete:
+			return &grapher.LiveStatus{Health: grapher.HealthHealthy}
+		}
+	}
+
+	return &grapher.LiveStatus{
+		Health:  grapher.HealthProgressing,
+		Message: fmt.Sprintf("%d active, %d succeeded, %d failed", job.Status.Active, job.Status.Succeeded, job.Status.Failed),
+	}
+}
+
+func getPodStatus(pod *v1.Pod) *grapher.LiveStatus {
+	podStatus := &grapher.PodStatus{
+		Name:  pod.Name,
+		Phase: string(pod.Status.Phase),
+	}
+
+	status := &grapher.LiveStatus{Pod: podStatus}
+
+	ready := true
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		podStatus.Restarts += cs.RestartCount
+
+		if terminated := cs.LastTerminationState.Terminated; terminated != nil && podStatus.LastTerminationReason == "" {
+			podStatus.LastTerminationReason = terminated.Reason
+		}
+
+		if waiting := cs.State.Waiting; waiting != nil && failingWaitingReasons[waiting.Reason] {
+			status.Health = grapher.HealthDegraded
+			status.Message = fmt.Sprintf("container %s: %s", cs.Name, waiting.Reason)
+		}
+
+		ready = ready && cs.Ready
+	}
+
+	if status.Health != "" {
+		return status
+	}
+
+	switch pod.Status.Phase {
+	case v1.PodSucceeded:
+		status.Health = grapher.HealthHealthy
+	case v1.PodFailed:
+		status.Health = grapher.HealthDegraded
+		status.Message = pod.Status.Message
+	case v1.PodPending:
+		status.Health = grapher.HealthProgressing
+	case v1.PodRunning:
+		if ready {
+			status.Health = grapher.HealthHealthy
+		} else {
+			status.Health = grapher.HealthProgressing
+			status.Message = "containers are not ready"
+		}
+	default:
+		status.Health = grapher.HealthUnknown
+	}
+
+	return status
+}
+
+func (a *Agent) getServiceStatus(ctx context.Context, obj grapher.Object) (*grapher.LiveStatus, error) {
+	svc, err := a.Clientset.CoreV1().Services(obj.Namespace).Get(ctx, obj.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// services without a selector have their endpoints managed elsewhere
+	if svc.Spec.Type == v1.ServiceTypeExternalName || len(svc.Spec.Selector) == 0 {
+		return &grapher.LiveStatus{Health: grapher.HealthUnknown}, nil
+	}
+
+	endpoints, err := a.Clientset.CoreV1().Endpoints(obj.Namespace).Get(ctx, obj.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	count := 0
+
+	if endpoints != nil {
+		for _, subset := range endpoints.Subsets {
+			count += len(subset.Addresses)
+		}
+	}
+
+	status := &grapher.LiveStatus{
+		Health:    grapher.HealthHealthy,
+		Endpoints: &count,
+	}
+
+	if count == 0 {
+		status.Health = grapher.HealthDegraded
+		status.Message = "no ready endpoints"
+	}
+
+	return status, nil
+}
+
+func (a *Agent) getIngressStatus(obj grapher.Object) (*grapher.LiveStatus, error) {
+	ingress, err := a.GetNetworkingV1Ingress(obj.Namespace, obj.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &grapher.LiveStatus{
+		Health:    grapher.HealthHealthy,
+		Addresses: []string{},
+	}
+
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			status.Addresses = append(status.Addresses, lb.Hostname)
+		} else if lb.IP != "" {
+			status.Addresses = append(status.Addresses, lb.IP)
+		}
+	}
+
+	if len(status.Addresses) == 0 {
+		status.Health = grapher.HealthProgressing
+		status.Message = "waiting for an address"
+	}
+
+	return status, nil
+}
+
+func getPVCStatus(pvc *v1.PersistentVolumeClaim) *grapher.LiveStatus {
+	status := &grapher.LiveStatus{
+		Binding: string(pvc.Status.Phase),
+	}
+
+	switch pvc.Status.Phase {
+	case v1.ClaimBound:
+		status.Health = grapher.HealthHealthy
+		status.Message = fmt.Sprintf("bound to %s", pvc.Spec.VolumeName)
+	case v1.ClaimPending:
+		status.Health = grapher.HealthProgressing
+	case v1.ClaimLost:
+		status.Health = grapher.HealthDegraded
+		status.Message = "bound volume was lost"
+	default:
+		status.Health = grapher.HealthUnknown
+	}
+
+	return status
+}
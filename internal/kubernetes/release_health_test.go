@@ -0,0 +1,111 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/karagatandev/porter/internal/helm/grapher"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const healthManifest = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web
+  ports:
+    - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+        - name: web
+          image: nginx
+`
+
+func TestGetReleaseHealth(t *testing.T) {
+	replicas := int32(1)
+	labels := map[string]string{"app": "web"}
+
+	k8sAgent := newAgentFixture(t,
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+			},
+			Status: appsv1.DeploymentStatus{UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default", Labels: labels},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         "web",
+						RestartCount: 4,
+						State: v1.ContainerState{
+							Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+						},
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled"},
+						},
+					},
+				},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Selector: labels},
+		},
+	)
+
+	parsed := grapher.ParsedObjs{
+		Objects: grapher.ParseObjs(grapher.ImportMultiDocYAML([]byte(healthManifest)), "default"),
+	}
+
+	parsed.GetControlRel()
+	parsed.GetLabelRel()
+	parsed.GetSpecRel()
+	parsed.GetResourceRel()
+
+	health, err := k8sAgent.GetReleaseHealth(context.Background(), &parsed)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	service, deployment, pod := health.Nodes[0], health.Nodes[1], health.Nodes[2]
+
+	if pod.Live.Pod == nil || pod.Live.Pod.Name != "web-abc" || pod.Live.Pod.Restarts != 4 || pod.Live.Pod.LastTerminationReason != "OOMKilled" {
+		t.Errorf("Expected the template pod to be matched to the live pod, got %+v", pod.Live.Pod)
+	}
+
+	if pod.Health != grapher.HealthDegraded {
+		t.Errorf("Expected the crashing pod to be degraded, got %s", pod.Health)
+	}
+
+	if deployment.Live.Health != grapher.HealthHealthy || deployment.Health != grapher.HealthDegraded {
+		t.Errorf("Expected the rolled out deployment to be degraded by its pod, got %s (own %s)", deployment.Health, deployment.Live.Health)
+	}
+
+	// the service has no endpoints object
+	if service.Live.Endpoints == nil || *service.Live.Endpoints != 0 || service.Health != grapher.HealthDegraded {
+		t.Errorf("Expected the service without endpoints to be degraded, got %+v", service.Live)
+	}
+}
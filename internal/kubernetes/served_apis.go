@@ -0,0 +1,37 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/karagatandev/porter/internal/helm/deprecation"
+	"k8s.io/client-go/discovery"
+)
+
+// GetServedAPIs returns the version of the cluster and the API versions and kinds it serves.
+// API groups whose discovery failed, such as unavailable aggregated APIs, are left out.
+func (a *Agent) GetServedAPIs() (*deprecation.ServedAPIs, error) {
+	serverVersion, err := a.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster version: %w", err)
+	}
+
+	version, err := deprecation.ParseVersion(serverVersion.Major, serverVersion.Minor)
+	if err != nil {
+		return nil, err
+	}
+
+	_, resourceLists, err := a.Clientset.Discovery().ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("error getting cluster resources: %w", err)
+	}
+
+	kinds := make(map[string][]string)
+
+	for _, resourceList := range resourceLists {
+		for _, resource := range resourceList.APIResources {
+			kinds[resourceList.GroupVersion] = append(kinds[resourceList.GroupVersion], resource.Kind)
+		}
+	}
+
+	return deprecation.NewServedAPIs(version, kinds), nil
+}
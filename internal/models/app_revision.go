@@ -55,8 +55,45 @@ const (
 	AppRevisionStatus_ApplyFailed AppRevisionStatus = "APPLY_FAILED"
 	// AppRevisionStatus_UpdateFailed is the status for a revision that failed due to an internal system error
 	AppRevisionStatus_UpdateFailed AppRevisionStatus = "UPDATE_FAILED"
+	// AppRevisionStatus_AwaitingApproval is the status for a revision which is held until it is approved for its deployment target
+	AppRevisionStatus_AwaitingApproval AppRevisionStatus = "AWAITING_APPROVAL"
+	// AppRevisionStatus_Approved is the status for a revision which was approved for its deployment target
+	AppRevisionStatus_Approved AppRevisionStatus = "APPROVED"
+	// AppRevisionStatus_ApprovalRejected is the status for a revision which was rejected for its deployment target, and will not be deployed
+	AppRevisionStatus_ApprovalRejected AppRevisionStatus = "APPROVAL_REJECTED"
+	// AppRevisionStatus_ApprovalExpired is the status for a revision which was not approved in time, and will not be deployed
+	AppRevisionStatus_ApprovalExpired AppRevisionStatus = "APPROVAL_EXPIRED"
 )
 
+// approvalTransitions are the statuses which a revision held for approval may move to from each approval status
+var approvalTransitions = map[AppRevisionStatus][]AppRevisionStatus{
+	AppRevisionStatus_AwaitingApproval: {
+		AppRevisionStatus_Approved,
+		AppRevisionStatus_ApprovalRejected,
+		AppRevisionStatus_ApprovalExpired,
+	},
+}
+
+// CanTransitionApproval returns true if a revision held for approval may move from its current approval status to next.
+// Approved, rejected and expired revisions are final.
+func (s AppRevisionStatus) CanTransitionApproval(next AppRevisionStatus) bool {
+	for _, status := range approvalTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DeployedAppRevisionStatuses are the statuses of revisions which are, or were, running in their deployment target
+var DeployedAppRevisionStatuses = []AppRevisionStatus{
+	AppRevisionStatus_InstallSuccessful,
+	AppRevisionStatus_DeploymentSuccessful,
+	AppRevisionStatus_DeploymentProgressing,
+	AppRevisionStatus_RollbackSuccessful,
+}
+
 // AppRevision represents the full spec for a revision of a porter app
 type AppRevision struct {
 	gorm.Model
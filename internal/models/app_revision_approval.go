@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// AppRevisionApproval holds a revision of an app until it is approved for a deployment target which requires approval
+type AppRevisionApproval struct {
+	gorm.Model
+
+	ProjectID          uint `gorm:"index:idx_app_revision_approvals_project_app"`
+	ClusterID          uint
+	DeploymentTargetID uuid.UUID `gorm:"type:uuid"`
+	AppName            string    `gorm:"index:idx_app_revision_approvals_project_app"`
+
+	// AppRevisionID is the id of the revision awaiting approval. Applies of an image which is not built are only sent to
+	// the cluster control plane once they are approved, so the id is generated here and DeployedAppRevisionID is set to the
+	// revision created on approval.
+	AppRevisionID         string `gorm:"uniqueIndex"`
+	DeployedAppRevisionID string
+
+	// AppDigest is the sha256 digest of the app which was held, so that follow up applies to the revision can only
+	// continue it with the same app
+	AppDigest string
+
+	// Status is AppRevisionStatus_AwaitingApproval until the revision is approved, rejected or expires
+	Status    AppRevisionStatus `gorm:"index"`
+	ExpiresAt time.Time
+
+	// BuildRequired is set for revisions whose image is built after they are created. BuildSucceeded is set once the
+	// image is ready, so that the revision rolls out as soon as it is both built and approved.
+	BuildRequired  bool
+	BuildSucceeded bool
+
+	// DeferredApply is the encoded cluster control plane update of an apply of an image which is not built. It carries
+	// the app's secrets, so it is encrypted before it is written, and it is cleared once the revision is decided.
+	DeferredApply []byte
+
+	// RequestedByUserID is set when the apply was made by a user, and RequestedByAPITokenID when it was made with a
+	// project API token
+	RequestedByUserID     uint
+	RequestedByAPITokenID string
+
+	DecidedByUserID     uint
+	DecidedByAPITokenID string
+	DecidedAt           *time.Time
+	Reason              string
+}
+
+// Deferred returns true if the apply of the revision is held by Porter rather than by the cluster control plane
+func (a *AppRevisionApproval) Deferred() bool {
+	return len(a.DeferredApply) > 0
+}
+
+// ToAppRevisionApprovalType generates an external types.AppRevisionApproval to be shared over REST
+func (a *AppRevisionApproval) ToAppRevisionApprovalType() *types.AppRevisionApproval {
+	return &types.AppRevisionApproval{
+		ID:                    a.ID,
+		CreatedAt:             a.CreatedAt,
+		DeploymentTargetID:    a.DeploymentTargetID,
+		AppName:               a.AppName,
+		AppRevisionID:         a.AppRevisionID,
+		DeployedAppRevisionID: a.DeployedAppRevisionID,
+		Status:                string(a.Status),
+		AwaitingBuild:         a.BuildRequired && !a.BuildSucceeded,
+		ExpiresAt:             a.ExpiresAt,
+		RequestedByUserID:     a.RequestedByUserID,
+		RequestedByAPITokenID: a.RequestedByAPITokenID,
+		DecidedByUserID:       a.DecidedByUserID,
+		DecidedByAPITokenID:   a.DecidedByAPITokenID,
+		DecidedAt:             a.DecidedAt,
+		Reason:                a.Reason,
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// AuditLog is a record of a single mutating API request made against a project
+type AuditLog struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+
+	// UserID is set when the request was made by a user, and APITokenID when it
+	// was made with a project API token
+	UserID     uint   `gorm:"index"`
+	APITokenID string `gorm:"index"`
+
+	Verb   types.APIVerb
+	Method types.HTTPVerb
+	Path   string
+
+	// Resources is the JSON-encoded map of permission scopes to the resources that
+	// the request targeted
+	Resources []byte
+
+	// RequestSummary is the request body with secret values redacted
+	RequestSummary string
+
+	StatusCode int
+	Outcome    types.AuditLogOutcome
+}
+
+// ToAuditLogType generates an external types.AuditLog to be shared over REST
+func (a *AuditLog) ToAuditLogType() *types.AuditLog {
+	resources := make(map[types.PermissionScope]string)
+
+	if len(a.Resources) > 0 {
+		// a malformed resource map should not prevent the log from being read
+		_ = json.Unmarshal(a.Resources, &resources)
+	}
+
+	return &types.AuditLog{
+		ID:             a.ID,
+		CreatedAt:      a.CreatedAt,
+		ProjectID:      a.ProjectID,
+		UserID:         a.UserID,
+		APITokenID:     a.APITokenID,
+		Verb:           a.Verb,
+		Method:         a.Method,
+		Path:           a.Path,
+		Resources:      resources,
+		RequestSummary: a.RequestSummary,
+		StatusCode:     a.StatusCode,
+		Outcome:        a.Outcome,
+	}
+}
+
+// AuditLogSettings stores the audit log settings of a project
+type AuditLogSettings struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"uniqueIndex"`
+
+	// RetentionDays is how many days the audit logs of the project are kept. If 0, audit logs
+	// are kept for the server's default retention.
+	RetentionDays uint
+}
+
+// Retention returns how long the audit logs of the project are kept, given the server's default
+// retention. A retention of 0 keeps audit logs forever.
+func (s *AuditLogSettings) Retention(defaultRetention time.Duration) time.Duration {
+	if s.RetentionDays == 0 {
+		return defaultRetention
+	}
+
+	return time.Duration(s.RetentionDays) * 24 * time.Hour
+}
+
+// ToAuditLogSettingsType generates an external types.AuditLogSettings to be shared over REST
+func (s *AuditLogSettings) ToAuditLogSettingsType(defaultRetention time.Duration) *types.AuditLogSettings {
+	return &types.AuditLogSettings{
+		RetentionDays:        s.RetentionDays,
+		DefaultRetentionDays: uint(defaultRetention / (24 * time.Hour)),
+	}
+}
@@ -100,6 +100,11 @@ type Cluster struct {
 
 	// MonitorHelmReleases to trim down the number of revisions per release
 	MonitorHelmReleases bool
+
+	// HelmRevisionsToKeep is the number of revisions of each release kept in the cluster when
+	// MonitorHelmReleases is set. Older revisions are archived, then deleted. If 0, the default
+	// of the revisions tracker job is used.
+	HelmRevisionsToKeep uint
 }
 
 // ToClusterType generates an external types.Cluster to be shared over REST
@@ -132,6 +137,8 @@ func (c *Cluster) ToClusterType() *types.Cluster {
 		ProvisionedBy:                     c.ProvisionedBy,
 		CloudProvider:                     c.CloudProvider,
 		CloudProviderCredentialIdentifier: c.CloudProviderCredentialIdentifier,
+		MonitorHelmReleases:               c.MonitorHelmReleases,
+		HelmRevisionsToKeep:               c.HelmRevisionsToKeep,
 	}
 }
 
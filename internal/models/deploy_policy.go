@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/deploypolicy"
+	"gorm.io/gorm"
+)
+
+// DeploymentTargetPolicy holds the freeze windows and protection rules of a deployment target
+type DeploymentTargetPolicy struct {
+	gorm.Model
+
+	ProjectID          uint      `gorm:"index"`
+	DeploymentTargetID uuid.UUID `gorm:"type:uuid;uniqueIndex"`
+
+	// Policy is the JSON encoded deploypolicy.Policy
+	Policy []byte
+}
+
+// NewDeploymentTargetPolicy creates a DeploymentTargetPolicy for a deployment target
+func NewDeploymentTargetPolicy(projectID uint, deploymentTargetID uuid.UUID, policy deploypolicy.Policy) (*DeploymentTargetPolicy, error) {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeploymentTargetPolicy{
+		ProjectID:          projectID,
+		DeploymentTargetID: deploymentTargetID,
+		Policy:             data,
+	}, nil
+}
+
+// DeployPolicy returns the policy to evaluate deploys against
+func (p *DeploymentTargetPolicy) DeployPolicy() (deploypolicy.Policy, error) {
+	var policy deploypolicy.Policy
+
+	if len(p.Policy) == 0 {
+		return policy, nil
+	}
+
+	err := json.Unmarshal(p.Policy, &policy)
+
+	return policy, err
+}
+
+// ToDeploymentTargetPolicyType generates an external types.DeploymentTargetPolicy to be shared over REST
+func (p *DeploymentTargetPolicy) ToDeploymentTargetPolicyType() (*types.DeploymentTargetPolicy, error) {
+	policy, err := p.DeployPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.DeploymentTargetPolicy{
+		DeploymentTargetID: p.DeploymentTargetID,
+		FreezeWindows:      make([]types.FreezeWindow, 0, len(policy.FreezeWindows)),
+		ApproverRole:       policy.ApproverRole,
+		AllowedBranches:    append([]string{}, policy.AllowedBranches...),
+		AllowedSources:     append([]string{}, policy.AllowedSources...),
+		RequiresApproval:   policy.RequiresApproval,
+		ApprovalTimeout:    policy.ApprovalTimeout,
+	}
+
+	for _, window := range policy.FreezeWindows {
+		res.FreezeWindows = append(res.FreezeWindows, types.FreezeWindow{
+			Name:     window.Name,
+			Schedule: window.Schedule,
+			Duration: window.Duration,
+			Timezone: window.Timezone,
+			Reason:   window.Reason,
+		})
+	}
+
+	return res, nil
+}
+
+// DeployPolicyOverride records a deploy which went out despite violating the policy of its deployment target
+type DeployPolicyOverride struct {
+	gorm.Model
+
+	ProjectID          uint      `gorm:"index:idx_deploy_policy_overrides_project_target"`
+	DeploymentTargetID uuid.UUID `gorm:"type:uuid;index:idx_deploy_policy_overrides_project_target"`
+	AppName            string
+	Action             deploypolicy.Action
+
+	// UserID is set when the deploy was made by a user, and APITokenID when it was made
+	// with a project API token
+	UserID     uint
+	APITokenID string
+
+	Reason string
+
+	// Violations is the JSON encoded list of deploypolicy.Violation which were overridden
+	Violations []byte
+}
+
+// ToDeployPolicyOverrideType generates an external types.DeployPolicyOverride to be shared over REST
+func (o *DeployPolicyOverride) ToDeployPolicyOverrideType() *types.DeployPolicyOverride {
+	res := &types.DeployPolicyOverride{
+		ID:                 o.ID,
+		CreatedAt:          o.CreatedAt,
+		DeploymentTargetID: o.DeploymentTargetID,
+		AppName:            o.AppName,
+		Action:             string(o.Action),
+		UserID:             o.UserID,
+		APITokenID:         o.APITokenID,
+		Reason:             o.Reason,
+		Violations:         []types.DeployPolicyViolation{},
+	}
+
+	var violations []deploypolicy.Violation
+
+	// malformed violations should not prevent the override from being read
+	_ = json.Unmarshal(o.Violations, &violations)
+
+	for _, violation := range violations {
+		res.Violations = append(res.Violations, types.DeployPolicyViolation{Rule: string(violation.Rule), Message: violation.Message})
+	}
+
+	return res
+}
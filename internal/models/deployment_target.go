@@ -44,6 +44,9 @@ type DeploymentTarget struct {
 
 	// IsDefault indicates whether this is the default deployment target for the cluster
 	IsDefault bool `gorm:"default:false" json:"is_default"`
+
+	// Protected indicates whether deploys to this target must meet the project's image trust policy
+	Protected bool `gorm:"default:false" json:"protected"`
 }
 
 // ToDeploymentTargetType generates an external types.PorterApp to be shared over REST
@@ -55,6 +58,7 @@ func (d *DeploymentTarget) ToDeploymentTargetType() *types.DeploymentTarget {
 		Namespace:    d.Selector,
 		IsPreview:    d.Preview,
 		IsDefault:    d.IsDefault,
+		IsProtected:  d.Protected,
 		Name:         d.VanityName,
 		CreatedAtUTC: d.CreatedAt,
 		UpdatedAtUTC: d.UpdatedAt,
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// EnvGroupProvider stores the external secrets manager an environment group is synced from. Porter resolves
+// the provider's secret and publishes it as a new version of the environment group, then re-syncs it every
+// refresh interval with the env group provider refresh worker job.
+type EnvGroupProvider struct {
+	gorm.Model
+
+	ProjectID    uint   `gorm:"index"`
+	ClusterID    uint   `gorm:"uniqueIndex:idx_env_group_providers_cluster_env_group"`
+	EnvGroupName string `gorm:"uniqueIndex:idx_env_group_providers_cluster_env_group"`
+
+	Type types.EnvGroupProviderType
+
+	// Config is the JSON encoded types.VaultEnvGroupProviderConfig or types.AWSSecretsManagerEnvGroupProviderConfig,
+	// depending on the type. Credentials are stored in the referenced integration, not in the config.
+	Config []byte
+	// KeyMappings is the JSON encoded map of provider keys to variable names
+	KeyMappings []byte
+
+	RefreshIntervalSeconds int
+
+	LastSyncedAt  *time.Time
+	LastSyncError string
+}
+
+// VaultConfig returns the config of a Vault provider
+func (p *EnvGroupProvider) VaultConfig() (*types.VaultEnvGroupProviderConfig, error) {
+	if p.Type != types.EnvGroupProviderTypeVault {
+		return nil, fmt.Errorf("env group provider is of type %s, not %s", p.Type, types.EnvGroupProviderTypeVault)
+	}
+
+	conf := &types.VaultEnvGroupProviderConfig{}
+
+	if err := json.Unmarshal(p.Config, conf); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// AWSSecretsManagerConfig returns the config of an AWS Secrets Manager provider
+func (p *EnvGroupProvider) AWSSecretsManagerConfig() (*types.AWSSecretsManagerEnvGroupProviderConfig, error) {
+	if p.Type != types.EnvGroupProviderTypeAWSSecretsManager {
+		return nil, fmt.Errorf("env group provider is of type %s, not %s", p.Type, types.EnvGroupProviderTypeAWSSecretsManager)
+	}
+
+	conf := &types.AWSSecretsManagerEnvGroupProviderConfig{}
+
+	if err := json.Unmarshal(p.Config, conf); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// Mappings returns the key mappings of the provider
+func (p *EnvGroupProvider) Mappings() (map[string]string, error) {
+	mappings := map[string]string{}
+
+	if len(p.KeyMappings) == 0 {
+		return mappings, nil
+	}
+
+	if err := json.Unmarshal(p.KeyMappings, &mappings); err != nil {
+		return nil, err
+	}
+
+	return mappings, nil
+}
+
+// RefreshInterval returns how often the environment group is synced
+func (p *EnvGroupProvider) RefreshInterval() time.Duration {
+	if p.RefreshIntervalSeconds == 0 {
+		return types.DefaultEnvGroupProviderRefreshIntervalSeconds * time.Second
+	}
+
+	return time.Duration(p.RefreshIntervalSeconds) * time.Second
+}
+
+// IsDue reports whether the environment group should be synced again
+func (p *EnvGroupProvider) IsDue(now time.Time) bool {
+	return p.LastSyncedAt == nil || !now.Before(p.LastSyncedAt.Add(p.RefreshInterval()))
+}
+
+// ToEnvGroupProviderType generates an external types.EnvGroupProvider to be shared over REST
+func (p *EnvGroupProvider) ToEnvGroupProviderType() *types.EnvGroupProvider {
+	res := &types.EnvGroupProvider{
+		EnvGroupName:           p.EnvGroupName,
+		Type:                   p.Type,
+		RefreshIntervalSeconds: int(p.RefreshInterval().Seconds()),
+		LastSyncedAt:           p.LastSyncedAt,
+		LastSyncError:          p.LastSyncError,
+	}
+
+	// configs are validated before they are stored, so decoding errors are not expected here
+	res.Vault, _ = p.VaultConfig()
+	res.AWSSecretsManager, _ = p.AWSSecretsManagerConfig()
+	res.KeyMappings, _ = p.Mappings()
+
+	return res
+}
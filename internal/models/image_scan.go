@@ -0,0 +1,143 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/imagescan"
+	"gorm.io/gorm"
+)
+
+// ImageScan stores the vulnerabilities found by scanning an image. Scans are looked up by
+// the digest of the image, or by the tag it was scanned by.
+type ImageScan struct {
+	gorm.Model
+
+	ProjectID  uint   `gorm:"index:idx_image_scans_project_digest;index:idx_image_scans_project_repository"`
+	Repository string `gorm:"index:idx_image_scans_project_repository"`
+	Tag        string
+	Digest     string `gorm:"index:idx_image_scans_project_digest"`
+
+	Scanner   string
+	ScannedAt time.Time
+
+	Critical int
+	High     int
+	Medium   int
+	Low      int
+	Unknown  int
+
+	// Findings is the JSON encoded list of imagescan.Finding
+	Findings []byte
+}
+
+// NewImageScan creates an ImageScan from the report of scanning an image
+func NewImageScan(projectID uint, repository, tag string, report *imagescan.Report) (*ImageScan, error) {
+	findings, err := json.Marshal(report.Findings)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := report.Counts()
+
+	return &ImageScan{
+		ProjectID:  projectID,
+		Repository: repository,
+		Tag:        tag,
+		Digest:     report.Digest,
+		Scanner:    report.Scanner,
+		ScannedAt:  report.ScannedAt,
+		Critical:   counts[imagescan.SeverityCritical],
+		High:       counts[imagescan.SeverityHigh],
+		Medium:     counts[imagescan.SeverityMedium],
+		Low:        counts[imagescan.SeverityLow],
+		Unknown:    counts[imagescan.SeverityUnknown],
+		Findings:   findings,
+	}, nil
+}
+
+// Report returns the scan report the ImageScan was created from
+func (s *ImageScan) Report() (*imagescan.Report, error) {
+	report := &imagescan.Report{
+		Digest:    s.Digest,
+		Scanner:   s.Scanner,
+		ScannedAt: s.ScannedAt,
+	}
+
+	if len(s.Findings) > 0 {
+		if err := json.Unmarshal(s.Findings, &report.Findings); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// ToImageScanSummaryType generates an external types.ImageScanSummary to be shared over REST
+func (s *ImageScan) ToImageScanSummaryType() *types.ImageScanSummary {
+	return &types.ImageScanSummary{
+		Repository: s.Repository,
+		Tag:        s.Tag,
+		Digest:     s.Digest,
+		Scanner:    s.Scanner,
+		ScannedAt:  s.ScannedAt,
+		Critical:   s.Critical,
+		High:       s.High,
+		Medium:     s.Medium,
+		Low:        s.Low,
+		Unknown:    s.Unknown,
+	}
+}
+
+// ToImageScanType generates an external types.ImageScan, with all vulnerabilities found, to be shared over REST
+func (s *ImageScan) ToImageScanType() (*types.ImageScan, error) {
+	report, err := s.Report()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.ImageScan{
+		ImageScanSummary: *s.ToImageScanSummaryType(),
+		Vulnerabilities:  make([]types.ImageVulnerability, 0, len(report.Findings)),
+	}
+
+	for _, f := range report.Findings {
+		res.Vulnerabilities = append(res.Vulnerabilities, types.ImageVulnerability{
+			ID:               f.ID,
+			Package:          f.Package,
+			InstalledVersion: f.InstalledVersion,
+			FixedVersion:     f.FixedVersion,
+			Severity:         string(f.Severity),
+			Title:            f.Title,
+		})
+	}
+
+	return res, nil
+}
+
+// ImageScanPolicy decides whether images may be deployed in a project based on the vulnerabilities found in them
+type ImageScanPolicy struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"uniqueIndex"`
+
+	Action            string
+	SeverityThreshold string
+}
+
+// Policy returns the policy to evaluate scan reports with
+func (p *ImageScanPolicy) Policy() imagescan.Policy {
+	return imagescan.Policy{
+		Action:    imagescan.Action(p.Action),
+		Threshold: imagescan.ParseSeverity(p.SeverityThreshold),
+	}
+}
+
+// ToImageScanPolicyType generates an external types.ImageScanPolicy to be shared over REST
+func (p *ImageScanPolicy) ToImageScanPolicyType() *types.ImageScanPolicy {
+	return &types.ImageScanPolicy{
+		Action:            types.ImageScanPolicyAction(p.Action),
+		SeverityThreshold: p.SeverityThreshold,
+	}
+}
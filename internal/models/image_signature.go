@@ -0,0 +1,128 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/imagesign"
+	"gorm.io/gorm"
+)
+
+// ImageTrustPolicy lists the signers whose image signatures a project trusts
+type ImageTrustPolicy struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"uniqueIndex"`
+
+	// Policy is the JSON encoded imagesign.TrustPolicy
+	Policy []byte
+}
+
+// NewImageTrustPolicy creates an ImageTrustPolicy for a project
+func NewImageTrustPolicy(projectID uint, policy imagesign.TrustPolicy) (*ImageTrustPolicy, error) {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImageTrustPolicy{
+		ProjectID: projectID,
+		Policy:    data,
+	}, nil
+}
+
+// TrustPolicy returns the policy to verify images against
+func (p *ImageTrustPolicy) TrustPolicy() (imagesign.TrustPolicy, error) {
+	var policy imagesign.TrustPolicy
+
+	if len(p.Policy) == 0 {
+		return policy, nil
+	}
+
+	err := json.Unmarshal(p.Policy, &policy)
+
+	return policy, err
+}
+
+// ToImageTrustPolicyType generates an external types.ImageTrustPolicy to be shared over REST
+func (p *ImageTrustPolicy) ToImageTrustPolicyType() (*types.ImageTrustPolicy, error) {
+	policy, err := p.TrustPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.ImageTrustPolicy{
+		PublicKeys:   make([]types.TrustedPublicKey, 0, len(policy.PublicKeys)),
+		Identities:   make([]types.TrustedIdentity, 0, len(policy.Identities)),
+		Attestations: append([]string{}, policy.Attestations...),
+	}
+
+	for _, key := range policy.PublicKeys {
+		res.PublicKeys = append(res.PublicKeys, types.TrustedPublicKey{Name: key.Name, PEM: key.PEM})
+	}
+
+	for _, identity := range policy.Identities {
+		res.Identities = append(res.Identities, types.TrustedIdentity{Issuer: identity.Issuer, Subject: identity.Subject})
+	}
+
+	return res, nil
+}
+
+// ImageVerification records a successful signature verification of an image which was deployed. The
+// verification is linked to the app revision which deployed the image once the revision is created.
+type ImageVerification struct {
+	gorm.Model
+
+	ProjectID     uint   `gorm:"index:idx_image_verifications_project_revision"`
+	AppRevisionID string `gorm:"index:idx_image_verifications_project_revision"`
+
+	Repository string
+	Tag        string
+	Digest     string
+
+	Verifier     string
+	Signer       string
+	Attestations string
+	VerifiedAt   time.Time
+}
+
+// NewImageVerification creates an ImageVerification from the result of verifying an image
+func NewImageVerification(projectID uint, repository, tag, verifier string, verification *imagesign.Verification) *ImageVerification {
+	return &ImageVerification{
+		ProjectID:    projectID,
+		Repository:   repository,
+		Tag:          tag,
+		Digest:       verification.Digest,
+		Verifier:     verifier,
+		Signer:       verification.Signer,
+		Attestations: strings.Join(verification.Attestations, ","),
+		VerifiedAt:   verification.VerifiedAt,
+	}
+}
+
+// ToImageVerificationType generates an external types.ImageVerification to be shared over REST
+func (v *ImageVerification) ToImageVerificationType() *types.ImageVerification {
+	res := &types.ImageVerification{
+		Repository:   v.Repository,
+		Tag:          v.Tag,
+		Digest:       v.Digest,
+		Signer:       v.Signer,
+		Attestations: []string{},
+		VerifiedAt:   v.VerifiedAt,
+	}
+
+	if v.Attestations != "" {
+		res.Attestations = strings.Split(v.Attestations, ",")
+	}
+
+	return res
+}
+
+// PinnedTag returns the verified tag with the verified digest appended, so that deploys of the tag pull the verified image
+func (v *ImageVerification) PinnedTag() string {
+	verification := &imagesign.Verification{Digest: v.Digest}
+
+	return verification.PinTag(v.Tag)
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// KeyRotation tracks a rotation of the key used to encrypt data at rest
+type KeyRotation struct {
+	gorm.Model
+
+	// KeyFingerprint identifies the old and new key pair, so that rerunning a rotation
+	// with the same keys resumes it instead of starting over
+	KeyFingerprint string `gorm:"unique"`
+
+	Status      types.KeyRotationStatus
+	Error       string
+	CompletedAt *time.Time
+
+	Checkpoints []KeyRotationCheckpoint
+}
+
+// KeyRotationCheckpoint records how far a key rotation has progressed through an
+// encrypted table. Records are rotated in ascending ID order, so LastID is the
+// point to resume from.
+type KeyRotationCheckpoint struct {
+	gorm.Model
+
+	KeyRotationID  uint   `gorm:"uniqueIndex:idx_key_rotation_table"`
+	EncryptedTable string `gorm:"uniqueIndex:idx_key_rotation_table"`
+
+	LastID    uint
+	Total     int64
+	Rotated   int64
+	Skipped   int64
+	Failed    int64
+	LastError string
+	Completed bool
+}
+
+func (k *KeyRotation) ToKeyRotationType() *types.KeyRotation {
+	tables := make([]*types.KeyRotationTable, 0, len(k.Checkpoints))
+
+	for _, c := range k.Checkpoints {
+		tables = append(tables, &types.KeyRotationTable{
+			Table:     c.EncryptedTable,
+			Total:     c.Total,
+			Rotated:   c.Rotated,
+			Skipped:   c.Skipped,
+			Failed:    c.Failed,
+			LastID:    c.LastID,
+			LastError: c.LastError,
+			Completed: c.Completed,
+		})
+	}
+
+	return &types.KeyRotation{
+		ID:          k.ID,
+		CreatedAt:   k.CreatedAt,
+		UpdatedAt:   k.UpdatedAt,
+		CompletedAt: k.CompletedAt,
+		Status:      k.Status,
+		Error:       k.Error,
+		Tables:      tables,
+	}
+}
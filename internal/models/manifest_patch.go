@@ -0,0 +1,73 @@
+package models
+
+import (
+	"github.com/karagatandev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// ManifestPatch is a kustomize patch applied to the rendered manifests of the application releases
+// of a project, or of the releases selected by AppName, ChartName and ClusterID
+type ManifestPatch struct {
+	gorm.Model
+
+	ProjectID       uint `gorm:"index"`
+	CreatedByUserID uint
+	Name            string
+
+	AppName   string
+	ChartName string
+	ClusterID uint
+	Priority  int
+
+	Type  string
+	Patch string `gorm:"type:text"`
+
+	TargetGroup              string
+	TargetVersion            string
+	TargetKind               string
+	TargetName               string
+	TargetNamespace          string
+	TargetLabelSelector      string
+	TargetAnnotationSelector string
+}
+
+// Target returns the selector of the objects which the patch applies to
+func (m *ManifestPatch) Target() types.ManifestPatchTarget {
+	return types.ManifestPatchTarget{
+		Group:              m.TargetGroup,
+		Version:            m.TargetVersion,
+		Kind:               m.TargetKind,
+		Name:               m.TargetName,
+		Namespace:          m.TargetNamespace,
+		LabelSelector:      m.TargetLabelSelector,
+		AnnotationSelector: m.TargetAnnotationSelector,
+	}
+}
+
+// SetTarget sets the selector of the objects which the patch applies to
+func (m *ManifestPatch) SetTarget(target types.ManifestPatchTarget) {
+	m.TargetGroup = target.Group
+	m.TargetVersion = target.Version
+	m.TargetKind = target.Kind
+	m.TargetName = target.Name
+	m.TargetNamespace = target.Namespace
+	m.TargetLabelSelector = target.LabelSelector
+	m.TargetAnnotationSelector = target.AnnotationSelector
+}
+
+// ToManifestPatchType generates an external types.ManifestPatch to be shared over REST
+func (m *ManifestPatch) ToManifestPatchType() *types.ManifestPatch {
+	return &types.ManifestPatch{
+		ID:        m.ID,
+		CreatedAt: m.CreatedAt,
+		ProjectID: m.ProjectID,
+		Name:      m.Name,
+		AppName:   m.AppName,
+		ChartName: m.ChartName,
+		ClusterID: m.ClusterID,
+		Priority:  m.Priority,
+		Type:      types.ManifestPatchType(m.Type),
+		Target:    m.Target(),
+		Patch:     m.Patch,
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// OIDCTrustRule permits CI jobs whose ID tokens match it to exchange them for short-lived
+// Porter tokens with the policy PolicyUID
+type OIDCTrustRule struct {
+	gorm.Model
+
+	ProjectID       uint `gorm:"index"`
+	CreatedByUserID uint
+	Name            string
+
+	Issuer         string
+	Audience       string
+	SubjectPattern string
+
+	ClusterID        uint
+	DeploymentTarget string
+	AppName          string
+
+	PolicyUID       string
+	TokenTTLSeconds uint
+}
+
+// TokenTTL returns the lifetime of tokens exchanged through the rule
+func (o *OIDCTrustRule) TokenTTL() time.Duration {
+	if o.TokenTTLSeconds == 0 {
+		return types.DefaultOIDCTokenTTL
+	}
+
+	return time.Duration(o.TokenTTLSeconds) * time.Second
+}
+
+// ToOIDCTrustRuleType generates an external types.OIDCTrustRule to be shared over REST
+func (o *OIDCTrustRule) ToOIDCTrustRuleType() *types.OIDCTrustRule {
+	return &types.OIDCTrustRule{
+		ID:               o.ID,
+		CreatedAt:        o.CreatedAt,
+		ProjectID:        o.ProjectID,
+		Name:             o.Name,
+		Issuer:           o.Issuer,
+		Audience:         o.Audience,
+		SubjectPattern:   o.SubjectPattern,
+		ClusterID:        o.ClusterID,
+		DeploymentTarget: o.DeploymentTarget,
+		AppName:          o.AppName,
+		PolicyUID:        o.PolicyUID,
+		TokenTTLSeconds:  o.TokenTTLSeconds,
+	}
+}
@@ -32,6 +32,13 @@ type Registry struct {
 	// For AWS EKS clusters, this will be an ARN for the final target role in the assume role chain.
 	CloudProviderCredentialIdentifier string `json:"cloud_provider_credential_identifier" gorm:"default:''"`
 
+	// Service is the registry service, for services which can't be told apart by their integration.
+	// Accepted values: [harbor, quay, ghcr]
+	Service string `json:"service" gorm:"default:''"`
+
+	// GithubAppInstallationID is the GitHub App installation used to access a GHCR registry
+	GithubAppInstallationID int64 `json:"github_app_installation_id"`
+
 	// ------------------------------------------------------------------
 	// All fields below this line are encrypted before storage
 	// ------------------------------------------------------------------
@@ -49,7 +56,9 @@ type Registry struct {
 func (r *Registry) ToRegistryType() *types.Registry {
 	var serv types.RegistryService
 
-	if r.AWSIntegrationID != 0 {
+	if r.Service != "" {
+		serv = types.RegistryService(r.Service)
+	} else if r.AWSIntegrationID != 0 {
 		serv = types.ECR
 	} else if r.GCPIntegrationID != 0 {
 		if strings.Contains(r.URL, "pkg.dev") {
@@ -84,5 +93,7 @@ func (r *Registry) ToRegistryType() *types.Registry {
 		AzureIntegrationID: r.AzureIntegrationID,
 		DOIntegrationID:    r.DOIntegrationID,
 		BasicIntegrationID: r.BasicIntegrationID,
+
+		GithubAppInstallationID: r.GithubAppInstallationID,
 	}
 }
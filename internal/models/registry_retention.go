@@ -0,0 +1,185 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/retention"
+	"gorm.io/gorm"
+)
+
+// RegistryRetentionPolicy stores the retention rules of a registry repository
+type RegistryRetentionPolicy struct {
+	gorm.Model
+
+	ProjectID  uint   `gorm:"index"`
+	RegistryID uint   `gorm:"uniqueIndex:idx_registry_retention_policies_registry_repository"`
+	Repository string `gorm:"uniqueIndex:idx_registry_retention_policies_registry_repository"`
+
+	KeepLast               int
+	KeepDeployedWithinDays int
+	// KeepTagPatterns is the comma separated list of tag patterns. Image tags can't contain commas.
+	KeepTagPatterns string
+}
+
+// Rules returns the retention rules of the policy
+func (p *RegistryRetentionPolicy) Rules() retention.Rules {
+	rules := retention.Rules{
+		KeepLast:           p.KeepLast,
+		KeepDeployedWithin: time.Duration(p.KeepDeployedWithinDays) * 24 * time.Hour,
+	}
+
+	if p.KeepTagPatterns != "" {
+		rules.KeepTagPatterns = strings.Split(p.KeepTagPatterns, ",")
+	}
+
+	return rules
+}
+
+// ToRegistryRetentionPolicyType generates an external types.RegistryRetentionPolicy to be shared over REST
+func (p *RegistryRetentionPolicy) ToRegistryRetentionPolicyType() *types.RegistryRetentionPolicy {
+	return &types.RegistryRetentionPolicy{
+		ID:                     p.ID,
+		RegistryID:             p.RegistryID,
+		Repository:             p.Repository,
+		KeepLast:               p.KeepLast,
+		KeepDeployedWithinDays: p.KeepDeployedWithinDays,
+		KeepTagPatterns:        append([]string{}, p.Rules().KeepTagPatterns...),
+		UpdatedAt:              p.UpdatedAt,
+	}
+}
+
+// RegistryGCRun is a garbage collection run of a registry repository. The run is planned as a dry run
+// and its plan is executed by the registry garbage collection worker job once it is queued.
+type RegistryGCRun struct {
+	gorm.Model
+
+	ProjectID  uint `gorm:"index:idx_registry_gc_runs_project_registry"`
+	RegistryID uint `gorm:"index:idx_registry_gc_runs_project_registry"`
+	Repository string
+	Status     types.RegistryGCRunStatus `gorm:"index"`
+
+	// Policy is the JSON encoded types.RegistryRetentionPolicy the run was planned with
+	Policy []byte
+	// Plan is the JSON encoded retention.Plan
+	Plan []byte
+	// Deleted is the JSON encoded audit of deleted manifests, as a list of types.RegistryGCDeletion
+	Deleted []byte
+	Error   string
+
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// NewRegistryGCRun creates a planned RegistryGCRun of the repository of a retention policy
+func NewRegistryGCRun(policy *RegistryRetentionPolicy, plan *retention.Plan) (*RegistryGCRun, error) {
+	policyData, err := json.Marshal(policy.ToRegistryRetentionPolicyType())
+	if err != nil {
+		return nil, err
+	}
+
+	planData, err := json.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegistryGCRun{
+		ProjectID:  policy.ProjectID,
+		RegistryID: policy.RegistryID,
+		Repository: policy.Repository,
+		Status:     types.RegistryGCRunStatusPlanned,
+		Policy:     policyData,
+		Plan:       planData,
+	}, nil
+}
+
+// RetentionPlan returns the plan of the run
+func (r *RegistryGCRun) RetentionPlan() (*retention.Plan, error) {
+	plan := &retention.Plan{}
+
+	if len(r.Plan) == 0 {
+		return plan, nil
+	}
+
+	err := json.Unmarshal(r.Plan, plan)
+
+	return plan, err
+}
+
+// Deletions returns the audit of the manifests deleted by the run
+func (r *RegistryGCRun) Deletions() ([]types.RegistryGCDeletion, error) {
+	deletions := []types.RegistryGCDeletion{}
+
+	if len(r.Deleted) == 0 {
+		return deletions, nil
+	}
+
+	err := json.Unmarshal(r.Deleted, &deletions)
+
+	return deletions, err
+}
+
+// SetDeletions replaces the audit of the manifests deleted by the run
+func (r *RegistryGCRun) SetDeletions(deletions []types.RegistryGCDeletion) error {
+	data, err := json.Marshal(deletions)
+	if err != nil {
+		return err
+	}
+
+	r.Deleted = data
+
+	return nil
+}
+
+// ToRegistryGCRunType generates an external types.RegistryGCRun to be shared over REST
+func (r *RegistryGCRun) ToRegistryGCRunType() (*types.RegistryGCRun, error) {
+	res := &types.RegistryGCRun{
+		ID:         r.ID,
+		RegistryID: r.RegistryID,
+		Repository: r.Repository,
+		Status:     r.Status,
+		Keep:       []types.RetainedImage{},
+		Delete:     []types.RegistryManifest{},
+		Error:      r.Error,
+		CreatedAt:  r.CreatedAt,
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+	}
+
+	if len(r.Policy) > 0 {
+		if err := json.Unmarshal(r.Policy, &res.Policy); err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := r.RetentionPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, kept := range plan.Keep {
+		res.Keep = append(res.Keep, types.RetainedImage{
+			Tag:      kept.Tag,
+			Digest:   kept.Digest,
+			PushedAt: kept.PushedAt,
+			Reason:   string(kept.Reason),
+		})
+	}
+
+	for _, manifest := range plan.Delete {
+		res.Delete = append(res.Delete, types.RegistryManifest{
+			Digest:   manifest.Digest,
+			Tags:     manifest.Tags,
+			PushedAt: manifest.PushedAt,
+		})
+	}
+
+	res.Deleted, err = r.Deletions()
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
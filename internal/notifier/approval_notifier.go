@@ -0,0 +1,22 @@
+package notifier
+
+import "github.com/karagatandev/porter/api/types"
+
+// ApprovalNotifier tells approvers about revisions held for approval, and the project when they are decided
+type ApprovalNotifier interface {
+	NotifyApprovalRequested(opts *ApprovalNotifyOpts) error
+	NotifyApprovalDecided(opts *ApprovalNotifyOpts) error
+}
+
+// ApprovalNotifyOpts describes a revision held for approval
+type ApprovalNotifyOpts struct {
+	Approval *types.AppRevisionApproval
+
+	// DeploymentTargetName is the name of the deployment target the revision is held for
+	DeploymentTargetName string
+
+	// ApproverRole is the role which may approve the revision, or empty if only admins may approve it
+	ApproverRole string
+
+	URL string
+}
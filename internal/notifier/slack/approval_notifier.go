@@ -0,0 +1,124 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/models/integrations"
+	"github.com/karagatandev/porter/internal/notifier"
+)
+
+// ApprovalNotifier posts revisions held for approval to the Slack integrations of a project
+type ApprovalNotifier struct {
+	slackInts []*integrations.SlackIntegration
+	// mentions are the users or groups, such as @channel, which are mentioned in each message
+	mentions []string
+}
+
+// NewApprovalNotifier returns an ApprovalNotifier which posts to the given Slack integrations, mentioning
+// the mentions set in their notification config
+func NewApprovalNotifier(mentions []string, slackInts ...*integrations.SlackIntegration) *ApprovalNotifier {
+	return &ApprovalNotifier{
+		slackInts: slackInts,
+		mentions:  mentions,
+	}
+}
+
+// NotifyApprovalRequested asks approvers to approve or reject a revision
+func (s *ApprovalNotifier) NotifyApprovalRequested(opts *notifier.ApprovalNotifyOpts) error {
+	approvers := "an admin"
+	if opts.ApproverRole != "" {
+		approvers = fmt.Sprintf("a user with the %s role", "`"+opts.ApproverRole+"`")
+	}
+
+	expiresAt := opts.Approval.ExpiresAt
+
+	blocks := []*SlackBlock{
+		getMarkdownBlock(fmt.Sprintf(
+			":raised_hand: A new revision of %s is waiting for approval to deploy to %s. <%s|View the application.>",
+			"`"+opts.Approval.AppName+"`",
+			"`"+opts.DeploymentTargetName+"`",
+			opts.URL,
+		)),
+		getDividerBlock(),
+		getMarkdownBlock(fmt.Sprintf("*Revision:* %s", "`"+opts.Approval.AppRevisionID+"`")),
+		getMarkdownBlock(fmt.Sprintf(
+			"*Expires at:* <!date^%d^{date_num} {time_secs}|%s>",
+			expiresAt.Unix(),
+			expiresAt.UTC().Format("2006-01-02 15:04:05 UTC"),
+		)),
+		getMarkdownBlock(fmt.Sprintf(
+			"It can be approved by %s with %s",
+			approvers,
+			"`porter app approve "+opts.Approval.AppName+" --revision "+opts.Approval.AppRevisionID+"`",
+		)),
+	}
+
+	return s.post(blocks)
+}
+
+// NotifyApprovalDecided tells the project that a revision was approved, rejected or expired
+func (s *ApprovalNotifier) NotifyApprovalDecided(opts *notifier.ApprovalNotifyOpts) error {
+	var md string
+
+	switch models.AppRevisionStatus(opts.Approval.Status) {
+	case models.AppRevisionStatus_Approved:
+		md = ":white_check_mark: A new revision of %s was approved to deploy to %s. <%s|View the application.>"
+	case models.AppRevisionStatus_ApprovalRejected:
+		md = ":no_entry: A new revision of %s was rejected for %s and will not be deployed. <%s|View the application.>"
+	case models.AppRevisionStatus_ApprovalExpired:
+		md = ":hourglass: A new revision of %s was not approved for %s in time and will not be deployed. <%s|View the application.>"
+	default:
+		return nil
+	}
+
+	blocks := []*SlackBlock{
+		getMarkdownBlock(fmt.Sprintf(md, "`"+opts.Approval.AppName+"`", "`"+opts.DeploymentTargetName+"`", opts.URL)),
+		getDividerBlock(),
+		getMarkdownBlock(fmt.Sprintf("*Revision:* %s", "`"+opts.Approval.AppRevisionID+"`")),
+	}
+
+	if opts.Approval.Reason != "" {
+		blocks = append(blocks, getMarkdownBlock(fmt.Sprintf("*Reason:* %s", opts.Approval.Reason)))
+	}
+
+	return s.post(blocks)
+}
+
+func (s *ApprovalNotifier) post(blocks []*SlackBlock) error {
+	var mentions []string
+	for _, mention := range s.mentions {
+		if mention != "" {
+			mentions = append(mentions, mention)
+		}
+	}
+
+	if len(mentions) > 0 {
+		blocks = append(blocks, getMarkdownBlock(strings.Join(mentions, " ")))
+	}
+
+	payload, err := json.Marshal(&SlackPayload{Blocks: blocks})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: time.Second * 5,
+	}
+
+	for _, slackInt := range s.slackInts {
+		resp, err := client.Post(string(slackInt.Webhook), "application/json", bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+
+		resp.Body.Close() // nolint:errcheck,gosec
+	}
+
+	return nil
+}
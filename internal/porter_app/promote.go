@@ -0,0 +1,132 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v2 "github.com/karagatandev/porter/internal/porter_app/v2"
+	"github.com/karagatandev/porter/internal/telemetry"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+// PromotedAppInput is the input to PromotedApp
+type PromotedAppInput struct {
+	// Source is the app definition of the revision being promoted
+	Source *porterv1.PorterApp
+	// Destination is the app definition of the current revision in the target, or nil if the app is not deployed to the target
+	Destination *porterv1.PorterApp
+	// PinnedTag is the image tag of the source revision, pinned to the digest of the image it deployed
+	PinnedTag string
+	// Overrides are patch operations applied to the promoted definition, for settings which differ between targets
+	Overrides []v2.PatchOperation
+}
+
+// PromotedApp returns the app definition which promotes a revision to another deployment target. The source definition is
+// copied with its image pinned to the deployed digest, so that the target runs exactly the same image even if the tag moves.
+// Environment groups are specific to each target, so the target keeps its own, then the overrides are applied.
+func PromotedApp(ctx context.Context, inp PromotedAppInput) (*porterv1.PorterApp, error) {
+	ctx, span := telemetry.NewSpan(ctx, "promoted-app")
+	defer span.End()
+
+	if inp.Source == nil {
+		return nil, telemetry.Error(ctx, span, nil, "source app is nil")
+	}
+
+	app, ok := proto.Clone(inp.Source).(*porterv1.PorterApp)
+	if !ok {
+		return nil, telemetry.Error(ctx, span, nil, "error copying source app")
+	}
+
+	if inp.PinnedTag != "" {
+		if app.Image == nil {
+			return nil, telemetry.Error(ctx, span, nil, "source app has no image")
+		}
+		app.Image.Tag = inp.PinnedTag
+	}
+
+	app.EnvGroups = nil
+	if inp.Destination != nil {
+		app.EnvGroups = inp.Destination.EnvGroups
+	}
+
+	if len(inp.Overrides) == 0 {
+		return app, nil
+	}
+
+	app, err := v2.PatchApp(ctx, app, inp.Overrides)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error applying overrides")
+	}
+
+	return app, nil
+}
+
+// DefinitionDiff returns a line diff of the porter.yaml representations of two app definitions. Removed lines are prefixed
+// with "-", added lines with "+" and unchanged lines with a space. The diff is empty if the definitions are equal. The
+// current definition is nil if the app is not deployed yet, in which case every line of the new definition is added.
+func DefinitionDiff(current, updated *porterv1.PorterApp) (string, error) {
+	currentYAML, err := definitionYAML(current)
+	if err != nil {
+		return "", fmt.Errorf("error converting current app to porter yaml: %w", err)
+	}
+
+	updatedYAML, err := definitionYAML(updated)
+	if err != nil {
+		return "", fmt.Errorf("error converting updated app to porter yaml: %w", err)
+	}
+
+	if currentYAML == updatedYAML {
+		return "", nil
+	}
+
+	dmp := diffmatchpatch.New()
+	currentChars, updatedChars, lines := dmp.DiffLinesToChars(currentYAML, updatedYAML)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(currentChars, updatedChars, false), lines)
+
+	var sb strings.Builder
+
+	for _, diff := range diffs {
+		prefix := " "
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		}
+
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line == "" {
+				continue
+			}
+
+			sb.WriteString(prefix + line)
+			if !strings.HasSuffix(line, "\n") {
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func definitionYAML(app *porterv1.PorterApp) (string, error) {
+	if app == nil {
+		return "", nil
+	}
+
+	porterApp, err := v2.AppFromProto(app)
+	if err != nil {
+		return "", err
+	}
+
+	by, err := yaml.Marshal(porterApp)
+	if err != nil {
+		return "", err
+	}
+
+	return string(by), nil
+}
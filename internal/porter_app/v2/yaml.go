@@ -159,6 +159,33 @@ type Build struct {
 	Buildpacks []string `yaml:"buildpacks,omitempty"`
 	Dockerfile string   `yaml:"dockerfile,omitempty" validate:"required_if=Method docker"`
 	CommitSHA  string   `yaml:"commitSha,omitempty"`
+	// Cache, BuildKit and Platforms are only read by the CLI when building locally, and are not stored with the app
+	Cache     *BuildCache `yaml:"cache,omitempty"`
+	BuildKit  *BuildKit   `yaml:"buildkit,omitempty"`
+	Platforms []string    `yaml:"platforms,omitempty"`
+}
+
+// BuildCache configures where the layer cache for a build is imported from and exported to
+type BuildCache struct {
+	// Type is the cache backend, one of registry, local or gha
+	Type string `yaml:"type" validate:"required,oneof=registry local gha"`
+	// Mode is min to only cache the layers of the final image, or max to cache all intermediate layers
+	Mode string `yaml:"mode,omitempty" validate:"omitempty,oneof=min max"`
+	// Tag is the tag in the app's image repository which holds the registry cache
+	Tag string `yaml:"tag,omitempty"`
+	// Dir is the directory holding the local cache
+	Dir string `yaml:"dir,omitempty" validate:"required_if=Type local"`
+	// Scope separates the GitHub Actions caches of different apps in the same repository
+	Scope string `yaml:"scope,omitempty"`
+}
+
+// BuildKit configures a remote BuildKit daemon to build with instead of the local Docker daemon
+type BuildKit struct {
+	// Host is the address of the daemon, either tcp://host:port or kube-pod://pod-name?namespace=ns
+	Host   string `yaml:"host" validate:"required"`
+	CACert string `yaml:"cacert,omitempty"`
+	Cert   string `yaml:"cert,omitempty"`
+	Key    string `yaml:"key,omitempty"`
 }
 
 // Image is the repository and tag for an app's build image
@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/internal/retention"
+	"github.com/karagatandev/porter/internal/telemetry"
+)
+
+// ErrImageDigestNotFound is returned when the digest of an image can't be looked up in the project's registries
+var ErrImageDigestNotFound = errors.New("image digest not found")
+
+// ResolveImageDigest returns the digest of the manifest an image tag refers to. Tags which are pinned to a
+// digest, in the form tag@sha256:..., return the pinned digest. Other tags are looked up in the project's
+// registry hosting the image, and ErrImageDigestNotFound is returned if no registry of the project hosts
+// the image or the registry does not report the tag's digest.
+func ResolveImageDigest(ctx context.Context, conf *config.Config, projectID uint, repository, tag string) (string, error) {
+	ctx, span := telemetry.NewSpan(ctx, "resolve-image-digest")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "repository", Value: repository},
+		telemetry.AttributeKV{Key: "tag", Value: tag},
+	)
+
+	tag, digest := retention.ParseImageTag(tag)
+	if digest != "" {
+		return digest, nil
+	}
+
+	registries, err := conf.Repo.Registry().ListRegistriesByProjectID(projectID)
+	if err != nil {
+		return "", telemetry.Error(ctx, span, err, "error listing registries")
+	}
+
+	reg := FindRegistryForImage(registries, repository)
+	if reg == nil {
+		return "", fmt.Errorf("%w: %s is not hosted by a registry connected to the project", ErrImageDigestNotFound, repository)
+	}
+
+	registryURL := strings.TrimSuffix(strings.TrimPrefix(reg.URL, "https://"), "/")
+	repoName := strings.TrimPrefix(strings.TrimPrefix(repository, "https://"), registryURL+"/")
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "registry-id", Value: reg.ID},
+		telemetry.AttributeKV{Key: "repo-name", Value: repoName},
+	)
+
+	// registries are queried through the listing used by retention, which reports digests for every registry type
+	_reg := Registry(*reg)
+
+	images, err := _reg.ListRetentionImages(ctx, repoName, conf.Repo, conf.DOConf)
+	if err != nil {
+		if errors.Is(err, ErrRetentionNotSupported) {
+			return "", fmt.Errorf("%w: digests can't be looked up in the registry hosting %s", ErrImageDigestNotFound, repository)
+		}
+
+		return "", telemetry.Error(ctx, span, err, "error listing images")
+	}
+
+	for _, img := range images {
+		if img.Tag == tag && img.Digest != "" {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "digest", Value: img.Digest})
+
+			return img.Digest, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s:%s", ErrImageDigestNotFound, repository, tag)
+}
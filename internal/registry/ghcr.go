@@ -0,0 +1,237 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/google/go-github/v39/github"
+	"github.com/karagatandev/porter/api/server/shared/config"
+	ptypes "github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/oauth"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// GHCR registries are connected with a GitHub App installation on an organization, and with a URL in
+// the form ghcr.io/organization. Images are pulled and pushed with installation tokens, which expire
+// after an hour, so tokens are cached on the registry and refreshed whenever the registry is used with
+// the GitHub App's credentials. GHCR creates packages when they are first pushed to.
+
+// ErrGHCRTokenExpired is returned when the docker config of a GHCR registry is requested without the
+// GitHub App's credentials, and the cached installation token has expired
+var ErrGHCRTokenExpired = errors.New("the GHCR installation token has expired")
+
+// ghcrTokenLifetime is how long an installation token is cached, which is less than the hour it is valid
+// for so that it doesn't expire while in use
+const ghcrTokenLifetime = 50 * time.Minute
+
+func (r *Registry) ghcrOrganization() (string, string, error) {
+	_, host, organization, err := parseRegistryURL(r.URL)
+	if err != nil {
+		return "", "", err
+	}
+
+	if organization == "" || strings.Contains(organization, "/") {
+		return "", "", fmt.Errorf("invalid GHCR registry url %s: expected to be in the form ghcr.io/ORGANIZATION", r.URL)
+	}
+
+	return host, organization, nil
+}
+
+func (r *Registry) ghcrTransport(githubApp *oauth.GithubAppConf) (*ghinstallation.Transport, error) {
+	if githubApp == nil {
+		return nil, errors.New("a GitHub App must be configured to use GHCR registries")
+	}
+
+	if r.GithubAppInstallationID == 0 {
+		return nil, errors.New("registry has no GitHub App installation")
+	}
+
+	return ghinstallation.NewKeyFromFile(
+		http.DefaultTransport,
+		githubApp.AppID,
+		r.GithubAppInstallationID,
+		githubApp.SecretPath,
+	)
+}
+
+// RefreshGHCRToken returns an installation token for a GHCR registry, and caches it on the registry so
+// that GetDockerConfigJSON can use it
+func (r *Registry) RefreshGHCRToken(ctx context.Context, repo repository.Repository, githubApp *oauth.GithubAppConf) (string, time.Time, error) {
+	return r.refreshGHCRToken(ctx, repo, func(ctx context.Context) (string, error) {
+		itr, err := r.ghcrTransport(githubApp)
+		if err != nil {
+			return "", err
+		}
+
+		return itr.Token(ctx)
+	})
+}
+
+// refreshGHCRToken returns the cached installation token of the registry, or creates a new one with newToken
+// if the cached token is missing or expires within five minutes
+func (r *Registry) refreshGHCRToken(ctx context.Context, repo repository.Repository, newToken func(ctx context.Context) (string, error)) (string, time.Time, error) {
+	cache, err := r.getTokenCacheFunc(ctx, repo)(ctx)
+	if err == nil && len(cache.Token) > 0 && time.Now().Add(5*time.Minute).Before(cache.Expiry) {
+		return string(cache.Token), cache.Expiry, nil
+	}
+
+	token, err := newToken(ctx)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error getting installation token: %w", err)
+	}
+
+	expiry := time.Now().Add(ghcrTokenLifetime)
+
+	if err := r.setTokenCacheFunc(ctx, repo)(ctx, token, expiry); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiry, nil
+}
+
+// dockerConfigJSON returns the registry's dockerconfigjson, refreshing the installation token first if
+// the registry is a GHCR registry
+func (r *Registry) dockerConfigJSON(ctx context.Context, conf *config.Config) ([]byte, error) {
+	if r.GithubAppInstallationID != 0 {
+		if _, _, err := r.RefreshGHCRToken(ctx, conf.Repo, conf.GithubAppConf); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.GetDockerConfigJSON(conf.Repo, conf.DOConf)
+}
+
+func (r *Registry) ghcrClient(githubApp *oauth.GithubAppConf) (*github.Client, error) {
+	itr, err := r.ghcrTransport(githubApp)
+	if err != nil {
+		return nil, err
+	}
+
+	return github.NewClient(&http.Client{Transport: itr}), nil
+}
+
+func (r *Registry) listGHCRRepositories(ctx context.Context, githubApp *oauth.GithubAppConf) ([]*ptypes.RegistryRepository, error) {
+	host, organization, err := r.ghcrOrganization()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := r.ghcrClient(githubApp)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &github.PackageListOptions{
+		PackageType: github.String("container"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	res := make([]*ptypes.RegistryRepository, 0)
+
+	for {
+		pkgs, resp, err := client.Organizations.ListPackages(ctx, organization, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pkg := range pkgs {
+			name := organization + "/" + pkg.GetName()
+
+			res = append(res, &ptypes.RegistryRepository{
+				Name:      name,
+				CreatedAt: pkg.GetCreatedAt().Time,
+				URI:       host + "/" + name,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			return res, nil
+		}
+
+		opts.Page = resp.NextPage
+	}
+}
+
+func (r *Registry) listGHCRImages(ctx context.Context, repoName string, githubApp *oauth.GithubAppConf) ([]*ptypes.Image, error) {
+	_, organization, err := r.ghcrOrganization()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := r.ghcrClient(githubApp)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgName := strings.TrimPrefix(repoName, organization+"/")
+
+	opts := &github.PackageListOptions{
+		State:       github.String("active"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	res := make([]*ptypes.Image, 0)
+
+	for {
+		versions, resp, err := client.Organizations.PackageGetAllVersions(ctx, organization, "container", url.PathEscape(pkgName), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, version := range versions {
+			pushedAt := version.GetCreatedAt().Time
+
+			// the name of a container package version is the digest of its manifest
+			for _, tag := range version.GetMetadata().GetContainer().Tags {
+				res = append(res, &ptypes.Image{
+					Digest:         version.GetName(),
+					Tag:            tag,
+					RepositoryName: organization + "/" + pkgName,
+					PushedAt:       &pushedAt,
+				})
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return res, nil
+		}
+
+		opts.Page = resp.NextPage
+	}
+}
+
+func (r *Registry) getGHCRDockerConfigFile(repo repository.Repository) (*configfile.ConfigFile, error) {
+	ctx := context.Background()
+
+	host, _, err := r.ghcrOrganization()
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := r.getTokenCacheFunc(ctx, repo)(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cache.Token) == 0 || cache.IsExpired() {
+		return nil, ErrGHCRTokenExpired
+	}
+
+	return &configfile.ConfigFile{
+		AuthConfigs: map[string]types.AuthConfig{
+			host: {
+				Username: "x-access-token",
+				Password: string(cache.Token),
+				Auth:     generateAuthToken("x-access-token", string(cache.Token)),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,161 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"github.com/karagatandev/porter/internal/repository/test"
+)
+
+func TestRefreshGHCRToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		cachedToken string
+		cachedUntil time.Duration
+		wantToken   string
+		wantRefresh bool
+	}{
+		{
+			name:        "no cached token",
+			wantToken:   "ghs_new",
+			wantRefresh: true,
+		},
+		{
+			name:        "cached token is valid",
+			cachedToken: "ghs_cached",
+			cachedUntil: 30 * time.Minute,
+			wantToken:   "ghs_cached",
+		},
+		{
+			name:        "cached token is about to expire",
+			cachedToken: "ghs_cached",
+			cachedUntil: 2 * time.Minute,
+			wantToken:   "ghs_new",
+			wantRefresh: true,
+		},
+		{
+			name:        "cached token has expired",
+			cachedToken: "ghs_cached",
+			cachedUntil: -time.Minute,
+			wantToken:   "ghs_new",
+			wantRefresh: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, reg := newGHCRRegistry(t, tt.cachedToken, tt.cachedUntil)
+
+			var refreshed bool
+
+			token, expiry, err := reg.refreshGHCRToken(context.Background(), repo, func(ctx context.Context) (string, error) {
+				refreshed = true
+				return "ghs_new", nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if token != tt.wantToken {
+				t.Errorf("expected token %s, got %s", tt.wantToken, token)
+			}
+
+			if refreshed != tt.wantRefresh {
+				t.Errorf("expected refresh to be %t, got %t", tt.wantRefresh, refreshed)
+			}
+
+			if !time.Now().Add(5 * time.Minute).Before(expiry) {
+				t.Errorf("expected the token to be valid for at least five minutes, expires at %s", expiry)
+			}
+
+			cached, err := repo.Registry().ReadRegistry(reg.ProjectID, reg.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(cached.TokenCache.Token) != tt.wantToken {
+				t.Errorf("expected token %s to be cached, got %s", tt.wantToken, cached.TokenCache.Token)
+			}
+		})
+	}
+}
+
+func TestRefreshGHCRTokenError(t *testing.T) {
+	repo, reg := newGHCRRegistry(t, "ghs_cached", -time.Minute)
+
+	_, _, err := reg.refreshGHCRToken(context.Background(), repo, func(ctx context.Context) (string, error) {
+		return "", errors.New("installation suspended")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	cached, err := repo.Registry().ReadRegistry(reg.ProjectID, reg.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(cached.TokenCache.Token) != "ghs_cached" {
+		t.Errorf("expected the cached token to be kept, got %s", cached.TokenCache.Token)
+	}
+}
+
+func TestGetGHCRDockerConfigJSON(t *testing.T) {
+	repo, reg := newGHCRRegistry(t, "ghs_cached", 30*time.Minute)
+
+	data, err := reg.GetDockerConfigJSON(repo, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var conf struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+
+	if err := json.Unmarshal(data, &conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if auth := conf.Auths["ghcr.io"]; auth.Username != "x-access-token" || auth.Password != "ghs_cached" {
+		t.Errorf("expected the cached token to be used for ghcr.io, got %+v", conf.Auths)
+	}
+
+	expiredRepo, expiredReg := newGHCRRegistry(t, "ghs_cached", -time.Minute)
+
+	if _, err := expiredReg.GetDockerConfigJSON(expiredRepo, nil); !errors.Is(err, ErrGHCRTokenExpired) {
+		t.Errorf("expected ErrGHCRTokenExpired, got %v", err)
+	}
+}
+
+// newGHCRRegistry creates a GHCR registry with a cached installation token which expires after cachedUntil
+func newGHCRRegistry(t *testing.T, cachedToken string, cachedUntil time.Duration) (repository.Repository, *Registry) {
+	t.Helper()
+
+	repo := test.NewRepository(true)
+
+	model, err := repo.Registry().CreateRegistry(&models.Registry{
+		ProjectID:               1,
+		URL:                     "ghcr.io/acme",
+		GithubAppInstallationID: 1234,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cachedToken != "" {
+		model.TokenCache.Token = []byte(cachedToken)
+		model.TokenCache.Expiry = time.Now().Add(cachedUntil)
+	}
+
+	reg := Registry(*model)
+
+	return repo, &reg
+}
@@ -0,0 +1,191 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ptypes "github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// Harbor registries are connected with a robot account stored in a basic integration, and with a URL in
+// the form harbor.example.com/project. Repositories are listed with the Harbor API rather than the
+// registry catalog, which Harbor only serves to system administrators.
+
+// harborClient makes requests to the Harbor API of a project
+type harborClient struct {
+	client *http.Client
+	// baseURL is the URL of the Harbor API, in the form scheme://host/api/v2.0
+	baseURL  string
+	host     string
+	project  string
+	username string
+	password string
+}
+
+func (r *Registry) harborClient(repo repository.Repository) (*harborClient, error) {
+	scheme, host, project, err := parseRegistryURL(r.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if project == "" || strings.Contains(project, "/") {
+		return nil, fmt.Errorf("invalid Harbor registry url %s: expected to be in the form HOST/PROJECT", r.URL)
+	}
+
+	basic, err := repo.BasicIntegration().ReadBasicIntegration(r.ProjectID, r.BasicIntegrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &harborClient{
+		client:   &http.Client{Timeout: time.Minute},
+		baseURL:  fmt.Sprintf("%s://%s/api/v2.0", scheme, host),
+		host:     host,
+		project:  project,
+		username: string(basic.Username),
+		password: string(basic.Password),
+	}, nil
+}
+
+// get requests a path of the Harbor API and decodes the response into res
+func (h *harborClient) get(ctx context.Context, path string, res interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(h.username, h.password)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("harbor returned %d for %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+// harborPageSize is the maximum page size of the Harbor API
+const harborPageSize = 100
+
+type harborRepository struct {
+	// Name is the name of the repository including the project, such as project/app
+	Name         string    `json:"name"`
+	CreationTime time.Time `json:"creation_time"`
+}
+
+type harborArtifact struct {
+	Digest   string    `json:"digest"`
+	PushTime time.Time `json:"push_time"`
+	Tags     []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+func (r *Registry) listHarborRepositories(ctx context.Context, repo repository.Repository) ([]*ptypes.RegistryRepository, error) {
+	h, err := r.harborClient(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*ptypes.RegistryRepository, 0)
+
+	for page := 1; ; page++ {
+		repos := []harborRepository{}
+
+		path := fmt.Sprintf("/projects/%s/repositories?page=%d&page_size=%d", url.PathEscape(h.project), page, harborPageSize)
+
+		if err := h.get(ctx, path, &repos); err != nil {
+			return nil, err
+		}
+
+		for _, hRepo := range repos {
+			res = append(res, &ptypes.RegistryRepository{
+				Name:      hRepo.Name,
+				CreatedAt: hRepo.CreationTime,
+				URI:       h.host + "/" + hRepo.Name,
+			})
+		}
+
+		if len(repos) < harborPageSize {
+			return res, nil
+		}
+	}
+}
+
+func (r *Registry) listHarborImages(ctx context.Context, repoName string, repo repository.Repository) ([]*ptypes.Image, error) {
+	h, err := r.harborClient(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// repositories are named relative to their project in the artifacts API, and slashes in the name
+	// must be escaped twice
+	relName := strings.TrimPrefix(repoName, h.project+"/")
+
+	res := make([]*ptypes.Image, 0)
+
+	for page := 1; ; page++ {
+		artifacts := []harborArtifact{}
+
+		path := fmt.Sprintf(
+			"/projects/%s/repositories/%s/artifacts?with_tag=true&page=%d&page_size=%d",
+			url.PathEscape(h.project), url.PathEscape(url.PathEscape(relName)), page, harborPageSize,
+		)
+
+		if err := h.get(ctx, path, &artifacts); err != nil {
+			return nil, err
+		}
+
+		for _, artifact := range artifacts {
+			pushedAt := artifact.PushTime
+
+			for _, tag := range artifact.Tags {
+				res = append(res, &ptypes.Image{
+					Digest:         artifact.Digest,
+					Tag:            tag.Name,
+					RepositoryName: repoName,
+					PushedAt:       &pushedAt,
+				})
+			}
+		}
+
+		if len(artifacts) < harborPageSize {
+			return res, nil
+		}
+	}
+}
+
+// checkHarborProject checks that the registry's project exists and can be read by the robot account.
+// Harbor creates repositories when they are first pushed to, as long as their project exists.
+func (r *Registry) checkHarborProject(ctx context.Context, repo repository.Repository) error {
+	h, err := r.harborClient(repo)
+	if err != nil {
+		return err
+	}
+
+	project := struct {
+		Name string `json:"name"`
+	}{}
+
+	if err := h.get(ctx, "/projects/"+url.PathEscape(h.project), &project); err != nil {
+		return fmt.Errorf("error reading Harbor project %s: %w", h.project, err)
+	}
+
+	return nil
+}
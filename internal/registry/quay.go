@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ptypes "github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// Quay registries are connected with a robot account of an organization stored in a basic integration,
+// and with a URL in the form quay.io/organization. Robot tokens can't call the Quay API, so repositories
+// are listed with the registry catalog, which Quay filters to the repositories the robot can access.
+// Quay creates repositories when they are first pushed to by a robot with the creator role.
+
+func (r *Registry) quayClient(repo repository.Repository) (*v2Client, string, string, error) {
+	scheme, host, organization, err := parseRegistryURL(r.URL)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if organization == "" || strings.Contains(organization, "/") {
+		return nil, "", "", fmt.Errorf("invalid Quay registry url %s: expected to be in the form HOST/ORGANIZATION", r.URL)
+	}
+
+	basic, err := repo.BasicIntegration().ReadBasicIntegration(r.ProjectID, r.BasicIntegrationID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return newV2Client(scheme, host, string(basic.Username), string(basic.Password)), host, organization, nil
+}
+
+func (r *Registry) listQuayRepositories(ctx context.Context, repo repository.Repository) ([]*ptypes.RegistryRepository, error) {
+	client, host, organization, err := r.quayClient(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := client.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*ptypes.RegistryRepository, 0)
+
+	for _, name := range names {
+		if !strings.HasPrefix(name, organization+"/") {
+			continue
+		}
+
+		res = append(res, &ptypes.RegistryRepository{
+			Name: name,
+			URI:  host + "/" + name,
+		})
+	}
+
+	return res, nil
+}
+
+func (r *Registry) listQuayImages(ctx context.Context, repoName string, repo repository.Repository) ([]*ptypes.Image, error) {
+	client, _, organization, err := r.quayClient(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(repoName, organization+"/") {
+		repoName = organization + "/" + repoName
+	}
+
+	v2 := &v2Repository{client: client, name: repoName}
+
+	imgs, err := v2.images(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*ptypes.Image, 0, len(imgs))
+
+	for _, img := range imgs {
+		res = append(res, &ptypes.Image{
+			Digest:         img.Digest,
+			Tag:            img.Tag,
+			RepositoryName: repoName,
+			PushedAt:       img.PushedAt,
+		})
+	}
+
+	return res, nil
+}
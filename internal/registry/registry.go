@@ -85,6 +85,36 @@ func (r *Registry) ListRepositories(
 		telemetry.AttributeKV{Key: "project-id", Value: r.ProjectID},
 	)
 
+	switch ptypes.RegistryService(r.Service) {
+	case ptypes.Harbor:
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "auth-mechanism", Value: "harbor"})
+
+		repos, err := r.listHarborRepositories(ctx, repo)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error listing harbor repositories")
+		}
+
+		return repos, nil
+	case ptypes.Quay:
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "auth-mechanism", Value: "quay"})
+
+		repos, err := r.listQuayRepositories(ctx, repo)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error listing quay repositories")
+		}
+
+		return repos, nil
+	case ptypes.GHCR:
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "auth-mechanism", Value: "ghcr"})
+
+		repos, err := r.listGHCRRepositories(ctx, conf.GithubAppConf)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error listing ghcr repositories")
+		}
+
+		return repos, nil
+	}
+
 	// switch on the auth mechanism to get a token
 	if r.AWSIntegrationID != 0 {
 		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "auth-mechanism", Value: "aws"})
@@ -883,6 +913,19 @@ func (r *Registry) CreateRepository(
 
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "registry-uri", Value: r.URL})
 
+	switch ptypes.RegistryService(r.Service) {
+	case ptypes.Harbor:
+		// repositories are created on push, but only in a project which exists
+		if err := r.checkHarborProject(ctx, conf.Repo); err != nil {
+			return telemetry.Error(ctx, span, err, "error checking harbor project")
+		}
+
+		return nil
+	case ptypes.Quay, ptypes.GHCR:
+		// repositories are created on push
+		return nil
+	}
+
 	// if aws, create repository
 	if r.AWSIntegrationID != 0 {
 		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "aws-integration-id", Value: r.AWSIntegrationID})
@@ -1050,6 +1093,15 @@ func (r *Registry) ListImages(
 		telemetry.AttributeKV{Key: "repo-name", Value: repoName},
 	)
 
+	switch ptypes.RegistryService(r.Service) {
+	case ptypes.Harbor:
+		return r.listHarborImages(ctx, repoName, repo)
+	case ptypes.Quay:
+		return r.listQuayImages(ctx, repoName, repo)
+	case ptypes.GHCR:
+		return r.listGHCRImages(ctx, repoName, conf.GithubAppConf)
+	}
+
 	// switch on the auth mechanism to get a token
 	if r.AWSIntegrationID != 0 {
 		aws, err := repo.AWSIntegration().ReadAWSIntegration(
@@ -1595,9 +1647,13 @@ func (r *Registry) listDOCRImages(
 	res := make([]*ptypes.Image, 0)
 
 	for _, tag := range tags {
+		updatedAt := tag.UpdatedAt
+
 		res = append(res, &ptypes.Image{
 			RepositoryName: repoName,
 			Tag:            tag.Tag,
+			Digest:         tag.ManifestDigest,
+			PushedAt:       &updatedAt,
 		})
 	}
 
@@ -1756,7 +1812,7 @@ func (r *Registry) listDockerHubImages(repoName string, repo repository.Reposito
 }
 
 // GetDockerConfigJSON returns a dockerconfigjson file contents with "auths"
-// populated.
+// populated. GHCR registries use the installation token cached by RefreshGHCRToken.
 func (r *Registry) GetDockerConfigJSON(
 	repo repository.Repository,
 	doAuth *oauth2.Config, // only required if using DOCR
@@ -1785,6 +1841,10 @@ func (r *Registry) GetDockerConfigJSON(
 		conf, err = r.getACRDockerConfigFile(repo)
 	}
 
+	if r.GithubAppInstallationID != 0 {
+		conf, err = r.getGHCRDockerConfigFile(repo)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,457 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/digitalocean/godo"
+	ptypes "github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/oauth"
+	"github.com/karagatandev/porter/internal/repository"
+	"github.com/karagatandev/porter/internal/retention"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"github.com/porter-dev/api-contracts/generated/go/helpers"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"golang.org/x/oauth2"
+	v1artifactregistry "google.golang.org/api/artifactregistry/v1"
+	"google.golang.org/api/option"
+)
+
+// ErrRetentionNotSupported is returned when images can't be garbage collected from a registry
+var ErrRetentionNotSupported = errors.New("garbage collection is not supported for this registry")
+
+// ErrLegacyAppRepository is returned when images would be garbage collected from a repository which legacy
+// applications deploy from. Their deployed images are only recorded in their Helm releases, so retention
+// rules can't tell which of them are in use.
+var ErrLegacyAppRepository = errors.New("garbage collection is not supported for repositories used by legacy applications")
+
+// PlanRetention lists the images in a repository and applies the retention rules to them. Images deployed
+// by the project's app revisions are kept according to the rules, and images deployed by the latest
+// revision of any app are always kept.
+func (r *Registry) PlanRetention(
+	ctx context.Context,
+	repoName string,
+	rules retention.Rules,
+	repo repository.Repository,
+	doAuth *oauth2.Config,
+) (*retention.Plan, error) {
+	ctx, span := telemetry.NewSpan(ctx, "plan-registry-retention")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "registry-id", Value: r.ID},
+		telemetry.AttributeKV{Key: "repo-name", Value: repoName},
+	)
+
+	images, err := r.ListRetentionImages(ctx, repoName, repo, doAuth)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing images")
+	}
+
+	now := time.Now().UTC()
+
+	deployments, err := ImageDeployments(repo, r.ProjectID, repoName, now.Add(-rules.KeepDeployedWithin))
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing image deployments")
+	}
+
+	plan := retention.NewPlan(images, deployments, rules, now)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "images", Value: len(images)},
+		telemetry.AttributeKV{Key: "kept", Value: len(plan.Keep)},
+		telemetry.AttributeKV{Key: "deleted", Value: len(plan.Delete)},
+	)
+
+	return plan, nil
+}
+
+// ImageDeployments lists the images from a repository which were deployed by a project's app revisions
+// created since a time, or by the latest revision of an app. Repositories are matched by name, so
+// images from a repository of the same name in another registry are listed too. ErrLegacyAppRepository
+// is returned if a legacy application of the project deploys from the repository.
+func ImageDeployments(repo repository.Repository, projectID uint, repoName string, since time.Time) ([]retention.Deployment, error) {
+	releases, err := repo.Release().ListReleasesByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, release := range releases {
+		uri := release.ImageRepoURI
+		if uri == "" && release.GitActionConfig != nil {
+			uri = release.GitActionConfig.ImageRepoURI
+		}
+
+		if uri != "" && matchesRepository(uri, repoName) {
+			return nil, fmt.Errorf("%w: %s is used by %s", ErrLegacyAppRepository, repoName, release.Name)
+		}
+	}
+
+	recent, err := repo.AppRevision().ListAppRevisionsCreatedSince(projectID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	// the latest revision may have failed, in which case the previous successful revision is still running
+	latest, err := repo.AppRevision().ListLatestAppRevisions(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	latestDeployed, err := repo.AppRevision().ListLatestAppRevisions(projectID, models.DeployedAppRevisionStatuses...)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []retention.Deployment
+
+	add := func(revisions []*models.AppRevision, current bool) error {
+		for _, rev := range revisions {
+			image, err := appRevisionImage(rev)
+			if err != nil {
+				return fmt.Errorf("error reading image of app revision %s: %w", rev.ID, err)
+			}
+
+			if image == nil || !matchesRepository(image.Repository, repoName) {
+				continue
+			}
+
+			tag, digest := retention.ParseImageTag(image.Tag)
+
+			res = append(res, retention.Deployment{
+				Tag:        tag,
+				Digest:     digest,
+				DeployedAt: rev.CreatedAt,
+				Current:    current,
+			})
+		}
+
+		return nil
+	}
+
+	if err := add(recent, false); err != nil {
+		return nil, err
+	}
+
+	if err := add(latest, true); err != nil {
+		return nil, err
+	}
+
+	if err := add(latestDeployed, true); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// matchesRepository returns true if an image repository uri is the repository of the given name, in any registry
+func matchesRepository(uri, repoName string) bool {
+	return uri == repoName || strings.HasSuffix(uri, "/"+repoName)
+}
+
+func appRevisionImage(rev *models.AppRevision) (*porterv1.AppImage, error) {
+	if rev.Base64App == "" {
+		return nil, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(rev.Base64App)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &porterv1.PorterApp{}
+
+	if err := helpers.UnmarshalContractObject(decoded, app); err != nil {
+		return nil, err
+	}
+
+	return app.Image, nil
+}
+
+// ListRetentionImages lists the images in a repository along with their digests and push times, which
+// retention rules need but which ListImages doesn't return for every registry
+func (r *Registry) ListRetentionImages(
+	ctx context.Context,
+	repoName string,
+	repo repository.Repository,
+	doAuth *oauth2.Config,
+) ([]retention.Image, error) {
+	if r.AWSIntegrationID != 0 {
+		aws, err := repo.AWSIntegration().ReadAWSIntegration(r.ProjectID, r.AWSIntegrationID)
+		if err != nil {
+			return nil, err
+		}
+
+		imgs, err := r.listECRImages(aws, repoName, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRetentionImages(imgs), nil
+	}
+
+	if r.GCPIntegrationID != 0 {
+		if strings.Contains(r.URL, "pkg.dev") {
+			imgs, err := r.listGARImages(ctx, repoName, repo)
+			if err != nil {
+				return nil, err
+			}
+
+			return toRetentionImages(imgs), nil
+		}
+
+		return r.listGCRRetentionImages(ctx, repoName, repo)
+	}
+
+	if r.DOIntegrationID != 0 {
+		imgs, err := r.listDOCRImages(repoName, repo, doAuth)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRetentionImages(imgs), nil
+	}
+
+	if r.AzureIntegrationID != 0 || (r.BasicIntegrationID != 0 && !strings.Contains(r.URL, "docker.io")) {
+		v2, err := r.v2Repository(repoName, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		return v2.images(ctx)
+	}
+
+	return nil, ErrRetentionNotSupported
+}
+
+// DeleteManifest deletes an image manifest, and with it all of its tags, from a repository
+func (r *Registry) DeleteManifest(
+	ctx context.Context,
+	repoName string,
+	manifest retention.Manifest,
+	repo repository.Repository,
+	doAuth *oauth2.Config,
+) error {
+	ctx, span := telemetry.NewSpan(ctx, "delete-registry-manifest")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "registry-id", Value: r.ID},
+		telemetry.AttributeKV{Key: "repo-name", Value: repoName},
+		telemetry.AttributeKV{Key: "digest", Value: manifest.Digest},
+	)
+
+	if manifest.Digest == "" {
+		return telemetry.Error(ctx, span, nil, "cannot delete a manifest without a digest")
+	}
+
+	var err error
+
+	switch {
+	case r.AWSIntegrationID != 0:
+		err = r.deleteECRManifest(repoName, manifest.Digest, repo)
+	case r.GCPIntegrationID != 0 && strings.Contains(r.URL, "pkg.dev"):
+		err = r.deleteGARManifest(ctx, repoName, manifest.Digest, repo)
+	case r.GCPIntegrationID != 0:
+		// GCR refuses to delete manifests which are still tagged
+		var v2 *v2Repository
+
+		v2, err = r.v2Repository(repoName, repo)
+		if err != nil {
+			break
+		}
+
+		for _, tag := range manifest.Tags {
+			if err = v2.deleteManifest(ctx, tag); err != nil {
+				break
+			}
+		}
+
+		if err == nil {
+			err = v2.deleteManifest(ctx, manifest.Digest)
+		}
+	case r.DOIntegrationID != 0:
+		err = r.deleteDOCRManifest(ctx, repoName, manifest.Digest, repo, doAuth)
+	case r.AzureIntegrationID != 0 || (r.BasicIntegrationID != 0 && !strings.Contains(r.URL, "docker.io")):
+		var v2 *v2Repository
+
+		v2, err = r.v2Repository(repoName, repo)
+		if err == nil {
+			err = v2.deleteManifest(ctx, manifest.Digest)
+		}
+	default:
+		err = ErrRetentionNotSupported
+	}
+
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting manifest")
+	}
+
+	return nil
+}
+
+func toRetentionImages(imgs []*ptypes.Image) []retention.Image {
+	res := make([]retention.Image, 0, len(imgs))
+
+	for _, img := range imgs {
+		res = append(res, retention.Image{
+			Tag:      img.Tag,
+			Digest:   img.Digest,
+			PushedAt: img.PushedAt,
+		})
+	}
+
+	return res
+}
+
+func (r *Registry) deleteECRManifest(repoName, digest string, repo repository.Repository) error {
+	aws, err := repo.AWSIntegration().ReadAWSIntegration(r.ProjectID, r.AWSIntegrationID)
+	if err != nil {
+		return err
+	}
+
+	sess, err := aws.GetSession()
+	if err != nil {
+		return err
+	}
+
+	resp, err := ecr.New(sess).BatchDeleteImage(&ecr.BatchDeleteImageInput{
+		RepositoryName: &repoName,
+		ImageIds:       []*ecr.ImageIdentifier{{ImageDigest: &digest}},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, failure := range resp.Failures {
+		// the image was already deleted
+		if failure.FailureCode != nil && *failure.FailureCode == ecr.ImageFailureCodeImageNotFound {
+			continue
+		}
+
+		return fmt.Errorf("error deleting image %s: %s", digest, failure.String())
+	}
+
+	return nil
+}
+
+func (r *Registry) deleteGARManifest(ctx context.Context, repoName, digest string, repo repository.Repository) error {
+	repoImageSlice := strings.Split(repoName, "/")
+
+	if len(repoImageSlice) != 2 {
+		return fmt.Errorf("invalid GAR repo name: %s. Expected to be in the form of REPOSITORY/IMAGE", repoName)
+	}
+
+	gcpInt, err := repo.GCPIntegration().ReadGCPIntegration(r.ProjectID, r.GCPIntegrationID)
+	if err != nil {
+		return err
+	}
+
+	svc, err := v1artifactregistry.NewService(ctx, option.WithTokenSource(&garTokenSource{
+		reg:  r,
+		repo: repo,
+		ctx:  ctx,
+	}))
+	if err != nil {
+		return err
+	}
+
+	parsedURL, err := url.Parse("https://" + r.URL)
+	if err != nil {
+		return err
+	}
+
+	location := strings.TrimSuffix(parsedURL.Host, "-docker.pkg.dev")
+
+	name := fmt.Sprintf(
+		"projects/%s/locations/%s/repositories/%s/packages/%s/versions/%s",
+		gcpInt.GCPProjectID, location, repoImageSlice[0], url.PathEscape(repoImageSlice[1]), digest,
+	)
+
+	// deleting a version deletes its tags too, which requires forcing the deletion
+	_, err = v1artifactregistry.NewProjectsLocationsRepositoriesPackagesVersionsService(svc).Delete(name).Force(true).Context(ctx).Do()
+
+	return err
+}
+
+func (r *Registry) deleteDOCRManifest(
+	ctx context.Context,
+	repoName, digest string,
+	repo repository.Repository,
+	doAuth *oauth2.Config,
+) error {
+	oauthInt, err := repo.OAuthIntegration().ReadOAuthIntegration(r.ProjectID, r.DOIntegrationID)
+	if err != nil {
+		return err
+	}
+
+	tok, _, err := oauth.GetAccessToken(oauthInt.SharedOAuthModel, doAuth, oauth.MakeUpdateOAuthIntegrationTokenFunction(oauthInt, repo))
+	if err != nil {
+		return err
+	}
+
+	urlArr := strings.Split(r.URL, "/")
+
+	if len(urlArr) != 2 {
+		return fmt.Errorf("invalid digital ocean registry url")
+	}
+
+	_, err = godo.NewFromToken(tok).Registry.DeleteManifest(ctx, urlArr[1], repoName, digest)
+
+	return err
+}
+
+type gcrManifestsResp struct {
+	Manifest map[string]struct {
+		Tag            []string `json:"tag"`
+		TimeUploadedMs string   `json:"timeUploadedMs"`
+	} `json:"manifest"`
+}
+
+// listGCRRetentionImages lists images with GCR's extension of the tags list, which includes digests and upload times
+func (r *Registry) listGCRRetentionImages(ctx context.Context, repoName string, repo repository.Repository) ([]retention.Image, error) {
+	v2, err := r.v2Repository(repoName, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v2.do(ctx, http.MethodGet, "/tags/list")
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	manifests := gcrManifestsResp{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&manifests); err != nil {
+		return nil, fmt.Errorf("could not read GCR manifests: %w", err)
+	}
+
+	var res []retention.Image
+
+	for digest, m := range manifests.Manifest {
+		var pushedAt *time.Time
+
+		if ms, err := strconv.ParseInt(m.TimeUploadedMs, 10, 64); err == nil {
+			t := time.UnixMilli(ms).UTC()
+			pushedAt = &t
+		}
+
+		for _, tag := range m.Tag {
+			res = append(res, retention.Image{Tag: tag, Digest: digest, PushedAt: pushedAt})
+		}
+	}
+
+	return res, nil
+}
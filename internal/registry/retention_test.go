@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository/test"
+)
+
+func TestImageDeploymentsRefusesLegacyAppRepositories(t *testing.T) {
+	repo := test.NewRepository(true)
+
+	releases := []*models.Release{
+		{ProjectID: 1, Name: "api", ImageRepoURI: "123456789012.dkr.ecr.us-east-1.amazonaws.com/api"},
+		{ProjectID: 1, Name: "worker", GitActionConfig: &models.GitActionConfig{ImageRepoURI: "ghcr.io/acme/worker"}},
+		{ProjectID: 2, Name: "web", ImageRepoURI: "ghcr.io/acme/web"},
+	}
+
+	for _, release := range releases {
+		if _, err := repo.Release().CreateRelease(release); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, repoName := range []string{"api", "acme/worker"} {
+		if _, err := ImageDeployments(repo, 1, repoName, time.Now()); !errors.Is(err, ErrLegacyAppRepository) {
+			t.Errorf("expected ErrLegacyAppRepository for %s, got %v", repoName, err)
+		}
+	}
+
+	// the legacy application deploys from a repository of another project, so only the app revisions of the
+	// project are read, which the test repository can't do
+	if _, err := ImageDeployments(repo, 1, "acme/web", time.Now()); errors.Is(err, ErrLegacyAppRepository) {
+		t.Errorf("expected a repository of another project's legacy application to be allowed")
+	}
+}
+
+func TestMatchesRepository(t *testing.T) {
+	tests := []struct {
+		uri      string
+		repoName string
+		want     bool
+	}{
+		{uri: "api", repoName: "api", want: true},
+		{uri: "123456789012.dkr.ecr.us-east-1.amazonaws.com/api", repoName: "api", want: true},
+		{uri: "ghcr.io/acme/api", repoName: "acme/api", want: true},
+		{uri: "ghcr.io/acme/internal-api", repoName: "api", want: false},
+		{uri: "ghcr.io/acme/api-v2", repoName: "acme/api", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesRepository(tt.uri, tt.repoName); got != tt.want {
+			t.Errorf("matchesRepository(%s, %s): expected %t, got %t", tt.uri, tt.repoName, tt.want, got)
+		}
+	}
+}
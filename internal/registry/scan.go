@@ -0,0 +1,168 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/internal/imagescan"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// ErrImageScanningDisabled is returned when an image is scanned but no image scanner is configured
+var ErrImageScanningDisabled = errors.New("image scanning is not enabled on this Porter instance")
+
+// ScanImage scans an image in the registry for vulnerabilities with the configured scanner and
+// stores the findings. The image is scanned by digest if it is set, and by tag otherwise.
+func (r *Registry) ScanImage(ctx context.Context, conf *config.Config, repository, tag, digest string) (*models.ImageScan, error) {
+	ctx, span := telemetry.NewSpan(ctx, "scan-image")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "registry-id", Value: r.ID},
+		telemetry.AttributeKV{Key: "repository", Value: repository},
+		telemetry.AttributeKV{Key: "tag", Value: tag},
+		telemetry.AttributeKV{Key: "digest", Value: digest},
+	)
+
+	if conf.ImageScanner == nil {
+		return nil, ErrImageScanningDisabled
+	}
+
+	dockerConfig, err := r.dockerConfigJSON(ctx, conf)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error getting registry credentials")
+	}
+
+	report, err := conf.ImageScanner.Scan(ctx, imagescan.ImageRef{
+		Repository:       repository,
+		Tag:              tag,
+		Digest:           digest,
+		DockerConfigJSON: dockerConfig,
+	})
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error scanning image")
+	}
+
+	scan, err := models.NewImageScan(r.ProjectID, repository, tag, report)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error encoding scan findings")
+	}
+
+	return conf.Repo.ImageScan().CreateImageScan(ctx, scan)
+}
+
+// FindRegistryForImage returns the registry which hosts an image repository, or nil if none of
+// the registries do
+func FindRegistryForImage(registries []*models.Registry, repository string) *models.Registry {
+	var res *models.Registry
+
+	repository = strings.TrimPrefix(repository, "https://")
+
+	for _, reg := range registries {
+		url := strings.TrimSuffix(strings.TrimPrefix(reg.URL, "https://"), "/")
+
+		if url == "" || !strings.HasPrefix(repository, url+"/") {
+			continue
+		}
+
+		// prefer the most specific registry, e.g. a GAR repository over the GAR host
+		if res == nil || len(url) > len(strings.TrimPrefix(res.URL, "https://")) {
+			res = reg
+		}
+	}
+
+	return res
+}
+
+// CheckImageScanPolicy evaluates a project's image scan policy for an image which is about to be deployed.
+// Scans are looked up by the digest the tag currently points to, since tags may be pushed again. Images which
+// have not been scanned yet, or whose digest can't be resolved, are scanned first if a scanner is configured.
+func CheckImageScanPolicy(ctx context.Context, conf *config.Config, projectID uint, repository, tag string) (imagescan.Decision, error) {
+	ctx, span := telemetry.NewSpan(ctx, "check-image-scan-policy")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "repository", Value: repository},
+		telemetry.AttributeKV{Key: "tag", Value: tag},
+	)
+
+	policyModel, err := conf.Repo.ImageScan().ReadImageScanPolicy(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return imagescan.Decision{}, nil
+		}
+
+		return imagescan.Decision{}, telemetry.Error(ctx, span, err, "error reading image scan policy")
+	}
+
+	policy := policyModel.Policy()
+	if policy.Action == imagescan.ActionOff {
+		return imagescan.Decision{}, nil
+	}
+
+	var scan *models.ImageScan
+
+	digest, err := ResolveImageDigest(ctx, conf, projectID, repository, tag)
+	if err != nil {
+		// findings of an earlier scan of the tag may be stale, so the image is scanned again
+		_ = telemetry.Error(ctx, span, err, "error resolving image digest")
+		digest = ""
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "digest", Value: digest})
+
+	if digest != "" {
+		scan, err = conf.Repo.ImageScan().ReadImageScanByDigest(ctx, projectID, digest)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return imagescan.Decision{}, telemetry.Error(ctx, span, err, "error reading image scan")
+		}
+	}
+
+	if scan == nil && conf.ImageScanner != nil {
+		scan, err = scanImageForPolicy(ctx, conf, projectID, repository, tag, digest)
+		if err != nil {
+			// the image is treated as unscanned, which the policy warns about
+			_ = telemetry.Error(ctx, span, err, "error scanning image")
+		}
+	}
+
+	var report *imagescan.Report
+
+	if scan != nil {
+		report, err = scan.Report()
+		if err != nil {
+			return imagescan.Decision{}, telemetry.Error(ctx, span, err, "error decoding image scan")
+		}
+	}
+
+	decision := policy.Evaluate(report)
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "blocked", Value: decision.Blocked},
+		telemetry.AttributeKV{Key: "message", Value: decision.Message},
+	)
+
+	return decision, nil
+}
+
+func scanImageForPolicy(ctx context.Context, conf *config.Config, projectID uint, repository, tag, digest string) (*models.ImageScan, error) {
+	registries, err := conf.Repo.Registry().ListRegistriesByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	reg := FindRegistryForImage(registries, repository)
+	if reg == nil {
+		return nil, fmt.Errorf("no registry connected to the project hosts %s", repository)
+	}
+
+	_reg := Registry(*reg)
+
+	return _reg.ScanImage(ctx, conf, repository, tag, digest)
+}
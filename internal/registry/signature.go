@@ -0,0 +1,163 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/karagatandev/porter/api/server/shared/config"
+	"github.com/karagatandev/porter/internal/imagesign"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// SignatureDecision is the outcome of verifying the signature of an image which is about to be deployed
+type SignatureDecision struct {
+	// Blocked is true if the image may not be deployed
+	Blocked bool
+	// Message explains why the image was blocked or, if it was not, why it could not be verified
+	Message string
+	// Verification records the verified image, or is nil if the image was not verified
+	Verification *models.ImageVerification
+}
+
+// VerifyImageSignatureInput is the input to VerifyImageSignature
+type VerifyImageSignatureInput struct {
+	ProjectID uint
+	// DeploymentTargetIdentifier is the id or name of the deployment target the image is deployed to
+	DeploymentTargetIdentifier string
+	Repository                 string
+	Tag                        string
+}
+
+// VerifyImageSignature verifies the signature of an image against the project's image trust policy. Images
+// which cannot be verified are refused if the deployment target is protected, and are allowed with a
+// warning otherwise. Verified images are recorded so that the revision deploying them can be pinned to
+// the verified digest.
+func VerifyImageSignature(ctx context.Context, conf *config.Config, inp VerifyImageSignatureInput) (SignatureDecision, error) {
+	ctx, span := telemetry.NewSpan(ctx, "verify-image-signature")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: inp.ProjectID},
+		telemetry.AttributeKV{Key: "deployment-target-identifier", Value: inp.DeploymentTargetIdentifier},
+		telemetry.AttributeKV{Key: "repository", Value: inp.Repository},
+		telemetry.AttributeKV{Key: "tag", Value: inp.Tag},
+	)
+
+	var decision SignatureDecision
+
+	if inp.Repository == "" {
+		return decision, nil
+	}
+
+	var policy imagesign.TrustPolicy
+
+	policyModel, err := conf.Repo.ImageSignature().ReadImageTrustPolicy(ctx, inp.ProjectID)
+	switch {
+	case err == nil:
+		policy, err = policyModel.TrustPolicy()
+		if err != nil {
+			return decision, telemetry.Error(ctx, span, err, "error decoding image trust policy")
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return decision, telemetry.Error(ctx, span, err, "error reading image trust policy")
+	}
+
+	var protected bool
+
+	if inp.DeploymentTargetIdentifier != "" {
+		deploymentTarget, err := conf.Repo.DeploymentTarget().DeploymentTarget(inp.ProjectID, inp.DeploymentTargetIdentifier)
+		if err != nil {
+			return decision, telemetry.Error(ctx, span, err, "error reading deployment target")
+		}
+
+		protected = deploymentTarget.Protected
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "protected", Value: protected})
+
+	if policy.Empty() && !protected {
+		return decision, nil
+	}
+
+	// unverified images are only refused by protected targets
+	refuse := func(reason string) SignatureDecision {
+		telemetry.WithAttributes(span,
+			telemetry.AttributeKV{Key: "verified", Value: false},
+			telemetry.AttributeKV{Key: "reason", Value: reason},
+		)
+
+		if protected {
+			return SignatureDecision{Blocked: true, Message: fmt.Sprintf("deployment target is protected: %s", reason)}
+		}
+
+		return SignatureDecision{Message: reason}
+	}
+
+	if policy.Empty() {
+		return refuse("the project does not trust any image signers"), nil
+	}
+
+	if conf.ImageVerifier == nil {
+		return refuse("image signature verification is not enabled on this Porter instance"), nil
+	}
+
+	image := imagesign.ImageRef{
+		Repository: inp.Repository,
+		Tag:        inp.Tag,
+	}
+
+	dockerConfig, err := dockerConfigForImage(ctx, conf, inp.ProjectID, inp.Repository)
+	if err != nil {
+		return decision, telemetry.Error(ctx, span, err, "error getting registry credentials")
+	}
+
+	image.DockerConfigJSON = dockerConfig
+
+	verification, err := conf.ImageVerifier.Verify(ctx, image, policy)
+	if err != nil {
+		if !errors.Is(err, imagesign.ErrNoValidSignature) && !errors.Is(err, imagesign.ErrMissingAttestation) {
+			_ = telemetry.Error(ctx, span, err, "error verifying image signature")
+		}
+
+		return refuse(fmt.Sprintf("could not verify the signature of %s: %s", image, err)), nil
+	}
+
+	verificationModel, err := conf.Repo.ImageSignature().CreateImageVerification(
+		ctx,
+		models.NewImageVerification(inp.ProjectID, inp.Repository, inp.Tag, conf.ImageVerifier.Name(), verification),
+	)
+	if err != nil {
+		return decision, telemetry.Error(ctx, span, err, "error recording image verification")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "verified", Value: true},
+		telemetry.AttributeKV{Key: "digest", Value: verification.Digest},
+		telemetry.AttributeKV{Key: "signer", Value: verification.Signer},
+	)
+
+	decision.Verification = verificationModel
+
+	return decision, nil
+}
+
+// dockerConfigForImage returns the credentials for the registry hosting an image, or nil if no registry
+// connected to the project hosts it, in which case the image is assumed to be public
+func dockerConfigForImage(ctx context.Context, conf *config.Config, projectID uint, repository string) ([]byte, error) {
+	registries, err := conf.Repo.Registry().ListRegistriesByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	reg := FindRegistryForImage(registries, repository)
+	if reg == nil {
+		return nil, nil
+	}
+
+	_reg := Registry(*reg)
+
+	return _reg.dockerConfigJSON(ctx, conf)
+}
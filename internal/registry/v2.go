@@ -0,0 +1,426 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/karagatandev/porter/internal/repository"
+	"github.com/karagatandev/porter/internal/retention"
+)
+
+// v2Client makes requests to a registry which implements the Docker registry HTTP API. Requests are
+// authenticated with basic auth, or with a bearer token requested with basic auth when the registry
+// responds with a bearer challenge, as Quay, Harbor and most hosted registries do.
+type v2Client struct {
+	client *http.Client
+	// baseURL is the URL of the registry's API, in the form scheme://host/v2
+	baseURL  string
+	username string
+	password string
+
+	mu sync.Mutex
+	// tokens are the bearer tokens issued by the registry, by the scope of the challenge they answer
+	tokens map[string]string
+}
+
+func newV2Client(scheme, host, username, password string) *v2Client {
+	return &v2Client{
+		client:   &http.Client{Timeout: time.Minute},
+		baseURL:  fmt.Sprintf("%s://%s/v2", scheme, host),
+		username: username,
+		password: password,
+		tokens:   make(map[string]string),
+	}
+}
+
+// parseRegistryURL splits a registry URL, which may not include a scheme, into its scheme, host and path
+func parseRegistryURL(registryURL string) (string, string, string, error) {
+	if !strings.Contains(registryURL, "://") {
+		registryURL = "https://" + registryURL
+	}
+
+	parsedURL, err := url.Parse(registryURL)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return parsedURL.Scheme, parsedURL.Host, strings.Trim(parsedURL.Path, "/"), nil
+}
+
+// do makes a request to a path of the registry's API, and returns an error if the response isn't successful
+func (v *v2Client) do(ctx context.Context, method, path string, accept ...string) (*http.Response, error) {
+	resp, err := v.request(ctx, method, path, "", accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return nil, fmt.Errorf("%s %s returned %d", method, path, http.StatusUnauthorized)
+		}
+
+		token, err := v.bearerToken(ctx, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = v.request(ctx, method, path, token, accept)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return nil, fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return resp, nil
+}
+
+func (v *v2Client) request(ctx context.Context, method, path, token string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, v.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case v.username != "" || v.password != "":
+		req.SetBasicAuth(v.username, v.password)
+	}
+
+	for _, mediaType := range accept {
+		req.Header.Add("Accept", mediaType)
+	}
+
+	return v.client.Do(req)
+}
+
+// bearerToken requests a token answering a bearer challenge from the registry's token service
+func (v *v2Client) bearerToken(ctx context.Context, challenge string) (string, error) {
+	params := parseChallenge(challenge)
+
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry returned a bearer challenge without a realm")
+	}
+
+	v.mu.Lock()
+	token, ok := v.tokens[params["scope"]]
+	v.mu.Unlock()
+
+	if ok {
+		return token, nil
+	}
+
+	query := url.Values{}
+
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if v.username != "" || v.password != "" {
+		req.SetBasicAuth(v.username, v.password)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token service returned %d", resp.StatusCode)
+	}
+
+	tokenResp := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("could not read registry token: %w", err)
+	}
+
+	token = tokenResp.Token
+	if token == "" {
+		token = tokenResp.AccessToken
+	}
+
+	v.mu.Lock()
+	v.tokens[params["scope"]] = token
+	v.mu.Unlock()
+
+	return token, nil
+}
+
+// parseChallenge parses the parameters of a WWW-Authenticate challenge, such as
+// Bearer realm="https://quay.io/v2/auth",service="quay.io",scope="repository:org/app:pull"
+func parseChallenge(challenge string) map[string]string {
+	res := make(map[string]string)
+
+	_, params, _ := strings.Cut(challenge, " ")
+
+	for params != "" {
+		var key, value string
+
+		key, params, _ = strings.Cut(strings.TrimLeft(params, ", "), "=")
+
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+
+		res[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+
+	return res
+}
+
+type v2CatalogResp struct {
+	Repositories []string `json:"repositories"`
+}
+
+// catalog lists the repositories in the registry which the credentials can access
+func (v *v2Client) catalog(ctx context.Context) ([]string, error) {
+	var res []string
+
+	path := "/_catalog?n=100"
+
+	for path != "" {
+		resp, err := v.do(ctx, http.MethodGet, path)
+		if err != nil {
+			return nil, err
+		}
+
+		catalog := v2CatalogResp{}
+
+		err = json.NewDecoder(resp.Body).Decode(&catalog)
+		resp.Body.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read catalog: %w", err)
+		}
+
+		res = append(res, catalog.Repositories...)
+
+		path = nextPagePath(resp.Header.Get("Link"))
+	}
+
+	return res, nil
+}
+
+// nextPagePath returns the path of the next page from a Link header such as
+// </v2/_catalog?last=org%2Fapp&n=100>; rel="next", relative to the /v2 base path
+func nextPagePath(link string) string {
+	if link == "" || !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+
+	start := strings.Index(link, "<")
+	end := strings.Index(link, ">")
+
+	if start == -1 || end < start {
+		return ""
+	}
+
+	next := link[start+1 : end]
+
+	if parsed, err := url.Parse(next); err == nil {
+		next = parsed.RequestURI()
+	}
+
+	return strings.TrimPrefix(next, "/v2")
+}
+
+// v2Repository is a repository in a registry which implements the Docker registry HTTP API
+type v2Repository struct {
+	client *v2Client
+	// name is the path of the repository in the registry
+	name string
+}
+
+func (r *Registry) v2Repository(repoName string, repo repository.Repository) (*v2Repository, error) {
+	scheme, host, path, err := parseRegistryURL(r.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case r.AzureIntegrationID != 0:
+		az, err := repo.AzureIntegration().ReadAzureIntegration(r.ProjectID, r.AzureIntegrationID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &v2Repository{
+			client: newV2Client(scheme, host, az.AzureClientID, string(az.ServicePrincipalSecret)),
+			name:   repoName,
+		}, nil
+	case r.GCPIntegrationID != 0:
+		gcp, err := repo.GCPIntegration().ReadGCPIntegration(r.ProjectID, r.GCPIntegrationID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &v2Repository{
+			client: newV2Client("https", host, "_json_key", string(gcp.GCPKeyData)),
+			name:   path + "/" + repoName,
+		}, nil
+	case r.BasicIntegrationID != 0:
+		basic, err := repo.BasicIntegration().ReadBasicIntegration(r.ProjectID, r.BasicIntegrationID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &v2Repository{
+			client: newV2Client(scheme, host, string(basic.Username), string(basic.Password)),
+			name:   repoName,
+		}, nil
+	}
+
+	return nil, ErrRetentionNotSupported
+}
+
+func (v *v2Repository) do(ctx context.Context, method, path string, accept ...string) (*http.Response, error) {
+	return v.client.do(ctx, method, "/"+v.name+path, accept...)
+}
+
+// manifestMediaTypes are the manifest types accepted from registries, so that registries return the
+// digest of the manifest as it was pushed rather than converting it
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// tags lists the tags in the repository
+func (v *v2Repository) tags(ctx context.Context) ([]string, error) {
+	resp, err := v.do(ctx, http.MethodGet, "/tags/list")
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	tags := gcrImageResp{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("could not read tags: %w", err)
+	}
+
+	return tags.Tags, nil
+}
+
+// images lists the tags in the repository, and reads the digest and creation time of each tag's manifest
+func (v *v2Repository) images(ctx context.Context) ([]retention.Image, error) {
+	tags, err := v.tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]retention.Image, 0, len(tags))
+
+	// the creation time is read from the image config, which is shared by all tags of a manifest
+	created := make(map[string]*time.Time)
+
+	for _, tag := range tags {
+		img := retention.Image{Tag: tag}
+
+		digest, configDigest, err := v.manifest(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		img.Digest = digest
+
+		if configDigest != "" {
+			if _, ok := created[configDigest]; !ok {
+				created[configDigest] = v.createdAt(ctx, configDigest)
+			}
+
+			img.PushedAt = created[configDigest]
+		}
+
+		res = append(res, img)
+	}
+
+	return res, nil
+}
+
+// manifest returns the digest of the manifest a tag refers to and, if it is an image manifest rather
+// than an index, the digest of the image config
+func (v *v2Repository) manifest(ctx context.Context, ref string) (string, string, error) {
+	resp, err := v.do(ctx, http.MethodGet, "/manifests/"+ref, manifestMediaTypes...)
+	if err != nil {
+		return "", "", err
+	}
+
+	defer resp.Body.Close()
+
+	manifest := struct {
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return "", "", fmt.Errorf("could not read manifest of %s: %w", ref, err)
+	}
+
+	return resp.Header.Get("Docker-Content-Digest"), manifest.Config.Digest, nil
+}
+
+// createdAt returns the creation time recorded in an image config, or nil if it can't be read
+func (v *v2Repository) createdAt(ctx context.Context, configDigest string) *time.Time {
+	resp, err := v.do(ctx, http.MethodGet, "/blobs/"+configDigest)
+	if err != nil {
+		return nil
+	}
+
+	defer resp.Body.Close()
+
+	config := struct {
+		Created *time.Time `json:"created"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return nil
+	}
+
+	return config.Created
+}
+
+func (v *v2Repository) deleteManifest(ctx context.Context, ref string) error {
+	resp, err := v.do(ctx, http.MethodDelete, "/manifests/"+ref)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
@@ -1,6 +1,10 @@
 package repository
 
 import (
+	"time"
+
+	"github.com/google/uuid"
+
 	"github.com/karagatandev/porter/internal/models"
 )
 
@@ -8,4 +12,12 @@ import (
 type AppRevisionRepository interface {
 	// AppRevisionById finds an app revision by id
 	AppRevisionById(projectID uint, appRevisionId string) (*models.AppRevision, error)
+	// ListAppRevisionsCreatedSince lists the app revisions of a project which were created after a time
+	ListAppRevisionsCreatedSince(projectID uint, since time.Time) ([]*models.AppRevision, error)
+	// ListLatestAppRevisions lists the latest revision of each app in each deployment target of a project. If
+	// statuses are given, the latest revision with one of the statuses is listed.
+	ListLatestAppRevisions(projectID uint, statuses ...models.AppRevisionStatus) ([]*models.AppRevision, error)
+	// LatestAppRevision finds the latest revision of an app in a deployment target. If statuses are given, the latest
+	// revision with one of the statuses is found. The returned revision has a nil ID if the app has no such revision.
+	LatestAppRevision(projectID uint, porterAppID uint, deploymentTargetID uuid.UUID, statuses ...models.AppRevisionStatus) (*models.AppRevision, error)
 }
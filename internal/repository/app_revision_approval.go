@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// AppRevisionApprovalRepository represents the set of queries on the AppRevisionApproval model
+type AppRevisionApprovalRepository interface {
+	CreateAppRevisionApproval(ctx context.Context, approval *models.AppRevisionApproval) (*models.AppRevisionApproval, error)
+	// ReadAppRevisionApproval reads the approval of a revision
+	ReadAppRevisionApproval(ctx context.Context, projectID uint, appRevisionID string) (*models.AppRevisionApproval, error)
+	UpdateAppRevisionApproval(ctx context.Context, approval *models.AppRevisionApproval) (*models.AppRevisionApproval, error)
+	// ListAppRevisionApprovals lists the approvals of an app's revisions in a cluster, newest first
+	ListAppRevisionApprovals(ctx context.Context, projectID, clusterID uint, appName string) ([]*models.AppRevisionApproval, error)
+	// ListExpiredAppRevisionApprovals lists the approvals of all projects which are still awaiting approval after they expired
+	ListExpiredAppRevisionApprovals(ctx context.Context, now time.Time) ([]*models.AppRevisionApproval, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository/gorm/helpers"
+)
+
+// AuditLogRepository represents the set of queries on the AuditLog model
+type AuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, auditLog *models.AuditLog) (*models.AuditLog, error)
+	// ListAuditLogs returns a page of the audit logs of a project matching the filter, newest first
+	ListAuditLogs(ctx context.Context, projectID uint, filter types.AuditLogFilter, opts ...helpers.QueryOption) ([]*models.AuditLog, helpers.PaginatedResult, error)
+	// ListAllAuditLogs returns every audit log of a project matching the filter, newest first
+	ListAllAuditLogs(ctx context.Context, projectID uint, filter types.AuditLogFilter) ([]*models.AuditLog, error)
+	// ReadAuditLogSettings reads the audit log settings of a project
+	ReadAuditLogSettings(ctx context.Context, projectID uint) (*models.AuditLogSettings, error)
+	// UpdateAuditLogSettings creates or replaces the audit log settings of a project
+	UpdateAuditLogSettings(ctx context.Context, settings *models.AuditLogSettings) (*models.AuditLogSettings, error)
+	// DeleteExpiredAuditLogs permanently deletes the audit logs created before the retention of their project,
+	// which is defaultRetention for projects which do not set one
+	DeleteExpiredAuditLogs(ctx context.Context, defaultRetention time.Duration) (int64, error)
+}
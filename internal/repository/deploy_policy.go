@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// DeployPolicyRepository represents the set of queries on the DeploymentTargetPolicy and DeployPolicyOverride models
+type DeployPolicyRepository interface {
+	// ReadDeploymentTargetPolicy reads the policy of a deployment target
+	ReadDeploymentTargetPolicy(ctx context.Context, projectID uint, deploymentTargetID uuid.UUID) (*models.DeploymentTargetPolicy, error)
+	// UpdateDeploymentTargetPolicy creates or replaces the policy of a deployment target
+	UpdateDeploymentTargetPolicy(ctx context.Context, policy *models.DeploymentTargetPolicy) (*models.DeploymentTargetPolicy, error)
+
+	CreateDeployPolicyOverride(ctx context.Context, override *models.DeployPolicyOverride) (*models.DeployPolicyOverride, error)
+	// ListDeployPolicyOverrides lists the policy overrides of a deployment target, newest first
+	ListDeployPolicyOverrides(ctx context.Context, projectID uint, deploymentTargetID uuid.UUID) ([]*models.DeployPolicyOverride, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// EnvGroupProviderRepository represents the set of queries on the EnvGroupProvider model
+type EnvGroupProviderRepository interface {
+	// ReadEnvGroupProvider reads the provider of an environment group
+	ReadEnvGroupProvider(ctx context.Context, projectID, clusterID uint, envGroupName string) (*models.EnvGroupProvider, error)
+	// ListEnvGroupProviders lists the providers of all projects, so that they can be refreshed
+	ListEnvGroupProviders(ctx context.Context) ([]*models.EnvGroupProvider, error)
+	// UpdateEnvGroupProvider creates or replaces the provider of an environment group
+	UpdateEnvGroupProvider(ctx context.Context, provider *models.EnvGroupProvider) (*models.EnvGroupProvider, error)
+	DeleteEnvGroupProvider(ctx context.Context, provider *models.EnvGroupProvider) error
+}
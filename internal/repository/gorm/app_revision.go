@@ -1,6 +1,10 @@
 package gorm
 
 import (
+	"time"
+
+	"github.com/google/uuid"
+
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/repository"
 	"gorm.io/gorm"
@@ -27,3 +31,54 @@ func (repo *AppRevisionRepository) AppRevisionById(projectID uint, id string) (*
 
 	return AppRevision, nil
 }
+
+// ListAppRevisionsCreatedSince lists the app revisions of a project which were created after a time
+func (repo *AppRevisionRepository) ListAppRevisionsCreatedSince(projectID uint, since time.Time) ([]*models.AppRevision, error) {
+	appRevisions := []*models.AppRevision{}
+
+	if err := repo.db.Where("project_id = ? AND created_at >= ?", projectID, since).Find(&appRevisions).Error; err != nil {
+		return nil, err
+	}
+
+	return appRevisions, nil
+}
+
+// ListLatestAppRevisions lists the latest revision of each app in each deployment target of a project. If
+// statuses are given, the latest revision with one of the statuses is listed.
+func (repo *AppRevisionRepository) ListLatestAppRevisions(projectID uint, statuses ...models.AppRevisionStatus) ([]*models.AppRevision, error) {
+	appRevisions := []*models.AppRevision{}
+
+	latest := repo.db.Model(&models.AppRevision{}).
+		Select("porter_app_id, deployment_target_id, MAX(revision_number)").
+		Where("project_id = ?", projectID)
+
+	if len(statuses) > 0 {
+		latest = latest.Where("status IN ?", statuses)
+	}
+
+	latest = latest.Group("porter_app_id, deployment_target_id")
+
+	if err := repo.db.Where("project_id = ? AND (porter_app_id, deployment_target_id, revision_number) IN (?)", projectID, latest).Find(&appRevisions).Error; err != nil {
+		return nil, err
+	}
+
+	return appRevisions, nil
+}
+
+// LatestAppRevision finds the latest revision of an app in a deployment target. If statuses are given, the latest
+// revision with one of the statuses is found. The returned revision has a nil ID if the app has no such revision.
+func (repo *AppRevisionRepository) LatestAppRevision(projectID uint, porterAppID uint, deploymentTargetID uuid.UUID, statuses ...models.AppRevisionStatus) (*models.AppRevision, error) {
+	appRevision := &models.AppRevision{}
+
+	query := repo.db.Where("project_id = ? AND porter_app_id = ? AND deployment_target_id = ?", projectID, porterAppID, deploymentTargetID)
+
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+
+	if err := query.Order("revision_number DESC").Limit(1).Find(appRevision).Error; err != nil {
+		return nil, err
+	}
+
+	return appRevision, nil
+}
@@ -0,0 +1,142 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AppRevisionApprovalRepository uses gorm.DB for querying the database
+type AppRevisionApprovalRepository struct {
+	db  *gorm.DB
+	key *[32]byte
+
+	fieldCipher
+}
+
+// NewAppRevisionApprovalRepository returns an AppRevisionApprovalRepository which uses
+// gorm.DB for querying the database. It accepts an encryption key to encrypt
+// deferred applies
+func NewAppRevisionApprovalRepository(db *gorm.DB, key *[32]byte) repository.AppRevisionApprovalRepository {
+	return &AppRevisionApprovalRepository{db: db, key: key}
+}
+
+// CreateAppRevisionApproval holds a revision until it is approved
+func (repo *AppRevisionApprovalRepository) CreateAppRevisionApproval(ctx context.Context, approval *models.AppRevisionApproval) (*models.AppRevisionApproval, error) {
+	if err := repo.EncryptAppRevisionApprovalData(approval, repo.key); err != nil {
+		return nil, err
+	}
+
+	if err := repo.db.WithContext(ctx).Create(approval).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repo.DecryptAppRevisionApprovalData(approval, repo.key); err != nil {
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+// ReadAppRevisionApproval reads the approval of a revision
+func (repo *AppRevisionApprovalRepository) ReadAppRevisionApproval(ctx context.Context, projectID uint, appRevisionID string) (*models.AppRevisionApproval, error) {
+	approval := &models.AppRevisionApproval{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND app_revision_id = ?", projectID, appRevisionID).First(approval).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repo.DecryptAppRevisionApprovalData(approval, repo.key); err != nil {
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+// UpdateAppRevisionApproval updates the status of an approval
+func (repo *AppRevisionApprovalRepository) UpdateAppRevisionApproval(ctx context.Context, approval *models.AppRevisionApproval) (*models.AppRevisionApproval, error) {
+	if err := repo.EncryptAppRevisionApprovalData(approval, repo.key); err != nil {
+		return nil, err
+	}
+
+	if err := repo.db.WithContext(ctx).Save(approval).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repo.DecryptAppRevisionApprovalData(approval, repo.key); err != nil {
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+// ListAppRevisionApprovals lists the approvals of an app's revisions in a cluster, newest first
+func (repo *AppRevisionApprovalRepository) ListAppRevisionApprovals(ctx context.Context, projectID, clusterID uint, appName string) ([]*models.AppRevisionApproval, error) {
+	approvals := []*models.AppRevisionApproval{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND cluster_id = ? AND app_name = ?", projectID, clusterID, appName).Order("id DESC").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+
+	for _, approval := range approvals {
+		if err := repo.DecryptAppRevisionApprovalData(approval, repo.key); err != nil {
+			return nil, err
+		}
+	}
+
+	return approvals, nil
+}
+
+// ListExpiredAppRevisionApprovals lists the approvals of all projects which are still awaiting approval after they expired
+func (repo *AppRevisionApprovalRepository) ListExpiredAppRevisionApprovals(ctx context.Context, now time.Time) ([]*models.AppRevisionApproval, error) {
+	approvals := []*models.AppRevisionApproval{}
+
+	if err := repo.db.WithContext(ctx).Where("status = ? AND expires_at <= ?", models.AppRevisionStatus_AwaitingApproval, now).Order("id").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+
+	for _, approval := range approvals {
+		if err := repo.DecryptAppRevisionApprovalData(approval, repo.key); err != nil {
+			return nil, err
+		}
+	}
+
+	return approvals, nil
+}
+
+// EncryptAppRevisionApprovalData will encrypt the deferred apply of an approval before
+// writing to the DB
+func (repo *AppRevisionApprovalRepository) EncryptAppRevisionApprovalData(approval *models.AppRevisionApproval, key *[32]byte) error {
+	if len(approval.DeferredApply) == 0 {
+		return nil
+	}
+
+	cipherData, err := repo.encrypt(approval.DeferredApply, key)
+	if err != nil {
+		return err
+	}
+
+	approval.DeferredApply = cipherData
+
+	return nil
+}
+
+// DecryptAppRevisionApprovalData will decrypt the deferred apply of an approval before
+// returning it from the DB
+func (repo *AppRevisionApprovalRepository) DecryptAppRevisionApprovalData(approval *models.AppRevisionApproval, key *[32]byte) error {
+	if len(approval.DeferredApply) == 0 {
+		return nil
+	}
+
+	plaintext, err := repo.decrypt(approval.DeferredApply, key)
+	if err != nil {
+		return err
+	}
+
+	approval.DeferredApply = plaintext
+
+	return nil
+}
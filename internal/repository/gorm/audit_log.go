@@ -0,0 +1,163 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"github.com/karagatandev/porter/internal/repository/gorm/helpers"
+	"github.com/karagatandev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository uses gorm.DB for querying the database
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository returns a AuditLogRepository which uses
+// gorm.DB for querying the database
+func NewAuditLogRepository(db *gorm.DB) repository.AuditLogRepository {
+	return &AuditLogRepository{db}
+}
+
+// CreateAuditLog creates a new audit log
+func (repo *AuditLogRepository) CreateAuditLog(ctx context.Context, auditLog *models.AuditLog) (*models.AuditLog, error) {
+	if err := repo.db.WithContext(ctx).Create(auditLog).Error; err != nil {
+		return nil, err
+	}
+
+	return auditLog, nil
+}
+
+// ListAuditLogs returns a page of the audit logs of a project matching the filter, newest first
+func (repo *AuditLogRepository) ListAuditLogs(
+	ctx context.Context,
+	projectID uint,
+	filter types.AuditLogFilter,
+	opts ...helpers.QueryOption,
+) ([]*models.AuditLog, helpers.PaginatedResult, error) {
+	ctx, span := telemetry.NewSpan(ctx, "list-audit-logs")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: projectID})
+
+	auditLogs := []*models.AuditLog{}
+	paginatedResult := helpers.PaginatedResult{}
+
+	db := repo.filterAuditLogs(ctx, projectID, filter)
+	resultDB := db.Session(&gorm.Session{}).Order("created_at DESC").Scopes(helpers.Paginate(db, &paginatedResult, opts...))
+
+	if err := resultDB.Find(&auditLogs).Error; err != nil {
+		return nil, paginatedResult, telemetry.Error(ctx, span, err, "error listing audit logs")
+	}
+
+	return auditLogs, paginatedResult, nil
+}
+
+// ListAllAuditLogs returns every audit log of a project matching the filter, newest first
+func (repo *AuditLogRepository) ListAllAuditLogs(ctx context.Context, projectID uint, filter types.AuditLogFilter) ([]*models.AuditLog, error) {
+	auditLogs := []*models.AuditLog{}
+
+	if err := repo.filterAuditLogs(ctx, projectID, filter).Order("created_at DESC").Find(&auditLogs).Error; err != nil {
+		return nil, err
+	}
+
+	return auditLogs, nil
+}
+
+// ReadAuditLogSettings reads the audit log settings of a project
+func (repo *AuditLogRepository) ReadAuditLogSettings(ctx context.Context, projectID uint) (*models.AuditLogSettings, error) {
+	settings := &models.AuditLogSettings{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).First(settings).Error; err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// UpdateAuditLogSettings creates or replaces the audit log settings of a project
+func (repo *AuditLogRepository) UpdateAuditLogSettings(ctx context.Context, settings *models.AuditLogSettings) (*models.AuditLogSettings, error) {
+	existing, err := repo.ReadAuditLogSettings(ctx, settings.ProjectID)
+
+	switch {
+	case err == nil:
+		settings.ID = existing.ID
+		settings.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if err := repo.db.WithContext(ctx).Save(settings).Error; err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// DeleteExpiredAuditLogs permanently deletes the audit logs created before the retention of their project,
+// which is defaultRetention for projects which do not set one. A retention of 0 keeps audit logs forever.
+func (repo *AuditLogRepository) DeleteExpiredAuditLogs(ctx context.Context, defaultRetention time.Duration) (int64, error) {
+	settings := []*models.AuditLogSettings{}
+
+	if err := repo.db.WithContext(ctx).Where("retention_days > 0").Find(&settings).Error; err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+
+	var deleted int64
+	var projectIDs []uint
+
+	for _, s := range settings {
+		res := repo.db.WithContext(ctx).Unscoped().
+			Where("project_id = ? AND created_at < ?", s.ProjectID, now.Add(-s.Retention(defaultRetention))).
+			Delete(&models.AuditLog{})
+		if res.Error != nil {
+			return deleted, res.Error
+		}
+
+		deleted += res.RowsAffected
+		projectIDs = append(projectIDs, s.ProjectID)
+	}
+
+	if defaultRetention <= 0 {
+		return deleted, nil
+	}
+
+	query := repo.db.WithContext(ctx).Unscoped().Where("created_at < ?", now.Add(-defaultRetention))
+
+	if len(projectIDs) > 0 {
+		query = query.Where("project_id NOT IN ?", projectIDs)
+	}
+
+	res := query.Delete(&models.AuditLog{})
+
+	return deleted + res.RowsAffected, res.Error
+}
+
+func (repo *AuditLogRepository) filterAuditLogs(ctx context.Context, projectID uint, filter types.AuditLogFilter) *gorm.DB {
+	db := repo.db.WithContext(ctx).Model(&models.AuditLog{}).Where("project_id = ?", projectID)
+
+	if filter.Since != nil {
+		db = db.Where("created_at >= ?", *filter.Since)
+	}
+
+	if filter.Until != nil {
+		db = db.Where("created_at <= ?", *filter.Until)
+	}
+
+	if filter.UserID != nil {
+		db = db.Where("user_id = ?", *filter.UserID)
+	}
+
+	if filter.APITokenID != nil {
+		db = db.Where("api_token_id = ?", *filter.APITokenID)
+	}
+
+	return db
+}
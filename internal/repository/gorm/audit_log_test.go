@@ -0,0 +1,116 @@
+package gorm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+
+	_gorm "gorm.io/gorm"
+)
+
+func TestDeleteExpiredAuditLogs(t *testing.T) {
+	tests := []struct {
+		name             string
+		defaultRetention time.Duration
+		// expected is the number of audit logs left in each project
+		expected map[uint]int
+	}{
+		{
+			name:             "projects without a retention use the default",
+			defaultRetention: 30 * 24 * time.Hour,
+			expected:         map[uint]int{1: 1, 2: 2},
+		},
+		{
+			name:             "a default of 0 keeps audit logs of projects without a retention forever",
+			defaultRetention: 0,
+			expected:         map[uint]int{1: 1, 2: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tester := &tester{
+				dbFileName: "./porter_audit_logs.db",
+			}
+
+			setupTestEnv(tester, t)
+			defer cleanup(tester, t)
+
+			ctx := context.Background()
+			repo := tester.repo.AuditLog()
+
+			// project 1 keeps audit logs for 7 days, and project 2 uses the default retention
+			_, err := repo.UpdateAuditLogSettings(ctx, &models.AuditLogSettings{ProjectID: 1, RetentionDays: 7})
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			for _, projectID := range []uint{1, 2} {
+				for _, age := range []time.Duration{time.Hour, 10 * 24 * time.Hour, 60 * 24 * time.Hour} {
+					_, err := repo.CreateAuditLog(ctx, &models.AuditLog{
+						Model:     _gorm.Model{CreatedAt: time.Now().Add(-age)},
+						ProjectID: projectID,
+					})
+					if err != nil {
+						t.Fatalf("%v\n", err)
+					}
+				}
+			}
+
+			if _, err := repo.DeleteExpiredAuditLogs(ctx, tt.defaultRetention); err != nil {
+				t.Fatalf("%v\n", err)
+			}
+
+			for projectID, expected := range tt.expected {
+				auditLogs, err := repo.ListAllAuditLogs(ctx, projectID, types.AuditLogFilter{})
+				if err != nil {
+					t.Fatalf("%v\n", err)
+				}
+
+				if len(auditLogs) != expected {
+					t.Errorf("expected project %d to have %d audit logs, got %d", projectID, expected, len(auditLogs))
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateAuditLogSettings(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_audit_log_settings.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+	repo := tester.repo.AuditLog()
+
+	for _, retentionDays := range []uint{7, 365} {
+		if _, err := repo.UpdateAuditLogSettings(ctx, &models.AuditLogSettings{ProjectID: 1, RetentionDays: retentionDays}); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	settings, err := repo.ReadAuditLogSettings(ctx, 1)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if settings.RetentionDays != 365 {
+		t.Errorf("expected a retention of 365 days, got %d", settings.RetentionDays)
+	}
+
+	var count int64
+
+	if err := tester.db.Model(&models.AuditLogSettings{}).Count(&count).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if count != 1 {
+		t.Errorf("expected the settings to be replaced, found %d rows", count)
+	}
+}
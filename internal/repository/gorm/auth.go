@@ -1,7 +1,6 @@
 package gorm
 
 import (
-	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/repository"
 	"github.com/karagatandev/porter/internal/repository/credentials"
@@ -14,6 +13,8 @@ import (
 type KubeIntegrationRepository struct {
 	db  *gorm.DB
 	key *[32]byte
+
+	fieldCipher
 }
 
 // NewKubeIntegrationRepository returns a KubeIntegrationRepository which uses
@@ -23,7 +24,7 @@ func NewKubeIntegrationRepository(
 	db *gorm.DB,
 	key *[32]byte,
 ) repository.KubeIntegrationRepository {
-	return &KubeIntegrationRepository{db, key}
+	return &KubeIntegrationRepository{db: db, key: key}
 }
 
 // CreateKubeIntegration creates a new kube auth mechanism
@@ -93,7 +94,7 @@ func (repo *KubeIntegrationRepository) EncryptKubeIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(ki.ClientCertificateData) > 0 {
-		cipherData, err := encryption.Encrypt(ki.ClientCertificateData, key)
+		cipherData, err := repo.encrypt(ki.ClientCertificateData, key)
 		if err != nil {
 			return err
 		}
@@ -102,7 +103,7 @@ func (repo *KubeIntegrationRepository) EncryptKubeIntegrationData(
 	}
 
 	if len(ki.ClientKeyData) > 0 {
-		cipherData, err := encryption.Encrypt(ki.ClientKeyData, key)
+		cipherData, err := repo.encrypt(ki.ClientKeyData, key)
 		if err != nil {
 			return err
 		}
@@ -111,7 +112,7 @@ func (repo *KubeIntegrationRepository) EncryptKubeIntegrationData(
 	}
 
 	if len(ki.Token) > 0 {
-		cipherData, err := encryption.Encrypt(ki.Token, key)
+		cipherData, err := repo.encrypt(ki.Token, key)
 		if err != nil {
 			return err
 		}
@@ -120,7 +121,7 @@ func (repo *KubeIntegrationRepository) EncryptKubeIntegrationData(
 	}
 
 	if len(ki.Username) > 0 {
-		cipherData, err := encryption.Encrypt(ki.Username, key)
+		cipherData, err := repo.encrypt(ki.Username, key)
 		if err != nil {
 			return err
 		}
@@ -129,7 +130,7 @@ func (repo *KubeIntegrationRepository) EncryptKubeIntegrationData(
 	}
 
 	if len(ki.Password) > 0 {
-		cipherData, err := encryption.Encrypt(ki.Password, key)
+		cipherData, err := repo.encrypt(ki.Password, key)
 		if err != nil {
 			return err
 		}
@@ -138,7 +139,7 @@ func (repo *KubeIntegrationRepository) EncryptKubeIntegrationData(
 	}
 
 	if len(ki.Kubeconfig) > 0 {
-		cipherData, err := encryption.Encrypt(ki.Kubeconfig, key)
+		cipherData, err := repo.encrypt(ki.Kubeconfig, key)
 		if err != nil {
 			return err
 		}
@@ -156,7 +157,7 @@ func (repo *KubeIntegrationRepository) DecryptKubeIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(ki.ClientCertificateData) > 0 {
-		plaintext, err := encryption.Decrypt(ki.ClientCertificateData, key)
+		plaintext, err := repo.decrypt(ki.ClientCertificateData, key)
 		if err != nil {
 			return err
 		}
@@ -165,7 +166,7 @@ func (repo *KubeIntegrationRepository) DecryptKubeIntegrationData(
 	}
 
 	if len(ki.ClientKeyData) > 0 {
-		plaintext, err := encryption.Decrypt(ki.ClientKeyData, key)
+		plaintext, err := repo.decrypt(ki.ClientKeyData, key)
 		if err != nil {
 			return err
 		}
@@ -174,7 +175,7 @@ func (repo *KubeIntegrationRepository) DecryptKubeIntegrationData(
 	}
 
 	if len(ki.Token) > 0 {
-		plaintext, err := encryption.Decrypt(ki.Token, key)
+		plaintext, err := repo.decrypt(ki.Token, key)
 		if err != nil {
 			return err
 		}
@@ -183,7 +184,7 @@ func (repo *KubeIntegrationRepository) DecryptKubeIntegrationData(
 	}
 
 	if len(ki.Username) > 0 {
-		plaintext, err := encryption.Decrypt(ki.Username, key)
+		plaintext, err := repo.decrypt(ki.Username, key)
 		if err != nil {
 			return err
 		}
@@ -192,7 +193,7 @@ func (repo *KubeIntegrationRepository) DecryptKubeIntegrationData(
 	}
 
 	if len(ki.Password) > 0 {
-		plaintext, err := encryption.Decrypt(ki.Password, key)
+		plaintext, err := repo.decrypt(ki.Password, key)
 		if err != nil {
 			return err
 		}
@@ -201,7 +202,7 @@ func (repo *KubeIntegrationRepository) DecryptKubeIntegrationData(
 	}
 
 	if len(ki.Kubeconfig) > 0 {
-		plaintext, err := encryption.Decrypt(ki.Kubeconfig, key)
+		plaintext, err := repo.decrypt(ki.Kubeconfig, key)
 		if err != nil {
 			return err
 		}
@@ -216,6 +217,8 @@ func (repo *KubeIntegrationRepository) DecryptKubeIntegrationData(
 type BasicIntegrationRepository struct {
 	db  *gorm.DB
 	key *[32]byte
+
+	fieldCipher
 }
 
 // NewBasicIntegrationRepository returns a BasicIntegrationRepository which uses
@@ -225,7 +228,7 @@ func NewBasicIntegrationRepository(
 	db *gorm.DB,
 	key *[32]byte,
 ) repository.BasicIntegrationRepository {
-	return &BasicIntegrationRepository{db, key}
+	return &BasicIntegrationRepository{db: db, key: key}
 }
 
 // CreateBasicIntegration creates a new basic auth mechanism
@@ -322,7 +325,7 @@ func (repo *BasicIntegrationRepository) EncryptBasicIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(basic.Username) > 0 {
-		cipherData, err := encryption.Encrypt(basic.Username, key)
+		cipherData, err := repo.encrypt(basic.Username, key)
 		if err != nil {
 			return err
 		}
@@ -331,7 +334,7 @@ func (repo *BasicIntegrationRepository) EncryptBasicIntegrationData(
 	}
 
 	if len(basic.Password) > 0 {
-		cipherData, err := encryption.Encrypt(basic.Password, key)
+		cipherData, err := repo.encrypt(basic.Password, key)
 		if err != nil {
 			return err
 		}
@@ -349,7 +352,7 @@ func (repo *BasicIntegrationRepository) DecryptBasicIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(basic.Username) > 0 {
-		plaintext, err := encryption.Decrypt(basic.Username, key)
+		plaintext, err := repo.decrypt(basic.Username, key)
 		if err != nil {
 			return err
 		}
@@ -358,7 +361,7 @@ func (repo *BasicIntegrationRepository) DecryptBasicIntegrationData(
 	}
 
 	if len(basic.Password) > 0 {
-		plaintext, err := encryption.Decrypt(basic.Password, key)
+		plaintext, err := repo.decrypt(basic.Password, key)
 		if err != nil {
 			return err
 		}
@@ -373,6 +376,8 @@ func (repo *BasicIntegrationRepository) DecryptBasicIntegrationData(
 type OIDCIntegrationRepository struct {
 	db  *gorm.DB
 	key *[32]byte
+
+	fieldCipher
 }
 
 // NewOIDCIntegrationRepository returns a OIDCIntegrationRepository which uses
@@ -382,7 +387,7 @@ func NewOIDCIntegrationRepository(
 	db *gorm.DB,
 	key *[32]byte,
 ) repository.OIDCIntegrationRepository {
-	return &OIDCIntegrationRepository{db, key}
+	return &OIDCIntegrationRepository{db: db, key: key}
 }
 
 // CreateOIDCIntegration creates a new oidc auth mechanism
@@ -452,7 +457,7 @@ func (repo *OIDCIntegrationRepository) EncryptOIDCIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(oidc.IssuerURL) > 0 {
-		cipherData, err := encryption.Encrypt(oidc.IssuerURL, key)
+		cipherData, err := repo.encrypt(oidc.IssuerURL, key)
 		if err != nil {
 			return err
 		}
@@ -461,7 +466,7 @@ func (repo *OIDCIntegrationRepository) EncryptOIDCIntegrationData(
 	}
 
 	if len(oidc.ClientID) > 0 {
-		cipherData, err := encryption.Encrypt(oidc.ClientID, key)
+		cipherData, err := repo.encrypt(oidc.ClientID, key)
 		if err != nil {
 			return err
 		}
@@ -470,7 +475,7 @@ func (repo *OIDCIntegrationRepository) EncryptOIDCIntegrationData(
 	}
 
 	if len(oidc.ClientSecret) > 0 {
-		cipherData, err := encryption.Encrypt(oidc.ClientSecret, key)
+		cipherData, err := repo.encrypt(oidc.ClientSecret, key)
 		if err != nil {
 			return err
 		}
@@ -479,7 +484,7 @@ func (repo *OIDCIntegrationRepository) EncryptOIDCIntegrationData(
 	}
 
 	if len(oidc.CertificateAuthorityData) > 0 {
-		cipherData, err := encryption.Encrypt(oidc.CertificateAuthorityData, key)
+		cipherData, err := repo.encrypt(oidc.CertificateAuthorityData, key)
 		if err != nil {
 			return err
 		}
@@ -488,7 +493,7 @@ func (repo *OIDCIntegrationRepository) EncryptOIDCIntegrationData(
 	}
 
 	if len(oidc.IDToken) > 0 {
-		cipherData, err := encryption.Encrypt(oidc.IDToken, key)
+		cipherData, err := repo.encrypt(oidc.IDToken, key)
 		if err != nil {
 			return err
 		}
@@ -497,7 +502,7 @@ func (repo *OIDCIntegrationRepository) EncryptOIDCIntegrationData(
 	}
 
 	if len(oidc.RefreshToken) > 0 {
-		cipherData, err := encryption.Encrypt(oidc.RefreshToken, key)
+		cipherData, err := repo.encrypt(oidc.RefreshToken, key)
 		if err != nil {
 			return err
 		}
@@ -515,7 +520,7 @@ func (repo *OIDCIntegrationRepository) DecryptOIDCIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(oidc.IssuerURL) > 0 {
-		plaintext, err := encryption.Decrypt(oidc.IssuerURL, key)
+		plaintext, err := repo.decrypt(oidc.IssuerURL, key)
 		if err != nil {
 			return err
 		}
@@ -524,7 +529,7 @@ func (repo *OIDCIntegrationRepository) DecryptOIDCIntegrationData(
 	}
 
 	if len(oidc.ClientID) > 0 {
-		plaintext, err := encryption.Decrypt(oidc.ClientID, key)
+		plaintext, err := repo.decrypt(oidc.ClientID, key)
 		if err != nil {
 			return err
 		}
@@ -533,7 +538,7 @@ func (repo *OIDCIntegrationRepository) DecryptOIDCIntegrationData(
 	}
 
 	if len(oidc.ClientSecret) > 0 {
-		plaintext, err := encryption.Decrypt(oidc.ClientSecret, key)
+		plaintext, err := repo.decrypt(oidc.ClientSecret, key)
 		if err != nil {
 			return err
 		}
@@ -542,7 +547,7 @@ func (repo *OIDCIntegrationRepository) DecryptOIDCIntegrationData(
 	}
 
 	if len(oidc.CertificateAuthorityData) > 0 {
-		plaintext, err := encryption.Decrypt(oidc.CertificateAuthorityData, key)
+		plaintext, err := repo.decrypt(oidc.CertificateAuthorityData, key)
 		if err != nil {
 			return err
 		}
@@ -551,7 +556,7 @@ func (repo *OIDCIntegrationRepository) DecryptOIDCIntegrationData(
 	}
 
 	if len(oidc.IDToken) > 0 {
-		plaintext, err := encryption.Decrypt(oidc.IDToken, key)
+		plaintext, err := repo.decrypt(oidc.IDToken, key)
 		if err != nil {
 			return err
 		}
@@ -560,7 +565,7 @@ func (repo *OIDCIntegrationRepository) DecryptOIDCIntegrationData(
 	}
 
 	if len(oidc.RefreshToken) > 0 {
-		plaintext, err := encryption.Decrypt(oidc.RefreshToken, key)
+		plaintext, err := repo.decrypt(oidc.RefreshToken, key)
 		if err != nil {
 			return err
 		}
@@ -576,6 +581,8 @@ type OAuthIntegrationRepository struct {
 	db             *gorm.DB
 	key            *[32]byte
 	storageBackend credentials.CredentialStorage
+
+	fieldCipher
 }
 
 // NewOAuthIntegrationRepository returns a OAuthIntegrationRepository which uses
@@ -586,7 +593,7 @@ func NewOAuthIntegrationRepository(
 	key *[32]byte,
 	storageBackend credentials.CredentialStorage,
 ) repository.OAuthIntegrationRepository {
-	return &OAuthIntegrationRepository{db, key, storageBackend}
+	return &OAuthIntegrationRepository{db: db, key: key, storageBackend: storageBackend}
 }
 
 // CreateOAuthIntegration creates a new oauth auth mechanism
@@ -731,7 +738,7 @@ func (repo *OAuthIntegrationRepository) EncryptOAuthIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(oauth.ClientID) > 0 {
-		cipherData, err := encryption.Encrypt(oauth.ClientID, key)
+		cipherData, err := repo.encrypt(oauth.ClientID, key)
 		if err != nil {
 			return err
 		}
@@ -740,7 +747,7 @@ func (repo *OAuthIntegrationRepository) EncryptOAuthIntegrationData(
 	}
 
 	if len(oauth.AccessToken) > 0 {
-		cipherData, err := encryption.Encrypt(oauth.AccessToken, key)
+		cipherData, err := repo.encrypt(oauth.AccessToken, key)
 		if err != nil {
 			return err
 		}
@@ -749,7 +756,7 @@ func (repo *OAuthIntegrationRepository) EncryptOAuthIntegrationData(
 	}
 
 	if len(oauth.RefreshToken) > 0 {
-		cipherData, err := encryption.Encrypt(oauth.RefreshToken, key)
+		cipherData, err := repo.encrypt(oauth.RefreshToken, key)
 		if err != nil {
 			return err
 		}
@@ -767,7 +774,7 @@ func (repo *OAuthIntegrationRepository) DecryptOAuthIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(oauth.ClientID) > 0 {
-		plaintext, err := encryption.Decrypt(oauth.ClientID, key)
+		plaintext, err := repo.decrypt(oauth.ClientID, key)
 		if err != nil {
 			return err
 		}
@@ -776,7 +783,7 @@ func (repo *OAuthIntegrationRepository) DecryptOAuthIntegrationData(
 	}
 
 	if len(oauth.AccessToken) > 0 {
-		plaintext, err := encryption.Decrypt(oauth.AccessToken, key)
+		plaintext, err := repo.decrypt(oauth.AccessToken, key)
 		if err != nil {
 			return err
 		}
@@ -785,7 +792,7 @@ func (repo *OAuthIntegrationRepository) DecryptOAuthIntegrationData(
 	}
 
 	if len(oauth.RefreshToken) > 0 {
-		plaintext, err := encryption.Decrypt(oauth.RefreshToken, key)
+		plaintext, err := repo.decrypt(oauth.RefreshToken, key)
 		if err != nil {
 			return err
 		}
@@ -801,6 +808,8 @@ type GCPIntegrationRepository struct {
 	db             *gorm.DB
 	key            *[32]byte
 	storageBackend credentials.CredentialStorage
+
+	fieldCipher
 }
 
 // NewGCPIntegrationRepository returns a GCPIntegrationRepository which uses
@@ -811,7 +820,7 @@ func NewGCPIntegrationRepository(
 	key *[32]byte,
 	storageBackend credentials.CredentialStorage,
 ) repository.GCPIntegrationRepository {
-	return &GCPIntegrationRepository{db, key, storageBackend}
+	return &GCPIntegrationRepository{db: db, key: key, storageBackend: storageBackend}
 }
 
 // CreateGCPIntegration creates a new gcp auth mechanism
@@ -907,7 +916,7 @@ func (repo *GCPIntegrationRepository) EncryptGCPIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(gcp.GCPKeyData) > 0 {
-		cipherData, err := encryption.Encrypt(gcp.GCPKeyData, key)
+		cipherData, err := repo.encrypt(gcp.GCPKeyData, key)
 		if err != nil {
 			return err
 		}
@@ -925,7 +934,7 @@ func (repo *GCPIntegrationRepository) DecryptGCPIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(gcp.GCPKeyData) > 0 {
-		plaintext, err := encryption.Decrypt(gcp.GCPKeyData, key)
+		plaintext, err := repo.decrypt(gcp.GCPKeyData, key)
 		if err != nil {
 			return err
 		}
@@ -941,6 +950,8 @@ type AWSIntegrationRepository struct {
 	db             *gorm.DB
 	key            *[32]byte
 	storageBackend credentials.CredentialStorage
+
+	fieldCipher
 }
 
 // NewAWSIntegrationRepository returns a AWSIntegrationRepository which uses
@@ -951,7 +962,7 @@ func NewAWSIntegrationRepository(
 	key *[32]byte,
 	storageBackend credentials.CredentialStorage,
 ) repository.AWSIntegrationRepository {
-	return &AWSIntegrationRepository{db, key, storageBackend}
+	return &AWSIntegrationRepository{db: db, key: key, storageBackend: storageBackend}
 }
 
 // CreateAWSIntegration creates a new aws auth mechanism
@@ -1095,7 +1106,7 @@ func (repo *AWSIntegrationRepository) EncryptAWSIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(aws.AWSClusterID) > 0 {
-		cipherData, err := encryption.Encrypt(aws.AWSClusterID, key)
+		cipherData, err := repo.encrypt(aws.AWSClusterID, key)
 		if err != nil {
 			return err
 		}
@@ -1104,7 +1115,7 @@ func (repo *AWSIntegrationRepository) EncryptAWSIntegrationData(
 	}
 
 	if len(aws.AWSAccessKeyID) > 0 {
-		cipherData, err := encryption.Encrypt(aws.AWSAccessKeyID, key)
+		cipherData, err := repo.encrypt(aws.AWSAccessKeyID, key)
 		if err != nil {
 			return err
 		}
@@ -1113,7 +1124,7 @@ func (repo *AWSIntegrationRepository) EncryptAWSIntegrationData(
 	}
 
 	if len(aws.AWSSecretAccessKey) > 0 {
-		cipherData, err := encryption.Encrypt(aws.AWSSecretAccessKey, key)
+		cipherData, err := repo.encrypt(aws.AWSSecretAccessKey, key)
 		if err != nil {
 			return err
 		}
@@ -1122,7 +1133,7 @@ func (repo *AWSIntegrationRepository) EncryptAWSIntegrationData(
 	}
 
 	if len(aws.AWSSessionToken) > 0 {
-		cipherData, err := encryption.Encrypt(aws.AWSSessionToken, key)
+		cipherData, err := repo.encrypt(aws.AWSSessionToken, key)
 		if err != nil {
 			return err
 		}
@@ -1140,7 +1151,7 @@ func (repo *AWSIntegrationRepository) DecryptAWSIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(aws.AWSClusterID) > 0 {
-		plaintext, err := encryption.Decrypt(aws.AWSClusterID, key)
+		plaintext, err := repo.decrypt(aws.AWSClusterID, key)
 		if err != nil {
 			return err
 		}
@@ -1149,7 +1160,7 @@ func (repo *AWSIntegrationRepository) DecryptAWSIntegrationData(
 	}
 
 	if len(aws.AWSAccessKeyID) > 0 {
-		plaintext, err := encryption.Decrypt(aws.AWSAccessKeyID, key)
+		plaintext, err := repo.decrypt(aws.AWSAccessKeyID, key)
 		if err != nil {
 			return err
 		}
@@ -1158,7 +1169,7 @@ func (repo *AWSIntegrationRepository) DecryptAWSIntegrationData(
 	}
 
 	if len(aws.AWSSecretAccessKey) > 0 {
-		plaintext, err := encryption.Decrypt(aws.AWSSecretAccessKey, key)
+		plaintext, err := repo.decrypt(aws.AWSSecretAccessKey, key)
 		if err != nil {
 			return err
 		}
@@ -1167,7 +1178,7 @@ func (repo *AWSIntegrationRepository) DecryptAWSIntegrationData(
 	}
 
 	if len(aws.AWSSessionToken) > 0 {
-		plaintext, err := encryption.Decrypt(aws.AWSSessionToken, key)
+		plaintext, err := repo.decrypt(aws.AWSSessionToken, key)
 		if err != nil {
 			return err
 		}
@@ -1284,6 +1295,8 @@ type AzureIntegrationRepository struct {
 	db             *gorm.DB
 	key            *[32]byte
 	storageBackend credentials.CredentialStorage
+
+	fieldCipher
 }
 
 // NewAzureIntegrationRepository returns a AzureIntegrationRepository which uses
@@ -1294,7 +1307,7 @@ func NewAzureIntegrationRepository(
 	key *[32]byte,
 	storageBackend credentials.CredentialStorage,
 ) repository.AzureIntegrationRepository {
-	return &AzureIntegrationRepository{db, key, storageBackend}
+	return &AzureIntegrationRepository{db: db, key: key, storageBackend: storageBackend}
 }
 
 // CreateAzureIntegration creates a new Azure auth mechanism
@@ -1439,7 +1452,7 @@ func (repo *AzureIntegrationRepository) EncryptAzureIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(az.ServicePrincipalSecret) > 0 {
-		cipherData, err := encryption.Encrypt(az.ServicePrincipalSecret, key)
+		cipherData, err := repo.encrypt(az.ServicePrincipalSecret, key)
 		if err != nil {
 			return err
 		}
@@ -1448,7 +1461,7 @@ func (repo *AzureIntegrationRepository) EncryptAzureIntegrationData(
 	}
 
 	if len(az.ACRPassword1) > 0 {
-		cipherData, err := encryption.Encrypt(az.ACRPassword1, key)
+		cipherData, err := repo.encrypt(az.ACRPassword1, key)
 		if err != nil {
 			return err
 		}
@@ -1457,7 +1470,7 @@ func (repo *AzureIntegrationRepository) EncryptAzureIntegrationData(
 	}
 
 	if len(az.ACRPassword2) > 0 {
-		cipherData, err := encryption.Encrypt(az.ACRPassword2, key)
+		cipherData, err := repo.encrypt(az.ACRPassword2, key)
 		if err != nil {
 			return err
 		}
@@ -1466,7 +1479,7 @@ func (repo *AzureIntegrationRepository) EncryptAzureIntegrationData(
 	}
 
 	if len(az.AKSPassword) > 0 {
-		cipherData, err := encryption.Encrypt(az.AKSPassword, key)
+		cipherData, err := repo.encrypt(az.AKSPassword, key)
 		if err != nil {
 			return err
 		}
@@ -1484,7 +1497,7 @@ func (repo *AzureIntegrationRepository) DecryptAzureIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(az.ServicePrincipalSecret) > 0 {
-		plaintext, err := encryption.Decrypt(az.ServicePrincipalSecret, key)
+		plaintext, err := repo.decrypt(az.ServicePrincipalSecret, key)
 		if err != nil {
 			return err
 		}
@@ -1493,7 +1506,7 @@ func (repo *AzureIntegrationRepository) DecryptAzureIntegrationData(
 	}
 
 	if len(az.ACRPassword1) > 0 {
-		plaintext, err := encryption.Decrypt(az.ACRPassword1, key)
+		plaintext, err := repo.decrypt(az.ACRPassword1, key)
 		if err != nil {
 			return err
 		}
@@ -1502,7 +1515,7 @@ func (repo *AzureIntegrationRepository) DecryptAzureIntegrationData(
 	}
 
 	if len(az.ACRPassword2) > 0 {
-		plaintext, err := encryption.Decrypt(az.ACRPassword2, key)
+		plaintext, err := repo.decrypt(az.ACRPassword2, key)
 		if err != nil {
 			return err
 		}
@@ -1511,7 +1524,7 @@ func (repo *AzureIntegrationRepository) DecryptAzureIntegrationData(
 	}
 
 	if len(az.AKSPassword) > 0 {
-		plaintext, err := encryption.Decrypt(az.AKSPassword, key)
+		plaintext, err := repo.decrypt(az.AKSPassword, key)
 		if err != nil {
 			return err
 		}
@@ -1527,6 +1540,8 @@ type GitlabIntegrationRepository struct {
 	db             *gorm.DB
 	key            *[32]byte
 	storageBackend credentials.CredentialStorage
+
+	fieldCipher
 }
 
 // NewGitlabIntegrationRepository returns a GitlabIntegrationRepository which uses
@@ -1536,7 +1551,7 @@ func NewGitlabIntegrationRepository(
 	key *[32]byte,
 	storageBackend credentials.CredentialStorage,
 ) repository.GitlabIntegrationRepository {
-	return &GitlabIntegrationRepository{db, key, storageBackend}
+	return &GitlabIntegrationRepository{db: db, key: key, storageBackend: storageBackend}
 }
 
 // CreateIntegration adds a new GitlabIntegration row to the gitlab_integration table in the database
@@ -1636,7 +1651,7 @@ func (repo *GitlabIntegrationRepository) EncryptGitlabIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(gi.AppClientID) > 0 {
-		cipherData, err := encryption.Encrypt(gi.AppClientID, key)
+		cipherData, err := repo.encrypt(gi.AppClientID, key)
 		if err != nil {
 			return err
 		}
@@ -1645,7 +1660,7 @@ func (repo *GitlabIntegrationRepository) EncryptGitlabIntegrationData(
 	}
 
 	if len(gi.AppClientSecret) > 0 {
-		cipherData, err := encryption.Encrypt(gi.AppClientSecret, key)
+		cipherData, err := repo.encrypt(gi.AppClientSecret, key)
 		if err != nil {
 			return err
 		}
@@ -1663,7 +1678,7 @@ func (repo *GitlabIntegrationRepository) DecryptGitlabIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(gi.AppClientID) > 0 {
-		plaintext, err := encryption.Decrypt(gi.AppClientID, key)
+		plaintext, err := repo.decrypt(gi.AppClientID, key)
 		if err != nil {
 			return err
 		}
@@ -1672,7 +1687,7 @@ func (repo *GitlabIntegrationRepository) DecryptGitlabIntegrationData(
 	}
 
 	if len(gi.AppClientSecret) > 0 {
-		plaintext, err := encryption.Decrypt(gi.AppClientSecret, key)
+		plaintext, err := repo.decrypt(gi.AppClientSecret, key)
 		if err != nil {
 			return err
 		}
@@ -1688,6 +1703,8 @@ type GitlabAppOAuthIntegrationRepository struct {
 	db             *gorm.DB
 	key            *[32]byte
 	storageBackend credentials.CredentialStorage
+
+	fieldCipher
 }
 
 // NewGitlabAppOAuthIntegrationRepository returns a GitlabAppOAuthIntegrationRepository which uses
@@ -1697,7 +1714,7 @@ func NewGitlabAppOAuthIntegrationRepository(
 	key *[32]byte,
 	storageBackend credentials.CredentialStorage,
 ) repository.GitlabAppOAuthIntegrationRepository {
-	return &GitlabAppOAuthIntegrationRepository{db, key, storageBackend}
+	return &GitlabAppOAuthIntegrationRepository{db: db, key: key, storageBackend: storageBackend}
 }
 
 func (repo *GitlabAppOAuthIntegrationRepository) CreateGitlabAppOAuthIntegration(
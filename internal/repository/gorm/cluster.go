@@ -3,7 +3,6 @@ package gorm
 import (
 	"fmt"
 
-	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/features"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/repository"
@@ -16,13 +15,15 @@ import (
 type ClusterRepository struct {
 	db  *gorm.DB
 	key *[32]byte
+
+	fieldCipher
 }
 
 // NewClusterRepository returns a ClusterRepository which uses
 // gorm.DB for querying the database. It accepts an encryption key to encrypt
 // sensitive data
 func NewClusterRepository(db *gorm.DB, key *[32]byte) repository.ClusterRepository {
-	return &ClusterRepository{db, key}
+	return &ClusterRepository{db: db, key: key}
 }
 
 // CreateClusterCandidate creates a new cluster candidate
@@ -286,7 +287,7 @@ func (repo *ClusterRepository) UpdateClusterTokenCache(
 	tokenCache *ints.ClusterTokenCache,
 ) (*models.Cluster, error) {
 	if tok := tokenCache.Token; len(tok) > 0 {
-		cipherData, err := encryption.Encrypt(tok, repo.key)
+		cipherData, err := repo.encrypt(tok, repo.key)
 		if err != nil {
 			return nil, err
 		}
@@ -351,7 +352,7 @@ func (repo *ClusterRepository) EncryptClusterData(
 	key *[32]byte,
 ) error {
 	if len(cluster.CertificateAuthorityData) > 0 {
-		cipherData, err := encryption.Encrypt(cluster.CertificateAuthorityData, key)
+		cipherData, err := repo.encrypt(cluster.CertificateAuthorityData, key)
 		if err != nil {
 			return err
 		}
@@ -360,7 +361,7 @@ func (repo *ClusterRepository) EncryptClusterData(
 	}
 
 	if tok := cluster.TokenCache.Token; len(tok) > 0 {
-		cipherData, err := encryption.Encrypt(tok, key)
+		cipherData, err := repo.encrypt(tok, key)
 		if err != nil {
 			return err
 		}
@@ -378,7 +379,7 @@ func (repo *ClusterRepository) EncryptClusterCandidateData(
 	key *[32]byte,
 ) error {
 	if len(cc.AWSClusterIDGuess) > 0 {
-		cipherData, err := encryption.Encrypt(cc.AWSClusterIDGuess, key)
+		cipherData, err := repo.encrypt(cc.AWSClusterIDGuess, key)
 		if err != nil {
 			return err
 		}
@@ -387,7 +388,7 @@ func (repo *ClusterRepository) EncryptClusterCandidateData(
 	}
 
 	if len(cc.Kubeconfig) > 0 {
-		cipherData, err := encryption.Encrypt(cc.Kubeconfig, key)
+		cipherData, err := repo.encrypt(cc.Kubeconfig, key)
 		if err != nil {
 			return err
 		}
@@ -405,7 +406,7 @@ func (repo *ClusterRepository) DecryptClusterData(
 	key *[32]byte,
 ) error {
 	if len(cluster.CertificateAuthorityData) > 0 {
-		plaintext, err := encryption.Decrypt(cluster.CertificateAuthorityData, key)
+		plaintext, err := repo.decrypt(cluster.CertificateAuthorityData, key)
 		if err != nil {
 			return err
 		}
@@ -414,7 +415,7 @@ func (repo *ClusterRepository) DecryptClusterData(
 	}
 
 	if tok := cluster.TokenCache.Token; len(tok) > 0 {
-		plaintext, err := encryption.Decrypt(tok, key)
+		plaintext, err := repo.decrypt(tok, key)
 
 		// in the case that the token cache is down, set empty token
 		if err != nil {
@@ -434,7 +435,7 @@ func (repo *ClusterRepository) DecryptClusterCandidateData(
 	key *[32]byte,
 ) error {
 	if len(cc.AWSClusterIDGuess) > 0 {
-		plaintext, err := encryption.Decrypt(cc.AWSClusterIDGuess, key)
+		plaintext, err := repo.decrypt(cc.AWSClusterIDGuess, key)
 		if err != nil {
 			return err
 		}
@@ -443,7 +444,7 @@ func (repo *ClusterRepository) DecryptClusterCandidateData(
 	}
 
 	if len(cc.Kubeconfig) > 0 {
-		plaintext, err := encryption.Decrypt(cc.Kubeconfig, key)
+		plaintext, err := repo.decrypt(cc.Kubeconfig, key)
 		if err != nil {
 			return err
 		}
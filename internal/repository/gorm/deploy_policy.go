@@ -0,0 +1,72 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// DeployPolicyRepository uses gorm.DB for querying the database
+type DeployPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewDeployPolicyRepository returns a DeployPolicyRepository which uses
+// gorm.DB for querying the database
+func NewDeployPolicyRepository(db *gorm.DB) repository.DeployPolicyRepository {
+	return &DeployPolicyRepository{db}
+}
+
+// ReadDeploymentTargetPolicy reads the policy of a deployment target
+func (repo *DeployPolicyRepository) ReadDeploymentTargetPolicy(ctx context.Context, projectID uint, deploymentTargetID uuid.UUID) (*models.DeploymentTargetPolicy, error) {
+	policy := &models.DeploymentTargetPolicy{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND deployment_target_id = ?", projectID, deploymentTargetID).First(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdateDeploymentTargetPolicy creates or replaces the policy of a deployment target
+func (repo *DeployPolicyRepository) UpdateDeploymentTargetPolicy(ctx context.Context, policy *models.DeploymentTargetPolicy) (*models.DeploymentTargetPolicy, error) {
+	existing, err := repo.ReadDeploymentTargetPolicy(ctx, policy.ProjectID, policy.DeploymentTargetID)
+
+	switch {
+	case err == nil:
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if err := repo.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// CreateDeployPolicyOverride records a deploy which overrode the policy of its deployment target
+func (repo *DeployPolicyRepository) CreateDeployPolicyOverride(ctx context.Context, override *models.DeployPolicyOverride) (*models.DeployPolicyOverride, error) {
+	if err := repo.db.WithContext(ctx).Create(override).Error; err != nil {
+		return nil, err
+	}
+
+	return override, nil
+}
+
+// ListDeployPolicyOverrides lists the policy overrides of a deployment target, newest first
+func (repo *DeployPolicyRepository) ListDeployPolicyOverrides(ctx context.Context, projectID uint, deploymentTargetID uuid.UUID) ([]*models.DeployPolicyOverride, error) {
+	overrides := []*models.DeployPolicyOverride{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND deployment_target_id = ?", projectID, deploymentTargetID).Order("id DESC").Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
@@ -121,3 +121,21 @@ func (repo *DeploymentTargetRepository) CreateDeploymentTarget(deploymentTarget
 
 	return deploymentTarget, nil
 }
+
+// UpdateDeploymentTarget updates a deployment target
+func (repo *DeploymentTargetRepository) UpdateDeploymentTarget(deploymentTarget *models.DeploymentTarget) (*models.DeploymentTarget, error) {
+	if deploymentTarget == nil {
+		return nil, errors.New("deployment target is nil")
+	}
+	if deploymentTarget.ID == uuid.Nil {
+		return nil, errors.New("deployment target id is empty")
+	}
+
+	deploymentTarget.UpdatedAt = time.Now().UTC()
+
+	if err := repo.db.Save(deploymentTarget).Error; err != nil {
+		return nil, err
+	}
+
+	return deploymentTarget, nil
+}
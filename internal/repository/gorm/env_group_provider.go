@@ -0,0 +1,67 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// EnvGroupProviderRepository uses gorm.DB for querying the database
+type EnvGroupProviderRepository struct {
+	db *gorm.DB
+}
+
+// NewEnvGroupProviderRepository returns an EnvGroupProviderRepository which uses
+// gorm.DB for querying the database
+func NewEnvGroupProviderRepository(db *gorm.DB) repository.EnvGroupProviderRepository {
+	return &EnvGroupProviderRepository{db}
+}
+
+// ReadEnvGroupProvider reads the provider of an environment group
+func (repo *EnvGroupProviderRepository) ReadEnvGroupProvider(ctx context.Context, projectID, clusterID uint, envGroupName string) (*models.EnvGroupProvider, error) {
+	provider := &models.EnvGroupProvider{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND cluster_id = ? AND env_group_name = ?", projectID, clusterID, envGroupName).First(provider).Error; err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// ListEnvGroupProviders lists the providers of all projects, so that they can be refreshed
+func (repo *EnvGroupProviderRepository) ListEnvGroupProviders(ctx context.Context) ([]*models.EnvGroupProvider, error) {
+	providers := []*models.EnvGroupProvider{}
+
+	if err := repo.db.WithContext(ctx).Order("id").Find(&providers).Error; err != nil {
+		return nil, err
+	}
+
+	return providers, nil
+}
+
+// UpdateEnvGroupProvider creates or replaces the provider of an environment group
+func (repo *EnvGroupProviderRepository) UpdateEnvGroupProvider(ctx context.Context, provider *models.EnvGroupProvider) (*models.EnvGroupProvider, error) {
+	existing, err := repo.ReadEnvGroupProvider(ctx, provider.ProjectID, provider.ClusterID, provider.EnvGroupName)
+
+	switch {
+	case err == nil:
+		provider.ID = existing.ID
+		provider.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if err := repo.db.WithContext(ctx).Save(provider).Error; err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// DeleteEnvGroupProvider deletes the provider of an environment group
+func (repo *EnvGroupProviderRepository) DeleteEnvGroupProvider(ctx context.Context, provider *models.EnvGroupProvider) error {
+	return repo.db.WithContext(ctx).Delete(provider).Error
+}
@@ -1,7 +1,6 @@
 package gorm
 
 import (
-	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/models"
 	ints "github.com/karagatandev/porter/internal/models/integrations"
 	"github.com/karagatandev/porter/internal/repository"
@@ -12,12 +11,14 @@ import (
 type HelmRepoRepository struct {
 	db  *gorm.DB
 	key *[32]byte
+
+	fieldCipher
 }
 
 // NewHelmRepoRepository returns a HelmRepoRepository which uses
 // gorm.DB for querying the database
 func NewHelmRepoRepository(db *gorm.DB, key *[32]byte) repository.HelmRepoRepository {
-	return &HelmRepoRepository{db, key}
+	return &HelmRepoRepository{db: db, key: key}
 }
 
 // CreateHelmRepo creates a new helm repo
@@ -110,7 +111,7 @@ func (repo *HelmRepoRepository) UpdateHelmRepoTokenCache(
 	tokenCache *ints.HelmRepoTokenCache,
 ) (*models.HelmRepo, error) {
 	if tok := tokenCache.Token; len(tok) > 0 {
-		cipherData, err := encryption.Encrypt(tok, repo.key)
+		cipherData, err := repo.encrypt(tok, repo.key)
 		if err != nil {
 			return nil, err
 		}
@@ -163,7 +164,7 @@ func (repo *HelmRepoRepository) EncryptHelmRepoData(
 	key *[32]byte,
 ) error {
 	if tok := hr.TokenCache.Token; len(tok) > 0 {
-		cipherData, err := encryption.Encrypt(tok, key)
+		cipherData, err := repo.encrypt(tok, key)
 		if err != nil {
 			return err
 		}
@@ -181,7 +182,7 @@ func (repo *HelmRepoRepository) DecryptHelmRepoData(
 	key *[32]byte,
 ) error {
 	if tok := hr.TokenCache.Token; len(tok) > 0 {
-		plaintext, err := encryption.Decrypt(tok, key)
+		plaintext, err := repo.decrypt(tok, key)
 		if err != nil {
 			return err
 		}
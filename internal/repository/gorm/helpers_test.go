@@ -91,6 +91,8 @@ func setupTestEnv(tester *tester, t *testing.T) {
 		&ints.RegTokenCache{},
 		&ints.HelmRepoTokenCache{},
 		&ints.GithubAppInstallation{},
+		&models.AuditLog{},
+		&models.AuditLogSettings{},
 	)
 
 	if err != nil {
@@ -0,0 +1,102 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ImageScanRepository uses gorm.DB for querying the database
+type ImageScanRepository struct {
+	db *gorm.DB
+}
+
+// NewImageScanRepository returns a ImageScanRepository which uses
+// gorm.DB for querying the database
+func NewImageScanRepository(db *gorm.DB) repository.ImageScanRepository {
+	return &ImageScanRepository{db}
+}
+
+// CreateImageScan creates a new image scan
+func (repo *ImageScanRepository) CreateImageScan(ctx context.Context, scan *models.ImageScan) (*models.ImageScan, error) {
+	if err := repo.db.WithContext(ctx).Create(scan).Error; err != nil {
+		return nil, err
+	}
+
+	return scan, nil
+}
+
+// ReadImageScanByDigest reads the latest scan of an image by its digest
+func (repo *ImageScanRepository) ReadImageScanByDigest(ctx context.Context, projectID uint, digest string) (*models.ImageScan, error) {
+	scan := &models.ImageScan{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND digest = ?", projectID, digest).Order("id DESC").First(scan).Error; err != nil {
+		return nil, err
+	}
+
+	return scan, nil
+}
+
+// ReadImageScanByTag reads the latest scan of an image by the tag it was scanned by
+func (repo *ImageScanRepository) ReadImageScanByTag(ctx context.Context, projectID uint, repository, tag string) (*models.ImageScan, error) {
+	scan := &models.ImageScan{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND repository = ? AND tag = ?", projectID, repository, tag).Order("id DESC").First(scan).Error; err != nil {
+		return nil, err
+	}
+
+	return scan, nil
+}
+
+// ListImageScansByDigests lists the latest scan of each of the images with the given digests
+func (repo *ImageScanRepository) ListImageScansByDigests(ctx context.Context, projectID uint, digests []string) ([]*models.ImageScan, error) {
+	scans := []*models.ImageScan{}
+
+	if len(digests) == 0 {
+		return scans, nil
+	}
+
+	latest := repo.db.Model(&models.ImageScan{}).
+		Select("MAX(id)").
+		Where("project_id = ? AND digest IN ?", projectID, digests).
+		Group("digest")
+
+	if err := repo.db.WithContext(ctx).Where("id IN (?)", latest).Find(&scans).Error; err != nil {
+		return nil, err
+	}
+
+	return scans, nil
+}
+
+// ReadImageScanPolicy reads the image scan policy of a project
+func (repo *ImageScanRepository) ReadImageScanPolicy(ctx context.Context, projectID uint) (*models.ImageScanPolicy, error) {
+	policy := &models.ImageScanPolicy{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).First(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdateImageScanPolicy creates or replaces the image scan policy of a project
+func (repo *ImageScanRepository) UpdateImageScanPolicy(ctx context.Context, policy *models.ImageScanPolicy) (*models.ImageScanPolicy, error) {
+	existing, err := repo.ReadImageScanPolicy(ctx, policy.ProjectID)
+
+	switch {
+	case err == nil:
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if err := repo.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,80 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ImageSignatureRepository uses gorm.DB for querying the database
+type ImageSignatureRepository struct {
+	db *gorm.DB
+}
+
+// NewImageSignatureRepository returns a ImageSignatureRepository which uses
+// gorm.DB for querying the database
+func NewImageSignatureRepository(db *gorm.DB) repository.ImageSignatureRepository {
+	return &ImageSignatureRepository{db}
+}
+
+// ReadImageTrustPolicy reads the image trust policy of a project
+func (repo *ImageSignatureRepository) ReadImageTrustPolicy(ctx context.Context, projectID uint) (*models.ImageTrustPolicy, error) {
+	policy := &models.ImageTrustPolicy{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).First(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdateImageTrustPolicy creates or replaces the image trust policy of a project
+func (repo *ImageSignatureRepository) UpdateImageTrustPolicy(ctx context.Context, policy *models.ImageTrustPolicy) (*models.ImageTrustPolicy, error) {
+	existing, err := repo.ReadImageTrustPolicy(ctx, policy.ProjectID)
+
+	switch {
+	case err == nil:
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if err := repo.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// CreateImageVerification creates a new image verification
+func (repo *ImageSignatureRepository) CreateImageVerification(ctx context.Context, verification *models.ImageVerification) (*models.ImageVerification, error) {
+	if err := repo.db.WithContext(ctx).Create(verification).Error; err != nil {
+		return nil, err
+	}
+
+	return verification, nil
+}
+
+// UpdateImageVerification updates an image verification
+func (repo *ImageSignatureRepository) UpdateImageVerification(ctx context.Context, verification *models.ImageVerification) (*models.ImageVerification, error) {
+	if err := repo.db.WithContext(ctx).Save(verification).Error; err != nil {
+		return nil, err
+	}
+
+	return verification, nil
+}
+
+// ReadImageVerificationByAppRevisionID reads the latest verification of the image deployed by an app revision
+func (repo *ImageSignatureRepository) ReadImageVerificationByAppRevisionID(ctx context.Context, projectID uint, appRevisionID string) (*models.ImageVerification, error) {
+	verification := &models.ImageVerification{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND app_revision_id = ?", projectID, appRevisionID).Order("id DESC").First(verification).Error; err != nil {
+		return nil, err
+	}
+
+	return verification, nil
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/hex"
 	"fmt"
 
-	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/repository"
 	"gorm.io/gorm"
@@ -14,12 +13,14 @@ import (
 type InfraRepository struct {
 	db  *gorm.DB
 	key *[32]byte
+
+	fieldCipher
 }
 
 // NewInfraRepository returns a InfraRepository which uses
 // gorm.DB for querying the database
 func NewInfraRepository(db *gorm.DB, key *[32]byte) repository.InfraRepository {
-	return &InfraRepository{db, key}
+	return &InfraRepository{db: db, key: key}
 }
 
 // CreateInfra creates a new aws infra
@@ -252,7 +253,7 @@ func (repo *InfraRepository) EncryptInfraData(
 	key *[32]byte,
 ) error {
 	if len(infra.LastApplied) > 0 {
-		cipherData, err := encryption.Encrypt(infra.LastApplied, key)
+		cipherData, err := repo.encrypt(infra.LastApplied, key)
 		if err != nil {
 			return err
 		}
@@ -270,7 +271,7 @@ func (repo *InfraRepository) DecryptInfraData(
 	key *[32]byte,
 ) error {
 	if len(infra.LastApplied) > 0 {
-		plaintext, err := encryption.Decrypt(infra.LastApplied, key)
+		plaintext, err := repo.decrypt(infra.LastApplied, key)
 		if err != nil {
 			return err
 		}
@@ -288,7 +289,7 @@ func (repo *InfraRepository) EncryptOperationData(
 	key *[32]byte,
 ) error {
 	if len(operation.LastApplied) > 0 {
-		cipherData, err := encryption.Encrypt(operation.LastApplied, key)
+		cipherData, err := repo.encrypt(operation.LastApplied, key)
 		if err != nil {
 			return err
 		}
@@ -306,7 +307,7 @@ func (repo *InfraRepository) DecryptOperationData(
 	key *[32]byte,
 ) error {
 	if len(operation.LastApplied) > 0 {
-		plaintext, err := encryption.Decrypt(operation.LastApplied, key)
+		plaintext, err := repo.decrypt(operation.LastApplied, key)
 		if err != nil {
 			return err
 		}
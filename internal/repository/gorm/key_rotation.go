@@ -0,0 +1,79 @@
+package gorm
+
+import (
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// KeyRotationRepository uses gorm.DB for querying the database
+type KeyRotationRepository struct {
+	db *gorm.DB
+}
+
+// NewKeyRotationRepository returns a KeyRotationRepository which uses
+// gorm.DB for querying the database
+func NewKeyRotationRepository(db *gorm.DB) repository.KeyRotationRepository {
+	return &KeyRotationRepository{db}
+}
+
+// CreateKeyRotation creates a new key rotation
+func (repo *KeyRotationRepository) CreateKeyRotation(rotation *models.KeyRotation) (*models.KeyRotation, error) {
+	if err := repo.db.Create(rotation).Error; err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
+
+// ReadKeyRotation finds a key rotation by id, along with its checkpoints
+func (repo *KeyRotationRepository) ReadKeyRotation(id uint) (*models.KeyRotation, error) {
+	rotation := &models.KeyRotation{}
+
+	if err := repo.db.Preload("Checkpoints").Where("id = ?", id).First(&rotation).Error; err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
+
+// ReadKeyRotationByFingerprint finds a key rotation by the fingerprint of its key pair,
+// along with its checkpoints
+func (repo *KeyRotationRepository) ReadKeyRotationByFingerprint(fingerprint string) (*models.KeyRotation, error) {
+	rotation := &models.KeyRotation{}
+
+	if err := repo.db.Preload("Checkpoints").Where("key_fingerprint = ?", fingerprint).First(&rotation).Error; err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
+
+// ListKeyRotations lists all key rotations, most recent first
+func (repo *KeyRotationRepository) ListKeyRotations() ([]*models.KeyRotation, error) {
+	rotations := []*models.KeyRotation{}
+
+	if err := repo.db.Preload("Checkpoints").Order("id desc").Find(&rotations).Error; err != nil {
+		return nil, err
+	}
+
+	return rotations, nil
+}
+
+// UpdateKeyRotation modifies an existing key rotation in the database
+func (repo *KeyRotationRepository) UpdateKeyRotation(rotation *models.KeyRotation) (*models.KeyRotation, error) {
+	if err := repo.db.Omit("Checkpoints").Save(rotation).Error; err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
+
+// UpdateKeyRotationCheckpoint creates or modifies the checkpoint for a table
+func (repo *KeyRotationRepository) UpdateKeyRotationCheckpoint(checkpoint *models.KeyRotationCheckpoint) (*models.KeyRotationCheckpoint, error) {
+	if err := repo.db.Save(checkpoint).Error; err != nil {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
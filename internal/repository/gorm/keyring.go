@@ -0,0 +1,89 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karagatandev/porter/internal/encryption"
+)
+
+// fieldCipher encrypts the sensitive columns of a repository. When envelope encryption
+// is enabled, each value is encrypted with its own data key, and the version of the
+// key-encryption key that wrapped it is stored in the header of the value. Otherwise
+// values are encrypted with the static key passed by the caller.
+type fieldCipher struct {
+	keyring *encryption.Keyring
+}
+
+// keyringRepository is implemented by every repository which embeds a fieldCipher
+type keyringRepository interface {
+	setKeyring(keyring *encryption.Keyring)
+}
+
+func (c *fieldCipher) setKeyring(keyring *encryption.Keyring) {
+	c.keyring = keyring
+}
+
+func (c *fieldCipher) envelope() *encryption.Envelope {
+	if c.keyring == nil {
+		return nil
+	}
+
+	return c.keyring.Envelope
+}
+
+// encrypt encrypts plaintext with the envelope if envelope encryption is enabled, and
+// with key otherwise
+func (c *fieldCipher) encrypt(plaintext []byte, key *[32]byte) ([]byte, error) {
+	envelope := c.envelope()
+
+	if envelope == nil {
+		return encryption.Encrypt(plaintext, key)
+	}
+
+	return envelope.Encrypt(context.Background(), plaintext)
+}
+
+// decrypt decrypts an envelope ciphertext with the envelope, and any other ciphertext
+// with key, or with the previous key if a key rotation has not re-encrypted it yet.
+// Values written before envelope encryption was enabled can therefore still be read,
+// and are re-encrypted under the current key-encryption key on their next write.
+func (c *fieldCipher) decrypt(ciphertext []byte, key *[32]byte) ([]byte, error) {
+	envelope := c.envelope()
+
+	if !encryption.IsEnvelope(ciphertext) {
+		return c.decryptStatic(ciphertext, key)
+	}
+
+	if envelope == nil {
+		// a static-key ciphertext can start with the envelope magic bytes by chance
+		if plaintext, err := c.decryptStatic(ciphertext, key); err == nil {
+			return plaintext, nil
+		}
+
+		return nil, errors.New("value was encrypted with envelope encryption, which is not enabled")
+	}
+
+	plaintext, err := envelope.Decrypt(context.Background(), ciphertext)
+	if err != nil {
+		if legacy, legacyErr := c.decryptStatic(ciphertext, key); legacyErr == nil {
+			return legacy, nil
+		}
+
+		return nil, err
+	}
+
+	return plaintext, nil
+}
+
+func (c *fieldCipher) decryptStatic(ciphertext []byte, key *[32]byte) ([]byte, error) {
+	plaintext, err := encryption.Decrypt(ciphertext, key)
+
+	if err != nil && c.keyring != nil && c.keyring.PreviousKey != nil {
+		if previous, previousErr := encryption.Decrypt(ciphertext, c.keyring.PreviousKey); previousErr == nil {
+			return previous, nil
+		}
+	}
+
+	return plaintext, err
+}
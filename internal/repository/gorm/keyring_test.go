@@ -0,0 +1,152 @@
+package gorm_test
+
+import (
+	"testing"
+
+	"github.com/karagatandev/porter/internal/encryption"
+	"github.com/karagatandev/porter/internal/features"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository/gorm"
+)
+
+func TestEnvelopeEncryption(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_envelope_encryption.db",
+	}
+
+	setupTestEnv(tester, t)
+	initProject(tester, t)
+	initKubeIntegration(tester, t)
+	defer cleanup(tester, t)
+
+	// a cluster written before envelope encryption was enabled
+	legacy, err := tester.repo.Cluster().CreateCluster(&models.Cluster{
+		ProjectID:                tester.initProjects[0].ID,
+		Name:                     "legacy",
+		KubeIntegrationID:        tester.initKIs[0].ID,
+		CertificateAuthorityData: []byte("legacy-ca"),
+	}, &features.Client{})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	kms := encryption.NewLocalKeyManager("v1", encryption.NewEncryptionKey())
+	repo := gorm.NewRepositoryWithKeyring(tester.db, tester.key, nil, &encryption.Keyring{
+		Envelope: encryption.NewEnvelope(kms, tester.key),
+	})
+
+	legacy, err = repo.Cluster().ReadCluster(tester.initProjects[0].ID, legacy.ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if string(legacy.CertificateAuthorityData) != "legacy-ca" {
+		t.Errorf("expected legacy value to be decrypted with the static key, got %s", legacy.CertificateAuthorityData)
+	}
+
+	// writing the cluster re-encrypts it under the current key version
+	if _, err := repo.Cluster().UpdateCluster(legacy, &features.Client{}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	expectStoredKeyVersion(tester, t, legacy.ID, "v1")
+
+	if err := kms.AddKey("v2", encryption.NewEncryptionKey()); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	rotated, err := repo.Cluster().CreateCluster(&models.Cluster{
+		ProjectID:                tester.initProjects[0].ID,
+		Name:                     "rotated",
+		KubeIntegrationID:        tester.initKIs[0].ID,
+		CertificateAuthorityData: []byte("rotated-ca"),
+	}, &features.Client{})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	expectStoredKeyVersion(tester, t, rotated.ID, "v2")
+
+	// values wrapped by earlier key versions can still be read
+	for id, expected := range map[uint]string{legacy.ID: "legacy-ca", rotated.ID: "rotated-ca"} {
+		cluster, err := repo.Cluster().ReadCluster(tester.initProjects[0].ID, id)
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		if string(cluster.CertificateAuthorityData) != expected {
+			t.Errorf("expected %s, got %s", expected, cluster.CertificateAuthorityData)
+		}
+	}
+
+	// a repository without envelope encryption cannot read envelope values
+	if _, err := tester.repo.Cluster().ReadCluster(tester.initProjects[0].ID, rotated.ID); err == nil {
+		t.Errorf("expected reading an envelope value without envelope encryption to fail")
+	}
+}
+
+// expectStoredKeyVersion checks the key version stored in the encrypted certificate
+// authority data of a cluster
+func expectStoredKeyVersion(tester *tester, t *testing.T, clusterID uint, expected string) {
+	t.Helper()
+
+	stored := &models.Cluster{}
+
+	if err := tester.db.Where("id = ?", clusterID).First(stored).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !encryption.IsEnvelope(stored.CertificateAuthorityData) {
+		t.Fatalf("expected the stored value to be an envelope ciphertext")
+	}
+
+	keyVersion, err := encryption.EnvelopeKeyVersion(stored.CertificateAuthorityData)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if keyVersion != expected {
+		t.Errorf("expected key version %s, got %s", expected, keyVersion)
+	}
+}
+
+func TestPreviousKeyDecryption(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_previous_key_decryption.db",
+	}
+
+	setupTestEnv(tester, t)
+	initProject(tester, t)
+	initKubeIntegration(tester, t)
+	defer cleanup(tester, t)
+
+	// a cluster which a key rotation has not yet re-encrypted with the new key
+	cluster, err := tester.repo.Cluster().CreateCluster(&models.Cluster{
+		ProjectID:                tester.initProjects[0].ID,
+		Name:                     "not-rotated",
+		KubeIntegrationID:        tester.initKIs[0].ID,
+		CertificateAuthorityData: []byte("not-rotated-ca"),
+	}, &features.Client{})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	newKey := encryption.NewEncryptionKey()
+
+	if _, err := gorm.NewRepository(tester.db, newKey, nil).Cluster().ReadCluster(tester.initProjects[0].ID, cluster.ID); err == nil {
+		t.Fatalf("expected reading the cluster with only the new key to fail")
+	}
+
+	repo := gorm.NewRepositoryWithKeyring(tester.db, newKey, nil, &encryption.Keyring{
+		PreviousKey: tester.key,
+	})
+
+	cluster, err = repo.Cluster().ReadCluster(tester.initProjects[0].ID, cluster.ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if string(cluster.CertificateAuthorityData) != "not-rotated-ca" {
+		t.Errorf("expected the value to be decrypted with the previous key, got %s", cluster.CertificateAuthorityData)
+	}
+}
@@ -0,0 +1,69 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ManifestPatchRepository uses gorm.DB for querying the database
+type ManifestPatchRepository struct {
+	db *gorm.DB
+}
+
+// NewManifestPatchRepository returns a ManifestPatchRepository which uses
+// gorm.DB for querying the database
+func NewManifestPatchRepository(db *gorm.DB) repository.ManifestPatchRepository {
+	return &ManifestPatchRepository{db}
+}
+
+// CreateManifestPatch creates a new manifest patch
+func (repo *ManifestPatchRepository) CreateManifestPatch(ctx context.Context, patch *models.ManifestPatch) (*models.ManifestPatch, error) {
+	if err := repo.db.WithContext(ctx).Create(patch).Error; err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+// ReadManifestPatch reads a manifest patch of a project by its id
+func (repo *ManifestPatchRepository) ReadManifestPatch(ctx context.Context, projectID, patchID uint) (*models.ManifestPatch, error) {
+	patch := &models.ManifestPatch{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND id = ?", projectID, patchID).First(patch).Error; err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+// ListManifestPatchesByProjectID lists the patches of a project in the order they are applied
+func (repo *ManifestPatchRepository) ListManifestPatchesByProjectID(ctx context.Context, projectID uint) ([]*models.ManifestPatch, error) {
+	patches := []*models.ManifestPatch{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).Order("priority ASC, id ASC").Find(&patches).Error; err != nil {
+		return nil, err
+	}
+
+	return patches, nil
+}
+
+// UpdateManifestPatch updates a manifest patch
+func (repo *ManifestPatchRepository) UpdateManifestPatch(ctx context.Context, patch *models.ManifestPatch) (*models.ManifestPatch, error) {
+	if err := repo.db.WithContext(ctx).Save(patch).Error; err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+// DeleteManifestPatch deletes a manifest patch
+func (repo *ManifestPatchRepository) DeleteManifestPatch(ctx context.Context, patch *models.ManifestPatch) (*models.ManifestPatch, error) {
+	if err := repo.db.WithContext(ctx).Delete(patch).Error; err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
@@ -91,5 +91,21 @@ func AutoMigrate(db *gorm.DB, debug bool) error {
 		&models.AppEventWebhooks{},
 		&models.ClusterHealthReport{},
 		&models.Referral{},
+		&models.KeyRotation{},
+		&models.KeyRotationCheckpoint{},
+		&models.AuditLog{},
+		&models.AuditLogSettings{},
+		&models.OIDCTrustRule{},
+		&models.ManifestPatch{},
+		&models.ImageScan{},
+		&models.ImageScanPolicy{},
+		&models.ImageTrustPolicy{},
+		&models.ImageVerification{},
+		&models.RegistryRetentionPolicy{},
+		&models.RegistryGCRun{},
+		&models.EnvGroupProvider{},
+		&models.DeploymentTargetPolicy{},
+		&models.DeployPolicyOverride{},
+		&models.AppRevisionApproval{},
 	)
 }
@@ -3,7 +3,6 @@ package gorm
 import (
 	"context"
 
-	"github.com/karagatandev/porter/internal/encryption"
 	ints "github.com/karagatandev/porter/internal/models/integrations"
 	"github.com/karagatandev/porter/internal/repository"
 	"github.com/karagatandev/porter/internal/telemetry"
@@ -14,11 +13,13 @@ import (
 type NeonIntegrationRepository struct {
 	db  *gorm.DB
 	key *[32]byte
+
+	fieldCipher
 }
 
 // NewNeonIntegrationRepository returns a NeonIntegrationRepository
 func NewNeonIntegrationRepository(db *gorm.DB, key *[32]byte) repository.NeonIntegrationRepository {
-	return &NeonIntegrationRepository{db, key}
+	return &NeonIntegrationRepository{db: db, key: key}
 }
 
 // Insert creates a new neon integration
@@ -76,7 +77,7 @@ func (repo *NeonIntegrationRepository) EncryptNeonIntegration(
 	encrypted := neonInt
 
 	if len(encrypted.ClientID) > 0 {
-		cipherData, err := encryption.Encrypt(encrypted.ClientID, key)
+		cipherData, err := repo.encrypt(encrypted.ClientID, key)
 		if err != nil {
 			return encrypted, err
 		}
@@ -85,7 +86,7 @@ func (repo *NeonIntegrationRepository) EncryptNeonIntegration(
 	}
 
 	if len(encrypted.AccessToken) > 0 {
-		cipherData, err := encryption.Encrypt(encrypted.AccessToken, key)
+		cipherData, err := repo.encrypt(encrypted.AccessToken, key)
 		if err != nil {
 			return encrypted, err
 		}
@@ -94,7 +95,7 @@ func (repo *NeonIntegrationRepository) EncryptNeonIntegration(
 	}
 
 	if len(encrypted.RefreshToken) > 0 {
-		cipherData, err := encryption.Encrypt(encrypted.RefreshToken, key)
+		cipherData, err := repo.encrypt(encrypted.RefreshToken, key)
 		if err != nil {
 			return encrypted, err
 		}
@@ -114,7 +115,7 @@ func (repo *NeonIntegrationRepository) DecryptNeonIntegration(
 	decrypted := neonInt
 
 	if len(decrypted.ClientID) > 0 {
-		plaintext, err := encryption.Decrypt(decrypted.ClientID, key)
+		plaintext, err := repo.decrypt(decrypted.ClientID, key)
 		if err != nil {
 			return decrypted, err
 		}
@@ -123,7 +124,7 @@ func (repo *NeonIntegrationRepository) DecryptNeonIntegration(
 	}
 
 	if len(decrypted.AccessToken) > 0 {
-		plaintext, err := encryption.Decrypt(decrypted.AccessToken, key)
+		plaintext, err := repo.decrypt(decrypted.AccessToken, key)
 		if err != nil {
 			return decrypted, err
 		}
@@ -132,7 +133,7 @@ func (repo *NeonIntegrationRepository) DecryptNeonIntegration(
 	}
 
 	if len(decrypted.RefreshToken) > 0 {
-		plaintext, err := encryption.Decrypt(decrypted.RefreshToken, key)
+		plaintext, err := repo.decrypt(decrypted.RefreshToken, key)
 		if err != nil {
 			return decrypted, err
 		}
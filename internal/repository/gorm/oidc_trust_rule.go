@@ -0,0 +1,84 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// OIDCTrustRuleRepository uses gorm.DB for querying the database
+type OIDCTrustRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewOIDCTrustRuleRepository returns a OIDCTrustRuleRepository which uses
+// gorm.DB for querying the database
+func NewOIDCTrustRuleRepository(db *gorm.DB) repository.OIDCTrustRuleRepository {
+	return &OIDCTrustRuleRepository{db}
+}
+
+// CreateOIDCTrustRule creates a new trust rule
+func (repo *OIDCTrustRuleRepository) CreateOIDCTrustRule(ctx context.Context, rule *models.OIDCTrustRule) (*models.OIDCTrustRule, error) {
+	if err := repo.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// ReadOIDCTrustRule reads a trust rule of a project by its id
+func (repo *OIDCTrustRuleRepository) ReadOIDCTrustRule(ctx context.Context, projectID, ruleID uint) (*models.OIDCTrustRule, error) {
+	rule := &models.OIDCTrustRule{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND id = ?", projectID, ruleID).First(rule).Error; err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// ListOIDCTrustRulesByProjectID lists the trust rules of a project
+func (repo *OIDCTrustRuleRepository) ListOIDCTrustRulesByProjectID(ctx context.Context, projectID uint) ([]*models.OIDCTrustRule, error) {
+	rules := []*models.OIDCTrustRule{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// ListOIDCTrustRulesByIssuer returns the trust rules of a project which trust an issuer
+func (repo *OIDCTrustRuleRepository) ListOIDCTrustRulesByIssuer(ctx context.Context, projectID uint, issuer string) ([]*models.OIDCTrustRule, error) {
+	rules := []*models.OIDCTrustRule{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND issuer = ?", projectID, issuer).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// DeleteOIDCTrustRule deletes a trust rule
+func (repo *OIDCTrustRuleRepository) DeleteOIDCTrustRule(ctx context.Context, rule *models.OIDCTrustRule) (*models.OIDCTrustRule, error) {
+	if err := repo.db.WithContext(ctx).Delete(rule).Error; err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteExpiredOIDCTokens permanently deletes the api tokens exchanged through trust rules,
+// including deleted ones, which expired before the given time
+func (repo *OIDCTrustRuleRepository) DeleteExpiredOIDCTokens(ctx context.Context, before time.Time) (int64, error) {
+	policyUIDs := repo.db.Unscoped().Model(&models.OIDCTrustRule{}).Select("policy_uid")
+
+	res := repo.db.WithContext(ctx).Unscoped().
+		Where("expiry < ? AND policy_uid IN (?)", before, policyUIDs).
+		Delete(&models.APIToken{})
+
+	return res.RowsAffected, res.Error
+}
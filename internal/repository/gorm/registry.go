@@ -1,7 +1,6 @@
 package gorm
 
 import (
-	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/models"
 	ints "github.com/karagatandev/porter/internal/models/integrations"
 	"github.com/karagatandev/porter/internal/repository"
@@ -12,12 +11,14 @@ import (
 type RegistryRepository struct {
 	db  *gorm.DB
 	key *[32]byte
+
+	fieldCipher
 }
 
 // NewRegistryRepository returns a RegistryRepository which uses
 // gorm.DB for querying the database
 func NewRegistryRepository(db *gorm.DB, key *[32]byte) repository.RegistryRepository {
-	return &RegistryRepository{db, key}
+	return &RegistryRepository{db: db, key: key}
 }
 
 // CreateRegistry creates a new registry
@@ -122,7 +123,7 @@ func (repo *RegistryRepository) UpdateRegistryTokenCache(
 	tokenCache *ints.RegTokenCache,
 ) (*models.Registry, error) {
 	if tok := tokenCache.Token; len(tok) > 0 {
-		cipherData, err := encryption.Encrypt(tok, repo.key)
+		cipherData, err := repo.encrypt(tok, repo.key)
 		if err != nil {
 			return nil, err
 		}
@@ -175,7 +176,7 @@ func (repo *RegistryRepository) EncryptRegistryData(
 	key *[32]byte,
 ) error {
 	if tok := registry.TokenCache.Token; len(tok) > 0 {
-		cipherData, err := encryption.Encrypt(tok, key)
+		cipherData, err := repo.encrypt(tok, key)
 		if err != nil {
 			return err
 		}
@@ -193,7 +194,7 @@ func (repo *RegistryRepository) DecryptRegistryData(
 	key *[32]byte,
 ) error {
 	if tok := registry.TokenCache.Token; len(tok) > 0 {
-		plaintext, err := encryption.Decrypt(tok, key)
+		plaintext, err := repo.decrypt(tok, key)
 		if err != nil {
 			return err
 		}
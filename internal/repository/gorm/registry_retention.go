@@ -0,0 +1,142 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// RegistryRetentionRepository uses gorm.DB for querying the database
+type RegistryRetentionRepository struct {
+	db *gorm.DB
+}
+
+// NewRegistryRetentionRepository returns a RegistryRetentionRepository which uses
+// gorm.DB for querying the database
+func NewRegistryRetentionRepository(db *gorm.DB) repository.RegistryRetentionRepository {
+	return &RegistryRetentionRepository{db}
+}
+
+// ListRegistryRetentionPolicies lists the retention policies of a registry's repositories
+func (repo *RegistryRetentionRepository) ListRegistryRetentionPolicies(ctx context.Context, projectID, registryID uint) ([]*models.RegistryRetentionPolicy, error) {
+	policies := []*models.RegistryRetentionPolicy{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND registry_id = ?", projectID, registryID).Order("repository").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// ReadRegistryRetentionPolicy reads the retention policy of a repository
+func (repo *RegistryRetentionRepository) ReadRegistryRetentionPolicy(ctx context.Context, projectID, registryID uint, repository string) (*models.RegistryRetentionPolicy, error) {
+	policy := &models.RegistryRetentionPolicy{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND registry_id = ? AND repository = ?", projectID, registryID, repository).First(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdateRegistryRetentionPolicy creates or replaces the retention policy of a repository
+func (repo *RegistryRetentionRepository) UpdateRegistryRetentionPolicy(ctx context.Context, policy *models.RegistryRetentionPolicy) (*models.RegistryRetentionPolicy, error) {
+	existing, err := repo.ReadRegistryRetentionPolicy(ctx, policy.ProjectID, policy.RegistryID, policy.Repository)
+
+	switch {
+	case err == nil:
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if err := repo.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// DeleteRegistryRetentionPolicy deletes the retention policy of a repository. The policy is deleted
+// permanently so that a policy for the same repository can be created again.
+func (repo *RegistryRetentionRepository) DeleteRegistryRetentionPolicy(ctx context.Context, policy *models.RegistryRetentionPolicy) error {
+	return repo.db.WithContext(ctx).Unscoped().Delete(policy).Error
+}
+
+// CreateRegistryGCRun creates a new garbage collection run
+func (repo *RegistryRetentionRepository) CreateRegistryGCRun(ctx context.Context, run *models.RegistryGCRun) (*models.RegistryGCRun, error) {
+	if err := repo.db.WithContext(ctx).Create(run).Error; err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// ReadRegistryGCRun reads a garbage collection run of a registry
+func (repo *RegistryRetentionRepository) ReadRegistryGCRun(ctx context.Context, projectID, registryID, id uint) (*models.RegistryGCRun, error) {
+	run := &models.RegistryGCRun{}
+
+	if err := repo.db.WithContext(ctx).Where("project_id = ? AND registry_id = ? AND id = ?", projectID, registryID, id).First(run).Error; err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// ListRegistryGCRuns lists the garbage collection runs of a registry, most recent first
+func (repo *RegistryRetentionRepository) ListRegistryGCRuns(ctx context.Context, projectID, registryID uint, repository string) ([]*models.RegistryGCRun, error) {
+	runs := []*models.RegistryGCRun{}
+
+	query := repo.db.WithContext(ctx).Where("project_id = ? AND registry_id = ?", projectID, registryID)
+
+	if repository != "" {
+		query = query.Where("repository = ?", repository)
+	}
+
+	if err := query.Order("id DESC").Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// ListRegistryGCRunsByStatus lists the garbage collection runs of all projects with a status, oldest first
+func (repo *RegistryRetentionRepository) ListRegistryGCRunsByStatus(ctx context.Context, status types.RegistryGCRunStatus) ([]*models.RegistryGCRun, error) {
+	runs := []*models.RegistryGCRun{}
+
+	if err := repo.db.WithContext(ctx).Where("status = ?", status).Order("id").Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// UpdateRegistryGCRun updates a garbage collection run
+func (repo *RegistryRetentionRepository) UpdateRegistryGCRun(ctx context.Context, run *models.RegistryGCRun) (*models.RegistryGCRun, error) {
+	if err := repo.db.WithContext(ctx).Save(run).Error; err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// UpdateRegistryGCRunStatus changes the status of a run if it has the expected status, and reports whether it did
+func (repo *RegistryRetentionRepository) UpdateRegistryGCRunStatus(ctx context.Context, run *models.RegistryGCRun, from, to types.RegistryGCRunStatus) (bool, error) {
+	res := repo.db.WithContext(ctx).Model(&models.RegistryGCRun{}).Where("id = ? AND status = ?", run.ID, from).Update("status", to)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	run.Status = to
+
+	return true, nil
+}
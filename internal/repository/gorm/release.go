@@ -49,6 +49,17 @@ func (repo *ReleaseRepository) ListReleasesByImageRepoURI(clusterID uint, imageR
 	return releases, nil
 }
 
+// ListReleasesByProjectID lists the releases of a project in every cluster
+func (repo *ReleaseRepository) ListReleasesByProjectID(projectID uint) ([]*models.Release, error) {
+	releases := make([]*models.Release, 0)
+
+	if err := repo.db.Preload("GitActionConfig").Where("project_id = ?", projectID).Find(&releases).Error; err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
 // ReadReleaseByWebhookToken finds a single release based on their unique webhook token.
 func (repo *ReleaseRepository) ReadReleaseByWebhookToken(token string) (*models.Release, error) {
 	release := &models.Release{}
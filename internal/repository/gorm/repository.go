@@ -1,6 +1,7 @@
 package gorm
 
 import (
+	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/repository"
 	"github.com/karagatandev/porter/internal/repository/credentials"
 	"gorm.io/gorm"
@@ -65,6 +66,16 @@ type GormRepository struct {
 	appInstance               repository.AppInstanceRepository
 	ipam                      repository.IpamRepository
 	referral                  repository.ReferralRepository
+	keyRotation               repository.KeyRotationRepository
+	auditLog                  repository.AuditLogRepository
+	oidcTrustRule             repository.OIDCTrustRuleRepository
+	manifestPatch             repository.ManifestPatchRepository
+	imageScan                 repository.ImageScanRepository
+	imageSignature            repository.ImageSignatureRepository
+	registryRetention         repository.RegistryRetentionRepository
+	envGroupProvider          repository.EnvGroupProviderRepository
+	deployPolicy              repository.DeployPolicyRepository
+	appRevisionApproval       repository.AppRevisionApprovalRepository
 }
 
 func (t *GormRepository) User() repository.UserRepository {
@@ -311,6 +322,56 @@ func (t *GormRepository) Referral() repository.ReferralRepository {
 	return t.referral
 }
 
+// KeyRotation returns the KeyRotationRepository interface implemented by gorm
+func (t *GormRepository) KeyRotation() repository.KeyRotationRepository {
+	return t.keyRotation
+}
+
+// AuditLog returns the AuditLogRepository interface implemented by gorm
+func (t *GormRepository) AuditLog() repository.AuditLogRepository {
+	return t.auditLog
+}
+
+// OIDCTrustRule returns the OIDCTrustRuleRepository interface implemented by gorm
+func (t *GormRepository) OIDCTrustRule() repository.OIDCTrustRuleRepository {
+	return t.oidcTrustRule
+}
+
+// ManifestPatch returns the ManifestPatchRepository interface implemented by gorm
+func (t *GormRepository) ManifestPatch() repository.ManifestPatchRepository {
+	return t.manifestPatch
+}
+
+// ImageScan returns the ImageScanRepository interface implemented by gorm
+func (t *GormRepository) ImageScan() repository.ImageScanRepository {
+	return t.imageScan
+}
+
+// ImageSignature returns the ImageSignatureRepository interface implemented by gorm
+func (t *GormRepository) ImageSignature() repository.ImageSignatureRepository {
+	return t.imageSignature
+}
+
+// RegistryRetention returns the RegistryRetentionRepository interface implemented by gorm
+func (t *GormRepository) RegistryRetention() repository.RegistryRetentionRepository {
+	return t.registryRetention
+}
+
+// EnvGroupProvider returns the EnvGroupProviderRepository interface implemented by gorm
+func (t *GormRepository) EnvGroupProvider() repository.EnvGroupProviderRepository {
+	return t.envGroupProvider
+}
+
+// DeployPolicy returns the DeployPolicyRepository interface implemented by gorm
+func (t *GormRepository) DeployPolicy() repository.DeployPolicyRepository {
+	return t.deployPolicy
+}
+
+// AppRevisionApproval returns the AppRevisionApprovalRepository interface implemented by gorm
+func (t *GormRepository) AppRevisionApproval() repository.AppRevisionApprovalRepository {
+	return t.appRevisionApproval
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.CredentialStorage) repository.Repository {
@@ -373,5 +434,52 @@ func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.Creden
 		ipam:                      NewIpamRepository(db),
 		appEventWebhook:           NewAppEventWebhookRepository(db),
 		referral:                  NewReferralRepository(db),
+		keyRotation:               NewKeyRotationRepository(db),
+		auditLog:                  NewAuditLogRepository(db),
+		oidcTrustRule:             NewOIDCTrustRuleRepository(db),
+		manifestPatch:             NewManifestPatchRepository(db),
+		imageScan:                 NewImageScanRepository(db),
+		imageSignature:            NewImageSignatureRepository(db),
+		registryRetention:         NewRegistryRetentionRepository(db),
+		envGroupProvider:          NewEnvGroupProviderRepository(db),
+		deployPolicy:              NewDeployPolicyRepository(db),
+		appRevisionApproval:       NewAppRevisionApprovalRepository(db, key),
+	}
+}
+
+// NewRepositoryWithKeyring returns a Repository which encrypts sensitive values with
+// envelope encryption if the keyring has an envelope, and decrypts values that are
+// still encrypted with the previous key of the keyring. Values which were encrypted
+// with key before envelope encryption was enabled are still decrypted with it.
+func NewRepositoryWithKeyring(
+	db *gorm.DB,
+	key *[32]byte,
+	storageBackend credentials.CredentialStorage,
+	keyring *encryption.Keyring,
+) repository.Repository {
+	res := NewRepository(db, key, storageBackend).(*GormRepository)
+
+	for _, repo := range []interface{}{
+		res.cluster,
+		res.helmRepo,
+		res.registry,
+		res.infra,
+		res.kubeIntegration,
+		res.basicIntegration,
+		res.oidcIntegration,
+		res.oauthIntegration,
+		res.gcpIntegration,
+		res.awsIntegration,
+		res.azIntegration,
+		res.slackIntegration,
+		res.gitlabIntegration,
+		res.gitlabAppOAuthIntegration,
+		res.upstashIntegration,
+		res.neonIntegration,
+		res.appRevisionApproval,
+	} {
+		repo.(keyringRepository).setKeyring(keyring)
 	}
+
+	return res
 }
@@ -1,7 +1,6 @@
 package gorm
 
 import (
-	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/repository"
 	"gorm.io/gorm"
 
@@ -12,6 +11,8 @@ import (
 type SlackIntegrationRepository struct {
 	db  *gorm.DB
 	key *[32]byte
+
+	fieldCipher
 }
 
 // NewSlackIntegrationRepository returns a SlackIntegrationRepository which uses
@@ -21,7 +22,7 @@ func NewSlackIntegrationRepository(
 	db *gorm.DB,
 	key *[32]byte,
 ) repository.SlackIntegrationRepository {
-	return &SlackIntegrationRepository{db, key}
+	return &SlackIntegrationRepository{db: db, key: key}
 }
 
 // CreateSlackIntegration creates a new kube auth mechanism
@@ -76,7 +77,7 @@ func (repo *SlackIntegrationRepository) EncryptSlackIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(slackInt.ClientID) > 0 {
-		cipherData, err := encryption.Encrypt(slackInt.ClientID, key)
+		cipherData, err := repo.encrypt(slackInt.ClientID, key)
 		if err != nil {
 			return err
 		}
@@ -85,7 +86,7 @@ func (repo *SlackIntegrationRepository) EncryptSlackIntegrationData(
 	}
 
 	if len(slackInt.AccessToken) > 0 {
-		cipherData, err := encryption.Encrypt(slackInt.AccessToken, key)
+		cipherData, err := repo.encrypt(slackInt.AccessToken, key)
 		if err != nil {
 			return err
 		}
@@ -94,7 +95,7 @@ func (repo *SlackIntegrationRepository) EncryptSlackIntegrationData(
 	}
 
 	if len(slackInt.RefreshToken) > 0 {
-		cipherData, err := encryption.Encrypt(slackInt.RefreshToken, key)
+		cipherData, err := repo.encrypt(slackInt.RefreshToken, key)
 		if err != nil {
 			return err
 		}
@@ -103,7 +104,7 @@ func (repo *SlackIntegrationRepository) EncryptSlackIntegrationData(
 	}
 
 	if len(slackInt.Webhook) > 0 {
-		cipherData, err := encryption.Encrypt(slackInt.Webhook, key)
+		cipherData, err := repo.encrypt(slackInt.Webhook, key)
 		if err != nil {
 			return err
 		}
@@ -121,7 +122,7 @@ func (repo *SlackIntegrationRepository) DecryptSlackIntegrationData(
 	key *[32]byte,
 ) error {
 	if len(slackInt.ClientID) > 0 {
-		plaintext, err := encryption.Decrypt(slackInt.ClientID, key)
+		plaintext, err := repo.decrypt(slackInt.ClientID, key)
 		if err != nil {
 			return err
 		}
@@ -130,7 +131,7 @@ func (repo *SlackIntegrationRepository) DecryptSlackIntegrationData(
 	}
 
 	if len(slackInt.AccessToken) > 0 {
-		plaintext, err := encryption.Decrypt(slackInt.AccessToken, key)
+		plaintext, err := repo.decrypt(slackInt.AccessToken, key)
 		if err != nil {
 			return err
 		}
@@ -139,7 +140,7 @@ func (repo *SlackIntegrationRepository) DecryptSlackIntegrationData(
 	}
 
 	if len(slackInt.RefreshToken) > 0 {
-		plaintext, err := encryption.Decrypt(slackInt.RefreshToken, key)
+		plaintext, err := repo.decrypt(slackInt.RefreshToken, key)
 		if err != nil {
 			return err
 		}
@@ -148,7 +149,7 @@ func (repo *SlackIntegrationRepository) DecryptSlackIntegrationData(
 	}
 
 	if len(slackInt.Webhook) > 0 {
-		plaintext, err := encryption.Decrypt(slackInt.Webhook, key)
+		plaintext, err := repo.decrypt(slackInt.Webhook, key)
 		if err != nil {
 			return err
 		}
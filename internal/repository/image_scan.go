@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// ImageScanRepository represents the set of queries on the ImageScan and ImageScanPolicy models
+type ImageScanRepository interface {
+	CreateImageScan(ctx context.Context, scan *models.ImageScan) (*models.ImageScan, error)
+	// ReadImageScanByDigest reads the latest scan of an image by its digest
+	ReadImageScanByDigest(ctx context.Context, projectID uint, digest string) (*models.ImageScan, error)
+	// ReadImageScanByTag reads the latest scan of an image by the tag it was scanned by
+	ReadImageScanByTag(ctx context.Context, projectID uint, repository, tag string) (*models.ImageScan, error)
+	// ListImageScansByDigests lists the latest scan of each of the images with the given digests
+	ListImageScansByDigests(ctx context.Context, projectID uint, digests []string) ([]*models.ImageScan, error)
+
+	// ReadImageScanPolicy reads the image scan policy of a project
+	ReadImageScanPolicy(ctx context.Context, projectID uint) (*models.ImageScanPolicy, error)
+	// UpdateImageScanPolicy creates or replaces the image scan policy of a project
+	UpdateImageScanPolicy(ctx context.Context, policy *models.ImageScanPolicy) (*models.ImageScanPolicy, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// ImageSignatureRepository represents the set of queries on the ImageTrustPolicy and ImageVerification models
+type ImageSignatureRepository interface {
+	// ReadImageTrustPolicy reads the image trust policy of a project
+	ReadImageTrustPolicy(ctx context.Context, projectID uint) (*models.ImageTrustPolicy, error)
+	// UpdateImageTrustPolicy creates or replaces the image trust policy of a project
+	UpdateImageTrustPolicy(ctx context.Context, policy *models.ImageTrustPolicy) (*models.ImageTrustPolicy, error)
+
+	CreateImageVerification(ctx context.Context, verification *models.ImageVerification) (*models.ImageVerification, error)
+	UpdateImageVerification(ctx context.Context, verification *models.ImageVerification) (*models.ImageVerification, error)
+	// ReadImageVerificationByAppRevisionID reads the latest verification of the image deployed by an app revision
+	ReadImageVerificationByAppRevisionID(ctx context.Context, projectID uint, appRevisionID string) (*models.ImageVerification, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// KeyRotationRepository represents the set of queries on the KeyRotation model
+type KeyRotationRepository interface {
+	CreateKeyRotation(rotation *models.KeyRotation) (*models.KeyRotation, error)
+	ReadKeyRotation(id uint) (*models.KeyRotation, error)
+	ReadKeyRotationByFingerprint(fingerprint string) (*models.KeyRotation, error)
+	ListKeyRotations() ([]*models.KeyRotation, error)
+	UpdateKeyRotation(rotation *models.KeyRotation) (*models.KeyRotation, error)
+	UpdateKeyRotationCheckpoint(checkpoint *models.KeyRotationCheckpoint) (*models.KeyRotationCheckpoint, error)
+}
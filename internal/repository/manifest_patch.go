@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// ManifestPatchRepository represents the set of queries on the ManifestPatch model
+type ManifestPatchRepository interface {
+	CreateManifestPatch(ctx context.Context, patch *models.ManifestPatch) (*models.ManifestPatch, error)
+	ReadManifestPatch(ctx context.Context, projectID, patchID uint) (*models.ManifestPatch, error)
+	// ListManifestPatchesByProjectID lists the patches of a project in the order they are applied
+	ListManifestPatchesByProjectID(ctx context.Context, projectID uint) ([]*models.ManifestPatch, error)
+	UpdateManifestPatch(ctx context.Context, patch *models.ManifestPatch) (*models.ManifestPatch, error)
+	DeleteManifestPatch(ctx context.Context, patch *models.ManifestPatch) (*models.ManifestPatch, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// OIDCTrustRuleRepository represents the set of queries on the OIDCTrustRule model
+type OIDCTrustRuleRepository interface {
+	CreateOIDCTrustRule(ctx context.Context, rule *models.OIDCTrustRule) (*models.OIDCTrustRule, error)
+	ReadOIDCTrustRule(ctx context.Context, projectID, ruleID uint) (*models.OIDCTrustRule, error)
+	ListOIDCTrustRulesByProjectID(ctx context.Context, projectID uint) ([]*models.OIDCTrustRule, error)
+	// ListOIDCTrustRulesByIssuer returns the trust rules of a project which trust an issuer
+	ListOIDCTrustRulesByIssuer(ctx context.Context, projectID uint, issuer string) ([]*models.OIDCTrustRule, error)
+	DeleteOIDCTrustRule(ctx context.Context, rule *models.OIDCTrustRule) (*models.OIDCTrustRule, error)
+	// DeleteExpiredOIDCTokens permanently deletes the api tokens exchanged through trust rules,
+	// including deleted ones, which expired before the given time
+	DeleteExpiredOIDCTokens(ctx context.Context, before time.Time) (int64, error)
+}
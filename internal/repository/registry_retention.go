@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+)
+
+// RegistryRetentionRepository represents the set of queries on the RegistryRetentionPolicy and RegistryGCRun models
+type RegistryRetentionRepository interface {
+	// ListRegistryRetentionPolicies lists the retention policies of a registry's repositories
+	ListRegistryRetentionPolicies(ctx context.Context, projectID, registryID uint) ([]*models.RegistryRetentionPolicy, error)
+	// ReadRegistryRetentionPolicy reads the retention policy of a repository
+	ReadRegistryRetentionPolicy(ctx context.Context, projectID, registryID uint, repository string) (*models.RegistryRetentionPolicy, error)
+	// UpdateRegistryRetentionPolicy creates or replaces the retention policy of a repository
+	UpdateRegistryRetentionPolicy(ctx context.Context, policy *models.RegistryRetentionPolicy) (*models.RegistryRetentionPolicy, error)
+	DeleteRegistryRetentionPolicy(ctx context.Context, policy *models.RegistryRetentionPolicy) error
+
+	CreateRegistryGCRun(ctx context.Context, run *models.RegistryGCRun) (*models.RegistryGCRun, error)
+	ReadRegistryGCRun(ctx context.Context, projectID, registryID, id uint) (*models.RegistryGCRun, error)
+	// ListRegistryGCRuns lists the garbage collection runs of a registry, most recent first. Runs of all
+	// repositories are listed if repository is empty.
+	ListRegistryGCRuns(ctx context.Context, projectID, registryID uint, repository string) ([]*models.RegistryGCRun, error)
+	// ListRegistryGCRunsByStatus lists the garbage collection runs of all projects with a status, oldest first
+	ListRegistryGCRunsByStatus(ctx context.Context, status types.RegistryGCRunStatus) ([]*models.RegistryGCRun, error)
+	UpdateRegistryGCRun(ctx context.Context, run *models.RegistryGCRun) (*models.RegistryGCRun, error)
+	// UpdateRegistryGCRunStatus changes the status of a run if it has the expected status, and reports
+	// whether it did, so that a run is only executed once
+	UpdateRegistryGCRunStatus(ctx context.Context, run *models.RegistryGCRun, from, to types.RegistryGCRunStatus) (bool, error)
+}
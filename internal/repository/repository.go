@@ -58,4 +58,14 @@ type Repository interface {
 	Datastore() DatastoreRepository
 	AppInstance() AppInstanceRepository
 	Referral() ReferralRepository
+	KeyRotation() KeyRotationRepository
+	AuditLog() AuditLogRepository
+	OIDCTrustRule() OIDCTrustRuleRepository
+	ManifestPatch() ManifestPatchRepository
+	ImageScan() ImageScanRepository
+	ImageSignature() ImageSignatureRepository
+	RegistryRetention() RegistryRetentionRepository
+	EnvGroupProvider() EnvGroupProviderRepository
+	DeployPolicy() DeployPolicyRepository
+	AppRevisionApproval() AppRevisionApprovalRepository
 }
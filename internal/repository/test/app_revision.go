@@ -2,6 +2,9 @@ package test
 
 import (
 	"errors"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/repository"
@@ -21,3 +24,18 @@ func NewAppRevisionRepository() repository.AppRevisionRepository {
 func (repo *AppRevisionRepository) AppRevisionById(projectID uint, appRevisionId string) (*models.AppRevision, error) {
 	return nil, errors.New("cannot read database")
 }
+
+// ListAppRevisionsCreatedSince lists the app revisions of a project which were created after a time
+func (repo *AppRevisionRepository) ListAppRevisionsCreatedSince(projectID uint, since time.Time) ([]*models.AppRevision, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// ListLatestAppRevisions lists the latest revision of each app in each deployment target of a project
+func (repo *AppRevisionRepository) ListLatestAppRevisions(projectID uint, statuses ...models.AppRevisionStatus) ([]*models.AppRevision, error) {
+	return nil, errors.New("cannot read database")
+}
+
+// LatestAppRevision finds the latest revision of an app in a deployment target
+func (repo *AppRevisionRepository) LatestAppRevision(projectID uint, porterAppID uint, deploymentTargetID uuid.UUID, statuses ...models.AppRevisionStatus) (*models.AppRevision, error) {
+	return nil, errors.New("cannot read database")
+}
@@ -0,0 +1,38 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// AppRevisionApprovalRepository represents the set of queries on the AppRevisionApproval model
+type AppRevisionApprovalRepository struct{}
+
+// NewAppRevisionApprovalRepository returns the test AppRevisionApprovalRepository
+func NewAppRevisionApprovalRepository() repository.AppRevisionApprovalRepository {
+	return &AppRevisionApprovalRepository{}
+}
+
+func (repo *AppRevisionApprovalRepository) CreateAppRevisionApproval(ctx context.Context, approval *models.AppRevisionApproval) (*models.AppRevisionApproval, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *AppRevisionApprovalRepository) ReadAppRevisionApproval(ctx context.Context, projectID uint, appRevisionID string) (*models.AppRevisionApproval, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *AppRevisionApprovalRepository) UpdateAppRevisionApproval(ctx context.Context, approval *models.AppRevisionApproval) (*models.AppRevisionApproval, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *AppRevisionApprovalRepository) ListAppRevisionApprovals(ctx context.Context, projectID, clusterID uint, appName string) ([]*models.AppRevisionApproval, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *AppRevisionApprovalRepository) ListExpiredAppRevisionApprovals(ctx context.Context, now time.Time) ([]*models.AppRevisionApproval, error) {
+	return nil, errors.New("cannot read database")
+}
@@ -0,0 +1,129 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"github.com/karagatandev/porter/internal/repository/gorm/helpers"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository is an in-memory repository.AuditLogRepository
+type AuditLogRepository struct {
+	auditLogs []*models.AuditLog
+	settings  map[uint]*models.AuditLogSettings
+}
+
+// NewAuditLogRepository returns the test AuditLogRepository
+func NewAuditLogRepository() repository.AuditLogRepository {
+	return &AuditLogRepository{settings: make(map[uint]*models.AuditLogSettings)}
+}
+
+func (repo *AuditLogRepository) CreateAuditLog(ctx context.Context, auditLog *models.AuditLog) (*models.AuditLog, error) {
+	if auditLog == nil {
+		return nil, errors.New("audit log is nil")
+	}
+
+	auditLog.ID = uint(len(repo.auditLogs) + 1)
+
+	if auditLog.CreatedAt.IsZero() {
+		auditLog.CreatedAt = time.Now()
+	}
+
+	repo.auditLogs = append(repo.auditLogs, auditLog)
+
+	return auditLog, nil
+}
+
+func (repo *AuditLogRepository) ListAuditLogs(ctx context.Context, projectID uint, filter types.AuditLogFilter, opts ...helpers.QueryOption) ([]*models.AuditLog, helpers.PaginatedResult, error) {
+	auditLogs, err := repo.ListAllAuditLogs(ctx, projectID, filter)
+	if err != nil {
+		return nil, helpers.PaginatedResult{}, err
+	}
+
+	return auditLogs, helpers.PaginatedResult{NumPages: 1, CurrentPage: 1}, nil
+}
+
+func (repo *AuditLogRepository) ListAllAuditLogs(ctx context.Context, projectID uint, filter types.AuditLogFilter) ([]*models.AuditLog, error) {
+	res := make([]*models.AuditLog, 0)
+
+	for _, auditLog := range repo.auditLogs {
+		if auditLog != nil && auditLog.ProjectID == projectID && auditLogMatchesFilter(auditLog, filter) {
+			res = append(res, auditLog)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].CreatedAt.After(res[j].CreatedAt)
+	})
+
+	return res, nil
+}
+
+func (repo *AuditLogRepository) ReadAuditLogSettings(ctx context.Context, projectID uint) (*models.AuditLogSettings, error) {
+	settings, ok := repo.settings[projectID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return settings, nil
+}
+
+func (repo *AuditLogRepository) UpdateAuditLogSettings(ctx context.Context, settings *models.AuditLogSettings) (*models.AuditLogSettings, error) {
+	if settings == nil {
+		return nil, errors.New("audit log settings are nil")
+	}
+
+	repo.settings[settings.ProjectID] = settings
+
+	return settings, nil
+}
+
+func (repo *AuditLogRepository) DeleteExpiredAuditLogs(ctx context.Context, defaultRetention time.Duration) (int64, error) {
+	now := time.Now()
+
+	var deleted int64
+
+	for i, auditLog := range repo.auditLogs {
+		if auditLog == nil {
+			continue
+		}
+
+		retention := defaultRetention
+		if settings, ok := repo.settings[auditLog.ProjectID]; ok {
+			retention = settings.Retention(defaultRetention)
+		}
+
+		if retention > 0 && auditLog.CreatedAt.Before(now.Add(-retention)) {
+			repo.auditLogs[i] = nil
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func auditLogMatchesFilter(auditLog *models.AuditLog, filter types.AuditLogFilter) bool {
+	if filter.Since != nil && auditLog.CreatedAt.Before(*filter.Since) {
+		return false
+	}
+
+	if filter.Until != nil && auditLog.CreatedAt.After(*filter.Until) {
+		return false
+	}
+
+	if filter.UserID != nil && auditLog.UserID != *filter.UserID {
+		return false
+	}
+
+	if filter.APITokenID != nil && auditLog.APITokenID != *filter.APITokenID {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,34 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// DeployPolicyRepository represents the set of queries on the DeploymentTargetPolicy and DeployPolicyOverride models
+type DeployPolicyRepository struct{}
+
+// NewDeployPolicyRepository returns the test DeployPolicyRepository
+func NewDeployPolicyRepository() repository.DeployPolicyRepository {
+	return &DeployPolicyRepository{}
+}
+
+func (repo *DeployPolicyRepository) ReadDeploymentTargetPolicy(ctx context.Context, projectID uint, deploymentTargetID uuid.UUID) (*models.DeploymentTargetPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *DeployPolicyRepository) UpdateDeploymentTargetPolicy(ctx context.Context, policy *models.DeploymentTargetPolicy) (*models.DeploymentTargetPolicy, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *DeployPolicyRepository) CreateDeployPolicyOverride(ctx context.Context, override *models.DeployPolicyOverride) (*models.DeployPolicyOverride, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *DeployPolicyRepository) ListDeployPolicyOverrides(ctx context.Context, projectID uint, deploymentTargetID uuid.UUID) ([]*models.DeployPolicyOverride, error) {
+	return nil, errors.New("cannot read database")
+}
@@ -3,46 +3,134 @@ package test
 import (
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/repository"
 )
 
 // DeploymentTargetRepository is a test repository that implements repository.DeploymentTargetRepository
 type DeploymentTargetRepository struct {
-	canQuery bool
+	canQuery          bool
+	deploymentTargets []*models.DeploymentTarget
 }
 
-// NewDeploymentTargetRepository returns the test DeploymentTargetRepository
-func NewDeploymentTargetRepository() repository.DeploymentTargetRepository {
-	return &DeploymentTargetRepository{canQuery: false}
+// NewDeploymentTargetRepository returns the test DeploymentTargetRepository, which returns errors if canQuery is false
+func NewDeploymentTargetRepository(canQuery bool) repository.DeploymentTargetRepository {
+	return &DeploymentTargetRepository{canQuery: canQuery}
 }
 
 // DeploymentTargetBySelectorAndSelectorType finds a deployment target for a projectID and clusterID by its selector and selector type
 func (repo *DeploymentTargetRepository) DeploymentTargetBySelectorAndSelectorType(projectID uint, clusterID uint, selector, selectorType string) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if deploymentTarget.ProjectID == int(projectID) && deploymentTarget.ClusterID == int(clusterID) &&
+			deploymentTarget.Selector == selector && string(deploymentTarget.SelectorType) == selectorType {
+			return deploymentTarget, nil
+		}
+	}
+
+	return &models.DeploymentTarget{}, nil
 }
 
 // ListForCluster returns all deployment targets for a project
 func (repo *DeploymentTargetRepository) ListForCluster(projectID uint, clusterID uint, preview bool) ([]*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.DeploymentTarget{}
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if deploymentTarget.ProjectID == int(projectID) && deploymentTarget.ClusterID == int(clusterID) && deploymentTarget.Preview == preview {
+			res = append(res, deploymentTarget)
+		}
+	}
+
+	return res, nil
 }
 
 // List returns all deployment targets for a project
 func (repo *DeploymentTargetRepository) List(projectID uint, preview bool) ([]*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.DeploymentTarget{}
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if deploymentTarget.ProjectID == int(projectID) && deploymentTarget.Preview == preview {
+			res = append(res, deploymentTarget)
+		}
+	}
+
+	return res, nil
 }
 
 // CreateDeploymentTarget creates a new deployment target
 func (repo *DeploymentTargetRepository) CreateDeploymentTarget(deploymentTarget *models.DeploymentTarget) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if deploymentTarget.ID == uuid.Nil {
+		deploymentTarget.ID = uuid.New()
+	}
+
+	repo.deploymentTargets = append(repo.deploymentTargets, deploymentTarget)
+
+	return deploymentTarget, nil
 }
 
 // DeploymentTarget finds a deployment target by its id if a uuid is provided or by name
 func (repo *DeploymentTargetRepository) DeploymentTarget(projectID uint, deploymentTargetIdentifier string) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	if deploymentTargetIdentifier == "" {
+		return nil, errors.New("deployment target identifier is empty")
+	}
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if deploymentTarget.ProjectID == int(projectID) &&
+			(deploymentTarget.ID.String() == deploymentTargetIdentifier || deploymentTarget.VanityName == deploymentTargetIdentifier) {
+			return deploymentTarget, nil
+		}
+	}
+
+	return &models.DeploymentTarget{}, nil
 }
 
 // DeploymentTargetById finds a deployment target by its uuid
 func (repo *DeploymentTargetRepository) DeploymentTargetById(id string) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if deploymentTarget.ID.String() == id {
+			return deploymentTarget, nil
+		}
+	}
+
+	return &models.DeploymentTarget{}, nil
+}
+
+// UpdateDeploymentTarget updates a deployment target
+func (repo *DeploymentTargetRepository) UpdateDeploymentTarget(deploymentTarget *models.DeploymentTarget) (*models.DeploymentTarget, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	for i, existing := range repo.deploymentTargets {
+		if existing.ID == deploymentTarget.ID {
+			repo.deploymentTargets[i] = deploymentTarget
+			return deploymentTarget, nil
+		}
+	}
+
+	return nil, errors.New("deployment target not found")
 }
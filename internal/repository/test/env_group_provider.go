@@ -0,0 +1,33 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// EnvGroupProviderRepository represents the set of queries on the EnvGroupProvider model
+type EnvGroupProviderRepository struct{}
+
+// NewEnvGroupProviderRepository returns the test EnvGroupProviderRepository
+func NewEnvGroupProviderRepository() repository.EnvGroupProviderRepository {
+	return &EnvGroupProviderRepository{}
+}
+
+func (repo *EnvGroupProviderRepository) ReadEnvGroupProvider(ctx context.Context, projectID, clusterID uint, envGroupName string) (*models.EnvGroupProvider, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *EnvGroupProviderRepository) ListEnvGroupProviders(ctx context.Context) ([]*models.EnvGroupProvider, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *EnvGroupProviderRepository) UpdateEnvGroupProvider(ctx context.Context, provider *models.EnvGroupProvider) (*models.EnvGroupProvider, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *EnvGroupProviderRepository) DeleteEnvGroupProvider(ctx context.Context, provider *models.EnvGroupProvider) error {
+	return errors.New("cannot write database")
+}
@@ -0,0 +1,41 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// ImageScanRepository represents the set of queries on the ImageScan and ImageScanPolicy models
+type ImageScanRepository struct{}
+
+// NewImageScanRepository returns the test ImageScanRepository
+func NewImageScanRepository() repository.ImageScanRepository {
+	return &ImageScanRepository{}
+}
+
+func (repo *ImageScanRepository) CreateImageScan(ctx context.Context, scan *models.ImageScan) (*models.ImageScan, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *ImageScanRepository) ReadImageScanByDigest(ctx context.Context, projectID uint, digest string) (*models.ImageScan, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *ImageScanRepository) ReadImageScanByTag(ctx context.Context, projectID uint, repository, tag string) (*models.ImageScan, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *ImageScanRepository) ListImageScansByDigests(ctx context.Context, projectID uint, digests []string) ([]*models.ImageScan, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *ImageScanRepository) ReadImageScanPolicy(ctx context.Context, projectID uint) (*models.ImageScanPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *ImageScanRepository) UpdateImageScanPolicy(ctx context.Context, policy *models.ImageScanPolicy) (*models.ImageScanPolicy, error) {
+	return nil, errors.New("cannot write database")
+}
@@ -0,0 +1,37 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// ImageSignatureRepository represents the set of queries on the ImageTrustPolicy and ImageVerification models
+type ImageSignatureRepository struct{}
+
+// NewImageSignatureRepository returns the test ImageSignatureRepository
+func NewImageSignatureRepository() repository.ImageSignatureRepository {
+	return &ImageSignatureRepository{}
+}
+
+func (repo *ImageSignatureRepository) ReadImageTrustPolicy(ctx context.Context, projectID uint) (*models.ImageTrustPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *ImageSignatureRepository) UpdateImageTrustPolicy(ctx context.Context, policy *models.ImageTrustPolicy) (*models.ImageTrustPolicy, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *ImageSignatureRepository) CreateImageVerification(ctx context.Context, verification *models.ImageVerification) (*models.ImageVerification, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *ImageSignatureRepository) UpdateImageVerification(ctx context.Context, verification *models.ImageVerification) (*models.ImageVerification, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *ImageSignatureRepository) ReadImageVerificationByAppRevisionID(ctx context.Context, projectID uint, appRevisionID string) (*models.ImageVerification, error) {
+	return nil, errors.New("cannot read database")
+}
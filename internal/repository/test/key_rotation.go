@@ -0,0 +1,40 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// KeyRotationRepository represents the set of queries on the KeyRotation model
+type KeyRotationRepository struct{}
+
+// NewKeyRotationRepository returns the test KeyRotationRepository
+func NewKeyRotationRepository() repository.KeyRotationRepository {
+	return &KeyRotationRepository{}
+}
+
+func (repo *KeyRotationRepository) CreateKeyRotation(rotation *models.KeyRotation) (*models.KeyRotation, error) {
+	return rotation, errors.New("cannot write database")
+}
+
+func (repo *KeyRotationRepository) ReadKeyRotation(id uint) (*models.KeyRotation, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *KeyRotationRepository) ReadKeyRotationByFingerprint(fingerprint string) (*models.KeyRotation, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *KeyRotationRepository) ListKeyRotations() ([]*models.KeyRotation, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *KeyRotationRepository) UpdateKeyRotation(rotation *models.KeyRotation) (*models.KeyRotation, error) {
+	return rotation, errors.New("cannot write database")
+}
+
+func (repo *KeyRotationRepository) UpdateKeyRotationCheckpoint(checkpoint *models.KeyRotationCheckpoint) (*models.KeyRotationCheckpoint, error) {
+	return checkpoint, errors.New("cannot write database")
+}
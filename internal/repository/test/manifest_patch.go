@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"sort"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ManifestPatchRepository is an in-memory repository.ManifestPatchRepository
+type ManifestPatchRepository struct {
+	patches []*models.ManifestPatch
+}
+
+// NewManifestPatchRepository returns the test ManifestPatchRepository
+func NewManifestPatchRepository() repository.ManifestPatchRepository {
+	return &ManifestPatchRepository{}
+}
+
+func (repo *ManifestPatchRepository) CreateManifestPatch(ctx context.Context, patch *models.ManifestPatch) (*models.ManifestPatch, error) {
+	patch.ID = uint(len(repo.patches) + 1)
+	repo.patches = append(repo.patches, patch)
+
+	return patch, nil
+}
+
+func (repo *ManifestPatchRepository) ReadManifestPatch(ctx context.Context, projectID, patchID uint) (*models.ManifestPatch, error) {
+	for _, patch := range repo.patches {
+		if patch != nil && patch.ProjectID == projectID && patch.ID == patchID {
+			return patch, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *ManifestPatchRepository) ListManifestPatchesByProjectID(ctx context.Context, projectID uint) ([]*models.ManifestPatch, error) {
+	res := make([]*models.ManifestPatch, 0)
+
+	for _, patch := range repo.patches {
+		if patch != nil && patch.ProjectID == projectID {
+			res = append(res, patch)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Priority < res[j].Priority
+	})
+
+	return res, nil
+}
+
+func (repo *ManifestPatchRepository) UpdateManifestPatch(ctx context.Context, patch *models.ManifestPatch) (*models.ManifestPatch, error) {
+	if _, err := repo.ReadManifestPatch(ctx, patch.ProjectID, patch.ID); err != nil {
+		return nil, err
+	}
+
+	repo.patches[patch.ID-1] = patch
+
+	return patch, nil
+}
+
+func (repo *ManifestPatchRepository) DeleteManifestPatch(ctx context.Context, patch *models.ManifestPatch) (*models.ManifestPatch, error) {
+	if _, err := repo.ReadManifestPatch(ctx, patch.ProjectID, patch.ID); err != nil {
+		return nil, err
+	}
+
+	repo.patches[patch.ID-1] = nil
+
+	return patch, nil
+}
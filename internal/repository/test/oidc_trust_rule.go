@@ -0,0 +1,42 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// OIDCTrustRuleRepository represents the set of queries on the OIDCTrustRule model
+type OIDCTrustRuleRepository struct{}
+
+// NewOIDCTrustRuleRepository returns the test OIDCTrustRuleRepository
+func NewOIDCTrustRuleRepository() repository.OIDCTrustRuleRepository {
+	return &OIDCTrustRuleRepository{}
+}
+
+func (repo *OIDCTrustRuleRepository) CreateOIDCTrustRule(ctx context.Context, rule *models.OIDCTrustRule) (*models.OIDCTrustRule, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *OIDCTrustRuleRepository) ReadOIDCTrustRule(ctx context.Context, projectID, ruleID uint) (*models.OIDCTrustRule, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *OIDCTrustRuleRepository) ListOIDCTrustRulesByProjectID(ctx context.Context, projectID uint) ([]*models.OIDCTrustRule, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *OIDCTrustRuleRepository) ListOIDCTrustRulesByIssuer(ctx context.Context, projectID uint, issuer string) ([]*models.OIDCTrustRule, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *OIDCTrustRuleRepository) DeleteOIDCTrustRule(ctx context.Context, rule *models.OIDCTrustRule) (*models.OIDCTrustRule, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *OIDCTrustRuleRepository) DeleteExpiredOIDCTokens(ctx context.Context, before time.Time) (int64, error) {
+	return 0, errors.New("cannot write database")
+}
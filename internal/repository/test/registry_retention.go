@@ -0,0 +1,58 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+)
+
+// RegistryRetentionRepository represents the set of queries on the RegistryRetentionPolicy and RegistryGCRun models
+type RegistryRetentionRepository struct{}
+
+// NewRegistryRetentionRepository returns the test RegistryRetentionRepository
+func NewRegistryRetentionRepository() repository.RegistryRetentionRepository {
+	return &RegistryRetentionRepository{}
+}
+
+func (repo *RegistryRetentionRepository) ListRegistryRetentionPolicies(ctx context.Context, projectID, registryID uint) ([]*models.RegistryRetentionPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *RegistryRetentionRepository) ReadRegistryRetentionPolicy(ctx context.Context, projectID, registryID uint, repository string) (*models.RegistryRetentionPolicy, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *RegistryRetentionRepository) UpdateRegistryRetentionPolicy(ctx context.Context, policy *models.RegistryRetentionPolicy) (*models.RegistryRetentionPolicy, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *RegistryRetentionRepository) DeleteRegistryRetentionPolicy(ctx context.Context, policy *models.RegistryRetentionPolicy) error {
+	return errors.New("cannot write database")
+}
+
+func (repo *RegistryRetentionRepository) CreateRegistryGCRun(ctx context.Context, run *models.RegistryGCRun) (*models.RegistryGCRun, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *RegistryRetentionRepository) ReadRegistryGCRun(ctx context.Context, projectID, registryID, id uint) (*models.RegistryGCRun, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *RegistryRetentionRepository) ListRegistryGCRuns(ctx context.Context, projectID, registryID uint, repository string) ([]*models.RegistryGCRun, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *RegistryRetentionRepository) ListRegistryGCRunsByStatus(ctx context.Context, status types.RegistryGCRunStatus) ([]*models.RegistryGCRun, error) {
+	return nil, errors.New("cannot read database")
+}
+
+func (repo *RegistryRetentionRepository) UpdateRegistryGCRun(ctx context.Context, run *models.RegistryGCRun) (*models.RegistryGCRun, error) {
+	return nil, errors.New("cannot write database")
+}
+
+func (repo *RegistryRetentionRepository) UpdateRegistryGCRunStatus(ctx context.Context, run *models.RegistryGCRun, from, to types.RegistryGCRunStatus) (bool, error) {
+	return false, errors.New("cannot write database")
+}
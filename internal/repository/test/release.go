@@ -93,6 +93,25 @@ func (repo *ReleaseRepository) ListReleasesByImageRepoURI(
 	return res, nil
 }
 
+// ListReleasesByProjectID lists the releases of a project in every cluster
+func (repo *ReleaseRepository) ListReleasesByProjectID(
+	projectID uint,
+) ([]*models.Release, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Release, 0)
+
+	for _, release := range repo.releases {
+		if release != nil && release.ProjectID == projectID {
+			res = append(res, release)
+		}
+	}
+
+	return res, nil
+}
+
 // UpdateRelease modifies an existing Release in the database
 func (repo *ReleaseRepository) UpdateRelease(
 	release *models.Release,
@@ -62,6 +62,16 @@ type TestRepository struct {
 	datastore                 repository.DatastoreRepository
 	appInstance               repository.AppInstanceRepository
 	referral                  repository.ReferralRepository
+	keyRotation               repository.KeyRotationRepository
+	auditLog                  repository.AuditLogRepository
+	oidcTrustRule             repository.OIDCTrustRuleRepository
+	manifestPatch             repository.ManifestPatchRepository
+	imageScan                 repository.ImageScanRepository
+	imageSignature            repository.ImageSignatureRepository
+	registryRetention         repository.RegistryRetentionRepository
+	envGroupProvider          repository.EnvGroupProviderRepository
+	deployPolicy              repository.DeployPolicyRepository
+	appRevisionApproval       repository.AppRevisionApprovalRepository
 }
 
 func (t *TestRepository) User() repository.UserRepository {
@@ -299,6 +309,56 @@ func (t *TestRepository) Referral() repository.ReferralRepository {
 	return t.referral
 }
 
+// KeyRotation returns a test KeyRotationRepository
+func (t *TestRepository) KeyRotation() repository.KeyRotationRepository {
+	return t.keyRotation
+}
+
+// AuditLog returns a test AuditLogRepository
+func (t *TestRepository) AuditLog() repository.AuditLogRepository {
+	return t.auditLog
+}
+
+// OIDCTrustRule returns a test OIDCTrustRuleRepository
+func (t *TestRepository) OIDCTrustRule() repository.OIDCTrustRuleRepository {
+	return t.oidcTrustRule
+}
+
+// ManifestPatch returns a test ManifestPatchRepository
+func (t *TestRepository) ManifestPatch() repository.ManifestPatchRepository {
+	return t.manifestPatch
+}
+
+// ImageScan returns a test ImageScanRepository
+func (t *TestRepository) ImageScan() repository.ImageScanRepository {
+	return t.imageScan
+}
+
+// ImageSignature returns a test ImageSignatureRepository
+func (t *TestRepository) ImageSignature() repository.ImageSignatureRepository {
+	return t.imageSignature
+}
+
+// RegistryRetention returns a test RegistryRetentionRepository
+func (t *TestRepository) RegistryRetention() repository.RegistryRetentionRepository {
+	return t.registryRetention
+}
+
+// EnvGroupProvider returns a test EnvGroupProviderRepository
+func (t *TestRepository) EnvGroupProvider() repository.EnvGroupProviderRepository {
+	return t.envGroupProvider
+}
+
+// DeployPolicy returns a test DeployPolicyRepository
+func (t *TestRepository) DeployPolicy() repository.DeployPolicyRepository {
+	return t.deployPolicy
+}
+
+// AppRevisionApproval returns a test AppRevisionApprovalRepository
+func (t *TestRepository) AppRevisionApproval() repository.AppRevisionApprovalRepository {
+	return t.appRevisionApproval
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(canQuery bool, failingMethods ...string) repository.Repository {
@@ -353,12 +413,22 @@ func NewRepository(canQuery bool, failingMethods ...string) repository.Repositor
 		porterApp:                 NewPorterAppRepository(canQuery, failingMethods...),
 		porterAppEvent:            NewPorterAppEventRepository(canQuery),
 		systemServiceStatus:       NewSystemServiceStatusRepository(canQuery),
-		deploymentTarget:          NewDeploymentTargetRepository(),
+		deploymentTarget:          NewDeploymentTargetRepository(canQuery),
 		appRevision:               NewAppRevisionRepository(),
 		appTemplate:               NewAppTemplateRepository(),
 		githubWebhook:             NewGithubWebhookRepository(),
 		datastore:                 NewDatastoreRepository(),
 		appInstance:               NewAppInstanceRepository(),
 		referral:                  NewReferralRepository(),
+		keyRotation:               NewKeyRotationRepository(),
+		auditLog:                  NewAuditLogRepository(),
+		oidcTrustRule:             NewOIDCTrustRuleRepository(),
+		manifestPatch:             NewManifestPatchRepository(),
+		imageScan:                 NewImageScanRepository(),
+		imageSignature:            NewImageSignatureRepository(),
+		registryRetention:         NewRegistryRetentionRepository(),
+		envGroupProvider:          NewEnvGroupProviderRepository(),
+		deployPolicy:              NewDeployPolicyRepository(),
+		appRevisionApproval:       NewAppRevisionApprovalRepository(),
 	}
 }
@@ -0,0 +1,250 @@
+// Package retention decides which images in a registry repository are kept and which are garbage
+// collected under a repository's retention rules.
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reason explains why an image is kept
+type Reason string
+
+const (
+	// ReasonLatest is given for the most recently pushed images, as counted by Rules.KeepLast
+	ReasonLatest Reason = "latest"
+	// ReasonDeployed is given for images deployed by a revision within Rules.KeepDeployedWithin
+	ReasonDeployed Reason = "deployed"
+	// ReasonCurrent is given for images deployed by the latest revision of an app, however old it is
+	ReasonCurrent Reason = "currently deployed"
+	// ReasonPattern is given for images with a tag matching one of Rules.KeepTagPatterns
+	ReasonPattern Reason = "tag pattern"
+	// ReasonUnknownAge is given for images whose push time isn't known, so can't be ordered
+	ReasonUnknownAge Reason = "push time unknown"
+	// ReasonUnknownDigest is given for images whose digest isn't known, so can't be deleted safely
+	ReasonUnknownDigest Reason = "digest unknown"
+	// ReasonSharedDigest is given for tags of a manifest which is kept because of one of its other tags
+	ReasonSharedDigest Reason = "manifest kept by another tag"
+)
+
+// Rules decide which images in a repository are kept. An image is kept if any rule keeps it.
+type Rules struct {
+	// KeepLast keeps the most recently pushed images
+	KeepLast int `json:"keep_last"`
+	// KeepDeployedWithin keeps images which were deployed by a revision created within the duration
+	KeepDeployedWithin time.Duration `json:"keep_deployed_within"`
+	// KeepTagPatterns keeps images with a tag matching one of the patterns, in the syntax of path.Match
+	KeepTagPatterns []string `json:"keep_tag_patterns"`
+}
+
+// Validate checks that the rules are usable
+func (r Rules) Validate() error {
+	if r.KeepLast < 0 {
+		return errors.New("the number of images to keep cannot be negative")
+	}
+
+	if r.KeepDeployedWithin < 0 {
+		return errors.New("the deployment window cannot be negative")
+	}
+
+	for _, pattern := range r.KeepTagPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Image is a tag in a repository
+type Image struct {
+	Tag    string `json:"tag"`
+	Digest string `json:"digest"`
+	// PushedAt is nil if the registry doesn't report when the image was pushed
+	PushedAt *time.Time `json:"pushed_at,omitempty"`
+}
+
+// Deployment is an image deployed by an app revision
+type Deployment struct {
+	Tag string
+	// Digest is set if the revision pinned the image by digest
+	Digest     string
+	DeployedAt time.Time
+	// Current is true if the revision is the latest revision of the app in its deployment target
+	Current bool
+}
+
+// Manifest is an image manifest, which may be tagged more than once. Registries delete manifests rather
+// than tags, so a manifest is only deleted if none of its tags are kept.
+type Manifest struct {
+	Digest   string     `json:"digest"`
+	Tags     []string   `json:"tags"`
+	PushedAt *time.Time `json:"pushed_at,omitempty"`
+}
+
+// Kept is an image which is kept, and the reason why
+type Kept struct {
+	Image
+	Reason Reason `json:"reason"`
+}
+
+// Plan lists the images which are kept and the manifests which are deleted
+type Plan struct {
+	Keep   []Kept     `json:"keep"`
+	Delete []Manifest `json:"delete"`
+}
+
+// NewPlan applies the rules to the images in a repository. Images without a digest are always kept,
+// since they can't be deleted safely.
+func NewPlan(images []Image, deployments []Deployment, rules Rules, now time.Time) *Plan {
+	sorted := append([]Image(nil), images...)
+
+	// newest first, with images of unknown age first since they may be the newest
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].PushedAt == nil || sorted[j].PushedAt == nil {
+			return sorted[i].PushedAt == nil && sorted[j].PushedAt != nil
+		}
+
+		return sorted[i].PushedAt.After(*sorted[j].PushedAt)
+	})
+
+	deployed := make(map[string]Reason)
+
+	for _, d := range deployments {
+		var reason Reason
+
+		switch {
+		case d.Current:
+			reason = ReasonCurrent
+		case rules.KeepDeployedWithin > 0 && now.Sub(d.DeployedAt) <= rules.KeepDeployedWithin:
+			reason = ReasonDeployed
+		default:
+			continue
+		}
+
+		for _, key := range []string{"tag:" + d.Tag, "digest:" + d.Digest} {
+			if key == "tag:" || key == "digest:" {
+				continue
+			}
+
+			if _, ok := deployed[key]; !ok || reason == ReasonCurrent {
+				deployed[key] = reason
+			}
+		}
+	}
+
+	plan := &Plan{}
+
+	// the digests of kept images, so that the other tags of their manifests are kept too
+	keptDigests := make(map[string]bool)
+	var unkept []Image
+
+	dated := 0
+
+	for _, img := range sorted {
+		reason := keepReason(img, deployed, rules)
+
+		if reason == "" && img.PushedAt != nil && dated < rules.KeepLast {
+			reason = ReasonLatest
+		}
+
+		if img.PushedAt != nil {
+			dated++
+		}
+
+		if reason == "" {
+			unkept = append(unkept, img)
+			continue
+		}
+
+		plan.Keep = append(plan.Keep, Kept{Image: img, Reason: reason})
+		keptDigests[img.Digest] = true
+	}
+
+	manifests := make(map[string]*Manifest)
+	var digests []string
+
+	for _, img := range unkept {
+		if keptDigests[img.Digest] {
+			plan.Keep = append(plan.Keep, Kept{Image: img, Reason: ReasonSharedDigest})
+			continue
+		}
+
+		m, ok := manifests[img.Digest]
+		if !ok {
+			m = &Manifest{Digest: img.Digest, PushedAt: img.PushedAt}
+			manifests[img.Digest] = m
+			digests = append(digests, img.Digest)
+		}
+
+		m.Tags = append(m.Tags, img.Tag)
+	}
+
+	for _, digest := range digests {
+		sort.Strings(manifests[digest].Tags)
+		plan.Delete = append(plan.Delete, *manifests[digest])
+	}
+
+	return plan
+}
+
+func keepReason(img Image, deployed map[string]Reason, rules Rules) Reason {
+	if img.Digest == "" {
+		return ReasonUnknownDigest
+	}
+
+	if reason, ok := deployed["digest:"+img.Digest]; ok {
+		return reason
+	}
+
+	if reason, ok := deployed["tag:"+img.Tag]; ok {
+		return reason
+	}
+
+	for _, pattern := range rules.KeepTagPatterns {
+		if ok, _ := path.Match(pattern, img.Tag); ok {
+			return ReasonPattern
+		}
+	}
+
+	if img.PushedAt == nil {
+		return ReasonUnknownAge
+	}
+
+	return ""
+}
+
+// ParseImageTag splits a deployed tag which was pinned to a digest, in the form tag@sha256:..., into
+// the tag and the digest
+func ParseImageTag(tag string) (string, string) {
+	tag, digest, _ := strings.Cut(tag, "@")
+
+	return tag, digest
+}
+
+// Deployed reports whether a manifest, or one of its tags, is deployed by the latest revision of an app
+// or by a revision created within the window. Plans are executed some time after they are made, so
+// manifests are checked again before they are deleted.
+func Deployed(m Manifest, deployments []Deployment, window time.Duration, now time.Time) bool {
+	tags := make(map[string]bool, len(m.Tags))
+
+	for _, tag := range m.Tags {
+		tags[tag] = true
+	}
+
+	for _, d := range deployments {
+		if !d.Current && now.Sub(d.DeployedAt) > window {
+			continue
+		}
+
+		if (d.Digest != "" && d.Digest == m.Digest) || tags[d.Tag] {
+			return true
+		}
+	}
+
+	return false
+}
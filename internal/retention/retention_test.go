@@ -0,0 +1,129 @@
+package retention
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPlan(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+
+	images := []Image{
+		{Tag: "v5", Digest: "sha256:5", PushedAt: daysAgo(1)},
+		{Tag: "v4", Digest: "sha256:4", PushedAt: daysAgo(2)},
+		{Tag: "v3", Digest: "sha256:3", PushedAt: daysAgo(30)},
+		{Tag: "v2", Digest: "sha256:2", PushedAt: daysAgo(60)},
+		{Tag: "old-alias", Digest: "sha256:2", PushedAt: daysAgo(60)},
+		{Tag: "v1", Digest: "sha256:1", PushedAt: daysAgo(90)},
+		{Tag: "release-1.0", Digest: "sha256:r1", PushedAt: daysAgo(120)},
+		{Tag: "v0", Digest: "sha256:0", PushedAt: daysAgo(200)},
+		{Tag: "untracked", Digest: "sha256:u"},
+		{Tag: "no-digest", PushedAt: daysAgo(300)},
+	}
+
+	deployments := []Deployment{
+		// deployed recently, by a revision which has since been superseded
+		{Tag: "v3", DeployedAt: now.AddDate(0, 0, -5)},
+		// deployed long ago, but still the latest revision of an app
+		{Tag: "v0", Digest: "sha256:0", DeployedAt: now.AddDate(0, 0, -200), Current: true},
+		// deployed long ago by a superseded revision
+		{Tag: "v1", DeployedAt: now.AddDate(0, 0, -90)},
+	}
+
+	rules := Rules{
+		KeepLast:           2,
+		KeepDeployedWithin: 14 * 24 * time.Hour,
+		KeepTagPatterns:    []string{"release-*"},
+	}
+
+	plan := NewPlan(images, deployments, rules, now)
+
+	expectedKept := map[string]Reason{
+		"v5":          ReasonLatest,
+		"v4":          ReasonLatest,
+		"v3":          ReasonDeployed,
+		"v0":          ReasonCurrent,
+		"release-1.0": ReasonPattern,
+		"untracked":   ReasonUnknownAge,
+		"no-digest":   ReasonUnknownDigest,
+	}
+
+	if len(plan.Keep) != len(expectedKept) {
+		t.Errorf("expected %d images to be kept, got %+v", len(expectedKept), plan.Keep)
+	}
+
+	for _, kept := range plan.Keep {
+		if reason, ok := expectedKept[kept.Tag]; !ok || reason != kept.Reason {
+			t.Errorf("expected %s to be kept for %q, got %q", kept.Tag, reason, kept.Reason)
+		}
+	}
+
+	if len(plan.Delete) != 2 {
+		t.Fatalf("expected 2 manifests to be deleted, got %+v", plan.Delete)
+	}
+
+	if plan.Delete[0].Digest != "sha256:2" || len(plan.Delete[0].Tags) != 2 {
+		t.Errorf("expected both tags of sha256:2 to be deleted together, got %+v", plan.Delete[0])
+	}
+
+	if plan.Delete[1].Digest != "sha256:1" {
+		t.Errorf("expected sha256:1 to be deleted, got %+v", plan.Delete[1])
+	}
+}
+
+func TestNewPlanSharedDigest(t *testing.T) {
+	now := time.Now()
+	pushed := now.AddDate(0, 0, -10)
+
+	images := []Image{
+		{Tag: "latest", Digest: "sha256:a", PushedAt: &pushed},
+		{Tag: "v1", Digest: "sha256:a", PushedAt: &pushed},
+	}
+
+	plan := NewPlan(images, nil, Rules{KeepTagPatterns: []string{"latest"}}, now)
+
+	if len(plan.Delete) != 0 {
+		t.Errorf("expected no manifests to be deleted, got %+v", plan.Delete)
+	}
+
+	if len(plan.Keep) != 2 || plan.Keep[1].Reason != ReasonSharedDigest {
+		t.Errorf("expected v1 to be kept with the manifest tagged latest, got %+v", plan.Keep)
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	if err := (Rules{KeepTagPatterns: []string{"["}}).Validate(); err == nil {
+		t.Error("expected an invalid pattern to be rejected")
+	}
+
+	if err := (Rules{KeepLast: -1}).Validate(); err == nil {
+		t.Error("expected a negative count to be rejected")
+	}
+}
+
+func TestDeployed(t *testing.T) {
+	now := time.Now()
+	m := Manifest{Digest: "sha256:a", Tags: []string{"v1", "v1.0"}}
+
+	tests := []struct {
+		name        string
+		deployments []Deployment
+		want        bool
+	}{
+		{"not deployed", []Deployment{{Tag: "v2", DeployedAt: now}}, false},
+		{"tag deployed within window", []Deployment{{Tag: "v1.0", DeployedAt: now.Add(-time.Hour)}}, true},
+		{"digest deployed within window", []Deployment{{Tag: "other", Digest: "sha256:a", DeployedAt: now}}, true},
+		{"deployed before window", []Deployment{{Tag: "v1", DeployedAt: now.AddDate(0, 0, -30)}}, false},
+		{"currently deployed", []Deployment{{Tag: "v1", DeployedAt: now.AddDate(0, 0, -30), Current: true}}, true},
+	}
+
+	for _, tt := range tests {
+		if got := Deployed(m, tt.deployments, 24*time.Hour, now); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/config/env"
 	"github.com/karagatandev/porter/internal/adapter"
 	"github.com/karagatandev/porter/internal/analytics"
+	"github.com/karagatandev/porter/internal/encryption"
 	"github.com/karagatandev/porter/internal/features"
 	"github.com/karagatandev/porter/internal/kubernetes"
 	klocal "github.com/karagatandev/porter/internal/kubernetes/local"
@@ -59,6 +60,10 @@ type Config struct {
 	StorageManager storage.StorageManager
 	Repo           repository.Repository
 
+	// Keyring holds the envelope encryption configured by ENCRYPTION_KMS_PROVIDER, and the
+	// previous key set by OLD_ENCRYPTION_KEY while a key rotation is in progress
+	Keyring *encryption.Keyring
+
 	// Logger for logging
 	Logger *logger.Logger
 
@@ -173,7 +178,12 @@ func GetConfig(envConf *EnvConf) (*Config, error) {
 		key[i] = b
 	}
 
-	res.Repo = gorm.NewRepository(db, &key, InstanceCredentialBackend)
+	res.Keyring, err = adapter.NewKeyring(envConf.DBConf, &key)
+	if err != nil {
+		return nil, fmt.Errorf("could not create encryption keyring: %w", err)
+	}
+
+	res.Repo = gorm.NewRepositoryWithKeyring(db, &key, InstanceCredentialBackend, res.Keyring)
 
 	launchDarklyClient, err := features.GetClient(envConf.FeatureFlagClient, envConf.LaunchDarklySDKKey)
 	if err != nil {
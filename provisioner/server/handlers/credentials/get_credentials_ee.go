@@ -58,7 +58,7 @@ func (c *CredentialsGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		}
 
 		// use this vault client for the repo
-		repo = gorm.NewRepository(c.config.DB, &key, vaultClient)
+		repo = gorm.NewRepositoryWithKeyring(c.config.DB, &key, vaultClient, c.config.Keyring)
 	}
 
 	if ceToken.DOCredentialID != 0 {
@@ -47,7 +47,12 @@ func main() {
 		key[i] = b
 	}
 
-	repo := pgorm.NewRepository(db, &key, credBackend)
+	keyring, err := adapter.NewKeyring(&conf.DBConf, &key)
+	if err != nil {
+		log.Fatalf("Failed to create encryption keyring: %v", err)
+	}
+
+	repo := pgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
 
 	log.Println("Creating test user")
 
@@ -63,7 +63,12 @@ func NewUsageTracker(opts *UsageTrackerOpts) (*UsageTracker, error) {
 		key[i] = b
 	}
 
-	repo := rgorm.NewRepository(db, &key, credBackend)
+	keyring, err := adapter.NewKeyring(opts.DBConf, &key)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := rgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
 
 	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
 		ClientID:     opts.DOClientID,
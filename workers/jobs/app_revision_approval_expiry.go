@@ -0,0 +1,154 @@
+//go:build ee
+
+/*
+
+                     === App Revision Approval Expiry Job ===
+
+This job expires app revisions which were held for approval, but were not approved before their deadline.
+
+  - Revisions which were created by the cluster control plane are marked as failed there, so that they never deploy.
+  - Deferred applies of an image are discarded along with the approval.
+  - The approvers of the project are notified of each expired revision.
+
+*/
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+
+	"github.com/karagatandev/porter/api/server/shared/config/env"
+	"github.com/karagatandev/porter/ee/integrations/vault"
+	"github.com/karagatandev/porter/internal/adapter"
+	"github.com/karagatandev/porter/internal/deployment_target"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/repository"
+	rcreds "github.com/karagatandev/porter/internal/repository/credentials"
+	rgorm "github.com/karagatandev/porter/internal/repository/gorm"
+	"gorm.io/gorm"
+)
+
+type appRevisionApprovalExpiry struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	repo        repository.Repository
+	serverURL   string
+	ccpClient   porterv1connect.ClusterControlPlaneServiceClient
+}
+
+// AppRevisionApprovalExpiryOpts holds the options required to run this job
+type AppRevisionApprovalExpiryOpts struct {
+	DBConf                     *env.DBConf
+	ServerURL                  string
+	ClusterControlPlaneAddress string
+}
+
+func NewAppRevisionApprovalExpiry(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *AppRevisionApprovalExpiryOpts,
+) (*appRevisionApprovalExpiry, error) {
+	if opts.ClusterControlPlaneAddress == "" {
+		return nil, fmt.Errorf("must provide CLUSTER_CONTROL_PLANE_ADDRESS")
+	}
+
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	keyring, err := adapter.NewKeyring(opts.DBConf, &key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating encryption keyring: %w", err)
+	}
+
+	repo := rgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
+
+	ccpClient := porterv1connect.NewClusterControlPlaneServiceClient(http.DefaultClient, opts.ClusterControlPlaneAddress)
+
+	return &appRevisionApprovalExpiry{
+		enqueueTime, db, repo, opts.ServerURL, ccpClient,
+	}, nil
+}
+
+func (e *appRevisionApprovalExpiry) ID() string {
+	return "app-revision-approval-expiry"
+}
+
+func (e *appRevisionApprovalExpiry) EnqueueTime() time.Time {
+	return e.enqueueTime
+}
+
+func (e *appRevisionApprovalExpiry) Run(ctx context.Context) error {
+	approvals, err := e.repo.AppRevisionApproval().ListExpiredAppRevisionApprovals(ctx, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("error listing expired app revision approvals: %w", err)
+	}
+
+	expired := 0
+
+	for _, approval := range approvals {
+		if err := e.expire(ctx, approval); err != nil {
+			log.Printf("error expiring revision %s of app %s in project ID %d: %v", approval.AppRevisionID, approval.AppName, approval.ProjectID, err)
+			continue
+		}
+
+		expired++
+	}
+
+	log.Printf("expired %d of %d app revisions awaiting approval", expired, len(approvals))
+
+	return nil
+}
+
+// expire fails a revision which was not approved in time, and notifies the approvers of its project
+func (e *appRevisionApprovalExpiry) expire(ctx context.Context, approval *models.AppRevisionApproval) error {
+	if !approval.Deferred() {
+		_, err := e.ccpClient.UpdateRevisionStatus(ctx, connect.NewRequest(&porterv1.UpdateRevisionStatusRequest{
+			ProjectId:      int64(approval.ProjectID),
+			AppRevisionId:  approval.AppRevisionID,
+			RevisionStatus: porterv1.EnumRevisionStatus_ENUM_REVISION_STATUS_DEPLOY_FAILED,
+		}))
+		if err != nil {
+			return fmt.Errorf("error updating revision status: %w", err)
+		}
+	}
+
+	approval.Status = models.AppRevisionStatus_ApprovalExpired
+	approval.DeferredApply = nil
+
+	if _, err := e.repo.AppRevisionApproval().UpdateAppRevisionApproval(ctx, approval); err != nil {
+		return fmt.Errorf("error updating app revision approval: %w", err)
+	}
+
+	if err := deployment_target.NotifyApproval(ctx, e.repo, deployment_target.NotifyApprovalInput{
+		Approval:  approval,
+		ServerURL: e.serverURL,
+		CCPClient: e.ccpClient,
+	}); err != nil {
+		log.Printf("error notifying approvers of expired revision %s: %v", approval.AppRevisionID, err)
+	}
+
+	return nil
+}
+
+func (e *appRevisionApprovalExpiry) SetData([]byte) {}
@@ -0,0 +1,285 @@
+//go:build ee
+
+/*
+
+                            === Drift Detector Job ===
+
+This job flags Helm releases whose objects were changed in the cluster outside of Helm.
+
+  - The job is run for a single cluster, passed as the project_id and cluster_id inputs.
+  - For every namespace in the cluster, the deployed releases are fetched.
+  - The objects in the manifest of every release are compared with the live objects.
+  - The result for every release is stored as a monitor test result in the "drift" category,
+    which fails when the release has drifted.
+
+*/
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/karagatandev/porter/api/server/shared/config/env"
+	"github.com/karagatandev/porter/api/server/shared/requestutils"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/adapter"
+	"github.com/karagatandev/porter/pkg/logger"
+	"github.com/karagatandev/porter/workers/utils"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/karagatandev/porter/ee/integrations/vault"
+	"github.com/karagatandev/porter/internal/helm"
+	"github.com/karagatandev/porter/internal/helm/drift"
+	"github.com/karagatandev/porter/internal/kubernetes"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/oauth"
+	"github.com/karagatandev/porter/internal/repository"
+	rcreds "github.com/karagatandev/porter/internal/repository/credentials"
+	rgorm "github.com/karagatandev/porter/internal/repository/gorm"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+// driftMonitorCategory is the category of the monitor test results created by the job
+const driftMonitorCategory = "drift"
+
+type driftDetector struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	repo        repository.Repository
+	doConf      *oauth2.Config
+	projectID   uint
+	clusterID   uint
+}
+
+// DriftDetectorOpts holds the options required to run this job
+type DriftDetectorOpts struct {
+	DBConf         *env.DBConf
+	DOClientID     string
+	DOClientSecret string
+	DOScopes       []string
+	ServerURL      string
+
+	Input map[string]interface{}
+}
+
+type driftDetectorInput struct {
+	ProjectID uint `mapstructure:"project_id" validate:"required"`
+	ClusterID uint `mapstructure:"cluster_id" validate:"required"`
+}
+
+func NewDriftDetector(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *DriftDetectorOpts,
+) (*driftDetector, error) {
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	keyring, err := adapter.NewKeyring(opts.DBConf, &key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating encryption keyring: %w", err)
+	}
+
+	repo := rgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	parsedInput := &driftDetectorInput{}
+
+	if err := mapstructure.Decode(opts.Input, parsedInput); err != nil {
+		return nil, err
+	}
+
+	validator := requestutils.NewDefaultValidator()
+
+	if requestErr := validator.Validate(parsedInput); requestErr != nil {
+		return nil, fmt.Errorf(requestErr.Error())
+	}
+
+	return &driftDetector{
+		enqueueTime, db, repo, doConf, parsedInput.ProjectID, parsedInput.ClusterID,
+	}, nil
+}
+
+func (d *driftDetector) ID() string {
+	return "drift-detector"
+}
+
+func (d *driftDetector) EnqueueTime() time.Time {
+	return d.enqueueTime
+}
+
+func (d *driftDetector) Run(ctx context.Context) error {
+	cluster, err := d.repo.Cluster().ReadCluster(d.projectID, d.clusterID)
+	if err != nil {
+		return fmt.Errorf("error reading cluster ID %d: %w", d.clusterID, err)
+	}
+
+	k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      d.repo,
+		DigitalOceanOAuth:         d.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   5 * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting k8s agent for cluster ID %d: %w", cluster.ID, err)
+	}
+
+	getter, err := k8sAgent.NewLiveObjectGetter()
+	if err != nil {
+		return fmt.Errorf("error getting live object getter for cluster ID %d: %w", cluster.ID, err)
+	}
+
+	namespaces, err := k8sAgent.ListNamespaces()
+	if err != nil {
+		return fmt.Errorf("error fetching namespaces for cluster ID %d: %w", cluster.ID, err)
+	}
+
+	for _, ns := range namespaces.Items {
+		agent, err := utils.NewRetryHelmAgent(ctx, &helm.Form{
+			Cluster:                   cluster,
+			Namespace:                 ns.Name,
+			Repo:                      d.repo,
+			DigitalOceanOAuth:         d.doConf,
+			AllowInClusterConnections: false,
+			Timeout:                   5 * time.Second,
+		}, logger.New(true, os.Stdout), 3, time.Second)
+		if err != nil {
+			log.Printf("error fetching helm client for namespace %s in cluster ID %d: %v. skipping namespace ...",
+				ns.Name, cluster.ID, err)
+			continue
+		}
+
+		releases, err := agent.ListReleases(ctx, ns.Name, &types.ReleaseListFilter{
+			StatusFilter: []string{"deployed"},
+		})
+		if err != nil {
+			log.Printf("error fetching releases for namespace %s in cluster ID %d: %v. skipping namespace ...",
+				ns.Name, cluster.ID, err)
+			continue
+		}
+
+		for _, rel := range releases {
+			report, err := drift.DetectRelease(ctx, getter, rel)
+			if err != nil {
+				log.Printf("error detecting drift of release %s in namespace %s of cluster ID %d: %v. skipping release ...",
+					rel.Name, ns.Name, cluster.ID, err)
+				continue
+			}
+
+			if err := d.saveResult(cluster, report); err != nil {
+				log.Printf("error saving drift of release %s in namespace %s of cluster ID %d: %v",
+					rel.Name, ns.Name, cluster.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// saveResult stores the drift of a release as a monitor test result, creating it on the
+// first run
+func (d *driftDetector) saveResult(cluster *models.Cluster, report *drift.Report) error {
+	objectID := fmt.Sprintf("helm_release/%s/%s/drift", report.Namespace, report.Release)
+
+	runResult := types.MonitorTestStatusSuccess
+	severity := types.MonitorTestSeverityLow
+	message := fmt.Sprintf("The %d objects of revision %d match the cluster", report.Checked, report.Revision)
+
+	if report.Drifted {
+		runResult = types.MonitorTestStatusFailed
+		message = driftMessage(report)
+
+		for _, res := range report.Resources {
+			if res.Missing {
+				severity = types.MonitorTestSeverityHigh
+			}
+		}
+	}
+
+	currTime := time.Now()
+
+	monitor, err := d.repo.MonitorTestResult().ReadMonitorTestResult(cluster.ProjectID, cluster.ID, objectID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err != nil {
+		_, err = d.repo.MonitorTestResult().CreateMonitorTestResult(&models.MonitorTestResult{
+			ProjectID:         cluster.ProjectID,
+			ClusterID:         cluster.ID,
+			Category:          driftMonitorCategory,
+			ObjectID:          objectID,
+			LastStatusChange:  &currTime,
+			LastTested:        &currTime,
+			LastRunResult:     string(runResult),
+			LastRunResultEnum: models.GetLastRunResultEnum(string(runResult)),
+			Title:             fmt.Sprintf("Release %s matches its manifest", report.Release),
+			Message:           message,
+			Severity:          string(severity),
+			SeverityEnum:      models.GetSeverityEnum(string(severity)),
+		})
+
+		return err
+	}
+
+	if monitor.LastRunResult != string(runResult) {
+		monitor.LastStatusChange = &currTime
+	}
+
+	monitor.LastTested = &currTime
+	monitor.LastRunResult = string(runResult)
+	monitor.LastRunResultEnum = models.GetLastRunResultEnum(string(runResult))
+	monitor.Message = message
+	monitor.Severity = string(severity)
+	monitor.SeverityEnum = models.GetSeverityEnum(string(severity))
+	monitor.Archived = false
+
+	_, err = d.repo.MonitorTestResult().UpdateMonitorTestResult(monitor)
+
+	return err
+}
+
+// driftMessage summarizes the drifted objects of a release
+func driftMessage(report *drift.Report) string {
+	objects := []string{}
+
+	for _, res := range report.Resources {
+		switch {
+		case res.Missing:
+			objects = append(objects, fmt.Sprintf("%s %s was deleted", res.Kind, res.Name))
+		case len(res.Fields) > 0:
+			objects = append(objects, fmt.Sprintf("%s %s has %d changed fields", res.Kind, res.Name, len(res.Fields)))
+		}
+	}
+
+	return fmt.Sprintf("Revision %d was changed outside of Porter: %s", report.Revision, strings.Join(objects, ", "))
+}
+
+func (d *driftDetector) SetData([]byte) {}
@@ -0,0 +1,193 @@
+//go:build ee
+
+/*
+
+                     === Env Group Provider Refresh Job ===
+
+This job re-syncs environment groups from the Vault and AWS Secrets Manager secrets they are configured with.
+
+  - Providers of all projects are synced once their refresh interval has passed since their last sync.
+  - A new version of the environment group is only published if the secret changed, in which case the
+    apps linked to the environment group are re-deployed through the cluster control plane.
+  - The time and error of each sync are recorded on the provider.
+
+*/
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+
+	"github.com/karagatandev/porter/api/server/shared/config/env"
+	"github.com/karagatandev/porter/ee/integrations/vault"
+	"github.com/karagatandev/porter/internal/adapter"
+	"github.com/karagatandev/porter/internal/envgroupprovider"
+	"github.com/karagatandev/porter/internal/kubernetes"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/oauth"
+	"github.com/karagatandev/porter/internal/repository"
+	rcreds "github.com/karagatandev/porter/internal/repository/credentials"
+	rgorm "github.com/karagatandev/porter/internal/repository/gorm"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+type envGroupProviderRefresh struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	repo        repository.Repository
+	doConf      *oauth2.Config
+	ccpClient   porterv1connect.ClusterControlPlaneServiceClient
+}
+
+// EnvGroupProviderRefreshOpts holds the options required to run this job
+type EnvGroupProviderRefreshOpts struct {
+	DBConf                     *env.DBConf
+	DOClientID                 string
+	DOClientSecret             string
+	DOScopes                   []string
+	ServerURL                  string
+	ClusterControlPlaneAddress string
+}
+
+func NewEnvGroupProviderRefresh(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *EnvGroupProviderRefreshOpts,
+) (*envGroupProviderRefresh, error) {
+	if opts.ClusterControlPlaneAddress == "" {
+		return nil, fmt.Errorf("must provide CLUSTER_CONTROL_PLANE_ADDRESS")
+	}
+
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	keyring, err := adapter.NewKeyring(opts.DBConf, &key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating encryption keyring: %w", err)
+	}
+
+	repo := rgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	ccpClient := porterv1connect.NewClusterControlPlaneServiceClient(http.DefaultClient, opts.ClusterControlPlaneAddress)
+
+	return &envGroupProviderRefresh{
+		enqueueTime, db, repo, doConf, ccpClient,
+	}, nil
+}
+
+func (r *envGroupProviderRefresh) ID() string {
+	return "env-group-provider-refresh"
+}
+
+func (r *envGroupProviderRefresh) EnqueueTime() time.Time {
+	return r.enqueueTime
+}
+
+func (r *envGroupProviderRefresh) Run(ctx context.Context) error {
+	providers, err := r.repo.EnvGroupProvider().ListEnvGroupProviders(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing env group providers: %w", err)
+	}
+
+	now := time.Now().UTC()
+	synced := 0
+
+	for _, provider := range providers {
+		if !provider.IsDue(now) {
+			continue
+		}
+
+		if err := r.refresh(ctx, provider); err != nil {
+			log.Printf("error refreshing env group %s in cluster ID %d: %v", provider.EnvGroupName, provider.ClusterID, err)
+		}
+
+		if _, err := r.repo.EnvGroupProvider().UpdateEnvGroupProvider(ctx, provider); err != nil {
+			log.Printf("error updating env group provider %d: %v", provider.ID, err)
+		}
+
+		synced++
+	}
+
+	log.Printf("refreshed %d of %d env group providers", synced, len(providers))
+
+	return nil
+}
+
+// refresh syncs the environment group of a provider, and re-deploys its linked apps if the secret changed
+func (r *envGroupProviderRefresh) refresh(ctx context.Context, provider *models.EnvGroupProvider) error {
+	// failures to connect to the cluster are recorded as failed syncs, so that they are retried after the refresh interval
+	now := time.Now().UTC()
+
+	cluster, err := r.repo.Cluster().ReadCluster(provider.ProjectID, provider.ClusterID)
+	if err != nil {
+		provider.LastSyncedAt = &now
+		provider.LastSyncError = fmt.Sprintf("error reading cluster: %v", err)
+		return fmt.Errorf("error reading cluster ID %d: %w", provider.ClusterID, err)
+	}
+
+	agent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      r.repo,
+		DigitalOceanOAuth:         r.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   5 * time.Second,
+	})
+	if err != nil {
+		provider.LastSyncedAt = &now
+		provider.LastSyncError = fmt.Sprintf("error connecting to cluster: %v", err)
+		return fmt.Errorf("error getting k8s agent for cluster ID %d: %w", cluster.ID, err)
+	}
+
+	output, err := envgroupprovider.Sync(ctx, agent, r.repo, provider, "")
+	if err != nil {
+		return err
+	}
+
+	if !output.Changed {
+		return nil
+	}
+
+	log.Printf("env group %s in cluster ID %d changed, published version %d", provider.EnvGroupName, cluster.ID, output.Version)
+
+	_, err = r.ccpClient.UpdateAppsLinkedToEnvGroup(ctx, connect.NewRequest(&porterv1.UpdateAppsLinkedToEnvGroupRequest{
+		ProjectId:    int64(provider.ProjectID),
+		ClusterId:    int64(provider.ClusterID),
+		EnvGroupName: provider.EnvGroupName,
+	}))
+	if err != nil {
+		return fmt.Errorf("error re-deploying apps linked to env group %s: %w", provider.EnvGroupName, err)
+	}
+
+	return nil
+}
+
+func (r *envGroupProviderRefresh) SetData([]byte) {}
@@ -5,15 +5,17 @@
                             === Helm Release Revisions Tracker Job ===
 
 This job keeps a track of helm releases and their revisions and deletes older revisions once they are
-backed up to an S3 bucket.
+archived to the configured store (S3, GCS or a local directory).
 
   - The job looks for clusters which have the `monitor_helm_releases` set to true.
   - The clusters are then checked for old helm release revisions.
   - In a cluster, list of all namespaces is fetched.
   - For every namespace, the list of releases is fetched.
   - For every release, its revision history is fetched.
-  - If the number of revisions exceeds 100, then we intend to only keep the most recent 100 revisions.
-  - For this, the older revisions are first backed up to an S3 bucket and then deleted.
+  - If the number of revisions exceeds the cluster's retention (or the job's default if the cluster
+    has none), then we intend to only keep that many of the most recent revisions.
+  - For this, the older revisions are first archived and then deleted. Archived revisions can be
+    restored into the cluster with the restore API.
 
 */
 
@@ -21,7 +23,6 @@ package jobs
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -30,12 +31,13 @@ import (
 
 	"github.com/karagatandev/porter/api/server/shared/config/env"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/adapter"
 	"github.com/karagatandev/porter/pkg/logger"
-	"github.com/karagatandev/porter/provisioner/integrations/storage/s3"
 	"github.com/karagatandev/porter/workers/utils"
 
 	"github.com/karagatandev/porter/ee/integrations/vault"
 	"github.com/karagatandev/porter/internal/helm"
+	"github.com/karagatandev/porter/internal/helm/archive"
 	"github.com/karagatandev/porter/internal/kubernetes"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/oauth"
@@ -48,31 +50,23 @@ import (
 )
 
 type helmRevisionsCountTracker struct {
-	enqueueTime        time.Time
-	db                 *gorm.DB
-	repo               repository.Repository
-	doConf             *oauth2.Config
-	awsAccessKeyID     string
-	awsSecretAccessKey string
-	awsRegion          string
-	s3BucketName       string
-	encryptionKey      *[32]byte
-	revisionsCount     int
+	enqueueTime    time.Time
+	db             *gorm.DB
+	repo           repository.Repository
+	doConf         *oauth2.Config
+	store          archive.Store
+	revisionsCount int
 }
 
 // HelmRevisionsCountTrackerOpts holds the options required to run this job
 type HelmRevisionsCountTrackerOpts struct {
-	DBConf             *env.DBConf
-	DOClientID         string
-	DOClientSecret     string
-	DOScopes           []string
-	ServerURL          string
-	AWSAccessKeyID     string
-	AWSSecretAccessKey string
-	AWSRegion          string
-	S3BucketName       string
-	EncryptionKey      string
-	RevisionsCount     int
+	DBConf         *env.DBConf
+	ArchiveConf    *env.HelmArchiveConf
+	DOClientID     string
+	DOClientSecret string
+	DOScopes       []string
+	ServerURL      string
+	RevisionsCount int
 }
 
 func NewHelmRevisionsCountTracker(
@@ -97,7 +91,12 @@ func NewHelmRevisionsCountTracker(
 		key[i] = b
 	}
 
-	repo := rgorm.NewRepository(db, &key, credBackend)
+	keyring, err := adapter.NewKeyring(opts.DBConf, &key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating encryption keyring: %w", err)
+	}
+
+	repo := rgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
 
 	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
 		ClientID:     opts.DOClientID,
@@ -106,15 +105,18 @@ func NewHelmRevisionsCountTracker(
 		BaseURL:      opts.ServerURL,
 	})
 
-	var s3Key [32]byte
+	// revisions are never deleted without being archived first
+	store, err := archive.NewStore(ctx, opts.ArchiveConf)
+	if err != nil {
+		return nil, err
+	}
 
-	for i, b := range []byte(opts.EncryptionKey) {
-		s3Key[i] = b
+	if store == nil {
+		return nil, fmt.Errorf("no helm archive backend is configured")
 	}
 
 	return &helmRevisionsCountTracker{
-		enqueueTime, db, repo, doConf, opts.AWSAccessKeyID, opts.AWSSecretAccessKey, opts.AWSRegion,
-		opts.S3BucketName, &s3Key, opts.RevisionsCount,
+		enqueueTime, db, repo, doConf, store, opts.RevisionsCount,
 	}, nil
 }
 
@@ -158,13 +160,10 @@ func (t *helmRevisionsCountTracker) Run(ctx context.Context) error {
 					return
 				}
 
-				// create s3 client to store revisions that need to be deleted
-				s3Client, err := s3.NewS3StorageClient(&s3.S3Options{
-					t.awsRegion, t.awsAccessKeyID, t.awsSecretAccessKey, t.s3BucketName, t.encryptionKey,
-				})
-				if err != nil {
-					log.Printf("error creating S3 client for cluster ID %d: %v. skipping cluster ...", cluster.ID, err)
-					return
+				revisionsCount := t.revisionsCount
+
+				if cluster.HelmRevisionsToKeep > 0 {
+					revisionsCount = int(cluster.HelmRevisionsToKeep)
 				}
 
 				k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
@@ -229,40 +228,30 @@ func (t *helmRevisionsCountTracker) Run(ctx context.Context) error {
 							continue
 						}
 
-						if len(revisions) <= t.revisionsCount {
+						if len(revisions) <= revisionsCount {
 							log.Printf("release %s of namespace %s in cluster ID %d has <= %d revisions. "+
-								"skipping release...", t.revisionsCount, rel.Name, ns.Name, cluster.ID)
+								"skipping release...", rel.Name, ns.Name, cluster.ID, revisionsCount)
 							continue
 						}
 
 						log.Printf("release %s of namespace %s in cluster ID %d has more than %d revisions. attempting to "+
-							"delete the older ones.", t.revisionsCount, rel.Name, ns.Name, cluster.ID)
+							"delete the older ones.", rel.Name, ns.Name, cluster.ID, revisionsCount)
 
 						// sort revisions from newest to oldest
 						releaseutil.Reverse(revisions, releaseutil.SortByRevision)
 
-						for i := t.revisionsCount; i < len(revisions); i += 1 {
+						for i := revisionsCount; i < len(revisions); i += 1 {
 							rev := revisions[i]
 
-							// store the revision in the s3 bucket before deleting it
-							data, err := json.Marshal(rev)
-							if err != nil {
-								log.Printf("error marshalling revision for release %s, number %d: %v. skipping revision ...",
-									rev.Name, rev.Version, err)
-								continue
-							}
-
-							// write to the bucket with key - <project_id>/<cluster_id>/<namespace>/<release_name>/<revision_number>
-							err = s3Client.WriteFileWithKey(data, true, fmt.Sprintf("%d/%d/%s/%s/%d", cluster.ProjectID,
-								cluster.ID, rel.Namespace, rel.Name, rev.Version))
-
+							// archive the revision before deleting it, so that it can be restored
+							err := archive.ArchiveRevision(ctx, t.store, cluster.ProjectID, cluster.ID, rev)
 							if err != nil {
-								log.Printf("error backing up revision for release %s, number %d: %v. skipping revision ...",
+								log.Printf("error archiving revision for release %s, number %d: %v. skipping revision ...",
 									rev.Name, rev.Version, err)
 								continue
 							}
 
-							log.Printf("revision %d of release %s in namespace %s of cluster ID %d was successfully backed up.",
+							log.Printf("revision %d of release %s in namespace %s of cluster ID %d was successfully archived.",
 								rev.Version, rel.Name, ns.Name, cluster.ID)
 
 							err = agent.DeleteReleaseRevision(ctx, rev.Name, rev.Version)
@@ -4,12 +4,14 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/karagatandev/porter/api/server/shared/config/env"
 	"github.com/karagatandev/porter/ee/integrations/vault"
+	"github.com/karagatandev/porter/internal/adapter"
 	"github.com/karagatandev/porter/internal/kubernetes"
 	"github.com/karagatandev/porter/internal/models"
 	"github.com/karagatandev/porter/internal/oauth"
@@ -80,7 +82,12 @@ func NewPreviewDeploymentsTTLDeleter(
 		key[i] = b
 	}
 
-	repo := rgorm.NewRepository(db, &key, credBackend)
+	keyring, err := adapter.NewKeyring(opts.DBConf, &key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating encryption keyring: %w", err)
+	}
+
+	repo := rgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
 
 	return &previewDeploymentsTTLDeleter{enqueueTime, db, doConf, repo, opts.PreviewDeploymentsTTL}, nil
 }
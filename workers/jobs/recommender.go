@@ -20,6 +20,7 @@ import (
 	"github.com/karagatandev/porter/api/server/shared/config/env"
 	"github.com/karagatandev/porter/api/server/shared/requestutils"
 	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/internal/adapter"
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/karagatandev/porter/ee/integrations/vault"
@@ -95,7 +96,12 @@ func NewRecommender(
 		key[i] = b
 	}
 
-	repo := rgorm.NewRepository(db, &key, credBackend)
+	keyring, err := adapter.NewKeyring(opts.DBConf, &key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating encryption keyring: %w", err)
+	}
+
+	repo := rgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
 
 	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
 		ClientID:     opts.DOClientID,
@@ -106,7 +112,7 @@ func NewRecommender(
 
 	// parse input
 	parsedInput := &recommenderInput{}
-	err := mapstructure.Decode(opts.Input, parsedInput)
+	err = mapstructure.Decode(opts.Input, parsedInput)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,222 @@
+//go:build ee
+
+/*
+
+                            === Registry GC Job ===
+
+This job executes the registry garbage collection runs which were planned as dry runs and then queued.
+
+  - Queued runs of all projects are executed, oldest first.
+  - Before a manifest is deleted, the project's app revisions are checked again, and manifests which
+    were deployed since the run was planned are skipped.
+  - Every manifest is recorded in the run's audit, with the time it was deleted or why it wasn't.
+  - A run fails if any manifest could not be deleted.
+
+*/
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/karagatandev/porter/api/server/shared/config/env"
+	"github.com/karagatandev/porter/api/types"
+	"github.com/karagatandev/porter/ee/integrations/vault"
+	"github.com/karagatandev/porter/internal/adapter"
+	"github.com/karagatandev/porter/internal/models"
+	"github.com/karagatandev/porter/internal/oauth"
+	"github.com/karagatandev/porter/internal/registry"
+	"github.com/karagatandev/porter/internal/repository"
+	rcreds "github.com/karagatandev/porter/internal/repository/credentials"
+	rgorm "github.com/karagatandev/porter/internal/repository/gorm"
+	"github.com/karagatandev/porter/internal/retention"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+type registryGC struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	repo        repository.Repository
+	doConf      *oauth2.Config
+}
+
+// RegistryGCOpts holds the options required to run this job
+type RegistryGCOpts struct {
+	DBConf         *env.DBConf
+	DOClientID     string
+	DOClientSecret string
+	DOScopes       []string
+	ServerURL      string
+}
+
+func NewRegistryGC(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *RegistryGCOpts,
+) (*registryGC, error) {
+	var credBackend rcreds.CredentialStorage
+
+	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
+		credBackend = vault.NewClient(
+			opts.DBConf.VaultServerURL,
+			opts.DBConf.VaultAPIKey,
+			opts.DBConf.VaultPrefix,
+		)
+	}
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	keyring, err := adapter.NewKeyring(opts.DBConf, &key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating encryption keyring: %w", err)
+	}
+
+	repo := rgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	return &registryGC{
+		enqueueTime, db, repo, doConf,
+	}, nil
+}
+
+func (g *registryGC) ID() string {
+	return "registry-gc"
+}
+
+func (g *registryGC) EnqueueTime() time.Time {
+	return g.enqueueTime
+}
+
+func (g *registryGC) Run(ctx context.Context) error {
+	runs, err := g.repo.RegistryRetention().ListRegistryGCRunsByStatus(ctx, types.RegistryGCRunStatusQueued)
+	if err != nil {
+		return fmt.Errorf("error listing queued garbage collection runs: %w", err)
+	}
+
+	log.Printf("found %d queued garbage collection runs", len(runs))
+
+	for _, run := range runs {
+		// another instance of the job may have started the run
+		started, err := g.repo.RegistryRetention().UpdateRegistryGCRunStatus(ctx, run, types.RegistryGCRunStatusQueued, types.RegistryGCRunStatusRunning)
+		if err != nil {
+			log.Printf("error starting garbage collection run %d: %v", run.ID, err)
+			continue
+		}
+
+		if !started {
+			continue
+		}
+
+		runErr := g.execute(ctx, run)
+
+		finishedAt := time.Now().UTC()
+		run.FinishedAt = &finishedAt
+		run.Status = types.RegistryGCRunStatusCompleted
+
+		if runErr != nil {
+			log.Printf("garbage collection run %d failed: %v", run.ID, runErr)
+
+			run.Status = types.RegistryGCRunStatusFailed
+			run.Error = runErr.Error()
+		}
+
+		if _, err := g.repo.RegistryRetention().UpdateRegistryGCRun(ctx, run); err != nil {
+			log.Printf("error updating garbage collection run %d: %v", run.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// execute deletes the manifests planned for deletion by a run, and records each one in the run's audit
+func (g *registryGC) execute(ctx context.Context, run *models.RegistryGCRun) error {
+	startedAt := time.Now().UTC()
+	run.StartedAt = &startedAt
+
+	if _, err := g.repo.RegistryRetention().UpdateRegistryGCRun(ctx, run); err != nil {
+		return fmt.Errorf("error updating run: %w", err)
+	}
+
+	reg, err := g.repo.Registry().ReadRegistry(run.ProjectID, run.RegistryID)
+	if err != nil {
+		return fmt.Errorf("error reading registry %d: %w", run.RegistryID, err)
+	}
+
+	plan, err := run.RetentionPlan()
+	if err != nil {
+		return fmt.Errorf("error decoding plan: %w", err)
+	}
+
+	policy := types.RegistryRetentionPolicy{}
+
+	if err := json.Unmarshal(run.Policy, &policy); err != nil {
+		return fmt.Errorf("error decoding retention policy: %w", err)
+	}
+
+	window := time.Duration(policy.KeepDeployedWithinDays) * 24 * time.Hour
+	now := time.Now().UTC()
+
+	deployments, err := registry.ImageDeployments(g.repo, run.ProjectID, run.Repository, now.Add(-window))
+	if err != nil {
+		return fmt.Errorf("error listing image deployments: %w", err)
+	}
+
+	_reg := registry.Registry(*reg)
+
+	deletions := make([]types.RegistryGCDeletion, 0, len(plan.Delete))
+	deleted, failed := 0, 0
+
+	for _, manifest := range plan.Delete {
+		deletion := types.RegistryGCDeletion{
+			Digest: manifest.Digest,
+			Tags:   manifest.Tags,
+		}
+
+		if retention.Deployed(manifest, deployments, window, now) {
+			deletion.Error = "skipped: the image was deployed after the run was planned"
+		} else if err := _reg.DeleteManifest(ctx, run.Repository, manifest, g.repo, g.doConf); err != nil {
+			deletion.Error = err.Error()
+			failed++
+		} else {
+			deletedAt := time.Now().UTC()
+			deletion.DeletedAt = &deletedAt
+			deleted++
+		}
+
+		deletions = append(deletions, deletion)
+
+		// the audit is saved as the run progresses, so that it is complete if the job is interrupted
+		if err := run.SetDeletions(deletions); err != nil {
+			return fmt.Errorf("error encoding audit: %w", err)
+		}
+
+		if _, err := g.repo.RegistryRetention().UpdateRegistryGCRun(ctx, run); err != nil {
+			return fmt.Errorf("error updating run: %w", err)
+		}
+	}
+
+	log.Printf("garbage collection run %d deleted %d of %d manifests from %s", run.ID, deleted, len(plan.Delete), run.Repository)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d manifests could not be deleted", failed, len(plan.Delete))
+	}
+
+	return nil
+}
+
+func (g *registryGC) SetData([]byte) {}
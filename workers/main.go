@@ -59,12 +59,15 @@ type EnvConf struct {
 	 */
 
 	// "helm-revisions-count-tracker"
+	HelmArchiveConf env.HelmArchiveConf
+	RevisionsCount  int `env:"REVISIONS_COUNT,default=20"`
+
+	// Deprecated: revisions are archived to this S3 bucket if HELM_ARCHIVE_BACKEND is not set
 	AWSAccessKeyID     string `env:"AWS_ACCESS_KEY_ID"`
 	AWSSecretAccessKey string `env:"AWS_SECRET_ACCESS_KEY"`
 	AWSRegion          string `env:"AWS_REGION"`
 	S3BucketName       string `env:"S3_BUCKET_NAME"`
 	EncryptionKey      string `env:"S3_ENCRYPTION_KEY"`
-	RevisionsCount     int    `env:"REVISIONS_COUNT,default=20"`
 
 	// "recommender"
 	OPAConfigFileDir string `env:"OPA_CONFIG_FILE_DIR,default=./internal/opa"`
@@ -72,6 +75,8 @@ type EnvConf struct {
 
 	// "preview-deployments-ttl-deleter"
 	PreviewDeploymentsTTL string `env:"PREVIEW_DEPLOYMENTS_TTL"`
+
+	ClusterControlPlaneAddress string `env:"CLUSTER_CONTROL_PLANE_ADDRESS"`
 }
 
 func main() {
@@ -109,7 +114,12 @@ func main() {
 		key[i] = b
 	}
 
-	repo = pgorm.NewRepository(db, &key, credBackend)
+	keyring, err := adapter.NewKeyring(&envDecoder.DBConf, &key)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	repo = pgorm.NewRepositoryWithKeyring(db, &key, credBackend, keyring)
 
 	opaPolicies, err = opa.LoadPolicies(envDecoder.OPAConfigFileDir)
 
@@ -210,18 +220,27 @@ func httpService(ctx context.Context) http.Handler {
 
 func getJob(ctx context.Context, id string, input map[string]interface{}) worker.Job {
 	if id == "helm-revisions-count-tracker" {
+		archiveConf := envDecoder.HelmArchiveConf
+
+		if archiveConf.Backend == "" && envDecoder.S3BucketName != "" {
+			archiveConf = env.HelmArchiveConf{
+				Backend:            "s3",
+				Bucket:             envDecoder.S3BucketName,
+				AWSRegion:          envDecoder.AWSRegion,
+				AWSAccessKeyID:     envDecoder.AWSAccessKeyID,
+				AWSSecretAccessKey: envDecoder.AWSSecretAccessKey,
+				EncryptionKey:      envDecoder.EncryptionKey,
+			}
+		}
+
 		newJob, err := jobs.NewHelmRevisionsCountTracker(ctx, dbConn, time.Now().UTC(), &jobs.HelmRevisionsCountTrackerOpts{
-			DBConf:             &envDecoder.DBConf,
-			DOClientID:         envDecoder.DOClientID,
-			DOClientSecret:     envDecoder.DOClientSecret,
-			DOScopes:           []string{"read", "write"},
-			ServerURL:          envDecoder.ServerURL,
-			AWSAccessKeyID:     envDecoder.AWSAccessKeyID,
-			AWSSecretAccessKey: envDecoder.AWSSecretAccessKey,
-			AWSRegion:          envDecoder.AWSRegion,
-			S3BucketName:       envDecoder.S3BucketName,
-			EncryptionKey:      envDecoder.EncryptionKey,
-			RevisionsCount:     envDecoder.RevisionsCount,
+			DBConf:         &envDecoder.DBConf,
+			ArchiveConf:    &archiveConf,
+			DOClientID:     envDecoder.DOClientID,
+			DOClientSecret: envDecoder.DOClientSecret,
+			DOScopes:       []string{"read", "write"},
+			ServerURL:      envDecoder.ServerURL,
+			RevisionsCount: envDecoder.RevisionsCount,
 		})
 		if err != nil {
 			log.Printf("error creating job with ID: helm-revisions-count-tracker. Error: %v", err)
@@ -244,6 +263,21 @@ func getJob(ctx context.Context, id string, input map[string]interface{}) worker
 			return nil
 		}
 
+		return newJob
+	} else if id == "drift-detector" {
+		newJob, err := jobs.NewDriftDetector(dbConn, time.Now().UTC(), &jobs.DriftDetectorOpts{
+			DBConf:         &envDecoder.DBConf,
+			DOClientID:     envDecoder.DOClientID,
+			DOClientSecret: envDecoder.DOClientSecret,
+			DOScopes:       []string{"read", "write"},
+			ServerURL:      envDecoder.ServerURL,
+			Input:          input,
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: drift-detector. Error: %v", err)
+			return nil
+		}
+
 		return newJob
 	} else if id == "preview-deployments-ttl-deleter" {
 		newJob, err := jobs.NewPreviewDeploymentsTTLDeleter(dbConn, time.Now().UTC(), &jobs.PreviewDeploymentsTTLDeleterOpts{
@@ -259,6 +293,47 @@ func getJob(ctx context.Context, id string, input map[string]interface{}) worker
 			return nil
 		}
 
+		return newJob
+	} else if id == "registry-gc" {
+		newJob, err := jobs.NewRegistryGC(dbConn, time.Now().UTC(), &jobs.RegistryGCOpts{
+			DBConf:         &envDecoder.DBConf,
+			DOClientID:     envDecoder.DOClientID,
+			DOClientSecret: envDecoder.DOClientSecret,
+			DOScopes:       []string{"read", "write"},
+			ServerURL:      envDecoder.ServerURL,
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: registry-gc. Error: %v", err)
+			return nil
+		}
+
+		return newJob
+	} else if id == "env-group-provider-refresh" {
+		newJob, err := jobs.NewEnvGroupProviderRefresh(dbConn, time.Now().UTC(), &jobs.EnvGroupProviderRefreshOpts{
+			DBConf:                     &envDecoder.DBConf,
+			DOClientID:                 envDecoder.DOClientID,
+			DOClientSecret:             envDecoder.DOClientSecret,
+			DOScopes:                   []string{"read", "write"},
+			ServerURL:                  envDecoder.ServerURL,
+			ClusterControlPlaneAddress: envDecoder.ClusterControlPlaneAddress,
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: env-group-provider-refresh. Error: %v", err)
+			return nil
+		}
+
+		return newJob
+	} else if id == "app-revision-approval-expiry" {
+		newJob, err := jobs.NewAppRevisionApprovalExpiry(dbConn, time.Now().UTC(), &jobs.AppRevisionApprovalExpiryOpts{
+			DBConf:                     &envDecoder.DBConf,
+			ServerURL:                  envDecoder.ServerURL,
+			ClusterControlPlaneAddress: envDecoder.ClusterControlPlaneAddress,
+		})
+		if err != nil {
+			log.Printf("error creating job with ID: app-revision-approval-expiry. Error: %v", err)
+			return nil
+		}
+
 		return newJob
 	}
 